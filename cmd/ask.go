@@ -0,0 +1,136 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/antenore/deecli/internal/api"
+	"github.com/antenore/deecli/internal/files"
+	"github.com/spf13/cobra"
+)
+
+// askFiles are the file patterns loaded as context of deecli ask
+var askFiles []string
+
+// askCmd represents the ask command
+var askCmd = &cobra.Command{
+	Use:     "ask [question]",
+	GroupID: groupChat,
+	Short:   "Ask a single question without starting the chat",
+	Long: `Ask a single question and print the answer to stdout, without the TUI
+and without saving a session.
+
+The question is the arguments joined by spaces. Text piped to stdin is
+added below it as a code block, or is the question when no arguments are
+given. Files loaded with --file are sent as context, as /load does in the
+chat; ignore patterns and large file previews apply.`,
+	Example: `  deecli ask "How do I reverse a slice in Go?"
+  deecli ask "Where is the config merged?" -f 'internal/config/*.go'
+  git diff --staged | deecli ask "Write a commit message for this diff"
+  deecli ask < question.txt`,
+	Annotations: needsAPIKey,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := configManager.Get()
+		if cfg.APIKey == "" {
+			fmt.Fprintf(os.Stderr, "❌ No API key found. Please run 'deecli config init' or set DEEPSEEK_API_KEY environment variable.\n")
+			os.Exit(1)
+		}
+
+		question := strings.TrimSpace(strings.Join(args, " "))
+		piped, err := readPipedStdin()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Failed to read stdin: %v\n", err)
+			os.Exit(1)
+		}
+		switch {
+		case question == "" && piped == "":
+			fmt.Fprintln(os.Stderr, "❌ No question given. Pass it as arguments or on stdin; see 'deecli ask --help'.")
+			os.Exit(2)
+		case question == "":
+			question = piped
+		case piped != "":
+			question += "\n\n```\n" + piped + "\n```"
+		}
+
+		contextPrompt := ""
+		if len(askFiles) > 0 {
+			fileContext := files.NewFileContext()
+			fileContext.Loader = files.NewFileLoaderWithPatterns(true, configManager.GetIgnorePatterns())
+			fileContext.Loader.PreviewThreshold = configManager.GetLargeFileThreshold()
+			fileContext.Loader.PreviewSize = configManager.GetLargeFilePreview()
+			fileContext.Loader.MaxFileSize = configManager.GetMaxFileSize()
+			fileContext.Loader.MaxFiles = configManager.GetMaxFilesPerLoad()
+			fileContext.MaxContext = configManager.GetMaxLoadedFiles()
+			if err := fileContext.LoadFiles(askFiles); err != nil {
+				fmt.Fprintf(os.Stderr, "❌ Failed to load files: %v\n", err)
+				os.Exit(1)
+			}
+			if !quiet {
+				fmt.Fprintf(os.Stderr, "📁 Loaded %d file(s)\n", len(fileContext.Files))
+			}
+			// Leave room for the question and the answer, as the chat does
+			contextPrompt = fileContext.BuildContextPromptWithLimit(configManager.GetMaxContextSize() - len(question) - 10000)
+		}
+
+		service := api.NewDeepSeekService(cfg.APIKey, cfg.Model, configManager.GetTemperature(), cfg.MaxTokens)
+		defer service.Close()
+		applyNetworkSettings(service)
+		applySeed(cmd, service, cfg.Model)
+		service.SetModePrompt(configManager.GetModePrompt())
+		if err := applyRedaction(service, cfg); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Secret redaction failed: %v\n", err)
+			os.Exit(1)
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		answer, err := service.ChatWithHistoryContext(ctx, nil, contextPrompt, question)
+		if err != nil {
+			if ctx.Err() != nil {
+				os.Exit(130)
+			}
+			fmt.Fprintf(os.Stderr, "❌ Request failed: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(strings.TrimSpace(answer))
+	},
+}
+
+// readPipedStdin returns what was piped or redirected to stdin, or "" when
+// stdin is a terminal
+func readPipedStdin() (string, error) {
+	info, err := os.Stdin.Stat()
+	if err != nil || info.Mode()&os.ModeCharDevice != 0 {
+		return "", nil
+	}
+	data, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+func init() {
+	rootCmd.AddCommand(askCmd)
+	addSeedFlag(askCmd)
+	askCmd.Flags().StringArrayVarP(&askFiles, "file", "f", nil, "Load files matching `pattern` as context (repeatable)")
+}
@@ -18,36 +18,59 @@ import (
 	"github.com/spf13/cobra"
 )
 
-var continueSession bool
+var (
+	continueSession bool
+	plainUI         bool
+)
 
 // chatCmd represents the chat command
 var chatCmd = &cobra.Command{
-	Use:   "chat",
-	Short: "Start interactive coding chat session",
+	Use:     "chat",
+	GroupID: groupChat,
+	Short:   "Start interactive coding chat session",
 	Long: `Start an interactive chat session with DeepSeek AI for discussing code,
-getting explanations, and iterative development assistance.
+getting explanations, and iterative development assistance. This is what
+deecli runs when no command is given.
 
 Features:
 - File loading with glob pattern support
 - Command history and completion  
 - Session persistence
-- Professional TUI with Bubbletea`,
-	Run: func(cmd *cobra.Command, args []string) {
-		// Use configuration values
-		chatApp := chat.NewChatApp()
-		if continueSession {
-			if err := chatApp.StartContinueWithConfig(configManager, apiKey, model, temperature, maxTokens); err != nil {
-				cmd.PrintErrf("Chat error: %v\n", err)
-			}
-		} else {
-			if err := chatApp.StartNewWithConfig(configManager, apiKey, model, temperature, maxTokens); err != nil {
-				cmd.PrintErrf("Chat error: %v\n", err)
-			}
+- Professional TUI with Bubbletea
+
+Type /help in the chat for its commands and key bindings.`,
+	Example: `  deecli chat
+  deecli chat --continue
+  deecli chat --plain --model deepseek-reasoner`,
+	Args:        cobra.NoArgs,
+	Annotations: needsAPIKey,
+	Run:         runChat,
+}
+
+// runChat starts the chat TUI, for deecli chat and deecli without a command
+func runChat(cmd *cobra.Command, args []string) {
+	// Use configuration values
+	chatApp := chat.NewChatApp()
+	chatApp.SetPlainMode(plainUI)
+	if continueSession {
+		if err := chatApp.StartContinueWithConfig(configManager, apiKey, model, temperature, maxTokens); err != nil {
+			cmd.PrintErrf("Chat error: %v\n", err)
 		}
-	},
+	} else {
+		if err := chatApp.StartNewWithConfig(configManager, apiKey, model, temperature, maxTokens); err != nil {
+			cmd.PrintErrf("Chat error: %v\n", err)
+		}
+	}
+}
+
+// addChatFlags adds the flags of the chat to cmd
+func addChatFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&continueSession, "continue", false, "Continue previous chat session")
+	cmd.Flags().BoolVar(&plainUI, "plain", false, "Lightweight UI for small tmux panes and slow SSH links")
 }
 
 func init() {
 	rootCmd.AddCommand(chatCmd)
-	chatCmd.Flags().BoolVar(&continueSession, "continue", false, "Continue previous chat session")
-}
\ No newline at end of file
+	addChatFlags(chatCmd)
+	addChatFlags(rootCmd)
+}
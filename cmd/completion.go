@@ -0,0 +1,139 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/antenore/deecli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// completionCmd prints the shell completion script
+var completionCmd = &cobra.Command{
+	Use:     "completion <bash|zsh|fish|powershell>",
+	GroupID: groupIntegrate,
+	Short:   "Generate shell completion scripts",
+	Long: `Generate the completion script of deecli for your shell. It completes
+subcommands, flags, configuration keys, profiles and model names.
+
+Bash (needs the bash-completion package):
+  source <(deecli completion bash)
+  # or, for every session:
+  deecli completion bash > ~/.local/share/bash-completion/completions/deecli
+
+Zsh:
+  deecli completion zsh > "${fpath[1]}/_deecli"
+  # completion must be enabled, e.g. with "autoload -U compinit; compinit"
+
+Fish:
+  deecli completion fish > ~/.config/fish/completions/deecli.fish
+
+PowerShell:
+  deecli completion powershell | Out-String | Invoke-Expression`,
+	ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+	Args:                  cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	DisableFlagsInUseLine: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out := cmd.OutOrStdout()
+		switch args[0] {
+		case "bash":
+			return rootCmd.GenBashCompletionV2(out, true)
+		case "zsh":
+			return rootCmd.GenZshCompletion(out)
+		case "fish":
+			return rootCmd.GenFishCompletion(out, true)
+		default:
+			return rootCmd.GenPowerShellCompletionWithDesc(out)
+		}
+	},
+}
+
+// configSetKeys are the keys "deecli config set" accepts
+var configSetKeys = []string{"api-key", "model", "user-name", "temperature", "max-tokens", "editor"}
+
+// completeModels completes model names, with their context window as description
+func completeModels(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	var models []string
+	for _, name := range config.ValidModels {
+		if strings.HasPrefix(name, toComplete) {
+			models = append(models, fmt.Sprintf("%s\t%dK context", name, config.LookupModel(name).ContextWindow/1000))
+		}
+	}
+	return models, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeProfiles completes the names of the configured profiles
+func completeProfiles(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	manager := configManager
+	if manager == nil {
+		manager = config.NewManager()
+		manager.Load()
+	}
+	var profiles []string
+	for _, name := range manager.ProfileNames() {
+		if strings.HasPrefix(name, toComplete) {
+			profiles = append(profiles, name)
+		}
+	}
+	return profiles, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeConfigSet completes the key of "deecli config set", then model
+// names for the model key
+func completeConfigSet(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	switch {
+	case len(args) == 0:
+		return configSetKeys, cobra.ShellCompDirectiveNoFileComp
+	case len(args) == 1 && args[0] == "model":
+		return completeModels(cmd, nil, toComplete)
+	}
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
+
+// noArgs completes nothing, for commands without arguments
+func noArgs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
+
+// registerFlagCompletions completes the values of the global flags; it
+// runs once root.go has defined them
+func registerFlagCompletions() {
+	rootCmd.RegisterFlagCompletionFunc("model", completeModels)
+	rootCmd.RegisterFlagCompletionFunc("profile", completeProfiles)
+}
+
+func init() {
+	// completionCmd replaces the one Cobra adds
+	rootCmd.CompletionOptions.DisableDefaultCmd = true
+	rootCmd.AddCommand(completionCmd)
+
+	configSetCmd.ValidArgsFunction = completeConfigSet
+	configModelCmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return completeModels(cmd, args, toComplete)
+	}
+	trustCmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return nil, cobra.ShellCompDirectiveFilterDirs
+	}
+	for _, c := range []*cobra.Command{chatCmd, serveCmd, doctorCmd, manCmd, updateCmd, sessionsListCmd, configInitCmd, configShowCmd, configTempCmd, configTokensCmd} {
+		c.ValidArgsFunction = noArgs
+	}
+}
@@ -26,9 +26,16 @@ import (
 )
 
 var configCmd = &cobra.Command{
-	Use:   "config",
-	Short: "Manage DeeCLI configuration",
-	Long:  `Initialize and manage DeeCLI configuration including API keys and model settings.`,
+	Use:     "config",
+	GroupID: groupSetup,
+	Short:   "Manage DeeCLI configuration",
+	Long: `Initialize and manage DeeCLI configuration including API keys and model settings.
+
+Settings are saved to ./.deecli/config.yaml when it exists, otherwise to
+~/.deecli/config.yaml. The chat's /config command edits every setting.`,
+	Example: `  deecli config init
+  deecli config show
+  deecli config set model deepseek-reasoner`,
 }
 
 var configInitCmd = &cobra.Command{
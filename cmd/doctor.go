@@ -0,0 +1,205 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/antenore/deecli/internal/api"
+	"github.com/antenore/deecli/internal/config"
+	"github.com/antenore/deecli/internal/editor"
+	"github.com/antenore/deecli/internal/sessions"
+	"github.com/spf13/cobra"
+)
+
+// doctorTimeout bounds the request checking the API
+const doctorTimeout = 20 * time.Second
+
+// doctorOffline skips the checks that need the network
+var doctorOffline bool
+
+// checkup prints the result of each check and remembers whether one failed
+type checkup struct {
+	failed bool
+}
+
+func (c *checkup) ok(label, detail string) {
+	fmt.Printf("✓ %s: %s\n", label, detail)
+}
+
+func (c *checkup) warn(label, detail string) {
+	fmt.Printf("⚠ %s: %s\n", label, detail)
+}
+
+func (c *checkup) fail(label, detail string) {
+	c.failed = true
+	fmt.Printf("✗ %s: %s\n", label, detail)
+}
+
+// doctorCmd represents the doctor command
+var doctorCmd = &cobra.Command{
+	Use:     "doctor",
+	GroupID: groupSetup,
+	Short:   "Check the configuration, API access and local tools",
+	Long: `Check that DeeCLI is ready to use: the configuration files load and are
+valid, an API key is set and accepted, the model is known, and the editor,
+git, formatters and session database it relies on are available.
+
+Each check prints ✓ when it passes, ⚠ when DeeCLI works with reduced
+features and ✗ when it fails. The exit status is 1 if any check failed.`,
+	Example: `  deecli doctor
+  deecli doctor --offline
+  deecli doctor --profile work`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		var c checkup
+		cfg := checkConfig(&c)
+		checkAPI(&c, cfg)
+		checkTools(&c)
+		checkSessions(&c)
+		if c.failed {
+			os.Exit(1)
+		}
+	},
+}
+
+// checkConfig checks that the configuration loads and is valid, and returns it
+func checkConfig(c *checkup) *config.Config {
+	// initConfig ignores load errors; load again to report them
+	if err := config.NewManager().Load(); err != nil {
+		c.fail("Configuration", err.Error())
+	} else {
+		var files []string
+		if configManager.GlobalConfigExists() {
+			files = append(files, "global")
+		}
+		if configManager.ProjectConfigExists() {
+			files = append(files, "project")
+		}
+		if len(files) == 0 {
+			c.warn("Configuration", "no config file; run 'deecli config init'")
+		} else {
+			c.ok("Configuration", strings.Join(files, " and ")+" config loaded")
+		}
+	}
+
+	cfg := configManager.Get()
+	if err := cfg.Validate(); err != nil {
+		c.fail("Settings", err.Error())
+	}
+	if err := config.ValidateModel(cfg.Model); err != nil {
+		c.warn("Model", err.Error())
+	} else {
+		c.ok("Model", fmt.Sprintf("%s (%dK context)", cfg.Model, config.LookupModel(cfg.Model).ContextWindow/1000))
+	}
+	return cfg
+}
+
+// checkAPI checks the network settings, the API key and, unless offline,
+// that the API accepts the key
+func checkAPI(c *checkup, cfg *config.Config) {
+	if cfg.APIKey == "" {
+		c.fail("API key", "not set; run 'deecli config init' or set DEEPSEEK_API_KEY")
+		return
+	}
+	source := "config"
+	if os.Getenv("DEEPSEEK_API_KEY") == cfg.APIKey {
+		source = "DEEPSEEK_API_KEY"
+	}
+	c.ok("API key", "set from "+source)
+
+	service := api.NewDeepSeekService(cfg.APIKey, cfg.Model, configManager.GetTemperature(), 1)
+	defer service.Close()
+	if err := service.SetNetwork(api.NetworkOptionsFromConfig(configManager)); err != nil {
+		c.fail("Network settings", err.Error())
+		return
+	}
+	if configManager.GetInsecureSkipVerify() {
+		c.warn("Network settings", "TLS certificate verification is disabled")
+	} else if bundle := configManager.GetCABundle(); bundle != "" {
+		c.ok("Network settings", "trusting the CAs in "+bundle)
+	}
+
+	if doctorOffline {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), doctorTimeout)
+	defer cancel()
+	result, err := service.Verify(ctx)
+	if err != nil {
+		c.fail("API", err.Error())
+		return
+	}
+	c.ok("API", fmt.Sprintf("key accepted · model %s · %dms", result.Model, result.Latency.Milliseconds()))
+}
+
+// checkTools checks the editor, git and the formatters /apply runs
+func checkTools(c *checkup) {
+	editorCmd, gotoCmd := configManager.GetEditorCommands()
+	switch {
+	case editorCmd != "" || gotoCmd != "":
+		c.ok("Editor", "configured command templates")
+	case editor.Find() != "":
+		c.ok("Editor", editor.Find())
+	default:
+		c.warn("Editor", "none found; set $EDITOR to use /edit")
+	}
+
+	if path, err := exec.LookPath("git"); err != nil {
+		c.warn("git", "not on PATH; /git and /review are unavailable")
+	} else {
+		c.ok("git", path)
+	}
+
+	formatters := configManager.GetFormatters()
+	languages := make([]string, 0, len(formatters))
+	for language := range formatters {
+		languages = append(languages, language)
+	}
+	slices.Sort(languages)
+	for _, language := range languages {
+		program := formatters[language][0]
+		if _, err := exec.LookPath(program); err != nil {
+			c.warn("Formatter "+language, program+" not on PATH; applied "+language+" files are left unformatted")
+		} else {
+			c.ok("Formatter "+language, program)
+		}
+	}
+}
+
+// checkSessions checks that the session database opens and can be read
+func checkSessions(c *checkup) {
+	manager, err := sessions.NewManager()
+	if err != nil {
+		c.fail("Sessions", err.Error())
+		return
+	}
+	defer manager.Close()
+	if _, err := manager.ListSessions(1); err != nil {
+		c.fail("Sessions", err.Error())
+		return
+	}
+	c.ok("Sessions", "database readable")
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+	doctorCmd.Flags().BoolVar(&doctorOffline, "offline", false, "Skip the checks that need the network")
+}
@@ -0,0 +1,121 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/antenore/deecli/internal/api"
+	"github.com/antenore/deecli/internal/eval"
+	"github.com/spf13/cobra"
+)
+
+var evalOutput string
+
+// evalCmd represents the eval command
+var evalCmd = &cobra.Command{
+	Use:     "eval <suite.yaml>",
+	GroupID: groupIntegrate,
+	Short:   "Compare two system prompt or model variants on a prompt suite",
+	Long: `Run every prompt of a YAML suite against two variants and print a
+Markdown report comparing their answers, latency and token usage.
+
+Example suite:
+
+  name: review-prompt
+  variants:
+    - name: current            # no system prompt: the shipped chat prompt
+    - name: terse
+      system_file: terse.txt   # relative to the suite
+      model: deepseek-reasoner
+      temperature: 0.2
+  cases:
+    - name: nil map
+      prompt: "Why does this panic? var m map[string]int; m[\"a\"] = 1"
+      expect: ["nil map", "make"]
+
+Each expected phrase found in an answer (case-insensitively) scores a
+point; a variant wins a case by scoring more than the other.`,
+	Example: `  deecli eval prompts/review.yaml
+  deecli eval prompts/review.yaml --seed 7 -o report.md`,
+	Args:        cobra.ExactArgs(1),
+	Annotations: needsAPIKey,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := configManager.Get()
+		if cfg.APIKey == "" {
+			fmt.Fprintf(os.Stderr, "❌ No API key found. Please run 'deecli config init' or set DEEPSEEK_API_KEY environment variable.\n")
+			os.Exit(1)
+		}
+
+		suite, err := eval.Load(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+
+		var completers [2]eval.Completer
+		for i, variant := range suite.Variants {
+			model, temperature := cfg.Model, configManager.GetTemperature()
+			if variant.Model != "" {
+				model = variant.Model
+			}
+			if variant.Temperature != nil {
+				temperature = *variant.Temperature
+			}
+			service := api.NewDeepSeekService(cfg.APIKey, model, temperature, cfg.MaxTokens)
+			defer service.Close()
+			applyNetworkSettings(service)
+			applySeed(cmd, service, model)
+			if err := applyRedaction(service, cfg); err != nil {
+				fmt.Fprintf(os.Stderr, "❌ Secret redaction failed: %v\n", err)
+				os.Exit(1)
+			}
+			completers[i] = service
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		// Progress goes to stderr so the report can be redirected
+		report := eval.Run(ctx, suite, completers, func(c eval.Case, variant eval.Variant) {
+			if !quiet {
+				fmt.Fprintf(os.Stderr, "🧪 %s: %s...\n", c.Name, variant.Name)
+			}
+		})
+
+		markdown := report.Markdown()
+		if evalOutput == "" {
+			fmt.Print(markdown)
+		} else {
+			if err := os.WriteFile(evalOutput, []byte(markdown), 0644); err != nil {
+				fmt.Fprintf(os.Stderr, "❌ Failed to write report: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Fprintf(os.Stderr, "📊 Report written to %s\n", evalOutput)
+		}
+		if ctx.Err() != nil {
+			os.Exit(130)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(evalCmd)
+	addSeedFlag(evalCmd)
+	evalCmd.Flags().StringVarP(&evalOutput, "output", "o", "", "Write the report to this file instead of stdout")
+}
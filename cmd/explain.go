@@ -24,11 +24,14 @@ import (
 
 // explainCmd represents the explain command
 var explainCmd = &cobra.Command{
-	Use:   "explain <file>",
-	Short: "Get code explanation",
+	Use:     "explain <file>",
+	GroupID: groupCode,
+	Short:   "Get code explanation",
 	Long: `Analyze a code file and get a detailed explanation of what it does,
 how it works, and its key components.`,
-	Args: cobra.ExactArgs(1),
+	Example:     `  deecli explain internal/files/format.go`,
+	Args:        cobra.ExactArgs(1),
+	Annotations: needsAPIKey,
 	Run: func(cmd *cobra.Command, args []string) {
 		filepath := args[0]
 		
@@ -43,6 +46,7 @@ how it works, and its key components.`,
 		
 		// Load the file
 		loader := files.NewFileLoader()
+		loader.MaxFileSize = configManager.GetMaxFileSize()
 		fileInfo, err := loader.LoadFile(filepath)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "❌ Failed to load file: %v\n", err)
@@ -50,10 +54,18 @@ how it works, and its key components.`,
 		}
 		
 		// Create API service
-		service := api.NewDeepSeekService(cfg.APIKey, cfg.Model, cfg.Temperature, cfg.MaxTokens)
+		service := api.NewDeepSeekService(cfg.APIKey, cfg.Model, configManager.GetTemperature(), cfg.MaxTokens)
+		applyNetworkSettings(service)
+		applySeed(cmd, service, cfg.Model)
+		
+		// Mask secrets before sending file content
+		if err := applyRedaction(service, cfg); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Secret redaction failed: %v\n", err)
+			os.Exit(1)
+		}
 		
 		// Get code explanation
-		explanation, err := service.ExplainCode(fileInfo.Content, fileInfo.RelPath)
+		explanation, err := service.ExplainCode(cmd.Context(), fileInfo.Content, fileInfo.RelPath)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "❌ Analysis failed: %v\n", err)
 			os.Exit(1)
@@ -65,4 +77,5 @@ how it works, and its key components.`,
 
 func init() {
 	rootCmd.AddCommand(explainCmd)
+	addSeedFlag(explainCmd)
 }
\ No newline at end of file
@@ -24,11 +24,14 @@ import (
 
 // improveCmd represents the improve command
 var improveCmd = &cobra.Command{
-	Use:   "improve <file>",
-	Short: "Get code improvement suggestions",
+	Use:     "improve <file>",
+	GroupID: groupCode,
+	Short:   "Get code improvement suggestions",
 	Long: `Analyze a code file and get AI-powered suggestions for improvements,
 refactoring opportunities, and best practices.`,
-	Args: cobra.ExactArgs(1),
+	Example:     `  deecli improve main.go --model deepseek-reasoner`,
+	Args:        cobra.ExactArgs(1),
+	Annotations: needsAPIKey,
 	Run: func(cmd *cobra.Command, args []string) {
 		filepath := args[0]
 		
@@ -43,6 +46,7 @@ refactoring opportunities, and best practices.`,
 		
 		// Load the file
 		loader := files.NewFileLoader()
+		loader.MaxFileSize = configManager.GetMaxFileSize()
 		fileInfo, err := loader.LoadFile(filepath)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "❌ Failed to load file: %v\n", err)
@@ -50,10 +54,18 @@ refactoring opportunities, and best practices.`,
 		}
 		
 		// Create API service
-		service := api.NewDeepSeekService(cfg.APIKey, cfg.Model, cfg.Temperature, cfg.MaxTokens)
+		service := api.NewDeepSeekService(cfg.APIKey, cfg.Model, configManager.GetTemperature(), cfg.MaxTokens)
+		applyNetworkSettings(service)
+		applySeed(cmd, service, cfg.Model)
+		
+		// Mask secrets before sending file content
+		if err := applyRedaction(service, cfg); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Secret redaction failed: %v\n", err)
+			os.Exit(1)
+		}
 		
 		// Get improvement suggestions
-		suggestions, err := service.ImproveCode(fileInfo.Content, fileInfo.RelPath)
+		suggestions, err := service.ImproveCode(cmd.Context(), fileInfo.Content, fileInfo.RelPath)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "❌ Analysis failed: %v\n", err)
 			os.Exit(1)
@@ -65,4 +77,5 @@ refactoring opportunities, and best practices.`,
 
 func init() {
 	rootCmd.AddCommand(improveCmd)
+	addSeedFlag(improveCmd)
 }
\ No newline at end of file
@@ -0,0 +1,171 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// manCmd prints the manual page
+var manCmd = &cobra.Command{
+	Use:     "man",
+	GroupID: groupSetup,
+	Short:   "Print the deecli(1) manual page",
+	Long: `Print the manual page of deecli in roff format, generated from the same
+help text as --help.`,
+	Example: `  deecli man | man -l -
+  deecli man > ~/.local/share/man/man1/deecli.1`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		writeManPage(cmd.OutOrStdout(), rootCmd, time.Now())
+	},
+}
+
+// roffEscaper escapes the characters roff treats specially inside a line
+var roffEscaper = strings.NewReplacer(`\`, `\e`, "-", `\-`)
+
+// roff escapes text for a roff body, keeping lines from starting with a
+// control character
+func roff(text string) string {
+	lines := strings.Split(roffEscaper.Replace(text), "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, ".") || strings.HasPrefix(line, "'") {
+			lines[i] = `\&` + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// writeManPage writes the manual page of root and its subcommands
+func writeManPage(w io.Writer, root *cobra.Command, date time.Time) {
+	fmt.Fprintf(w, ".TH DEECLI 1 %q \"deecli\" \"User Commands\"\n", date.Format("2006-01-02"))
+	summary := strings.TrimLeftFunc(root.Short, func(r rune) bool { return !unicode.IsLetter(r) })
+	fmt.Fprintf(w, ".SH NAME\ndeecli \\- %s\n", roff(summary))
+	fmt.Fprintf(w, ".SH SYNOPSIS\n.B deecli\n[\\fIflags\\fR]\n.br\n.B deecli\n\\fIcommand\\fR [\\fIflags\\fR] [\\fIarguments\\fR]\n")
+	fmt.Fprintf(w, ".SH DESCRIPTION\n%s\n", roffParagraphs(root.Long))
+
+	fmt.Fprintln(w, ".SH COMMANDS")
+	for _, cmd := range manCommands(root) {
+		fmt.Fprintf(w, ".SS \"%s\"\n", roff(cmd.UseLine()))
+		fmt.Fprintln(w, roffParagraphs(firstNonEmpty(cmd.Long, cmd.Short)))
+		if len(cmd.Aliases) > 0 {
+			fmt.Fprintf(w, ".PP\nAliases: %s\n", roff(strings.Join(cmd.Aliases, ", ")))
+		}
+		writeManFlags(w, cmd.LocalNonPersistentFlags())
+		if cmd.Example != "" {
+			fmt.Fprintf(w, ".PP\nExamples:\n.PP\n.RS\n.nf\n%s\n.fi\n.RE\n", roff(cmd.Example))
+		}
+	}
+
+	fmt.Fprintln(w, ".SH OPTIONS")
+	writeManFlags(w, root.PersistentFlags())
+	writeManFlags(w, root.LocalNonPersistentFlags())
+
+	fmt.Fprint(w, `.SH ENVIRONMENT
+.TP
+.B DEEPSEEK_API_KEY
+API key, used when the configuration sets none.
+.TP
+.BR EDITOR ", " VISUAL
+Editor opened by /edit, unless editor_cmd is configured.
+.TP
+.BR HTTPS_PROXY ", " NO_PROXY
+Proxy for API requests, unless the proxy setting is configured.
+.SH FILES
+.TP
+.I ~/.deecli/config.yaml
+Global configuration.
+.TP
+.I .deecli/config.yaml
+Project configuration; it overrides the global one.
+.TP
+.I ~/.deecli/session.db
+Saved chat sessions.
+.SH EXIT STATUS
+0 on success, 1 when a command fails, 2 on invalid usage.
+.SH SEE ALSO
+deecli doctor, deecli completion, and /help inside the chat.
+`)
+}
+
+// manCommands returns the available subcommands of root, depth first
+func manCommands(root *cobra.Command) []*cobra.Command {
+	var commands []*cobra.Command
+	for _, cmd := range root.Commands() {
+		if !cmd.IsAvailableCommand() || cmd.IsAdditionalHelpTopicCommand() {
+			continue
+		}
+		if cmd.Runnable() {
+			commands = append(commands, cmd)
+		}
+		commands = append(commands, manCommands(cmd)...)
+	}
+	return commands
+}
+
+// writeManFlags writes the visible flags of a set as a tagged list
+func writeManFlags(w io.Writer, flags *pflag.FlagSet) {
+	flags.VisitAll(func(flag *pflag.Flag) {
+		if flag.Hidden {
+			return
+		}
+		name := `\fB\-\-` + roff(flag.Name) + `\fR`
+		if flag.Shorthand != "" {
+			name = `\fB\-` + flag.Shorthand + `\fR, ` + name
+		}
+		varName, usage := pflag.UnquoteUsage(flag)
+		if varName != "" {
+			name += ` \fI` + roff(varName) + `\fR`
+		}
+		if flag.DefValue != "" && flag.DefValue != "false" && flag.DefValue != "0" && flag.DefValue != "[]" {
+			usage += fmt.Sprintf(" (default %s)", flag.DefValue)
+		}
+		fmt.Fprintf(w, ".TP\n%s\n%s\n", name, roff(usage))
+	})
+}
+
+// roffParagraphs turns blank-line separated text into roff paragraphs;
+// indented lines are kept as they are
+func roffParagraphs(text string) string {
+	var out []string
+	for _, para := range strings.Split(strings.TrimSpace(text), "\n\n") {
+		if strings.HasPrefix(para, "  ") || strings.Contains(para, "\n  ") {
+			out = append(out, ".PP\n.nf\n"+roff(para)+"\n.fi")
+		} else {
+			out = append(out, ".PP\n"+roff(para))
+		}
+	}
+	return strings.Join(out, "\n")
+}
+
+// firstNonEmpty returns the first of its arguments that is not ""
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func init() {
+	rootCmd.AddCommand(manCmd)
+}
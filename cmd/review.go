@@ -0,0 +1,93 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/antenore/deecli/internal/api"
+	"github.com/antenore/deecli/internal/files"
+)
+
+// reviewCmd represents the review command
+var reviewCmd = &cobra.Command{
+	Use:     "review <file>...",
+	Aliases: []string{"analyze"},
+	GroupID: groupCode,
+	Short:   "Review code files and get suggestions",
+	Long: `Review one or more code files and get AI-powered suggestions for
+improvements, potential issues, and best practices. Each file is reviewed
+on its own; secrets are masked first when redact_secrets is on.
+
+"analyze" is another name for this command.`,
+	Example: `  deecli review main.go
+  deecli review internal/server/*.go --seed 42`,
+	Args:        cobra.MinimumNArgs(1),
+	Annotations: needsAPIKey,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Check if API key is available
+		cfg := configManager.Get()
+		if cfg.APIKey == "" {
+			fmt.Fprintf(os.Stderr, "❌ No API key found. Please run 'deecli config init' or set DEEPSEEK_API_KEY environment variable.\n")
+			os.Exit(1)
+		}
+		
+		// Create API service
+		service := api.NewDeepSeekService(cfg.APIKey, cfg.Model, configManager.GetTemperature(), cfg.MaxTokens)
+		applyNetworkSettings(service)
+		applySeed(cmd, service, cfg.Model)
+		
+		// Mask secrets before sending file content
+		if err := applyRedaction(service, cfg); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Secret redaction failed: %v\n", err)
+			os.Exit(1)
+		}
+		
+		loader := files.NewFileLoader()
+		
+		loader.MaxFileSize = configManager.GetMaxFileSize()
+		failed := false
+		for _, filepath := range args {
+			fmt.Printf("🔍 Analyzing %s...\n", filepath)
+			
+			// Load the file
+			fileInfo, err := loader.LoadFile(filepath)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "❌ Failed to load file: %v\n", err)
+				failed = true
+				continue
+			}
+			
+			// Analyze the code
+			analysis, err := service.AnalyzeCode(cmd.Context(), fileInfo.Content, fileInfo.RelPath)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "❌ Analysis failed: %v\n", err)
+				failed = true
+				continue
+			}
+			
+			fmt.Printf("\n📊 Analysis of %s:\n\n%s\n", fileInfo.RelPath, analysis)
+		}
+		if failed {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(reviewCmd)
+	addSeedFlag(reviewCmd)
+}
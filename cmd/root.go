@@ -18,7 +18,11 @@ import (
 	"os"
 
 	"github.com/spf13/cobra"
+	"github.com/antenore/deecli/internal/api"
 	"github.com/antenore/deecli/internal/config"
+	"github.com/antenore/deecli/internal/redact"
+	"github.com/antenore/deecli/internal/metrics"
+	"github.com/antenore/deecli/internal/update"
 )
 
 var (
@@ -29,20 +33,66 @@ var (
 	maxTokens   int
 	verbose     bool
 	quiet       bool
+	profile     string
+
+	// Sampling seed of one-shot commands, see addSeedFlag
+	seed int
 
 	// Config manager
 	configManager *config.Manager
 )
 
+// Command groups of the help output
+const (
+	groupChat      = "chat"
+	groupCode      = "code"
+	groupSetup     = "setup"
+	groupIntegrate = "integrate"
+)
+
+// apiKeyAnnotation marks commands that talk to the API, so they stop early
+// with a hint when no API key is configured
+const apiKeyAnnotation = "deecli/needs-api-key"
+
+// needsAPIKey is the annotation set of commands that talk to the API
+var needsAPIKey = map[string]string{apiKeyAnnotation: "true"}
+
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use:   "deecli",
 	Short: "🐉 AI-powered code assistant using DeepSeek models",
-	Long: `DeeCLI is a professional AI-powered code assistant using DeepSeek models,
-focusing on excellent terminal UX, session persistence, and extensibility.
+	Long: `DeeCLI is an AI code assistant for the terminal using DeepSeek models.
+
+Run without a command, deecli starts the interactive chat, the same as
+"deecli chat". The other commands work without the TUI and write plain
+text to stdout, so they can be used from scripts, pipes and editors.
 
-Built with Go, Cobra, and Bubbletea for maximum performance and reliability.`,
-	Version: "0.1.0",
+Settings come from ~/.deecli/config.yaml, ./.deecli/config.yaml, the
+active profile and the DEEPSEEK_API_KEY environment variable, in
+increasing order of priority; the global flags override them for one run.
+"deecli doctor" checks the setup and "deecli man" prints a manual page.`,
+	Example: `  deecli                              Start the chat
+  deecli --continue                   Continue the last chat session
+  deecli ask "What does this do?" -f main.go
+  git diff | deecli ask "Write a commit message for this diff"
+  deecli review internal/server/*.go
+  deecli --profile work sessions list`,
+	Version:     update.Version,
+	Annotations: needsAPIKey,
+	Args:        cobra.NoArgs,
+	Run:         runChat,
+	// Commands that talk to the API need a key, unless a global config
+	// exists, in which case the chat can ask for it
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		if !isCompletionCommand() {
+			usageStats().Command(cmd.CommandPath())
+		}
+		if cmd.Annotations[apiKeyAnnotation] != "" && apiKey == "" && !configManager.GlobalConfigExists() {
+			fmt.Fprintln(os.Stderr, "❌ No API key found. Please run 'deecli config init' or set DEEPSEEK_API_KEY environment variable.")
+			os.Exit(1)
+		}
+		askTrust(cmd)
+	},
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -61,12 +111,32 @@ func init() {
 	rootCmd.PersistentFlags().IntVar(&maxTokens, "max-tokens", 0, "Maximum tokens to generate (overrides config)")
 	rootCmd.PersistentFlags().BoolVar(&verbose, "verbose", false, "Enable verbose output")
 	rootCmd.PersistentFlags().BoolVar(&quiet, "quiet", false, "Quiet mode")
+	rootCmd.PersistentFlags().StringVar(&profile, "profile", "", "Configuration profile to use (overrides active_profile)")
+
+	rootCmd.AddGroup(
+		&cobra.Group{ID: groupChat, Title: "Chat:"},
+		&cobra.Group{ID: groupCode, Title: "Code:"},
+		&cobra.Group{ID: groupSetup, Title: "Setup and sessions:"},
+		&cobra.Group{ID: groupIntegrate, Title: "Integrations:"},
+	)
+	rootCmd.SetHelpCommandGroupID(groupSetup)
 
-	// Hide help command since we have custom help
+	// Cobra always lists the help command, so give it a place among the
+	// setup commands
 	rootCmd.SetHelpCommand(&cobra.Command{
-		Use:    "no-help",
-		Hidden: true,
+		Use:   "help [command]",
+		Short: "Help about any command",
+		Run: func(cmd *cobra.Command, args []string) {
+			target, _, err := rootCmd.Find(args)
+			if err != nil {
+				target = rootCmd
+			}
+			target.Help()
+		},
 	})
+
+	// Flags are defined now, so their completions can be registered
+	registerFlagCompletions()
 }
 
 func initConfig() {
@@ -79,7 +149,20 @@ func initConfig() {
 		}
 	}
 
-	// Apply command-line overrides
+	// A profile given on the command line replaces the active one
+	if profile != "" {
+		if err := configManager.UseProfile(profile); err != nil && !isCompletionCommand() {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	applyFlags()
+}
+
+// applyFlags applies the command-line overrides to the configuration and
+// fills the flags that were not given from it
+func applyFlags() {
 	cfg := configManager.Get()
 	
 	// Command-line flags take precedence
@@ -91,6 +174,7 @@ func initConfig() {
 	}
 	if temperature != 0 {
 		cfg.Temperature = temperature
+		cfg.Mode = "" // An explicit temperature replaces the /mode preset
 	}
 	if maxTokens != 0 {
 		cfg.MaxTokens = maxTokens
@@ -104,21 +188,104 @@ func initConfig() {
 		model = cfg.Model
 	}
 	if temperature == 0 {
-		temperature = cfg.Temperature
+		temperature = configManager.GetTemperature()
 	}
 	if maxTokens == 0 {
 		maxTokens = cfg.MaxTokens
 	}
+}
 
-	// Check if API key is set when needed
-	if !isConfigCommand() && apiKey == "" && !configManager.GlobalConfigExists() {
-		fmt.Fprintln(os.Stderr, "❌ No API key found. Please run 'deecli config init' or set DEEPSEEK_API_KEY environment variable.")
-		os.Exit(1)
+// reapplyFlags applies the command-line overrides again once the
+// configuration was reloaded, refilling the flags cmd was not given
+func reapplyFlags(cmd *cobra.Command) {
+	flags := cmd.Flags()
+	if !flags.Changed("api-key") {
+		apiKey = ""
+	}
+	if !flags.Changed("model") {
+		model = ""
+	}
+	if !flags.Changed("temperature") {
+		temperature = 0
 	}
+	if !flags.Changed("max-tokens") {
+		maxTokens = 0
+	}
+	applyFlags()
 }
 
-func isConfigCommand() bool {
-	// Check if the command is a config command (we'll implement this command next)
+// isCompletionCommand reports whether deecli runs to print a completion
+// script or to answer a completion request from the shell, which must work
+// before an API key is configured
+func isCompletionCommand() bool {
 	args := os.Args[1:]
-	return len(args) > 0 && args[0] == "config"
-}
\ No newline at end of file
+	if len(args) == 0 {
+		return false
+	}
+	switch args[0] {
+	case "completion", cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd:
+		return true
+	}
+	return false
+}
+
+// applyRedaction masks secrets in what service sends when redact_secrets is
+// on. A pattern that does not compile is returned as an error, so that the
+// command stops instead of sending content unmasked.
+func applyRedaction(service *api.Service, cfg *config.Config) error {
+	if !cfg.RedactSecrets {
+		return nil
+	}
+	redactor, err := redact.New(cfg.RedactPatterns)
+	if err != nil {
+		return err
+	}
+	service.SetRedactor(redactor)
+	return nil
+}
+
+// applyNetworkSettings applies the configured proxy and TLS options to
+// service, exiting on invalid settings and warning loudly when certificate
+// verification is disabled. It also enables the request metrics log and the
+// local usage statistics.
+func applyNetworkSettings(service *api.Service) {
+	if err := service.SetNetwork(api.NetworkOptionsFromConfig(configManager)); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Invalid proxy/TLS settings: %v\n", err)
+		os.Exit(1)
+	}
+	if configManager.GetInsecureSkipVerify() {
+		fmt.Fprintln(os.Stderr, "⚠️  WARNING: TLS certificate verification is disabled (insecure_skip_verify).")
+		fmt.Fprintln(os.Stderr, "   Anyone on the network path can read and alter API traffic, including your API key.")
+	}
+	if configManager.GetRequestMetrics() {
+		service.SetMetricsLog(api.NewMetricsLog(api.DefaultMetricsPath))
+	}
+	service.SetUsageStats(usageStats())
+}
+
+// usageStats returns the recorder of local usage statistics, or nil when
+// telemetry is off
+func usageStats() *metrics.Recorder {
+	if configManager.GetTelemetry() != "local" {
+		return nil
+	}
+	return metrics.NewRecorder(metrics.DefaultPath())
+}
+
+// addSeedFlag adds --seed to a one-shot command
+func addSeedFlag(cmd *cobra.Command) {
+	cmd.Flags().IntVar(&seed, "seed", 0, "Sampling seed for reproducible output, if the model supports it")
+}
+
+// applySeed sets the --seed value, when given, on service, warning if model
+// does not accept a seed
+func applySeed(cmd *cobra.Command, service *api.Service, model string) {
+	if !cmd.Flags().Changed("seed") {
+		return
+	}
+	if !config.LookupModel(model).SupportsSeed && !quiet {
+		fmt.Fprintf(os.Stderr, "⚠️  %s does not support --seed; output will not be reproducible\n", model)
+	}
+	s := seed
+	service.SetSeed(&s)
+}
@@ -0,0 +1,135 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/antenore/deecli/internal/api"
+	"github.com/antenore/deecli/internal/server"
+	"github.com/antenore/deecli/internal/sessions"
+	"github.com/spf13/cobra"
+)
+
+var (
+	serveAddr  string
+	serveToken string
+	serveStdio bool
+)
+
+// serveCmd represents the serve command
+var serveCmd = &cobra.Command{
+	Use:     "serve",
+	GroupID: groupIntegrate,
+	Short:   "Run a local HTTP API for editor plugins and other tools",
+	Long: `Run deecli headless, exposing its file context and chat over a local HTTP API.
+
+Endpoints:
+  GET    /v1/health    Health check
+  GET    /v1/files     List loaded files
+  POST   /v1/files     Load files: {"patterns": ["*.go"]}
+  DELETE /v1/files     Clear loaded files
+  POST   /v1/chat      Chat: {"message": "...", "stream": true}
+                       Streaming responses use server-sent events
+  DELETE /v1/history   Forget the conversation
+
+The server binds to localhost and only answers requests addressed to
+localhost or 127.0.0.1. Every request needs an "Authorization: Bearer <token>"
+header; the token is printed at startup unless --token sets it. Request
+bodies must be sent as application/json.
+
+With --stdio, deecli instead speaks newline-delimited JSON-RPC 2.0 on
+stdin/stdout for editor plugins. Methods: initialize, loadFiles, listFiles,
+clearFiles, sendMessage, streamEvents, applyPatch, resetConversation.`,
+	Example: `  deecli serve
+  deecli serve --addr 127.0.0.1:9000 --token "$(openssl rand -hex 16)"
+  deecli serve --stdio`,
+	Args:        cobra.NoArgs,
+	Annotations: needsAPIKey,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := configManager.Get()
+		if cfg.APIKey == "" {
+			fmt.Fprintf(os.Stderr, "❌ No API key found. Please run 'deecli config init' or set DEEPSEEK_API_KEY environment variable.\n")
+			os.Exit(1)
+		}
+
+		service := api.NewDeepSeekService(cfg.APIKey, cfg.Model, configManager.GetTemperature(), cfg.MaxTokens)
+		applyNetworkSettings(service)
+		service.SetModePrompt(configManager.GetModePrompt())
+		if err := applyRedaction(service, cfg); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Secret redaction failed: %v\n", err)
+			os.Exit(1)
+		}
+
+		opts := server.Options{Token: serveToken}
+		if sessionManager, err := sessions.NewManager(); err == nil {
+			defer sessionManager.Close()
+			opts.Sessions = sessionManager
+		} else if verbose {
+			fmt.Fprintf(os.Stderr, "Warning: Sessions unavailable: %v\n", err)
+		}
+		apiServer := server.New(service, configManager, opts)
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		if serveStdio {
+			// stdout carries the protocol, so diagnostics go to stderr
+			if err := apiServer.ServeRPC(ctx, os.Stdin, os.Stdout); err != nil && !errors.Is(err, context.Canceled) {
+				fmt.Fprintf(os.Stderr, "❌ RPC error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+
+		srv := &http.Server{
+			Addr:              serveAddr,
+			Handler:           apiServer.Handler(),
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+
+		go func() {
+			<-ctx.Done()
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			srv.Shutdown(shutdownCtx)
+		}()
+
+		if !quiet {
+			fmt.Printf("🐉 deecli API listening on http://%s (Ctrl+C to stop)\n", serveAddr)
+		}
+		if serveToken == "" {
+			// Clients cannot connect without the generated token, so it is shown even with --quiet
+			fmt.Printf("🔑 Token: %s\n", apiServer.Token())
+		}
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fmt.Fprintf(os.Stderr, "❌ Server error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+	serveCmd.Flags().StringVar(&serveAddr, "addr", server.DefaultAddr, "Address to listen on")
+	serveCmd.Flags().StringVar(&serveToken, "token", "", "Bearer token required on every request (default: a random one, printed at startup)")
+	serveCmd.Flags().BoolVar(&serveStdio, "stdio", false, "Speak JSON-RPC over stdin/stdout instead of HTTP")
+}
@@ -0,0 +1,181 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/antenore/deecli/internal/api"
+	"github.com/antenore/deecli/internal/redact"
+	"github.com/antenore/deecli/internal/sessions"
+	"github.com/antenore/deecli/internal/share"
+	"github.com/spf13/cobra"
+)
+
+var (
+	sessionsLimit       int  // How many sessions "deecli sessions list" shows
+	sessionsAnnotations bool // Whether "deecli sessions show" notes how each response was generated
+)
+
+// sessionsCmd represents the sessions command
+var sessionsCmd = &cobra.Command{
+	Use:     "sessions",
+	GroupID: groupSetup,
+	Short:   "List, show and delete saved chat sessions",
+	Long: `Manage the chat sessions saved in ~/.deecli/session.db.
+
+The chat resumes the most recently updated session; /session and /fork
+manage sessions from inside it.`,
+	Example: `  deecli sessions list
+  deecli sessions show 12 > conversation.md
+  deecli sessions delete 12`,
+}
+
+var sessionsListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List the most recently updated sessions",
+	Args:    cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		manager := openSessions()
+		defer manager.Close()
+
+		sessionList, err := manager.ListSessions(sessionsLimit)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Failed to list sessions: %v\n", err)
+			os.Exit(1)
+		}
+		if len(sessionList) == 0 {
+			fmt.Println("No sessions yet")
+			return
+		}
+		for _, session := range sessionList {
+			title := session.Title
+			if title == "" {
+				title = "Untitled"
+			}
+			fmt.Printf("#%d  %s  (%d messages, %s)\n", session.ID, title, session.MessageCount,
+				session.UpdatedAt.Local().Format("2006-01-02 15:04"))
+		}
+	},
+}
+
+var sessionsShowCmd = &cobra.Command{
+	Use:   "show <id>",
+	Short: "Print a session as Markdown, with secrets redacted",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		id := sessionID(args[0])
+		manager := openSessions()
+		defer manager.Close()
+
+		session, err := manager.GetSession(id)
+		if err != nil {
+			exitSessionError(id, err)
+		}
+		stored, err := manager.GetSessionMessages(id)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Failed to read session #%d: %v\n", id, err)
+			os.Exit(1)
+		}
+
+		messages := make([]api.Message, 0, len(stored))
+		for _, msg := range stored {
+			message := api.Message{Role: msg.Role, Content: msg.Content, Time: msg.Timestamp}
+			if msg.Generation.Model != "" {
+				message.Generation = &msg.Generation
+			}
+			messages = append(messages, message)
+		}
+
+		redactor, err := redact.New(configManager.GetRedactPatterns())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Invalid redact pattern: %v\n", err)
+			os.Exit(1)
+		}
+		opts := share.Options{
+			Title:      session.Title,
+			Redactor:   redactor,
+			Time:       time.Now(),
+			Timestamps: configManager.GetMessageTimestamps() != "off",
+
+			Annotations: sessionsAnnotations,
+		}
+		opts.BaseDir, _ = os.Getwd()
+		opts.HomeDir, _ = os.UserHomeDir()
+		fmt.Print(share.Markdown(messages, opts))
+	},
+}
+
+var sessionsDeleteCmd = &cobra.Command{
+	Use:     "delete <id>",
+	Aliases: []string{"rm"},
+	Short:   "Delete a session and its messages",
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		id := sessionID(args[0])
+		manager := openSessions()
+		defer manager.Close()
+
+		if err := manager.DeleteSession(id); err != nil {
+			exitSessionError(id, err)
+		}
+		if !quiet {
+			fmt.Printf("✅ Deleted session #%d\n", id)
+		}
+	},
+}
+
+// openSessions opens the session database or exits
+func openSessions() *sessions.Manager {
+	manager, err := sessions.NewManager()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Failed to open the session database: %v\n", err)
+		os.Exit(1)
+	}
+	return manager
+}
+
+// sessionID parses a session ID as listed, with or without its "#"
+func sessionID(arg string) int64 {
+	id, err := strconv.ParseInt(strings.TrimPrefix(arg, "#"), 10, 64)
+	if err != nil || id <= 0 {
+		fmt.Fprintf(os.Stderr, "❌ Invalid session ID: %s\n", arg)
+		os.Exit(2)
+	}
+	return id
+}
+
+// exitSessionError reports a failed lookup of session id and exits
+func exitSessionError(id int64, err error) {
+	if errors.Is(err, sql.ErrNoRows) {
+		fmt.Fprintf(os.Stderr, "❌ No session #%d; see 'deecli sessions list'\n", id)
+	} else {
+		fmt.Fprintf(os.Stderr, "❌ Failed to read session #%d: %v\n", id, err)
+	}
+	os.Exit(1)
+}
+
+func init() {
+	rootCmd.AddCommand(sessionsCmd)
+	sessionsCmd.AddCommand(sessionsListCmd, sessionsShowCmd, sessionsDeleteCmd)
+	sessionsListCmd.Flags().IntVarP(&sessionsLimit, "limit", "n", 20, "Number of sessions to show")
+	sessionsShowCmd.Flags().BoolVar(&sessionsAnnotations, "annotations", false, "Note the model, temperature and tokens under each response")
+}
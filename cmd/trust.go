@@ -0,0 +1,124 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var (
+	trustList   bool
+	trustRevoke bool
+)
+
+// trustCmd represents the trust command
+var trustCmd = &cobra.Command{
+	Use:     "trust [directory]",
+	GroupID: groupSetup,
+	Short:   "Trust a directory's project config and tools",
+	Long: `Trust a directory, the current one by default, and the directories
+inside it.
+
+A project's .deecli/config.yaml can change the model, the proxy and the
+tool permissions, and external tools and plugins run commands on the
+project files. DeeCLI therefore reads the project config and enables those
+tools only in trusted directories. The first interactive run in a new
+directory asks whether to trust it; this command does the same without
+asking, for scripts and CI.
+
+Trusted directories are kept in trusted_directories of the global config.`,
+	Example: `  deecli trust
+  deecli trust ~/src
+  deecli trust --list
+  deecli trust --revoke ~/src`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if trustList {
+			for _, dir := range configManager.TrustedDirectories() {
+				fmt.Println(dir)
+			}
+			return
+		}
+
+		dir, _ := filepath.Abs(".")
+		if len(args) > 0 {
+			dir, _ = filepath.Abs(args[0])
+		}
+		if trustRevoke {
+			found, err := configManager.Untrust(dir)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "❌ Failed to update the global config: %v\n", err)
+				os.Exit(1)
+			}
+			if !found {
+				fmt.Fprintf(os.Stderr, "❌ %s is not in the trusted directories (see deecli trust --list)\n", dir)
+				os.Exit(1)
+			}
+			fmt.Printf("🔒 %s is no longer trusted\n", dir)
+			return
+		}
+
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			fmt.Fprintf(os.Stderr, "❌ %s is not a directory\n", dir)
+			os.Exit(1)
+		}
+		if err := configManager.Trust(dir); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Failed to trust %s: %v\n", dir, err)
+			os.Exit(1)
+		}
+		fmt.Printf("✅ %s is trusted\n", dir)
+	},
+}
+
+// askTrust asks, on the first interactive run in an untrusted directory,
+// whether to trust it, before a command uses the project config or tools.
+// Without a terminal the directory stays untrusted and a project config
+// that exists is reported as ignored.
+func askTrust(cmd *cobra.Command) {
+	if configManager.Trusted() || cmd.Annotations[apiKeyAnnotation] == "" {
+		return
+	}
+	dir := configManager.ProjectDir()
+	if !term.IsTerminal(int(os.Stdin.Fd())) || !term.IsTerminal(int(os.Stdout.Fd())) {
+		if configManager.ProjectConfigExists() && !quiet {
+			fmt.Fprintf(os.Stderr, "⚠️  Ignoring .deecli/config.yaml: %s is not trusted (run 'deecli trust' to trust it)\n", dir)
+		}
+		return
+	}
+
+	fmt.Printf("🔒 Do you trust the files in %s?\n", dir)
+	fmt.Println("   A project's .deecli/config.yaml can change settings and tool permissions,")
+	fmt.Println("   and external tools and plugins run commands on its files.")
+	fmt.Println("   Trusting a directory also trusts the directories inside it.")
+	if !confirm("Trust this directory? [y/N] ") {
+		fmt.Println("Continuing in restricted mode: the project config is ignored and external tools and plugins are disabled")
+		return
+	}
+	if err := configManager.Trust(dir); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Failed to trust %s: %v\n", dir, err)
+		os.Exit(1)
+	}
+	reapplyFlags(cmd)
+}
+
+func init() {
+	rootCmd.AddCommand(trustCmd)
+	trustCmd.Flags().BoolVar(&trustList, "list", false, "List the trusted directories")
+	trustCmd.Flags().BoolVar(&trustRevoke, "revoke", false, "Stop trusting the directory")
+}
@@ -0,0 +1,128 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/antenore/deecli/internal/api"
+	"github.com/antenore/deecli/internal/update"
+	"github.com/spf13/cobra"
+)
+
+// updateTimeout bounds the whole update, download included
+const updateTimeout = 10 * time.Minute
+
+var (
+	updateCheckOnly bool
+	updateYes       bool
+)
+
+// updateCmd represents the update command
+var updateCmd = &cobra.Command{
+	Use:     "update",
+	GroupID: groupSetup,
+	Short:   "Update deecli to the latest release",
+	Long: `Look for a newer release of DeeCLI on GitHub, show its changelog and,
+once confirmed, replace the running binary with it.
+
+The binary for this platform is checked against the SHA-256 sums published
+with the release before anything is replaced; a release without sums is
+not installed. The new binary is written next to the current one and moved
+over it in one step, so an interrupted update leaves the old one working.
+Proxy and certificate settings apply as they do for API requests.
+
+Set check_updates: true to have the chat mention a new release when it
+starts; it asks GitHub at most once a day.`,
+	Example: `  deecli update
+  deecli update --check
+  deecli update --yes`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		client, err := api.NetworkOptionsFromConfig(configManager).HTTPClient(updateTimeout)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Invalid proxy/TLS settings: %v\n", err)
+			os.Exit(1)
+		}
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		release, err := update.Latest(ctx, client)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Could not look for updates: %v\n", err)
+			os.Exit(1)
+		}
+		update.RecordCheck(update.DefaultCachePath(), release.TagName)
+
+		if !update.Newer(release.TagName, update.Version) {
+			fmt.Printf("✅ deecli %s is up to date\n", update.Version)
+			return
+		}
+
+		fmt.Printf("🆕 deecli %s is available (you have %s)\n", release.TagName, update.Version)
+		if notes := strings.TrimSpace(release.Body); notes != "" {
+			fmt.Printf("\n%s\n\n", notes)
+		}
+		if release.HTMLURL != "" {
+			fmt.Printf("Release notes: %s\n", release.HTMLURL)
+		}
+		if updateCheckOnly {
+			return
+		}
+
+		if !updateYes && !confirm(fmt.Sprintf("Install %s? [y/N] ", release.TagName)) {
+			fmt.Println("Update cancelled")
+			return
+		}
+
+		exe, err := os.Executable()
+		if err == nil {
+			exe, err = filepath.EvalSymlinks(exe)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Cannot find the deecli binary: %v\n", err)
+			os.Exit(1)
+		}
+		if err := update.Install(ctx, client, release, exe); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Update failed: %v\n", err)
+			if os.IsPermission(err) {
+				fmt.Fprintf(os.Stderr, "   %s is not writable; run the update as its owner\n", filepath.Dir(exe))
+			}
+			os.Exit(1)
+		}
+		fmt.Printf("✅ Updated %s to %s\n", exe, release.TagName)
+	},
+}
+
+// confirm asks a yes/no question on the terminal, defaulting to no
+func confirm(question string) bool {
+	fmt.Print(question)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+func init() {
+	rootCmd.AddCommand(updateCmd)
+	updateCmd.Flags().BoolVar(&updateCheckOnly, "check", false, "Only show whether a newer release exists and its changelog")
+	updateCmd.Flags().BoolVarP(&updateYes, "yes", "y", false, "Install without asking for confirmation")
+}
@@ -0,0 +1,68 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// MaxChoices is the most alternative answers /send --n asks for
+const MaxChoices = 5
+
+// ChoicesMsg carries the alternative answers to a prompt sent with /send --n
+type ChoicesMsg struct {
+	Prompt  string
+	Choices []string
+	Err     error
+}
+
+// CallAPIChoices asks for n alternative answers to userInput, without tools
+// so that none of them changes anything before one is chosen. The prompt
+// and the chosen answer are added to the history by the caller.
+func (o *Operations) CallAPIChoices(contextPrompt, userInput string, n int) tea.Cmd {
+	// Context size guard (same as CallAPI)
+	contextSize := len(contextPrompt) + len(userInput)
+	contextTokens := EstimateTokens(contextPrompt + userInput)
+
+	maxContextSize := o.configManager.GetMaxContextSize()
+	maxContextTokens := EstimateTokens(fmt.Sprintf("%*s", maxContextSize, ""))
+
+	if contextSize > maxContextSize || contextTokens > maxContextTokens {
+		return func() tea.Msg {
+			fileInfo := o.fileContext.GetInfo()
+			return ChoicesMsg{Prompt: userInput, Err: fmt.Errorf("context too large - chars: %d/%d, tokens: %d/%d\n\n%s\n\nTry loading fewer files or unload large files with /clear",
+				contextSize, maxContextSize, contextTokens, maxContextTokens, fileInfo)}
+		}
+	}
+
+	// Model-aware timeout, for every answer
+	timeout := 180 * time.Second
+	if cfg := o.configManager.Get(); cfg != nil && strings.EqualFold(cfg.Model, "deepseek-reasoner") {
+		timeout = 300 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(n)*timeout)
+	o.apiCancel = cancel
+
+	history := trimHistory(o.apiMessages, defaultHistoryWindow)
+	return func() tea.Msg {
+		defer cancel()
+		choices, err := o.apiClient.ChatWithHistoryChoices(ctx, history, contextPrompt, userInput, n)
+		return ChoicesMsg{Prompt: userInput, Choices: choices, Err: err}
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/antenore/deecli/internal/files"
+)
+
+// DocPackage is a Go package documented by /docs
+type DocPackage struct {
+	Dir      string
+	Files    []files.LoadedFile // Its Go files, with README.md if it has one
+	Problems []string           // Doc convention problems found in it
+}
+
+// DocumentPackages asks for the documentation each package lacks, one
+// request per package, and joins the answers. Their diffs target the loaded
+// files, so they can be reviewed with /apply.
+func (o *Operations) DocumentPackages(ctx context.Context, packages []DocPackage, progress func(done, total int, current string)) (string, error) {
+	if len(packages) == 0 {
+		return "", fmt.Errorf("no Go packages to document")
+	}
+
+	var all strings.Builder
+	for i, pkg := range packages {
+		if progress != nil {
+			progress(i, len(packages), pkg.Dir)
+		}
+		var sources strings.Builder
+		for _, file := range pkg.Files {
+			content, err := o.fileContext.Content(file)
+			if err != nil {
+				return "", err
+			}
+			sources.WriteString(fmt.Sprintf("=== %s ===\n```\n%s\n```\n\n", file.RelPath, content))
+		}
+		answer, err := o.apiClient.DocumentPackage(ctx, pkg.Dir, sources.String(), strings.Join(pkg.Problems, "\n"))
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		if err != nil {
+			return "", fmt.Errorf("error documenting %s: %w", pkg.Dir, err)
+		}
+		all.WriteString(fmt.Sprintf("Documentation for %s:\n\n%s\n\n", pkg.Dir, answer))
+	}
+	return all.String(), nil
+}
@@ -2,9 +2,7 @@ package ai
 
 import (
     "context"
-    "encoding/json"
     "fmt"
-    "io"
     "os"
     "strings"
     "time"
@@ -28,36 +26,31 @@ type APIResponseMsg struct {
 	Err      error
 }
 
-// ToolCallsResponseMsg for API calls that request tool execution
-type ToolCallsResponseMsg struct {
-	ToolCalls []api.ToolCall
-	Response  *api.ChatResponse
-}
-
-// StreamChunkMsg represents a chunk of streaming response
-type StreamChunkMsg struct {
+// ProjectSummaryMsg carries the project map generated by /init
+type ProjectSummaryMsg struct {
 	Content string
-	IsDone  bool
 	Err     error
 }
 
-// StreamCompleteMsg signals the end of a streaming response
-type StreamCompleteMsg struct {
-	TotalContent string
-	Err          error
+// CompactedMsg carries the summary that replaces the older part of the
+// conversation after /compact
+type CompactedMsg struct {
+	Summary  string
+	Replaced int // Number of leading history messages the summary replaces
+	Err      error
 }
 
-// ToolCallsStreamMsg for streaming API calls that request tool execution
-type ToolCallsStreamMsg struct {
-	ToolCalls    []api.ToolCall
-	TotalContent string
+// SessionTitleMsg carries a generated title for a session
+type SessionTitleMsg struct {
+	SessionID int64
+	Title     string
+	Err       error
 }
 
-// StreamChunkWithToolsMsg represents a chunk with accumulated tool calls
-type StreamChunkWithToolsMsg struct {
-	Content   string
+// ToolCallsResponseMsg for API calls that request tool execution
+type ToolCallsResponseMsg struct {
 	ToolCalls []api.ToolCall
-	IsDone    bool
+	Response  *api.ChatResponse
 }
 
 // Operations handles AI-related operations
@@ -68,8 +61,17 @@ type Operations struct {
 	fileContext   *files.FileContext
 	configManager *config.Manager
 	availableTools []api.Tool  // Available function calling tools
+	streamContext string       // Context prompt of the last streamed request, replayed by RetryStream
+	streamInput   string       // User input of the last streamed request
+	lastCall      func(contextPrompt, userInput string) tea.Cmd // Kind of the last chat request, replayed by Reask
+	lastContext   string       // Context prompt of the last chat request
+	lastInput     string       // User input of the last chat request
+	historyWindow int          // History messages the next chat request sends, 0 for defaultHistoryWindow
 }
 
+// defaultHistoryWindow is how many recent history messages a chat request sends
+const defaultHistoryWindow = 30
+
 // NewOperations creates a new Operations instance
 func NewOperations(apiClient *api.Service, fileContext *files.FileContext, configManager *config.Manager) *Operations {
 	return &Operations{
@@ -90,6 +92,16 @@ func (o *Operations) SetAPIMessages(messages []api.Message) {
 	o.apiMessages = messages
 }
 
+// HistorySize returns the size in characters of the conversation history
+// the next chat request sends
+func (o *Operations) HistorySize() int {
+	size := 0
+	for _, msg := range trimHistory(o.apiMessages, defaultHistoryWindow) {
+		size += len(msg.Content)
+	}
+	return size
+}
+
 // GetAPICancel returns the current API cancel function
 func (o *Operations) GetAPICancel() context.CancelFunc {
 	return o.apiCancel
@@ -112,15 +124,14 @@ func (o *Operations) GetAvailableTools() []api.Tool {
 
 // CallAPI makes an API call with context and user input
 func (o *Operations) CallAPI(contextPrompt, userInput string) tea.Cmd {
+	o.lastCall, o.lastContext, o.lastInput = o.CallAPI, contextPrompt, userInput
+	window := o.takeHistoryWindow()
+
 	// Check context size limit before making API call
 	contextSize := len(contextPrompt) + len(userInput)
 	contextTokens := EstimateTokens(contextPrompt + userInput)
 
-	cfg := o.configManager.Get()
-	maxContextSize := cfg.MaxContextSize
-	if maxContextSize == 0 {
-		maxContextSize = 100000 // Default 100KB if not configured
-	}
+	maxContextSize := o.configManager.GetMaxContextSize()
 	maxContextTokens := EstimateTokens(fmt.Sprintf("%*s", maxContextSize, ""))
 
     // Optional debug output (enable with DEECLI_DEBUG=1)
@@ -157,7 +168,7 @@ func (o *Operations) CallAPI(contextPrompt, userInput string) tea.Cmd {
 
     return func() tea.Msg {
         // Trim conversation history to a recent window to reduce re-answering past questions
-        history := trimHistory(o.apiMessages, 30)
+        history := trimHistory(o.apiMessages, window)
         // Check if we have tools available
         if len(o.availableTools) > 0 {
             // Use tools-enabled API call
@@ -190,15 +201,14 @@ func (o *Operations) CallAPI(contextPrompt, userInput string) tea.Cmd {
 // CallAPIWithToolsNoChoice makes a non-streaming API call with tools present but tool_choice="none".
 // Used to finalize an assistant response after tool execution, preventing loops while maintaining tool context.
 func (o *Operations) CallAPIWithToolsNoChoice(contextPrompt, userInput string) tea.Cmd {
+    o.lastCall, o.lastContext, o.lastInput = o.CallAPIWithToolsNoChoice, contextPrompt, userInput
+    window := o.takeHistoryWindow()
+
     // Context size guard (same as CallAPI)
     contextSize := len(contextPrompt) + len(userInput)
     contextTokens := EstimateTokens(contextPrompt + userInput)
 
-    cfg := o.configManager.Get()
-    maxContextSize := cfg.MaxContextSize
-    if maxContextSize == 0 {
-        maxContextSize = 100000
-    }
+    maxContextSize := o.configManager.GetMaxContextSize()
     maxContextTokens := EstimateTokens(fmt.Sprintf("%*s", maxContextSize, ""))
 
     if contextSize > maxContextSize || contextTokens > maxContextTokens {
@@ -222,7 +232,7 @@ func (o *Operations) CallAPIWithToolsNoChoice(contextPrompt, userInput string) t
 
     return func() tea.Msg {
         // Use trimmed history with tools present but tool_choice="none"
-        history := trimHistory(o.apiMessages, 30)
+        history := trimHistory(o.apiMessages, window)
         if len(o.availableTools) > 0 {
             // Use tools-enabled API call with tool_choice="none"
             chatResp, err := o.apiClient.ChatWithHistoryContextAndToolsWithChoice(ctx, history, contextPrompt, userInput, o.availableTools, "none")
@@ -249,11 +259,7 @@ func (o *Operations) CallAPIWithoutTools(contextPrompt, userInput string) tea.Cm
     contextSize := len(contextPrompt) + len(userInput)
     contextTokens := EstimateTokens(contextPrompt + userInput)
 
-    cfg := o.configManager.Get()
-    maxContextSize := cfg.MaxContextSize
-    if maxContextSize == 0 {
-        maxContextSize = 100000
-    }
+    maxContextSize := o.configManager.GetMaxContextSize()
     maxContextTokens := EstimateTokens(fmt.Sprintf("%*s", maxContextSize, ""))
 
     if contextSize > maxContextSize || contextTokens > maxContextTokens {
@@ -277,7 +283,7 @@ func (o *Operations) CallAPIWithoutTools(contextPrompt, userInput string) tea.Cm
 
     return func() tea.Msg {
         // Use trimmed history, but never include tools in this call
-        history := trimHistory(o.apiMessages, 30)
+        history := trimHistory(o.apiMessages, defaultHistoryWindow)
         response, err := o.apiClient.ChatWithHistoryContext(ctx, history, contextPrompt, userInput)
         return APIResponseMsg{Response: response, Err: err}
     }
@@ -286,15 +292,14 @@ func (o *Operations) CallAPIWithoutTools(contextPrompt, userInput string) tea.Cm
 // CallAPIStream makes a streaming API call with context and user input
 // It returns a command that starts the streaming process
 func (o *Operations) CallAPIStream(contextPrompt, userInput string) tea.Cmd {
+	o.lastCall, o.lastContext, o.lastInput = o.CallAPIStream, contextPrompt, userInput
+	window := o.takeHistoryWindow()
+
 	// Check context size limit before making API call
 	contextSize := len(contextPrompt) + len(userInput)
 	contextTokens := EstimateTokens(contextPrompt + userInput)
 
-	cfg := o.configManager.Get()
-	maxContextSize := cfg.MaxContextSize
-	if maxContextSize == 0 {
-		maxContextSize = 100000 // Default 100KB if not configured
-	}
+	maxContextSize := o.configManager.GetMaxContextSize()
 	maxContextTokens := EstimateTokens(fmt.Sprintf("%*s", maxContextSize, ""))
 
     // Optional debug output (enable with DEECLI_DEBUG=1)
@@ -308,7 +313,8 @@ func (o *Operations) CallAPIStream(contextPrompt, userInput string) tea.Cmd {
 		return func() tea.Msg {
 			// Get helpful info about loaded files
 			fileInfo := o.fileContext.GetInfo()
-			return StreamCompleteMsg{
+			return StreamEventMsg{
+				Kind: StreamFailed,
 				Err: fmt.Errorf("context too large - chars: %d/%d, tokens: %d/%d\n\n%s\n\nTry loading fewer files or unload large files with /clear",
 					contextSize, maxContextSize, contextTokens, maxContextTokens, fileInfo),
 			}
@@ -329,10 +335,12 @@ func (o *Operations) CallAPIStream(contextPrompt, userInput string) tea.Cmd {
 
 	// Store the cancel function so we can use it later
 	o.apiCancel = cancel
+	o.streamContext, o.streamInput = contextPrompt, userInput
+	stallTimeout := o.configManager.GetStreamStallTimeout()
 
     return func() tea.Msg {
         // Trim conversation history to a recent window
-        history := trimHistory(o.apiMessages, 30)
+        history := trimHistory(o.apiMessages, window)
         var stream api.StreamReader
         var err error
 
@@ -346,15 +354,75 @@ func (o *Operations) CallAPIStream(contextPrompt, userInput string) tea.Cmd {
         }
 
 		if err != nil {
-			return StreamCompleteMsg{Err: err}
+			return StreamEventMsg{Kind: StreamFailed, Err: err}
 		}
 
-		// Return a StreamReader wrapper that the model can use
-		return StreamStartedMsg{
-			Stream: stream,
-			Ctx:    ctx,
+		return StreamEventMsg{Kind: StreamStarted, Stream: NewStream(ctx, stream, stallTimeout)}
+	}
+}
+
+// RetryStream cancels the current streaming request and sends it again, for
+// a stream that stalled. The history is unchanged until a stream completes,
+// so the replayed request is the same as the original.
+func (o *Operations) RetryStream() tea.Cmd {
+	if o.apiCancel != nil {
+		o.apiCancel()
+		o.apiCancel = nil
+	}
+	return o.CallAPIStream(o.streamContext, o.streamInput)
+}
+
+// Reask sends the last chat request again, in the same way, with nudge
+// added to its user input. It is used when the model returned an empty
+// response; the history is unchanged, so only the nudge differs.
+func (o *Operations) Reask(nudge string) tea.Cmd {
+	if o.lastCall == nil {
+		return func() tea.Msg {
+			return APIResponseMsg{Err: fmt.Errorf("no request to send again")}
 		}
 	}
+	input := nudge
+	if strings.TrimSpace(o.lastInput) != "" {
+		input = o.lastInput + "\n\n" + nudge
+	}
+	return o.lastCall(o.lastContext, input)
+}
+
+// LastContextPrompt returns the file context of the last chat request
+func (o *Operations) LastContextPrompt() string {
+	return o.lastContext
+}
+
+// RetryShorter sends the last chat request again, in the same way, after the
+// provider refused it as too long for the model: contextPrompt replaces its
+// file context and only the last keepTurns user turns of the history are
+// sent. It returns the command and how many older history messages were
+// left out, or a nil command when that would send the same request again.
+// The conversation itself is unchanged.
+func (o *Operations) RetryShorter(contextPrompt string, keepTurns int) (tea.Cmd, int) {
+	if o.lastCall == nil {
+		return func() tea.Msg {
+			return APIResponseMsg{Err: fmt.Errorf("no request to send again")}
+		}, 0
+	}
+	history := trimHistory(o.apiMessages, defaultHistoryWindow)
+	split := CompactSplit(history, keepTurns)
+	if split == 0 && contextPrompt == o.lastContext {
+		return nil, 0
+	}
+	o.historyWindow = len(history) - split
+	return o.lastCall(contextPrompt, o.lastInput), split
+}
+
+// takeHistoryWindow returns how many history messages the chat request being
+// made sends, and restores the default for the next one
+func (o *Operations) takeHistoryWindow() int {
+	window := o.historyWindow
+	o.historyWindow = 0
+	if window <= 0 {
+		return defaultHistoryWindow
+	}
+	return window
 }
 
 // trimHistory keeps only the last N messages to avoid the model re-answering older questions.
@@ -366,219 +434,96 @@ func trimHistory(messages []api.Message, max int) []api.Message {
     return messages[len(messages)-max:]
 }
 
-// StreamStartedMsg indicates that streaming has started
-type StreamStartedMsg struct {
-	Stream api.StreamReader
-	Ctx    context.Context
-}
-
-// ReadNextChunk returns a command to read the next chunk from a stream
-func ReadNextChunk(stream api.StreamReader, accumulated string) tea.Cmd {
-	return ReadNextChunkWithTools(stream, accumulated, nil)
+// EstimateHistoryTokens estimates the tokens a conversation history takes up
+func EstimateHistoryTokens(messages []api.Message) int {
+	tokens := 0
+	for _, msg := range messages {
+		tokens += EstimateTokens(msg.Content)
+	}
+	return tokens
 }
 
-// ReadNextChunkWithTools returns a command to read the next chunk from a stream, handling tool calls
-func ReadNextChunkWithTools(stream api.StreamReader, accumulated string, accumulatedToolCalls []api.ToolCall) tea.Cmd {
-	return func() tea.Msg {
-		chunk, err := stream.Recv()
-		if err != nil {
-			if err == io.EOF {
-				// Stream completed successfully
-				stream.Close()
-				// If we have accumulated tool calls, return them
-				if len(accumulatedToolCalls) > 0 {
-					return ToolCallsStreamMsg{
-						ToolCalls:    accumulatedToolCalls,
-						TotalContent: accumulated,
-					}
-				}
-				return StreamCompleteMsg{TotalContent: accumulated}
-			}
-			// Stream error
-			stream.Close()
-			return StreamCompleteMsg{TotalContent: accumulated, Err: err}
-		}
-
-		// Check for finish reason indicating tool calls
-		if len(chunk.Choices) > 0 && chunk.Choices[0].FinishReason != nil {
-			finishReason := *chunk.Choices[0].FinishReason
-			// Tool calls can be signaled by "tool_calls" or "function_call"
-			if finishReason == "tool_calls" || finishReason == "function_call" {
-				// Tool calls are complete
-				if len(accumulatedToolCalls) > 0 {
-					return ToolCallsStreamMsg{
-						ToolCalls:    accumulatedToolCalls,
-						TotalContent: accumulated,
-					}
-				}
-			}
-		}
-
-		// Extract content and tool calls from chunk
-		content := ""
-		var toolCalls []api.ToolCall
-		if len(chunk.Choices) > 0 {
-			content = chunk.Choices[0].Delta.Content
-			
-			// Enhanced debug logging for DeepSeek responses
-			if os.Getenv("DEECLI_DEBUG") == "1" && content != "" {
-				fmt.Fprintf(os.Stderr, "[DEBUG] Stream chunk content: %q\n", content)
-				if len(content) > 0 {
-					// Check if content looks like tool call markers
-					if strings.Contains(content, "<") || strings.Contains(content, "tool") {
-						fmt.Fprintf(os.Stderr, "[DEBUG] Suspicious content detected (possible tool markers): %q\n", content)
-					}
-				}
-			}
-
-			// Check for tool calls in the delta
-			if len(chunk.Choices[0].Delta.ToolCalls) > 0 {
-				toolCalls = chunk.Choices[0].Delta.ToolCalls
-				// Merge with accumulated tool calls
-				accumulatedToolCalls = mergeToolCalls(accumulatedToolCalls, toolCalls)
-
-				// Debug: log tool call progress (optional, enable with DEECLI_DEBUG=1)
-				if os.Getenv("DEECLI_DEBUG") == "1" {
-					for _, tc := range accumulatedToolCalls {
-						fmt.Fprintf(os.Stderr, "[DEBUG] Tool call ID=%s, Name=%s, Args=%q\n", tc.ID, tc.Function.Name, tc.Function.Arguments)
-					}
-				}
-			} else if os.Getenv("DEECLI_DEBUG") == "1" {
-				// Log when no tool calls are found but content exists
-				if content != "" {
-					fmt.Fprintf(os.Stderr, "[DEBUG] No tool_calls in delta, but content present: %q\n", content)
-				}
-			}
-		}
-
-		// If we have tool calls but no finish reason yet, continue accumulating
-		if len(accumulatedToolCalls) > 0 && (len(chunk.Choices) == 0 || chunk.Choices[0].FinishReason == nil || *chunk.Choices[0].FinishReason != "tool_calls") {
-			// Continue reading chunks with accumulated tool calls
-			return StreamChunkWithToolsMsg{
-				Content:   content,
-				ToolCalls: accumulatedToolCalls,
-				IsDone:    false,
-			}
+// CompactSplit returns the index of the message that starts the last keep
+// user turns, or 0 when there are no earlier messages to summarize. Splitting
+// on a user message keeps tool calls together with their results.
+func CompactSplit(messages []api.Message, keep int) int {
+	if keep <= 0 {
+		return len(messages)
+	}
+	turns := 0
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Role != "user" {
+			continue
 		}
-
-		return StreamChunkMsg{
-			Content: content,
-			IsDone:  false,
+		turns++
+		if turns >= keep {
+			return i
 		}
 	}
+	return 0
 }
 
-// mergeToolCalls merges new tool call deltas into accumulated tool calls
-func mergeToolCalls(accumulated, new []api.ToolCall) []api.ToolCall {
-	if len(accumulated) == 0 {
-		return new
-	}
+// CompactConversation summarizes the history before the last keep user turns
+func (o *Operations) CompactConversation(keep int) tea.Cmd {
+	split := CompactSplit(o.apiMessages, keep)
+	// Snapshot the part to summarize; new messages only go after it
+	older := make([]api.Message, split)
+	copy(older, o.apiMessages[:split])
 
-	// Map to track existing tool calls by ID
-	toolMap := make(map[string]*api.ToolCall)
-	for i := range accumulated {
-		toolMap[accumulated[i].ID] = &accumulated[i]
-	}
+	ctx, cancel := context.WithCancel(context.Background())
+	o.apiCancel = cancel
 
-	// Merge new tool calls
-	for _, newCall := range new {
-		if existing, ok := toolMap[newCall.ID]; ok {
-			// Merge arguments (they may come in chunks)
-			if newCall.Function.Arguments != "" {
-				// Check if we already have arguments - if so, concatenate
-				if existing.Function.Arguments == "" {
-					existing.Function.Arguments = newCall.Function.Arguments
-				} else {
-					// Concatenate carefully - ensure we don't break JSON
-					// This handles cases where arguments are split across chunks
-					existing.Function.Arguments += newCall.Function.Arguments
-					
-					// Validate if we have complete JSON now
-					if os.Getenv("DEECLI_DEBUG") == "1" {
-						var test interface{}
-						if err := json.Unmarshal([]byte(existing.Function.Arguments), &test); err != nil {
-							fmt.Fprintf(os.Stderr, "[DEBUG] Partial JSON accumulated for tool %s: %s\n", existing.Function.Name, existing.Function.Arguments)
-						} else {
-							fmt.Fprintf(os.Stderr, "[DEBUG] Complete JSON for tool %s: %s\n", existing.Function.Name, existing.Function.Arguments)
-						}
-					}
-				}
-			}
-			// Update name if provided
-			if newCall.Function.Name != "" {
-				existing.Function.Name = newCall.Function.Name
-			}
-		} else {
-			// New tool call
-			accumulated = append(accumulated, newCall)
-			toolMap[newCall.ID] = &accumulated[len(accumulated)-1]
-		}
+	return func() tea.Msg {
+		summary, err := o.apiClient.SummarizeConversation(ctx, older)
+		return CompactedMsg{Summary: summary, Replaced: split, Err: err}
 	}
-
-	return accumulated
 }
 
-// AnalyzeFiles analyzes loaded files
-func (o *Operations) AnalyzeFiles() tea.Cmd {
-	return func() tea.Msg {
-		if len(o.fileContext.Files) == 0 {
-			return APIResponseMsg{Err: fmt.Errorf("no files loaded")}
-		}
-
-		var allAnalysis strings.Builder
-		for _, file := range o.fileContext.Files {
-			analysis, err := o.apiClient.AnalyzeCode(file.Content, file.RelPath)
-			if err != nil {
-				return APIResponseMsg{Err: fmt.Errorf("error analyzing %s: %w", file.RelPath, err)}
-			}
-			allAnalysis.WriteString(fmt.Sprintf("Analysis of %s:\n\n%s\n\n", file.RelPath, analysis))
-		}
-
-		return APIResponseMsg{Response: allAnalysis.String()}
+// eachFile sends the loaded files one request at a time and joins the
+// answers, each under heading. progress, if set, is called before each request.
+func eachFile(ctx context.Context, fc *files.FileContext, loaded []files.LoadedFile, heading, verb string, send func(ctx context.Context, code, filename string) (string, error), progress func(done, total int, current string)) (string, error) {
+	if len(loaded) == 0 {
+		return "", fmt.Errorf("no files loaded")
 	}
-}
 
-// ExplainFiles explains loaded files
-func (o *Operations) ExplainFiles() tea.Cmd {
-	return func() tea.Msg {
-		if len(o.fileContext.Files) == 0 {
-			return APIResponseMsg{Err: fmt.Errorf("no files loaded")}
+	var all strings.Builder
+	for i, file := range loaded {
+		if progress != nil {
+			progress(i, len(loaded), file.RelPath)
 		}
-
-		var allExplanations strings.Builder
-		for _, file := range o.fileContext.Files {
-			explanation, err := o.apiClient.ExplainCode(file.Content, file.RelPath)
-			if err != nil {
-				return APIResponseMsg{Err: fmt.Errorf("error explaining %s: %w", file.RelPath, err)}
-			}
-			allExplanations.WriteString(fmt.Sprintf("Explanation of %s:\n\n%s\n\n", file.RelPath, explanation))
+		content, err := fc.Content(file)
+		if err != nil {
+			return "", err
 		}
-
-		return APIResponseMsg{Response: allExplanations.String()}
+		answer, err := send(ctx, content, file.RelPath)
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		if err != nil {
+			return "", fmt.Errorf("error %s %s: %w", verb, file.RelPath, err)
+		}
+		all.WriteString(fmt.Sprintf("%s %s:\n\n%s\n\n", heading, file.RelPath, answer))
 	}
+	return all.String(), nil
 }
 
-// ImproveFiles suggests improvements for loaded files
-func (o *Operations) ImproveFiles() tea.Cmd {
-	return func() tea.Msg {
-		if len(o.fileContext.Files) == 0 {
-			return APIResponseMsg{Err: fmt.Errorf("no files loaded")}
-		}
+// AnalyzeFiles analyzes the given loaded files
+func (o *Operations) AnalyzeFiles(ctx context.Context, loaded []files.LoadedFile, progress func(done, total int, current string)) (string, error) {
+	return eachFile(ctx, o.fileContext, loaded, "Analysis of", "analyzing", o.apiClient.AnalyzeCode, progress)
+}
 
-		var allImprovements strings.Builder
-		for _, file := range o.fileContext.Files {
-			improvements, err := o.apiClient.ImproveCode(file.Content, file.RelPath)
-			if err != nil {
-				return APIResponseMsg{Err: fmt.Errorf("error improving %s: %w", file.RelPath, err)}
-			}
-			allImprovements.WriteString(fmt.Sprintf("Improvement suggestions for %s:\n\n%s\n\n", file.RelPath, improvements))
-		}
+// ExplainFiles explains the given loaded files
+func (o *Operations) ExplainFiles(ctx context.Context, loaded []files.LoadedFile, progress func(done, total int, current string)) (string, error) {
+	return eachFile(ctx, o.fileContext, loaded, "Explanation of", "explaining", o.apiClient.ExplainCode, progress)
+}
 
-		return APIResponseMsg{Response: allImprovements.String()}
-	}
+// ImproveFiles suggests improvements for the given loaded files
+func (o *Operations) ImproveFiles(ctx context.Context, loaded []files.LoadedFile, progress func(done, total int, current string)) (string, error) {
+	return eachFile(ctx, o.fileContext, loaded, "Improvement suggestions for", "improving", o.apiClient.ImproveCode, progress)
 }
 
-// GenerateEditSuggestions suggests edits based on conversation history
+// GenerateEditSuggestions suggests edits based on conversation history, as
+// an EditSuggestionsMsg
 func (o *Operations) GenerateEditSuggestions() tea.Cmd {
 	// Create a context that can be cancelled
 	ctx, cancel := context.WithCancel(context.Background())
@@ -616,11 +561,10 @@ func (o *Operations) GenerateEditSuggestions() tea.Cmd {
 		promptBuilder.WriteString("2. Feature requests or improvements discussed\n")
 		promptBuilder.WriteString("3. Code quality concerns raised\n")
 		promptBuilder.WriteString("4. Missing functionality identified\n\n")
-		promptBuilder.WriteString("Format your response as:\n")
-		promptBuilder.WriteString("**📝 Edit Suggestions:**\n")
-		promptBuilder.WriteString("• **filename.ext** - Brief description of what changes are needed\n")
-		promptBuilder.WriteString("• **another.file** - Another change description\n\n")
-		promptBuilder.WriteString("If no specific changes are needed, suggest general improvements or say 'No specific edits needed based on current conversation'.")
+		promptBuilder.WriteString("Write one line per suggested edit, and nothing else, in this format:\n")
+		promptBuilder.WriteString("SUGGESTION | priority | path/to/file.ext | Brief description of what changes are needed\n\n")
+		promptBuilder.WriteString("Priority is high, medium or low. Use the file paths as listed above.\n")
+		promptBuilder.WriteString("If no specific changes are needed, say 'No specific edits needed based on current conversation'.")
 
 		// Create messages for API call
 		messages := []api.Message{
@@ -636,6 +580,59 @@ func (o *Operations) GenerateEditSuggestions() tea.Cmd {
 
 		// Call API with context for cancellation
 		response, err := o.apiClient.ChatWithHistoryContext(ctx, messages, "", "")
-		return APIResponseMsg{Response: response, Err: err}
+		if err != nil {
+			return EditSuggestionsMsg{Err: err}
+		}
+		return EditSuggestionsMsg{Suggestions: ParseEditSuggestions(response), Response: response}
+	}
+}
+
+// InitProject walks the project and asks the AI for a project map
+func (o *Operations) InitProject() tea.Cmd {
+	// Create a context that can be cancelled
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// Store the cancel function
+	o.apiCancel = cancel
+
+	loader := o.fileContext.Loader
+
+	return func() tea.Msg {
+		overview, err := loader.BuildProjectOverview(".")
+		if err != nil {
+			return ProjectSummaryMsg{Err: err}
+		}
+
+		summary, err := o.apiClient.GenerateProjectMap(ctx, overview)
+		return ProjectSummaryMsg{Content: summary, Err: err}
+	}
+}
+
+// ReviewPullRequest asks the AI to review a pull request diff
+func (o *Operations) ReviewPullRequest(title, description, diff string) tea.Cmd {
+	// Create a context that can be cancelled
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// Store the cancel function
+	o.apiCancel = cancel
+
+	return func() tea.Msg {
+		review, err := o.apiClient.ReviewPullRequest(ctx, title, description, diff)
+		return APIResponseMsg{Response: review, Err: err}
+	}
+}
+
+// GenerateSessionTitle asks the AI for a short title in the background
+func (o *Operations) GenerateSessionTitle(sessionID int64) tea.Cmd {
+	// Snapshot the history so the background call is not affected by new messages
+	history := make([]api.Message, len(o.apiMessages))
+	copy(history, o.apiMessages)
+
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		title, err := o.apiClient.GenerateSessionTitle(ctx, history)
+		return SessionTitleMsg{SessionID: sessionID, Title: title, Err: err}
 	}
 }
@@ -0,0 +1,92 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"testing"
+
+	"github.com/antenore/deecli/internal/api"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestCompactSplit(t *testing.T) {
+	history := []api.Message{
+		{Role: "user", Content: "first"},
+		{Role: "assistant", Content: "answer"},
+		{Role: "user", Content: "read main.go"},
+		{Role: "assistant", Content: "calling read_file"},
+		{Role: "tool", Content: "package main"},
+		{Role: "assistant", Content: "it is empty"},
+		{Role: "user", Content: "thanks"},
+		{Role: "assistant", Content: "welcome"},
+	}
+
+	tests := []struct {
+		keep int
+		want int
+	}{
+		{keep: 1, want: 6},
+		{keep: 2, want: 2}, // the tool call stays with its turn
+		{keep: 3, want: 0},
+		{keep: 5, want: 0},
+		{keep: 0, want: len(history)},
+	}
+	for _, tt := range tests {
+		if got := CompactSplit(history, tt.keep); got != tt.want {
+			t.Errorf("CompactSplit(keep=%d) = %d, want %d", tt.keep, got, tt.want)
+		}
+	}
+
+	if got := EstimateHistoryTokens(history[:2]); got != (len("first")+len("answer"))/4 {
+		t.Errorf("EstimateHistoryTokens() = %d", got)
+	}
+}
+
+func TestRetryShorter(t *testing.T) {
+	o := &Operations{apiMessages: []api.Message{
+		{Role: "user", Content: "first"},
+		{Role: "assistant", Content: "answer"},
+		{Role: "user", Content: "second"},
+		{Role: "assistant", Content: "answer"},
+		{Role: "user", Content: "third"},
+	}}
+	if cmd, _ := o.RetryShorter("", 2); cmd == nil {
+		t.Fatal("expected an error command without a previous request")
+	}
+
+	var sentContext, sentInput string
+	var sentWindow int
+	o.lastCall = func(contextPrompt, userInput string) tea.Cmd {
+		o.lastContext, o.lastInput = contextPrompt, userInput
+		sentContext, sentInput, sentWindow = contextPrompt, userInput, o.takeHistoryWindow()
+		return func() tea.Msg { return nil }
+	}
+	o.lastContext, o.lastInput = "all the files", "third"
+
+	cmd, dropped := o.RetryShorter("fewer files", 2)
+	if cmd == nil || dropped != 2 {
+		t.Fatalf("RetryShorter() = %v, %d, want a command leaving out 2 messages", cmd != nil, dropped)
+	}
+	if sentContext != "fewer files" || sentInput != "third" || sentWindow != 3 {
+		t.Errorf("sent context %q, input %q, window %d", sentContext, sentInput, sentWindow)
+	}
+	if got := o.takeHistoryWindow(); got != defaultHistoryWindow {
+		t.Errorf("window after the retry = %d, want the default %d", got, defaultHistoryWindow)
+	}
+
+	// Nothing left to cut
+	if cmd, _ := o.RetryShorter("fewer files", 5); cmd != nil {
+		t.Error("expected no retry when the request would be the same")
+	}
+}
@@ -0,0 +1,157 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ReviewSeverities are the severity labels of review findings, worst first
+var ReviewSeverities = []string{"critical", "major", "minor", "nit"}
+
+// findingLine matches "FINDING | severity | file:line | description"
+var findingLine = regexp.MustCompile(`(?i)^\s*(?:[-*]\s*)?\**FINDING\**\s*\|\s*(\w+)\s*\|\s*([^|]+?)\s*\|\s*(.+?)\s*$`)
+
+// ReviewFinding is one problem reported by a branch review
+type ReviewFinding struct {
+	Severity    string
+	File        string
+	Line        int // 0 when the finding is about the file as a whole
+	Description string
+}
+
+// ParseReviewFindings extracts the findings from a review reply. Unknown
+// severities are reported as minor.
+func ParseReviewFindings(reply string) []ReviewFinding {
+	var findings []ReviewFinding
+	for _, line := range strings.Split(reply, "\n") {
+		match := findingLine.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		finding := ReviewFinding{
+			Severity:    strings.ToLower(match[1]),
+			File:        strings.Trim(match[2], "`"),
+			Description: match[3],
+		}
+		if !slices.Contains(ReviewSeverities, finding.Severity) {
+			finding.Severity = "minor"
+		}
+		if i := strings.LastIndex(finding.File, ":"); i > 0 {
+			if n, err := strconv.Atoi(finding.File[i+1:]); err == nil {
+				finding.File, finding.Line = finding.File[:i], n
+			}
+		}
+		findings = append(findings, finding)
+	}
+	return findings
+}
+
+// FormatReviewReport groups findings by file, worst files and findings
+// first. failed lists the diff parts that could not be reviewed.
+func FormatReviewReport(refRange string, findings []ReviewFinding, parts int, failed []int) string {
+	rank := func(severity string) int { return slices.Index(ReviewSeverities, severity) }
+
+	byFile := make(map[string][]ReviewFinding)
+	var paths []string
+	counts := make(map[string]int)
+	for _, finding := range findings {
+		if _, ok := byFile[finding.File]; !ok {
+			paths = append(paths, finding.File)
+		}
+		byFile[finding.File] = append(byFile[finding.File], finding)
+		counts[finding.Severity]++
+	}
+	for _, path := range paths {
+		slices.SortStableFunc(byFile[path], func(a, b ReviewFinding) int {
+			if rank(a.Severity) != rank(b.Severity) {
+				return rank(a.Severity) - rank(b.Severity)
+			}
+			return a.Line - b.Line
+		})
+	}
+	slices.SortStableFunc(paths, func(a, b string) int {
+		if worst := rank(byFile[a][0].Severity) - rank(byFile[b][0].Severity); worst != 0 {
+			return worst
+		}
+		return strings.Compare(a, b)
+	})
+
+	var report strings.Builder
+	report.WriteString(fmt.Sprintf("## Review of %s\n\n", refRange))
+	if len(findings) == 0 {
+		report.WriteString(fmt.Sprintf("No findings in %d part(s) of the diff.\n", parts))
+	} else {
+		var summary []string
+		for _, severity := range ReviewSeverities {
+			if counts[severity] > 0 {
+				summary = append(summary, fmt.Sprintf("%d %s", counts[severity], severity))
+			}
+		}
+		report.WriteString(fmt.Sprintf("%d finding(s) in %d file(s): %s. Reviewed in %d part(s).\n",
+			len(findings), len(paths), strings.Join(summary, ", "), parts))
+	}
+	if len(failed) > 0 {
+		var numbers []string
+		for _, part := range failed {
+			numbers = append(numbers, strconv.Itoa(part))
+		}
+		report.WriteString(fmt.Sprintf("\n⚠️ Part(s) %s could not be reviewed; their files may have findings that are missing here.\n", strings.Join(numbers, ", ")))
+	}
+
+	for _, path := range paths {
+		report.WriteString(fmt.Sprintf("\n### %s\n", path))
+		for _, finding := range byFile[path] {
+			location := ""
+			if finding.Line > 0 {
+				location = fmt.Sprintf(" line %d:", finding.Line)
+			}
+			report.WriteString(fmt.Sprintf("- **%s**%s %s\n", strings.ToUpper(finding.Severity), location, finding.Description))
+		}
+	}
+	return strings.TrimRight(report.String(), "\n")
+}
+
+// ReviewDiff reviews the chunks of the diff for refRange one request at a
+// time and aggregates the findings into a single report. progress, if set,
+// is called before each request.
+func (o *Operations) ReviewDiff(ctx context.Context, refRange string, chunks []string, progress func(done, total int, current string)) (string, error) {
+	var findings []ReviewFinding
+	var failed []int
+	var lastErr error
+	for i, chunk := range chunks {
+		if progress != nil {
+			progress(i, len(chunks), fmt.Sprintf("part %d", i+1))
+		}
+		reply, err := o.apiClient.ReviewDiffChunk(ctx, refRange, chunk, i+1, len(chunks))
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		if err != nil {
+			failed = append(failed, i+1)
+			lastErr = err
+			continue
+		}
+		findings = append(findings, ParseReviewFindings(reply)...)
+	}
+	if len(failed) == len(chunks) {
+		return "", lastErr
+	}
+	return FormatReviewReport(refRange, findings, len(chunks), failed), nil
+}
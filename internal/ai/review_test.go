@@ -0,0 +1,79 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseReviewFindings(t *testing.T) {
+	reply := strings.Join([]string{
+		"Here is what I found:",
+		"FINDING | major | `internal/a.go:12` | Error is ignored",
+		"- **FINDING** | Critical | b.go:3 | Nil dereference",
+		"FINDING | severe | c.go | Unclear naming",
+		"NO FINDINGS",
+	}, "\n")
+
+	got := ParseReviewFindings(reply)
+	want := []ReviewFinding{
+		{Severity: "major", File: "internal/a.go", Line: 12, Description: "Error is ignored"},
+		{Severity: "critical", File: "b.go", Line: 3, Description: "Nil dereference"},
+		{Severity: "minor", File: "c.go", Description: "Unclear naming"},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("ParseReviewFindings() = %+v, want %+v", got, want)
+	}
+}
+
+func TestFormatReviewReport(t *testing.T) {
+	findings := []ReviewFinding{
+		{Severity: "nit", File: "a.go", Line: 9, Description: "Typo"},
+		{Severity: "major", File: "b.go", Line: 20, Description: "Leak"},
+		{Severity: "critical", File: "a.go", Line: 30, Description: "Panic"},
+		{Severity: "major", File: "b.go", Line: 5, Description: "Race"},
+	}
+
+	report := FormatReviewReport("main..HEAD", findings, 2, []int{2})
+	for _, want := range []string{
+		"## Review of main..HEAD",
+		"4 finding(s) in 2 file(s): 1 critical, 2 major, 1 nit. Reviewed in 2 part(s).",
+		"Part(s) 2 could not be reviewed",
+		"- **CRITICAL** line 30: Panic",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report missing %q:\n%s", want, report)
+		}
+	}
+
+	// Files with the worst findings come first, and findings by severity then line
+	order := []string{"### a.go", "Panic", "Typo", "### b.go", "Race", "Leak"}
+	last := -1
+	for _, text := range order {
+		i := strings.Index(report, text)
+		if i < last {
+			t.Errorf("%q is out of order:\n%s", text, report)
+		}
+		last = i
+	}
+}
+
+func TestFormatReviewReportNoFindings(t *testing.T) {
+	report := FormatReviewReport("v1..v2", nil, 1, nil)
+	if !strings.Contains(report, "No findings in 1 part(s)") {
+		t.Errorf("unexpected report:\n%s", report)
+	}
+}
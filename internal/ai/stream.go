@@ -0,0 +1,277 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/antenore/deecli/internal/api"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// StreamEventKind identifies what a StreamEventMsg reports
+type StreamEventKind int
+
+const (
+	StreamStarted StreamEventKind = iota // The API accepted the request; read events from Stream
+	StreamDelta                          // Content holds newly received response text
+	StreamDone                           // The response finished; ToolCalls lists any requested tools
+	StreamFailed                         // Err ended the stream (or kept it from starting)
+	StreamStalled                        // No data arrived for the stall timeout; the stream keeps waiting
+)
+
+// streamBuffer is how many events the reader may queue ahead of the UI
+const streamBuffer = 64
+
+// StreamEventMsg is the single message the streaming pipeline sends to the UI
+type StreamEventMsg struct {
+	Kind      StreamEventKind
+	Stream    *Stream        // The stream the event belongs to
+	Content   string         // New text for StreamDelta, all text so far for StreamDone, StreamFailed and StreamStalled
+	ToolCalls []api.ToolCall // Complete tool calls requested by the response (StreamDone)
+	Err       error          // Why the stream failed (StreamFailed)
+}
+
+// Stream reads an API stream on its own goroutine and delivers StreamEventMsg
+// values over a channel, so the UI never blocks on the network
+type Stream struct {
+	Ctx          context.Context
+	events       chan StreamEventMsg
+	done         chan struct{}
+	closeOnce    sync.Once
+	stallTimeout time.Duration
+}
+
+// recvResult is one result of StreamReader.Recv
+type recvResult struct {
+	chunk api.ChatCompletionChunk
+	err   error
+}
+
+// NewStream starts reading reader in the background. The reader is closed when
+// the stream ends or Close is called. A StreamStalled event is sent whenever
+// no chunk arrives for stallTimeout; zero disables stall detection. SSE
+// keep-alive comments are skipped by the reader, so they do not count as data.
+func NewStream(ctx context.Context, reader api.StreamReader, stallTimeout time.Duration) *Stream {
+	s := &Stream{
+		Ctx:          ctx,
+		events:       make(chan StreamEventMsg, streamBuffer),
+		done:         make(chan struct{}),
+		stallTimeout: stallTimeout,
+	}
+	go s.pump(reader)
+	return s
+}
+
+// pump feeds chunks through a StreamAccumulator and emits the resulting events
+func (s *Stream) pump(reader api.StreamReader) {
+	defer close(s.events)
+	defer reader.Close()
+
+	// Recv blocks, so it runs on its own goroutine and the stall timer can fire
+	results := make(chan recvResult)
+	go func() {
+		for {
+			chunk, err := reader.Recv()
+			select {
+			case results <- recvResult{chunk, err}:
+			case <-s.done:
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	var stall <-chan time.Time
+	var timer *time.Timer
+	if s.stallTimeout > 0 {
+		timer = time.NewTimer(s.stallTimeout)
+		defer timer.Stop()
+		stall = timer.C
+	}
+
+	var acc StreamAccumulator
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-stall:
+			// Report the stall once; the timer is rearmed by the next chunk
+			stall = nil
+			if !s.emit(StreamEventMsg{Kind: StreamStalled, Content: acc.Content()}) {
+				return
+			}
+		case r := <-results:
+			if r.err == io.EOF {
+				s.emit(StreamEventMsg{Kind: StreamDone, Content: acc.Content(), ToolCalls: acc.ToolCalls()})
+				return
+			}
+			if r.err != nil {
+				s.emit(StreamEventMsg{Kind: StreamFailed, Content: acc.Content(), Err: r.err})
+				return
+			}
+			if timer != nil {
+				timer.Reset(s.stallTimeout)
+				stall = timer.C
+			}
+			if delta := acc.Add(r.chunk); delta != "" {
+				if !s.emit(StreamEventMsg{Kind: StreamDelta, Content: delta}) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// emit queues ev for the UI, returning false once the stream was closed
+func (s *Stream) emit(ev StreamEventMsg) bool {
+	ev.Stream = s
+	select {
+	case s.events <- ev:
+		return true
+	case <-s.done:
+		return false
+	}
+}
+
+// Next returns a command that waits for the next event. It yields nil once
+// the stream has ended.
+func (s *Stream) Next() tea.Cmd {
+	return func() tea.Msg {
+		ev, ok := <-s.events
+		if !ok {
+			return nil
+		}
+		return ev
+	}
+}
+
+// Events returns the event channel, for consumers outside the Bubble Tea loop
+func (s *Stream) Events() <-chan StreamEventMsg {
+	return s.events
+}
+
+// Close stops delivering events. Cancel the request context to also abort
+// a read that is in progress.
+func (s *Stream) Close() {
+	s.closeOnce.Do(func() { close(s.done) })
+}
+
+// StreamAccumulator is the state machine that folds streaming chunks into the
+// response text and complete tool calls
+type StreamAccumulator struct {
+	content      strings.Builder
+	calls        []api.ToolCall
+	byIndex      map[int]int // Delta index to position in calls
+	finishReason string
+}
+
+// Add folds a chunk into the accumulated state and returns the text it carried
+func (a *StreamAccumulator) Add(chunk api.ChatCompletionChunk) string {
+	if len(chunk.Choices) == 0 {
+		return ""
+	}
+	choice := chunk.Choices[0]
+
+	a.content.WriteString(choice.Delta.Content)
+	for _, delta := range choice.Delta.ToolCalls {
+		a.addToolCall(delta)
+	}
+	// The finish reason may arrive in the same chunk as the last fragment
+	if choice.FinishReason != nil {
+		a.finishReason = *choice.FinishReason
+	}
+	return choice.Delta.Content
+}
+
+// addToolCall merges one tool call fragment. Fragments are matched by index
+// when the API provides one, otherwise by ID; a fragment with neither
+// continues the last call.
+func (a *StreamAccumulator) addToolCall(delta api.ToolCallDelta) {
+	pos := -1
+	switch {
+	case delta.Index != nil:
+		if i, ok := a.byIndex[*delta.Index]; ok {
+			pos = i
+		}
+	case delta.ID != "":
+		for i, call := range a.calls {
+			if call.ID == delta.ID {
+				pos = i
+				break
+			}
+		}
+	case len(a.calls) > 0:
+		pos = len(a.calls) - 1
+	}
+
+	if pos == -1 {
+		a.calls = append(a.calls, api.ToolCall{})
+		pos = len(a.calls) - 1
+		if delta.Index != nil {
+			if a.byIndex == nil {
+				a.byIndex = make(map[int]int)
+			}
+			a.byIndex[*delta.Index] = pos
+		}
+	}
+
+	call := &a.calls[pos]
+	if delta.ID != "" {
+		call.ID = delta.ID
+	}
+	if delta.Type != "" {
+		call.Type = delta.Type
+	}
+	if delta.Function.Name != "" {
+		call.Function.Name = delta.Function.Name
+	}
+	call.Function.Arguments += delta.Function.Arguments
+}
+
+// Content returns all response text received so far
+func (a *StreamAccumulator) Content() string {
+	return a.content.String()
+}
+
+// FinishReason returns the finish reason reported by the API, if any
+func (a *StreamAccumulator) FinishReason() string {
+	return a.finishReason
+}
+
+// ToolCalls returns the tool calls received so far. Calls without a name are
+// dropped, and missing IDs and types are filled in so results can be matched.
+func (a *StreamAccumulator) ToolCalls() []api.ToolCall {
+	var calls []api.ToolCall
+	for i, call := range a.calls {
+		if call.Function.Name == "" {
+			continue
+		}
+		if call.ID == "" {
+			call.ID = fmt.Sprintf("call_%d", i+1)
+		}
+		if call.Type == "" {
+			call.Type = "function"
+		}
+		calls = append(calls, call)
+	}
+	return calls
+}
@@ -0,0 +1,218 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/antenore/deecli/internal/api"
+)
+
+// chunk decodes a streaming chunk from its JSON wire format
+func chunk(t *testing.T, data string) api.ChatCompletionChunk {
+	t.Helper()
+	var c api.ChatCompletionChunk
+	if err := json.Unmarshal([]byte(data), &c); err != nil {
+		t.Fatalf("bad chunk %s: %v", data, err)
+	}
+	return c
+}
+
+func TestStreamAccumulator_IndexedToolCalls(t *testing.T) {
+	var acc StreamAccumulator
+	// Only the first fragment of each call carries the ID and name
+	for _, data := range []string{
+		`{"choices":[{"delta":{"content":"Let me look."}}]}`,
+		`{"choices":[{"delta":{"tool_calls":[{"index":0,"id":"call_a","type":"function","function":{"name":"read_file","arguments":""}}]}}]}`,
+		`{"choices":[{"delta":{"tool_calls":[{"index":0,"function":{"arguments":"{\"path\":"}}]}}]}`,
+		`{"choices":[{"delta":{"tool_calls":[{"index":1,"id":"call_b","type":"function","function":{"name":"list_files","arguments":"{}"}}]}}]}`,
+		`{"choices":[{"delta":{"tool_calls":[{"index":0,"function":{"arguments":"\"main.go\"}"}}]},"finish_reason":"tool_calls"}]}`,
+	} {
+		acc.Add(chunk(t, data))
+	}
+
+	if acc.Content() != "Let me look." {
+		t.Errorf("unexpected content %q", acc.Content())
+	}
+	if acc.FinishReason() != "tool_calls" {
+		t.Errorf("unexpected finish reason %q", acc.FinishReason())
+	}
+
+	calls := acc.ToolCalls()
+	if len(calls) != 2 {
+		t.Fatalf("expected 2 tool calls, got %d: %+v", len(calls), calls)
+	}
+	if calls[0].ID != "call_a" || calls[0].Function.Name != "read_file" || calls[0].Function.Arguments != `{"path":"main.go"}` {
+		t.Errorf("unexpected first call %+v", calls[0])
+	}
+	if calls[1].ID != "call_b" || calls[1].Function.Arguments != "{}" {
+		t.Errorf("unexpected second call %+v", calls[1])
+	}
+}
+
+func TestStreamAccumulator_UnindexedToolCalls(t *testing.T) {
+	var acc StreamAccumulator
+	for _, data := range []string{
+		`{"choices":[{"delta":{"tool_calls":[{"id":"x1","function":{"name":"git_status","arguments":"{"}}]}}]}`,
+		`{"choices":[{"delta":{"tool_calls":[{"function":{"arguments":"}"}}]}}]}`,
+		`{"choices":[{"delta":{"tool_calls":[{"function":{"arguments":""}}]}}]}`,
+	} {
+		acc.Add(chunk(t, data))
+	}
+
+	calls := acc.ToolCalls()
+	if len(calls) != 1 || calls[0].Function.Arguments != "{}" || calls[0].Type != "function" {
+		t.Errorf("expected fragments without index or ID to continue the last call, got %+v", calls)
+	}
+}
+
+type fakeReader struct {
+	chunks []api.ChatCompletionChunk
+	err    error
+	closed bool
+}
+
+func (r *fakeReader) Recv() (api.ChatCompletionChunk, error) {
+	if len(r.chunks) == 0 {
+		return api.ChatCompletionChunk{}, r.err
+	}
+	c := r.chunks[0]
+	r.chunks = r.chunks[1:]
+	return c, nil
+}
+
+func (r *fakeReader) Close() error {
+	r.closed = true
+	return nil
+}
+
+func TestStream_Events(t *testing.T) {
+	reader := &fakeReader{
+		chunks: []api.ChatCompletionChunk{
+			chunk(t, `{"choices":[{"delta":{"content":"Hello"}}]}`),
+			chunk(t, `{"choices":[{"delta":{"content":" world"}}]}`),
+		},
+		err: io.EOF,
+	}
+	stream := NewStream(context.Background(), reader, 0)
+
+	var kinds []StreamEventKind
+	var last StreamEventMsg
+	for ev := range stream.Events() {
+		if ev.Stream != stream {
+			t.Error("events should reference their stream")
+		}
+		kinds = append(kinds, ev.Kind)
+		last = ev
+	}
+
+	want := []StreamEventKind{StreamDelta, StreamDelta, StreamDone}
+	if len(kinds) != len(want) {
+		t.Fatalf("expected events %v, got %v", want, kinds)
+	}
+	if last.Content != "Hello world" {
+		t.Errorf("expected the full text on completion, got %q", last.Content)
+	}
+	if !reader.closed {
+		t.Error("expected the reader to be closed")
+	}
+
+	// Next yields nil once the stream has ended
+	if msg := stream.Next()(); msg != nil {
+		t.Errorf("expected nil after the end of the stream, got %T", msg)
+	}
+}
+
+func TestStream_Failure(t *testing.T) {
+	boom := errors.New("connection reset")
+	reader := &fakeReader{
+		chunks: []api.ChatCompletionChunk{chunk(t, `{"choices":[{"delta":{"content":"partial"}}]}`)},
+		err:    boom,
+	}
+	stream := NewStream(context.Background(), reader, 0)
+
+	var last StreamEventMsg
+	for ev := range stream.Events() {
+		last = ev
+	}
+	if last.Kind != StreamFailed || !errors.Is(last.Err, boom) || last.Content != "partial" {
+		t.Errorf("unexpected final event %+v", last)
+	}
+}
+
+// gatedReader sends its chunks one at a time, each once a value arrives on gate
+type gatedReader struct {
+	chunks []api.ChatCompletionChunk
+	gate   chan struct{}
+}
+
+func (r *gatedReader) Recv() (api.ChatCompletionChunk, error) {
+	if len(r.chunks) == 0 {
+		return api.ChatCompletionChunk{}, io.EOF
+	}
+	<-r.gate
+	c := r.chunks[0]
+	r.chunks = r.chunks[1:]
+	return c, nil
+}
+
+func (r *gatedReader) Close() error {
+	return nil
+}
+
+func TestStream_Stalled(t *testing.T) {
+	reader := &gatedReader{
+		chunks: []api.ChatCompletionChunk{
+			chunk(t, `{"choices":[{"delta":{"content":"Hello"}}]}`),
+			chunk(t, `{"choices":[{"delta":{"content":" world"}}]}`),
+		},
+		gate: make(chan struct{}),
+	}
+	stream := NewStream(context.Background(), reader, 20*time.Millisecond)
+	defer stream.Close()
+
+	next := func() StreamEventMsg {
+		t.Helper()
+		select {
+		case ev := <-stream.Events():
+			return ev
+		case <-time.After(2 * time.Second):
+			t.Fatal("timed out waiting for a stream event")
+			return StreamEventMsg{}
+		}
+	}
+
+	// After the first chunk nothing arrives, so the stream reports a stall
+	reader.gate <- struct{}{}
+	if ev := next(); ev.Kind != StreamDelta {
+		t.Fatalf("expected a delta first, got %v", ev.Kind)
+	}
+	if ev := next(); ev.Kind != StreamStalled || ev.Content != "Hello" {
+		t.Fatalf("expected a stall with the text so far, got %+v", ev)
+	}
+
+	// Data flowing again rearms the detection
+	reader.gate <- struct{}{}
+	if ev := next(); ev.Kind != StreamDelta || ev.Content != " world" {
+		t.Fatalf("expected the stream to resume, got %+v", ev)
+	}
+	if ev := next(); ev.Kind != StreamDone || ev.Content != "Hello world" {
+		t.Fatalf("expected the stream to finish, got %+v", ev)
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// SuggestionPriorities are the priority labels of edit suggestions, highest first
+var SuggestionPriorities = []string{"high", "medium", "low"}
+
+var (
+	// suggestionLine matches "SUGGESTION | priority | file | description"
+	suggestionLine = regexp.MustCompile(`(?i)^\s*(?:[-*•]\s*)?\**SUGGESTION\**\s*\|\s*(\w+)\s*\|\s*([^|]+?)\s*\|\s*(.+?)\s*$`)
+	// suggestionBullet matches the older "• **file** - description" format
+	suggestionBullet = regexp.MustCompile(`^\s*[-*•]\s*\*\*([^*\s]+)\*\*\s*[-–—:]\s*(.+?)\s*$`)
+)
+
+// EditSuggestion is one file edit suggested by /suggest
+type EditSuggestion struct {
+	File        string
+	Description string
+	Priority    string // One of SuggestionPriorities
+}
+
+// EditSuggestionsMsg carries the edits suggested from the conversation
+type EditSuggestionsMsg struct {
+	Suggestions []EditSuggestion
+	Response    string // The reply they were parsed from
+	Err         error
+}
+
+// ParseEditSuggestions extracts the edit suggestions from a reply, highest
+// priority first. Unknown priorities are reported as medium.
+func ParseEditSuggestions(reply string) []EditSuggestion {
+	var suggestions []EditSuggestion
+	for _, line := range strings.Split(reply, "\n") {
+		var suggestion EditSuggestion
+		if match := suggestionLine.FindStringSubmatch(line); match != nil {
+			suggestion = EditSuggestion{
+				Priority:    strings.ToLower(match[1]),
+				File:        strings.Trim(match[2], "`*"),
+				Description: match[3],
+			}
+		} else if match := suggestionBullet.FindStringSubmatch(line); match != nil {
+			suggestion = EditSuggestion{File: strings.Trim(match[1], "`"), Description: match[2]}
+		} else {
+			continue
+		}
+		if !slices.Contains(SuggestionPriorities, suggestion.Priority) {
+			suggestion.Priority = "medium"
+		}
+		suggestions = append(suggestions, suggestion)
+	}
+
+	rank := func(priority string) int { return slices.Index(SuggestionPriorities, priority) }
+	slices.SortStableFunc(suggestions, func(a, b EditSuggestion) int {
+		return rank(a.Priority) - rank(b.Priority)
+	})
+	return suggestions
+}
+
+// FormatEditSuggestions lists suggestions numbered for /suggest open, prompt
+// and fix
+func FormatEditSuggestions(suggestions []EditSuggestion) string {
+	var list strings.Builder
+	list.WriteString(fmt.Sprintf("📝 %d edit suggestion(s):\n", len(suggestions)))
+	for i, suggestion := range suggestions {
+		list.WriteString(fmt.Sprintf("%2d. [%s] %s - %s\n", i+1, suggestion.Priority, suggestion.File, suggestion.Description))
+	}
+	list.WriteString("\n/suggest open <n> opens the file, /suggest prompt <n> puts it in the input, /suggest fix <n> asks the AI to make the change")
+	return list.String()
+}
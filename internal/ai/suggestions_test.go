@@ -0,0 +1,51 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseEditSuggestions(t *testing.T) {
+	reply := strings.Join([]string{
+		"Based on the conversation:",
+		"SUGGESTION | low | README.md | Document the new flag",
+		"- **SUGGESTION** | High | `internal/a.go` | Handle the ignored error",
+		"SUGGESTION | urgent | b.go | Rename the helper",
+		"• **c.go** - Older bullet format",
+		"No more edits.",
+	}, "\n")
+
+	got := ParseEditSuggestions(reply)
+	want := []EditSuggestion{
+		{Priority: "high", File: "internal/a.go", Description: "Handle the ignored error"},
+		{Priority: "medium", File: "b.go", Description: "Rename the helper"},
+		{Priority: "medium", File: "c.go", Description: "Older bullet format"},
+		{Priority: "low", File: "README.md", Description: "Document the new flag"},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("ParseEditSuggestions() = %+v, want %+v", got, want)
+	}
+
+	if got := ParseEditSuggestions("No specific edits needed based on current conversation"); len(got) != 0 {
+		t.Errorf("expected no suggestions, got %+v", got)
+	}
+
+	list := FormatEditSuggestions(want[:1])
+	if !strings.Contains(list, " 1. [high] internal/a.go - Handle the ignored error") {
+		t.Errorf("FormatEditSuggestions() = %q", list)
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"strings"
+)
+
+// choicesKey is the context key of the number of completions a request asks for
+type choicesKey struct{}
+
+// withChoices asks for n alternative completions in the requests sent with ctx
+func withChoices(ctx context.Context, n int) context.Context {
+	return context.WithValue(ctx, choicesKey{}, n)
+}
+
+// requestedChoices returns the completions asked for with ctx, 0 for the
+// API default of one
+func requestedChoices(ctx context.Context) int {
+	if n, ok := ctx.Value(choicesKey{}).(int); ok && n > 1 {
+		return n
+	}
+	return 0
+}
+
+// SendChatRequestChoices asks for n alternative completions of messages.
+// Models that return fewer choices than asked for, or ignore n, are asked
+// again until there are n. Once some were received, a failed request ends
+// the search with what there is.
+func (client *DeepSeekClient) SendChatRequestChoices(ctx context.Context, messages []Message, n int) ([]string, error) {
+	var choices []string
+	for len(choices) < n {
+		response, err := client.sendChatRequestWithToolsAndRetry(withChoices(ctx, n-len(choices)), messages, nil, "")
+		if err != nil {
+			if len(choices) > 0 {
+				break
+			}
+			return nil, err
+		}
+		received := 0
+		for _, choice := range response.Choices {
+			if strings.TrimSpace(choice.Message.Content) != "" {
+				choices = append(choices, choice.Message.Content)
+				received++
+			}
+		}
+		if received == 0 {
+			if len(choices) > 0 {
+				break
+			}
+			return nil, APIError{
+				Message:     "no response choices received",
+				Retryable:   true,
+				UserMessage: "Empty response received. Please try again.",
+			}
+		}
+	}
+	if len(choices) > n {
+		choices = choices[:n]
+	}
+	return choices, nil
+}
+
+// ChatWithHistoryChoices asks for n alternative answers to userMessage, with
+// the conversation history and code context but without tools, so that the
+// user can keep one
+func (s *Service) ChatWithHistoryChoices(ctx context.Context, conversationHistory []Message, contextPrompt, userMessage string, n int) ([]string, error) {
+	messages := s.chatMessages(conversationHistory, contextPrompt, userMessage)
+	return s.client.SendChatRequestChoices(withWorkflow(ctx, "choices"), messages, n)
+}
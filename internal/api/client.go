@@ -25,6 +25,10 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/antenore/deecli/internal/config"
+	"github.com/antenore/deecli/internal/metrics"
+	"github.com/antenore/deecli/internal/redact"
 )
 
 // DeepSeekClient handles low-level HTTP communication with DeepSeek API
@@ -34,6 +38,8 @@ type DeepSeekClient struct {
 	model       string
 	temperature float64
 	maxTokens   int
+	seed        *int         // Sampling seed, nil for none
+	settingsMu  sync.RWMutex // Guards model, temperature, maxTokens and seed, which a config reload can change
 	httpClient  *http.Client
 	maxRetries  int
 	baseDelay   time.Duration
@@ -44,11 +50,25 @@ type DeepSeekClient struct {
 	transport    *http.Transport
 	ctx          context.Context
 	cancel       context.CancelFunc
+
+	// Secret redaction applied to outgoing messages
+	redactor *redact.Redactor
+
+	// Per-request metrics, nil when not recorded
+	metrics *MetricsLog
+
+	// Anonymous usage statistics for /stats, nil when not kept
+	stats *metrics.Recorder
+
+	// The last chat request that succeeded, for the annotations of responses
+	lastChat   Generation
+	lastChatMu sync.Mutex
 }
 
 // NewDeepSeekClient creates a new DeepSeek API client
 func NewDeepSeekClient(apiKey, model string, temperature float64, maxTokens int) *DeepSeekClient {
     transport := &http.Transport{
+		Proxy:               http.ProxyFromEnvironment, // HTTP_PROXY, HTTPS_PROXY and NO_PROXY
 		MaxIdleConns:        10,               // Maximum idle connections to keep
 		MaxIdleConnsPerHost: 10,               // Maximum idle connections per host
 		IdleConnTimeout:     90 * time.Second, // How long to keep idle connections
@@ -86,6 +106,61 @@ func NewDeepSeekClient(apiKey, model string, temperature float64, maxTokens int)
 	return client
 }
 
+// SetModelSettings changes the model, temperature and max tokens used by later requests
+func (client *DeepSeekClient) SetModelSettings(model string, temperature float64, maxTokens int) {
+	client.settingsMu.Lock()
+	defer client.settingsMu.Unlock()
+	client.model = model
+	client.temperature = temperature
+	client.maxTokens = maxTokens
+}
+
+// modelSettings returns the model, temperature and max tokens for a request,
+// with max tokens limited to what the model accepts
+func (client *DeepSeekClient) modelSettings() (string, float64, int) {
+	client.settingsMu.RLock()
+	defer client.settingsMu.RUnlock()
+	return client.model, client.temperature, config.ClampMaxTokens(client.model, client.maxTokens)
+}
+
+// SetSeed sets the sampling seed sent with later requests to models that
+// accept one, making their output reproducible; nil removes it
+func (client *DeepSeekClient) SetSeed(seed *int) {
+	client.settingsMu.Lock()
+	defer client.settingsMu.Unlock()
+	client.seed = seed
+}
+
+// requestSeed returns the seed to send to model, nil if there is none or the
+// model does not accept it
+func (client *DeepSeekClient) requestSeed(model string) *int {
+	client.settingsMu.RLock()
+	defer client.settingsMu.RUnlock()
+	if !config.LookupModel(model).SupportsSeed {
+		return nil
+	}
+	return client.seed
+}
+
+// SetRedactor sets the redactor used to mask secrets before requests are sent
+func (client *DeepSeekClient) SetRedactor(redactor *redact.Redactor) {
+	client.redactor = redactor
+}
+
+// redactMessages returns a copy of messages with secrets masked
+func (client *DeepSeekClient) redactMessages(messages []Message) []Message {
+	if client.redactor == nil {
+		return messages
+	}
+
+	redacted := make([]Message, len(messages))
+	for i, msg := range messages {
+		msg.Content = client.redactor.Redact(msg.Content)
+		redacted[i] = msg
+	}
+	return redacted
+}
+
 // SendChatRequest sends a chat completion request
 func (client *DeepSeekClient) SendChatRequest(ctx context.Context, messages []Message) (string, error) {
 	return client.sendChatRequestWithRetryContext(ctx, messages, nil)
@@ -102,10 +177,14 @@ func (client *DeepSeekClient) SendChatRequestWithToolsAndChoice(ctx context.Cont
 }
 
 // sendChatRequestWithRetryContext sends a chat request with retry logic and context cancellation
-func (client *DeepSeekClient) sendChatRequestWithRetryContext(ctx context.Context, messages []Message, tools []Tool) (string, error) {
+func (client *DeepSeekClient) sendChatRequestWithRetryContext(ctx context.Context, messages []Message, tools []Tool) (result string, err error) {
 	var lastErr error
+	var stats attemptStats
+	start := time.Now()
+	attempt := 0
+	defer func() { client.recordMetrics(ctx, start, attempt, stats, false, err) }()
 
-	for attempt := 0; attempt <= client.maxRetries; attempt++ {
+	for ; attempt <= client.maxRetries; attempt++ {
 		if attempt > 0 {
 			// Exponential backoff with jitter
 			delay := time.Duration(float64(client.baseDelay) * math.Pow(2, float64(attempt-1)))
@@ -125,7 +204,8 @@ func (client *DeepSeekClient) sendChatRequestWithRetryContext(ctx context.Contex
 			}
 		}
 
-		result, err := client.sendSingleRequestWithContext(ctx, messages, tools)
+		stats = attemptStats{}
+		result, err := client.sendSingleRequestWithContext(ctx, messages, tools, &stats)
 		if err == nil {
 			return result, nil
 		}
@@ -147,10 +227,14 @@ func (client *DeepSeekClient) sendChatRequestWithRetryContext(ctx context.Contex
 }
 
 // sendChatRequestWithToolsAndRetry sends a chat request with tools and returns full response
-func (client *DeepSeekClient) sendChatRequestWithToolsAndRetry(ctx context.Context, messages []Message, tools []Tool, toolChoice string) (*ChatResponse, error) {
+func (client *DeepSeekClient) sendChatRequestWithToolsAndRetry(ctx context.Context, messages []Message, tools []Tool, toolChoice string) (response *ChatResponse, err error) {
 	var lastErr error
+	var stats attemptStats
+	start := time.Now()
+	attempt := 0
+	defer func() { client.recordMetrics(ctx, start, attempt, stats, false, err) }()
 
-	for attempt := 0; attempt <= client.maxRetries; attempt++ {
+	for ; attempt <= client.maxRetries; attempt++ {
 		if attempt > 0 {
 			// Exponential backoff with jitter
 			delay := time.Duration(float64(client.baseDelay) * math.Pow(2, float64(attempt-1)))
@@ -170,7 +254,8 @@ func (client *DeepSeekClient) sendChatRequestWithToolsAndRetry(ctx context.Conte
 			}
 		}
 
-		result, err := client.sendSingleRequestWithToolsAndContext(ctx, messages, tools, toolChoice)
+		stats = attemptStats{}
+		result, err := client.sendSingleRequestWithToolsAndContext(ctx, messages, tools, toolChoice, &stats)
 		if err == nil {
 			return result, nil
 		}
@@ -191,16 +276,19 @@ func (client *DeepSeekClient) sendChatRequestWithToolsAndRetry(ctx context.Conte
 	return nil, fmt.Errorf("failed after %d attempts: %w", client.maxRetries+1, lastErr)
 }
 
-// sendSingleRequestWithToolsAndContext makes a single API request with tools and returns full response
-func (client *DeepSeekClient) sendSingleRequestWithToolsAndContext(ctx context.Context, messages []Message, tools []Tool, toolChoice string) (*ChatResponse, error) {
+// sendSingleRequestWithToolsAndContext makes a single API request with tools and returns full response,
+// and fills stats with what was sent and received
+func (client *DeepSeekClient) sendSingleRequestWithToolsAndContext(ctx context.Context, messages []Message, tools []Tool, toolChoice string, stats *attemptStats) (*ChatResponse, error) {
 	// Update activity timestamp
 	client.updateActivity()
+	model, temperature, maxTokens := client.modelSettings()
+	stats.model = model
 
 	// DeepSeek reasoner model doesn't support temperature parameter
 	request := ChatRequest{
-		Model:     client.model,
-		Messages:  messages,
-		MaxTokens: client.maxTokens,
+		Model:     model,
+		Messages:  client.redactMessages(messages),
+		MaxTokens: maxTokens,
 		Tools:     tools,
 	}
 
@@ -209,10 +297,13 @@ func (client *DeepSeekClient) sendSingleRequestWithToolsAndContext(ctx context.C
 		request.ToolChoice = toolChoice
 	}
 
-	// Only add temperature for non-reasoner models
-	if client.model != "deepseek-reasoner" {
-		request.Temperature = client.temperature
+	// Only add temperature for models that honour it
+	if config.LookupModel(model).SupportsTemperature {
+		request.Temperature = temperature
+		stats.temperature = &temperature
 	}
+	request.Seed = client.requestSeed(model)
+	request.N = requestedChoices(ctx)
 
 	jsonData, err := json.Marshal(request)
 	if err != nil {
@@ -224,6 +315,8 @@ func (client *DeepSeekClient) sendSingleRequestWithToolsAndContext(ctx context.C
 		}
 	}
 
+	stats.requestBytes = len(jsonData)
+
 	req, err := http.NewRequestWithContext(ctx, "POST", client.baseURL+"/chat/completions", bytes.NewBuffer(jsonData))
 	if err != nil {
 		return nil, APIError{
@@ -257,8 +350,10 @@ func (client *DeepSeekClient) sendSingleRequestWithToolsAndContext(ctx context.C
 		}
 	}
 	defer resp.Body.Close()
+	stats.status = resp.StatusCode
 
 	body, err := io.ReadAll(resp.Body)
+	stats.responseBytes = len(body)
 	if err != nil {
 		return nil, APIError{
 			StatusCode:  resp.StatusCode,
@@ -281,27 +376,34 @@ func (client *DeepSeekClient) sendSingleRequestWithToolsAndContext(ctx context.C
 			UserMessage: "Invalid API response format. Please try again.",
 		}
 	}
+	stats.promptTokens = chatResp.Usage.PromptTokens
+	stats.completionTokens = chatResp.Usage.CompletionTokens
 
 	return &chatResp, nil
 }
 
-// sendSingleRequestWithContext makes a single API request with context support for cancellation
-func (client *DeepSeekClient) sendSingleRequestWithContext(ctx context.Context, messages []Message, tools []Tool) (string, error) {
+// sendSingleRequestWithContext makes a single API request with context support for cancellation,
+// filling stats with what was sent and received
+func (client *DeepSeekClient) sendSingleRequestWithContext(ctx context.Context, messages []Message, tools []Tool, stats *attemptStats) (string, error) {
 	// Update activity timestamp
 	client.updateActivity()
+	model, temperature, maxTokens := client.modelSettings()
+	stats.model = model
 
 	// DeepSeek reasoner model doesn't support temperature parameter
 	request := ChatRequest{
-		Model:     client.model,
-		Messages:  messages,
-		MaxTokens: client.maxTokens,
+		Model:     model,
+		Messages:  client.redactMessages(messages),
+		MaxTokens: maxTokens,
 		Tools:     tools,
 	}
 
-	// Only add temperature for non-reasoner models
-	if client.model != "deepseek-reasoner" {
-		request.Temperature = client.temperature
+	// Only add temperature for models that honour it
+	if config.LookupModel(model).SupportsTemperature {
+		request.Temperature = temperature
+		stats.temperature = &temperature
 	}
+	request.Seed = client.requestSeed(model)
 
 	jsonData, err := json.Marshal(request)
 	if err != nil {
@@ -313,6 +415,8 @@ func (client *DeepSeekClient) sendSingleRequestWithContext(ctx context.Context,
 		}
 	}
 
+	stats.requestBytes = len(jsonData)
+
 	req, err := http.NewRequestWithContext(ctx, "POST", client.baseURL+"/chat/completions", bytes.NewBuffer(jsonData))
 	if err != nil {
 		return "", APIError{
@@ -346,8 +450,10 @@ func (client *DeepSeekClient) sendSingleRequestWithContext(ctx context.Context,
 		}
 	}
 	defer resp.Body.Close()
+	stats.status = resp.StatusCode
 
 	body, err := io.ReadAll(resp.Body)
+	stats.responseBytes = len(body)
 	if err != nil {
 		return "", APIError{
 			StatusCode:  resp.StatusCode,
@@ -370,6 +476,8 @@ func (client *DeepSeekClient) sendSingleRequestWithContext(ctx context.Context,
 			UserMessage: "Error parsing response. Retrying...",
 		}
 	}
+	stats.promptTokens = response.Usage.PromptTokens
+	stats.completionTokens = response.Usage.CompletionTokens
 
 	if len(response.Choices) == 0 {
 		return "", APIError{
@@ -442,17 +550,24 @@ func (client *DeepSeekClient) WarmUp() error {
 	}
 
 	// Store original values
+	client.settingsMu.Lock()
 	origMaxTokens := client.maxTokens
 	client.maxTokens = 1 // Minimal response
+	client.settingsMu.Unlock()
 
 	// Perform warm-up request
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	_, err := client.sendSingleRequestWithContext(ctx, warmupMsg, nil)
+	var stats attemptStats
+	start := time.Now()
+	_, err := client.sendSingleRequestWithContext(ctx, warmupMsg, nil, &stats)
+	client.recordMetrics(withWorkflow(ctx, "warmup"), start, 0, stats, false, err)
 
 	// Restore original values
+	client.settingsMu.Lock()
 	client.maxTokens = origMaxTokens
+	client.settingsMu.Unlock()
 
 	if err != nil {
 		// Don't fail if warm-up fails, just log it
@@ -462,6 +577,45 @@ func (client *DeepSeekClient) WarmUp() error {
 	return nil
 }
 
+// VerifyResult describes a successful verification request
+type VerifyResult struct {
+	Model   string        // Model that answered, as reported by the API
+	Latency time.Duration // Round trip of the request
+}
+
+// Verify sends a 1-token request to check the API key and model, reporting
+// which model answered and how long the round trip took
+func (client *DeepSeekClient) Verify(ctx context.Context) (VerifyResult, error) {
+	verifyMsg := []Message{
+		{Role: "user", Content: "ping"},
+	}
+
+	client.settingsMu.Lock()
+	origMaxTokens := client.maxTokens
+	client.maxTokens = 1 // Minimal response
+	client.settingsMu.Unlock()
+
+	var stats attemptStats
+	start := time.Now()
+	response, err := client.sendSingleRequestWithToolsAndContext(ctx, verifyMsg, nil, "", &stats)
+	latency := time.Since(start)
+	client.recordMetrics(withWorkflow(ctx, "verify"), start, 0, stats, false, err)
+
+	client.settingsMu.Lock()
+	client.maxTokens = origMaxTokens
+	client.settingsMu.Unlock()
+
+	if err != nil {
+		return VerifyResult{}, err
+	}
+
+	result := VerifyResult{Model: response.Model, Latency: latency}
+	if result.Model == "" {
+		result.Model, _, _ = client.modelSettings()
+	}
+	return result, nil
+}
+
 // manageConnection monitors activity and closes idle connections
 func (client *DeepSeekClient) manageConnection() {
 	ticker := time.NewTicker(30 * time.Second) // Check every 30 seconds
@@ -509,6 +663,24 @@ type deepSeekStreamReader struct {
 	reader  *bufio.Reader
 	resp    *http.Response
 	ctx     context.Context
+
+	// Metrics of the request, recorded once when the stream ends
+	client   *DeepSeekClient
+	start    time.Time
+	stats    attemptStats
+	recorded bool
+}
+
+// finish records the metrics of the stream the first time it is called
+func (s *deepSeekStreamReader) finish(err error) {
+	if s.recorded || s.client == nil {
+		return
+	}
+	s.recorded = true
+	if err == io.EOF {
+		err = nil
+	}
+	s.client.recordMetrics(s.ctx, s.start, 0, s.stats, true, err)
 }
 
 // Recv reads the next chunk from the stream
@@ -517,12 +689,15 @@ func (s *deepSeekStreamReader) Recv() (ChatCompletionChunk, error) {
 		// Check context cancellation
 		select {
 		case <-s.ctx.Done():
+			s.finish(s.ctx.Err())
 			return ChatCompletionChunk{}, s.ctx.Err()
 		default:
 		}
 
 		line, err := s.reader.ReadBytes('\n')
+		s.stats.responseBytes += len(line)
 		if err != nil {
+			s.finish(err)
 			if err == io.EOF {
 				return ChatCompletionChunk{}, io.EOF
 			}
@@ -551,6 +726,7 @@ func (s *deepSeekStreamReader) Recv() (ChatCompletionChunk, error) {
 
 		// Check for stream end
 		if data == "[DONE]" {
+			s.finish(nil)
 			return ChatCompletionChunk{}, io.EOF
 		}
 
@@ -560,6 +736,10 @@ func (s *deepSeekStreamReader) Recv() (ChatCompletionChunk, error) {
 			// Skip malformed chunks
 			continue
 		}
+		if chunk.Usage != nil {
+			s.stats.promptTokens = chunk.Usage.PromptTokens
+			s.stats.completionTokens = chunk.Usage.CompletionTokens
+		}
 
 		return chunk, nil
 	}
@@ -567,6 +747,7 @@ func (s *deepSeekStreamReader) Recv() (ChatCompletionChunk, error) {
 
 // Close closes the stream reader
 func (s *deepSeekStreamReader) Close() error {
+	s.finish(nil)
 	if s.resp != nil && s.resp.Body != nil {
 		return s.resp.Body.Close()
 	}
@@ -577,19 +758,24 @@ func (s *deepSeekStreamReader) Close() error {
 func (client *DeepSeekClient) SendChatRequestStream(ctx context.Context, messages []Message) (StreamReader, error) {
 	// Update activity timestamp
 	client.updateActivity()
+	model, temperature, maxTokens := client.modelSettings()
+	start := time.Now()
+	stats := attemptStats{model: model}
 
 	// Create streaming request
 	request := StreamingChatRequest{
-		Model:     client.model,
-		Messages:  messages,
-		MaxTokens: client.maxTokens,
+		Model:     model,
+		Messages:  client.redactMessages(messages),
+		MaxTokens: maxTokens,
 		Stream:    true,
 	}
 
-	// Only add temperature for non-reasoner models
-	if client.model != "deepseek-reasoner" {
-		request.Temperature = client.temperature
+	// Only add temperature for models that honour it
+	if config.LookupModel(model).SupportsTemperature {
+		request.Temperature = temperature
+		stats.temperature = &temperature
 	}
+	request.Seed = client.requestSeed(model)
 
 	jsonData, err := json.Marshal(request)
 	if err != nil {
@@ -601,6 +787,8 @@ func (client *DeepSeekClient) SendChatRequestStream(ctx context.Context, message
 		}
 	}
 
+	stats.requestBytes = len(jsonData)
+
 	req, err := http.NewRequestWithContext(ctx, "POST", client.baseURL+"/chat/completions", bytes.NewBuffer(jsonData))
 	if err != nil {
 		return nil, APIError{
@@ -621,25 +809,33 @@ func (client *DeepSeekClient) SendChatRequestStream(ctx context.Context, message
 	if err != nil {
 		// Check if context was cancelled
 		if ctx.Err() == context.Canceled {
-			return nil, APIError{
+			apiErr := APIError{
 				StatusCode:  0,
 				Message:     "request cancelled by user",
 				Retryable:   false,
 				UserMessage: "Request cancelled",
 			}
+			client.recordMetrics(ctx, start, 0, stats, true, apiErr)
+			return nil, apiErr
 		}
-		return nil, APIError{
+		apiErr := APIError{
 			StatusCode:  0,
 			Message:     fmt.Sprintf("request failed: %v", err),
 			Retryable:   true,
 			UserMessage: "Network error. Please try again.",
 		}
+		client.recordMetrics(ctx, start, 0, stats, true, apiErr)
+		return nil, apiErr
 	}
+	stats.status = resp.StatusCode
 
 	if resp.StatusCode != http.StatusOK {
 		defer resp.Body.Close()
 		body, _ := io.ReadAll(resp.Body)
-		return nil, client.handleHTTPError(resp.StatusCode, body)
+		stats.responseBytes = len(body)
+		apiErr := client.handleHTTPError(resp.StatusCode, body)
+		client.recordMetrics(ctx, start, 0, stats, true, apiErr)
+		return nil, apiErr
 	}
 
 	// Create stream reader
@@ -647,6 +843,9 @@ func (client *DeepSeekClient) SendChatRequestStream(ctx context.Context, message
 		reader: bufio.NewReader(resp.Body),
 		resp:   resp,
 		ctx:    ctx,
+		client: client,
+		start:  start,
+		stats:  stats,
 	}
 
 	return reader, nil
@@ -661,12 +860,15 @@ func (client *DeepSeekClient) SendChatRequestStreamWithTools(ctx context.Context
 func (client *DeepSeekClient) SendChatRequestStreamWithToolsAndChoice(ctx context.Context, messages []Message, tools []Tool, toolChoice string) (StreamReader, error) {
 	// Update activity timestamp
 	client.updateActivity()
+	model, temperature, maxTokens := client.modelSettings()
+	start := time.Now()
+	stats := attemptStats{model: model}
 
 	// Create streaming request
 	request := StreamingChatRequest{
-		Model:     client.model,
-		Messages:  messages,
-		MaxTokens: client.maxTokens,
+		Model:     model,
+		Messages:  client.redactMessages(messages),
+		MaxTokens: maxTokens,
 		Stream:    true,
 		Tools:     tools,
 	}
@@ -676,10 +878,12 @@ func (client *DeepSeekClient) SendChatRequestStreamWithToolsAndChoice(ctx contex
 		request.ToolChoice = toolChoice
 	}
 
-	// Only add temperature for non-reasoner models
-	if client.model != "deepseek-reasoner" {
-		request.Temperature = client.temperature
+	// Only add temperature for models that honour it
+	if config.LookupModel(model).SupportsTemperature {
+		request.Temperature = temperature
+		stats.temperature = &temperature
 	}
+	request.Seed = client.requestSeed(model)
 
 	jsonData, err := json.Marshal(request)
 	if err != nil {
@@ -691,6 +895,8 @@ func (client *DeepSeekClient) SendChatRequestStreamWithToolsAndChoice(ctx contex
 		}
 	}
 
+	stats.requestBytes = len(jsonData)
+
 	req, err := http.NewRequestWithContext(ctx, "POST", client.baseURL+"/chat/completions", bytes.NewBuffer(jsonData))
 	if err != nil {
 		return nil, APIError{
@@ -711,25 +917,33 @@ func (client *DeepSeekClient) SendChatRequestStreamWithToolsAndChoice(ctx contex
 	if err != nil {
 		// Check if context was cancelled
 		if ctx.Err() == context.Canceled {
-			return nil, APIError{
+			apiErr := APIError{
 				StatusCode:  0,
 				Message:     "request cancelled by user",
 				Retryable:   false,
 				UserMessage: "Request cancelled",
 			}
+			client.recordMetrics(ctx, start, 0, stats, true, apiErr)
+			return nil, apiErr
 		}
-		return nil, APIError{
+		apiErr := APIError{
 			StatusCode:  0,
 			Message:     fmt.Sprintf("request failed: %v", err),
 			Retryable:   true,
 			UserMessage: "Network error. Please try again.",
 		}
+		client.recordMetrics(ctx, start, 0, stats, true, apiErr)
+		return nil, apiErr
 	}
+	stats.status = resp.StatusCode
 
 	if resp.StatusCode != http.StatusOK {
 		defer resp.Body.Close()
 		body, _ := io.ReadAll(resp.Body)
-		return nil, client.handleHTTPError(resp.StatusCode, body)
+		stats.responseBytes = len(body)
+		apiErr := client.handleHTTPError(resp.StatusCode, body)
+		client.recordMetrics(ctx, start, 0, stats, true, apiErr)
+		return nil, apiErr
 	}
 
 	// Create stream reader
@@ -737,6 +951,9 @@ func (client *DeepSeekClient) SendChatRequestStreamWithToolsAndChoice(ctx contex
 		reader: bufio.NewReader(resp.Body),
 		resp:   resp,
 		ctx:    ctx,
+		client: client,
+		start:  start,
+		stats:  stats,
 	}
 
 	return reader, nil
@@ -0,0 +1,149 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestVerify checks that Verify sends a 1-token request and reports the
+// answering model, and that a rejected key surfaces as a 401 APIError
+func TestVerify(t *testing.T) {
+	var maxTokens int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer good-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":{"message":"Authentication Fails"}}`))
+			return
+		}
+		var request ChatRequest
+		json.NewDecoder(r.Body).Decode(&request)
+		maxTokens = request.MaxTokens
+		w.Write([]byte(`{"model":"deepseek-chat","choices":[{"message":{"role":"assistant","content":"p"}}]}`))
+	}))
+	defer server.Close()
+
+	client := &DeepSeekClient{
+		apiKey:     "good-key",
+		baseURL:    server.URL,
+		model:      "deepseek-chat",
+		httpClient: &http.Client{Timeout: 5 * time.Second},
+		maxTokens:  2048,
+	}
+
+	result, err := client.Verify(context.Background())
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if result.Model != "deepseek-chat" {
+		t.Errorf("Expected model deepseek-chat, got %q", result.Model)
+	}
+	if maxTokens != 1 {
+		t.Errorf("Expected a 1-token request, got max_tokens %d", maxTokens)
+	}
+	if client.maxTokens != 2048 {
+		t.Errorf("Expected max tokens to be restored, got %d", client.maxTokens)
+	}
+
+	client.apiKey = "bad-key"
+	_, err = client.Verify(context.Background())
+	var apiErr APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected a 401 APIError, got %v", err)
+	}
+}
+
+// TestSeed checks that the seed is sent only when set and accepted by the model
+func TestSeed(t *testing.T) {
+	var seeds []*int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request ChatRequest
+		json.NewDecoder(r.Body).Decode(&request)
+		seeds = append(seeds, request.Seed)
+		w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"ok"}}]}`))
+	}))
+	defer server.Close()
+
+	client := &DeepSeekClient{
+		apiKey:     "key",
+		baseURL:    server.URL,
+		model:      "deepseek-chat",
+		httpClient: &http.Client{Timeout: 5 * time.Second},
+		maxTokens:  256,
+	}
+	messages := []Message{{Role: "user", Content: "hi"}}
+
+	client.SendChatRequest(context.Background(), messages)
+	seed := 42
+	client.SetSeed(&seed)
+	client.SendChatRequest(context.Background(), messages)
+	client.SetModelSettings("deepseek-reasoner", 0, 256)
+	client.SendChatRequest(context.Background(), messages)
+
+	if len(seeds) != 3 {
+		t.Fatalf("Expected 3 requests, got %d", len(seeds))
+	}
+	if seeds[0] != nil {
+		t.Errorf("Expected no seed before SetSeed, got %d", *seeds[0])
+	}
+	if seeds[1] == nil || *seeds[1] != 42 {
+		t.Errorf("Expected seed 42, got %v", seeds[1])
+	}
+	if seeds[2] != nil {
+		t.Errorf("Expected no seed for a model without seed support, got %d", *seeds[2])
+	}
+}
+
+// TestSendChatRequestChoices checks that n is sent and that missing choices
+// are asked for again
+func TestSendChatRequestChoices(t *testing.T) {
+	var asked []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request ChatRequest
+		json.NewDecoder(r.Body).Decode(&request)
+		asked = append(asked, request.N)
+		if len(asked) == 1 {
+			w.Write([]byte(`{"choices":[{"message":{"content":"one"}},{"message":{"content":"two"}}]}`))
+			return
+		}
+		w.Write([]byte(`{"choices":[{"message":{"content":"three"}}]}`))
+	}))
+	defer server.Close()
+
+	client := &DeepSeekClient{
+		apiKey:     "key",
+		baseURL:    server.URL,
+		model:      "deepseek-chat",
+		httpClient: &http.Client{Timeout: 5 * time.Second},
+		maxTokens:  256,
+	}
+	choices, err := client.SendChatRequestChoices(context.Background(), []Message{{Role: "user", Content: "ideas"}}, 3)
+	if err != nil {
+		t.Fatalf("SendChatRequestChoices failed: %v", err)
+	}
+	if strings.Join(choices, ",") != "one,two,three" {
+		t.Errorf("Expected the choices of both requests, got %q", choices)
+	}
+	// A single missing choice is asked for without n
+	if len(asked) != 2 || asked[0] != 3 || asked[1] != 0 {
+		t.Errorf("Expected n of 3 then none, got %v", asked)
+	}
+}
@@ -0,0 +1,202 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/antenore/deecli/internal/debug"
+	"github.com/antenore/deecli/internal/metrics"
+)
+
+// DefaultMetricsPath is the project file request metrics are written to
+var DefaultMetricsPath = filepath.Join(".deecli", "requests.jsonl")
+
+// RequestMetrics describes one API request, retries included
+type RequestMetrics struct {
+	Time             time.Time `json:"time"`
+	Workflow         string    `json:"workflow"` // Feature that sent the request, such as chat or review
+	Model            string    `json:"model"`
+	Stream           bool      `json:"stream,omitempty"`
+	RequestBytes     int       `json:"request_bytes"`  // JSON payload of the last attempt
+	ResponseBytes    int       `json:"response_bytes"` // Body, or streamed data, of the last attempt
+	LatencyMs        int64     `json:"latency_ms"`     // From the first attempt to the end of the response
+	Retries          int       `json:"retries"`
+	Status           int       `json:"status"` // HTTP status of the last attempt, 0 if none was received
+	PromptTokens     int       `json:"prompt_tokens,omitempty"`
+	CompletionTokens int       `json:"completion_tokens,omitempty"`
+	Error            string    `json:"error,omitempty"`
+}
+
+// Generation describes the request that produced a response: the model,
+// the temperature and the tokens used
+type Generation struct {
+	Model            string
+	Temperature      *float64 // nil when the model ignores the temperature
+	PromptTokens     int
+	CompletionTokens int
+}
+
+// String describes the generation in one line, e.g.
+// "deepseek-chat · temperature 0.1 · 1200 → 350 tokens"
+func (g Generation) String() string {
+	parts := []string{g.Model}
+	if g.Temperature != nil {
+		parts = append(parts, "temperature "+strconv.FormatFloat(*g.Temperature, 'f', -1, 64))
+	}
+	if g.PromptTokens > 0 || g.CompletionTokens > 0 {
+		parts = append(parts, fmt.Sprintf("%d → %d tokens", g.PromptTokens, g.CompletionTokens))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// MetricsLog appends request metrics to a JSON Lines file
+type MetricsLog struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewMetricsLog creates a log writing to path; the file is created on first use
+func NewMetricsLog(path string) *MetricsLog {
+	return &MetricsLog{path: path}
+}
+
+// Path returns the file the log writes to
+func (l *MetricsLog) Path() string {
+	return l.path
+}
+
+// Record appends an entry to the log
+func (l *MetricsLog) Record(metrics RequestMetrics) error {
+	if l == nil {
+		return nil
+	}
+	line, err := json.Marshal(metrics)
+	if err != nil {
+		return fmt.Errorf("failed to encode request metrics: %w", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(l.path), 0755); err != nil {
+		return fmt.Errorf("failed to create metrics directory: %w", err)
+	}
+	file, err := os.OpenFile(l.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open metrics log: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write metrics log: %w", err)
+	}
+	return nil
+}
+
+// workflowKey is the context key of the workflow a request belongs to
+type workflowKey struct{}
+
+// withWorkflow labels the requests sent with ctx as part of workflow
+func withWorkflow(ctx context.Context, workflow string) context.Context {
+	return context.WithValue(ctx, workflowKey{}, workflow)
+}
+
+// workflowOf returns the workflow ctx was labelled with, chat by default
+func workflowOf(ctx context.Context) string {
+	if workflow, ok := ctx.Value(workflowKey{}).(string); ok {
+		return workflow
+	}
+	return "chat"
+}
+
+// attemptStats collects what a single request attempt sent and received
+type attemptStats struct {
+	model            string
+	temperature      *float64 // nil when none was sent
+	requestBytes     int
+	responseBytes    int
+	status           int
+	promptTokens     int
+	completionTokens int
+}
+
+// SetMetricsLog sets the log request metrics are written to; nil disables it
+func (client *DeepSeekClient) SetMetricsLog(log *MetricsLog) {
+	client.metrics = log
+}
+
+// SetUsageStats sets where request counts and latencies are kept for
+// /stats; nil disables it
+func (client *DeepSeekClient) SetUsageStats(stats *metrics.Recorder) {
+	client.stats = stats
+}
+
+// LastGeneration describes the last chat request that succeeded; the
+// Model is empty before the first one
+func (client *DeepSeekClient) LastGeneration() Generation {
+	client.lastChatMu.Lock()
+	defer client.lastChatMu.Unlock()
+	return client.lastChat
+}
+
+// recordMetrics writes the metrics of a finished request to the debug output
+// and, when set, to the metrics log
+func (client *DeepSeekClient) recordMetrics(ctx context.Context, start time.Time, retries int, stats attemptStats, stream bool, err error) {
+	metrics := RequestMetrics{
+		Time:             start,
+		Workflow:         workflowOf(ctx),
+		Model:            stats.model,
+		Stream:           stream,
+		RequestBytes:     stats.requestBytes,
+		ResponseBytes:    stats.responseBytes,
+		LatencyMs:        time.Since(start).Milliseconds(),
+		Retries:          retries,
+		Status:           stats.status,
+		PromptTokens:     stats.promptTokens,
+		CompletionTokens: stats.completionTokens,
+	}
+	if err != nil {
+		metrics.Error = err.Error()
+	}
+
+	debug.Printf("[DEBUG] API %s request: %d bytes sent, %d received, %dms, %d retries, status %d\n",
+		metrics.Workflow, metrics.RequestBytes, metrics.ResponseBytes, metrics.LatencyMs, metrics.Retries, metrics.Status)
+	if recordErr := client.metrics.Record(metrics); recordErr != nil {
+		debug.Printf("[DEBUG] Failed to record request metrics: %v\n", recordErr)
+	}
+	if err == nil && metrics.Workflow == "chat" {
+		client.lastChatMu.Lock()
+		client.lastChat = Generation{
+			Model:            stats.model,
+			Temperature:      stats.temperature,
+			PromptTokens:     stats.promptTokens,
+			CompletionTokens: stats.completionTokens,
+		}
+		client.lastChatMu.Unlock()
+	}
+	// A request the user cancelled says nothing about how long requests take
+	if !errors.Is(err, context.Canceled) {
+		client.stats.Request(metrics.Workflow, time.Since(start), err != nil)
+	}
+}
@@ -0,0 +1,176 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// readMetrics returns the entries written to the metrics log at path
+func readMetrics(t *testing.T, path string) []RequestMetrics {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Failed to open metrics log: %v", err)
+	}
+	defer file.Close()
+
+	var entries []RequestMetrics
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry RequestMetrics
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("Invalid metrics line %q: %v", scanner.Text(), err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// TestRequestMetrics checks that a request retried after a server error is
+// recorded once, with its workflow, sizes, retry count, status and usage
+func TestRequestMetrics(t *testing.T) {
+	const body = `{"choices":[{"message":{"role":"assistant","content":"ok"}}],"usage":{"prompt_tokens":12,"completion_tokens":3}}`
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "requests.jsonl")
+	client := &DeepSeekClient{
+		apiKey:     "key",
+		baseURL:    server.URL,
+		model:      "deepseek-chat",
+		httpClient: &http.Client{Timeout: 5 * time.Second},
+		maxTokens:  256,
+		maxRetries: 2,
+		baseDelay:  time.Millisecond,
+	}
+	client.SetMetricsLog(NewMetricsLog(path))
+
+	ctx := withWorkflow(context.Background(), "review")
+	if _, err := client.SendChatRequest(ctx, []Message{{Role: "user", Content: "hi"}}); err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+
+	entries := readMetrics(t, path)
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 metrics entry, got %d", len(entries))
+	}
+	got := entries[0]
+	if got.Workflow != "review" || got.Model != "deepseek-chat" {
+		t.Errorf("Unexpected workflow/model: %q/%q", got.Workflow, got.Model)
+	}
+	if got.Retries != 1 || got.Status != http.StatusOK || got.Error != "" {
+		t.Errorf("Expected 1 retry ending in 200, got %d retries, status %d, error %q", got.Retries, got.Status, got.Error)
+	}
+	if got.RequestBytes == 0 || got.ResponseBytes != len(body) {
+		t.Errorf("Unexpected sizes: %d sent, %d received", got.RequestBytes, got.ResponseBytes)
+	}
+	if got.PromptTokens != 12 || got.CompletionTokens != 3 {
+		t.Errorf("Unexpected usage: %d/%d", got.PromptTokens, got.CompletionTokens)
+	}
+}
+
+// TestRequestMetricsStream checks that a stream is recorded once it ends
+func TestRequestMetricsStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte("data: {\"choices\":[{\"delta\":{\"content\":\"hi\"}}]}\n\ndata: [DONE]\n\n"))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "requests.jsonl")
+	client := &DeepSeekClient{
+		apiKey:     "key",
+		baseURL:    server.URL,
+		model:      "deepseek-chat",
+		httpClient: &http.Client{Timeout: 5 * time.Second},
+		maxTokens:  256,
+	}
+	client.SetMetricsLog(NewMetricsLog(path))
+
+	stream, err := client.SendChatRequestStream(context.Background(), []Message{{Role: "user", Content: "hi"}})
+	if err != nil {
+		t.Fatalf("Stream failed: %v", err)
+	}
+	for {
+		if _, err := stream.Recv(); err != nil {
+			break
+		}
+	}
+	stream.Close()
+
+	entries := readMetrics(t, path)
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 metrics entry, got %d", len(entries))
+	}
+	if !entries[0].Stream || entries[0].Workflow != "chat" || entries[0].ResponseBytes == 0 {
+		t.Errorf("Unexpected stream metrics: %+v", entries[0])
+	}
+}
+
+// TestLastGeneration checks that only chat requests describe the latest response
+func TestLastGeneration(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"ok"}}],"usage":{"prompt_tokens":12,"completion_tokens":3}}`))
+	}))
+	defer server.Close()
+
+	client := &DeepSeekClient{
+		apiKey:      "key",
+		baseURL:     server.URL,
+		model:       "deepseek-chat",
+		temperature: 0.7,
+		httpClient:  &http.Client{Timeout: 5 * time.Second},
+		maxTokens:   256,
+	}
+	messages := []Message{{Role: "user", Content: "hi"}}
+
+	if _, err := client.SendChatRequest(withWorkflow(context.Background(), "title"), messages); err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	if gen := client.LastGeneration(); gen.Model != "" {
+		t.Errorf("A background request was taken for a chat response: %+v", gen)
+	}
+
+	if _, err := client.SendChatRequest(context.Background(), messages); err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	gen := client.LastGeneration()
+	if gen.String() != "deepseek-chat · temperature 0.7 · 12 → 3 tokens" {
+		t.Errorf("Unexpected generation: %q", gen.String())
+	}
+
+	// The reasoner ignores the temperature, so none is noted
+	client.SetModelSettings("deepseek-reasoner", 0.7, 256)
+	client.SendChatRequest(context.Background(), messages)
+	if gen := client.LastGeneration(); gen.Model != "deepseek-reasoner" || gen.Temperature != nil {
+		t.Errorf("Unexpected reasoner generation: %+v", gen)
+	}
+}
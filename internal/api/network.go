@@ -0,0 +1,109 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/antenore/deecli/internal/config"
+)
+
+// NetworkOptions configures how the client reaches the API, for networks
+// behind a proxy or a TLS-intercepting gateway
+type NetworkOptions struct {
+	Proxy              string // Proxy URL; empty uses HTTP_PROXY, HTTPS_PROXY and NO_PROXY
+	CABundle           string // PEM file with certificate authorities trusted in addition to the system ones
+	InsecureSkipVerify bool   // Skip TLS certificate verification
+}
+
+// NetworkOptionsFromConfig returns the proxy and TLS options of the configuration
+func NetworkOptionsFromConfig(configManager *config.Manager) NetworkOptions {
+	return NetworkOptions{
+		Proxy:              configManager.GetProxy(),
+		CABundle:           configManager.GetCABundle(),
+		InsecureSkipVerify: configManager.GetInsecureSkipVerify(),
+	}
+}
+
+// SetNetwork applies the proxy and TLS options to the client's transport.
+// It must be called before the first request.
+func (client *DeepSeekClient) SetNetwork(opts NetworkOptions) error {
+	if err := opts.apply(client.transport); err != nil {
+		return err
+	}
+	client.transport.CloseIdleConnections()
+	return nil
+}
+
+// HTTPClient returns a client for requests other than API calls, such as
+// release downloads, that goes through the same proxy and TLS options
+func (opts NetworkOptions) HTTPClient(timeout time.Duration) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if err := opts.apply(transport); err != nil {
+		return nil, err
+	}
+	return &http.Client{Transport: transport, Timeout: timeout}, nil
+}
+
+// apply sets the proxy and TLS options on transport
+func (opts NetworkOptions) apply(transport *http.Transport) error {
+	proxy := http.ProxyFromEnvironment
+	if opts.Proxy != "" {
+		proxyURL, err := url.Parse(opts.Proxy)
+		if err != nil || proxyURL.Host == "" {
+			return fmt.Errorf("invalid proxy URL: %s", opts.Proxy)
+		}
+		proxy = http.ProxyURL(proxyURL)
+	}
+
+	var tlsConfig *tls.Config
+	if opts.CABundle != "" || opts.InsecureSkipVerify {
+		tlsConfig = &tls.Config{InsecureSkipVerify: opts.InsecureSkipVerify}
+	}
+	if opts.CABundle != "" {
+		pool, err := loadCABundle(opts.CABundle)
+		if err != nil {
+			return err
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	transport.Proxy = proxy
+	transport.TLSClientConfig = tlsConfig
+	return nil
+}
+
+// loadCABundle returns the system certificate pool with the certificates
+// in the PEM file at path added
+func loadCABundle(path string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates found in CA bundle %s", path)
+	}
+	return pool, nil
+}
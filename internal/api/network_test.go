@@ -0,0 +1,107 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestSetNetwork checks that a custom CA bundle or skipping verification
+// lets the client reach a server with an untrusted certificate
+func TestSetNetwork(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"model":"deepseek-chat","choices":[{"message":{"role":"assistant","content":"p"}}]}`))
+	}))
+	defer server.Close()
+
+	bundle := filepath.Join(t.TempDir(), "ca.pem")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(bundle, certPEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	verify := func(opts NetworkOptions) error {
+		client := NewDeepSeekClient("key", "deepseek-chat", 0.1, 100)
+		defer client.Close()
+		client.baseURL = server.URL
+		if err := client.SetNetwork(opts); err != nil {
+			t.Fatalf("SetNetwork(%+v) failed: %v", opts, err)
+		}
+		_, err := client.Verify(context.Background())
+		return err
+	}
+
+	if err := verify(NetworkOptions{}); err == nil {
+		t.Error("Expected an untrusted certificate to be rejected")
+	}
+	if err := verify(NetworkOptions{CABundle: bundle}); err != nil {
+		t.Errorf("Expected the CA bundle to be trusted, got %v", err)
+	}
+	if err := verify(NetworkOptions{InsecureSkipVerify: true}); err != nil {
+		t.Errorf("Expected verification to be skipped, got %v", err)
+	}
+
+	client := NewDeepSeekClient("key", "deepseek-chat", 0.1, 100)
+	defer client.Close()
+	if err := client.SetNetwork(NetworkOptions{Proxy: "not a url"}); err == nil {
+		t.Error("Expected an invalid proxy to be rejected")
+	}
+	if err := client.SetNetwork(NetworkOptions{CABundle: filepath.Join(t.TempDir(), "missing.pem")}); err == nil {
+		t.Error("Expected a missing CA bundle to be rejected")
+	}
+}
+
+// TestNetworkOptions_HTTPClient checks that clients for other requests trust
+// the same CA bundle
+func TestNetworkOptions_HTTPClient(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	bundle := filepath.Join(t.TempDir(), "ca.pem")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(bundle, certPEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		opts    NetworkOptions
+		wantErr bool
+	}{
+		{NetworkOptions{}, true},
+		{NetworkOptions{CABundle: bundle}, false},
+	} {
+		client, err := tt.opts.HTTPClient(5 * time.Second)
+		if err != nil {
+			t.Fatalf("HTTPClient(%+v) failed: %v", tt.opts, err)
+		}
+		resp, err := client.Get(server.URL)
+		if err == nil {
+			resp.Body.Close()
+		}
+		if (err != nil) != tt.wantErr {
+			t.Errorf("GET with %+v: error = %v, wantErr %v", tt.opts, err, tt.wantErr)
+		}
+	}
+
+	if _, err := (NetworkOptions{Proxy: "not a url"}).HTTPClient(time.Second); err == nil {
+		t.Error("Expected an invalid proxy to be rejected")
+	}
+}
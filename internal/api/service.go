@@ -17,14 +17,25 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 
 	"github.com/antenore/deecli/internal/debug"
 	"github.com/antenore/deecli/internal/files"
+	"github.com/antenore/deecli/internal/metrics"
+	"github.com/antenore/deecli/internal/redact"
+	"github.com/antenore/deecli/internal/utils"
 )
 
+// ChatSystemPrompt is the system prompt of plain chat requests
+const ChatSystemPrompt = `You are an expert software engineer and code reviewer.
+You help developers understand, improve, and debug their code.
+Provide clear, actionable advice and explanations.`
+
 // Service provides high-level AI operations using the underlying client
 type Service struct {
-	client *DeepSeekClient
+	client     *DeepSeekClient
+	promptMu   sync.RWMutex
+	modePrompt string // Added to the chat system prompt, set by /mode
 }
 
 // NewService creates a new AI service with the provided client
@@ -32,6 +43,76 @@ func NewService(client *DeepSeekClient) *Service {
 	return &Service{client: client}
 }
 
+// Close cancels the requests in flight and closes idle connections
+func (s *Service) Close() {
+	if s.client != nil {
+		s.client.Close()
+	}
+}
+
+// SetModelSettings changes the model, temperature and max tokens of later requests
+func (s *Service) SetModelSettings(model string, temperature float64, maxTokens int) {
+	s.client.SetModelSettings(model, temperature, maxTokens)
+}
+
+// SetNetwork applies proxy and TLS options; call it before the first request
+func (s *Service) SetNetwork(opts NetworkOptions) error {
+	return s.client.SetNetwork(opts)
+}
+
+// WarmUp opens the connection to the API with a minimal request
+func (s *Service) WarmUp() error {
+	return s.client.WarmUp()
+}
+
+// Verify sends a minimal request to check the API key and model
+func (s *Service) Verify(ctx context.Context) (VerifyResult, error) {
+	return s.client.Verify(ctx)
+}
+
+// SetSeed sets the sampling seed of later requests; nil removes it
+func (s *Service) SetSeed(seed *int) {
+	s.client.SetSeed(seed)
+}
+
+// SetMetricsLog sets the log per-request metrics are written to; nil disables it
+func (s *Service) SetMetricsLog(log *MetricsLog) {
+	s.client.SetMetricsLog(log)
+}
+
+// SetUsageStats sets where request counts and latencies are kept for /stats
+func (s *Service) SetUsageStats(stats *metrics.Recorder) {
+	s.client.SetUsageStats(stats)
+}
+
+// LastGeneration describes the last chat request that succeeded: the model,
+// temperature and tokens behind the latest response
+func (s *Service) LastGeneration() Generation {
+	return s.client.LastGeneration()
+}
+
+// SetModePrompt sets the text added to the chat system prompt; empty removes it
+func (s *Service) SetModePrompt(prompt string) {
+	s.promptMu.Lock()
+	defer s.promptMu.Unlock()
+	s.modePrompt = prompt
+}
+
+// systemPrompt returns base with the mode prompt, if any, appended
+func (s *Service) systemPrompt(base string) string {
+	s.promptMu.RLock()
+	defer s.promptMu.RUnlock()
+	if s.modePrompt == "" {
+		return base
+	}
+	return base + "\n\n" + s.modePrompt
+}
+
+// SetRedactor enables secret redaction for all outgoing requests
+func (s *Service) SetRedactor(redactor *redact.Redactor) {
+	s.client.SetRedactor(redactor)
+}
+
 // ChatAboutCode sends a chat request about code to the AI
 func (s *Service) ChatAboutCode(code, userMessage string) (string, error) {
     messages := []Message{
@@ -67,12 +148,16 @@ func (s *Service) ChatWithHistory(conversationHistory []Message, contextPrompt,
 
 // ChatWithHistoryContext sends a chat request with conversation history and code context, with cancellation support
 func (s *Service) ChatWithHistoryContext(ctx context.Context, conversationHistory []Message, contextPrompt, userMessage string) (string, error) {
+	return s.client.SendChatRequest(ctx, s.chatMessages(conversationHistory, contextPrompt, userMessage))
+}
+
+// chatMessages builds the messages of a chat request without tools: the
+// system prompt, the history, the file context and the user message
+func (s *Service) chatMessages(conversationHistory []Message, contextPrompt, userMessage string) []Message {
 	messages := []Message{
 		{
 			Role: "system",
-			Content: `You are an expert software engineer and code reviewer.
-You help developers understand, improve, and debug their code.
-Provide clear, actionable advice and explanations.`,
+			Content: s.systemPrompt(ChatSystemPrompt),
 		},
 	}
 
@@ -94,7 +179,7 @@ Provide clear, actionable advice and explanations.`,
         })
     }
 
-	return s.client.SendChatRequest(ctx, messages)
+	return messages
 }
 
 // ChatWithHistoryContextAndTools sends a chat request with tools, conversation history and code context
@@ -135,6 +220,10 @@ Tool Usage Guidelines:
    - Read with path: {"path": "internal/api/client.go"}
    - Read lines 10-50: {"path": "main.go", "startLine": 10, "endLine": 50}
 
+3. read_more - Read the next chunk of a large file loaded as a preview
+   - Continue after the preview: {"path": "data/big.log", "offset": 32768}
+   - Use the path and offset given in the [PREVIEW ...] note
+
 CRITICAL RULES:
 - ALWAYS provide arguments in valid JSON format
 - NEVER call a tool without arguments (use {} for no args, not empty/null)
@@ -148,7 +237,7 @@ CRITICAL RULES:
     messages := []Message{
         {
             Role: "system",
-            Content: systemContent,
+            Content: s.systemPrompt(systemContent),
         },
     }
 
@@ -177,8 +266,18 @@ CRITICAL RULES:
 	return s.client.SendChatRequestWithToolsAndChoice(ctx, messages, tools, toolChoice)
 }
 
+// RunPrompt sends prompt with the given system prompt and no history, mode
+// prompt or tools, returning the full response with its token usage
+func (s *Service) RunPrompt(ctx context.Context, systemPrompt, prompt string) (*ChatResponse, error) {
+	messages := []Message{
+		{Role: "system", Content: systemPrompt},
+		{Role: "user", Content: prompt},
+	}
+	return s.client.SendChatRequestWithToolsAndChoice(withWorkflow(ctx, "eval"), messages, nil, "")
+}
+
 // AnalyzeCode analyzes code and provides suggestions
-func (s *Service) AnalyzeCode(code, filename string) (string, error) {
+func (s *Service) AnalyzeCode(ctx context.Context, code, filename string) (string, error) {
 	messages := []Message{
 		{
 			Role: "system",
@@ -195,11 +294,11 @@ func (s *Service) AnalyzeCode(code, filename string) (string, error) {
 		},
 	}
 
-	return s.client.SendChatRequest(context.Background(), messages)
+	return s.client.SendChatRequest(withWorkflow(ctx, "analyze"), messages)
 }
 
 // ImproveCode suggests improvements for the given code
-func (s *Service) ImproveCode(code, filename string) (string, error) {
+func (s *Service) ImproveCode(ctx context.Context, code, filename string) (string, error) {
 	messages := []Message{
 		{
 			Role: "system",
@@ -216,11 +315,11 @@ func (s *Service) ImproveCode(code, filename string) (string, error) {
 		},
 	}
 
-	return s.client.SendChatRequest(context.Background(), messages)
+	return s.client.SendChatRequest(withWorkflow(ctx, "improve"), messages)
 }
 
 // ExplainCode explains what the code does
-func (s *Service) ExplainCode(code, filename string) (string, error) {
+func (s *Service) ExplainCode(ctx context.Context, code, filename string) (string, error) {
 	messages := []Message{
 		{
 			Role: "system",
@@ -237,7 +336,209 @@ func (s *Service) ExplainCode(code, filename string) (string, error) {
 		},
 	}
 
-	return s.client.SendChatRequest(context.Background(), messages)
+	return s.client.SendChatRequest(withWorkflow(ctx, "explain"), messages)
+}
+
+// DocumentPackage asks for the doc comments, examples and README sections a
+// Go package lacks, as unified diffs against the files in sources. problems
+// lists the doc convention problems found in it, if any.
+func (s *Service) DocumentPackage(ctx context.Context, dir, sources, problems string) (string, error) {
+	if problems == "" {
+		problems = "none found"
+	}
+	messages := []Message{
+		{
+			Role: "system",
+			Content: `You are an expert Go technical writer. Document the provided Go package:
+1. A package comment of the form "Package name ...", in a doc.go if the package has one, else in its main file
+2. A doc comment on every exported identifier, starting with its name, saying what it does and why, not how
+3. Example functions only in an example_test.go shown below; if none is shown, skip them
+4. README.md sections, if a README.md is shown, that match the current API
+
+Reply with unified diffs in ` + "```diff" + ` blocks, one per file, with --- and +++ lines naming the files exactly as shown and hunks that apply to their content. Change only comments and documentation, never code. Keep the existing comments that are already right. Explain each change in one line before its diff.`,
+		},
+		{
+			Role:    "user",
+			Content: fmt.Sprintf("Please document the Go package in %s.\n\nDoc convention problems:\n%s\n\n%s", dir, problems, sources),
+		},
+	}
+
+	return s.client.SendChatRequest(withWorkflow(ctx, "docs"), messages)
+}
+
+// GenerateProjectMap summarizes a project overview into a concise project map
+func (s *Service) GenerateProjectMap(ctx context.Context, overview string) (string, error) {
+	messages := []Message{
+		{
+			Role: "system",
+			Content: `You are an expert software engineer onboarding onto a new codebase.
+From the project tree and key files provided, write a concise project map in Markdown with:
+1. Purpose - what the project does in one or two sentences
+2. Tech stack - language, frameworks and key dependencies
+3. Layout - the main directories and packages and what each is responsible for
+4. Entry points - main packages, commands or binaries
+5. Conventions - build, test and coding conventions you can infer
+
+Keep it under 80 lines. It will be included as context in future conversations.`,
+		},
+		{
+			Role:    "user",
+			Content: fmt.Sprintf("Please write a project map for this repository:\n\n%s", overview),
+		},
+	}
+
+	return s.client.SendChatRequest(withWorkflow(ctx, "project-map"), messages)
+}
+
+// ReviewPullRequest reviews the diff of a pull request against its description
+func (s *Service) ReviewPullRequest(ctx context.Context, title, description, diff string) (string, error) {
+	messages := []Message{
+		{
+			Role: "system",
+			Content: `You are an expert code reviewer. Review the pull request diff provided and give:
+1. A short summary of what the change does
+2. Bugs, regressions or missed edge cases, citing file and line
+3. Security or performance concerns
+4. Gaps between the description and the implementation, and missing tests
+5. A verdict: approve, approve with nits, or request changes
+
+Only comment on the changed lines and be specific.`,
+		},
+		{
+			Role:    "user",
+			Content: fmt.Sprintf("Please review this pull request.\n\nTitle: %s\n\nDescription:\n%s\n\n```diff\n%s\n```", title, description, diff),
+		},
+	}
+
+	return s.client.SendChatRequest(withWorkflow(ctx, "review"), messages)
+}
+
+// ReviewDiffChunk reviews one chunk of the diff between two refs. Findings
+// are returned one per line as "FINDING | severity | file:line | description"
+// so the chunks can be aggregated; part and parts number the chunk.
+func (s *Service) ReviewDiffChunk(ctx context.Context, refRange, diff string, part, parts int) (string, error) {
+	messages := []Message{
+		{
+			Role: "system",
+			Content: `You are an expert code reviewer. Review the diff provided and report bugs, regressions, missed edge cases, security and performance problems, and missing tests.
+
+Report each finding on its own line in exactly this format:
+FINDING | <severity> | <file>:<line> | <description>
+
+Severity is one of: critical, major, minor, nit. Use the line number in the new version of the file. Only comment on the changed lines and be specific. If the diff has no problems, reply with NO FINDINGS.`,
+		},
+		{
+			Role:    "user",
+			Content: fmt.Sprintf("Please review part %d of %d of the changes in %s.\n\n```diff\n%s\n```", part, parts, refRange, diff),
+		},
+	}
+
+	return s.client.SendChatRequest(withWorkflow(ctx, "review"), messages)
+}
+
+// GenerateSessionTitle produces a short title summarizing a conversation
+func (s *Service) GenerateSessionTitle(ctx context.Context, conversationHistory []Message) (string, error) {
+	var conversation strings.Builder
+	for _, msg := range conversationHistory {
+		if msg.Role != "user" && msg.Role != "assistant" {
+			continue
+		}
+		// Only the beginning of each message is needed for a title
+		conversation.WriteString(fmt.Sprintf("%s: %s\n", msg.Role, utils.Truncate(msg.Content, 300)))
+	}
+
+	messages := []Message{
+		{
+			Role:    "system",
+			Content: "You write short titles for conversations. Reply with a title of at most 6 words, without quotes or trailing punctuation.",
+		},
+		{
+			Role:    "user",
+			Content: fmt.Sprintf("Write a title for this conversation:\n\n%s", conversation.String()),
+		},
+	}
+
+	title, err := s.client.SendChatRequest(withWorkflow(ctx, "title"), messages)
+	if err != nil {
+		return "", err
+	}
+
+	title = strings.TrimSpace(strings.Split(strings.TrimSpace(title), "\n")[0])
+	title = strings.Trim(title, "\"'`*#. ")
+	return title, nil
+}
+
+// GenerateCommitMessage writes a commit message for a diff, using the
+// conversation that led to it to explain why the change was made. Guidelines,
+// when given, add project rules the message must follow.
+func (s *Service) GenerateCommitMessage(ctx context.Context, conversationHistory []Message, diff, guidelines string) (string, error) {
+	var conversation strings.Builder
+	for _, msg := range conversationHistory {
+		if msg.Role != "user" && msg.Role != "assistant" {
+			continue
+		}
+		conversation.WriteString(fmt.Sprintf("%s: %s\n", msg.Role, msg.Content[:min(len(msg.Content), 1000)]))
+	}
+
+	system := `You write git commit messages. Reply with the message only, no quotes or code fences:
+a subject line of at most 72 characters in the imperative mood, then a blank line and
+a short body explaining what changed and why. Describe the diff; use the conversation
+only to explain the motivation.`
+	if guidelines != "" {
+		system += "\n\n" + guidelines
+	}
+
+	messages := []Message{
+		{
+			Role:    "system",
+			Content: system,
+		},
+		{
+			Role:    "user",
+			Content: fmt.Sprintf("Conversation:\n%s\nDiff:\n```diff\n%s\n```", conversation.String(), diff),
+		},
+	}
+
+	message, err := s.client.SendChatRequest(withWorkflow(ctx, "commit"), messages)
+	if err != nil {
+		return "", err
+	}
+	message = strings.TrimSpace(message)
+	message = strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(message, "```"), "```"))
+	return message, nil
+}
+
+// SummarizeConversation condenses a conversation into notes that let it
+// continue without the original messages
+func (s *Service) SummarizeConversation(ctx context.Context, conversationHistory []Message) (string, error) {
+	var conversation strings.Builder
+	for _, msg := range conversationHistory {
+		content := msg.Content
+		if msg.Role == "tool" {
+			// Tool output is bulky; its gist is enough for a summary
+			content = content[:min(len(content), 2000)]
+		}
+		conversation.WriteString(fmt.Sprintf("%s: %s\n\n", msg.Role, content))
+	}
+
+	messages := []Message{
+		{
+			Role: "system",
+			Content: `You summarize a conversation between a developer and a coding assistant so that it can continue without the original messages.
+Keep the goals, decisions made, open questions, file names, function names and any code or commands that later messages may rely on.
+Drop greetings, repetition and superseded attempts. Reply with the summary only, as concise bullet points.`,
+		},
+		{
+			Role:    "user",
+			Content: fmt.Sprintf("Summarize this conversation:\n\n%s", conversation.String()),
+		},
+	}
+
+	summary, err := s.client.SendChatRequest(withWorkflow(ctx, "summary"), messages)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(summary), nil
 }
 
 // GenerateEditSuggestions analyzes conversation context and suggests which files to edit
@@ -249,8 +550,9 @@ func (s *Service) GenerateEditSuggestions(ctx context.Context, conversationHisto
 		contextBuilder.WriteString(fmt.Sprintf("File: %s (%s)\n", file.RelPath, file.Language))
 		contextBuilder.WriteString(fmt.Sprintf("Size: %d bytes\n", file.Size))
 		contextBuilder.WriteString("Content preview:\n")
-		contextBuilder.WriteString(file.Content[:min(len(file.Content), 500)])
-		if len(file.Content) > 500 {
+		content, _ := fileContext.Content(file)
+		contextBuilder.WriteString(content[:min(len(content), 500)])
+		if len(content) > 500 {
 			contextBuilder.WriteString("...")
 		}
 		contextBuilder.WriteString("\n\n")
@@ -293,7 +595,7 @@ Brief explanation of the suggested approach and order.`,
 		},
 	}
 
-	return s.client.SendChatRequest(ctx, messages)
+	return s.client.SendChatRequest(withWorkflow(ctx, "edits"), messages)
 }
 
 // min helper function
@@ -309,9 +611,7 @@ func (s *Service) ChatAboutCodeStream(ctx context.Context, code, userMessage str
 	messages := []Message{
 		{
 			Role: "system",
-			Content: `You are an expert software engineer and code reviewer.
-You help developers understand, improve, and debug their code.
-Provide clear, actionable advice and explanations.`,
+			Content: ChatSystemPrompt,
 		},
 	}
 
@@ -335,11 +635,11 @@ func (s *Service) ChatWithHistoryContextStream(ctx context.Context, conversation
     messages := []Message{
         {
             Role: "system",
-            Content: `You are an expert software engineer and code reviewer.
+            Content: s.systemPrompt(`You are an expert software engineer and code reviewer.
 You help developers understand, improve, and debug their code.
 Provide clear, actionable advice and explanations.
 
-CRITICAL: If tool results are already present in the conversation history, you MUST use those results to answer. Do not ignore tool outputs or hallucinate different information. Always base your response on the actual tool results provided.`,
+CRITICAL: If tool results are already present in the conversation history, you MUST use those results to answer. Do not ignore tool outputs or hallucinate different information. Always base your response on the actual tool results provided.`),
         },
     }
 
@@ -374,7 +674,7 @@ func (s *Service) ChatWithHistoryContextStreamWithToolsAndChoice(ctx context.Con
     messages := []Message{
         {
             Role: "system",
-            Content: `You are an expert software engineer and code reviewer.
+            Content: s.systemPrompt(`You are an expert software engineer and code reviewer.
 You help developers understand, improve, and debug their code.
 Provide clear, actionable advice and explanations.
 You have access to tools to help you gather information about the project.
@@ -392,6 +692,10 @@ Tool Usage Guidelines:
    - Read with path: {"path": "internal/api/client.go"}
    - Read lines 10-50: {"path": "main.go", "startLine": 10, "endLine": 50}
 
+3. read_more - Read the next chunk of a large file loaded as a preview
+   - Continue after the preview: {"path": "data/big.log", "offset": 32768}
+   - Use the path and offset given in the [PREVIEW ...] note
+
 CRITICAL RULES:
 - ALWAYS provide arguments in valid JSON format
 - NEVER call a tool without arguments (use {} for no args, not empty/null)
@@ -399,7 +703,7 @@ CRITICAL RULES:
 - If user asks to read "X", you must call read_file with {"path": "X"}
 - Tool calls without proper JSON arguments WILL FAIL
 - Start with list_files {"recursive": true} to see all files
-- Tool results appear as role:"tool" messages - use those results`,
+- Tool results appear as role:"tool" messages - use those results`),
         },
     }
 
@@ -0,0 +1,60 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestGenerateSessionTitle checks that messages are cut by runes, so text
+// with multi-byte characters reaches the API intact
+func TestGenerateSessionTitle(t *testing.T) {
+	var sent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request ChatRequest
+		json.NewDecoder(r.Body).Decode(&request)
+		sent = request.Messages[len(request.Messages)-1].Content
+		w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"\"Titolo\""}}]}`))
+	}))
+	defer server.Close()
+
+	service := NewService(&DeepSeekClient{
+		apiKey:     "key",
+		baseURL:    server.URL,
+		model:      "deepseek-chat",
+		httpClient: &http.Client{Timeout: 5 * time.Second},
+		maxTokens:  256,
+	})
+	history := []Message{{Role: "user", Content: "a" + strings.Repeat("è", 400)}}
+
+	title, err := service.GenerateSessionTitle(context.Background(), history)
+	if err != nil {
+		t.Fatalf("GenerateSessionTitle failed: %v", err)
+	}
+	if title != "Titolo" {
+		t.Errorf("Expected title Titolo, got %q", title)
+	}
+	if strings.ContainsRune(sent, '�') {
+		t.Errorf("Expected valid UTF-8 in the request, got %q", sent)
+	}
+	if !strings.Contains(sent, "a"+strings.Repeat("è", 299)+"\n") {
+		t.Errorf("Expected the message cut to 300 runes, got %q", sent)
+	}
+}
@@ -13,6 +13,8 @@
 
 package api
 
+import "time"
+
 // ChatRequest represents a chat completion request
 type ChatRequest struct {
 	Model       string      `json:"model"`
@@ -21,14 +23,18 @@ type ChatRequest struct {
 	MaxTokens   int         `json:"max_tokens"`
 	Tools       []Tool      `json:"tools,omitempty"`
 	ToolChoice  interface{} `json:"tool_choice,omitempty"`
+	Seed        *int        `json:"seed,omitempty"` // Sampling seed for reproducible output, nil for none
+	N           int         `json:"n,omitempty"`    // Alternative completions to generate, 0 for one
 }
 
 // Message represents a chat message
 type Message struct {
-	Role       string     `json:"role"`
-	Content    string     `json:"content,omitempty"`
-	ToolCalls  []ToolCall `json:"tool_calls,omitempty"`
-	ToolCallID string     `json:"tool_call_id,omitempty"`
+	Role       string      `json:"role"`
+	Content    string      `json:"content,omitempty"`
+	ToolCalls  []ToolCall  `json:"tool_calls,omitempty"`
+	ToolCallID string      `json:"tool_call_id,omitempty"`
+	Time       time.Time   `json:"-"` // When the message was added; not sent to the API
+	Generation *Generation `json:"-"` // Model, temperature and tokens of a response, if known; not sent to the API
 }
 
 // Tool represents a function that can be called by the model
@@ -54,6 +60,18 @@ type ToolCall struct {
 	} `json:"function"`
 }
 
+// ToolCallDelta is a fragment of a tool call in a streaming response. Only the
+// first fragment of a call carries its ID and name; later ones are matched by Index.
+type ToolCallDelta struct {
+	Index    *int   `json:"index,omitempty"`
+	ID       string `json:"id,omitempty"`
+	Type     string `json:"type,omitempty"`
+	Function struct {
+		Name      string `json:"name,omitempty"`
+		Arguments string `json:"arguments,omitempty"`
+	} `json:"function"`
+}
+
 // ChatResponse represents the API response
 type ChatResponse struct {
 	ID      string `json:"id"`
@@ -85,6 +103,7 @@ type StreamingChatRequest struct {
 	Stream      bool        `json:"stream"`
 	Tools       []Tool      `json:"tools,omitempty"`
 	ToolChoice  interface{} `json:"tool_choice,omitempty"`
+	Seed        *int        `json:"seed,omitempty"` // Sampling seed for reproducible output, nil for none
 }
 
 // ChatCompletionChunk represents a chunk in streaming response
@@ -98,7 +117,7 @@ type ChatCompletionChunk struct {
 		Delta struct {
 			Role      string     `json:"role,omitempty"`
 			Content   string     `json:"content,omitempty"`
-			ToolCalls []ToolCall `json:"tool_calls,omitempty"`
+			ToolCalls []ToolCallDelta `json:"tool_calls,omitempty"`
 		} `json:"delta"`
 		FinishReason *string `json:"finish_reason"`
 	} `json:"choices"`
@@ -0,0 +1,203 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultPath is the project file tool calls are recorded to
+var DefaultPath = filepath.Join(".deecli", "audit.jsonl")
+
+// Decision is the user's answer to a tool approval request
+type Decision string
+
+const (
+	DecisionOnce   Decision = "once"   // Approved for this call
+	DecisionAlways Decision = "always" // Approved for the project
+	DecisionDenied Decision = "denied" // Rejected or cancelled
+)
+
+// Entry is a single recorded tool call
+type Entry struct {
+	Time       time.Time       `json:"time"`
+	Tool       string          `json:"tool"`
+	Arguments  json.RawMessage `json:"arguments,omitempty"`
+	Decision   Decision        `json:"decision"`
+	DurationMs int64           `json:"duration_ms"`
+	ResultSize int             `json:"result_size"`
+	Success    bool            `json:"success"`
+	Error      string          `json:"error,omitempty"`
+	DryRun     bool            `json:"dry_run,omitempty"`      // The call was only simulated
+	ToolCallID string          `json:"tool_call_id,omitempty"` // ID the model gave the call
+	SessionID  int64           `json:"session_id,omitempty"`
+	MessageID  int             `json:"message_id,omitempty"` // Position of the user message that started the chain
+}
+
+// Status summarizes the outcome of the call: ok, failed, simulated or not run
+func (e Entry) Status() string {
+	switch {
+	case e.Decision == DecisionDenied:
+		return "not run"
+	case !e.Success:
+		return "failed"
+	case e.DryRun:
+		return "simulated"
+	}
+	return "ok"
+}
+
+// targetKeys are the arguments that best say what a call looked at
+var targetKeys = []string{"path", "file", "pattern", "query", "command", "url", "number", "ref"}
+
+// targetWidth is how much of the target Target returns
+const targetWidth = 40
+
+// Target returns the main argument of the call, such as the path it read,
+// shortened for display. It is empty when the call had no arguments.
+func (e Entry) Target() string {
+	var args map[string]interface{}
+	if json.Unmarshal(e.Arguments, &args) != nil || len(args) == 0 {
+		return ""
+	}
+
+	keys := append([]string{}, targetKeys...)
+	var rest []string
+	for key := range args {
+		if !slices.Contains(targetKeys, key) {
+			rest = append(rest, key)
+		}
+	}
+	sort.Strings(rest)
+
+	for _, key := range append(keys, rest...) {
+		var target string
+		switch value := args[key].(type) {
+		case string:
+			target = value
+		case float64:
+			target = strconv.FormatFloat(value, 'f', -1, 64)
+		default:
+			continue
+		}
+		if runes := []rune(target); len(runes) > targetWidth {
+			target = string(runes[:targetWidth-1]) + "…"
+		}
+		return target
+	}
+	return ""
+}
+
+// Log appends tool call entries to a JSON Lines file
+type Log struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewLog creates a log writing to path; the file is created on first use
+func NewLog(path string) *Log {
+	return &Log{path: path}
+}
+
+// Path returns the file the log writes to
+func (l *Log) Path() string {
+	return l.path
+}
+
+// Record appends an entry to the log
+func (l *Log) Record(entry Entry) error {
+	if l == nil {
+		return nil
+	}
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+	// Invalid JSON arguments are kept as a string so the line stays valid
+	if len(entry.Arguments) > 0 && !json.Valid(entry.Arguments) {
+		quoted, _ := json.Marshal(string(entry.Arguments))
+		entry.Arguments = quoted
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit entry: %w", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(l.path), 0755); err != nil {
+		return fmt.Errorf("failed to create audit directory: %w", err)
+	}
+	file, err := os.OpenFile(l.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return nil
+}
+
+// Recent returns up to n entries, newest first. n <= 0 returns all entries.
+// Lines that cannot be parsed are skipped.
+func (l *Log) Recent(n int) ([]Entry, error) {
+	if l == nil {
+		return nil, nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	file, err := os.Open(l.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer file.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var entry Entry
+		if json.Unmarshal(scanner.Bytes(), &entry) == nil {
+			entries = append(entries, entry)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+
+	if n <= 0 || n > len(entries) {
+		n = len(entries)
+	}
+	recent := make([]Entry, 0, n)
+	for i := len(entries) - 1; i >= 0 && len(recent) < n; i-- {
+		recent = append(recent, entries[i])
+	}
+	return recent, nil
+}
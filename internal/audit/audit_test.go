@@ -0,0 +1,96 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLogRecordAndRecent(t *testing.T) {
+	log := NewLog(filepath.Join(t.TempDir(), ".deecli", "audit.jsonl"))
+
+	entries, err := log.Recent(0)
+	if err != nil || len(entries) != 0 {
+		t.Fatalf("expected an empty log before the first record, got %v (%v)", entries, err)
+	}
+
+	records := []Entry{
+		{Tool: "read_file", Arguments: json.RawMessage(`{"path":"main.go"}`), Decision: DecisionOnce, Success: true, ResultSize: 120, MessageID: 3},
+		{Tool: "git_diff", Decision: DecisionDenied},
+		{Tool: "list_files", Arguments: json.RawMessage(`{"path":`), Decision: DecisionAlways, Error: "invalid JSON"},
+	}
+	for _, entry := range records {
+		if err := log.Record(entry); err != nil {
+			t.Fatalf("Record() error = %v", err)
+		}
+	}
+
+	entries, err = log.Recent(2)
+	if err != nil {
+		t.Fatalf("Recent() error = %v", err)
+	}
+	if len(entries) != 2 || entries[0].Tool != "list_files" || entries[1].Tool != "git_diff" {
+		t.Fatalf("expected the two newest entries first, got %+v", entries)
+	}
+	if entries[0].Time.IsZero() {
+		t.Error("expected the time to be filled in")
+	}
+	if string(entries[0].Arguments) != `"{\"path\":"` {
+		t.Errorf("expected invalid arguments to be kept as a string, got %s", entries[0].Arguments)
+	}
+
+	all, _ := log.Recent(0)
+	if len(all) != 3 || all[2].MessageID != 3 || all[2].ResultSize != 120 {
+		t.Errorf("expected all entries with their fields, got %+v", all)
+	}
+}
+
+func TestLogRecentSkipsBadLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	data := `{"tool":"read_file","decision":"once"}` + "\n" + "not json\n"
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatalf("Failed to write log: %v", err)
+	}
+
+	entries, err := NewLog(path).Recent(0)
+	if err != nil || len(entries) != 1 || entries[0].Tool != "read_file" {
+		t.Errorf("expected the one valid entry, got %+v (%v)", entries, err)
+	}
+}
+
+func TestEntryStatusAndTarget(t *testing.T) {
+	tests := []struct {
+		entry  Entry
+		status string
+		target string
+	}{
+		{Entry{Decision: DecisionOnce, Success: true, Arguments: json.RawMessage(`{"limit":5,"path":"main.go"}`)}, "ok", "main.go"},
+		{Entry{Decision: DecisionAlways, Arguments: json.RawMessage(`{"number":42}`)}, "failed", "42"},
+		{Entry{Decision: DecisionOnce, Success: true, DryRun: true, Arguments: json.RawMessage(`{"b":"second","a":"first"}`)}, "simulated", "first"},
+		{Entry{Decision: DecisionDenied, Arguments: json.RawMessage(`{}`)}, "not run", ""},
+		{Entry{Decision: DecisionOnce, Success: true, Arguments: json.RawMessage(`{"command":"` + strings.Repeat("x", 60) + `"}`)}, "ok", strings.Repeat("x", 39) + "…"},
+	}
+	for _, tt := range tests {
+		if got := tt.entry.Status(); got != tt.status {
+			t.Errorf("Status() = %q, want %q", got, tt.status)
+		}
+		if got := tt.entry.Target(); got != tt.target {
+			t.Errorf("Target() = %q, want %q", got, tt.target)
+		}
+	}
+}
@@ -358,4 +358,18 @@ func TestParseAndExtractToolCalls_EdgeCases(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+
+func BenchmarkHandler_ParseAndExtractToolCalls(b *testing.B) {
+	handler := NewHandler(Dependencies{
+		FileTracker: tracker.NewFileTracker(),
+	})
+	call := `<｜tool▁call▁begin｜>read_file<｜tool▁sep｜>{"path": "main.go"}<｜tool▁call▁end｜>`
+	content := strings.Repeat("Some explanation before the tools. ", 50) +
+		"<｜tool▁calls▁begin｜>" + strings.Repeat(call, 5) + "<｜tool▁calls▁end｜>"
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		handler.ParseAndExtractToolCalls(content)
+	}
+}
@@ -15,13 +15,19 @@ package chat
 
 import (
 	"fmt"
+	"os"
+	"runtime/debug"
+
 	"github.com/antenore/deecli/internal/config"
+	"github.com/antenore/deecli/internal/sessions"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
 // ChatApp represents the main chat application
 type ChatApp struct {
 	program *tea.Program
+	plain   bool              // Force the lightweight plain UI (--plain)
+	newTab  func() *NewModel // Creates the chat of a /tab new tab; nil disables tabs
 }
 
 // NewChatApp creates a new chat application
@@ -29,86 +35,114 @@ func NewChatApp() *ChatApp {
 	return &ChatApp{}
 }
 
+// SetPlainMode forces the lightweight plain UI regardless of terminal width
+func (app *ChatApp) SetPlainMode(plain bool) {
+	app.plain = plain
+}
+
 // Start initializes and starts the chat application (legacy method)
 func (app *ChatApp) Start() error {
 	m := newChatModel()
 	
-	// Try with alt screen first, fallback to normal mode if TTY issues
-	app.program = tea.NewProgram(m, tea.WithAltScreen())
-	
-	if _, err := app.program.Run(); err != nil {
-		// Fallback to basic mode without alt screen
-		fmt.Println("Falling back to basic mode...")
-		app.program = tea.NewProgram(m)
-		_, err = app.program.Run()
-		return err
-	}
-	
-	return nil
+	return app.run(m, tea.WithAltScreen(), tea.WithReportFocus())
 }
 
 // StartNew initializes and starts the new chat application
 func (app *ChatApp) StartNew() error {
 	m := newChatModel()
 	
-	// Use alt screen for full terminal control with proper input handling
-	app.program = tea.NewProgram(m, 
-		tea.WithAltScreen(),
-	)
-	
-	if _, err := app.program.Run(); err != nil {
+	return app.run(m, tea.WithAltScreen(), tea.WithReportFocus())
+}
+
+// run runs the chat UI with m as its first tab and opts, falling back to
+// basic mode without them if that fails. A clean exit removes the recovery
+// checkpoint; it is kept when the program ends with an error. Panics are
+// handled by recoverCrash instead of Bubble Tea, which would report them as
+// a clean exit; the active tab is checkpointed. SIGTERM and SIGHUP quit
+// gracefully; the terminal may be gone by then, so an error after such a
+// signal neither falls back nor keeps the checkpoint.
+func (app *ChatApp) run(m *NewModel, opts ...tea.ProgramOption) (err error) {
+	tabs := newTabSet(m, app.newTab)
+
+	// Runs last, also after a crash has been handled
+	defer func() {
+		if shutdownErr := tabs.shutdown(); shutdownErr != nil {
+			fmt.Fprintf(os.Stderr, "Shutdown: %v\n", shutdownErr)
+		}
+	}()
+	defer func() {
+		if r := recover(); r != nil {
+			err = app.recoverCrash(tabs.current().model, r, debug.Stack())
+		}
+	}()
+
+	app.program = tea.NewProgram(crashGuard{tabs}, append(opts, tea.WithoutCatchPanics())...)
+	stopSignals := quitOnSignals(app.program)
+	_, err = app.program.Run()
+	if stopSignals() {
+		err = nil
+	} else if err != nil {
 		// Fallback to basic mode without alt screen
 		fmt.Println("Falling back to basic mode...")
-		app.program = tea.NewProgram(m)
+		app.program = tea.NewProgram(crashGuard{tabs}, tea.WithoutCatchPanics())
+		stopSignals = quitOnSignals(app.program)
 		_, err = app.program.Run()
-		return err
+		if stopSignals() {
+			err = nil
+		}
 	}
-	
-	return nil
+	if err == nil {
+		sessions.RemoveCheckpoint()
+	}
+	return err
 }
 
 // StartNewWithConfig initializes and starts the chat application with specific configuration
 func (app *ChatApp) StartNewWithConfig(configManager *config.Manager, apiKey, model string, temperature float64, maxTokens int) error {
 	m := newChatModelWithConfig(configManager, apiKey, model, temperature, maxTokens)
-	
-	// Use alt screen for full terminal control with proper input handling
-	app.program = tea.NewProgram(m, 
-		tea.WithAltScreen(),
-	)
-	
-	if _, err := app.program.Run(); err != nil {
-		// Fallback to basic mode without alt screen
-		fmt.Println("Falling back to basic mode...")
-		app.program = tea.NewProgram(m)
-		_, err = app.program.Run()
-		return err
+	if m.redactErr != nil {
+		// Never send what redaction was asked to mask
+		return m.redactErr
 	}
+	m.forcePlain = app.plain
+	app.enableTabs(configManager, apiKey, model, temperature, maxTokens)
+	offerRecovery(m)
 	
-	return nil
+	return app.run(m, tea.WithAltScreen(), tea.WithReportFocus())
 }
 
 // StartContinueWithConfig continues previous session with specific configuration
 func (app *ChatApp) StartContinueWithConfig(configManager *config.Manager, apiKey, model string, temperature float64, maxTokens int) error {
 	m := newChatModelWithConfig(configManager, apiKey, model, temperature, maxTokens)
-	
-	// Load previous session messages
-	if err := m.loadPreviousSession(); err != nil {
-		fmt.Printf("Could not load previous session: %v\n", err)
-		fmt.Println("Starting new session instead...")
+	if m.redactErr != nil {
+		// Never send what redaction was asked to mask
+		return m.redactErr
 	}
+	m.forcePlain = app.plain
+	app.enableTabs(configManager, apiKey, model, temperature, maxTokens)
 	
-	// Use alt screen for full terminal control with proper input handling
-	app.program = tea.NewProgram(m, 
-		tea.WithAltScreen(),
-	)
-	
-	if _, err := app.program.Run(); err != nil {
-		// Fallback to basic mode without alt screen
-		fmt.Println("Falling back to basic mode...")
-		app.program = tea.NewProgram(m)
-		_, err = app.program.Run()
-		return err
+	// Load previous session messages, unless an interrupted run was recovered
+	if !offerRecovery(m) {
+		if err := m.loadPreviousSession(); err != nil {
+			fmt.Printf("Could not load previous session: %v\n", err)
+			fmt.Println("Starting new session instead...")
+		}
 	}
 	
-	return nil
-}
\ No newline at end of file
+	return app.run(m, tea.WithAltScreen(), tea.WithReportFocus())
+}
+
+// enableTabs lets /tab new open tabs with the given configuration, each
+// starting a new session
+func (app *ChatApp) enableTabs(configManager *config.Manager, apiKey, model string, temperature float64, maxTokens int) {
+	app.newTab = func() *NewModel {
+		m := newChatModelWithConfig(configManager, apiKey, model, temperature, maxTokens)
+		m.forcePlain = app.plain
+		if m.sessionManager != nil {
+			if session, err := m.sessionManager.CreateSession(); err == nil {
+				m.switchSession(session)
+			}
+		}
+		return m
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chat
+
+import (
+	"fmt"
+
+	"github.com/antenore/deecli/internal/ai"
+	"github.com/antenore/deecli/internal/chat/ui"
+	"github.com/antenore/deecli/internal/errlog"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// sendChoices asks for n answers to input for /send --n. The prompt is only
+// shown until an answer is chosen: the prompt and the chosen answer are
+// added to the conversation together, and neither if none is kept.
+func (m *NewModel) sendChoices(input string, n int) tea.Cmd {
+	if m.turnBusy() {
+		m.addMessage("system", "Wait for the current answer to finish before asking for several answers")
+		return nil
+	}
+	if m.aiOperations == nil {
+		return func() tea.Msg {
+			return ai.ChoicesMsg{Prompt: input, Err: fmt.Errorf("AI operations not available")}
+		}
+	}
+
+	m.lastPrompt = input
+	m.confirmRepeat = ""
+	m.renderer.SetViewportWidth(m.viewport.Width, m.filesWidgetVisible)
+	m.messageManager.AddDisplayMessage(m.renderer.FormatMessage("user", input))
+
+	cmd := m.aiOperations.CallAPIChoices(m.buildContextPrompt(input), input, n)
+	m.apiCancel = m.aiOperations.GetAPICancel()
+	loading := m.setLoading(true, fmt.Sprintf("Thinking of %d answers...", n))
+	m.refreshViewport()
+	return tea.Batch(loading, cmd)
+}
+
+// handleChoices opens the picker on the answers to a /send --n prompt, or
+// keeps the only one received
+func (m *NewModel) handleChoices(msg ai.ChoicesMsg) {
+	m.setLoading(false, "")
+	m.apiCancel = nil
+	m.showRedactionNotice()
+
+	if msg.Err != nil {
+		m.reportError(errlog.Classify(msg.Err), "Asking for several answers failed: "+errlog.UserMessage(msg.Err), msg.Err)
+		return
+	}
+
+	m.choicePrompt, m.choices = msg.Prompt, msg.Choices
+	if len(msg.Choices) == 1 {
+		m.addMessage("system", "Only one answer was received; keeping it")
+		m.keepChoice(0)
+		return
+	}
+	plain := m.layoutManager.IsPlain() || m.renderer.IsAccessible()
+	m.choicePicker = ui.NewChoicePicker(msg.Choices, plain, m.width, m.height)
+}
+
+// keepChoice adds the /send --n prompt and the answer at index to the
+// conversation; -1 means the picker was cancelled and nothing is kept
+func (m *NewModel) keepChoice(index int) {
+	prompt, choices := m.choicePrompt, m.choices
+	m.choicePrompt, m.choices = "", nil
+	if index < 0 || index >= len(choices) {
+		m.addMessage("system", fmt.Sprintf("No answer kept; the prompt was not added to the conversation (%d discarded)", len(choices)))
+		return
+	}
+
+	m.messageManager.RecordMessage("user", prompt)
+	m.addResponse(choices[index])
+	m.fileContext.TrackPatchSuggestions(choices[index])
+	if len(choices) > 1 {
+		m.addMessage("system", fmt.Sprintf("Kept answer %d of %d; the others were discarded", index+1, len(choices)))
+	}
+	m.viewport.GotoBottom()
+}
@@ -14,13 +14,21 @@
 package commands
 
 import (
+	"context"
 	"fmt"
+	"os"
 	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
+	aiops "github.com/antenore/deecli/internal/ai"
+	"github.com/antenore/deecli/internal/config"
 	"github.com/antenore/deecli/internal/editor"
+	"github.com/antenore/deecli/internal/files"
+	"github.com/antenore/deecli/internal/forge"
+	"github.com/antenore/deecli/internal/scaffold"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
@@ -34,9 +42,22 @@ func NewAICommands(deps Dependencies) *AICommands {
 	return &AICommands{deps: deps}
 }
 
-// Analyze handles the /analyze command
+// Analyze handles the /analyze command, which runs as a background task
 func (ai *AICommands) Analyze(args []string) tea.Cmd {
-	if len(ai.deps.FileContext.Files) == 0 {
+	return ai.runFileTask("/analyze", args, ai.deps.AnalyzeFiles)
+}
+
+// runFileTask starts an analysis command on the files its arguments name, or
+// on the loaded files without any, and writes the result to the file given
+// after ">" instead of the chat
+func (ai *AICommands) runFileTask(command string, args []string, start func([]files.LoadedFile, string) tea.Cmd) tea.Cmd {
+	patterns, output, ok := splitRedirect(args)
+	if !ok {
+		ai.deps.MessageLogger("system", fmt.Sprintf("Usage: %s [files...] [> output-file]", command))
+		return nil
+	}
+
+	if len(patterns) == 0 && len(ai.deps.FileContext.Files) == 0 {
 		ai.deps.MessageLogger("system", "No files loaded. Use /load to load files first.")
 		return nil
 	}
@@ -46,15 +67,51 @@ func (ai *AICommands) Analyze(args []string) tea.Cmd {
 		return nil
 	}
 
-	loadingCmd := ai.deps.SetLoading(true, "Analyzing files...")
+	loaded, err := ai.filesFor(patterns)
+	if err != nil {
+		ai.deps.MessageLogger("system", fmt.Sprintf("❌ %v", err))
+		return nil
+	}
+	if !ai.confirmOutput(output) {
+		return nil
+	}
+	return start(loaded, output)
+}
+
+// Init handles the /init command
+func (ai *AICommands) Init(args []string) tea.Cmd {
+	if ai.deps.APIClient == nil {
+		ai.deps.MessageLogger("system", "Please set DEEPSEEK_API_KEY environment variable")
+		return nil
+	}
+
+	if ai.deps.InitProject == nil {
+		ai.deps.MessageLogger("system", "❌ Project init not available")
+		return nil
+	}
+
+	ai.deps.MessageLogger("system", "🗺️ Mapping project structure and key files...")
+	loadingCmd := ai.deps.SetLoading(true, "Generating project map...")
 	ai.deps.RefreshUI()
-	return tea.Batch(loadingCmd, ai.deps.AnalyzeFiles())
+	return tea.Batch(loadingCmd, ai.deps.InitProject())
 }
 
-// Explain handles the /explain command
-func (ai *AICommands) Explain(args []string) tea.Cmd {
-	if len(ai.deps.FileContext.Files) == 0 {
-		ai.deps.MessageLogger("system", "No files loaded. Use /load to load files first.")
+// prDiffDir is where /pr review saves fetched diffs
+var prDiffDir = filepath.Join(".deecli", "pr")
+
+// prFetchTimeout bounds fetching a pull request and its diff
+const prFetchTimeout = 60 * time.Second
+
+// PR handles the /pr command: "/pr review <number>" fetches a pull or merge
+// request from the origin forge, loads its diff and reviews it
+func (ai *AICommands) PR(args []string) tea.Cmd {
+	if len(args) != 2 || args[0] != "review" {
+		ai.deps.MessageLogger("system", "Usage: /pr review <number>")
+		return nil
+	}
+	number, err := strconv.Atoi(strings.TrimPrefix(args[1], "#"))
+	if err != nil || number <= 0 {
+		ai.deps.MessageLogger("system", fmt.Sprintf("Invalid pull request number: %s", args[1]))
 		return nil
 	}
 
@@ -63,26 +120,127 @@ func (ai *AICommands) Explain(args []string) tea.Cmd {
 		return nil
 	}
 
-	loadingCmd := ai.deps.SetLoading(true, "Explaining code...")
+	var tokens forge.Tokens
+	if ai.deps.ConfigManager != nil {
+		tokens = forge.Tokens{GitHub: ai.deps.ConfigManager.GetGitHubToken(), GitLab: ai.deps.ConfigManager.GetGitLabToken()}
+	}
+
+	loadingCmd := ai.deps.SetLoading(true, fmt.Sprintf("Fetching #%d...", number))
 	ai.deps.RefreshUI()
-	return tea.Batch(loadingCmd, ai.deps.ExplainFiles())
+	return tea.Batch(loadingCmd, func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), prFetchTimeout)
+		defer cancel()
+		path, pr, err := fetchPullRequest(ctx, tokens, number)
+		return PullRequestFetchedMsg{PullRequest: pr, DiffPath: path, Err: err}
+	})
 }
 
-// Improve handles the /improve command
-func (ai *AICommands) Improve(args []string) tea.Cmd {
-	if len(ai.deps.FileContext.Files) == 0 {
-		ai.deps.MessageLogger("system", "No files loaded. Use /load to load files first.")
-		return nil
+// fetchPullRequest fetches a pull request and saves its diff in prDiffDir
+func fetchPullRequest(ctx context.Context, tokens forge.Tokens, number int) (string, *forge.PullRequest, error) {
+	remote, err := forge.DetectRemote(ctx, ".")
+	if err != nil {
+		return "", nil, err
+	}
+	pr, err := forge.NewClient(remote, tokens).GetPullRequest(ctx, number)
+	if err != nil {
+		return "", nil, err
+	}
+	if strings.TrimSpace(pr.Diff) == "" {
+		return "", nil, fmt.Errorf("#%d has no changes", number)
 	}
 
-	if ai.deps.APIClient == nil {
+	if err := os.MkdirAll(prDiffDir, 0755); err != nil {
+		return "", nil, err
+	}
+	path := filepath.Join(prDiffDir, fmt.Sprintf("%d.diff", number))
+	if err := os.WriteFile(path, []byte(pr.Diff), 0644); err != nil {
+		return "", nil, err
+	}
+	return path, pr, nil
+}
+
+// DefaultCompactKeep is how many recent turns /compact keeps verbatim
+const DefaultCompactKeep = 2
+
+// Compact handles the /compact command, which replaces the older part of the
+// conversation with a summary to free up token budget
+func (ai *AICommands) Compact(args []string) tea.Cmd {
+	if ai.deps.APIClient == nil || ai.deps.CompactConversation == nil {
 		ai.deps.MessageLogger("system", "Please set DEEPSEEK_API_KEY environment variable")
 		return nil
 	}
 
-	loadingCmd := ai.deps.SetLoading(true, "Generating improvement suggestions...")
+	keep := DefaultCompactKeep
+	if len(args) > 0 {
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n < 0 {
+			ai.deps.MessageLogger("system", "Usage: /compact [turns to keep]")
+			return nil
+		}
+		keep = n
+	}
+
+	if aiops.CompactSplit(ai.deps.APIMessages, keep) == 0 {
+		ai.deps.MessageLogger("system", fmt.Sprintf("Nothing to compact: the conversation has no more than %d turn(s)", keep))
+		return nil
+	}
+
+	loadingCmd := ai.deps.SetLoading(true, "Summarizing the conversation...")
 	ai.deps.RefreshUI()
-	return tea.Batch(loadingCmd, ai.deps.ImproveFiles())
+	return tea.Batch(loadingCmd, ai.deps.CompactConversation(keep))
+}
+
+// defaultReviewBudget is the chunk size of /review without a configuration
+const defaultReviewBudget = 64 * 1024
+
+// Review handles the /review command, which reviews the diff between two git
+// refs in chunks that fit the context budget and reports the findings by
+// file. It runs as a background task.
+func (ai *AICommands) Review(args []string) tea.Cmd {
+	args, output, ok := splitRedirect(args)
+	if !ok || len(args) != 1 || strings.HasPrefix(args[0], "-") {
+		ai.deps.MessageLogger("system", "Usage: /review <base>..<head> [> output-file] (or /review <base> to review up to HEAD)")
+		return nil
+	}
+	if ai.deps.APIClient == nil || ai.deps.ReviewDiff == nil {
+		ai.deps.MessageLogger("system", "Please set DEEPSEEK_API_KEY environment variable")
+		return nil
+	}
+
+	refRange := args[0]
+	if !strings.Contains(refRange, "..") {
+		refRange += "..HEAD"
+	}
+	diff, err := gitOutput("diff", refRange, "--")
+	if err != nil {
+		ai.deps.MessageLogger("system", fmt.Sprintf("❌ %v", err))
+		return nil
+	}
+	if strings.TrimSpace(diff) == "" {
+		ai.deps.MessageLogger("system", fmt.Sprintf("No changes in %s", refRange))
+		return nil
+	}
+
+	budget := defaultReviewBudget
+	if ai.deps.ConfigManager != nil {
+		budget = ai.deps.ConfigManager.GetMaxContextSize()
+	}
+	chunks := files.ChunkDiff(diff, budget)
+
+	if !ai.confirmOutput(output) {
+		return nil
+	}
+	return ai.deps.ReviewDiff(refRange, chunks, output)
+}
+
+// Explain handles the /explain command, which runs as a background task
+func (ai *AICommands) Explain(args []string) tea.Cmd {
+	return ai.runFileTask("/explain", args, ai.deps.ExplainFiles)
+}
+
+// Improve handles the /improve command, which runs as a background task
+func (ai *AICommands) Improve(args []string) tea.Cmd {
+	return ai.runFileTask("/improve", args, ai.deps.ImproveFiles)
 }
 
 // getFileFromRecentContext analyzes recent user messages to find the most recently mentioned loaded file
@@ -145,6 +303,39 @@ func (ai *AICommands) extractFileFromMessage(message string) string {
 	return ""
 }
 
+// editorConfig returns the editor settings of the chat: the conversation for
+// the instruction file, the configured templates for new files and editor
+// commands, and whether to report where the cursor was
+func editorConfig(deps Dependencies) editor.Config {
+	cfg := editor.Config{
+		Instructions:    func(path string) *editor.Instructions { return instructions(deps, path) },
+		MessageLogger:   deps.MessageLogger,
+		Scaffolder:      scaffold.New(deps.ConfigManager),
+		ReturnContext:   deps.ConfigManager != nil && deps.ConfigManager.GetEditorContext(),
+	}
+	if deps.ConfigManager != nil {
+		cfg.Command, cfg.GotoCommand = deps.ConfigManager.GetEditorCommands()
+	}
+	return cfg
+}
+
+// instructions returns what the instruction file for path is built from, or
+// nil when instruction files are turned off
+func instructions(deps Dependencies, path string) *editor.Instructions {
+	size := config.DefaultInstructionFileSize * 1024
+	if deps.ConfigManager != nil {
+		size = deps.ConfigManager.GetInstructionFileSize()
+	}
+	if size == 0 {
+		return nil
+	}
+	ins := &editor.Instructions{Messages: deps.APIMessages, MaxSize: size}
+	if deps.FileTracker != nil {
+		ins.Line, ins.Symbol = deps.FileTracker.Location(path)
+	}
+	return ins
+}
+
 // showInteractiveFileSelection displays a numbered list of loaded files for user selection
 func (ai *AICommands) showInteractiveFileSelection() tea.Cmd {
 	if len(ai.deps.FileContext.Files) == 0 {
@@ -155,10 +346,7 @@ func (ai *AICommands) showInteractiveFileSelection() tea.Cmd {
 	if len(ai.deps.FileContext.Files) == 1 {
 		// Only one file loaded, use it directly
 		file := ai.deps.FileContext.Files[0]
-		config := editor.Config{
-			MessageProvider: func() []string { return ai.deps.Messages },
-			MessageLogger:   ai.deps.MessageLogger,
-		}
+		config := editorConfig(ai.deps)
 		ai.deps.MessageLogger("system", fmt.Sprintf("📝 Opening only loaded file: %s", file.RelPath))
 		return editor.OpenFileWithInstructions(file.RelPath, config)
 	}
@@ -184,12 +372,9 @@ func (ai *AICommands) Edit(args []string) tea.Cmd {
 	if len(args) < 1 {
 		// First, try to find a file from recent conversation context
 		if contextFile := ai.getFileFromRecentContext(); contextFile != "" {
-			config := editor.Config{
-				MessageProvider: func() []string { return ai.deps.Messages },
-				MessageLogger:   ai.deps.MessageLogger,
-			}
+			config := editorConfig(ai.deps)
 			ai.deps.MessageLogger("system", fmt.Sprintf("📝 Opening file from context: %s", contextFile))
-			return editor.OpenFileWithInstructions(contextFile, config)
+			return editor.OpenFileWithInstructions(ai.referencedLocation(contextFile), config)
 		}
 
 		// If no context, show interactive file selection
@@ -200,12 +385,9 @@ func (ai *AICommands) Edit(args []string) tea.Cmd {
 	if fileIndex, err := strconv.Atoi(args[0]); err == nil {
 		if fileIndex >= 1 && fileIndex <= len(ai.deps.FileContext.Files) {
 			selectedFile := ai.deps.FileContext.Files[fileIndex-1]
-			config := editor.Config{
-				MessageProvider: func() []string { return ai.deps.Messages },
-				MessageLogger:   ai.deps.MessageLogger,
-			}
+			config := editorConfig(ai.deps)
 			ai.deps.MessageLogger("system", fmt.Sprintf("📝 Opening selected file [%d]: %s", fileIndex, selectedFile.RelPath))
-			return editor.OpenFileWithInstructions(selectedFile.RelPath, config)
+			return editor.OpenFileWithInstructions(ai.referencedLocation(selectedFile.RelPath), config)
 		} else {
 			ai.deps.MessageLogger("system", fmt.Sprintf("Invalid file number. Please use 1-%d", len(ai.deps.FileContext.Files)))
 			return nil
@@ -213,10 +395,144 @@ func (ai *AICommands) Edit(args []string) tea.Cmd {
 	}
 
 	// Open specific file in editor
-	config := editor.Config{
-		MessageProvider: func() []string { return ai.deps.Messages },
-		MessageLogger:   ai.deps.MessageLogger,
+	config := editorConfig(ai.deps)
+	return editor.OpenFileWithInstructions(ai.referencedLocation(args[0]), config)
+}
+
+// suggestUsage lists the forms of /suggest
+const suggestUsage = "Usage: /suggest [list | open <n> | prompt <n> | fix <n>]"
+
+// Suggest handles the /suggest command: without arguments it asks the AI
+// which files the conversation calls for editing and lists the suggestions
+// numbered; open, prompt and fix act on one of them.
+func (ai *AICommands) Suggest(args []string) tea.Cmd {
+	if len(args) == 0 {
+		if ai.deps.APIClient == nil || ai.deps.GenerateEditSuggestions == nil {
+			ai.deps.MessageLogger("system", "Please set DEEPSEEK_API_KEY environment variable")
+			return nil
+		}
+		if len(ai.deps.APIMessages) == 0 && len(ai.deps.FileContext.Files) == 0 {
+			ai.deps.MessageLogger("system", "Nothing to suggest edits from yet: /load files or discuss the code first")
+			return nil
+		}
+		loadingCmd := ai.deps.SetLoading(true, "Looking for edits to suggest...")
+		ai.deps.RefreshUI()
+		return tea.Batch(loadingCmd, ai.deps.GenerateEditSuggestions())
+	}
+
+	suggestions := ai.deps.EditSuggestions
+	if args[0] == "list" {
+		if len(suggestions) == 0 {
+			ai.deps.MessageLogger("system", "No edit suggestions yet. Run /suggest to get some")
+		} else {
+			ai.deps.MessageLogger("system", aiops.FormatEditSuggestions(suggestions))
+		}
+		return nil
+	}
+
+	if len(args) != 2 {
+		ai.deps.MessageLogger("system", suggestUsage)
+		return nil
+	}
+	n, err := strconv.Atoi(args[1])
+	if err != nil || n < 1 || n > len(suggestions) {
+		if len(suggestions) == 0 {
+			ai.deps.MessageLogger("system", "No edit suggestions yet. Run /suggest to get some")
+		} else {
+			ai.deps.MessageLogger("system", fmt.Sprintf("Invalid suggestion number. Please use 1-%d", len(suggestions)))
+		}
+		return nil
+	}
+	suggestion := suggestions[n-1]
+
+	switch args[0] {
+	case "open":
+		ai.deps.MessageLogger("system", fmt.Sprintf("📝 Opening %s for suggestion %d: %s", suggestion.File, n, suggestion.Description))
+		return editor.OpenFileWithInstructions(ai.referencedLocation(suggestion.File), editorConfig(ai.deps))
+	case "prompt":
+		if ai.deps.SetInput == nil {
+			return nil
+		}
+		ai.deps.SetInput(fmt.Sprintf("In %s: %s", suggestion.File, suggestion.Description))
+		ai.deps.MessageLogger("system", fmt.Sprintf("✏️ Suggestion %d is in the input; edit it and press Enter to send", n))
+		return nil
+	case "fix":
+		if ai.deps.SendPrompt == nil {
+			return nil
+		}
+		return ai.deps.SendPrompt(fmt.Sprintf("Make this change in %s: %s\n\n"+
+			"Read the file, edit it with the file tools, then check the result, for example by building it or running its tests, "+
+			"and keep fixing it until the check passes.", suggestion.File, suggestion.Description))
+	default:
+		ai.deps.MessageLogger("system", suggestUsage)
+		return nil
+	}
+}
+
+// sendUsage lists the forms of /send
+var sendUsage = fmt.Sprintf("Usage: /send [--n <1-%d>] <prompt>", aiops.MaxChoices)
+
+// Send handles the /send command: it sends a prompt as if typed, or with
+// --n asks for several answers to choose from, keeping only the chosen one
+// in the conversation
+func (ai *AICommands) Send(args []string) tea.Cmd {
+	n := 1
+	if len(args) > 0 && (args[0] == "--n" || strings.HasPrefix(args[0], "--n=")) {
+		value := strings.TrimPrefix(args[0], "--n=")
+		args = args[1:]
+		if value == "--n" {
+			if len(args) == 0 {
+				ai.deps.MessageLogger("system", sendUsage)
+				return nil
+			}
+			value, args = args[0], args[1:]
+		}
+		count, err := strconv.Atoi(value)
+		if err != nil || count < 1 || count > aiops.MaxChoices {
+			ai.deps.MessageLogger("system", fmt.Sprintf("Invalid number of answers. Please use 1-%d", aiops.MaxChoices))
+			return nil
+		}
+		n = count
+	}
+
+	prompt := strings.TrimSpace(strings.Join(args, " "))
+	if prompt == "" {
+		ai.deps.MessageLogger("system", sendUsage)
+		return nil
+	}
+	if n == 1 {
+		if ai.deps.SendPrompt == nil {
+			return nil
+		}
+		return ai.deps.SendPrompt(prompt)
+	}
+	if ai.deps.APIClient == nil || ai.deps.SendChoices == nil {
+		ai.deps.MessageLogger("system", "Please set DEEPSEEK_API_KEY environment variable")
+		return nil
+	}
+	return ai.deps.SendChoices(prompt, n)
+}
+
+// referencedLocation appends to path the line the AI last pointed to in the
+// file, as main.go:42 or by naming a function, unless path has a line already
+func (ai *AICommands) referencedLocation(path string) string {
+	file, line := editor.ParseFileAndLine(path)
+	if line > 0 || ai.deps.FileTracker == nil {
+		return path
+	}
+
+	line, symbol := ai.deps.FileTracker.Location(file)
+	if line == 0 && symbol != "" {
+		line = editor.FindSymbol(file, symbol)
+	}
+	if line == 0 {
+		return path
+	}
+	if symbol != "" {
+		ai.deps.MessageLogger("system", fmt.Sprintf("📍 Jumping to %s (line %d), referenced in the last response", symbol, line))
+	} else {
+		ai.deps.MessageLogger("system", fmt.Sprintf("📍 Jumping to line %d, referenced in the last response", line))
 	}
-	return editor.OpenFileWithInstructions(args[0], config)
+	return fmt.Sprintf("%s:%d", file, line)
 }
 
@@ -14,9 +14,15 @@
 package commands
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	aiops "github.com/antenore/deecli/internal/ai"
+	"github.com/antenore/deecli/internal/api"
 	"github.com/antenore/deecli/internal/files"
+	tea "github.com/charmbracelet/bubbletea"
 )
 
 func TestGetFileFromRecentContext(t *testing.T) {
@@ -184,4 +190,188 @@ func TestExtractFileFromMessage(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+
+func TestSuggestActions(t *testing.T) {
+	var logged []string
+	var input, sent string
+	cmds := NewAICommands(Dependencies{
+		FileContext:   files.NewFileContext(),
+		MessageLogger: func(role, content string) { logged = append(logged, content) },
+		EditSuggestions: []aiops.EditSuggestion{
+			{File: "main.go", Description: "Check the error of Close", Priority: "high"},
+		},
+		SetInput:   func(text string) { input = text },
+		SendPrompt: func(prompt string) tea.Cmd { sent = prompt; return nil },
+	})
+
+	cmds.Suggest([]string{"prompt", "1"})
+	if input != "In main.go: Check the error of Close" {
+		t.Errorf("input = %q", input)
+	}
+
+	cmds.Suggest([]string{"fix", "1"})
+	if !strings.HasPrefix(sent, "Make this change in main.go: Check the error of Close") {
+		t.Errorf("sent prompt = %q", sent)
+	}
+
+	logged = nil
+	cmds.Suggest([]string{"fix", "2"})
+	if len(logged) != 1 || !strings.Contains(logged[0], "1-1") {
+		t.Errorf("expected an invalid number message, got %q", logged)
+	}
+
+	logged = nil
+	cmds.Suggest([]string{"list"})
+	if len(logged) != 1 || !strings.Contains(logged[0], "[high] main.go") {
+		t.Errorf("expected the list, got %q", logged)
+	}
+}
+
+func TestSendChoices(t *testing.T) {
+	var logged []string
+	var sent string
+	var asked int
+	cmds := NewAICommands(Dependencies{
+		APIClient:     &api.Service{},
+		MessageLogger: func(role, content string) { logged = append(logged, content) },
+		SendPrompt:    func(prompt string) tea.Cmd { sent = prompt; return nil },
+		SendChoices:   func(prompt string, n int) tea.Cmd { sent, asked = prompt, n; return nil },
+	})
+
+	tests := []struct {
+		args   []string
+		prompt string
+		n      int
+	}{
+		{[]string{"name", "ideas"}, "name ideas", 0},
+		{[]string{"--n", "3", "name", "ideas"}, "name ideas", 3},
+		{[]string{"--n=2", "name", "ideas"}, "name ideas", 2},
+		{[]string{"--n", "1", "name"}, "name", 0},
+	}
+	for _, tt := range tests {
+		sent, asked = "", 0
+		cmds.Send(tt.args)
+		if sent != tt.prompt || asked != tt.n {
+			t.Errorf("Send(%q) sent %q for %d answers, want %q for %d", tt.args, sent, asked, tt.prompt, tt.n)
+		}
+	}
+
+	for _, args := range [][]string{{"--n", "9", "x"}, {"--n", "x"}, {"--n", "2"}, {}} {
+		logged, sent = nil, ""
+		cmds.Send(args)
+		if sent != "" || len(logged) != 1 {
+			t.Errorf("Send(%q) should only log a usage message, sent %q, logged %q", args, sent, logged)
+		}
+	}
+}
+
+func TestAnalysisRedirect(t *testing.T) {
+	t.Chdir(t.TempDir())
+	for _, name := range []string{"a.go", "b.go"} {
+		if err := os.WriteFile(name, []byte("package main\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var logged []string
+	var analyzed []files.LoadedFile
+	var output string
+	var pending string
+	calls := 0
+	fc := files.NewFileContext()
+	// Handlers are rebuilt for every command, as in the chat
+	analyze := func(args ...string) {
+		NewAICommands(Dependencies{
+			FileContext:   fc,
+			APIClient:     &api.Service{},
+			MessageLogger: func(role, content string) { logged = append(logged, content) },
+			AnalyzeFiles: func(loaded []files.LoadedFile, out string) tea.Cmd {
+				analyzed, output = loaded, out
+				calls++
+				return nil
+			},
+			PendingOverwrite:    pending,
+			SetPendingOverwrite: func(path string) { pending = path },
+		}).Analyze(args)
+	}
+
+	analyze("*.go", ">", "docs/analysis.md")
+	if calls != 1 || len(analyzed) != 2 || output != "docs/analysis.md" {
+		t.Fatalf("expected both files analyzed to docs/analysis.md, got %d call(s), %d file(s), %q", calls, len(analyzed), output)
+	}
+
+	logged = nil
+	analyze(">analysis.md")
+	if calls != 1 || len(logged) != 1 || !strings.Contains(logged[0], "No files loaded") {
+		t.Errorf("expected a no files message without patterns or loaded files, got %q", logged)
+	}
+
+	logged = nil
+	analyze("a.go", ">")
+	if calls != 1 || len(logged) != 1 || !strings.HasPrefix(logged[0], "Usage: /analyze") {
+		t.Errorf("expected the usage for a redirection without a file, got %q", logged)
+	}
+
+	// An existing file is only overwritten when the command is run again
+	os.WriteFile("analysis.md", []byte("old"), 0644)
+	logged = nil
+	analyze("a.go", ">analysis.md")
+	if calls != 1 || len(logged) != 1 || !strings.Contains(logged[0], "already exists") {
+		t.Fatalf("expected an overwrite warning, got %q", logged)
+	}
+	analyze("a.go", ">analysis.md")
+	if calls != 2 || len(analyzed) != 1 || output != "analysis.md" {
+		t.Errorf("expected the repeated command to analyze a.go to analysis.md, got %d call(s), %d file(s), %q", calls, len(analyzed), output)
+	}
+}
+
+func TestDocs(t *testing.T) {
+	t.Chdir(t.TempDir())
+	os.MkdirAll(filepath.Join("pkg", "store"), 0755)
+	os.MkdirAll(filepath.Join("pkg", "empty"), 0755)
+	os.WriteFile(filepath.Join("pkg", "store", "store.go"), []byte("package store\n\nfunc Get() {}\n"), 0644)
+	os.WriteFile(filepath.Join("pkg", "store", "store_test.go"), []byte("package store\n"), 0644)
+	os.WriteFile(filepath.Join("pkg", "store", "README.md"), []byte("# store\n"), 0644)
+
+	var logged []string
+	var documented []aiops.DocPackage
+	cmds := NewAICommands(Dependencies{
+		FileContext:      files.NewFileContext(),
+		APIClient:        &api.Service{},
+		MessageLogger:    func(role, content string) { logged = append(logged, content) },
+		DocumentPackages: func(packages []aiops.DocPackage) tea.Cmd { documented = packages; return nil },
+	})
+
+	if cmd := cmds.Docs([]string{"--check", "./pkg/..."}); cmd == nil {
+		t.Error("expected --check to run go vet")
+	}
+	report := strings.Join(logged, "\n")
+	if !strings.Contains(report, "2 doc convention problem(s) in 1 package(s)") || !strings.Contains(report, "exported function Get should have a comment") {
+		t.Errorf("expected the problems of pkg/store, got:\n%s", report)
+	}
+	if documented != nil || len(cmds.deps.FileContext.Files) != 0 {
+		t.Error("--check should neither load files nor ask for documentation")
+	}
+
+	cmds.Docs([]string{"pkg/store/*.go"})
+	if len(documented) != 1 || documented[0].Dir != filepath.Join("pkg", "store") {
+		t.Fatalf("expected pkg/store to be documented, got %+v", documented)
+	}
+	var names []string
+	for _, file := range documented[0].Files {
+		names = append(names, filepath.Base(file.Path))
+	}
+	if strings.Join(names, ",") != "store.go,README.md" || len(documented[0].Problems) != 2 {
+		t.Errorf("expected store.go and README.md with 2 problems, got %v and %q", names, documented[0].Problems)
+	}
+	if len(cmds.deps.FileContext.Files) != 2 {
+		t.Errorf("expected the files to be loaded for /apply, got %d", len(cmds.deps.FileContext.Files))
+	}
+
+	logged = nil
+	cmds.Docs([]string{"pkg/empty"})
+	if len(logged) != 1 || !strings.Contains(logged[0], "no Go packages match") {
+		t.Errorf("expected no packages for a directory without Go files, got %q", logged)
+	}
+}
@@ -14,15 +14,26 @@
 package commands
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/antenore/deecli/internal/api"
 	"github.com/antenore/deecli/internal/config"
+	"github.com/antenore/deecli/internal/errlog"
+	"github.com/antenore/deecli/internal/i18n"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
+// verifyTimeout bounds the /config verify request
+const verifyTimeout = 30 * time.Second
+
 // ConfigCommands handles configuration-related chat commands
 type ConfigCommands struct {
 	deps Dependencies
@@ -41,10 +52,50 @@ func (cc *ConfigCommands) Config(args []string) tea.Cmd {
 		return nil
 	}
 
+	if args[0] == "verify" {
+		return cc.verifyConfig()
+	}
 	cc.handleConfigCommand(args)
 	return nil
 }
 
+// verifyConfig sends a 1-token request in the background and reports the
+// latency, the model that answered and whether the API key was accepted
+func (cc *ConfigCommands) verifyConfig() tea.Cmd {
+	if cc.deps.APIClient == nil {
+		cc.configError("No API key configured. Set DEEPSEEK_API_KEY or run /config set api-key <key>")
+		return nil
+	}
+
+	client := cc.deps.APIClient
+	cc.deps.MessageLogger("system", "🔑 Verifying API key and model...")
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), verifyTimeout)
+		defer cancel()
+		result, err := client.Verify(ctx)
+		if err != nil {
+			return CommandResultMsg{Message: verifyFailure(err), Err: err, Category: errlog.Classify(err)}
+		}
+		return CommandResultMsg{Message: fmt.Sprintf("✅ API key accepted · model %s · %dms",
+			result.Model, result.Latency.Milliseconds())}
+	}
+}
+
+// verifyFailure summarizes a failed verification, telling authentication
+// problems apart from the rest
+func verifyFailure(err error) string {
+	var apiErr api.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.StatusCode {
+		case 401:
+			return "API key rejected (401): check DEEPSEEK_API_KEY or /config set api-key"
+		case 403:
+			return "API key not allowed to use this model (403)"
+		}
+	}
+	return "Verification failed; the API key could not be checked"
+}
+
 // KeySetup handles the /keysetup command
 func (cc *ConfigCommands) KeySetup(args []string) tea.Cmd {
 	if len(args) > 0 {
@@ -76,7 +127,7 @@ func (cc *ConfigCommands) History(args []string) tea.Cmd {
 			cc.deps.InputHistory = cc.deps.InputHistory[:0] // Clear slice
 			if cc.deps.HistoryManager != nil {
 				if err := cc.deps.HistoryManager.Clear(); err != nil {
-					cc.deps.MessageLogger("system", fmt.Sprintf("❌ Failed to clear persistent history: %v", err))
+					cc.configError(fmt.Sprintf("Failed to clear persistent history: %v", err))
 				} else {
 					cc.deps.MessageLogger("system", "✅ History cleared (both in-memory and persistent)")
 				}
@@ -85,9 +136,15 @@ func (cc *ConfigCommands) History(args []string) tea.Cmd {
 			}
 		case "show", "list":
 			cc.deps.ShowHistory()
+		case "search":
+			if len(args) > 1 && args[1] == "--all" {
+				cc.searchHistory(strings.Join(args[2:], " "), true)
+			} else {
+				cc.searchHistory(strings.Join(args[1:], " "), false)
+			}
 		default:
 			cc.deps.MessageLogger("system", "Unknown history command: "+subCmd)
-			cc.deps.MessageLogger("system", "Usage: /history [show|clear]")
+			cc.deps.MessageLogger("system", "Usage: /history [show|clear|search [--all] <term>]")
 		}
 	} else {
 		// No argument - show history
@@ -96,6 +153,201 @@ func (cc *ConfigCommands) History(args []string) tea.Cmd {
 	return nil
 }
 
+// searchHistory lists the persisted history entries containing term, from
+// the current project or, with all, from every project
+func (cc *ConfigCommands) searchHistory(term string, all bool) {
+	if term == "" {
+		cc.deps.MessageLogger("system", "Usage: /history search [--all] <term>")
+		return
+	}
+	if cc.deps.HistoryManager == nil {
+		cc.configError("Persistent history not available")
+		return
+	}
+	search := cc.deps.HistoryManager.Search
+	if all {
+		search = cc.deps.HistoryManager.SearchAll
+	}
+	matches, err := search(term)
+	if err != nil {
+		cc.configError(fmt.Sprintf("Failed to search history: %v", err))
+		return
+	}
+	if len(matches) == 0 {
+		cc.deps.MessageLogger("system", fmt.Sprintf("No history entries match %q", term))
+		return
+	}
+
+	var list strings.Builder
+	list.WriteString(fmt.Sprintf("📜 History entries matching %q (most recent first):", term))
+	for i, entry := range matches {
+		if i == 20 {
+			list.WriteString(fmt.Sprintf("\n  ... %d more, refine the search to see them", len(matches)-i))
+			break
+		}
+		command := strings.ReplaceAll(entry.Command, "\n", " ")
+		if len(command) > 80 {
+			command = command[:77] + "..."
+		}
+		list.WriteString(fmt.Sprintf("\n  %s  %s", entry.Timestamp.Format("2006-01-02 15:04"), command))
+		if all && entry.Project != "" {
+			list.WriteString(fmt.Sprintf("  (%s)", filepath.Base(entry.Project)))
+		}
+	}
+	cc.deps.MessageLogger("system", list.String())
+}
+
+// Mode handles the /mode command, which picks a temperature preset
+func (cc *ConfigCommands) Mode(args []string) tea.Cmd {
+	if cc.deps.ConfigManager == nil {
+		cc.configError("Configuration manager not available")
+		return nil
+	}
+	if len(args) == 0 {
+		cc.showModes()
+		return nil
+	}
+
+	name := strings.ToLower(args[0])
+	if err := config.ValidateMode(name); err != nil {
+		cc.configError(err.Error())
+		cc.deps.MessageLogger("system", "Usage: /mode <coding|general|creative|off> [--prompt|--no-prompt] [--global|--project]")
+		return nil
+	}
+
+	if err := cc.deps.ConfigManager.Load(); err != nil {
+		cc.configError(fmt.Sprintf("Failed to load configuration: %v", err))
+		return nil
+	}
+	newCfg := *cc.deps.ConfigManager.Get()
+	newCfg.Mode = name
+
+	scope := ""
+	for _, flag := range args[1:] {
+		switch flag {
+		case "--prompt":
+			newCfg.ModePrompt = true
+		case "--no-prompt":
+			newCfg.ModePrompt = false
+		case "--global":
+			scope = "global"
+		case "--project":
+			scope = "project"
+		}
+	}
+
+	if preset, ok := config.LookupMode(name); ok {
+		// Keep the saved temperature in line with the preset
+		newCfg.Temperature = preset.Temperature
+		msg := fmt.Sprintf("✅ Mode set to: %s (temperature %.1f)", preset.Name, preset.Temperature)
+		if newCfg.ModePrompt {
+			msg += " with its system prompt"
+		}
+		cc.deps.MessageLogger("system", msg)
+	} else {
+		newCfg.ModePrompt = false
+		cc.deps.MessageLogger("system", fmt.Sprintf("✅ Mode turned off, temperature stays at %.2f", newCfg.Temperature))
+	}
+	cc.saveConfig(&newCfg, scope)
+	return nil
+}
+
+// showModes lists the temperature presets and marks the active one
+func (cc *ConfigCommands) showModes() {
+	active, hasMode := cc.deps.ConfigManager.GetMode()
+	cc.deps.MessageLogger("system", "🎛️ Modes:")
+	for _, preset := range config.ModePresets {
+		marker := "  "
+		if hasMode && preset.Name == active.Name {
+			marker = "▶ "
+		}
+		cc.deps.MessageLogger("system", fmt.Sprintf("%s%-9s temperature %.1f  %s", marker, preset.Name, preset.Temperature, preset.Description))
+	}
+	if !hasMode {
+		cc.deps.MessageLogger("system", fmt.Sprintf("No mode set, temperature is %.2f", cc.deps.ConfigManager.GetTemperature()))
+	} else if cc.deps.ConfigManager.GetModePrompt() != "" {
+		cc.deps.MessageLogger("system", "The mode's system prompt is enabled")
+	}
+	cc.deps.MessageLogger("system", "Usage: /mode <coding|general|creative|off> [--prompt|--no-prompt] [--global|--project]")
+}
+
+// PostProcess handles the /postprocess command, which turns the response
+// post-processors on and off
+func (cc *ConfigCommands) PostProcess(args []string) tea.Cmd {
+	if cc.deps.ConfigManager == nil {
+		cc.configError("Configuration manager not available")
+		return nil
+	}
+	if len(args) == 0 {
+		cc.showPostProcessors()
+		return nil
+	}
+
+	usage := "Usage: /postprocess <name> <on|off> [--global|--project]"
+	name := strings.ToLower(args[0])
+	if err := config.ValidatePostProcessor(name); err != nil {
+		cc.configError(err.Error())
+		cc.deps.MessageLogger("system", usage)
+		return nil
+	}
+	if len(args) < 2 {
+		cc.deps.MessageLogger("system", usage)
+		return nil
+	}
+	var enabled bool
+	switch args[1] {
+	case "on", "true", "yes", "1":
+		enabled = true
+	case "off", "false", "no", "0":
+		enabled = false
+	default:
+		cc.configError(fmt.Sprintf("Invalid value: %s (use on/off)", args[1]))
+		return nil
+	}
+
+	if err := cc.deps.ConfigManager.Load(); err != nil {
+		cc.configError(fmt.Sprintf("Failed to load configuration: %v", err))
+		return nil
+	}
+	newCfg := *cc.deps.ConfigManager.Get()
+	newCfg.PostProcessors = maps.Clone(newCfg.PostProcessors)
+	if newCfg.PostProcessors == nil {
+		newCfg.PostProcessors = make(map[string]bool)
+	}
+	newCfg.PostProcessors[name] = enabled
+
+	scope := ""
+	for _, flag := range args[2:] {
+		switch flag {
+		case "--global":
+			scope = "global"
+		case "--project":
+			scope = "project"
+		}
+	}
+
+	state := "off"
+	if enabled {
+		state = "on"
+	}
+	cc.deps.MessageLogger("system", fmt.Sprintf("✅ Post-processor %s turned %s", name, state))
+	cc.saveConfig(&newCfg, scope)
+	return nil
+}
+
+// showPostProcessors lists the response post-processors in the order they run
+func (cc *ConfigCommands) showPostProcessors() {
+	cc.deps.MessageLogger("system", "🧹 Response post-processors, in order:")
+	for _, processor := range config.PostProcessors {
+		state := "off"
+		if cc.deps.ConfigManager.PostProcessorEnabled(processor.Name) {
+			state = "on "
+		}
+		cc.deps.MessageLogger("system", fmt.Sprintf("  %s  %-20s %s", state, processor.Name, processor.Description))
+	}
+	cc.deps.MessageLogger("system", "Usage: /postprocess <name> <on|off> [--global|--project]")
+}
+
 // handleConfigCommand processes specific config subcommands
 func (cc *ConfigCommands) handleConfigCommand(args []string) {
 	if len(args) == 0 {
@@ -113,6 +365,12 @@ func (cc *ConfigCommands) handleConfigCommand(args []string) {
 			cc.deps.MessageLogger("system", "Keys: api-key, model, user-name, temperature, max-tokens, auto-reload-files, auto-reload-debounce, show-reload-notices")
 			return
 		}
+		if args[1] == "editor-cmd" || args[1] == "editor-goto-cmd" {
+			// Command templates have spaces: the value is every argument but the flags
+			value, flags := splitFlags(args[2:])
+			cc.handleConfigSet(args[1], strings.Join(value, " "), flags)
+			return
+		}
 		cc.handleConfigSet(args[1], args[2], args[3:])
 	case "get":
 		if len(args) < 2 {
@@ -121,6 +379,12 @@ func (cc *ConfigCommands) handleConfigCommand(args []string) {
 			return
 		}
 		cc.handleConfigGet(args[1])
+	case "edit":
+		if cc.deps.ConfigManager == nil || cc.deps.OpenConfigEditor == nil {
+			cc.configError("Configuration manager not available")
+			return
+		}
+		cc.deps.OpenConfigEditor()
 	case "editor":
 		cc.handleEditorConfig(args[1:])
 	case "model":
@@ -152,6 +416,20 @@ func (cc *ConfigCommands) handleConfigCommand(args []string) {
 	}
 }
 
+// splitFlags separates the --global and --project flags of /config set
+// from the other arguments
+func splitFlags(args []string) ([]string, []string) {
+	var values, flags []string
+	for _, arg := range args {
+		if arg == "--global" || arg == "--project" {
+			flags = append(flags, arg)
+		} else {
+			values = append(values, arg)
+		}
+	}
+	return values, flags
+}
+
 // showConfig displays current configuration
 func (cc *ConfigCommands) showConfig() {
 	cc.deps.MessageLogger("system", "📋 Current Configuration:")
@@ -192,6 +470,9 @@ func (cc *ConfigCommands) showConfig() {
 		cc.deps.MessageLogger("system", fmt.Sprintf("  Model: %s", cfg.Model))
 		cc.deps.MessageLogger("system", fmt.Sprintf("  Temperature: %.2f", cfg.Temperature))
 		cc.deps.MessageLogger("system", fmt.Sprintf("  Max Tokens: %d", cfg.MaxTokens))
+		info := config.LookupModel(cfg.Model)
+		cc.deps.MessageLogger("system", fmt.Sprintf("  Model Limits: %d context, %d output tokens", info.ContextWindow, info.MaxOutputTokens))
+		cc.deps.MessageLogger("system", fmt.Sprintf("  Max Context Size: %d bytes", cc.deps.ConfigManager.GetMaxContextSize()))
 		cc.deps.MessageLogger("system", "")
 		cc.deps.MessageLogger("system", "File Auto-Reload:")
 		cc.deps.MessageLogger("system", fmt.Sprintf("  Enabled: %t", cfg.AutoReloadFiles))
@@ -238,7 +519,7 @@ func (cc *ConfigCommands) handleEditorConfig(args []string) {
 	}
 
 	if len(editorParts) == 0 {
-		cc.deps.MessageLogger("system", "❌ Editor name required")
+		cc.configError("Editor name required")
 		return
 	}
 
@@ -343,7 +624,7 @@ func (cc *ConfigCommands) handleConfigInit() {
 // handleConfigSet sets a configuration value
 func (cc *ConfigCommands) handleConfigSet(key, value string, flags []string) {
 	if cc.deps.ConfigManager == nil {
-		cc.deps.MessageLogger("system", "❌ Configuration manager not available")
+		cc.configError("Configuration manager not available")
 		return
 	}
 
@@ -359,7 +640,7 @@ func (cc *ConfigCommands) handleConfigSet(key, value string, flags []string) {
 
 	// Load current config
 	if err := cc.deps.ConfigManager.Load(); err != nil {
-		cc.deps.MessageLogger("system", fmt.Sprintf("❌ Failed to load configuration: %v", err))
+		cc.configError(fmt.Sprintf("Failed to load configuration: %v", err))
 		return
 	}
 
@@ -372,7 +653,7 @@ func (cc *ConfigCommands) handleConfigSet(key, value string, flags []string) {
 	case "api-key":
 		// Validate API key before setting
 		if err := config.ValidateAPIKey(value); err != nil {
-			cc.deps.MessageLogger("system", fmt.Sprintf("❌ %v", err))
+			cc.configError(err.Error())
 			cc.deps.MessageLogger("system", "   DeepSeek API keys should start with 'sk-'")
 			return
 		}
@@ -387,38 +668,47 @@ func (cc *ConfigCommands) handleConfigSet(key, value string, flags []string) {
 	case "model":
 		// Validate model before setting
 		if err := config.ValidateModel(value); err != nil {
-			cc.deps.MessageLogger("system", fmt.Sprintf("❌ %v", err))
+			cc.configError(err.Error())
 			return
 		}
 		newCfg.Model = value
 		cc.deps.MessageLogger("system", fmt.Sprintf("✅ Model set to: %s", value))
+		if limit := config.LookupModel(value).MaxOutputTokens; newCfg.MaxTokens > limit {
+			cc.deps.MessageLogger("system", fmt.Sprintf("   ⚠️ max-tokens %d exceeds this model's maximum; %d will be used", newCfg.MaxTokens, limit))
+		}
 
 	case "temperature":
 		var temp float64
 		if _, err := fmt.Sscanf(value, "%f", &temp); err != nil {
-			cc.deps.MessageLogger("system", fmt.Sprintf("❌ Invalid temperature value: %s", value))
+			cc.configError(fmt.Sprintf("Invalid temperature value: %s", value))
 			cc.deps.MessageLogger("system", "   Temperature should be a decimal number (e.g., 0.7)")
 			return
 		}
 		if err := config.ValidateTemperature(temp); err != nil {
-			cc.deps.MessageLogger("system", fmt.Sprintf("❌ %v", err))
+			cc.configError(err.Error())
 			cc.deps.MessageLogger("system", "   Lower values (0.0-0.5) = more focused, deterministic")
 			cc.deps.MessageLogger("system", "   Higher values (0.5-2.0) = more creative, varied")
 			return
 		}
 		newCfg.Temperature = temp
 		cc.deps.MessageLogger("system", fmt.Sprintf("✅ Temperature set to: %.2f", temp))
+		if _, ok := config.LookupMode(newCfg.Mode); ok {
+			// The preset would otherwise keep overriding the temperature
+			newCfg.Mode = config.ModeOff
+			cc.deps.MessageLogger("system", "   Mode preset turned off")
+		}
 
 	case "max-tokens":
 		var tokens int
 		if _, err := fmt.Sscanf(value, "%d", &tokens); err != nil {
-			cc.deps.MessageLogger("system", fmt.Sprintf("❌ Invalid max-tokens value: %s", value))
+			cc.configError(fmt.Sprintf("Invalid max-tokens value: %s", value))
 			cc.deps.MessageLogger("system", "   Max tokens should be a positive integer")
 			return
 		}
-		if err := config.ValidateMaxTokens(tokens); err != nil {
-			cc.deps.MessageLogger("system", fmt.Sprintf("❌ %v", err))
-			cc.deps.MessageLogger("system", "   Limits: deepseek-chat (max 8192), deepseek-reasoner (max 65536)")
+		if err := config.ValidateMaxTokensForModel(newCfg.Model, tokens); err != nil {
+			cc.configError(err.Error())
+			cc.deps.MessageLogger("system", fmt.Sprintf("   Limits: deepseek-chat (max %d), deepseek-reasoner (max %d)",
+				config.LookupModel("deepseek-chat").MaxOutputTokens, config.LookupModel("deepseek-reasoner").MaxOutputTokens))
 			cc.deps.MessageLogger("system", "   Recommended: 8192 for chat, 32768 for reasoner")
 			return
 		}
@@ -427,7 +717,7 @@ func (cc *ConfigCommands) handleConfigSet(key, value string, flags []string) {
 
 	case "user-name":
 		if err := config.ValidateUserName(value); err != nil {
-			cc.deps.MessageLogger("system", fmt.Sprintf("❌ %v", err))
+			cc.configError(err.Error())
 			return
 		}
 		newCfg.UserName = value
@@ -440,7 +730,7 @@ func (cc *ConfigCommands) handleConfigSet(key, value string, flags []string) {
 		} else if value == "false" || value == "0" || value == "no" || value == "off" {
 			enabled = false
 		} else {
-			cc.deps.MessageLogger("system", fmt.Sprintf("❌ Invalid auto-reload-files value: %s (use true/false)", value))
+			cc.configError(fmt.Sprintf("Invalid auto-reload-files value: %s (use true/false)", value))
 			return
 		}
 		newCfg.AutoReloadFiles = enabled
@@ -449,12 +739,12 @@ func (cc *ConfigCommands) handleConfigSet(key, value string, flags []string) {
 	case "auto-reload-debounce":
 		var debounce int
 		if _, err := fmt.Sscanf(value, "%d", &debounce); err != nil {
-			cc.deps.MessageLogger("system", fmt.Sprintf("❌ Invalid auto-reload-debounce value: %s", value))
+			cc.configError(fmt.Sprintf("Invalid auto-reload-debounce value: %s", value))
 			cc.deps.MessageLogger("system", "   Debounce should be a number in milliseconds")
 			return
 		}
 		if err := config.ValidateAutoReloadDebounce(debounce); err != nil {
-			cc.deps.MessageLogger("system", fmt.Sprintf("❌ %v", err))
+			cc.configError(err.Error())
 			cc.deps.MessageLogger("system", "   Recommended: 100-500ms for most editors")
 			return
 		}
@@ -468,34 +758,405 @@ func (cc *ConfigCommands) handleConfigSet(key, value string, flags []string) {
 		} else if value == "false" || value == "0" || value == "no" || value == "off" {
 			show = false
 		} else {
-			cc.deps.MessageLogger("system", fmt.Sprintf("❌ Invalid show-reload-notices value: %s (use true/false)", value))
+			cc.configError(fmt.Sprintf("Invalid show-reload-notices value: %s (use true/false)", value))
 			return
 		}
 		newCfg.ShowReloadNotices = show
 		cc.deps.MessageLogger("system", fmt.Sprintf("✅ Show reload notices set to: %t", show))
 
+	case "redact-secrets":
+		var enabled bool
+		if value == "true" || value == "1" || value == "yes" || value == "on" {
+			enabled = true
+		} else if value == "false" || value == "0" || value == "no" || value == "off" {
+			enabled = false
+		} else {
+			cc.configError(fmt.Sprintf("Invalid redact-secrets value: %s (use true/false)", value))
+			return
+		}
+		newCfg.RedactSecrets = enabled
+		cc.deps.MessageLogger("system", fmt.Sprintf("✅ Redact secrets set to: %t", enabled))
+		cc.deps.MessageLogger("system", "   Restart the chat session to apply")
+
+	case "notify-on-complete":
+		if err := config.ValidateNotifyOnComplete(value); err != nil {
+			cc.configError(err.Error())
+			return
+		}
+		newCfg.NotifyOnComplete = value
+		cc.deps.MessageLogger("system", fmt.Sprintf("✅ Notify on complete set to: %s", value))
+
+	case "message-timestamps":
+		if err := config.ValidateMessageTimestamps(value); err != nil {
+			cc.configError(err.Error())
+			return
+		}
+		newCfg.MessageTimestamps = value
+		cc.deps.MessageLogger("system", fmt.Sprintf("✅ Message timestamps set to: %s (new messages)", value))
+
+	case "sidebar-sort":
+		if err := config.ValidateSidebarSort(value); err != nil {
+			cc.configError(err.Error())
+			return
+		}
+		newCfg.SidebarSort = value
+		cc.deps.MessageLogger("system", fmt.Sprintf("✅ Sidebar sort set to: %s", value))
+
+	case "sidebar-group":
+		var enabled bool
+		if value == "true" || value == "1" || value == "yes" || value == "on" {
+			enabled = true
+		} else if value == "false" || value == "0" || value == "no" || value == "off" {
+			enabled = false
+		} else {
+			cc.configError(fmt.Sprintf("Invalid sidebar-group value: %s (use true/false)", value))
+			return
+		}
+		newCfg.SidebarGroup = enabled
+		cc.deps.MessageLogger("system", fmt.Sprintf("✅ Sidebar grouping by directory set to: %t", enabled))
+
+	case "plain-mode":
+		var enabled bool
+		if value == "true" || value == "1" || value == "yes" || value == "on" {
+			enabled = true
+		} else if value == "false" || value == "0" || value == "no" || value == "off" {
+			enabled = false
+		} else {
+			cc.configError(fmt.Sprintf("Invalid plain-mode value: %s (use true/false)", value))
+			return
+		}
+		newCfg.PlainMode = enabled
+		cc.deps.MessageLogger("system", fmt.Sprintf("✅ Plain mode set to: %t", enabled))
+		cc.deps.MessageLogger("system", "   Applied on the next terminal resize or session")
+
+	case "plain-mode-width":
+		var width int
+		if _, err := fmt.Sscanf(value, "%d", &width); err != nil {
+			cc.configError(fmt.Sprintf("Invalid plain-mode-width value: %s", value))
+			cc.deps.MessageLogger("system", "   Width should be a number of columns (negative disables)")
+			return
+		}
+		if err := config.ValidatePlainModeWidth(width); err != nil {
+			cc.configError(err.Error())
+			return
+		}
+		newCfg.PlainModeWidth = width
+		cc.deps.MessageLogger("system", fmt.Sprintf("✅ Plain mode width set to: %d", width))
+
+	case "screen-reader":
+		var enabled bool
+		if value == "true" || value == "1" || value == "yes" || value == "on" {
+			enabled = true
+		} else if value == "false" || value == "0" || value == "no" || value == "off" {
+			enabled = false
+		} else {
+			cc.configError(fmt.Sprintf("Invalid screen-reader value: %s (use true/false)", value))
+			return
+		}
+		newCfg.ScreenReader = enabled
+		cc.deps.MessageLogger("system", fmt.Sprintf("✅ Screen reader mode set to: %t", enabled))
+		cc.deps.MessageLogger("system", "   Restart the chat session to apply")
+
+	case "extract-text":
+		var enabled bool
+		if value == "true" || value == "1" || value == "yes" || value == "on" {
+			enabled = true
+		} else if value == "false" || value == "0" || value == "no" || value == "off" {
+			enabled = false
+		} else {
+			cc.configError(fmt.Sprintf("Invalid extract-text value: %s (use true/false)", value))
+			return
+		}
+		newCfg.ExtractText = enabled
+		if cc.deps.FileContext != nil {
+			cc.deps.FileContext.Loader.ExtractText = enabled
+		}
+		cc.deps.MessageLogger("system", fmt.Sprintf("✅ Text extraction set to: %t", enabled))
+
+	case "lazy-load-threshold":
+		var threshold int
+		if _, err := fmt.Sscanf(value, "%d", &threshold); err != nil {
+			cc.configError(fmt.Sprintf("Invalid lazy-load-threshold value: %s", value))
+			cc.deps.MessageLogger("system", "   Threshold should be a number of files (negative disables)")
+			return
+		}
+		newCfg.LazyLoadThreshold = threshold
+		if cc.deps.FileContext != nil {
+			cc.deps.FileContext.LazyThreshold = max(threshold, 0)
+		}
+		cc.deps.MessageLogger("system", fmt.Sprintf("✅ Lazy load threshold set to: %d", threshold))
+
+	case "scrollback-limit":
+		var limit int
+		if _, err := fmt.Sscanf(value, "%d", &limit); err != nil {
+			cc.configError(fmt.Sprintf("Invalid scrollback-limit value: %s", value))
+			cc.deps.MessageLogger("system", "   Limit should be a number of messages (negative disables)")
+			return
+		}
+		newCfg.ScrollbackLimit = limit
+		cc.deps.MessageLogger("system", fmt.Sprintf("✅ Scrollback limit set to: %d messages", limit))
+
+	case "tool-calls-per-turn", "tool-calls-per-minute":
+		var limit int
+		if _, err := fmt.Sscanf(value, "%d", &limit); err != nil {
+			cc.configError(fmt.Sprintf("Invalid %s value: %s", key, value))
+			cc.deps.MessageLogger("system", "   Limit should be a number of tool calls (negative disables)")
+			return
+		}
+		if key == "tool-calls-per-turn" {
+			newCfg.ToolCallsPerTurn = limit
+		} else {
+			newCfg.ToolCallsPerMinute = limit
+		}
+		cc.deps.MessageLogger("system", fmt.Sprintf("✅ %s set to: %d", key, limit))
+
+	case "stream-stall-timeout":
+		var seconds int
+		if _, err := fmt.Sscanf(value, "%d", &seconds); err != nil {
+			cc.configError(fmt.Sprintf("Invalid stream-stall-timeout value: %s", value))
+			cc.deps.MessageLogger("system", "   Timeout should be a number of seconds (negative disables)")
+			return
+		}
+		newCfg.StreamStallTimeout = seconds
+		cc.deps.MessageLogger("system", fmt.Sprintf("✅ Stream stall timeout set to: %d seconds", seconds))
+
+	case "stream-auto-reconnect":
+		var enabled bool
+		if value == "true" || value == "1" || value == "yes" || value == "on" {
+			enabled = true
+		} else if value == "false" || value == "0" || value == "no" || value == "off" {
+			enabled = false
+		} else {
+			cc.configError(fmt.Sprintf("Invalid stream-auto-reconnect value: %s (use true/false)", value))
+			return
+		}
+		newCfg.StreamAutoReconnect = enabled
+		cc.deps.MessageLogger("system", fmt.Sprintf("✅ Stream auto-reconnect set to: %t", enabled))
+
+	case "warm-up-on-start":
+		var enabled bool
+		if value == "true" || value == "1" || value == "yes" || value == "on" {
+			enabled = true
+		} else if value == "false" || value == "0" || value == "no" || value == "off" {
+			enabled = false
+		} else {
+			cc.configError(fmt.Sprintf("Invalid warm-up-on-start value: %s (use true/false)", value))
+			return
+		}
+		newCfg.WarmUpOnStart = enabled
+		cc.deps.MessageLogger("system", fmt.Sprintf("✅ Warm-up on start set to: %t (applies from the next session)", enabled))
+
+	case "check-updates":
+		var enabled bool
+		if value == "true" || value == "1" || value == "yes" || value == "on" {
+			enabled = true
+		} else if value == "false" || value == "0" || value == "no" || value == "off" {
+			enabled = false
+		} else {
+			cc.configError(fmt.Sprintf("Invalid check-updates value: %s (use true/false)", value))
+			return
+		}
+		newCfg.CheckUpdates = enabled
+		cc.deps.MessageLogger("system", fmt.Sprintf("✅ Check for updates set to: %t (applies from the next session)", enabled))
+
+	case "telemetry":
+		if err := config.ValidateTelemetry(value); err != nil {
+			cc.configError(err.Error())
+			return
+		}
+		newCfg.Telemetry = value
+		cc.deps.MessageLogger("system", fmt.Sprintf("✅ Telemetry set to: %s (applies from the next session)", value))
+
+	case "proxy":
+		if err := config.ValidateProxy(value); err != nil {
+			cc.configError(err.Error())
+			return
+		}
+		newCfg.Proxy = value
+		cc.deps.MessageLogger("system", fmt.Sprintf("✅ Proxy set to: %s (applies from the next session)", value))
+
+	case "ca-bundle":
+		if _, err := os.Stat(value); err != nil {
+			cc.configError(fmt.Sprintf("Cannot read CA bundle: %v", err))
+			return
+		}
+		newCfg.CABundle = value
+		cc.deps.MessageLogger("system", fmt.Sprintf("✅ CA bundle set to: %s (applies from the next session)", value))
+
+	case "insecure-skip-verify":
+		var enabled bool
+		if value == "true" || value == "1" || value == "yes" || value == "on" {
+			enabled = true
+		} else if value == "false" || value == "0" || value == "no" || value == "off" {
+			enabled = false
+		} else {
+			cc.configError(fmt.Sprintf("Invalid insecure-skip-verify value: %s (use true/false)", value))
+			return
+		}
+		newCfg.InsecureSkipVerify = enabled
+		cc.deps.MessageLogger("system", fmt.Sprintf("✅ Insecure skip verify set to: %t (applies from the next session)", enabled))
+		if enabled {
+			cc.deps.MessageLogger("system", "⚠️ WARNING: TLS certificates will not be verified. Anyone on the network path can read and alter API traffic, including your API key. Prefer ca-bundle.")
+		}
+
+	case "request-metrics":
+		var enabled bool
+		if value == "true" || value == "1" || value == "yes" || value == "on" {
+			enabled = true
+		} else if value == "false" || value == "0" || value == "no" || value == "off" {
+			enabled = false
+		} else {
+			cc.configError(fmt.Sprintf("Invalid request-metrics value: %s (use true/false)", value))
+			return
+		}
+		newCfg.RequestMetrics = enabled
+		cc.deps.MessageLogger("system", fmt.Sprintf("✅ Request metrics set to: %t (applies from the next session)", enabled))
+
+	case "editor-context":
+		var enabled bool
+		if value == "true" || value == "1" || value == "yes" || value == "on" {
+			enabled = true
+		} else if value == "false" || value == "0" || value == "no" || value == "off" {
+			enabled = false
+		} else {
+			cc.configError(fmt.Sprintf("Invalid editor-context value: %s (use true/false)", value))
+			return
+		}
+		newCfg.EditorContext = enabled
+		cc.deps.MessageLogger("system", fmt.Sprintf("✅ Editor context set to: %t", enabled))
+
+	case "history-max-entries":
+		var entries int
+		if _, err := fmt.Sscanf(value, "%d", &entries); err != nil {
+			cc.configError(fmt.Sprintf("Invalid history-max-entries value: %s", value))
+			return
+		}
+		if err := config.ValidateHistoryMaxEntries(entries); err != nil {
+			cc.configError(fmt.Sprintf("Invalid history-max-entries: %v", err))
+			return
+		}
+		newCfg.HistoryMaxEntries = entries
+		cc.deps.MessageLogger("system", fmt.Sprintf("✅ History size limit set to: %d entries", entries))
+
+	case "large-file-threshold":
+		var threshold int
+		if _, err := fmt.Sscanf(value, "%d", &threshold); err != nil {
+			cc.configError(fmt.Sprintf("Invalid large-file-threshold value: %s", value))
+			cc.deps.MessageLogger("system", "   Threshold should be a size in KB (negative disables)")
+			return
+		}
+		newCfg.LargeFileThreshold = threshold
+		if cc.deps.FileContext != nil {
+			cc.deps.FileContext.Loader.PreviewThreshold = int64(max(threshold, 0)) * 1024
+		}
+		cc.deps.MessageLogger("system", fmt.Sprintf("✅ Large file threshold set to: %d KB", threshold))
+
+	case "large-file-preview":
+		var preview int
+		if _, err := fmt.Sscanf(value, "%d", &preview); err != nil || preview <= 0 {
+			cc.configError(fmt.Sprintf("Invalid large-file-preview value: %s", value))
+			cc.deps.MessageLogger("system", "   Preview should be a positive size in KB")
+			return
+		}
+		newCfg.LargeFilePreview = preview
+		if cc.deps.FileContext != nil {
+			cc.deps.FileContext.Loader.PreviewSize = int64(preview) * 1024
+		}
+		cc.deps.MessageLogger("system", fmt.Sprintf("✅ Large file preview set to: %d KB", preview))
+
+	case "max-file-size", "max-files-per-load", "max-loaded-files":
+		var limit int
+		if _, err := fmt.Sscanf(value, "%d", &limit); err != nil {
+			cc.configError(fmt.Sprintf("Invalid %s value: %s", key, value))
+			if key == "max-file-size" {
+				cc.deps.MessageLogger("system", "   Size should be a number of KB (0 for the default)")
+			} else {
+				cc.deps.MessageLogger("system", "   Limit should be a number of files (0 for the default)")
+			}
+			return
+		}
+		switch key {
+		case "max-file-size":
+			newCfg.MaxFileSize = limit
+		case "max-files-per-load":
+			newCfg.MaxFilesPerLoad = limit
+		default:
+			newCfg.MaxLoadedFiles = limit
+		}
+		if err := config.ValidateFileLimits(newCfg.MaxFileSize, newCfg.MaxFilesPerLoad, newCfg.MaxLoadedFiles); err != nil {
+			cc.configError(fmt.Sprintf("Invalid %s: %v", key, err))
+			return
+		}
+		cc.deps.MessageLogger("system", fmt.Sprintf("✅ %s set to: %d", key, limit))
+
+	case "instruction-file-size":
+		var size int
+		if _, err := fmt.Sscanf(value, "%d", &size); err != nil {
+			cc.configError(fmt.Sprintf("Invalid instruction-file-size value: %s", value))
+			cc.deps.MessageLogger("system", "   Size should be a number of KB (negative disables instruction files)")
+			return
+		}
+		newCfg.InstructionFileSize = size
+		cc.deps.MessageLogger("system", fmt.Sprintf("✅ Instruction file size set to: %d KB", size))
+
+	case "editor-cmd", "editor-goto-cmd":
+		if value == "default" || value == "\"\"" {
+			value = ""
+		}
+		if err := config.ValidateEditorCommand(value); err != nil {
+			cc.configError(err.Error())
+			cc.deps.MessageLogger("system", fmt.Sprintf("   Placeholders: %s", strings.Join(config.EditorPlaceholders, ", ")))
+			return
+		}
+		if key == "editor-cmd" {
+			newCfg.EditorCmd = value
+		} else {
+			newCfg.EditorGotoCmd = value
+		}
+		if value == "" {
+			cc.deps.MessageLogger("system", fmt.Sprintf("✅ %s reset to the built-in command of the editor", key))
+		} else {
+			cc.deps.MessageLogger("system", fmt.Sprintf("✅ %s set to: %s", key, value))
+		}
+
+	case "language":
+		if err := config.ValidateLanguage(value); err != nil {
+			cc.configError(i18n.T("config.language_invalid", value))
+			cc.deps.MessageLogger("system", fmt.Sprintf("   Valid values: %s, %s", i18n.Auto, strings.Join(i18n.Supported(), ", ")))
+			return
+		}
+		newCfg.Language = value
+		i18n.SetLanguage(value)
+		cc.deps.MessageLogger("system", i18n.T("config.language_set", i18n.Language()))
+
 	default:
-		cc.deps.MessageLogger("system", fmt.Sprintf("❌ Unknown config key: %s", key))
-		cc.deps.MessageLogger("system", "Valid keys: api-key, model, temperature, max-tokens, auto-reload-files, auto-reload-debounce, show-reload-notices")
+		cc.configError(fmt.Sprintf("Unknown config key: %s", key))
+		cc.deps.MessageLogger("system", "Valid keys: api-key, model, temperature, max-tokens, auto-reload-files, auto-reload-debounce, show-reload-notices, redact-secrets, notify-on-complete, plain-mode, plain-mode-width, screen-reader, extract-text, language, history-max-entries, lazy-load-threshold, large-file-threshold, large-file-preview, max-file-size, max-files-per-load, max-loaded-files, scrollback-limit, tool-calls-per-turn, tool-calls-per-minute, stream-stall-timeout, stream-auto-reconnect, message-timestamps, sidebar-sort, sidebar-group, warm-up-on-start, check-updates, telemetry, proxy, ca-bundle, insecure-skip-verify, request-metrics, editor-context, instruction-file-size, editor-cmd, editor-goto-cmd")
 		return
 	}
 
-	// Determine where to save
+	cc.saveConfig(&newCfg, scope)
+}
+
+// saveConfig writes newCfg to the global or project config file, as chosen
+// by scope ("global", "project" or "" for the project file when it exists),
+// and applies it to the running session
+func (cc *ConfigCommands) saveConfig(newCfg *config.Config, scope string) {
 	var err error
 	if scope == "global" || (!cc.deps.ConfigManager.ProjectConfigExists() && scope != "project") {
-		err = cc.deps.ConfigManager.SaveGlobal(&newCfg)
+		err = cc.deps.ConfigManager.SaveGlobal(newCfg)
 		if err == nil {
 			cc.deps.MessageLogger("system", "   Saved to global config: ~/.deecli/config.yaml")
 		}
 	} else {
-		err = cc.deps.ConfigManager.SaveProject(&newCfg)
+		err = cc.deps.ConfigManager.SaveProject(newCfg)
 		if err == nil {
 			cc.deps.MessageLogger("system", "   Saved to project config: ./.deecli/config.yaml")
 		}
 	}
 
 	if err != nil {
-		cc.deps.MessageLogger("system", fmt.Sprintf("❌ Failed to save configuration: %v", err))
+		cc.configError(fmt.Sprintf("Failed to save configuration: %v", err))
 		return
 	}
 
@@ -503,6 +1164,9 @@ func (cc *ConfigCommands) handleConfigSet(key, value string, flags []string) {
 	if err := cc.deps.ConfigManager.Load(); err != nil {
 		cc.deps.MessageLogger("system", fmt.Sprintf("⚠️ Configuration saved but reload failed: %v", err))
 	} else {
+		if cc.deps.ApplyConfig != nil {
+			cc.deps.ApplyConfig()
+		}
 		cc.deps.MessageLogger("system", "   Configuration reloaded and applied")
 	}
 }
@@ -510,7 +1174,7 @@ func (cc *ConfigCommands) handleConfigSet(key, value string, flags []string) {
 // handleConfigGet retrieves a configuration value
 func (cc *ConfigCommands) handleConfigGet(key string) {
 	if cc.deps.ConfigManager == nil {
-		cc.deps.MessageLogger("system", "❌ Configuration manager not available")
+		cc.configError("Configuration manager not available")
 		return
 	}
 
@@ -554,9 +1218,123 @@ func (cc *ConfigCommands) handleConfigGet(key string) {
 	case "show-reload-notices":
 		cc.deps.MessageLogger("system", fmt.Sprintf("Show Reload Notices: %t", cfg.ShowReloadNotices))
 
+	case "notify-on-complete":
+		cc.deps.MessageLogger("system", fmt.Sprintf("Notify On Complete: %s", cc.deps.ConfigManager.GetNotifyOnComplete()))
+
+	case "message-timestamps":
+		cc.deps.MessageLogger("system", fmt.Sprintf("Message Timestamps: %s", cc.deps.ConfigManager.GetMessageTimestamps()))
+
+	case "sidebar-sort":
+		cc.deps.MessageLogger("system", fmt.Sprintf("Sidebar Sort: %s", cc.deps.ConfigManager.GetSidebarSort()))
+
+	case "sidebar-group":
+		cc.deps.MessageLogger("system", fmt.Sprintf("Sidebar Group: %t", cfg.SidebarGroup))
+
+	case "plain-mode":
+		cc.deps.MessageLogger("system", fmt.Sprintf("Plain Mode: %t", cfg.PlainMode))
+
+	case "plain-mode-width":
+		cc.deps.MessageLogger("system", fmt.Sprintf("Plain Mode Width: %d", cc.deps.ConfigManager.GetPlainModeWidth()))
+
+	case "screen-reader":
+		cc.deps.MessageLogger("system", fmt.Sprintf("Screen Reader: %t", cfg.ScreenReader))
+
+	case "extract-text":
+		cc.deps.MessageLogger("system", fmt.Sprintf("Extract Text: %t", cfg.ExtractText))
+
+	case "lazy-load-threshold":
+		cc.deps.MessageLogger("system", fmt.Sprintf("Lazy Load Threshold: %d", cc.deps.ConfigManager.GetLazyLoadThreshold()))
+
+	case "scrollback-limit":
+		cc.deps.MessageLogger("system", fmt.Sprintf("Scrollback Limit: %d", cc.deps.ConfigManager.GetScrollbackLimit()))
+
+	case "tool-calls-per-turn":
+		cc.deps.MessageLogger("system", fmt.Sprintf("Tool Calls per Turn: %d", cc.deps.ConfigManager.GetToolCallsPerTurn()))
+
+	case "tool-calls-per-minute":
+		cc.deps.MessageLogger("system", fmt.Sprintf("Tool Calls per Minute: %d", cc.deps.ConfigManager.GetToolCallsPerMinute()))
+
+	case "stream-stall-timeout":
+		cc.deps.MessageLogger("system", fmt.Sprintf("Stream Stall Timeout: %s", cc.deps.ConfigManager.GetStreamStallTimeout()))
+
+	case "stream-auto-reconnect":
+		cc.deps.MessageLogger("system", fmt.Sprintf("Stream Auto-reconnect: %t", cfg.StreamAutoReconnect))
+
+	case "warm-up-on-start":
+		cc.deps.MessageLogger("system", fmt.Sprintf("Warm-up on Start: %t", cfg.WarmUpOnStart))
+
+	case "check-updates":
+		cc.deps.MessageLogger("system", fmt.Sprintf("Check for Updates: %t", cfg.CheckUpdates))
+
+	case "telemetry":
+		cc.deps.MessageLogger("system", fmt.Sprintf("Telemetry: %s", cc.deps.ConfigManager.GetTelemetry()))
+
+	case "proxy":
+		if cfg.Proxy == "" {
+			cc.deps.MessageLogger("system", "Proxy: from HTTP_PROXY/HTTPS_PROXY")
+		} else {
+			cc.deps.MessageLogger("system", fmt.Sprintf("Proxy: %s", cfg.Proxy))
+		}
+
+	case "ca-bundle":
+		cc.deps.MessageLogger("system", fmt.Sprintf("CA Bundle: %s", cfg.CABundle))
+
+	case "insecure-skip-verify":
+		cc.deps.MessageLogger("system", fmt.Sprintf("Insecure Skip Verify: %t", cfg.InsecureSkipVerify))
+
+	case "request-metrics":
+		cc.deps.MessageLogger("system", fmt.Sprintf("Request Metrics: %t", cfg.RequestMetrics))
+
+	case "editor-context":
+		cc.deps.MessageLogger("system", fmt.Sprintf("Editor Context: %t", cfg.EditorContext))
+
+	case "history-max-entries":
+		cc.deps.MessageLogger("system", fmt.Sprintf("History Max Entries: %d", cc.deps.ConfigManager.GetHistoryMaxEntries()))
+
+	case "large-file-threshold":
+		cc.deps.MessageLogger("system", fmt.Sprintf("Large File Threshold: %d KB", cc.deps.ConfigManager.GetLargeFileThreshold()/1024))
+
+	case "large-file-preview":
+		cc.deps.MessageLogger("system", fmt.Sprintf("Large File Preview: %d KB", cc.deps.ConfigManager.GetLargeFilePreview()/1024))
+
+	case "max-file-size":
+		cc.deps.MessageLogger("system", fmt.Sprintf("Max File Size: %d KB", cc.deps.ConfigManager.GetMaxFileSize()/1024))
+
+	case "max-files-per-load":
+		cc.deps.MessageLogger("system", fmt.Sprintf("Max Files per Load: %d", cc.deps.ConfigManager.GetMaxFilesPerLoad()))
+
+	case "max-loaded-files":
+		cc.deps.MessageLogger("system", fmt.Sprintf("Max Loaded Files: %d", cc.deps.ConfigManager.GetMaxLoadedFiles()))
+
+	case "instruction-file-size":
+		if size := cc.deps.ConfigManager.GetInstructionFileSize(); size == 0 {
+			cc.deps.MessageLogger("system", "Instruction File Size: disabled")
+		} else {
+			cc.deps.MessageLogger("system", fmt.Sprintf("Instruction File Size: %d KB", size/1024))
+		}
+
+	case "editor-cmd", "editor-goto-cmd":
+		command, gotoCommand := cc.deps.ConfigManager.GetEditorCommands()
+		if key == "editor-goto-cmd" {
+			command = gotoCommand
+		}
+		if command == "" {
+			command = "built-in command of the editor"
+		}
+		cc.deps.MessageLogger("system", fmt.Sprintf("%s: %s", key, command))
+
+	case "language":
+		cc.deps.MessageLogger("system", i18n.T("config.language_get", cc.deps.ConfigManager.GetLanguage()+" ("+i18n.Language()+")"))
+
+	case "redact-secrets":
+		cc.deps.MessageLogger("system", fmt.Sprintf("Redact Secrets: %t", cfg.RedactSecrets))
+		if len(cfg.RedactPatterns) > 0 {
+			cc.deps.MessageLogger("system", fmt.Sprintf("Custom Redact Patterns: %s", strings.Join(cfg.RedactPatterns, ", ")))
+		}
+
 	default:
-		cc.deps.MessageLogger("system", fmt.Sprintf("❌ Unknown config key: %s", key))
-		cc.deps.MessageLogger("system", "Valid keys: api-key, model, user-name, temperature, max-tokens, auto-reload-files, auto-reload-debounce, show-reload-notices")
+		cc.configError(fmt.Sprintf("Unknown config key: %s", key))
+		cc.deps.MessageLogger("system", "Valid keys: api-key, model, user-name, temperature, max-tokens, auto-reload-files, auto-reload-debounce, show-reload-notices, redact-secrets, notify-on-complete, plain-mode, plain-mode-width, screen-reader, extract-text, language, history-max-entries, lazy-load-threshold, large-file-threshold, large-file-preview, max-file-size, max-files-per-load, max-loaded-files, scrollback-limit, tool-calls-per-turn, tool-calls-per-minute, stream-stall-timeout, stream-auto-reconnect, message-timestamps, sidebar-sort, sidebar-group, warm-up-on-start, check-updates, telemetry, proxy, ca-bundle, insecure-skip-verify, request-metrics, editor-context, instruction-file-size, editor-cmd, editor-goto-cmd")
 	}
 }
 
@@ -573,6 +1351,8 @@ func (cc *ConfigCommands) showConfigHelp() {
 	cc.deps.MessageLogger("system", "  /config init             - Initialize configuration")
 	cc.deps.MessageLogger("system", "  /config get <key>        - Get a specific config value")
 	cc.deps.MessageLogger("system", "  /config set <key> <val>  - Set a config value")
+	cc.deps.MessageLogger("system", "  /config edit             - Edit all settings in a form")
+	cc.deps.MessageLogger("system", "  /config verify           - Check the API key and model with a 1-token request")
 	cc.deps.MessageLogger("system", "")
 	cc.deps.MessageLogger("system", "Shortcuts:")
 	cc.deps.MessageLogger("system", "  /config model <name>     - Set model quickly")
@@ -587,4 +1367,13 @@ func (cc *ConfigCommands) showConfigHelp() {
 	cc.deps.MessageLogger("system", "  /config model deepseek-reasoner --global")
 	cc.deps.MessageLogger("system", "  /config set temperature 0.7")
 	cc.deps.MessageLogger("system", "  /config get model")
-}
\ No newline at end of file
+}
+
+// configError reports a configuration error through the shared error log
+func (cc *ConfigCommands) configError(summary string) {
+	if cc.deps.ReportError != nil {
+		cc.deps.ReportError(errlog.CategoryConfig, summary, nil)
+		return
+	}
+	cc.deps.MessageLogger("system", "❌ "+summary)
+}
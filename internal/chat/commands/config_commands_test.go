@@ -14,10 +14,13 @@
 package commands
 
 import (
+	"errors"
 	"strings"
 	"testing"
 
+	"github.com/antenore/deecli/internal/api"
 	"github.com/antenore/deecli/internal/config"
+	"github.com/antenore/deecli/internal/errlog"
 )
 
 // TestConfigCommands_Config tests the /config command without arguments
@@ -153,3 +156,67 @@ func TestConfigCommands_ShowConfigHelp(t *testing.T) {
 		t.Error("Expected help information to be displayed")
 	}
 }
+
+// TestConfigCommands_Mode tests switching temperature presets with /mode
+func TestConfigCommands_Mode(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Chdir(t.TempDir())
+
+	var messages []string
+	configManager := config.NewManager()
+	applied := 0
+	cc := NewConfigCommands(Dependencies{
+		ConfigManager: configManager,
+		MessageLogger: func(role, content string) { messages = append(messages, content) },
+		ApplyConfig:   func() { applied++ },
+	})
+
+	cc.Mode([]string{"creative", "--prompt"})
+	if got := configManager.GetTemperature(); got != 1.5 {
+		t.Errorf("GetTemperature() = %v, want 1.5", got)
+	}
+	if configManager.GetModePrompt() == "" {
+		t.Error("expected the creative system prompt to be enabled")
+	}
+	if applied != 1 {
+		t.Errorf("expected the config to be applied once, got %d", applied)
+	}
+
+	cc.Mode([]string{"off"})
+	if _, ok := configManager.GetMode(); ok || configManager.GetModePrompt() != "" {
+		t.Error("expected no mode after /mode off")
+	}
+	if got := configManager.GetTemperature(); got != 1.5 {
+		t.Errorf("expected the preset temperature to stay after /mode off, got %v", got)
+	}
+
+	messages = nil
+	cc.Mode([]string{"poetry"})
+	if len(messages) == 0 || !strings.Contains(strings.Join(messages, "\n"), "invalid mode") {
+		t.Errorf("expected an invalid mode error, got %v", messages)
+	}
+}
+
+// TestConfigCommands_Verify tests /config verify without an API client and
+// the summaries of failed verifications
+func TestConfigCommands_Verify(t *testing.T) {
+	var messages []string
+	deps := Dependencies{
+		MessageLogger: func(role, content string) { messages = append(messages, content) },
+		ReportError:   func(category errlog.Category, summary string, err error) { messages = append(messages, summary) },
+	}
+
+	if cmd := NewConfigCommands(deps).Config([]string{"verify"}); cmd != nil {
+		t.Error("Expected no request without an API client")
+	}
+	if len(messages) == 0 || !strings.Contains(messages[0], "No API key") {
+		t.Errorf("Expected a missing key message, got %v", messages)
+	}
+
+	if msg := verifyFailure(api.APIError{StatusCode: 401}); !strings.Contains(msg, "rejected") {
+		t.Errorf("Expected a rejected key summary, got %q", msg)
+	}
+	if msg := verifyFailure(errors.New("dial tcp: timeout")); !strings.Contains(msg, "Verification failed") {
+		t.Errorf("Expected a generic failure summary, got %q", msg)
+	}
+}
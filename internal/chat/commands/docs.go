@@ -0,0 +1,241 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	aiops "github.com/antenore/deecli/internal/ai"
+	"github.com/antenore/deecli/internal/doclint"
+	"github.com/antenore/deecli/internal/errlog"
+	"github.com/antenore/deecli/internal/files"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// docsUsage lists the forms of /docs
+const docsUsage = "Usage: /docs [--check] <package>... (a directory, ./dir/... or a glob of Go files)"
+
+// goVetTimeout bounds the go vet run of /docs --check
+const goVetTimeout = 2 * time.Minute
+
+// Docs handles the /docs command. It loads the Go files of the packages the
+// patterns match, with their README.md and example_test.go, and asks for the
+// doc comments, examples and README sections they lack as diffs, to review
+// with /apply. With --check it only reports the doc convention problems and
+// runs go vet.
+func (ai *AICommands) Docs(args []string) tea.Cmd {
+	check := false
+	var patterns []string
+	for _, arg := range args {
+		if arg == "--check" {
+			check = true
+		} else {
+			patterns = append(patterns, arg)
+		}
+	}
+	if len(patterns) == 0 {
+		ai.deps.MessageLogger("system", docsUsage)
+		return nil
+	}
+
+	dirs, err := goPackageDirs(patterns)
+	if err != nil {
+		ai.deps.MessageLogger("system", fmt.Sprintf("❌ %v", err))
+		return nil
+	}
+	problems := make(map[string][]string, len(dirs))
+	count := 0
+	for _, dir := range dirs {
+		found, err := doclint.Lint(dir)
+		if err != nil {
+			ai.deps.MessageLogger("system", fmt.Sprintf("❌ Cannot check %s: %v", dir, err))
+			return nil
+		}
+		for _, problem := range found {
+			problems[dir] = append(problems[dir], problem.String())
+		}
+		count += len(found)
+	}
+
+	if check {
+		ai.deps.MessageLogger("system", formatDocProblems(dirs, problems, count))
+		ai.deps.MessageLogger("system", "🔎 Running go vet...")
+		return goVet(dirs)
+	}
+
+	if ai.deps.APIClient == nil || ai.deps.DocumentPackages == nil {
+		ai.deps.MessageLogger("system", "Please set DEEPSEEK_API_KEY environment variable")
+		return nil
+	}
+
+	// The diffs can only be reviewed with /apply for loaded files
+	var paths []string
+	for _, dir := range dirs {
+		paths = append(paths, docFiles(dir)...)
+	}
+	if err := ai.deps.FileContext.LoadFiles(paths); err != nil {
+		ai.deps.MessageLogger("system", fmt.Sprintf("❌ Cannot load the packages: %v", err))
+		return nil
+	}
+
+	loaded := make(map[string]files.LoadedFile)
+	for _, file := range ai.deps.FileContext.Files {
+		abs, _ := filepath.Abs(file.Path)
+		loaded[abs] = file
+	}
+	packages := make([]aiops.DocPackage, len(dirs))
+	for i, dir := range dirs {
+		packages[i] = aiops.DocPackage{Dir: dir, Problems: problems[dir]}
+		for _, path := range docFiles(dir) {
+			abs, _ := filepath.Abs(path)
+			if file, ok := loaded[abs]; ok {
+				packages[i].Files = append(packages[i].Files, file)
+			}
+		}
+	}
+
+	ai.deps.MessageLogger("system", fmt.Sprintf("📚 Loaded %d file(s) of %d package(s), with %d doc convention problem(s). "+
+		"When the task finishes, review the diffs with /apply <file>", len(paths), len(dirs), count))
+	return ai.deps.DocumentPackages(packages)
+}
+
+// goPackageDirs returns the directories of the Go packages patterns name: a
+// directory, a directory followed by /... for the packages below it, or a
+// glob of Go files
+func goPackageDirs(patterns []string) ([]string, error) {
+	seen := make(map[string]bool)
+	var dirs []string
+	add := func(dir string) {
+		dir = filepath.Clean(dir)
+		if !seen[dir] && len(docFiles(dir)) > 0 {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+
+	for _, pattern := range patterns {
+		if root, ok := strings.CutSuffix(pattern, "/..."); ok {
+			err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+				if err != nil || !d.IsDir() {
+					return err
+				}
+				name := d.Name()
+				if path != root && (strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") || name == "vendor" || name == "testdata") {
+					return filepath.SkipDir
+				}
+				add(path)
+				return nil
+			})
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if info, err := os.Stat(pattern); err == nil && info.IsDir() {
+			add(pattern)
+			continue
+		}
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, err
+		}
+		for _, match := range matches {
+			if strings.HasSuffix(match, ".go") {
+				add(filepath.Dir(match))
+			}
+		}
+	}
+
+	if len(dirs) == 0 {
+		return nil, fmt.Errorf("no Go packages match %s", strings.Join(patterns, " "))
+	}
+	sort.Strings(dirs)
+	return dirs, nil
+}
+
+// docFiles returns the files of the package in dir that /docs documents: its
+// Go files without tests, example_test.go and README.md. It returns nil
+// for a directory without Go files.
+func docFiles(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var paths, extra []string
+	for _, entry := range entries {
+		name := entry.Name()
+		switch {
+		case entry.IsDir():
+		case name == "example_test.go" || name == "README.md":
+			extra = append(extra, filepath.Join(dir, name))
+		case strings.HasSuffix(name, ".go") && !strings.HasSuffix(name, "_test.go"):
+			paths = append(paths, filepath.Join(dir, name))
+		}
+	}
+	if len(paths) == 0 {
+		return nil
+	}
+	return append(paths, extra...)
+}
+
+// formatDocProblems reports the doc convention problems of /docs --check
+func formatDocProblems(dirs []string, problems map[string][]string, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("✅ No doc convention problems in %d package(s)", len(dirs))
+	}
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("📚 %d doc convention problem(s) in %d package(s):\n", count, len(dirs)))
+	for _, dir := range dirs {
+		for _, problem := range problems[dir] {
+			b.WriteString("  " + problem + "\n")
+		}
+	}
+	b.WriteString("Run /docs without --check to get the missing documentation as diffs")
+	return b.String()
+}
+
+// goVet runs go vet on the packages in dirs in the background
+func goVet(dirs []string) tea.Cmd {
+	args := []string{"vet"}
+	for _, dir := range dirs {
+		if !filepath.IsAbs(dir) {
+			dir = "./" + filepath.ToSlash(dir)
+		}
+		args = append(args, dir)
+	}
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), goVetTimeout)
+		defer cancel()
+		output, err := exec.CommandContext(ctx, "go", args...).CombinedOutput()
+		var exitErr *exec.ExitError
+		switch {
+		case errors.As(err, &exitErr):
+			// go vet exits with an error when it reports problems
+			return CommandResultMsg{Message: "🔎 go vet:\n" + strings.TrimSpace(string(output))}
+		case err != nil:
+			return CommandResultMsg{Message: "go vet failed", Err: err, Category: errlog.Classify(err)}
+		}
+		return CommandResultMsg{Message: "✅ go vet found no problems"}
+	}
+}
@@ -14,10 +14,16 @@
 package commands
 
 import (
+	"bytes"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/antenore/deecli/internal/chat/tracker"
+	"github.com/antenore/deecli/internal/editor"
 	"github.com/antenore/deecli/internal/files"
 )
 
@@ -36,6 +42,15 @@ func (fc *FileCommands) Load(args []string) tea.Cmd {
 	if len(args) < 1 {
 		fc.deps.MessageLogger("system", "Usage: /load <filepath>. Examples: /load *.go, /load main.go, /load src/**/*.py")
 		fc.deps.MessageLogger("system", "Use --all flag to bypass .gitignore: /load --all *.js")
+		fc.deps.MessageLogger("system", "Load from git: /load --modified, /load --staged, /load --branch [base]")
+		fc.deps.MessageLogger("system", "Set the language of scripts without an extension: /load bin/deploy --lang python")
+		return nil
+	}
+
+	// --lang sets the language the files are labeled with
+	language, args, ok := languageFlag(args)
+	if !ok || len(args) == 0 {
+		fc.deps.MessageLogger("system", "Usage: /load <filepath> --lang <language>. Example: /load bin/deploy --lang python")
 		return nil
 	}
 
@@ -51,24 +66,119 @@ func (fc *FileCommands) Load(args []string) tea.Cmd {
 		}
 	}
 
+	// Git flags load the files git reports instead of, or as well as, patterns
+	if len(patterns) > 0 && isGitLoadFlag(patterns[0]) {
+		gitPatterns, ok := fc.gitLoadPatterns(patterns)
+		if !ok {
+			return nil
+		}
+		patterns = gitPatterns
+	}
+
 	// Temporarily set a different loader if --all is specified
 	originalLoader := fc.deps.FileContext.Loader
 	if !respectGitignore {
-		fc.deps.FileContext.Loader = files.NewFileLoaderWithOptions(false)
+		fc.deps.FileContext.Loader = files.NewFileLoaderWithPatterns(false, originalLoader.IgnorePatterns())
+		fc.deps.FileContext.Loader.MaxFileSize = originalLoader.MaxFileSize
+		fc.deps.FileContext.Loader.MaxFiles = originalLoader.MaxFiles
 		defer func() { fc.deps.FileContext.Loader = originalLoader }()
-		fc.deps.MessageLogger("system", "Loading files with --all flag (ignoring .gitignore)")
+		fc.deps.MessageLogger("system", "Loading files with --all flag (ignoring .gitignore, .deecliignore still applies)")
 	}
 
+	fc.deps.FileContext.SaveUndo("/load")
 	err := fc.deps.FileContext.LoadFiles(patterns)
 	if err != nil {
+		fc.deps.FileContext.DiscardUndo()
 		fc.deps.MessageLogger("system", fmt.Sprintf("❌ %v", err))
 	} else {
+		if language != "" {
+			if _, err := fc.deps.FileContext.SetLanguage(patterns, language); err != nil {
+				fc.deps.MessageLogger("system", fmt.Sprintf("❌ %v", err))
+			}
+		}
 		fc.deps.MessageLogger("system", fc.deps.FileContext.GetInfo())
 		fc.deps.RefreshUI()
 	}
 	return nil
 }
 
+// languageFlag removes "--lang <language>" or "--lang=<language>" from args
+// and returns the language, "" when the flag is not given. It reports false
+// when the flag has no language.
+func languageFlag(args []string) (string, []string, bool) {
+	for i, arg := range args {
+		if value, found := strings.CutPrefix(arg, "--lang="); found {
+			rest := append(append([]string{}, args[:i]...), args[i+1:]...)
+			return value, rest, value != ""
+		}
+		if arg == "--lang" {
+			if i+1 >= len(args) {
+				return "", nil, false
+			}
+			rest := append(append([]string{}, args[:i]...), args[i+2:]...)
+			return args[i+1], rest, true
+		}
+	}
+	return "", args, true
+}
+
+// isGitLoadFlag reports whether arg selects files from git status
+func isGitLoadFlag(arg string) bool {
+	return arg == "--modified" || arg == "--staged" || arg == "--branch"
+}
+
+// gitLoadPatterns replaces the git flag at the start of args with the files
+// git reports, skipping binary ones. It reports false when there is nothing
+// to load.
+func (fc *FileCommands) gitLoadPatterns(args []string) ([]string, bool) {
+	flag, rest := args[0], args[1:]
+	base := ""
+	if flag == "--branch" && len(rest) > 0 && !strings.ContainsAny(rest[0], "*?[") && !fileExists(rest[0]) {
+		base, rest = rest[0], rest[1:]
+	}
+
+	paths, err := gitChangedFiles(flag, base)
+	if err != nil {
+		fc.deps.MessageLogger("system", fmt.Sprintf("❌ %v", err))
+		return nil, false
+	}
+
+	var patterns, skipped []string
+	for _, path := range paths {
+		if !fileExists(path) || isBinaryFile(path) {
+			skipped = append(skipped, path)
+			continue
+		}
+		patterns = append(patterns, path)
+	}
+	if len(skipped) > 0 {
+		fc.deps.MessageLogger("system", fmt.Sprintf("Skipping %d binary or non-file path(s): %s", len(skipped), strings.Join(skipped, ", ")))
+	}
+	patterns = append(patterns, rest...)
+	if len(patterns) == 0 {
+		fc.deps.MessageLogger("system", fmt.Sprintf("No files to load: git reports no %s files", strings.TrimPrefix(flag, "--")))
+		return nil, false
+	}
+	return patterns, true
+}
+
+// isBinaryFile reports whether the start of the file at path contains a NUL byte
+func isBinaryFile(path string) bool {
+	file, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+	head := make([]byte, 512)
+	n, _ := file.Read(head)
+	return isBinaryContent(head[:n])
+}
+
+// isBinaryContent reports whether data, the start of a file, looks binary
+func isBinaryContent(data []byte) bool {
+	return bytes.IndexByte(data[:min(len(data), 512)], 0) >= 0
+}
+
 // Add handles the /add command
 func (fc *FileCommands) Add(args []string) tea.Cmd {
 	if len(args) < 1 {
@@ -77,8 +187,10 @@ func (fc *FileCommands) Add(args []string) tea.Cmd {
 	}
 
 	patterns := args
+	fc.deps.FileContext.SaveUndo("/add")
 	err := fc.deps.FileContext.LoadFiles(patterns)
 	if err != nil {
+		fc.deps.FileContext.DiscardUndo()
 		fc.deps.MessageLogger("system", fmt.Sprintf("❌ %v", err))
 	} else {
 		fc.deps.MessageLogger("system", fc.deps.FileContext.GetInfo())
@@ -99,8 +211,11 @@ func (fc *FileCommands) List(args []string) tea.Cmd {
 
 // Clear handles the /clear command
 func (fc *FileCommands) Clear(args []string) tea.Cmd {
+	if len(fc.deps.FileContext.Files) > 0 {
+		fc.deps.FileContext.SaveUndo("/clear")
+	}
 	fc.deps.FileContext.Clear()
-	fc.deps.MessageLogger("system", "All files cleared")
+	fc.deps.MessageLogger("system", "All files cleared. Use /undo-files to restore them")
 	fc.deps.RefreshUI()
 	return nil
 }
@@ -113,7 +228,11 @@ func (fc *FileCommands) Unload(args []string) tea.Cmd {
 	}
 
 	pattern := args[0]
+	fc.deps.FileContext.SaveUndo("/unload")
 	removed := fc.deps.FileContext.UnloadFiles(pattern)
+	if removed == 0 {
+		fc.deps.FileContext.DiscardUndo()
+	}
 	if removed > 0 {
 		fc.deps.MessageLogger("system", fmt.Sprintf("✓ Removed %d file(s) matching '%s'", removed, pattern))
 		fc.deps.MessageLogger("system", fc.deps.FileContext.GetInfo())
@@ -124,6 +243,368 @@ func (fc *FileCommands) Unload(args []string) tea.Cmd {
 	return nil
 }
 
+// UndoFiles handles the /undo-files command, which restores the loaded files
+// as they were before the last /load, /unload or /clear
+func (fc *FileCommands) UndoFiles(args []string) tea.Cmd {
+	operation, ok := fc.deps.FileContext.Undo()
+	if !ok {
+		fc.deps.MessageLogger("system", "Nothing to undo. /undo-files reverts the last /load, /unload or /clear")
+		return nil
+	}
+	fc.deps.MessageLogger("system", fmt.Sprintf("↩️ Undid %s", operation))
+	if len(fc.deps.FileContext.Files) == 0 {
+		fc.deps.MessageLogger("system", "No files loaded")
+	} else {
+		fc.deps.MessageLogger("system", fc.deps.FileContext.GetInfo())
+	}
+	fc.deps.RefreshUI()
+	return nil
+}
+
+// Snapshot handles the /snapshot command: "create <name>" records the loaded
+// files as they are on disk, "restore <name>" writes back those changed since
+// and with no argument the snapshots are listed
+func (fc *FileCommands) Snapshot(args []string) tea.Cmd {
+	usage := "Usage: /snapshot [create <name>|restore <name>]"
+	if len(args) == 0 {
+		fc.listSnapshots()
+		return nil
+	}
+	if len(args) != 2 {
+		fc.deps.MessageLogger("system", usage)
+		return nil
+	}
+
+	switch args[0] {
+	case "create":
+		snapshot, err := files.CreateSnapshot(files.DefaultSnapshotDir, args[1], fc.deps.FileContext.Files)
+		if err != nil {
+			fc.deps.MessageLogger("system", fmt.Sprintf("❌ %v", err))
+			return nil
+		}
+		fc.deps.MessageLogger("system", fmt.Sprintf("📸 Snapshot %s saved with %d file(s). /snapshot restore %s reverts them to this state", snapshot.Name, len(snapshot.Files), snapshot.Name))
+
+	case "restore":
+		snapshot, err := files.LoadSnapshot(files.DefaultSnapshotDir, args[1])
+		if err != nil {
+			fc.deps.MessageLogger("system", fmt.Sprintf("❌ %v", err))
+			return nil
+		}
+		restored, err := snapshot.Restore()
+		if len(restored) > 0 {
+			paths := make([]string, len(restored))
+			var msg strings.Builder
+			msg.WriteString(fmt.Sprintf("⏪ Restored %d file(s) from snapshot %s:\n", len(restored), snapshot.Name))
+			for i, file := range restored {
+				paths[i] = file.Path
+				msg.WriteString("  " + file.RelPath + "\n")
+			}
+			fc.deps.MessageLogger("system", strings.TrimSuffix(msg.String(), "\n"))
+			// Refresh the restored files that are still loaded
+			if _, err := fc.deps.FileContext.ReloadFiles(paths); err != nil {
+				fc.deps.MessageLogger("system", fmt.Sprintf("⚠️ Restored files could not be reloaded: %v. Use /reload", err))
+			}
+		}
+		if err != nil {
+			fc.deps.MessageLogger("system", fmt.Sprintf("❌ %v", err))
+		} else if len(restored) == 0 {
+			fc.deps.MessageLogger("system", fmt.Sprintf("Nothing to restore: the files of snapshot %s are unchanged", snapshot.Name))
+		}
+
+	default:
+		fc.deps.MessageLogger("system", usage)
+		return nil
+	}
+	fc.deps.RefreshUI()
+	return nil
+}
+
+// listSnapshots shows the saved snapshots and how many of their files changed
+func (fc *FileCommands) listSnapshots() {
+	snapshots, err := files.ListSnapshots(files.DefaultSnapshotDir)
+	if err != nil {
+		fc.deps.MessageLogger("system", fmt.Sprintf("❌ Error reading snapshots: %v", err))
+		return
+	}
+	if len(snapshots) == 0 {
+		fc.deps.MessageLogger("system", "No snapshots. /snapshot create <name> records the loaded files before an experiment")
+		return
+	}
+
+	var msg strings.Builder
+	msg.WriteString("📸 **Snapshots** (/snapshot restore <name> reverts the changed files)\n\n")
+	for _, snapshot := range snapshots {
+		msg.WriteString(fmt.Sprintf("- %s: %d file(s), %d changed since, taken %s\n",
+			snapshot.Name, len(snapshot.Files), len(snapshot.Changed()), snapshot.Created.Format("2006-01-02 15:04")))
+	}
+	fc.deps.MessageLogger("system", strings.TrimSuffix(msg.String(), "\n"))
+}
+
+// Mentioned handles the /mentioned command: it lists the files the AI has
+// mentioned and loads, opens or diffs one of them
+func (fc *FileCommands) Mentioned(args []string) tea.Cmd {
+	if fc.deps.FileTracker == nil {
+		fc.deps.MessageLogger("system", "File tracking is not available")
+		return nil
+	}
+	mentioned := fc.deps.FileTracker.Mentioned()
+	if len(mentioned) == 0 {
+		fc.deps.MessageLogger("system", "No files have been mentioned in AI responses yet")
+		return nil
+	}
+	if len(args) == 0 {
+		fc.showMentioned(mentioned)
+		return nil
+	}
+
+	usage := "Usage: /mentioned [load <n|all>|edit <n>|diff <n>]"
+	if len(args) < 2 {
+		fc.deps.MessageLogger("system", usage)
+		return nil
+	}
+	if args[0] == "load" && args[1] == "all" {
+		var paths []string
+		for _, file := range mentioned {
+			if fileExists(file.Path) && fc.loadedFile(file.Path) == nil {
+				paths = append(paths, file.Path)
+			}
+		}
+		if len(paths) == 0 {
+			fc.deps.MessageLogger("system", "All mentioned files that exist are already loaded")
+			return nil
+		}
+		return fc.Load(paths)
+	}
+
+	n, err := strconv.Atoi(args[1])
+	if err != nil || n < 1 || n > len(mentioned) {
+		fc.deps.MessageLogger("system", fmt.Sprintf("Invalid file number. Please use 1-%d", len(mentioned)))
+		return nil
+	}
+	path := mentioned[n-1].Path
+
+	switch args[0] {
+	case "load":
+		if !fileExists(path) {
+			fc.deps.MessageLogger("system", fmt.Sprintf("❌ %s does not exist", path))
+			return nil
+		}
+		return fc.Load([]string{path})
+	case "edit":
+		config := editorConfig(fc.deps)
+		return editor.OpenFileWithInstructions(path, config)
+	case "diff":
+		fc.showSuggestedDiff(path)
+	default:
+		fc.deps.MessageLogger("system", usage)
+	}
+	return nil
+}
+
+// showMentioned lists the mentioned files with their state and marks them seen
+func (fc *FileCommands) showMentioned(mentioned []tracker.TrackedFile) {
+	var list strings.Builder
+	list.WriteString("📎 Files mentioned by the AI (most recent first):\n")
+	for i, file := range mentioned {
+		state := "missing"
+		if loaded := fc.loadedFile(file.Path); loaded != nil {
+			state = "loaded"
+			if len(fc.deps.FileContext.PendingSuggestions(loaded.Path)) > 0 {
+				state = "loaded, suggested diff"
+			}
+		} else if fileExists(file.Path) {
+			state = "not loaded"
+		}
+		list.WriteString(fmt.Sprintf("  [%d] %s (%s) - %s\n", i+1, file.Path, state, file.Description))
+	}
+	list.WriteString("\nActions: /mentioned load <n|all>, /mentioned edit <n>, /mentioned diff <n>")
+	fc.deps.MessageLogger("system", list.String())
+	fc.deps.FileTracker.MarkSeen()
+}
+
+// showSuggestedDiff shows the pending AI diffs for a mentioned file
+func (fc *FileCommands) showSuggestedDiff(path string) {
+	loaded := fc.loadedFile(path)
+	if loaded == nil {
+		fc.deps.MessageLogger("system", fmt.Sprintf("%s is not loaded; suggested diffs are only tracked for loaded files. Try /mentioned load", path))
+		return
+	}
+	pending := fc.deps.FileContext.PendingSuggestions(loaded.Path)
+	if len(pending) == 0 {
+		fc.deps.MessageLogger("system", fmt.Sprintf("No pending suggested diff for %s", loaded.RelPath))
+		return
+	}
+	for _, suggestion := range pending {
+		fc.deps.MessageLogger("system", fmt.Sprintf("Suggested diff for %s (applies to the current content):\n```diff\n%s\n```", suggestion.RelPath, suggestion.Patch))
+	}
+}
+
+// Apply handles the /apply command: it opens a picker over the hunks of the
+// latest diff the AI suggested for a loaded file, and writes only the
+// accepted ones. Without a file it picks the only file with a suggestion.
+func (fc *FileCommands) Apply(args []string) tea.Cmd {
+	if fc.deps.OpenHunkPicker == nil {
+		fc.deps.MessageLogger("system", "Applying diffs is not available")
+		return nil
+	}
+	if len(args) > 1 {
+		fc.deps.MessageLogger("system", "Usage: /apply [file]")
+		return nil
+	}
+
+	var pending []files.PatchSuggestion
+	if len(args) == 1 {
+		file := fc.loadedFile(args[0])
+		if file == nil {
+			fc.deps.MessageLogger("system", fmt.Sprintf("%s is not loaded. Use /list to see loaded files", args[0]))
+			return nil
+		}
+		pending = fc.deps.FileContext.PendingSuggestions(file.Path)
+	} else {
+		var targets []string
+		for _, file := range fc.deps.FileContext.Files {
+			if suggestions := fc.deps.FileContext.PendingSuggestions(file.Path); len(suggestions) > 0 {
+				pending = suggestions
+				targets = append(targets, file.RelPath)
+			}
+		}
+		if len(targets) > 1 {
+			fc.deps.MessageLogger("system", fmt.Sprintf("Several files have suggested diffs: %s. Use /apply <file>", strings.Join(targets, ", ")))
+			return nil
+		}
+	}
+	if len(pending) == 0 {
+		fc.deps.MessageLogger("system", "No pending suggested diff. /apply works on ```diff blocks the AI wrote for loaded files")
+		return nil
+	}
+
+	fc.deps.OpenHunkPicker(pending[len(pending)-1])
+	return nil
+}
+
+// loadedFile returns the loaded file with the given relative or absolute path
+func (fc *FileCommands) loadedFile(path string) *files.LoadedFile {
+	for i, file := range fc.deps.FileContext.Files {
+		if file.RelPath == path || file.Path == path {
+			return &fc.deps.FileContext.Files[i]
+		}
+	}
+	return nil
+}
+
+// fileExists reports whether path is an existing regular file
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+// View handles the /view command: a read-only look at a workspace file that
+// does not load it into the context
+func (fc *FileCommands) View(args []string) tea.Cmd {
+	if len(args) == 2 && args[0] == "--compare" {
+		return fc.compare(args[1])
+	}
+	if len(args) != 1 {
+		fc.deps.MessageLogger("system", "Usage: /view <file>[:line] or /view --compare <loaded file>")
+		return nil
+	}
+	if fc.deps.OpenFileViewer == nil {
+		fc.deps.MessageLogger("system", "File viewer not available")
+		return nil
+	}
+
+	path, line := editor.ParseFileAndLine(args[0])
+	absPath, err := workspacePath(path)
+	if err != nil {
+		fc.deps.MessageLogger("system", fmt.Sprintf("❌ %v", err))
+		return nil
+	}
+	info, err := os.Stat(absPath)
+	if err != nil {
+		fc.deps.MessageLogger("system", fmt.Sprintf("❌ File not found: %s", path))
+		return nil
+	}
+	if info.IsDir() {
+		fc.deps.MessageLogger("system", fmt.Sprintf("❌ '%s' is a directory, not a file", path))
+		return nil
+	}
+	if fc.deps.FileContext != nil && fc.deps.FileContext.Loader != nil && info.Size() > fc.deps.FileContext.Loader.MaxFileSize {
+		fc.deps.MessageLogger("system", fmt.Sprintf("❌ File too large to view: %s (%s). Use /edit instead", path, fc.formatFileSize(info.Size())))
+		return nil
+	}
+
+	content, err := os.ReadFile(absPath)
+	if err != nil {
+		fc.deps.MessageLogger("system", fmt.Sprintf("❌ Error reading %s: %v", path, err))
+		return nil
+	}
+	if isBinaryContent(content) {
+		fc.deps.MessageLogger("system", fmt.Sprintf("❌ '%s' appears to be a binary file", path))
+		return nil
+	}
+
+	fc.deps.OpenFileViewer(path, string(content), line)
+	return nil
+}
+
+// compare shows the loaded content of a file next to its content on disk
+func (fc *FileCommands) compare(path string) tea.Cmd {
+	if fc.deps.OpenCompareViewer == nil {
+		fc.deps.MessageLogger("system", "File viewer not available")
+		return nil
+	}
+	loaded := fc.loadedFile(path)
+	if loaded == nil {
+		if absPath, err := filepath.Abs(path); err == nil {
+			loaded = fc.loadedFile(absPath)
+		}
+	}
+	if loaded == nil {
+		fc.deps.MessageLogger("system", fmt.Sprintf("%s is not loaded. Use /list to see loaded files", path))
+		return nil
+	}
+	if loaded.Lazy {
+		fc.deps.MessageLogger("system", fmt.Sprintf("%s is loaded lazily, so its content is always read from disk", loaded.RelPath))
+		return nil
+	}
+	if loaded.Preview {
+		fc.deps.MessageLogger("system", fmt.Sprintf("%s is loaded as a preview of its first part and cannot be compared", loaded.RelPath))
+		return nil
+	}
+	if loaded.ExtractedBy != "" {
+		fc.deps.MessageLogger("system", fmt.Sprintf("%s was loaded as text extracted with %s and cannot be compared", loaded.RelPath, loaded.ExtractedBy))
+		return nil
+	}
+
+	disk, err := os.ReadFile(loaded.Path)
+	if err != nil {
+		fc.deps.MessageLogger("system", fmt.Sprintf("❌ Error reading %s: %v", loaded.RelPath, err))
+		return nil
+	}
+	if string(disk) == loaded.Content {
+		fc.deps.MessageLogger("system", fmt.Sprintf("✅ The loaded context for %s matches the file on disk", loaded.RelPath))
+		return nil
+	}
+	fc.deps.OpenCompareViewer(loaded.RelPath, loaded.Content, string(disk))
+	return nil
+}
+
+// workspacePath resolves path and checks that it is inside the working directory
+func workspacePath(path string) (string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	if rel, err := filepath.Rel(cwd, absPath); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("path is outside the working directory: %s", path)
+	}
+	return absPath, nil
+}
+
 // Reload handles the /reload command
 func (fc *FileCommands) Reload(args []string) tea.Cmd {
 	var patterns []string
@@ -185,6 +666,11 @@ func (fc *FileCommands) Reload(args []string) tea.Cmd {
 	}
 
 	fc.deps.MessageLogger("system", strings.TrimSuffix(msg.String(), "\n"))
+	for _, result := range results {
+		for _, check := range result.Suggestions {
+			fc.deps.MessageLogger("system", check.Summary())
+		}
+	}
 	fc.deps.RefreshUI()
 	return nil
 }
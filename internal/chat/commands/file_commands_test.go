@@ -0,0 +1,316 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/antenore/deecli/internal/chat/tracker"
+	"github.com/antenore/deecli/internal/files"
+)
+
+func TestMentioned(t *testing.T) {
+	t.Chdir(t.TempDir())
+	if err := os.WriteFile("main.go", []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ft := tracker.NewFileTracker()
+	ft.ExtractFilesFromResponse("Update main.go and create util.go")
+	fc := files.NewFileContext()
+	var logged []string
+	cmds := NewFileCommands(Dependencies{
+		FileContext:   fc,
+		FileTracker:   ft,
+		MessageLogger: func(role, content string) { logged = append(logged, content) },
+		RefreshUI:     func() {},
+	})
+
+	cmds.Mentioned(nil)
+	out := strings.Join(logged, "\n")
+	if !strings.Contains(out, "[1] util.go (missing)") || !strings.Contains(out, "[2] main.go (not loaded)") {
+		t.Errorf("unexpected listing:\n%s", out)
+	}
+	if ft.NewCount() != 0 {
+		t.Error("expected the listing to mark the files as seen")
+	}
+
+	cmds.Mentioned([]string{"load", "all"})
+	if len(fc.Files) != 1 || fc.Files[0].RelPath != "main.go" {
+		t.Fatalf("expected only main.go to be loaded, got %+v", fc.Files)
+	}
+
+	logged = nil
+	cmds.Mentioned([]string{"diff", "2"})
+	if !strings.Contains(strings.Join(logged, "\n"), "No pending suggested diff for main.go") {
+		t.Errorf("unexpected diff output: %v", logged)
+	}
+}
+
+func TestView(t *testing.T) {
+	t.Chdir(t.TempDir())
+	if err := os.WriteFile("main.go", []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile("blob.bin", []byte{'a', 0, 'b'}, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var logged []string
+	var viewed, content string
+	var line int
+	fc := files.NewFileContext()
+	cmds := NewFileCommands(Dependencies{
+		FileContext:   fc,
+		MessageLogger: func(role, content string) { logged = append(logged, content) },
+		OpenFileViewer: func(p, c string, l int) {
+			viewed, content, line = p, c, l
+		},
+	})
+
+	cmds.View([]string{"main.go:3"})
+	if viewed != "main.go" || content != "package main\n" || line != 3 {
+		t.Errorf("unexpected viewer call: %q %q %d", viewed, content, line)
+	}
+	if len(fc.Files) != 0 {
+		t.Error("expected /view not to load the file into the context")
+	}
+
+	for _, args := range [][]string{{"missing.go"}, {"blob.bin"}, {"../outside.go"}, {"."}, nil} {
+		viewed, logged = "", nil
+		cmds.View(args)
+		if viewed != "" || len(logged) != 1 {
+			t.Errorf("/view %v: expected an error message only, got viewer %q and %v", args, viewed, logged)
+		}
+	}
+}
+
+func TestViewCompare(t *testing.T) {
+	t.Chdir(t.TempDir())
+	if err := os.WriteFile("main.go", []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var logged []string
+	var loaded, disk string
+	fc := files.NewFileContext()
+	cmds := NewFileCommands(Dependencies{
+		FileContext:   fc,
+		MessageLogger: func(role, content string) { logged = append(logged, content) },
+		RefreshUI:     func() {},
+		OpenCompareViewer: func(p, l, d string) {
+			loaded, disk = l, d
+		},
+	})
+
+	cmds.View([]string{"--compare", "main.go"})
+	if loaded != "" || !strings.Contains(strings.Join(logged, "\n"), "main.go is not loaded") {
+		t.Errorf("expected an unloaded file to be refused, got %v", logged)
+	}
+
+	cmds.Load([]string{"main.go"})
+	logged = nil
+	cmds.View([]string{"--compare", "main.go"})
+	if loaded != "" || !strings.Contains(strings.Join(logged, "\n"), "matches the file on disk") {
+		t.Errorf("expected an unchanged file not to open the viewer, got %v", logged)
+	}
+
+	if err := os.WriteFile("main.go", []byte("package app\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cmds.View([]string{"--compare", "main.go"})
+	if loaded != "package main\n" || disk != "package app\n" {
+		t.Errorf("expected the loaded and disk content, got %q and %q", loaded, disk)
+	}
+}
+
+func TestUndoFiles(t *testing.T) {
+	t.Chdir(t.TempDir())
+	if err := os.WriteFile("main.go", []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var logged []string
+	fc := files.NewFileContext()
+	cmds := NewFileCommands(Dependencies{
+		FileContext:   fc,
+		MessageLogger: func(role, content string) { logged = append(logged, content) },
+		RefreshUI:     func() {},
+	})
+
+	cmds.Load([]string{"main.go"})
+	cmds.Unload([]string{"nothing*"}) // Removes nothing, so there is nothing to undo for it
+	cmds.Clear(nil)
+
+	logged = nil
+	cmds.UndoFiles(nil)
+	if len(fc.Files) != 1 || !strings.Contains(strings.Join(logged, "\n"), "Undid /clear") {
+		t.Errorf("expected /clear to be undone, got %d files and %v", len(fc.Files), logged)
+	}
+	cmds.UndoFiles(nil)
+	if len(fc.Files) != 0 {
+		t.Errorf("expected /load to be undone, got %d files", len(fc.Files))
+	}
+
+	logged = nil
+	cmds.UndoFiles(nil)
+	if !strings.Contains(strings.Join(logged, "\n"), "Nothing to undo") {
+		t.Errorf("expected nothing left to undo, got %v", logged)
+	}
+}
+
+func TestLoadFromGit(t *testing.T) {
+	initTestRepo(t, map[string]string{"a.go": "package a\n", "b.go": "package b\n", "c.go": "package c\n"})
+
+	var logged []string
+	fc := files.NewFileContext()
+	cmds := NewFileCommands(Dependencies{
+		FileContext:   fc,
+		MessageLogger: func(role, content string) { logged = append(logged, content) },
+		RefreshUI:     func() {},
+	})
+	loaded := func() []string {
+		var paths []string
+		for _, file := range fc.Files {
+			paths = append(paths, file.RelPath)
+		}
+		slices.Sort(paths)
+		return paths
+	}
+
+	logged = nil
+	cmds.Load([]string{"--modified"})
+	if len(fc.Files) != 0 || !strings.Contains(strings.Join(logged, "\n"), "git reports no modified files") {
+		t.Errorf("expected nothing to load in a clean tree, got %v", logged)
+	}
+
+	// A branch with a committed change, a staged change, an unstaged change and a new file
+	gitOutput("checkout", "-qb", "feature")
+	os.WriteFile("a.go", []byte("package a\n\nvar A = 1\n"), 0644)
+	gitOutput("commit", "-qam", "change a")
+	os.WriteFile("b.go", []byte("package b\n\nvar B = 1\n"), 0644)
+	gitOutput("add", "b.go")
+	os.WriteFile("c.go", []byte("package c\n\nvar C = 1\n"), 0644)
+	os.WriteFile("new.go", []byte("package a\n"), 0644)
+	os.WriteFile("blob.bin", []byte{0, 1, 2}, 0644)
+
+	cmds.Load([]string{"--staged"})
+	if got := loaded(); !slices.Equal(got, []string{"b.go"}) {
+		t.Errorf("--staged loaded %v", got)
+	}
+
+	cmds.Clear(nil)
+	logged = nil
+	cmds.Load([]string{"--modified"})
+	if got := loaded(); !slices.Equal(got, []string{"b.go", "c.go", "new.go"}) {
+		t.Errorf("--modified loaded %v", got)
+	}
+	if !strings.Contains(strings.Join(logged, "\n"), "Skipping 1 binary or non-file path(s): blob.bin") {
+		t.Errorf("expected the binary file to be skipped, got %v", logged)
+	}
+
+	cmds.Clear(nil)
+	cmds.Load([]string{"--branch"})
+	if got := loaded(); !slices.Equal(got, []string{"a.go"}) {
+		t.Errorf("--branch loaded %v", got)
+	}
+
+	cmds.Clear(nil)
+	cmds.Load([]string{"--branch", "main", "c.go"})
+	if got := loaded(); !slices.Equal(got, []string{"a.go", "c.go"}) {
+		t.Errorf("--branch main c.go loaded %v", got)
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	t.Chdir(t.TempDir())
+	if err := os.WriteFile("main.go", []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var logged []string
+	fc := files.NewFileContext()
+	cmds := NewFileCommands(Dependencies{
+		FileContext:   fc,
+		MessageLogger: func(role, content string) { logged = append(logged, content) },
+		RefreshUI:     func() {},
+	})
+
+	cmds.Load([]string{"main.go"})
+	cmds.Snapshot([]string{"create", "base"})
+	if err := os.WriteFile("main.go", []byte("package main\n\nfunc main() {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	logged = nil
+	cmds.Snapshot(nil)
+	if out := strings.Join(logged, "\n"); !strings.Contains(out, "base: 1 file(s), 1 changed since") {
+		t.Errorf("unexpected listing:\n%s", out)
+	}
+
+	logged = nil
+	cmds.Snapshot([]string{"restore", "base"})
+	if out := strings.Join(logged, "\n"); !strings.Contains(out, "Restored 1 file(s) from snapshot base") {
+		t.Errorf("unexpected restore output:\n%s", out)
+	}
+	if data, _ := os.ReadFile("main.go"); string(data) != "package main\n" {
+		t.Errorf("expected main.go restored, got %q", data)
+	}
+	if fc.Files[0].Content != "package main\n" {
+		t.Errorf("expected the loaded copy reloaded, got %q", fc.Files[0].Content)
+	}
+}
+
+func TestLoadLanguage(t *testing.T) {
+	t.Chdir(t.TempDir())
+	if err := os.WriteFile("deploy", []byte("print('deploying')\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	fc := files.NewFileContext()
+	var logged []string
+	cmds := NewFileCommands(Dependencies{
+		FileContext:   fc,
+		MessageLogger: func(role, content string) { logged = append(logged, content) },
+		RefreshUI:     func() {},
+	})
+
+	cmds.Load([]string{"deploy", "--lang"})
+	if len(fc.Files) != 0 || !strings.Contains(strings.Join(logged, "\n"), "Usage: /load <filepath> --lang") {
+		t.Fatalf("expected usage for --lang without a language, got %v", logged)
+	}
+
+	cmds.Load([]string{"deploy", "--lang", "Python3"})
+	if len(fc.Files) != 1 || fc.Files[0].Language != "python3" || !fc.Files[0].LanguageSet {
+		t.Fatalf("expected deploy labeled python3, got %+v", fc.Files)
+	}
+	cmds.Load([]string{"--lang=py", "deploy"})
+	if fc.Files[0].Language != "python" {
+		t.Errorf("Language = %q, want python", fc.Files[0].Language)
+	}
+	if !strings.Contains(fc.BuildContextPrompt(), "```python\n") {
+		t.Error("expected the context prompt fence to be labeled python")
+	}
+
+	// A reload keeps the language
+	if _, err := fc.ReloadFiles(nil); err != nil {
+		t.Fatal(err)
+	}
+	if fc.Files[0].Language != "python" {
+		t.Errorf("Language after reload = %q, want python", fc.Files[0].Language)
+	}
+}
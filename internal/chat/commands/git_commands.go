@@ -0,0 +1,371 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/antenore/deecli/internal/api"
+	"github.com/antenore/deecli/internal/commitlint"
+	"github.com/antenore/deecli/internal/errlog"
+	"github.com/antenore/deecli/internal/files"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// GitCommands handles git-related chat commands
+type GitCommands struct {
+	deps Dependencies
+}
+
+// CommitProposal is a commit waiting for "/git commit confirm"
+type CommitProposal struct {
+	Message string
+	Files   []files.ChangeEntry // Journal entries with uncommitted changes
+	Push    bool
+}
+
+// paths returns the absolute paths of the files to commit
+func (p *CommitProposal) paths() []string {
+	paths := make([]string, len(p.Files))
+	for i, file := range p.Files {
+		paths[i] = file.Path
+	}
+	return paths
+}
+
+const (
+	// commitMessageTimeout bounds generating a commit message
+	commitMessageTimeout = 60 * time.Second
+	// gitPushTimeout bounds pushing a commit
+	gitPushTimeout = 2 * time.Minute
+	// maxCommitDiffSize caps the diff sent to generate a commit message
+	maxCommitDiffSize = 60 * 1024
+)
+
+// NewGitCommands creates a new git commands handler
+func NewGitCommands(deps Dependencies) *GitCommands {
+	return &GitCommands{deps: deps}
+}
+
+// Git handles the /git command
+func (gc *GitCommands) Git(args []string) tea.Cmd {
+	if len(args) == 0 || args[0] != "commit" {
+		gc.deps.MessageLogger("system", "Usage: /git commit [-m auto|-m <message>] [--push]")
+		return nil
+	}
+	return gc.commit(args[1:])
+}
+
+// commit proposes a commit of the files in the change journal, or with
+// "confirm"/"cancel" acts on the pending proposal
+func (gc *GitCommands) commit(args []string) tea.Cmd {
+	if len(args) == 1 && args[0] == "confirm" {
+		return gc.confirmCommit()
+	}
+	if len(args) == 1 && args[0] == "cancel" {
+		if gc.deps.PendingCommit == nil {
+			gc.deps.MessageLogger("system", "No commit is waiting for approval")
+		} else {
+			gc.deps.SetPendingCommit(nil)
+			gc.deps.MessageLogger("system", "Commit cancelled")
+		}
+		return nil
+	}
+
+	message, push := "", false
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "--push":
+			push = true
+		case args[i] == "-m" && i+1 < len(args):
+			// The message runs up to the next flag
+			end := i + 1
+			for end < len(args) && args[end] != "--push" {
+				end++
+			}
+			message = strings.Join(args[i+1:end], " ")
+			i = end - 1
+		default:
+			gc.deps.MessageLogger("system", "Usage: /git commit [-m auto|-m <message>] [--push]")
+			return nil
+		}
+	}
+
+	changed, err := gc.changedFiles()
+	if err != nil {
+		gc.deps.ReportError(errlog.CategoryGeneral, "Failed to read the git status", err)
+		return nil
+	}
+	if len(changed) == 0 {
+		gc.deps.MessageLogger("system", "No uncommitted changes to files edited in this session")
+		return nil
+	}
+
+	if message != "" && message != "auto" {
+		proposal := &CommitProposal{Message: message, Files: changed, Push: push}
+		gc.deps.SetPendingCommit(proposal)
+		gc.deps.MessageLogger("system", describeCommit(proposal))
+		return nil
+	}
+
+	if gc.deps.APIClient == nil {
+		gc.deps.MessageLogger("system", "Please set DEEPSEEK_API_KEY environment variable, or pass the message with -m")
+		return nil
+	}
+
+	proposal := &CommitProposal{Files: changed, Push: push}
+	diff, err := gitOutput(append([]string{"diff", "HEAD", "--"}, proposal.paths()...)...)
+	if err != nil {
+		// Without a first commit there is no HEAD to diff against
+		diff, err = gitOutput(append([]string{"diff", "--"}, proposal.paths()...)...)
+	}
+	if err != nil {
+		gc.deps.ReportError(errlog.CategoryGeneral, "Failed to read the diff", err)
+		return nil
+	}
+	if len(diff) > maxCommitDiffSize {
+		diff = diff[:maxCommitDiffSize] + "\n[diff truncated]"
+	}
+
+	client := gc.deps.APIClient
+	// Snapshot the history so the background call is not affected by new messages
+	history := append([]api.Message(nil), gc.deps.APIMessages...)
+	rules := gc.lintRules()
+	gc.deps.MessageLogger("system", "📝 Writing a commit message...")
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), commitMessageTimeout)
+		defer cancel()
+		message, problems, err := generateCommitMessage(ctx, client, history, diff, rules)
+		if err != nil {
+			return CommandResultMsg{Message: "Failed to generate a commit message", Err: err, Category: errlog.Classify(err)}
+		}
+		if message == "" {
+			return CommandResultMsg{Message: "The generated commit message was empty; pass one with -m"}
+		}
+		proposal.Message = message
+		result := describeCommit(proposal)
+		if len(problems) > 0 {
+			result += fmt.Sprintf("\n⚠️ The message still breaks the commit rules after %d attempts: %s\n   Pass your own with -m",
+				maxCommitLintAttempts, strings.Join(problems, "; "))
+		}
+		return CommitProposedMsg{Proposal: proposal, Message: result}
+	}
+}
+
+// maxCommitLintAttempts is how many messages are generated before giving up
+// on one that follows the commit rules
+const maxCommitLintAttempts = 3
+
+// generateCommitMessage asks for a commit message and, when rules are set,
+// regenerates it with the reasons until it complies. The last message is
+// returned with its remaining problems if none does.
+func generateCommitMessage(ctx context.Context, client *api.Service, history []api.Message, diff string, rules *commitlint.Rules) (string, []string, error) {
+	if rules == nil {
+		message, err := client.GenerateCommitMessage(ctx, history, diff, "")
+		return message, nil, err
+	}
+
+	guidelines := rules.Describe()
+	var message string
+	var problems []string
+	for attempt := 0; attempt < maxCommitLintAttempts; attempt++ {
+		var err error
+		message, err = client.GenerateCommitMessage(ctx, history, diff, guidelines)
+		if err != nil {
+			return "", nil, err
+		}
+		if problems = commitlint.Lint(message, *rules); len(problems) == 0 {
+			return message, nil, nil
+		}
+		guidelines = fmt.Sprintf("%s\n\nA previous attempt was rejected:\n%s\nProblems: %s",
+			rules.Describe(), message, strings.Join(problems, "; "))
+	}
+	return message, problems, nil
+}
+
+// lintRules returns the configured commit message rules, or nil when linting is off
+func (gc *GitCommands) lintRules() *commitlint.Rules {
+	if gc.deps.ConfigManager == nil {
+		return nil
+	}
+	lint := gc.deps.ConfigManager.GetCommitLint()
+	if lint == nil {
+		return nil
+	}
+	return &commitlint.Rules{
+		Types:           lint.Types,
+		Scopes:          lint.Scopes,
+		RequireScope:    lint.RequireScope,
+		MaxHeaderLength: lint.MaxHeaderLength,
+	}
+}
+
+// changedFiles returns the journal entries that have uncommitted changes
+func (gc *GitCommands) changedFiles() ([]files.ChangeEntry, error) {
+	if gc.deps.FileContext == nil {
+		return nil, nil
+	}
+
+	var changed []files.ChangeEntry
+	for _, entry := range gc.deps.FileContext.Journal().Entries() {
+		status, err := gitOutput("status", "--porcelain", "--", entry.Path)
+		if err != nil {
+			return nil, err
+		}
+		if strings.TrimSpace(status) != "" {
+			changed = append(changed, entry)
+		}
+	}
+	return changed, nil
+}
+
+// confirmCommit stages and commits the pending proposal, then pushes if asked
+func (gc *GitCommands) confirmCommit() tea.Cmd {
+	proposal := gc.deps.PendingCommit
+	if proposal == nil {
+		gc.deps.MessageLogger("system", "Run /git commit first to prepare and review a commit")
+		return nil
+	}
+	gc.deps.SetPendingCommit(nil)
+
+	paths := proposal.paths()
+	if _, err := gitOutput(append([]string{"add", "--"}, paths...)...); err != nil {
+		gc.deps.ReportError(errlog.CategoryGeneral, "Failed to stage the files", err)
+		return nil
+	}
+	// Limit the commit to the journal files, leaving anything else staged as it was
+	if _, err := gitOutput(append([]string{"commit", "-m", proposal.Message, "--"}, paths...)...); err != nil {
+		gc.deps.ReportError(errlog.CategoryGeneral, "Commit failed", err)
+		return nil
+	}
+	gc.deps.FileContext.Journal().Forget(paths)
+
+	hash, _ := gitOutput("rev-parse", "--short", "HEAD")
+	subject := strings.SplitN(proposal.Message, "\n", 2)[0]
+	gc.deps.MessageLogger("system", fmt.Sprintf("✅ Committed %s %s", strings.TrimSpace(hash), subject))
+	if !proposal.Push {
+		return nil
+	}
+
+	gc.deps.MessageLogger("system", "⬆️ Pushing...")
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), gitPushTimeout)
+		defer cancel()
+		output, err := exec.CommandContext(ctx, "git", "push").CombinedOutput()
+		if err != nil {
+			err = fmt.Errorf("%w: %s", err, strings.TrimSpace(string(output)))
+			return CommandResultMsg{Message: "Push failed", Err: err, Category: errlog.Classify(err)}
+		}
+		return CommandResultMsg{Message: "⬆️ Pushed"}
+	}
+}
+
+// describeCommit shows a proposal for approval
+func describeCommit(proposal *CommitProposal) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("📝 **Proposed commit** of %d file(s):\n", len(proposal.Files)))
+	for _, file := range proposal.Files {
+		note := ""
+		if file.Applied {
+			note = " (applied AI diff)"
+		}
+		b.WriteString("  " + file.RelPath + note + "\n")
+	}
+	b.WriteString("\n" + proposal.Message + "\n\n")
+	if proposal.Push {
+		b.WriteString("Run /git commit confirm to commit and push, or /git commit cancel")
+	} else {
+		b.WriteString("Run /git commit confirm to commit, or /git commit cancel")
+	}
+	return b.String()
+}
+
+// gitChangedFiles lists the existing files selected by a /load git flag,
+// relative to the working directory: --modified for uncommitted changes and
+// new untracked files, --staged for the index, and --branch for the commits
+// on the current branch since it left base
+func gitChangedFiles(flag, base string) ([]string, error) {
+	var outputs []string
+	switch flag {
+	case "--modified":
+		changed, err := gitOutput("diff", "--name-only", "--relative", "--diff-filter=d", "HEAD")
+		if err != nil {
+			return nil, err
+		}
+		untracked, err := gitOutput("ls-files", "--others", "--exclude-standard")
+		if err != nil {
+			return nil, err
+		}
+		outputs = append(outputs, changed, untracked)
+	case "--staged":
+		staged, err := gitOutput("diff", "--name-only", "--relative", "--diff-filter=d", "--cached")
+		if err != nil {
+			return nil, err
+		}
+		outputs = append(outputs, staged)
+	case "--branch":
+		if base == "" {
+			var err error
+			if base, err = defaultBaseBranch(); err != nil {
+				return nil, err
+			}
+		}
+		changed, err := gitOutput("diff", "--name-only", "--relative", "--diff-filter=d", base+"...HEAD")
+		if err != nil {
+			return nil, err
+		}
+		outputs = append(outputs, changed)
+	default:
+		return nil, fmt.Errorf("unknown git flag: %s", flag)
+	}
+
+	seen := make(map[string]bool)
+	var paths []string
+	for _, output := range outputs {
+		for _, path := range strings.Split(output, "\n") {
+			if path = strings.TrimSpace(path); path != "" && !seen[path] {
+				seen[path] = true
+				paths = append(paths, path)
+			}
+		}
+	}
+	return paths, nil
+}
+
+// defaultBaseBranch returns the branch --branch compares against when none
+// is given: main or master, locally or on origin
+func defaultBaseBranch() (string, error) {
+	for _, branch := range []string{"main", "master", "origin/main", "origin/master"} {
+		if _, err := gitOutput("rev-parse", "--verify", "--quiet", branch); err == nil {
+			return branch, nil
+		}
+	}
+	return "", fmt.Errorf("no main or master branch found; name the base branch: /load --branch <base>")
+}
+
+// gitOutput runs git and returns its output, including stderr in errors
+func gitOutput(args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return string(output), nil
+}
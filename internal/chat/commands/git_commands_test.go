@@ -0,0 +1,112 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/antenore/deecli/internal/errlog"
+	"github.com/antenore/deecli/internal/files"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// initTestRepo creates a git repository in a new working directory with
+// files committed on the main branch
+func initTestRepo(t *testing.T, committed map[string]string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	t.Chdir(t.TempDir())
+	for _, args := range [][]string{
+		{"init", "-q", "-b", "main"},
+		{"config", "user.email", "dev@example.com"},
+		{"config", "user.name", "Dev"},
+	} {
+		if _, err := gitOutput(args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for name, content := range committed {
+		if err := os.WriteFile(name, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := gitOutput("add", "."); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := gitOutput("commit", "-qm", "initial"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGitCommit(t *testing.T) {
+	initTestRepo(t, map[string]string{"main.go": "package main\n", "other.go": "package main\n"})
+
+	fc := files.NewFileContext()
+	if err := fc.LoadFile("main.go"); err != nil {
+		t.Fatal(err)
+	}
+	var logged []string
+	var pending *CommitProposal
+	// Handlers are rebuilt for every command, as in the chat
+	git := func(args ...string) tea.Cmd {
+		return NewGitCommands(Dependencies{
+			FileContext:   fc,
+			MessageLogger: func(role, content string) { logged = append(logged, content) },
+			ReportError: func(_ errlog.Category, summary string, err error) {
+				t.Fatalf("%s: %v", summary, err)
+			},
+			PendingCommit:    pending,
+			SetPendingCommit: func(proposal *CommitProposal) { pending = proposal },
+		}).Git(args)
+	}
+
+	git("commit", "-m", "Update", "main")
+	if pending != nil || !strings.Contains(logged[len(logged)-1], "No uncommitted changes") {
+		t.Fatalf("expected no changes to commit, got %q", logged[len(logged)-1])
+	}
+
+	// other.go is changed and staged but was not edited through the session
+	os.WriteFile("main.go", []byte("package main\n\nfunc main() {}\n"), 0644)
+	os.WriteFile("other.go", []byte("package other\n"), 0644)
+	gitOutput("add", "other.go")
+	if _, err := fc.ReloadFiles(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	git("commit", "-m", "Update", "main")
+	if pending == nil || pending.Message != "Update main" || pending.Push {
+		t.Fatalf("pending commit = %+v", pending)
+	}
+	if cmd := git("commit", "confirm"); cmd != nil {
+		t.Fatal("expected no push")
+	}
+
+	if subject, _ := gitOutput("log", "-1", "--format=%s"); strings.TrimSpace(subject) != "Update main" {
+		t.Errorf("last commit = %q", subject)
+	}
+	if committed, _ := gitOutput("show", "--name-only", "--format=", "HEAD"); strings.TrimSpace(committed) != "main.go" {
+		t.Errorf("committed files = %q, want main.go", committed)
+	}
+	if staged, _ := gitOutput("diff", "--cached", "--name-only"); strings.TrimSpace(staged) != "other.go" {
+		t.Errorf("staged files = %q, want other.go left staged", staged)
+	}
+	if entries := fc.Journal().Entries(); len(entries) != 0 {
+		t.Errorf("journal = %+v after commit", entries)
+	}
+}
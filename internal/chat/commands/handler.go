@@ -16,6 +16,7 @@ package commands
 import (
 	"strings"
 
+	"github.com/antenore/deecli/internal/metrics"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
@@ -25,6 +26,9 @@ type Handler struct {
 	aiCommands     *AICommands
 	configCommands *ConfigCommands
 	systemCommands *SystemCommands
+	sessionCommands *SessionCommands
+	gitCommands    *GitCommands
+	usageStats     *metrics.Recorder
 }
 
 // NewHandler creates a new command handler
@@ -34,6 +38,9 @@ func NewHandler(deps Dependencies) *Handler {
 		aiCommands:     NewAICommands(deps),
 		configCommands: NewConfigCommands(deps),
 		systemCommands: NewSystemCommands(deps),
+		sessionCommands: NewSessionCommands(deps),
+		gitCommands:    NewGitCommands(deps),
+		usageStats:     deps.UsageStats,
 	}
 }
 
@@ -47,6 +54,14 @@ func (h *Handler) Handle(input string) tea.Cmd {
 	command := parts[0]
 	args := parts[1:]
 
+	// Only known command names are counted, never what the user typed
+	known := true
+	defer func() {
+		if known {
+			h.usageStats.Command(command)
+		}
+	}()
+
 	switch command {
 	// File commands
 	case "/load":
@@ -61,6 +76,16 @@ func (h *Handler) Handle(input string) tea.Cmd {
 		return h.fileCommands.Unload(args)
 	case "/reload":
 		return h.fileCommands.Reload(args)
+	case "/apply":
+		return h.fileCommands.Apply(args)
+	case "/snapshot":
+		return h.fileCommands.Snapshot(args)
+	case "/undo-files":
+		return h.fileCommands.UndoFiles(args)
+	case "/mentioned":
+		return h.fileCommands.Mentioned(args)
+	case "/view":
+		return h.fileCommands.View(args)
 
 	// AI commands
 	case "/analyze":
@@ -71,6 +96,20 @@ func (h *Handler) Handle(input string) tea.Cmd {
 		return h.aiCommands.Improve(args)
 	case "/edit":
 		return h.aiCommands.Edit(args)
+	case "/init":
+		return h.aiCommands.Init(args)
+	case "/pr":
+		return h.aiCommands.PR(args)
+	case "/review":
+		return h.aiCommands.Review(args)
+	case "/compact":
+		return h.aiCommands.Compact(args)
+	case "/suggest":
+		return h.aiCommands.Suggest(args)
+	case "/send":
+		return h.aiCommands.Send(args)
+	case "/docs":
+		return h.aiCommands.Docs(args)
 
 	// Config commands
 	case "/config":
@@ -79,6 +118,22 @@ func (h *Handler) Handle(input string) tea.Cmd {
 		return h.configCommands.KeySetup(args)
 	case "/history":
 		return h.configCommands.History(args)
+	case "/mode":
+		return h.configCommands.Mode(args)
+	case "/postprocess":
+		return h.configCommands.PostProcess(args)
+
+	// Session commands
+	case "/session", "/sessions":
+		return h.sessionCommands.Session(args)
+	case "/fork":
+		return h.sessionCommands.Fork(args)
+	case "/share":
+		return h.sessionCommands.Share(args)
+
+	// Git commands
+	case "/git":
+		return h.gitCommands.Git(args)
 
 	// System commands
 	case "/help":
@@ -89,8 +144,28 @@ func (h *Handler) Handle(input string) tea.Cmd {
 		return h.systemCommands.Create(args)
 	case "/tools":
 		return h.systemCommands.Tools(args)
+	case "/errors":
+		return h.systemCommands.Errors(args)
+	case "/audit":
+		return h.systemCommands.Audit(args)
+	case "/stats":
+		return h.systemCommands.Stats(args)
+	case "/sources":
+		return h.systemCommands.Sources(args)
+	case "/sections":
+		return h.systemCommands.Sections(args)
+	case "/tab", "/tabs":
+		return h.systemCommands.Tab(args)
+	case "/tasks":
+		return h.systemCommands.Tasks(args)
+	case "/dryrun":
+		return h.systemCommands.DryRun(args)
+	case "/pprof":
+		// Hidden: profiling aid for developers, not listed in /help
+		return h.systemCommands.Pprof(args)
 
 	default:
+		known = false
 		h.systemCommands.ShowUnknownCommand(command)
 		return nil
 	}
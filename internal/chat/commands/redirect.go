@@ -0,0 +1,90 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/antenore/deecli/internal/files"
+)
+
+// splitRedirect separates a trailing "> file" (or ">file") from the
+// arguments of an analysis command. It returns the arguments before it and
+// the file, "" without redirection, and false if the redirection is not
+// followed by exactly one file.
+func splitRedirect(args []string) ([]string, string, bool) {
+	for i, arg := range args {
+		if !strings.HasPrefix(arg, ">") {
+			continue
+		}
+		rest := args[i+1:]
+		output := strings.TrimPrefix(arg, ">")
+		if output == "" && len(rest) > 0 {
+			output, rest = rest[0], rest[1:]
+		}
+		if output == "" || len(rest) > 0 {
+			return nil, "", false
+		}
+		return args[:i], output, true
+	}
+	return args, "", true
+}
+
+// confirmOutput reports whether the result of a command can be written to
+// output. A file that exists is only overwritten when the same command is
+// run again; until then the user is told how to confirm.
+func (ai *AICommands) confirmOutput(output string) bool {
+	if output == "" {
+		return true
+	}
+	info, err := os.Stat(output)
+	if err != nil {
+		ai.deps.SetPendingOverwrite("")
+		return true
+	}
+	if info.IsDir() {
+		ai.deps.MessageLogger("system", fmt.Sprintf("❌ %s is a directory; give a file to write the result to", output))
+		return false
+	}
+	abs, _ := filepath.Abs(output)
+	if ai.deps.PendingOverwrite == abs {
+		ai.deps.SetPendingOverwrite("")
+		return true
+	}
+	ai.deps.SetPendingOverwrite(abs)
+	ai.deps.MessageLogger("system", fmt.Sprintf("⚠️ %s already exists. Run the same command again to overwrite it", output))
+	return false
+}
+
+// filesFor returns the files an analysis command works on: all loaded files
+// without patterns (nil), else the files the patterns match, read for this
+// command only without changing the context
+func (ai *AICommands) filesFor(patterns []string) ([]files.LoadedFile, error) {
+	if len(patterns) == 0 {
+		return nil, nil
+	}
+	scratch := files.NewFileContext()
+	scratch.Loader = ai.deps.FileContext.Loader
+	scratch.MaxContext = ai.deps.FileContext.MaxContext
+	if err := scratch.LoadFiles(patterns); err != nil {
+		return nil, err
+	}
+	if len(scratch.Files) == 0 {
+		return nil, fmt.Errorf("no files match %s", strings.Join(patterns, " "))
+	}
+	return scratch.Files, nil
+}
@@ -0,0 +1,268 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/antenore/deecli/internal/errlog"
+	"github.com/antenore/deecli/internal/redact"
+	"github.com/antenore/deecli/internal/share"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// sharesDir is where /share writes conversation exports
+var sharesDir = filepath.Join(".deecli", "shares")
+
+// gistUploadTimeout bounds the gist upload
+const gistUploadTimeout = 30 * time.Second
+
+// SessionCommands handles session-related chat commands
+type SessionCommands struct {
+	deps Dependencies
+}
+
+// NewSessionCommands creates a new session commands handler
+func NewSessionCommands(deps Dependencies) *SessionCommands {
+	return &SessionCommands{deps: deps}
+}
+
+// Session handles the /session command
+func (sc *SessionCommands) Session(args []string) tea.Cmd {
+	if sc.deps.SessionManager == nil {
+		sc.deps.MessageLogger("system", "❌ Session storage not available")
+		return nil
+	}
+
+	if len(args) == 0 {
+		sc.listSessions()
+		return nil
+	}
+
+	switch args[0] {
+	case "list", "ls":
+		sc.listSessions()
+	case "title":
+		sc.setTitle(strings.Join(args[1:], " "))
+	default:
+		sc.deps.MessageLogger("system", "Usage: /session [list|title <text>]")
+	}
+	return nil
+}
+
+// listSessions shows recent sessions with their titles
+func (sc *SessionCommands) listSessions() {
+	sessionList, err := sc.deps.SessionManager.ListSessions(20)
+	if err != nil {
+		sc.deps.MessageLogger("system", fmt.Sprintf("❌ Failed to list sessions: %v", err))
+		return
+	}
+
+	if len(sessionList) == 0 {
+		sc.deps.MessageLogger("system", "No sessions yet")
+		return
+	}
+
+	var output strings.Builder
+	output.WriteString("📋 Recent sessions:\n")
+	for _, session := range sessionList {
+		marker := "  "
+		if sc.deps.CurrentSession != nil && session.ID == sc.deps.CurrentSession.ID {
+			marker = "▶ "
+		}
+
+		title := session.Title
+		if title == "" {
+			title = "Untitled"
+		}
+
+		output.WriteString(fmt.Sprintf("%s#%d  %s  (%d messages, %s)\n",
+			marker, session.ID, title, session.MessageCount,
+			session.UpdatedAt.Local().Format("2006-01-02 15:04")))
+	}
+
+	sc.deps.MessageLogger("system", strings.TrimRight(output.String(), "\n"))
+}
+
+// setTitle renames the current session
+func (sc *SessionCommands) setTitle(title string) {
+	title = strings.TrimSpace(title)
+	if title == "" {
+		sc.deps.MessageLogger("system", "Usage: /session title <text>")
+		return
+	}
+
+	if sc.deps.CurrentSession == nil {
+		sc.deps.MessageLogger("system", "❌ No active session")
+		return
+	}
+
+	if err := sc.deps.SessionManager.SetSessionTitle(sc.deps.CurrentSession.ID, title); err != nil {
+		sc.deps.MessageLogger("system", fmt.Sprintf("❌ Failed to set session title: %v", err))
+		return
+	}
+
+	sc.deps.CurrentSession.Title = title
+	sc.deps.MessageLogger("system", fmt.Sprintf("✅ Session title set to: %s", title))
+}
+
+// Fork handles the /fork command: it copies the conversation into a new
+// session and continues there, so the original session stays as it was. The
+// loaded files carry over to the fork.
+func (sc *SessionCommands) Fork(args []string) tea.Cmd {
+	if sc.deps.SessionManager == nil || sc.deps.CurrentSession == nil {
+		sc.deps.MessageLogger("system", "❌ Session storage not available")
+		return nil
+	}
+
+	original := sc.deps.CurrentSession
+	title := strings.TrimSpace(strings.Join(args, " "))
+	if title == "" {
+		name := original.Title
+		if name == "" {
+			name = fmt.Sprintf("#%d", original.ID)
+		}
+		title = "Fork of " + name
+	}
+
+	fork, err := sc.deps.SessionManager.ForkSession(original.ID, title)
+	if err != nil {
+		sc.deps.MessageLogger("system", fmt.Sprintf("❌ Failed to fork session: %v", err))
+		return nil
+	}
+	if sc.deps.SwitchSession != nil {
+		sc.deps.SwitchSession(fork)
+	}
+
+	sc.deps.MessageLogger("system", fmt.Sprintf("🍴 Forked session #%d into #%d: %s\nNew messages go to the fork; #%d is kept as it was. See /session list.",
+		original.ID, fork.ID, title, original.ID))
+	return nil
+}
+
+// Share handles the /share command: it exports the conversation with secrets
+// redacted and local paths relativized, to a file or, once confirmed, to a gist
+func (sc *SessionCommands) Share(args []string) tea.Cmd {
+	if len(args) > 0 && args[0] == "gist" {
+		if len(args) > 1 && args[1] == "confirm" {
+			return sc.uploadGist()
+		}
+		path, err := sc.writeShare("")
+		if err != nil {
+			sc.deps.ReportError(errlog.CategoryGeneral, "Failed to export the conversation", err)
+			return nil
+		}
+		sc.deps.SetPendingGist(path)
+		sc.deps.MessageLogger("system", fmt.Sprintf("📤 Redacted export written to %s\n   Review it, then run /share gist confirm to upload it as a secret GitHub gist", path))
+		return nil
+	}
+
+	var path string
+	switch {
+	case len(args) == 0:
+	case args[0] == "file" && len(args) == 2:
+		path = args[1]
+	default:
+		sc.deps.MessageLogger("system", "Usage: /share [file <path>|gist [confirm]]")
+		return nil
+	}
+
+	path, err := sc.writeShare(path)
+	if err != nil {
+		sc.deps.ReportError(errlog.CategoryGeneral, "Failed to export the conversation", err)
+		return nil
+	}
+	sc.deps.MessageLogger("system", fmt.Sprintf("📤 Conversation exported to %s (secrets redacted, paths relativized)", path))
+	return nil
+}
+
+// writeShare renders the conversation and writes it to path, or to a new
+// file in sharesDir when path is empty
+func (sc *SessionCommands) writeShare(path string) (string, error) {
+	content, err := sc.shareMarkdown()
+	if err != nil {
+		return "", err
+	}
+	if path == "" {
+		path = filepath.Join(sharesDir, fmt.Sprintf("conversation-%s.md", time.Now().Format("20060102-150405")))
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// shareMarkdown renders the conversation for sharing
+func (sc *SessionCommands) shareMarkdown() (string, error) {
+	if len(sc.deps.APIMessages) == 0 {
+		return "", fmt.Errorf("there is no conversation to share yet")
+	}
+
+	var patterns []string
+	if sc.deps.ConfigManager != nil {
+		patterns = sc.deps.ConfigManager.GetRedactPatterns()
+	}
+	redactor, err := redact.New(patterns)
+	if err != nil {
+		return "", err
+	}
+
+	opts := share.Options{Redactor: redactor, Time: time.Now()}
+	opts.BaseDir, _ = os.Getwd()
+	opts.HomeDir, _ = os.UserHomeDir()
+	if sc.deps.ConfigManager != nil {
+		opts.Timestamps = sc.deps.ConfigManager.GetMessageTimestamps() != "off"
+	}
+	if sc.deps.CurrentSession != nil {
+		opts.Title = sc.deps.CurrentSession.Title
+	}
+	return share.Markdown(sc.deps.APIMessages, opts), nil
+}
+
+// uploadGist uploads the export prepared by "/share gist"
+func (sc *SessionCommands) uploadGist() tea.Cmd {
+	if sc.deps.PendingGist == "" {
+		sc.deps.MessageLogger("system", "Run /share gist first to prepare and review the export")
+		return nil
+	}
+	content, err := os.ReadFile(sc.deps.PendingGist)
+	if err != nil {
+		sc.deps.ReportError(errlog.CategoryGeneral, "Failed to read the prepared export", err)
+		return nil
+	}
+	token := ""
+	if sc.deps.ConfigManager != nil {
+		token = sc.deps.ConfigManager.GetGitHubToken()
+	}
+	filename := filepath.Base(sc.deps.PendingGist)
+	sc.deps.SetPendingGist("")
+
+	sc.deps.MessageLogger("system", "📤 Uploading gist...")
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), gistUploadTimeout)
+		defer cancel()
+		url, err := share.CreateGist(ctx, token, filename, "DeeCLI conversation", string(content))
+		if err != nil {
+			return CommandResultMsg{Message: "Failed to upload the gist", Err: err, Category: errlog.Classify(err)}
+		}
+		return CommandResultMsg{Message: "🔗 Conversation shared as a secret gist: " + url}
+	}
+}
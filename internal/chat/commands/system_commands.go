@@ -15,9 +15,19 @@ package commands
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/antenore/deecli/internal/audit"
 	"github.com/antenore/deecli/internal/editor"
+	"github.com/antenore/deecli/internal/errlog"
+	"github.com/antenore/deecli/internal/metrics"
+	"github.com/antenore/deecli/internal/tools"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
@@ -51,11 +61,7 @@ func (sc *SystemCommands) Create(args []string) tea.Cmd {
 	}
 
 	// Use the editor module for new file creation
-	config := editor.Config{
-		MessageProvider: func() []string { return sc.deps.Messages },
-		MessageLogger:   sc.deps.MessageLogger,
-	}
-	return editor.CreateAndEditNewFile(args[0], config)
+	return editor.CreateAndEditNewFile(args[0], editorConfig(sc.deps))
 }
 
 // Tools handles the /tools command
@@ -65,8 +71,8 @@ func (sc *SystemCommands) Tools(args []string) tea.Cmd {
 		return nil
 	}
 
-	tools := sc.deps.ToolsRegistry.GetAll()
-	if len(tools) == 0 {
+	registered := sc.deps.ToolsRegistry.GetAll()
+	if len(registered) == 0 {
 		sc.deps.MessageLogger("system", "🔧 No tools are currently registered")
 		return nil
 	}
@@ -74,8 +80,19 @@ func (sc *SystemCommands) Tools(args []string) tea.Cmd {
 	var output strings.Builder
 	output.WriteString("🔧 **Available AI Tools**\n\n")
 
-	for _, tool := range tools {
-		output.WriteString(fmt.Sprintf("**%s**: %s\n", tool.Name(), tool.Description()))
+	for _, tool := range registered {
+		output.WriteString(fmt.Sprintf("**%s**: %s", tool.Name(), tool.Description()))
+		if _, ok := tool.(tools.SandboxedTool); ok {
+			caps := []string{"no host access"}
+			if declared := tools.DeclaredCapabilities(tool); len(declared) > 0 {
+				caps = caps[:0]
+				for _, c := range declared {
+					caps = append(caps, string(c))
+				}
+			}
+			output.WriteString(fmt.Sprintf(" (sandboxed: %s)", strings.Join(caps, ", ")))
+		}
+		output.WriteString("\n")
 	}
 
 	output.WriteString("\nAI can autonomously use these tools with your approval to gather information and help with your requests.")
@@ -84,6 +101,414 @@ func (sc *SystemCommands) Tools(args []string) tea.Cmd {
 	return nil
 }
 
+// errorsShownByDefault is how many errors /errors lists without "all"
+const errorsShownByDefault = 10
+
+// Errors handles the /errors command
+func (sc *SystemCommands) Errors(args []string) tea.Cmd {
+	if sc.deps.ErrorLog == nil {
+		sc.deps.MessageLogger("system", "Error log not available")
+		return nil
+	}
+
+	limit := errorsShownByDefault
+	if len(args) > 0 {
+		switch args[0] {
+		case "clear":
+			sc.deps.ErrorLog.Clear()
+			sc.deps.MessageLogger("system", "🧹 Error log cleared")
+			return nil
+		case "all":
+			limit = 0
+		default:
+			sc.deps.MessageLogger("system", "Usage: /errors [all|clear]")
+			return nil
+		}
+	}
+
+	entries := sc.deps.ErrorLog.Recent(limit)
+	if len(entries) == 0 {
+		sc.deps.MessageLogger("system", "✅ No errors recorded in this session")
+		return nil
+	}
+
+	var output strings.Builder
+	output.WriteString("🧾 **Recent errors** (newest first)\n\n")
+	for _, entry := range entries {
+		output.WriteString(fmt.Sprintf("%s [%s] %s (%s)\n",
+			entry.Time.Format("15:04:05"), entry.Category.Code(), entry.Summary, entry.Category))
+		if entry.Details != "" && entry.Details != entry.Summary {
+			output.WriteString(fmt.Sprintf("    %s\n", entry.Details))
+		}
+	}
+	output.WriteString(fmt.Sprintf("\nCodes: %s=config, %s=network, %s=context too large, %s=tool failure, %s=other",
+		errlog.CategoryConfig.Code(), errlog.CategoryNetwork.Code(), errlog.CategoryContext.Code(),
+		errlog.CategoryTool.Code(), errlog.CategoryGeneral.Code()))
+
+	sc.deps.MessageLogger("system", output.String())
+	return nil
+}
+
+// auditShownByDefault is how many tool calls /audit lists without "all"
+const auditShownByDefault = 20
+
+// auditArgumentsWidth is how much of the arguments /audit shows per call
+const auditArgumentsWidth = 80
+
+// Audit handles the /audit command, listing recorded tool calls
+func (sc *SystemCommands) Audit(args []string) tea.Cmd {
+	if sc.deps.AuditLog == nil {
+		sc.deps.MessageLogger("system", "Audit log not available")
+		return nil
+	}
+
+	limit := auditShownByDefault
+	if len(args) > 0 {
+		if args[0] == "all" {
+			limit = 0
+		} else if n, err := strconv.Atoi(args[0]); err == nil && n > 0 {
+			limit = n
+		} else {
+			sc.deps.MessageLogger("system", "Usage: /audit [all|<count>]")
+			return nil
+		}
+	}
+
+	entries, err := sc.deps.AuditLog.Recent(limit)
+	if err != nil {
+		sc.deps.ReportError(errlog.CategoryGeneral, "Failed to read audit log", err)
+		return nil
+	}
+	if len(entries) == 0 {
+		sc.deps.MessageLogger("system", fmt.Sprintf("📋 No tool calls recorded in %s", sc.deps.AuditLog.Path()))
+		return nil
+	}
+
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("📋 **Tool calls** (newest first, from %s)\n\n", sc.deps.AuditLog.Path()))
+	for _, entry := range entries {
+		output.WriteString(fmt.Sprintf("%s %s [%s] %s", entry.Time.Local().Format("2006-01-02 15:04:05"), entry.Tool, entry.Decision, entry.Status()))
+		if entry.Decision != audit.DecisionDenied {
+			output.WriteString(fmt.Sprintf(", %dms, %d bytes", entry.DurationMs, entry.ResultSize))
+		}
+		if entry.MessageID > 0 {
+			output.WriteString(fmt.Sprintf(", message #%d", entry.MessageID))
+		}
+		output.WriteString("\n")
+		if arguments := string(entry.Arguments); arguments != "" && arguments != "{}" {
+			if runes := []rune(arguments); len(runes) > auditArgumentsWidth {
+				arguments = string(runes[:auditArgumentsWidth]) + "…"
+			}
+			output.WriteString(fmt.Sprintf("    args: %s\n", arguments))
+		}
+		if entry.Error != "" {
+			output.WriteString(fmt.Sprintf("    error: %s\n", entry.Error))
+		}
+	}
+
+	sc.deps.MessageLogger("system", output.String())
+	return nil
+}
+
+// Stats shows the usage statistics kept on this machine
+func (sc *SystemCommands) Stats(args []string) tea.Cmd {
+	path := sc.deps.UsageStats.Path()
+	if path == "" {
+		sc.deps.MessageLogger("system", "📊 Usage statistics are off (telemetry: off); set telemetry: local to keep them on this machine")
+		return nil
+	}
+
+	if len(args) > 0 {
+		switch args[0] {
+		case "reset":
+			if err := metrics.Reset(path); err != nil {
+				sc.deps.ReportError(errlog.CategoryGeneral, "Failed to reset usage statistics", err)
+				return nil
+			}
+			sc.deps.MessageLogger("system", "🧹 Usage statistics reset")
+		case "export":
+			target := "deecli-stats.json"
+			if len(args) > 1 {
+				target = args[1]
+			}
+			if _, err := metrics.Export(path, target); err != nil {
+				sc.deps.ReportError(errlog.CategoryGeneral, "Failed to export usage statistics", err)
+				return nil
+			}
+			sc.deps.MessageLogger("system", fmt.Sprintf("📤 Usage statistics written to %s; nothing is sent, share the file if you want to", target))
+		default:
+			sc.deps.MessageLogger("system", "Usage: /stats [reset|export [file]]")
+		}
+		return nil
+	}
+
+	stats, err := metrics.Load(path)
+	if err != nil {
+		sc.deps.ReportError(errlog.CategoryGeneral, "Failed to read usage statistics", err)
+		return nil
+	}
+	if len(stats.Commands) == 0 && len(stats.Requests) == 0 {
+		sc.deps.MessageLogger("system", fmt.Sprintf("📊 No usage recorded yet in %s", path))
+		return nil
+	}
+
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("📊 **Usage statistics** since %s (kept in %s, never sent)\n", stats.Since.Local().Format("2006-01-02"), path))
+	if len(stats.Commands) > 0 {
+		output.WriteString("\nCommands:\n")
+		for _, name := range stats.CommandNames() {
+			output.WriteString(fmt.Sprintf("  %-20s %d\n", name, stats.Commands[name]))
+		}
+	}
+	if len(stats.Requests) > 0 {
+		output.WriteString("\nAPI requests:\n")
+		for _, name := range stats.Workflows() {
+			t := stats.Requests[name]
+			output.WriteString(fmt.Sprintf("  %-12s %d requests, avg %v, p50 %v, p95 %v, max %v",
+				name, t.Count, t.Average(), t.Percentile(50), t.Percentile(95), time.Duration(t.MaxMs)*time.Millisecond))
+			if t.Failures > 0 {
+				output.WriteString(fmt.Sprintf(", %d failed", t.Failures))
+			}
+			output.WriteString("\n")
+		}
+	}
+	output.WriteString("\n/stats export [file] writes them as JSON, /stats reset starts over")
+
+	sc.deps.MessageLogger("system", output.String())
+	return nil
+}
+
+// Sources handles the /sources command, expanding the footnote under the last
+// response into the audit log entries of the tool calls it cites
+func (sc *SystemCommands) Sources(args []string) tea.Cmd {
+	if len(sc.deps.Sources) == 0 {
+		sc.deps.MessageLogger("system", "📎 The last response did not use any tools")
+		return nil
+	}
+
+	var output strings.Builder
+	output.WriteString("📎 **Sources of the last response**")
+	if sc.deps.AuditLog != nil {
+		output.WriteString(fmt.Sprintf(" (recorded in %s)", sc.deps.AuditLog.Path()))
+	}
+	output.WriteString("\n\n")
+	for i, entry := range sc.deps.Sources {
+		output.WriteString(fmt.Sprintf("[%d] %s %s [%s] %s, %dms, %d bytes", i+1, entry.Time.Local().Format("2006-01-02 15:04:05"),
+			entry.Tool, entry.Decision, entry.Status(), entry.DurationMs, entry.ResultSize))
+		if entry.ToolCallID != "" {
+			output.WriteString(fmt.Sprintf(", call %s", entry.ToolCallID))
+		}
+		output.WriteString("\n")
+		if arguments := string(entry.Arguments); arguments != "" && arguments != "{}" {
+			if runes := []rune(arguments); len(runes) > auditArgumentsWidth {
+				arguments = string(runes[:auditArgumentsWidth]) + "…"
+			}
+			output.WriteString(fmt.Sprintf("    args: %s\n", arguments))
+		}
+		if entry.Error != "" {
+			output.WriteString(fmt.Sprintf("    error: %s\n", entry.Error))
+		}
+	}
+
+	sc.deps.MessageLogger("system", output.String())
+	return nil
+}
+
+// Tab handles the /tab and /tabs commands. Each tab is a separate chat with
+// its own conversation, files and requests; with no argument they are listed.
+func (sc *SystemCommands) Tab(args []string) tea.Cmd {
+	usage := "Usage: /tab [new [name]|<number>|rename <name>|close]"
+	msg := TabMsg{Action: "list"}
+	if len(args) > 0 {
+		switch args[0] {
+		case "new":
+			msg = TabMsg{Action: "new", Name: strings.Join(args[1:], " ")}
+		case "close":
+			msg.Action = "close"
+		case "rename":
+			if len(args) < 2 {
+				sc.deps.MessageLogger("system", usage)
+				return nil
+			}
+			msg = TabMsg{Action: "rename", Name: strings.Join(args[1:], " ")}
+		default:
+			n, err := strconv.Atoi(args[0])
+			if err != nil || n < 1 {
+				sc.deps.MessageLogger("system", usage)
+				return nil
+			}
+			msg = TabMsg{Action: "switch", Index: n - 1}
+		}
+	}
+	return func() tea.Msg { return msg }
+}
+
+// Tasks handles the /tasks command: with no argument it lists the background
+// tasks, "/tasks cancel <n>" stops one and "/tasks cancel" stops them all
+func (sc *SystemCommands) Tasks(args []string) tea.Cmd {
+	if sc.deps.Tasks == nil {
+		sc.deps.MessageLogger("system", "Background tasks not available")
+		return nil
+	}
+
+	if len(args) == 0 {
+		running := sc.deps.Tasks.Tasks()
+		if len(running) == 0 {
+			sc.deps.MessageLogger("system", "No background tasks. /analyze, /explain, /improve and /review run as tasks.")
+			return nil
+		}
+		var b strings.Builder
+		b.WriteString("⚙️ **Background tasks** (/tasks cancel <n> stops one)\n\n")
+		for _, task := range running {
+			b.WriteString("- " + task.Status() + "\n")
+		}
+		sc.deps.MessageLogger("system", b.String())
+		return nil
+	}
+
+	if args[0] != "cancel" || len(args) > 2 {
+		sc.deps.MessageLogger("system", "Usage: /tasks [cancel [number]]")
+		return nil
+	}
+	if len(args) == 1 {
+		if len(sc.deps.Tasks.Tasks()) == 0 {
+			sc.deps.MessageLogger("system", "No background tasks to cancel")
+			return nil
+		}
+		sc.deps.Tasks.CancelAll()
+		sc.deps.MessageLogger("system", "⏹️ Cancelling all background tasks...")
+		sc.deps.RefreshUI()
+		return nil
+	}
+	id, err := strconv.Atoi(strings.TrimPrefix(args[1], "#"))
+	if err != nil || !sc.deps.Tasks.Cancel(id) {
+		sc.deps.MessageLogger("system", fmt.Sprintf("No running task %s (see /tasks)", args[1]))
+		return nil
+	}
+	sc.deps.MessageLogger("system", fmt.Sprintf("⏹️ Cancelling task #%d...", id))
+	sc.deps.RefreshUI()
+	return nil
+}
+
+// Sections handles the /sections command: with no argument it lists the
+// headings and code blocks of the chat, with a number it scrolls to one
+func (sc *SystemCommands) Sections(args []string) tea.Cmd {
+	if sc.deps.Sections == nil || sc.deps.GotoSection == nil {
+		sc.deps.MessageLogger("system", "Section navigation not available")
+		return nil
+	}
+
+	if len(args) > 0 {
+		n, err := strconv.Atoi(args[0])
+		if err != nil || !sc.deps.GotoSection(n-1) {
+			sc.deps.MessageLogger("system", "Usage: /sections [number] (see /sections for the numbers)")
+		}
+		return nil
+	}
+
+	titles := sc.deps.Sections()
+	if len(titles) == 0 {
+		sc.deps.MessageLogger("system", "🔖 No headings or code blocks in the chat yet")
+		return nil
+	}
+
+	var output strings.Builder
+	output.WriteString("🔖 **Sections** (/sections <number> jumps to one; with the chat focused, [ and ] move between them)\n\n")
+	for i, title := range titles {
+		output.WriteString(fmt.Sprintf("%3d  %s\n", i+1, title))
+	}
+	sc.deps.MessageLogger("system", output.String())
+	return nil
+}
+
+// DryRun handles the /dryrun command: with no argument it shows the current mode
+func (sc *SystemCommands) DryRun(args []string) tea.Cmd {
+	if sc.deps.SetDryRun == nil {
+		sc.deps.MessageLogger("system", "🔧 Function calling tools are not available in this session")
+		return nil
+	}
+
+	enabled := sc.deps.DryRun
+	if len(args) > 0 {
+		switch args[0] {
+		case "on":
+			enabled = true
+		case "off":
+			enabled = false
+		default:
+			sc.deps.MessageLogger("system", "Usage: /dryrun [on|off]")
+			return nil
+		}
+		sc.deps.SetDryRun(enabled)
+	}
+
+	if enabled {
+		sc.deps.MessageLogger("system", "🧪 Dry run is on: tools that write files or run commands only report what they would do")
+	} else {
+		sc.deps.MessageLogger("system", "Dry run is off: approved tools run for real")
+	}
+	return nil
+}
+
+// Pprof handles the hidden /pprof command used to profile the TUI:
+// "/pprof cpu" starts and stops a CPU capture, "/pprof heap" writes a heap snapshot
+func (sc *SystemCommands) Pprof(args []string) tea.Cmd {
+	if len(args) == 0 {
+		sc.deps.MessageLogger("system", "Usage: /pprof cpu|heap")
+		return nil
+	}
+
+	switch args[0] {
+	case "cpu":
+		if profile := sc.deps.CPUProfile; profile != nil {
+			pprof.StopCPUProfile()
+			path := profile.Name()
+			profile.Close()
+			sc.deps.SetCPUProfile(nil)
+			sc.deps.MessageLogger("system", fmt.Sprintf("CPU profile written to %s\n   Inspect with: go tool pprof %s", path, path))
+			return nil
+		}
+		file, err := os.Create(profilePath("cpu"))
+		if err != nil {
+			sc.deps.MessageLogger("system", fmt.Sprintf("Cannot create CPU profile: %v", err))
+			return nil
+		}
+		if err := pprof.StartCPUProfile(file); err != nil {
+			file.Close()
+			os.Remove(file.Name())
+			sc.deps.MessageLogger("system", fmt.Sprintf("Cannot start CPU profile: %v", err))
+			return nil
+		}
+		sc.deps.SetCPUProfile(file)
+		sc.deps.MessageLogger("system", "CPU profiling started. Run /pprof cpu again to stop and save it")
+
+	case "heap":
+		path := profilePath("heap")
+		file, err := os.Create(path)
+		if err != nil {
+			sc.deps.MessageLogger("system", fmt.Sprintf("Cannot create heap profile: %v", err))
+			return nil
+		}
+		defer file.Close()
+		runtime.GC() // Report up-to-date allocations
+		if err := pprof.WriteHeapProfile(file); err != nil {
+			sc.deps.MessageLogger("system", fmt.Sprintf("Cannot write heap profile: %v", err))
+			return nil
+		}
+		sc.deps.MessageLogger("system", fmt.Sprintf("Heap profile written to %s\n   Inspect with: go tool pprof %s", path, path))
+
+	default:
+		sc.deps.MessageLogger("system", "Usage: /pprof cpu|heap")
+	}
+	return nil
+}
+
+// profilePath returns a timestamped profile file name in the temp directory
+func profilePath(kind string) string {
+	name := fmt.Sprintf("deecli-%s-%s.pprof", kind, time.Now().Format("20060102-150405"))
+	return filepath.Join(os.TempDir(), name)
+}
+
 // ShowUnknownCommand handles unknown commands
 func (sc *SystemCommands) ShowUnknownCommand(command string) {
 	sc.deps.MessageLogger("system", fmt.Sprintf("Unknown command: %s. Type /help for available commands.", command))
@@ -15,12 +15,19 @@ package commands
 
 import (
 	"context"
+	"os"
 
+	aiops "github.com/antenore/deecli/internal/ai"
 	"github.com/antenore/deecli/internal/api"
+	"github.com/antenore/deecli/internal/audit"
+	"github.com/antenore/deecli/internal/chat/tasks"
 	"github.com/antenore/deecli/internal/chat/tracker"
 	"github.com/antenore/deecli/internal/config"
+	"github.com/antenore/deecli/internal/errlog"
 	"github.com/antenore/deecli/internal/files"
+	"github.com/antenore/deecli/internal/forge"
 	"github.com/antenore/deecli/internal/history"
+	"github.com/antenore/deecli/internal/metrics"
 	"github.com/antenore/deecli/internal/sessions"
 	"github.com/antenore/deecli/internal/tools"
 	tea "github.com/charmbracelet/bubbletea"
@@ -37,27 +44,91 @@ type Dependencies struct {
 	HistoryManager   *history.Manager
 	FileTracker      *tracker.FileTracker
 	ToolsRegistry    *tools.Registry
+	ErrorLog         *errlog.Log
+	AuditLog         *audit.Log
+	UsageStats       *metrics.Recorder // nil when telemetry is off
 
 	// UI state
 	Messages     []string
 	APIMessages  []api.Message
 	InputHistory []string
+	Sources      []audit.Entry // Tool calls cited under the last response
+	EditSuggestions []aiops.EditSuggestion // Edits suggested by the last /suggest
 	HelpVisible  bool
+	CPUProfile   *os.File // Open while a /pprof cpu capture is running
+	PendingGist  string   // Export prepared by /share gist, uploaded once confirmed
+	PendingCommit *CommitProposal // Commit waiting for /git commit confirm
+	PendingOverwrite string // Existing file an analysis command must be run again to overwrite
 
 	// State management
 	MessageLogger func(role, content string)
+	ReportError   func(errlog.Category, string, error) // Logs and shows a categorized error
 	SetLoading    func(bool, string) tea.Cmd
 	SetCancel     func(context.CancelFunc)
+	SetCPUProfile func(*os.File)
+	SetPendingGist func(string)
+	SetPendingCommit func(*CommitProposal)
+	SetPendingOverwrite func(string)
+	Tasks         *tasks.Manager // Background tasks such as /analyze
 	RefreshUI     func()
 	ShowHistory   func() // Show input history
+	SwitchSession func(*sessions.Session) // Save new messages to another session
 
 	// AI operations
-	AnalyzeFiles func() tea.Cmd
-	ExplainFiles func() tea.Cmd
-	ImproveFiles func() tea.Cmd
-	GenerateEditSuggestions func() tea.Cmd
+	AnalyzeFiles func(loaded []files.LoadedFile, output string) tea.Cmd // nil loaded means all loaded files; output is the file for the result, "" for the chat
+	ExplainFiles func(loaded []files.LoadedFile, output string) tea.Cmd
+	ImproveFiles func(loaded []files.LoadedFile, output string) tea.Cmd
+	GenerateEditSuggestions func() tea.Cmd // Suggest edits from the conversation, as an EditSuggestionsMsg
+	SetInput     func(string)          // Replace the input with a prompt to edit before sending
+	SendPrompt   func(string) tea.Cmd  // Send a prompt as if typed, queued during a turn
+	SendChoices  func(prompt string, n int) tea.Cmd // Ask for n answers to prompt and let the user keep one
+	InitProject  func() tea.Cmd
+	CompactConversation func(keep int) tea.Cmd // Summarize all but the last keep turns
+	ReviewDiff func(refRange string, chunks []string, output string) tea.Cmd // Review diff chunks and report the findings, to output if set
+	DocumentPackages func([]aiops.DocPackage) tea.Cmd // Ask for the documentation of Go packages as diffs
 
 	// UI control
 	SetHelpVisible  func(bool)
 	SetKeyDetection func(bool, string)
-}
\ No newline at end of file
+	OpenConfigEditor func() // Show the /config edit form
+	OpenFileViewer   func(path, content string, line int) // Show a file read-only, scrolled to line
+	OpenCompareViewer func(path, loaded, disk string)    // Show the loaded content of a file next to its content on disk
+	OpenHunkPicker   func(files.PatchSuggestion)        // Choose which hunks of a suggested diff to apply
+	Sections         func() []string  // Headings and code blocks of the chat, in order
+	GotoSection      func(int) bool   // Scroll the chat to a section by index
+	ApplyConfig      func() // Apply reloaded settings to the running session
+
+	// Tool execution
+	DryRun    bool       // Tools that write files or run commands are only simulated
+	SetDryRun func(bool) // Toggle dry-run mode
+}
+
+// CommandResultMsg reports the outcome of a command that finishes in the background
+type CommandResultMsg struct {
+	Message  string          // Shown as a system message, or as the error summary
+	Err      error
+	Category errlog.Category // Error category when Err is set
+}
+
+// TabMsg asks the chat to open, switch to, rename, close or list tabs. It is
+// handled by the tab set around the chat of the tab that sent it.
+type TabMsg struct {
+	Action string // "new", "switch", "rename", "close" or "list"
+	Name   string // Name of a new or renamed tab; empty picks one
+	Index  int    // Tab to switch to, from 0
+}
+
+// PullRequestFetchedMsg carries a pull request fetched by /pr review; its
+// diff, saved to DiffPath, is loaded into the context and reviewed
+type PullRequestFetchedMsg struct {
+	PullRequest *forge.PullRequest
+	DiffPath    string
+	Err         error
+}
+
+// CommitProposedMsg carries a commit whose message /git commit generated in
+// the background; the chat keeps it for /git commit confirm and shows Message
+type CommitProposedMsg struct {
+	Proposal *CommitProposal
+	Message  string
+}
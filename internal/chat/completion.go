@@ -17,10 +17,15 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/antenore/deecli/internal/config"
+	"github.com/antenore/deecli/internal/files"
+	"github.com/antenore/deecli/internal/i18n"
 )
 
 type CompletionEngine struct {
-	commands []string
+	commands   []string
+	fileLoader *files.FileLoader
 }
 
 func NewCompletionEngine() *CompletionEngine {
@@ -32,22 +37,58 @@ func NewCompletionEngine() *CompletionEngine {
 			"/clear",
 			"/unload",
 			"/reload",
+			"/undo-files",
+			"/snapshot",
+			"/mentioned",
+			"/apply",
+			"/view",
 			"/analyze",
 			"/edit",
+			"/init",
+			"/pr",
+			"/review",
+			"/compact",
+			"/suggest",
+			"/send",
+			"/docs",
+			"/git",
 			"/create",
 			"/improve",
 			"/explain",
 			"/history",
 			"/keysetup",
 			"/config",
+			"/mode",
+			"/postprocess",
 			"/help",
 			"/quit",
 			"/exit",
+			"/session",
+			"/fork",
 			"/sessions",
+			"/tab",
+			"/tasks",
+			"/errors",
+			"/audit",
+			"/stats",
+			"/sources",
+			"/sections",
+			"/dryrun",
+			"/share",
 		},
 	}
 }
 
+// SetFileLoader sets the loader used to hide .deecliignore'd paths from completions
+func (ce *CompletionEngine) SetFileLoader(loader *files.FileLoader) {
+	ce.fileLoader = loader
+}
+
+// isExcluded reports whether a path is excluded by .deecliignore or config patterns
+func (ce *CompletionEngine) isExcluded(path string) bool {
+	return ce.fileLoader != nil && ce.fileLoader.IsExcluded(path)
+}
+
 func (ce *CompletionEngine) Complete(input string, cursorPos int) ([]string, string) {
 	if cursorPos > len(input) {
 		cursorPos = len(input)
@@ -102,7 +143,48 @@ func (ce *CompletionEngine) Complete(input string, cursorPos int) ([]string, str
 			}
 		}
 
-		if cmd == "/load" || cmd == "/add" || cmd == "/unload" || cmd == "/reload" || cmd == "/edit" || cmd == "/create" {
+		// Complete /mode presets
+		if cmd == "/mode" {
+			if len(parts) == 1 && strings.HasSuffix(prefix, " ") {
+				return ce.completeModes(""), ""
+			} else if len(parts) == 2 && !strings.HasSuffix(prefix, " ") {
+				return ce.completeModes(parts[1]), parts[1]
+			}
+		}
+
+		// Complete /suggest actions
+		if cmd == "/suggest" {
+			if len(parts) == 1 && strings.HasSuffix(prefix, " ") {
+				return ce.completeFromList([]string{"list", "open", "prompt", "fix"}, ""), ""
+			} else if len(parts) == 2 && !strings.HasSuffix(prefix, " ") {
+				return ce.completeFromList([]string{"list", "open", "prompt", "fix"}, parts[1]), parts[1]
+			}
+		}
+
+		// Complete the /docs option
+		if cmd == "/docs" && len(parts) == 2 && !strings.HasSuffix(prefix, " ") && strings.HasPrefix(parts[1], "-") {
+			return ce.completeFromList([]string{"--check"}, parts[1]), parts[1]
+		}
+
+		// Complete the /send option
+		if cmd == "/send" && len(parts) == 2 && !strings.HasSuffix(prefix, " ") && strings.HasPrefix(parts[1], "-") {
+			return ce.completeFromList([]string{"--n"}, parts[1]), parts[1]
+		}
+
+		// Complete /postprocess names and states
+		if cmd == "/postprocess" {
+			if len(parts) == 1 && strings.HasSuffix(prefix, " ") {
+				return ce.completeFromList(config.PostProcessorNames(), ""), ""
+			} else if len(parts) == 2 && !strings.HasSuffix(prefix, " ") {
+				return ce.completeFromList(config.PostProcessorNames(), parts[1]), parts[1]
+			} else if len(parts) == 2 {
+				return ce.completeFromList([]string{"on", "off"}, ""), ""
+			} else if len(parts) == 3 && !strings.HasSuffix(prefix, " ") {
+				return ce.completeFromList([]string{"on", "off"}, parts[2]), parts[2]
+			}
+		}
+
+		if cmd == "/load" || cmd == "/add" || cmd == "/unload" || cmd == "/reload" || cmd == "/edit" || cmd == "/view" || cmd == "/create" {
 			// Find the current word being typed at cursor position
 			currentWord, wordStart := ce.getCurrentWord(input, cursorPos)
 			if wordStart > 0 { // We're after the command
@@ -146,6 +228,9 @@ func (ce *CompletionEngine) completeFilePath(prefix string) []string {
 			if strings.HasPrefix(name, ".") && !showDotFiles {
 				continue
 			}
+			if ce.isExcluded(name) {
+				continue
+			}
 
 			fullPath := name
 			if entry.IsDir() {
@@ -192,6 +277,9 @@ func (ce *CompletionEngine) completeFilePath(prefix string) []string {
 			if dir == "." {
 				fullPath = name
 			}
+			if ce.isExcluded(fullPath) {
+				continue
+			}
 			
 			if entry.IsDir() {
 				fullPath += "/"
@@ -207,7 +295,7 @@ func (ce *CompletionEngine) completeFilePath(prefix string) []string {
 		if err == nil {
 			for _, match := range globMatches {
 				info, err := os.Stat(match)
-				if err == nil {
+				if err == nil && !ce.isExcluded(match) {
 					if info.IsDir() {
 						match += "/"
 					}
@@ -308,7 +396,7 @@ func (ce *CompletionEngine) getCurrentWord(input string, cursorPos int) (string,
 // completeConfigSubcommands returns available config subcommands
 func (ce *CompletionEngine) completeConfigSubcommands(prefix string) []string {
 	subcommands := []string{
-		"show", "init", "get", "set",
+		"show", "init", "get", "set", "edit", "verify",
 		"model", "temperature", "max-tokens", "help",
 	}
 
@@ -326,6 +414,11 @@ func (ce *CompletionEngine) completeConfigKeys(prefix string) []string {
 	keys := []string{
 		"api-key", "model", "user-name", "temperature", "max-tokens",
 		"auto-reload-files", "auto-reload-debounce", "show-reload-notices",
+		"redact-secrets", "notify-on-complete", "plain-mode", "plain-mode-width",
+		"screen-reader", "extract-text", "language", "history-max-entries", "lazy-load-threshold",
+		"large-file-threshold", "large-file-preview", "max-file-size", "max-files-per-load", "max-loaded-files", "scrollback-limit", "tool-calls-per-turn", "tool-calls-per-minute", "stream-stall-timeout", "stream-auto-reconnect", "message-timestamps", "sidebar-sort", "sidebar-group", "warm-up-on-start", "check-updates", "telemetry",
+		"proxy", "ca-bundle", "insecure-skip-verify", "request-metrics", "editor-context", "instruction-file-size",
+		"editor-cmd", "editor-goto-cmd",
 	}
 
 	var matches []string
@@ -337,6 +430,28 @@ func (ce *CompletionEngine) completeConfigKeys(prefix string) []string {
 	return matches
 }
 
+// completeModes returns the /mode presets
+func (ce *CompletionEngine) completeModes(prefix string) []string {
+	var matches []string
+	for _, mode := range append(config.ModeNames(), config.ModeOff) {
+		if strings.HasPrefix(mode, prefix) {
+			matches = append(matches, mode)
+		}
+	}
+	return matches
+}
+
+// completeFromList returns the values starting with prefix
+func (ce *CompletionEngine) completeFromList(values []string, prefix string) []string {
+	var matches []string
+	for _, value := range values {
+		if strings.HasPrefix(value, prefix) {
+			matches = append(matches, value)
+		}
+	}
+	return matches
+}
+
 // completeModels returns available model names
 func (ce *CompletionEngine) completeModels(prefix string) []string {
 	models := []string{
@@ -384,7 +499,35 @@ func (ce *CompletionEngine) completeConfigValues(key, prefix string) []string {
 			}
 		}
 		return matches
-	case "show-reload-notices":
+	case "notify-on-complete":
+		values := []string{"off", "bell", "desktop", "both"}
+		var matches []string
+		for _, val := range values {
+			if strings.HasPrefix(val, prefix) {
+				matches = append(matches, val)
+			}
+		}
+		return matches
+	case "message-timestamps":
+		values := []string{"off", "relative", "absolute"}
+		var matches []string
+		for _, val := range values {
+			if strings.HasPrefix(val, prefix) {
+				matches = append(matches, val)
+			}
+		}
+		return matches
+	case "sidebar-sort":
+		return ce.completeFromList(config.ValidSidebarSorts, prefix)
+	case "telemetry":
+		var matches []string
+		for _, val := range config.ValidTelemetryModes {
+			if strings.HasPrefix(val, prefix) {
+				matches = append(matches, val)
+			}
+		}
+		return matches
+	case "show-reload-notices", "redact-secrets", "plain-mode", "screen-reader", "extract-text", "stream-auto-reconnect", "warm-up-on-start", "check-updates", "insecure-skip-verify", "request-metrics", "editor-context", "sidebar-group":
 		values := []string{"true", "false"}
 		var matches []string
 		for _, val := range values {
@@ -393,6 +536,15 @@ func (ce *CompletionEngine) completeConfigValues(key, prefix string) []string {
 			}
 		}
 		return matches
+	case "language":
+		values := append([]string{i18n.Auto}, i18n.Supported()...)
+		var matches []string
+		for _, val := range values {
+			if strings.HasPrefix(val, prefix) {
+				matches = append(matches, val)
+			}
+		}
+		return matches
 	case "auto-reload-debounce":
 		values := []string{"50", "100", "200", "500", "1000"}
 		var matches []string
@@ -0,0 +1,123 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chat
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/antenore/deecli/internal/config"
+	"github.com/antenore/deecli/internal/errlog"
+	"github.com/antenore/deecli/internal/files"
+	"github.com/antenore/deecli/internal/i18n"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// configFileChangedMsg reports that a config file was changed on disk
+type configFileChangedMsg struct{}
+
+// watchConfigFiles watches the existing config files and signals on the
+// returned channel when one changes. It returns nil when watching is unsupported.
+func watchConfigFiles(configManager *config.Manager) <-chan struct{} {
+	watcher, err := files.NewWatcher(0)
+	if err != nil || !watcher.IsSupported() {
+		return nil
+	}
+
+	watched := 0
+	for _, path := range configManager.Paths() {
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		if err := watcher.Watch(path); err == nil {
+			watched++
+		}
+	}
+	if watched == 0 {
+		watcher.Stop()
+		return nil
+	}
+
+	changes := make(chan struct{}, 1)
+	watcher.Start(context.Background(), func([]string) error {
+		// Coalesce changes the model has not picked up yet
+		select {
+		case changes <- struct{}{}:
+		default:
+		}
+		return nil
+	})
+	return changes
+}
+
+// waitForConfigChange delivers the next config file change
+func waitForConfigChange(changes <-chan struct{}) tea.Cmd {
+	if changes == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		<-changes
+		return configFileChangedMsg{}
+	}
+}
+
+// reloadConfig re-reads the config files after an external edit, applies
+// the settings that can change while running and summarizes the changes
+func (m *NewModel) reloadConfig() {
+	changes, err := m.configManager.Reload()
+	if err != nil {
+		m.reportError(errlog.CategoryConfig, "Config file changed but is invalid, keeping the previous settings: "+err.Error(), err)
+		return
+	}
+	if len(changes) == 0 {
+		return
+	}
+
+	m.applyConfig()
+	m.addMessage("system", fmt.Sprintf("⚙️ Configuration reloaded:\n   %s", strings.Join(changes, "\n   ")))
+}
+
+// applyConfig applies the settings that take effect without a restart
+func (m *NewModel) applyConfig() {
+	cfg := m.configManager.Get()
+	i18n.SetLanguage(cfg.Language)
+	if m.keyDetector != nil {
+		// History keys are read from the config on use; the newline key lives in the textarea
+		m.keyDetector.UpdateTextareaKeymap(&m.textarea)
+	}
+	if m.apiClient != nil {
+		m.apiClient.SetModelSettings(m.configManager.GetModel(), m.configManager.GetTemperature(), m.configManager.GetMaxTokens())
+		m.apiClient.SetModePrompt(m.configManager.GetModePrompt())
+	}
+	if m.inputManager != nil && m.inputManager.GetHistoryManager() != nil {
+		m.inputManager.GetHistoryManager().SetMaxEntries(m.configManager.GetHistoryMaxEntries())
+	}
+	m.fileContext.LazyThreshold = m.configManager.GetLazyLoadThreshold()
+	m.fileContext.Formatters = m.configManager.GetFormatters()
+	m.fileContext.Loader.PreviewThreshold = m.configManager.GetLargeFileThreshold()
+	m.fileContext.Loader.PreviewSize = m.configManager.GetLargeFilePreview()
+	m.fileContext.Loader.MaxFileSize = m.configManager.GetMaxFileSize()
+	m.fileContext.Loader.MaxFiles = m.configManager.GetMaxFilesPerLoad()
+	m.fileContext.MaxContext = m.configManager.GetMaxLoadedFiles()
+	m.fileContext.Loader.ExtractText = m.configManager.GetExtractText()
+	m.messageManager.SetScrollbackLimit(m.configManager.GetScrollbackLimit())
+	m.renderer.SetTimestamps(m.configManager.GetMessageTimestamps())
+	m.sidebar.Sort = m.configManager.GetSidebarSort()
+	m.sidebar.Group = m.configManager.GetSidebarGroup()
+	if m.filesWidgetVisible {
+		m.sidebarViewport.SetContent(m.renderFilesSidebar())
+	}
+}
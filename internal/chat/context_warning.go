@@ -0,0 +1,71 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chat
+
+import (
+	"strings"
+
+	"github.com/antenore/deecli/internal/i18n"
+)
+
+// contextWarningPercent is the share of max_context_size the file context
+// and conversation history reach before the header warns about it
+const contextWarningPercent = 80
+
+// contextWarning returns the header warning for a prompt nearing
+// max_context_size, with what would shrink it most: /compact for a long
+// conversation, /unload for the largest file. It returns "" below
+// contextWarningPercent.
+func (m *NewModel) contextWarning() string {
+	maxContextSize := 100000 // Default
+	if m.configManager != nil {
+		maxContextSize = m.configManager.GetMaxContextSize()
+	}
+	if maxContextSize <= 0 {
+		return ""
+	}
+
+	historySize := 0
+	if m.aiOperations != nil {
+		historySize = m.aiOperations.HistorySize()
+	}
+	filesSize := 0
+	if m.fileContext != nil && len(m.fileContext.Files) > 0 {
+		filesSize = m.fileContext.GetFormattedContextSize()
+	}
+	percent := (historySize + filesSize) * 100 / maxContextSize
+	if percent < contextWarningPercent {
+		return ""
+	}
+
+	var fixes []string
+	if historySize > 0 {
+		fixes = append(fixes, "/compact")
+	}
+	if filesSize > 0 {
+		largest, largestTokens := 0, 0
+		for i, tokens := range m.fileContext.GetFileTokens() {
+			if tokens > largestTokens {
+				largest, largestTokens = i, tokens
+			}
+		}
+		unload := "/unload " + m.fileContext.Files[largest].RelPath
+		if filesSize > historySize {
+			fixes = append([]string{unload}, fixes...)
+		} else {
+			fixes = append(fixes, unload)
+		}
+	}
+	return i18n.T("context.near_limit", percent, strings.Join(fixes, i18n.T("context.or")))
+}
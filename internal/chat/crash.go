@@ -0,0 +1,135 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chat
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// crashPanic is a panic caught in a command, carried to the event loop with
+// the stack of the goroutine it happened on
+type crashPanic struct {
+	value any
+	stack []byte
+}
+
+// crashGuard wraps the chat tabs so that panics, including those in
+// commands, which Bubble Tea runs on their own goroutines, surface in the
+// event loop and reach ChatApp.run
+type crashGuard struct {
+	m tea.Model
+}
+
+func (g crashGuard) Init() tea.Cmd {
+	return guardCmd(g.m.Init())
+}
+
+func (g crashGuard) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if crash, ok := msg.(crashPanic); ok {
+		panic(crash)
+	}
+	_, cmd := g.m.Update(msg)
+	return g, guardCmd(cmd)
+}
+
+func (g crashGuard) View() string {
+	return g.m.View()
+}
+
+// guardCmd turns a panic in cmd, or in the commands of a batch it returns,
+// into a crashPanic message
+func guardCmd(cmd tea.Cmd) tea.Cmd {
+	if cmd == nil {
+		return nil
+	}
+	return func() (msg tea.Msg) {
+		defer func() {
+			if r := recover(); r != nil {
+				msg = crashPanic{value: r, stack: debug.Stack()}
+			}
+		}()
+		msg = cmd()
+		if batch, ok := msg.(tea.BatchMsg); ok {
+			for i := range batch {
+				batch[i] = guardCmd(batch[i])
+			}
+		}
+		return msg
+	}
+}
+
+// crashLogPath is the file panic traces are appended to
+func crashLogPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".deecli", "crash.log")
+	}
+	return filepath.Join(home, ".deecli", "crash.log")
+}
+
+// recoverCrash handles a panic that stopped the chat: it restores the
+// terminal, writes the trace to the crash log, checkpoints the conversation
+// and prints how to get it back
+func (app *ChatApp) recoverCrash(m *NewModel, value any, stack []byte) error {
+	if crash, ok := value.(crashPanic); ok {
+		value, stack = crash.value, crash.stack
+	}
+	if app.program != nil {
+		app.program.Kill() // Leaves raw mode and the alt screen
+	}
+
+	logPath := crashLogPath()
+	if err := writeCrashLog(logPath, value, stack); err != nil {
+		logPath = ""
+		fmt.Fprintf(os.Stderr, "panic: %v\n\n%s\n", value, stack)
+	}
+
+	saved := func() (ok bool) {
+		defer func() {
+			if recover() != nil {
+				ok = false // The model may be too broken to save
+			}
+		}()
+		return m.saveCheckpoint()
+	}()
+
+	fmt.Fprintf(os.Stderr, "\ndeecli stopped after an unexpected error: %v\n", value)
+	if logPath != "" {
+		fmt.Fprintf(os.Stderr, "The trace was written to %s; please include it when reporting the problem.\n", logPath)
+	}
+	if saved {
+		fmt.Fprintln(os.Stderr, "Your conversation was saved: run deecli chat in this directory to recover it.")
+	}
+	return fmt.Errorf("panic: %v", value)
+}
+
+// writeCrashLog appends a panic and its stack trace to the crash log
+func writeCrashLog(path string, value any, stack []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = fmt.Fprintf(f, "=== %s\npanic: %v\n\n%s\n", time.Now().Format(time.RFC3339), value, stack)
+	return err
+}
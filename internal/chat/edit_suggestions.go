@@ -0,0 +1,49 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chat
+
+import (
+	"strings"
+
+	"github.com/antenore/deecli/internal/ai"
+	"github.com/antenore/deecli/internal/errlog"
+)
+
+// handleEditSuggestions keeps the edits suggested by /suggest and lists them
+// numbered, for /suggest open, prompt and fix. A reply without any
+// suggestion in the expected format is shown as written.
+func (m *NewModel) handleEditSuggestions(msg ai.EditSuggestionsMsg) {
+	m.setLoading(false, "")
+	m.apiCancel = nil
+	m.showRedactionNotice()
+
+	if msg.Err != nil {
+		m.reportError(errlog.Classify(msg.Err), "Edit suggestions failed: "+errlog.UserMessage(msg.Err), msg.Err)
+		return
+	}
+
+	m.editSuggestions = msg.Suggestions
+	if len(msg.Suggestions) == 0 {
+		m.addMessage("system", strings.TrimSpace(msg.Response))
+		return
+	}
+	m.addMessage("system", ai.FormatEditSuggestions(msg.Suggestions))
+}
+
+// setInput replaces the input with text, to be edited before it is sent
+func (m *NewModel) setInput(text string) {
+	m.textarea.SetValue(text)
+	m.focusMode = "input"
+	m.textarea.Focus()
+}
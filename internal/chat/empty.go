@@ -0,0 +1,50 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chat
+
+import (
+	"errors"
+
+	"github.com/antenore/deecli/internal/errlog"
+	"github.com/antenore/deecli/internal/i18n"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// emptyNudge is added to a request sent again after an empty response
+const emptyNudge = "Your previous reply was empty. Please answer my last message."
+
+// errEmptyResponse is reported when the response stays empty after a re-ask
+var errEmptyResponse = errors.New("the API returned an empty response twice")
+
+// reaskEmpty handles an empty response: the first one of a prompt is asked
+// again automatically with a nudge, a second one is reported. suppressTools
+// carries over the tool call suppression of a follow-up after tool calls.
+func (m *NewModel) reaskEmpty(suppressTools bool) tea.Cmd {
+	if m.emptyRetried || m.aiOperations == nil {
+		m.reportError(errlog.CategoryGeneral, i18n.T("empty.failed"), errEmptyResponse)
+		return nil
+	}
+	m.emptyRetried = true
+	m.addMessage("system", i18n.T("empty.retrying"))
+	if m.toolsManager != nil {
+		m.toolsManager.SetSuppressToolCalls(suppressTools)
+	}
+
+	cmd := m.aiOperations.Reask(emptyNudge)
+	m.apiCancel = m.aiOperations.GetAPICancel()
+	if loading := m.setLoading(true, i18n.T("loading.thinking")); loading != nil {
+		cmd = tea.Batch(cmd, loading)
+	}
+	return cmd
+}
@@ -98,7 +98,10 @@ func (m *Manager) HandleHistoryForward(textarea *textarea.Model) bool {
 
 // AddToHistory adds a new entry to input history
 func (m *Manager) AddToHistory(input string) {
-	m.inputHistory = append(m.inputHistory, input)
+	// Repeating the last entry adds nothing to navigate back through
+	if len(m.inputHistory) == 0 || m.inputHistory[len(m.inputHistory)-1] != input {
+		m.inputHistory = append(m.inputHistory, input)
+	}
 	m.historyIndex = -1
 	m.tempInput = ""
 
@@ -138,7 +141,7 @@ func (m *Manager) ShowHistory() {
 
 	historyFile := "in-memory only"
 	if m.historyMgr != nil {
-		historyFile = ".deecli/history.jsonl"
+		historyFile = m.historyMgr.Path()
 	}
 	m.messageLogger("system", fmt.Sprintf("  History file: %s", historyFile))
 	m.refreshViewport()
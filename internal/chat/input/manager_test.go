@@ -228,6 +228,12 @@ func TestAddToHistory(t *testing.T) {
 	if mgr.historyIndex != -1 {
 		t.Errorf("Expected historyIndex to be -1, got %d", mgr.historyIndex)
 	}
+
+	// Repeating the last command does not add a duplicate
+	mgr.AddToHistory("new command")
+	if len(mgr.GetInputHistory()) != 2 {
+		t.Errorf("Expected consecutive duplicates to be skipped, got %v", mgr.GetInputHistory())
+	}
 }
 
 func TestTabCompletion(t *testing.T) {
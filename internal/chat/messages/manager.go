@@ -16,10 +16,12 @@ package messages
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/antenore/deecli/internal/ai"
 	"github.com/antenore/deecli/internal/api"
 	"github.com/antenore/deecli/internal/chat/ui"
+	"github.com/antenore/deecli/internal/i18n"
 	"github.com/antenore/deecli/internal/sessions"
 	"github.com/charmbracelet/lipgloss"
 )
@@ -37,7 +39,19 @@ type Dependencies struct {
 type Manager struct {
 	messages       []string       // Formatted messages for display
 	apiMessages    []api.Message  // Raw API messages for conversation context
+	transcript     ui.Transcript  // Incrementally joined messages for the viewport
+	sources        []string       // Markdown of each displayed response, for its sections; "" for other messages
+	annotations    []string       // Formatted model, temperature and tokens of each displayed response; "" for other messages
 	deps           Dependencies
+
+	showAnnotations bool // Show the annotations under the responses
+
+	scrollbackLimit int   // Displayed messages kept in memory; 0 keeps all
+	archived        int   // Older displayed messages moved to the session store
+	archiveSession  int64 // Session the archived messages are stored under
+
+	queued []string // Formatted prompts waiting for the current turn to end
+	tasks  []string // Formatted status lines of the background tasks
 }
 
 // NewManager creates a new message manager
@@ -51,42 +65,291 @@ func NewManager(deps Dependencies) *Manager {
 
 // AddMessage adds a new message to the conversation
 func (mm *Manager) AddMessage(role, content string, viewport ViewportInterface, filesWidgetVisible bool) {
+	mm.addMessage(role, content, nil, viewport, filesWidgetVisible)
+}
+
+// AddResponse adds a response of the model to the conversation, annotated
+// with the model, temperature and tokens of gen when it is not nil
+func (mm *Manager) AddResponse(content string, gen *api.Generation, viewport ViewportInterface, filesWidgetVisible bool) {
+	mm.addMessage("assistant", content, gen, viewport, filesWidgetVisible)
+}
+
+func (mm *Manager) addMessage(role, content string, gen *api.Generation, viewport ViewportInterface, filesWidgetVisible bool) {
 	// Update renderer with current viewport dimensions
 	if mm.deps.Renderer != nil {
 		mm.deps.Renderer.SetViewportWidth(viewport.GetWidth(), filesWidgetVisible)
 	}
 
+	mm.record(role, content, gen)
+
+	// Use renderer to format the message
+	var formattedContent string
+	if mm.deps.Renderer != nil {
+		formattedContent = mm.deps.Renderer.FormatMessage(role, content)
+	} else {
+		// Fallback if renderer is not available
+		formattedContent = fmt.Sprintf("%s: %s", role, content)
+	}
+
+	// Add to message history
+	mm.messages = append(mm.messages, formattedContent)
+	source := ""
+	if role == "assistant" {
+		source = content
+	}
+	mm.sources = append(mm.sources, source)
+	mm.annotations = append(mm.annotations, mm.formatAnnotation(gen))
+	mm.trimScrollback()
+
+	// Rebuild full content from all messages
+	mm.updateViewport(viewport, false, "")
+}
+
+// RecordMessage saves a message to the session and, unless it is a system
+// message, to the API history without displaying it. Streamed responses use
+// this because their text is already on screen.
+func (mm *Manager) RecordMessage(role, content string) {
+	mm.record(role, content, nil)
+}
+
+// RecordResponse records a response of the model that is already displayed
+// as the last message, such as a streamed one, and annotates it with gen
+// when it is not nil
+func (mm *Manager) RecordResponse(content string, gen *api.Generation) {
+	mm.record("assistant", content, gen)
+	if len(mm.annotations) > 0 {
+		mm.annotations[len(mm.annotations)-1] = mm.formatAnnotation(gen)
+	}
+}
+
+func (mm *Manager) record(role, content string, gen *api.Generation) {
 	// Save to session database
 	if mm.deps.SessionManager != nil && mm.deps.CurrentSession != nil && role != "system" {
-		mm.deps.SessionManager.SaveMessage(mm.deps.CurrentSession.ID, role, content)
+		var saved api.Generation
+		if gen != nil {
+			saved = *gen
+		}
+		mm.deps.SessionManager.SaveResponse(mm.deps.CurrentSession.ID, role, content, saved)
 	}
 
 	// Store in API format for conversation context (exclude system messages)
 	if role != "system" {
 		mm.apiMessages = append(mm.apiMessages, api.Message{
-			Role:    role,
-			Content: content,
+			Role:       role,
+			Content:    content,
+			Time:       time.Now(),
+			Generation: gen,
 		})
 		// Sync with AI operations
 		if mm.deps.AIOperations != nil {
 			mm.deps.AIOperations.SetAPIMessages(mm.apiMessages)
 		}
 	}
+}
 
-	// Use renderer to format the message
-	var formattedContent string
+// AddDisplayMessage appends an already formatted message that is shown but
+// not recorded, such as the welcome screen or a status note
+func (mm *Manager) AddDisplayMessage(formatted string) {
+	mm.messages = append(mm.messages, formatted)
+	mm.sources = append(mm.sources, "")
+	mm.annotations = append(mm.annotations, "")
+	mm.trimScrollback()
+}
+
+// ReplaceLastMessage replaces the last displayed message, as streaming does
+// while a response grows
+func (mm *Manager) ReplaceLastMessage(formatted string) {
+	if len(mm.messages) == 0 {
+		mm.AddDisplayMessage(formatted)
+		return
+	}
+	mm.messages[len(mm.messages)-1] = formatted
+}
+
+// SetLastSource records the Markdown of the last displayed message, a
+// response shown through AddDisplayMessage such as a streamed one, so that
+// its sections can be found
+func (mm *Manager) SetLastSource(content string) {
+	if len(mm.sources) > 0 {
+		mm.sources[len(mm.sources)-1] = content
+	}
+}
+
+// RemoveLastMessage removes the last displayed message, such as the partial
+// response of a stream that is retried
+func (mm *Manager) RemoveLastMessage() {
+	if len(mm.messages) > 0 {
+		mm.messages = mm.messages[:len(mm.messages)-1]
+		mm.sources = mm.sources[:len(mm.sources)-1]
+		mm.annotations = mm.annotations[:len(mm.annotations)-1]
+	}
+}
+
+// formatAnnotation returns the line shown under a response produced as gen
+// describes, or "" without one
+func (mm *Manager) formatAnnotation(gen *api.Generation) string {
+	if gen == nil || gen.Model == "" {
+		return ""
+	}
 	if mm.deps.Renderer != nil {
-		formattedContent = mm.deps.Renderer.FormatMessage(role, content)
-	} else {
-		// Fallback if renderer is not available
-		formattedContent = fmt.Sprintf("%s: %s", role, content)
+		return mm.deps.Renderer.FormatAnnotation(*gen)
 	}
+	return gen.String()
+}
 
-	// Add to message history
-	mm.messages = append(mm.messages, formattedContent)
+// ToggleAnnotations shows or hides the model, temperature and tokens under
+// each response and returns whether they are now shown
+func (mm *Manager) ToggleAnnotations() bool {
+	mm.showAnnotations = !mm.showAnnotations
+	return mm.showAnnotations
+}
 
-	// Rebuild full content from all messages
-	mm.updateViewport(viewport, false, "")
+// displayed returns the displayed messages, with their annotations when
+// those are shown
+func (mm *Manager) displayed() []string {
+	if !mm.showAnnotations {
+		return mm.messages
+	}
+	messages := make([]string, len(mm.messages))
+	for i, msg := range mm.messages {
+		if mm.annotations[i] != "" {
+			msg += "\n" + mm.annotations[i]
+		}
+		messages[i] = msg
+	}
+	return messages
+}
+
+// Render returns the displayed messages joined for the viewport, followed by
+// trailer when it is not empty, by the queued prompts and by the background
+// tasks. Archived messages
+// are represented by a line telling how to load them.
+func (mm *Manager) Render(trailer string) string {
+	content := mm.transcript.Join(mm.displayed(), trailer)
+	for _, queued := range mm.queued {
+		if content != "" {
+			content += ui.MessageSeparator
+		}
+		content += queued
+	}
+	if len(mm.tasks) > 0 {
+		if content != "" {
+			content += ui.MessageSeparator
+		}
+		content += strings.Join(mm.tasks, "\n")
+	}
+	if mm.archived == 0 {
+		return content
+	}
+	return i18n.T("scrollback.placeholder", mm.archived) + ui.MessageSeparator + content
+}
+
+// Sections returns the headings and code blocks of the displayed responses,
+// with the lines of Render they start on
+func (mm *Manager) Sections() []ui.Section {
+	var sections []ui.Section
+	line := 0
+	if mm.archived > 0 {
+		line = strings.Count(i18n.T("scrollback.placeholder", mm.archived), "\n") + 2
+	}
+	for i, msg := range mm.displayed() {
+		if mm.sources[i] != "" {
+			for _, section := range ui.FindSections(mm.sources[i], msg) {
+				section.Line += line
+				sections = append(sections, section)
+			}
+		}
+		// Each message is followed by the blank line of MessageSeparator
+		line += strings.Count(msg, "\n") + 2
+	}
+	return sections
+}
+
+// SetQueued sets the prompts shown, dimmed, after the conversation until
+// they are sent
+func (mm *Manager) SetQueued(prompts []string) {
+	mm.queued = mm.queued[:0]
+	for _, prompt := range prompts {
+		if mm.deps.Renderer != nil {
+			mm.queued = append(mm.queued, mm.deps.Renderer.FormatQueuedMessage(prompt))
+		} else {
+			mm.queued = append(mm.queued, "queued: "+prompt)
+		}
+	}
+}
+
+// SetTasks sets the status lines of the background tasks shown after the
+// conversation until they finish
+func (mm *Manager) SetTasks(statuses []string) {
+	mm.tasks = mm.tasks[:0]
+	for _, status := range statuses {
+		if mm.deps.Renderer != nil {
+			mm.tasks = append(mm.tasks, mm.deps.Renderer.FormatTaskStatus(status))
+		} else {
+			mm.tasks = append(mm.tasks, "task: "+status)
+		}
+	}
+}
+
+// SetScrollbackLimit sets how many displayed messages are kept in memory;
+// values below 1 keep all of them. Older messages are moved to the session
+// store and loaded back with LoadEarlier.
+func (mm *Manager) SetScrollbackLimit(limit int) {
+	mm.scrollbackLimit = max(limit, 0)
+	mm.trimScrollback()
+}
+
+// ArchivedCount returns how many older messages are in the session store
+// instead of memory
+func (mm *Manager) ArchivedCount() int {
+	return mm.archived
+}
+
+// trimScrollback moves the oldest displayed messages over the scrollback
+// limit to the session store. Without a session store all are kept.
+func (mm *Manager) trimScrollback() {
+	excess := len(mm.messages) - mm.scrollbackLimit
+	if mm.scrollbackLimit == 0 || excess <= 0 {
+		return
+	}
+	if mm.deps.SessionManager == nil || mm.deps.CurrentSession == nil {
+		return
+	}
+
+	if mm.archived == 0 {
+		// Drop messages left over from an earlier run of the session
+		mm.archiveSession = mm.deps.CurrentSession.ID
+		if err := mm.deps.SessionManager.ClearScrollback(mm.archiveSession); err != nil {
+			return
+		}
+	}
+	if err := mm.deps.SessionManager.ArchiveScrollback(mm.archiveSession, mm.messages[:excess]); err != nil {
+		return // Keep the messages in memory rather than lose them
+	}
+	mm.messages = append([]string(nil), mm.messages[excess:]...)
+	mm.sources = append([]string(nil), mm.sources[excess:]...)
+	mm.annotations = append([]string(nil), mm.annotations[excess:]...)
+	mm.archived += excess
+}
+
+// LoadEarlier moves up to count archived messages back into memory, in
+// front of the displayed ones, and returns how many were loaded
+func (mm *Manager) LoadEarlier(count int) (int, error) {
+	if mm.archived == 0 || mm.deps.SessionManager == nil {
+		return 0, nil
+	}
+	restored, err := mm.deps.SessionManager.RestoreScrollback(mm.archiveSession, min(count, mm.archived))
+	if err != nil {
+		return 0, err
+	}
+	mm.messages = append(restored, mm.messages...)
+	mm.sources = append(make([]string, len(restored)), mm.sources...)
+	mm.annotations = append(make([]string, len(restored)), mm.annotations...)
+	mm.archived -= len(restored)
+	if len(restored) == 0 {
+		mm.archived = 0 // The store no longer has them
+	}
+	return len(restored), nil
 }
 
 // RefreshViewport rebuilds the viewport display
@@ -100,7 +363,7 @@ func (mm *Manager) updateViewport(viewport ViewportInterface, isLoading bool, lo
 		// Use renderer with animated spinner
 		var loadingDisplay string
 		if mm.deps.Renderer != nil {
-			loadingDisplay = mm.deps.Renderer.FormatLoadingMessageWithSpinner(loadingMsg, mm.deps.Spinner.Frame())
+			loadingDisplay = mm.deps.Renderer.FormatLoadingMessageWithProgress(loadingMsg, mm.deps.Spinner.Frame(), mm.deps.Spinner.Progress())
 		} else {
 			// Fallback if renderer is not available
 			loadingStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("220")).Bold(true)
@@ -112,28 +375,32 @@ func (mm *Manager) updateViewport(viewport ViewportInterface, isLoading bool, lo
 		}
 
 		// Show all messages plus loading indicator
-		allContent := strings.Join(mm.messages, "\n\n")
-		if allContent != "" {
-			viewport.SetContent(allContent + "\n\n" + loadingDisplay)
-		} else {
-			viewport.SetContent(loadingDisplay)
-		}
+		viewport.SetContent(mm.Render(loadingDisplay))
 		viewport.GotoBottom()
 	} else {
 		// Just show all messages
-		fullContent := strings.Join(mm.messages, "\n\n")
-		viewport.SetContent(fullContent)
+		viewport.SetContent(mm.Render(""))
 	}
 }
 
+// SetSession makes new messages be saved to session
+func (mm *Manager) SetSession(session *sessions.Session) {
+	mm.deps.CurrentSession = session
+}
+
 // GetMessages returns the formatted messages
 func (mm *Manager) GetMessages() []string {
 	return mm.messages
 }
 
-// SetMessages sets the formatted messages (for session loading)
-func (mm *Manager) SetMessages(messages []string) {
+// SetMessages sets the formatted messages (for session loading) with the
+// formatted annotations of the responses among them; annotations may be nil
+func (mm *Manager) SetMessages(messages, annotations []string) {
 	mm.messages = messages
+	mm.sources = make([]string, len(messages))
+	mm.annotations = make([]string, len(messages))
+	copy(mm.annotations, annotations)
+	mm.trimScrollback()
 }
 
 // GetAPIMessages returns the API messages
@@ -0,0 +1,133 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package messages
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/antenore/deecli/internal/api"
+	"github.com/antenore/deecli/internal/sessions"
+)
+
+func TestManager_ScrollbackLimit(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	store, err := sessions.NewManager()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	session, err := store.CreateSession()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mm := NewManager(Dependencies{SessionManager: store, CurrentSession: session})
+	mm.SetScrollbackLimit(3)
+	for i := 1; i <= 7; i++ {
+		mm.AddDisplayMessage(fmt.Sprintf("message %d", i))
+	}
+
+	if !slices.Equal(mm.GetMessages(), []string{"message 5", "message 6", "message 7"}) {
+		t.Fatalf("expected the newest messages in memory, got %q", mm.GetMessages())
+	}
+	if mm.ArchivedCount() != 4 || !strings.HasPrefix(mm.Render(""), "… 4 earlier messages") {
+		t.Fatalf("expected a placeholder for 4 archived messages, got %q", mm.Render(""))
+	}
+
+	// Loading goes backwards from the newest archived message
+	if n, err := mm.LoadEarlier(3); err != nil || n != 3 {
+		t.Fatalf("expected 3 messages loaded, got %d (%v)", n, err)
+	}
+	if !slices.Equal(mm.GetMessages()[:3], []string{"message 2", "message 3", "message 4"}) {
+		t.Errorf("expected the loaded messages in order, got %q", mm.GetMessages())
+	}
+	if n, _ := mm.LoadEarlier(3); n != 1 || mm.ArchivedCount() != 0 || mm.GetMessages()[0] != "message 1" {
+		t.Errorf("expected the last archived message, got %d loaded and %q", n, mm.GetMessages())
+	}
+	if strings.Contains(mm.Render(""), "earlier messages") {
+		t.Error("the placeholder should go once nothing is archived")
+	}
+}
+
+func TestManager_Sections(t *testing.T) {
+	mm := NewManager(Dependencies{})
+	mm.AddDisplayMessage("welcome\n# not a response")
+	mm.AddDisplayMessage("assistant: ## First\ntext")
+	mm.SetLastSource("## First\ntext")
+	mm.AddDisplayMessage("assistant: intro\n## Second")
+	mm.SetLastSource("intro\n## Second")
+
+	lines := strings.Split(mm.Render(""), "\n")
+	sections := mm.Sections()
+	if len(sections) != 2 {
+		t.Fatalf("expected the headings of the two responses, got %+v", sections)
+	}
+	for _, section := range sections {
+		if !strings.Contains(lines[section.Line], strings.TrimSpace(section.Title)) {
+			t.Errorf("section %q points at line %q", section.Title, lines[section.Line])
+		}
+	}
+
+	// Removing a response drops its sections
+	mm.RemoveLastMessage()
+	if sections := mm.Sections(); len(sections) != 1 {
+		t.Errorf("expected one section left, got %+v", sections)
+	}
+}
+
+func TestManager_Annotations(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	store, err := sessions.NewManager()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	session, err := store.CreateSession()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mm := NewManager(Dependencies{SessionManager: store, CurrentSession: session})
+	temperature := 0.1
+	gen := &api.Generation{Model: "deepseek-chat", Temperature: &temperature, PromptTokens: 100, CompletionTokens: 20}
+	mm.AddMessage("user", "hi", &fakeViewport{}, false)
+	mm.AddResponse("hello", gen, &fakeViewport{}, false)
+
+	annotation := "deepseek-chat · temperature 0.1 · 100 → 20 tokens"
+	if strings.Contains(mm.Render(""), annotation) {
+		t.Error("annotations shown before they were toggled on")
+	}
+	if !mm.ToggleAnnotations() || !strings.HasSuffix(mm.Render(""), "hello\n"+annotation) {
+		t.Errorf("expected the annotation under the response, got %q", mm.Render(""))
+	}
+
+	// The session keeps how each response was produced
+	stored, err := store.GetSessionMessages(session.ID)
+	if err != nil || len(stored) != 2 {
+		t.Fatalf("expected 2 stored messages, got %d (%v)", len(stored), err)
+	}
+	if stored[0].Generation.Model != "" || stored[1].Generation.String() != annotation {
+		t.Errorf("unexpected stored generations: %+v, %+v", stored[0].Generation, stored[1].Generation)
+	}
+}
+
+// fakeViewport records nothing and is as wide as a terminal
+type fakeViewport struct{}
+
+func (fakeViewport) SetContent(string) {}
+func (fakeViewport) GotoBottom()       {}
+func (fakeViewport) GetWidth() int     { return 80 }
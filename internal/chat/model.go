@@ -17,11 +17,15 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"slices"
 	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
 
 	"github.com/antenore/deecli/internal/ai"
 	"github.com/antenore/deecli/internal/api"
+	"github.com/antenore/deecli/internal/audit"
 	apiHandler "github.com/antenore/deecli/internal/chat/api"
 	"github.com/antenore/deecli/internal/chat/commands"
 	"github.com/antenore/deecli/internal/debug"
@@ -29,16 +33,24 @@ import (
 	"github.com/antenore/deecli/internal/chat/keydetect"
 	"github.com/antenore/deecli/internal/chat/messages"
 	"github.com/antenore/deecli/internal/chat/streaming"
+	"github.com/antenore/deecli/internal/chat/tasks"
 	toolsManager "github.com/antenore/deecli/internal/chat/tools"
 	"github.com/antenore/deecli/internal/chat/tracker"
 	"github.com/antenore/deecli/internal/chat/ui"
 	viewportmgr "github.com/antenore/deecli/internal/chat/viewport"
 	"github.com/antenore/deecli/internal/config"
 	"github.com/antenore/deecli/internal/editor"
+	"github.com/antenore/deecli/internal/errlog"
 	"github.com/antenore/deecli/internal/files"
+	"github.com/antenore/deecli/internal/forge"
 	"github.com/antenore/deecli/internal/history"
+	"github.com/antenore/deecli/internal/i18n"
+	"github.com/antenore/deecli/internal/metrics"
+	"github.com/antenore/deecli/internal/notify"
 	"github.com/antenore/deecli/internal/permissions"
+	"github.com/antenore/deecli/internal/redact"
 	"github.com/antenore/deecli/internal/sessions"
+	"github.com/antenore/deecli/internal/shutdown"
 	"github.com/antenore/deecli/internal/tools"
 	"github.com/antenore/deecli/internal/tools/functions"
 	"github.com/antenore/deecli/internal/utils"
@@ -77,34 +89,89 @@ type NewModel struct {
 	loadingMsg       string
 	focusMode        string // "input", "viewport", or "sidebar" - tracks which component has focus
 	keyDetector      *keydetect.Detector // Key detection handler
-	messageManager   *messages.Manager // Message storage and formatting
-	messages         []string // Keep track of all messages for full scrollback
-	apiMessages      []api.Message // Keep chat history for API context
+	messageManager   *messages.Manager // Single source of truth for displayed and API messages
 	sessionManager   *sessions.Manager
 	currentSession   *sessions.Session
 	sessionLoader    *sessions.Loader
+	titleRequested   bool // Whether a session title has been requested already
+	hasFocus         bool // Whether the terminal window has focus (requires focus reporting)
+	forcePlain       bool // Always use the plain UI (--plain)
 	inputManager     *input.Manager // Input and history management
 	apiCancel        context.CancelFunc // Function to cancel ongoing API request
 	fileTracker      *tracker.FileTracker // Track files mentioned in AI responses
+	redactor         *redact.Redactor     // Masks secrets before they are sent to the API
+	redactErr        error                // Why redaction is on but could not be set up; the chat does not start
+	errorLog         *errlog.Log          // Recent errors shown by /errors
+	cpuProfile       *os.File             // Open while a /pprof cpu capture is running
+	auditLog         *audit.Log           // Tool calls recorded for /audit
+	pendingGist      string               // Export prepared by /share gist, uploaded once confirmed
+	pendingCommit    *commands.CommitProposal // Commit waiting for /git commit confirm
+	pendingOverwrite string               // Existing file an analysis command must be run again to overwrite
+	usageStats       *metrics.Recorder    // Local usage statistics for /stats, nil when telemetry is off
+	lastSources      []audit.Entry        // Tool calls cited under the last response, for /sources
+	editSuggestions  []ai.EditSuggestion  // Edits suggested by the last /suggest, for /suggest open, prompt and fix
+	configEditor     *ui.ConfigEditor     // Open /config edit form, if any
+	fileViewer       *ui.FileViewer       // Open /view file viewer, if any
+	hunkPicker       *ui.HunkPicker       // Open /apply hunk picker, if any
+	hunkSuggestion   files.PatchSuggestion // Suggestion the hunk picker applies
+	choicePicker     *ui.ChoicePicker     // Open /send --n answer picker, if any
+	choicePrompt     string               // Prompt the picked answer replies to
+	choices          []string             // Answers offered by the choice picker
+	configChanges    <-chan struct{}      // Signals external edits of the config files
+	checkpointFailed bool                 // The last recovery checkpoint could not be saved
+	shutdown         *shutdown.Manager    // Releases resources when the app exits
+	promptQueue      []string             // Prompts typed during a turn, sent when it ends
+	lastPrompt       string               // Prompt last sent to the model
+	confirmRepeat    string               // Repeated prompt waiting for a second Enter
 
 	// Streaming support
 	streamingEnabled bool                // Whether to use streaming API
 	streamingManager *streaming.Manager // Streaming operations manager
-	streamReader     api.StreamReader   // Current stream reader
-	streamContent    string             // Accumulated stream content
+	taskManager      *tasks.Manager     // Background tasks such as /analyze
+	streamStalled    bool                // The current stream stalled; r replays it
+	streamRetried    bool                // The current request was already replayed after a stall
+	emptyRetried     bool                // An empty response to the current prompt was already asked again
+	overflowRetried  bool                // The current prompt was already sent again shorter after a context length error
 
 	// API response handling - now managed by apiHandler
 	apiResponseHandler *apiHandler.Handler      // Handles API response processing
 
 	// Function calling support - now managed by toolsManager
 	toolsManager       *toolsManager.Manager    // Manages all tool execution and approval
+	toolsRegistry      *tools.Registry           // Registry of available tools, for the API and commands
+}
+
+// registerToolsOnce guards the tool registry, which every chat tab shares
+var registerToolsOnce sync.Once
 
-	// Keep these for backward compatibility during migration
-	toolsRegistry      *tools.Registry           // Registry of available tools
-	toolsExecutor      *tools.Executor           // Executor for running tools
-	permissionManager  *permissions.Manager     // Permission manager for tools
-	approvalHandler    *ui.ApprovalHandler      // Approval UI handler
-	// Tool-related fields now managed by toolsManager
+// registerTools registers the built-in, forge, external and plugin tools in
+// the default registry the first time it is called
+func registerTools(configManager *config.Manager) {
+	registerToolsOnce.Do(func() {
+		// Register all built-in tools
+		if err := functions.RegisterAll(configManager.GetIgnorePatterns()); err != nil {
+			// Log error but continue
+			fmt.Fprintf(os.Stderr, "Warning: Failed to register tools: %v\n", err)
+		}
+		// Register the GitHub/GitLab issue and pull request tools
+		forgeTokens := forge.Tokens{GitHub: configManager.GetGitHubToken(), GitLab: configManager.GetGitLabToken()}
+		if err := functions.RegisterForge(forgeTokens); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Failed to register forge tools: %v\n", err)
+		}
+		// External tools and plugins run commands on the project files, so
+		// an untrusted directory only gets the read-only built-in tools
+		if !configManager.Trusted() {
+			return
+		}
+		// Register tools provided by external executables
+		if err := functions.RegisterExternal(configManager.GetExternalTools()); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Failed to register external tools: %v\n", err)
+		}
+		// Register sandboxed WASM plugins
+		if err := functions.RegisterPlugins(configManager.GetPlugins(), configManager.GetWasmRuntime(), configManager.GetIgnorePatterns()); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Failed to register plugins: %v\n", err)
+		}
+	})
 }
 
 // initializeComponents creates common components needed by both constructors
@@ -113,10 +180,30 @@ func initializeComponents(width, height int, client *api.Service, configManager
 	historyMgr, err := history.NewManager()
 	var historyData []string
 	if err == nil && historyMgr != nil {
+		if configManager != nil {
+			historyMgr.SetMaxEntries(configManager.GetHistoryMaxEntries())
+		}
 		historyData, _ = historyMgr.Load()
 	}
 
+	// Select the UI language before any text is rendered
+	if configManager != nil {
+		i18n.SetLanguage(configManager.GetLanguage())
+	}
+
 	fileCtx := files.NewFileContext()
+	if configManager != nil {
+		// Apply .deecliignore plus configured ignore patterns
+		fileCtx.Loader = files.NewFileLoaderWithPatterns(true, configManager.GetIgnorePatterns())
+		fileCtx.LazyThreshold = configManager.GetLazyLoadThreshold()
+		fileCtx.Formatters = configManager.GetFormatters()
+		fileCtx.Loader.PreviewThreshold = configManager.GetLargeFileThreshold()
+		fileCtx.Loader.PreviewSize = configManager.GetLargeFilePreview()
+		fileCtx.Loader.MaxFileSize = configManager.GetMaxFileSize()
+		fileCtx.Loader.MaxFiles = configManager.GetMaxFilesPerLoad()
+		fileCtx.MaxContext = configManager.GetMaxLoadedFiles()
+		fileCtx.Loader.ExtractText = configManager.GetExtractText()
+	}
 
 	// Initialize file watcher with configuration
 	var debounceMs int = 100 // Default debounce time
@@ -133,9 +220,14 @@ func initializeComponents(width, height int, client *api.Service, configManager
 	}
 
 	completionEngine := NewCompletionEngine()
+	completionEngine.SetFileLoader(fileCtx.Loader)
 	renderer := ui.NewRenderer(configManager)
 	layoutManager := ui.NewLayout(configManager)
 	sidebar := ui.NewSidebar()
+	if configManager != nil {
+		sidebar.Sort = configManager.GetSidebarSort()
+		sidebar.Group = configManager.GetSidebarGroup()
+	}
 	aiOperations := ai.NewOperations(client, fileCtx, configManager)
 
 	return fileCtx, completionEngine, renderer, layoutManager, sidebar, aiOperations, historyMgr, historyData
@@ -221,35 +313,57 @@ func newChatModelInternal(configManager *config.Manager, apiKey, model string, t
 		width:            width,
 		height:           height,
 		focusMode:        "input", // Start with input focused
-		messages:         []string{}, // Initialize message history
-		apiMessages:      []api.Message{}, // Initialize API message history
+		hasFocus:         true,    // Assume focus until the terminal reports otherwise
 		sessionManager:   sessionMgr,
 		currentSession:   currentSession,
 		fileTracker:      tracker.NewFileTracker(), // Initialize file tracker
 		streamingEnabled: true, // Enable streaming by default
 		streamingManager: streaming.NewManager(), // Initialize streaming manager
+		taskManager:      tasks.NewManager(),
+		errorLog:         errlog.NewLog(errlog.DefaultLimit),
+		auditLog:         audit.NewLog(audit.DefaultPath),
 	}
 
-	// Initialize function calling support
+	// Auto-include the project map generated by /init
 	if configManager != nil {
-		// Register all built-in tools
-		if err := functions.RegisterAll(); err != nil {
-			// Log error but continue
-			fmt.Fprintf(os.Stderr, "Warning: Failed to register tools: %v\n", err)
+		fileCtx.LoadProjectSummary()
+	}
+
+	// Initialize secret redaction
+	if configManager != nil && client != nil && configManager.GetRedactSecrets() {
+		if redactor, err := redact.New(configManager.GetRedactPatterns()); err == nil {
+			chatModel.redactor = redactor
+			client.SetRedactor(redactor)
+		} else {
+			chatModel.redactErr = fmt.Errorf("secret redaction failed: %w", err)
 		}
+	}
+
+	// System prompt variant of the /mode preset
+	if configManager != nil && client != nil {
+		client.SetModePrompt(configManager.GetModePrompt())
+	}
+
+	// Initialize function calling support
+	if configManager != nil {
+		registerTools(configManager)
 
 		// Initialize tools components
 		chatModel.toolsRegistry = tools.DefaultRegistry
-		chatModel.approvalHandler = ui.NewApprovalHandler()
-		chatModel.permissionManager = permissions.NewManager(configManager, chatModel.approvalHandler)
-		chatModel.toolsExecutor = tools.NewExecutor(chatModel.toolsRegistry, chatModel.permissionManager)
+		approvalHandler := ui.NewApprovalHandler()
+		permissionManager := permissions.NewManager(configManager, approvalHandler)
 
 		// Initialize the integrated tools manager
 		chatModel.toolsManager = toolsManager.NewManager(toolsManager.Dependencies{
 			ToolsRegistry:     chatModel.toolsRegistry,
-			ToolsExecutor:     chatModel.toolsExecutor,
-			PermissionManager: chatModel.permissionManager,
-			ApprovalHandler:   chatModel.approvalHandler,
+			ToolsExecutor:     tools.NewExecutor(chatModel.toolsRegistry, permissionManager),
+			PermissionManager: permissionManager,
+			ApprovalHandler:   approvalHandler,
+			AuditLog:          chatModel.auditLog,
+			AuditContext:      chatModel.auditContext,
+			CallLimits: func() (int, int) {
+				return configManager.GetToolCallsPerTurn(), configManager.GetToolCallsPerMinute()
+			},
 		})
 
 		// Initialize the integrated API response handler
@@ -272,6 +386,9 @@ func newChatModelInternal(configManager *config.Manager, apiKey, model string, t
 		CurrentSession: chatModel.currentSession,
 		AIOperations:   chatModel.aiOperations,
 	})
+	if chatModel.configManager != nil {
+		chatModel.messageManager.SetScrollbackLimit(chatModel.configManager.GetScrollbackLimit())
+	}
 
 	// Initialize input manager
 	chatModel.inputManager = input.NewManager(
@@ -296,6 +413,14 @@ func newChatModelInternal(configManager *config.Manager, apiKey, model string, t
 		chatModel.keyDetector.UpdateTextareaKeymap(&chatModel.textarea)
 	}
 
+	// Usage statistics stay on this machine; telemetry: off disables them
+	if configManager != nil && configManager.GetTelemetry() == "local" {
+		chatModel.usageStats = metrics.NewRecorder(metrics.DefaultPath())
+		if chatModel.apiClient != nil {
+			chatModel.apiClient.SetUsageStats(chatModel.usageStats)
+		}
+	}
+
 	// Initialize command handler with dependencies
 	chatModel.commandHandler = commands.NewHandler(chatModel.createCommandDependencies())
 
@@ -310,13 +435,18 @@ func newChatModelInternal(configManager *config.Manager, apiKey, model string, t
 			FilesWidgetVisible:  chatModel.filesWidgetVisible,
 			FormatInitialContent: func() string {
 				if chatModel.viewportManager != nil {
-					return chatModel.viewportManager.FormatInitialContent()
+					return chatModel.renderer.FormatText(chatModel.viewportManager.FormatInitialContent())
 				}
 				return "Welcome to DeeCLI"
 			},
 		})
 	}
 
+	// Hot-reload the config files when they are edited outside the app
+	if configManager != nil {
+		chatModel.configChanges = watchConfigFiles(configManager)
+	}
+
 	// Enable auto-reload if configured and supported
 	if configManager != nil && configManager.GetAutoReloadFiles() && fileCtx.IsAutoReloadSupported() {
 		// Create a context for the watcher (it will live for the lifetime of the app)
@@ -331,9 +461,12 @@ func newChatModelInternal(configManager *config.Manager, apiKey, model string, t
 				}
 			}
 
+			// Pending AI diffs are always re-checked and reported, even with notices off
+			chatModel.reportSuggestionChecks(results)
+
 			// Show auto-reload notification if configured
 			if configManager.GetShowReloadNotices() && changedCount > 0 {
-				chatModel.addMessage("system", fmt.Sprintf("📁 Auto-reloaded %d modified file(s)", changedCount))
+				chatModel.addMessage("system", i18n.T("reload.auto_reloaded", changedCount))
 
 				// Update sidebar if visible
 				if chatModel.filesWidgetVisible {
@@ -345,15 +478,35 @@ func newChatModelInternal(configManager *config.Manager, apiKey, model string, t
 			}
 		}); err != nil {
 			// Auto-reload setup failed, but continue
-			chatModel.addMessage("system", fmt.Sprintf("⚠️ Auto-reload setup failed: %v", err))
+			chatModel.addMessage("system", i18n.T("reload.setup_failed", err))
 		}
 	} else if configManager != nil && !fileCtx.IsAutoReloadSupported() {
 		// Show platform limitation message once
-		chatModel.addMessage("system",
-			"ℹ️ File auto-reload is not available on this platform.\n" +
-			"   Use /reload command to manually reload modified files.")
+		notice := i18n.T("reload.unsupported")
+		if reason := fileCtx.AutoReloadUnsupportedReason(); reason != nil {
+			notice += "\n" + i18n.T("reload.unsupported_reason", reason)
+		}
+		chatModel.addMessage("system", notice)
+	}
+
+	// Restricted mode in a directory the user has not trusted
+	if configManager != nil && !configManager.Trusted() {
+		chatModel.addMessage("system", i18n.T("trust.restricted", configManager.ProjectDir()))
 	}
 
+	// Proxy and TLS settings for corporate networks
+	if configManager != nil && client != nil {
+		if err := client.SetNetwork(api.NetworkOptionsFromConfig(configManager)); err != nil {
+			chatModel.addMessage("system", i18n.T("network.setup_failed", err))
+		} else if configManager.GetInsecureSkipVerify() {
+			chatModel.addMessage("system", i18n.T("network.insecure"))
+		}
+		if configManager.GetRequestMetrics() {
+			client.SetMetricsLog(api.NewMetricsLog(api.DefaultMetricsPath))
+		}
+	}
+
+	chatModel.registerShutdownHooks()
 	return chatModel
 }
 
@@ -367,22 +520,53 @@ func (m *NewModel) createCommandDependencies() commands.Dependencies {
 		historyManager = m.inputManager.GetHistoryManager()
 	}
 
+	var dryRun bool
+	var setDryRun func(bool)
+	if m.toolsManager != nil {
+		dryRun = m.toolsManager.DryRun()
+		setDryRun = m.toolsManager.SetDryRun
+	}
+
 	return commands.Dependencies{
 		FileContext:      m.fileContext,
 		APIClient:        m.apiClient,
 		ConfigManager:    m.configManager,
 		SessionManager:   m.sessionManager,
 		CurrentSession:   m.currentSession,
+		SwitchSession:    m.switchSession,
 		HistoryManager:   historyManager,
 		FileTracker:      m.fileTracker,
 		ToolsRegistry:    m.toolsRegistry,
-		Messages:         m.messages,
-		APIMessages:      m.apiMessages,
+		Messages:         m.messageManager.GetMessages(),
+		APIMessages:      m.messageManager.GetAPIMessages(),
 		InputHistory:     inputHistory,
 		HelpVisible:      m.helpVisible,
+		CPUProfile:       m.cpuProfile,
+		PendingGist:      m.pendingGist,
+		PendingCommit:    m.pendingCommit,
+		PendingOverwrite: m.pendingOverwrite,
 		MessageLogger:    m.addMessage,
+		ReportError:      m.reportError,
+		ErrorLog:         m.errorLog,
+		AuditLog:         m.auditLog,
+		UsageStats:       m.usageStats,
+		Sources:          m.lastSources,
+		EditSuggestions:  m.editSuggestions,
 		SetLoading:       m.setLoading,
 		SetCancel:        m.setCancel,
+		SetCPUProfile: func(file *os.File) {
+			m.cpuProfile = file
+		},
+		SetPendingGist: func(path string) {
+			m.pendingGist = path
+		},
+		SetPendingCommit: func(proposal *commands.CommitProposal) {
+			m.pendingCommit = proposal
+		},
+		SetPendingOverwrite: func(path string) {
+			m.pendingOverwrite = path
+		},
+		Tasks:            m.taskManager,
 		RefreshUI:        m.refreshViewport,
 		ShowHistory: func() {
 			if m.inputManager != nil {
@@ -393,8 +577,24 @@ func (m *NewModel) createCommandDependencies() commands.Dependencies {
 		ExplainFiles:     m.explainFiles,
 		ImproveFiles:     m.improveFiles,
 		GenerateEditSuggestions: m.generateEditSuggestions,
+		SetInput:         m.setInput,
+		SendPrompt:       m.handlePrompt,
+		SendChoices:      m.sendChoices,
+		InitProject:      m.initProject,
+		CompactConversation: m.compactConversation,
+		ReviewDiff:          m.reviewDiff,
+		DocumentPackages:    m.documentPackages,
 		SetHelpVisible:   m.setHelpVisible,
 		SetKeyDetection:  m.keyDetector.SetDetection,
+		OpenConfigEditor: m.openConfigEditor,
+		OpenFileViewer:   m.openFileViewer,
+		OpenCompareViewer: m.openCompareViewer,
+		OpenHunkPicker:   m.openHunkPicker,
+		Sections:         m.sectionTitles,
+		GotoSection:      m.gotoSection,
+		ApplyConfig:      m.applyConfig,
+		DryRun:           dryRun,
+		SetDryRun:        setDryRun,
 	}
 }
 
@@ -419,7 +619,7 @@ func (m *NewModel) setHelpVisible(visible bool) {
 	m.helpVisible = visible
 	if m.helpVisible {
 		if m.viewportManager != nil {
-			m.viewport.SetContent(m.viewportManager.HelpContent())
+			m.viewport.SetContent(m.renderer.FormatText(m.viewportManager.HelpContent()))
 		} else {
 			m.viewport.SetContent("Help not available")
 		}
@@ -430,7 +630,7 @@ func (m *NewModel) setHelpVisible(visible bool) {
 
 
 func (m NewModel) Init() tea.Cmd {
-	return nil
+	return tea.Batch(waitForConfigChange(m.configChanges), checkpointTick(), m.warmUpConnection(), m.checkForUpdates())
 }
 
 
@@ -449,6 +649,7 @@ func (m *NewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
+		m.updatePlainMode()
 		
 		if !m.ready {
 			// Initialize viewports with proper size and positioning
@@ -469,49 +670,131 @@ func (m *NewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					Renderer:         m.renderer,
 					LayoutManager:    m.layoutManager,
 					ConfigManager:    m.configManager,
-					SessionManager:   m.sessionManager,
-					CurrentSession:   m.currentSession,
-					Spinner:          m.spinner,
-					Messages:         &m.messages,
-					APIMessages:      &m.apiMessages,
 					FilesWidgetVisible: &m.filesWidgetVisible,
-					IsLoading:        &m.isLoading,
-					LoadingMsg:       &m.loadingMsg,
 				})
 			}
 
 			// Add welcome message to history
 			if m.viewportManager != nil {
-				m.messages = append(m.messages, m.viewportManager.FormatInitialContent())
+				m.messageManager.AddDisplayMessage(m.renderer.FormatText(m.viewportManager.FormatInitialContent()))
 			} else {
-				m.messages = append(m.messages, "Welcome to DeeCLI")
+				m.messageManager.AddDisplayMessage("Welcome to DeeCLI")
 			}
-			m.viewport.SetContent(strings.Join(m.messages, "\n\n"))
+			m.viewport.SetContent(m.messageManager.Render(""))
 			m.ready = true
 		} else {
 			// Update viewport width and recalculate layout
 			m.viewport.Width = m.width
 			m.layout()
 		}
+		if m.fileViewer != nil {
+			m.fileViewer.SetSize(m.width, m.height)
+		}
+		if m.hunkPicker != nil {
+			m.hunkPicker.SetSize(m.width, m.height)
+		}
+		if m.choicePicker != nil {
+			m.choicePicker.SetSize(m.width, m.height)
+		}
 
 	case cancelApiMsg:
 		if cmd := m.setLoading(false, ""); cmd != nil {
 			cmds = append(cmds, cmd)
 		}
 		m.apiCancel = nil
-		// Close stream reader if active
-		if m.streamReader != nil {
-			m.streamReader.Close()
-			m.streamReader = nil
-			m.streamContent = ""
+		m.streamStalled = false
+		// Stop displaying the stream, its reader ends with the cancelled
+		// request, but keep the partial answer for follow-up questions
+		if partial := m.streamingManager.Keep(i18n.T("stream.truncated"), m.renderer, m.messageManager); partial != "" {
+			m.messageManager.RecordResponse(partial, nil)
+			m.messageManager.SetLastSource(partial)
+			m.addMessage("system", i18n.T("status.partial_kept"))
+		} else {
+			m.addMessage("system", i18n.T("status.request_cancelled"))
 		}
-		m.addMessage("system", "🚫 Request cancelled")
+		m.restoreQueuedPrompts()
 		m.viewport.GotoBottom()
 
+	case tea.FocusMsg:
+		m.hasFocus = true
+
+	case tea.BlurMsg:
+		m.hasFocus = false
+
 	case ai.APIResponseMsg:
-		m.handleAPIResponse(msg.Response, msg.Err)
+		m.showRedactionNotice()
+		if cmd := m.handleAPIResponse(msg.Response, msg.Err); cmd != nil {
+			// An empty response or a request too long for the model is
+			// sent again before the turn ends
+			cmds = append(cmds, cmd)
+			break
+		}
+		m.notifyCompletion()
+		if cmd := m.maybeGenerateSessionTitle(); cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+		if cmd := m.sendQueuedPrompt(); cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+
+	case ai.SessionTitleMsg:
+		m.handleSessionTitle(msg)
+
+	case tasks.ProgressMsg:
+		if cmd := m.taskManager.Update(msg); cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+		m.refreshViewport()
+
+	case tasks.DoneMsg:
+		m.handleTaskDone(msg)
+
+	case commands.CommandResultMsg:
+		if msg.Err != nil {
+			m.reportError(msg.Category, msg.Message, msg.Err)
+		} else {
+			m.addMessage("system", msg.Message)
+		}
+
+	case commands.CommitProposedMsg:
+		m.pendingCommit = msg.Proposal
+		m.addMessage("system", msg.Message)
+
+	case warmUpDoneMsg:
+		m.handleWarmUpDone(msg)
+
+	case updateCheckDoneMsg:
+		m.handleUpdateCheckDone(msg)
+
+	case configFileChangedMsg:
+		m.reloadConfig()
+		cmds = append(cmds, waitForConfigChange(m.configChanges))
+
+	case commands.PullRequestFetchedMsg:
+		if cmd := m.reviewPullRequest(msg); cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+
+	case ai.ProjectSummaryMsg:
+		m.handleProjectSummary(msg)
+
+	case ai.CompactedMsg:
+		m.handleCompacted(msg)
+
+	case ai.EditSuggestionsMsg:
+		m.handleEditSuggestions(msg)
+
+	case ai.ChoicesMsg:
+		m.handleChoices(msg)
+		// Prompts queued meanwhile wait until an answer is picked
+		if m.choicePicker == nil {
+			if cmd := m.sendQueuedPrompt(); cmd != nil {
+				cmds = append(cmds, cmd)
+			}
+		}
 
 	case ai.ToolCallsResponseMsg:
+		m.showRedactionNotice()
 		if cmd := m.handleToolCallsResponse(msg); cmd != nil {
 			cmds = append(cmds, cmd)
 		}
@@ -532,114 +815,124 @@ func (m *NewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			cmds = append(cmds, cmd)
 		}
 
+	case toolsManager.ToolLimitReachedMsg:
+		// A runaway chain was stopped; the model hears why in the follow-up
+		m.addMessage("system", i18n.T("tools.limit_reached", msg.Reason))
+		cmds = append(cmds, func() tea.Msg {
+			return toolsManager.TriggerFollowupMsg{Note: fmt.Sprintf("The tool call limit was reached (%s), so no more tools can be called for this message. Answer with the information you already have and say what you could not check.", msg.Reason)}
+		})
+
+	case toolsManager.ToolLoopDetectedMsg:
+		// The model asked for the same call again; stop and make it answer
+		m.addMessage("system", i18n.T("tools.loop_detected", msg.Tool, msg.Count))
+		cmds = append(cmds, func() tea.Msg {
+			return toolsManager.TriggerFollowupMsg{Note: fmt.Sprintf("You requested %s with the arguments %s %d times for this message, so the tool chain was stopped. Its result will not change: answer directly from the results you already have.", msg.Tool, msg.Arguments, msg.Count)}
+		})
+
 	case toolsManager.TriggerFollowupMsg:
-		// Trigger follow-up API call after tool execution
-		if m.aiOperations != nil {
+		// Trigger follow-up API call after tool execution, unless cancelled meanwhile
+		if m.aiOperations != nil && m.toolsManager.TakeFollowup() {
 			m.toolsManager.SetSuppressToolCalls(true)
-			if cmd := m.setLoading(true, "Continuing..."); cmd != nil {
-				follow := m.aiOperations.CallAPIWithToolsNoChoice("", "")
+			if cmd := m.setLoading(true, i18n.T("loading.continuing")); cmd != nil {
+				follow := m.aiOperations.CallAPIWithToolsNoChoice("", msg.Note)
 				m.apiCancel = m.aiOperations.GetAPICancel()
 				cmds = append(cmds, cmd, follow)
 			} else {
-				follow := m.aiOperations.CallAPIWithToolsNoChoice("", "")
+				follow := m.aiOperations.CallAPIWithToolsNoChoice("", msg.Note)
 				m.apiCancel = m.aiOperations.GetAPICancel()
 				cmds = append(cmds, follow)
 			}
 		}
 
-	case ai.StreamStartedMsg:
-		// Use streaming manager to handle stream start
-		if cmd := m.setLoading(true, "Thinking..."); cmd != nil {
-			cmds = append(cmds, cmd)
-		}
-		m.refreshViewport()
-		// Let streaming manager handle the stream start
-		nextCmd := m.streamingManager.StartStream(msg, m.renderer, &m.messages)
-		return m, nextCmd
-
-	case ai.StreamChunkMsg:
-		// Use streaming manager to handle chunk processing
-		nextCmd, extraCmds := m.streamingManager.HandleChunk(msg, m.spinner, &m.isLoading, m.setLoading)
-		if extraCmds != nil {
-			cmds = append(cmds, extraCmds...)
-		}
-		if nextCmd != nil {
-			cmds = append(cmds, nextCmd)
+	case ai.StreamEventMsg:
+		if !m.streamingManager.IsCurrent(msg) {
+			// Late event from a cancelled or replaced stream
+			return m, nil
 		}
-
-		// Update display with current streaming content
-		m.streamingManager.UpdateDisplay(m.streamingManager.GetStreamContent(), m.renderer, &m.messages, &m.viewport)
-
-		// Keep message manager in sync
-		if m.messageManager != nil {
-			m.messageManager.SetMessages(m.messages)
+		switch msg.Kind {
+		case ai.StreamStarted:
+			m.streamStalled = false
+			if cmd := m.setLoading(true, i18n.T("loading.thinking")); cmd != nil {
+				cmds = append(cmds, cmd)
+			}
+			m.refreshViewport()
+			cmds = append(cmds, m.streamingManager.StartStream(msg))
+
+		case ai.StreamStalled:
+			if m.configManager != nil && m.configManager.GetStreamAutoReconnect() && !m.streamRetried {
+				cmds = append(cmds, m.retryStream())
+				m.addMessage("system", i18n.T("stream.reconnecting"))
+				break
+			}
+			// Keep reading: the notice goes away if data starts flowing again
+			m.streamStalled = true
+			if cmd := m.setLoading(true, i18n.T("stream.stalled")); cmd != nil {
+				cmds = append(cmds, cmd)
+			}
+			m.refreshViewport()
+			cmds = append(cmds, msg.Stream.Next())
+
+		case ai.StreamDelta:
+			m.streamStalled = false
+			// The text is drawn on the next frame, together with later chunks
+			cmds = append(cmds, m.streamingManager.HandleChunk(msg, m.spinner, &m.isLoading, m.setLoading)...)
+
+		case ai.StreamDone, ai.StreamFailed:
+			m.streamStalled = false
+			m.streamingManager.Flush(m.renderer, m.messageManager, &m.viewport)
+			if msg.Kind == ai.StreamDone && len(msg.ToolCalls) > 0 {
+				// The response asks for tools: run them instead of finishing the exchange
+				m.streamingManager.CompleteStream(msg)
+				if cmd := m.setLoading(false, ""); cmd != nil {
+					cmds = append(cmds, cmd)
+				}
+				toolMsg := ai.ToolCallsResponseMsg{
+					ToolCalls: msg.ToolCalls,
+					Response:  nil, // No full response in streaming
+				}
+				if cmd := m.handleToolCallsResponse(toolMsg); cmd != nil {
+					cmds = append(cmds, cmd)
+				}
+			} else {
+				cmds = append(cmds, m.streamingManager.CompleteStream(msg))
+			}
 		}
-
 		if len(cmds) > 0 {
 			return m, tea.Batch(cmds...)
 		}
 
-	case ai.StreamChunkWithToolsMsg:
-		// Similar to StreamChunkMsg but tracks tool calls
-		// Continue streaming with accumulated tool calls
-		nextCmd := ai.ReadNextChunkWithTools(
-			m.streamingManager.GetStream(),
-			m.streamingManager.GetStreamContent(),
-			msg.ToolCalls,
-		)
-		if nextCmd != nil {
-			cmds = append(cmds, nextCmd)
-		}
+	case checkpointMsg:
+		m.saveCheckpoint()
+		cmds = append(cmds, checkpointTick())
 
-		// Update display with current streaming content
-		if msg.Content != "" {
-			m.streamingManager.AppendContent(msg.Content)
-			m.streamingManager.UpdateDisplay(m.streamingManager.GetStreamContent(), m.renderer, &m.messages, &m.viewport)
-		}
+	case streaming.FrameMsg:
+		m.streamingManager.HandleFrame(msg, m.renderer, m.messageManager, &m.viewport)
 
-	case ai.ToolCallsStreamMsg:
-		// Streaming completed with tool calls
-		completionMsg := ai.StreamCompleteMsg{
-			TotalContent: msg.TotalContent,
-			Err:          nil,
+	case streaming.StreamCompleteInternalMsg:
+		// Handle streaming completion from streaming manager
+		if cmd := m.handleStreamCompleteInternal(msg); cmd != nil {
+			cmds = append(cmds, cmd)
+			break
 		}
-		m.streamingManager.CompleteStream(completionMsg)
-		if cmd := m.setLoading(false, ""); cmd != nil {
+		m.notifyCompletion()
+		if cmd := m.maybeGenerateSessionTitle(); cmd != nil {
 			cmds = append(cmds, cmd)
 		}
-
-		// Handle the tool calls
-		if len(msg.ToolCalls) > 0 {
-			// Convert to non-streaming format and handle
-			toolMsg := ai.ToolCallsResponseMsg{
-				ToolCalls: msg.ToolCalls,
-				Response:  nil, // No full response in streaming
-			}
-			if cmd := m.handleToolCallsResponse(toolMsg); cmd != nil {
-				cmds = append(cmds, cmd)
-			}
+		if cmd := m.sendQueuedPrompt(); cmd != nil {
+			cmds = append(cmds, cmd)
 		}
 
-	case ai.StreamCompleteMsg:
-		// Use streaming manager to handle completion
-		completionCmd := m.streamingManager.CompleteStream(msg)
-		return m, completionCmd
-
-	case streaming.StreamCompleteInternalMsg:
-		// Handle streaming completion from streaming manager
-		m.handleStreamCompleteInternal(msg)
-
 	case editor.EditorFinishedMsg:
 		if msg.Error != nil {
-			m.addMessage("system", fmt.Sprintf("❌ Editor error: %v", msg.Error))
+			m.reportError(errlog.CategoryGeneral, fmt.Sprintf("Editor error: %v", msg.Error), msg.Error)
 		} else {
-			m.addMessage("system", "✓ Editor closed")
+			m.addMessage("system", i18n.T("status.editor_closed"))
 			
 			// Auto-reload any files that are currently loaded to pick up changes
 			if len(m.fileContext.Files) > 0 {
 				results, err := m.fileContext.ReloadFiles(nil) // Reload all loaded files
 				if err != nil {
-					m.addMessage("system", fmt.Sprintf("⚠️ Failed to auto-reload files: %v", err))
+					m.addMessage("system", i18n.T("reload.failed", err))
 				} else if len(results) > 0 {
 					changedCount := 0
 					for _, result := range results {
@@ -648,8 +941,9 @@ func (m *NewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						}
 					}
 					if changedCount > 0 {
-						m.addMessage("system", fmt.Sprintf("🔄 Auto-reloaded %d file(s), %d changed", len(results), changedCount))
+						m.addMessage("system", i18n.T("reload.after_edit", len(results), changedCount))
 					}
+					m.reportSuggestionChecks(results)
 					
 					// Update sidebar if visible
 					if m.filesWidgetVisible {
@@ -657,12 +951,20 @@ func (m *NewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					}
 				}
 			}
+			if msg.Context != nil {
+				m.prefillEditContext(msg.Context)
+			}
 		}
 		m.refreshViewport()
 
 	case tea.KeyMsg:
 		// Handle tool approval dialog first (highest priority)
 		if m.toolsManager.IsShowingApproval() && m.toolsManager.GetApprovalDialog() != nil {
+			// Esc cancels the whole chain, not just this tool call
+			if msg.String() == "esc" {
+				m.cancelToolChain()
+				return m, nil
+			}
 			done, response := m.toolsManager.GetApprovalDialog().Update(msg.String())
 			if done && response != nil {
 				m.toolsManager.SetShowingApproval(false)
@@ -672,6 +974,44 @@ func (m *NewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil // Dialog is still active
 		}
 
+		// The /config edit form takes all keys while open
+		if m.configEditor != nil {
+			if done, result := m.configEditor.Update(msg); done {
+				m.configEditor = nil
+				if result != nil {
+					m.saveConfigEdit(*result)
+				}
+			}
+			return m, nil
+		}
+
+		// The /view file viewer takes all keys while open
+		if m.fileViewer != nil {
+			if m.fileViewer.Update(msg) {
+				m.fileViewer = nil
+			}
+			return m, nil
+		}
+
+		// So does the /apply hunk picker
+		if m.hunkPicker != nil {
+			if done, accept := m.hunkPicker.Update(msg); done {
+				m.hunkPicker = nil
+				m.applyHunks(accept)
+			}
+			return m, nil
+		}
+
+		// And the /send --n answer picker
+		if m.choicePicker != nil {
+			if done, chosen := m.choicePicker.Update(msg); done {
+				m.choicePicker = nil
+				m.keepChoice(chosen)
+				return m, m.sendQueuedPrompt()
+			}
+			return m, nil
+		}
+
 		// Handle key detection mode (second priority)
 		if m.keyDetector != nil && m.keyDetector.IsDetecting() {
 			return m, m.keyDetector.HandleDetection(msg.String())
@@ -688,11 +1028,16 @@ func (m *NewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.apiCancel = nil
 				return m, func() tea.Msg { return cancelApiMsg{} }
 			}
+			// Cancel a tool chain that is between steps (running tool or queued follow-up)
+			if m.toolsManager != nil && m.toolsManager.HasPendingChain() {
+				m.cancelToolChain()
+				return m, nil
+			}
 		case "f1":
 			m.helpVisible = !m.helpVisible
 			if m.helpVisible {
 				if m.viewportManager != nil {
-					m.viewport.SetContent(m.viewportManager.HelpContent())
+					m.viewport.SetContent(m.renderer.FormatText(m.viewportManager.HelpContent()))
 				} else {
 					m.viewport.SetContent("Help not available")
 				}
@@ -701,6 +1046,10 @@ func (m *NewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 		case "f2":
+			if m.layoutManager.IsPlain() {
+				m.addMessage("system", i18n.T("status.plain_no_sidebar"))
+				return m, nil
+			}
 			m.filesWidgetVisible = !m.filesWidgetVisible
 			if m.filesWidgetVisible {
 				m.sidebarViewport.SetContent(m.renderFilesSidebar())
@@ -708,6 +1057,19 @@ func (m *NewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			m.layout()
 			return m, nil
+		case "r":
+			// Replay a stalled stream, unless the key is being typed into a message
+			if m.streamStalled && m.textarea.Value() == "" {
+				return m, m.retryStream()
+			}
+		case "f4":
+			// Show or hide the model, temperature and tokens under each response
+			if m.messageManager.ToggleAnnotations() {
+				m.addSystemMessage(i18n.T("status.annotations_on"))
+			} else {
+				m.addSystemMessage(i18n.T("status.annotations_off"))
+			}
+			return m, nil
 		case "f3":
 			// Toggle raw code mode for easy copying
 			if m.renderer != nil {
@@ -727,10 +1089,22 @@ func (m *NewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Handle viewport scrolling when viewport has focus
 		if m.focusMode == "viewport" {
 			switch msg.String() {
-			case "up", "down", "pgup", "pgdown", "ctrl+u", "ctrl+d", "home", "end":
+			case "pgup":
+				if m.viewport.AtTop() && m.messageManager.ArchivedCount() > 0 {
+					m.loadEarlierMessages()
+					return m, nil
+				}
 				m.viewport, cmd = m.viewport.Update(msg)
 				cmds = append(cmds, cmd)
 				return m, tea.Batch(cmds...)
+			case "up", "down", "pgdown", "ctrl+u", "ctrl+d", "home", "end":
+				m.viewport, cmd = m.viewport.Update(msg)
+				cmds = append(cmds, cmd)
+				return m, tea.Batch(cmds...)
+			case "]", "[":
+				// Jump between headings and code blocks instead of paging
+				m.jumpSection(msg.String() == "]")
+				return m, nil
 			case "tab":
 				// Continue focus cycle from viewport
 				if m.filesWidgetVisible {
@@ -755,6 +1129,10 @@ func (m *NewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.sidebarViewport, cmd = m.sidebarViewport.Update(msg)
 				cmds = append(cmds, cmd)
 				return m, tea.Batch(cmds...)
+			case "s", "g", "[", "]", " ":
+				// Sort, group and collapse the files
+				m.handleSidebarKey(msg.String())
+				return m, nil
 			case "tab":
 				// Complete focus cycle - back to input
 				m.focusMode = "input"
@@ -769,6 +1147,11 @@ func (m *NewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		// Input mode - handle special keys first, then let textarea handle the rest
 		if m.focusMode == "input" {
+			// Up on an empty prompt takes back the last queued message for editing
+			if msg.String() == "up" && m.textarea.Value() == "" && m.editQueuedPrompt() {
+				return m, nil
+			}
+
 			// Handle completion navigation with arrow keys
 			if m.inputManager != nil {
 				completions, _, showCompletions := m.inputManager.GetCompletionState()
@@ -874,6 +1257,12 @@ func (m *NewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.inputManager.ClearCompletions()
 			}
 
+			// Images pasted from the clipboard arrive as raw bytes; keep them out of the prompt
+			if msg.Paste && isBinaryPaste(msg.Runes) {
+				m.addMessage("system", "📋 The pasted data is not text (an image?), so it was not inserted. Save it to a file and /load it; with extract-text enabled, PDFs and images are loaded as their text.")
+				return m, nil
+			}
+
 			// Let textarea handle non-tab, non-history keys
 			if !historyHandled {
 				m.textarea, cmd = m.textarea.Update(msg)
@@ -890,60 +1279,19 @@ func (m *NewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					}
 
 					if strings.HasPrefix(input, "/") {
-						// Handle chat commands
-						cmd := m.handleCommand(input)
+						// Handle chat commands; they may put a prompt in the input
 						m.textarea.Reset()
+						cmd := m.handleCommand(input)
 						if m.inputManager != nil {
 							m.inputManager.ClearCompletions()
 						}
 						return m, cmd
-					} else {
-						// Add user message
-						m.addMessage("user", input)
-
-						// Send to API
-						if m.apiClient != nil {
-							contextPrompt := ""
-							if len(m.fileContext.Files) > 0 {
-								// Get config for smart context management
-								maxContextSize := 100000 // Default
-								if m.configManager != nil {
-									cfg := m.configManager.Get()
-									if cfg != nil && cfg.MaxContextSize > 0 {
-										maxContextSize = cfg.MaxContextSize
-									}
-								}
-
-								// Estimate if we need truncation (leave buffer for user input and API overhead)
-								inputSize := len(input)
-								bufferSize := inputSize + 10000 // Reserve 10KB for API overhead and user input
-								contextBudget := maxContextSize - bufferSize
-
-								if contextBudget > 5000 { // Only use truncation if we have reasonable budget
-									contextPrompt = m.fileContext.BuildContextPromptWithLimit(contextBudget)
-								} else {
-									// Very tight budget, use minimal context
-									contextPrompt = fmt.Sprintf("Files loaded: %d (content truncated due to size limits)\n",
-										len(m.fileContext.Files))
-								}
-							}
-
-							m.textarea.Reset()
-							if m.inputManager != nil {
-								m.inputManager.ClearCompletions()
-							}
-							if cmd := m.setLoading(true, "Thinking..."); cmd != nil {
-								cmds = append(cmds, cmd)
-							}
-							m.refreshViewport()
-
-							cmds = append(cmds, m.callAPI(contextPrompt, input))
-							return m, tea.Batch(cmds...)
-						} else {
-							m.addMessage("system", "Please set DEEPSEEK_API_KEY environment variable")
-							m.textarea.Reset()
-						}
 					}
+					if m.inputManager != nil {
+						m.inputManager.ClearCompletions()
+					}
+					cmds = append(cmds, m.handlePrompt(input))
+					return m, tea.Batch(cmds...)
 				}
 			}
 
@@ -954,7 +1302,7 @@ func (m *NewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	// If we have an error type message, handle it
 	if err, ok := msg.(error); ok {
-		m.addMessage("system", fmt.Sprintf("❌ Unexpected error: %v", err))
+		m.reportError(errlog.Classify(err), fmt.Sprintf("Unexpected error: %v", err), err)
 	}
 
 	return m, tea.Batch(cmds...)
@@ -967,7 +1315,16 @@ func (m NewModel) View() string {
 
 	// Build header using layout manager
 	filesCount := len(m.fileContext.Files)
-	header := m.layoutManager.RenderHeader(filesCount, m.focusMode, m.fileContext, m.renderer)
+	// Show streaming progress in the header once the spinner has given way to content
+	progress := ""
+	if m.streamingManager != nil && m.streamingManager.IsActive() && !m.isLoading && !m.renderer.IsAccessible() {
+		progress = m.spinner.Progress()
+	}
+	if m.fileTracker != nil {
+		m.layoutManager.SetNewMentions(m.fileTracker.NewCount())
+	}
+	m.layoutManager.SetContextWarning(m.contextWarning())
+	header := m.layoutManager.RenderHeader(filesCount, m.focusMode, m.fileContext, m.renderer, progress)
 
 	// Build main content area using layout manager
 	chatContent := m.viewport.View()
@@ -991,6 +1348,22 @@ func (m NewModel) View() string {
 		return fmt.Sprintf("%s\n%s", header, dialogView)
 	}
 
+	if m.configEditor != nil {
+		return fmt.Sprintf("%s\n%s", header, m.configEditor.View())
+	}
+
+	if m.fileViewer != nil {
+		return fmt.Sprintf("%s\n%s", header, m.fileViewer.View())
+	}
+
+	if m.hunkPicker != nil {
+		return fmt.Sprintf("%s\n%s", header, m.hunkPicker.View())
+	}
+
+	if m.choicePicker != nil {
+		return fmt.Sprintf("%s\n%s", header, m.choicePicker.View())
+	}
+
 	// Normal view when no approval dialog is shown
 	baseView := fmt.Sprintf("%s\n%s\n%s", header, mainContent, footer)
 	return baseView
@@ -1002,6 +1375,29 @@ func (m *NewModel) renderFilesSidebar() string {
 }
 
 
+// updatePlainMode switches to the lightweight UI when forced by config or --plain,
+// or when the terminal is narrower than the configured threshold
+func (m *NewModel) updatePlainMode() {
+	plain := m.forcePlain || m.renderer.IsAccessible()
+	if !plain && m.configManager != nil {
+		threshold := m.configManager.GetPlainModeWidth()
+		plain = m.configManager.GetPlainMode() || (threshold > 0 && m.width < threshold)
+	}
+	if plain == m.layoutManager.IsPlain() {
+		return
+	}
+
+	m.layoutManager.SetPlain(plain)
+	m.renderer.SetPlain(plain)
+	if plain {
+		m.filesWidgetVisible = false
+		if m.focusMode == "sidebar" {
+			m.focusMode = "input"
+			m.textarea.Focus()
+		}
+	}
+}
+
 // layout calculates and sets proper dimensions for all components
 func (m *NewModel) layout() {
 	// Calculate viewport dimensions using layout manager
@@ -1028,49 +1424,106 @@ func (m *NewModel) addMessage(role, content string) {
 	// Delegate to message manager
 	viewportWrapper := messages.NewViewportWrapper(&m.viewport)
 	m.messageManager.AddMessage(role, content, viewportWrapper, m.filesWidgetVisible)
+}
 
-	// Update local references for backward compatibility
-	m.messages = m.messageManager.GetMessages()
-	m.apiMessages = m.messageManager.GetAPIMessages()
+// addResponse adds a response of the model, annotated with the request that
+// produced it
+func (m *NewModel) addResponse(content string) {
+	viewportWrapper := messages.NewViewportWrapper(&m.viewport)
+	m.messageManager.AddResponse(content, m.lastGeneration(), viewportWrapper, m.filesWidgetVisible)
+}
+
+// lastGeneration returns the model, temperature and tokens of the last chat
+// request, or nil before the first one
+func (m *NewModel) lastGeneration() *api.Generation {
+	if m.apiClient == nil {
+		return nil
+	}
+	gen := m.apiClient.LastGeneration()
+	if gen.Model == "" {
+		return nil
+	}
+	return &gen
 }
 
 func (m *NewModel) refreshViewport() {
+	m.messageManager.SetTasks(m.taskStatuses())
 	// Delegate to message manager
 	viewportWrapper := messages.NewViewportWrapper(&m.viewport)
 	m.messageManager.RefreshViewport(viewportWrapper, m.isLoading, m.loadingMsg)
 }
 
-// updateStreamingDisplay updates the display with streaming content
-func (m *NewModel) updateStreamingDisplay(content string) {
-	// Only update if we have messages (allow updates during entire streaming process)
-	if len(m.messages) == 0 {
+// scrollbackPage is how many archived messages PgUp loads back at a time
+const scrollbackPage = 50
+
+// loadEarlierMessages loads archived messages back above the top of the
+// viewport, keeping the view where it was
+func (m *NewModel) loadEarlierMessages() {
+	before := m.viewport.TotalLineCount()
+	if _, err := m.messageManager.LoadEarlier(scrollbackPage); err != nil {
+		m.reportError(errlog.CategoryGeneral, "Failed to load earlier messages", err)
 		return
 	}
+	m.refreshViewport()
+	added := m.viewport.TotalLineCount() - before
+	m.viewport.SetYOffset(max(added-m.viewport.Height, 0))
+}
 
-    // Update the last message (which should be our streaming assistant message)
-    lastIdx := len(m.messages) - 1
-    m.messages[lastIdx] = m.renderer.FormatMessage("assistant", content)
-    // Keep message manager in sync so future refreshes don't lose content
-    if m.messageManager != nil {
-        m.messageManager.SetMessages(m.messages)
-    }
+// sectionTitles lists the headings and code blocks of the chat for /sections
+func (m *NewModel) sectionTitles() []string {
+	var titles []string
+	for _, section := range m.messageManager.Sections() {
+		titles = append(titles, section.Title)
+	}
+	return titles
+}
 
-	// Update viewport content
-	m.viewport.SetContent(strings.Join(m.messages, "\n\n"))
-	m.viewport.GotoBottom()
+// gotoSection scrolls the chat to the section at index, as listed by
+// /sections, and focuses the chat so [ and ] continue from there
+func (m *NewModel) gotoSection(index int) bool {
+	sections := m.messageManager.Sections()
+	if index < 0 || index >= len(sections) {
+		return false
+	}
+	m.refreshViewport()
+	m.viewport.SetYOffset(sections[index].Line)
+	m.focusMode = "viewport"
+	m.textarea.Blur()
+	return true
 }
 
+// jumpSection scrolls the chat to the next section below the top of the
+// viewport, or the previous one above it
+func (m *NewModel) jumpSection(forward bool) {
+	sections := m.messageManager.Sections()
+	top := m.viewport.YOffset
+	if forward {
+		for _, section := range sections {
+			if section.Line > top {
+				m.viewport.SetYOffset(section.Line)
+				return
+			}
+		}
+		return
+	}
+	for i := len(sections) - 1; i >= 0; i-- {
+		if sections[i].Line < top {
+			m.viewport.SetYOffset(sections[i].Line)
+			return
+		}
+	}
+}
 
 // addSystemMessage adds a temporary system message to the viewport
 func (m *NewModel) addSystemMessage(message string) {
 	// Format as system message
 	formatted := m.renderer.FormatMessage("system", message)
 
-	// Add to messages temporarily (not saved to API messages)
-	m.messages = append(m.messages, formatted)
+	// Display only; not saved to the session or API messages
+	m.messageManager.AddDisplayMessage(formatted)
 
 	// Update viewport
-	m.viewport.SetContent(strings.Join(m.messages, "\n\n"))
+	m.viewport.SetContent(m.messageManager.Render(""))
 	m.viewport.GotoBottom()
 }
 
@@ -1094,25 +1547,22 @@ func (m *NewModel) callAPI(contextPrompt, userInput string) tea.Cmd {
 	// Check if context is too large for reliable streaming
 	contextSize := len(contextPrompt) + len(userInput)
 
-	// Get max context size and max tokens from config
+	// Get max context size, max tokens and the model's window from config
 	maxContextSize := 50000
 	maxTokens := 2048
+	modelTokenLimit := config.LookupModel("").ContextWindow
 	if m.configManager != nil {
-		cfg := m.configManager.Get()
-		if cfg.MaxContextSize > 0 {
-			maxContextSize = cfg.MaxContextSize
-		}
-		if cfg.MaxTokens > 0 {
-			maxTokens = cfg.MaxTokens
+		maxContextSize = m.configManager.GetMaxContextSize()
+		if tokens := m.configManager.GetMaxTokens(); tokens > 0 {
+			maxTokens = tokens
 		}
+		modelTokenLimit = config.LookupModel(m.configManager.GetModel()).ContextWindow
 	}
 
 	// Estimate tokens (rough approximation: 1 token ≈ 4 characters)
 	contextTokens := contextSize / 4
 	totalTokens := contextTokens + maxTokens
 
-	// DeepSeek models have ~128K token limit, leave safety margin
-	const modelTokenLimit = 120000
 	if totalTokens > modelTokenLimit {
 		return func() tea.Msg {
 			return ai.APIResponseMsg{
@@ -1141,6 +1591,7 @@ func (m *NewModel) callAPI(contextPrompt, userInput string) tea.Cmd {
     
     // Use streaming when enabled, no tools, and total context is under threshold
     if m.streamingEnabled && contextSize < streamingThreshold {
+		m.streamRetried = false
 		cmd := m.aiOperations.CallAPIStream(contextPrompt, userInput)
 		// Store the cancel function
 		m.apiCancel = m.aiOperations.GetAPICancel()
@@ -1154,37 +1605,70 @@ func (m *NewModel) callAPI(contextPrompt, userInput string) tea.Cmd {
 	return cmd
 }
 
-func (m *NewModel) analyzeFiles() tea.Cmd {
+// retryStream drops the stalled stream and what it displayed, and sends the
+// same request again
+func (m *NewModel) retryStream() tea.Cmd {
+	m.streamStalled = false
+	m.streamRetried = true
+	m.streamingManager.Discard(m.messageManager)
+	cmd := m.aiOperations.RetryStream()
+	m.apiCancel = m.aiOperations.GetAPICancel()
+	if loading := m.setLoading(true, i18n.T("loading.thinking")); loading != nil {
+		cmd = tea.Batch(cmd, loading)
+	}
+	m.refreshViewport()
+	return cmd
+}
+
+func (m *NewModel) analyzeFiles(loaded []files.LoadedFile, output string) tea.Cmd {
 	if m.aiOperations == nil {
 		return func() tea.Msg {
 			return ai.APIResponseMsg{Err: fmt.Errorf("AI operations not available")}
 		}
 	}
-	return m.aiOperations.AnalyzeFiles()
+	if loaded == nil {
+		loaded = m.fileContext.Files
+	}
+	ops, loaded := m.aiOperations, slices.Clone(loaded)
+	return m.startTask(fmt.Sprintf("Analyze %d file(s)", len(loaded)), output, func(ctx context.Context, progress tasks.Progress) (string, error) {
+		return ops.AnalyzeFiles(ctx, loaded, progress)
+	})
 }
 
-func (m *NewModel) explainFiles() tea.Cmd {
+func (m *NewModel) explainFiles(loaded []files.LoadedFile, output string) tea.Cmd {
 	if m.aiOperations == nil {
 		return func() tea.Msg {
 			return ai.APIResponseMsg{Err: fmt.Errorf("AI operations not available")}
 		}
 	}
-	return m.aiOperations.ExplainFiles()
+	if loaded == nil {
+		loaded = m.fileContext.Files
+	}
+	ops, loaded := m.aiOperations, slices.Clone(loaded)
+	return m.startTask(fmt.Sprintf("Explain %d file(s)", len(loaded)), output, func(ctx context.Context, progress tasks.Progress) (string, error) {
+		return ops.ExplainFiles(ctx, loaded, progress)
+	})
 }
 
-func (m *NewModel) improveFiles() tea.Cmd {
+func (m *NewModel) improveFiles(loaded []files.LoadedFile, output string) tea.Cmd {
 	if m.aiOperations == nil {
 		return func() tea.Msg {
 			return ai.APIResponseMsg{Err: fmt.Errorf("AI operations not available")}
 		}
 	}
-	return m.aiOperations.ImproveFiles()
+	if loaded == nil {
+		loaded = m.fileContext.Files
+	}
+	ops, loaded := m.aiOperations, slices.Clone(loaded)
+	return m.startTask(fmt.Sprintf("Improve %d file(s)", len(loaded)), output, func(ctx context.Context, progress tasks.Progress) (string, error) {
+		return ops.ImproveFiles(ctx, loaded, progress)
+	})
 }
 
 func (m *NewModel) generateEditSuggestions() tea.Cmd {
 	if m.aiOperations == nil {
 		return func() tea.Msg {
-			return ai.APIResponseMsg{Err: fmt.Errorf("AI operations not available")}
+			return ai.EditSuggestionsMsg{Err: fmt.Errorf("AI operations not available")}
 		}
 	}
 	cmd := m.aiOperations.GenerateEditSuggestions()
@@ -1200,28 +1684,406 @@ func (m *NewModel) generateEditSuggestions() tea.Cmd {
 
 
 
+func (m *NewModel) initProject() tea.Cmd {
+	if m.aiOperations == nil {
+		return func() tea.Msg {
+			return ai.ProjectSummaryMsg{Err: fmt.Errorf("AI operations not available")}
+		}
+	}
+	cmd := m.aiOperations.InitProject()
+	// Store the cancel function
+	m.apiCancel = m.aiOperations.GetAPICancel()
+	return cmd
+}
+
+// compactConversation summarizes the conversation except the last keep turns
+func (m *NewModel) compactConversation(keep int) tea.Cmd {
+	if m.aiOperations == nil {
+		return func() tea.Msg {
+			return ai.CompactedMsg{Err: fmt.Errorf("AI operations not available")}
+		}
+	}
+	cmd := m.aiOperations.CompactConversation(keep)
+	m.apiCancel = m.aiOperations.GetAPICancel()
+	return cmd
+}
+
+// reviewDiff reviews the chunks of the diff of refRange one request at a time
+func (m *NewModel) reviewDiff(refRange string, chunks []string, output string) tea.Cmd {
+	if m.aiOperations == nil {
+		return func() tea.Msg {
+			return ai.APIResponseMsg{Err: fmt.Errorf("AI operations not available")}
+		}
+	}
+	ops := m.aiOperations
+	return m.startTask("Review "+refRange, output, func(ctx context.Context, progress tasks.Progress) (string, error) {
+		return ops.ReviewDiff(ctx, refRange, chunks, progress)
+	})
+}
+
+// documentPackages asks for the documentation of Go packages in a
+// background task; its diffs are tracked for /apply when it finishes
+func (m *NewModel) documentPackages(packages []ai.DocPackage) tea.Cmd {
+	if m.aiOperations == nil {
+		return func() tea.Msg {
+			return ai.APIResponseMsg{Err: fmt.Errorf("AI operations not available")}
+		}
+	}
+	ops := m.aiOperations
+	return m.startTask(fmt.Sprintf("Document %d package(s)", len(packages)), "", func(ctx context.Context, progress tasks.Progress) (string, error) {
+		return ops.DocumentPackages(ctx, packages, progress)
+	})
+}
+
+// handleCompacted replaces the summarized messages with the summary and
+// reports how many tokens that saved
+func (m *NewModel) handleCompacted(msg ai.CompactedMsg) {
+	m.setLoading(false, "")
+	m.apiCancel = nil
+	m.showRedactionNotice()
+
+	if msg.Err != nil {
+		m.reportError(errlog.Classify(msg.Err), "Compacting failed: "+errlog.UserMessage(msg.Err), msg.Err)
+		return
+	}
+
+	history := m.messageManager.GetAPIMessages()
+	if msg.Replaced > len(history) {
+		m.reportError(errlog.CategoryGeneral, "Compacting failed: the conversation changed meanwhile", fmt.Errorf("history shrank to %d messages", len(history)))
+		return
+	}
+
+	compacted := []api.Message{{Role: "system", Content: "Summary of the earlier conversation:\n" + msg.Summary}}
+	compacted = append(compacted, history[msg.Replaced:]...)
+	before := ai.EstimateHistoryTokens(history)
+	m.messageManager.SetAPIMessages(compacted)
+	after := ai.EstimateHistoryTokens(compacted)
+
+	m.addMessage("system", fmt.Sprintf("🗜️ Compacted %d message(s) into a summary: ~%d → ~%d tokens\n\n%s", msg.Replaced, before, after, msg.Summary))
+}
+
+// handleProjectSummary saves the generated project map and adds it to the context
+func (m *NewModel) handleProjectSummary(msg ai.ProjectSummaryMsg) {
+	m.setLoading(false, "")
+	m.apiCancel = nil
+	m.showRedactionNotice()
+
+	if msg.Err != nil {
+		m.reportError(errlog.Classify(msg.Err), "Project init failed: "+errlog.UserMessage(msg.Err), msg.Err)
+		return
+	}
+
+	if err := files.WriteProjectSummary(msg.Content); err != nil {
+		m.reportError(errlog.CategoryGeneral, "", err)
+		return
+	}
+
+	if err := m.fileContext.LoadFile(files.ProjectSummaryPath); err != nil {
+		m.addMessage("system", fmt.Sprintf("⚠️ Project map saved but not loaded: %v", err))
+	}
+
+	m.addMessage("system", msg.Content)
+	m.addMessage("system", fmt.Sprintf("✅ Project map written to %s\n   It is included as context automatically in future sessions", files.ProjectSummaryPath))
+}
+
+// openConfigEditor shows the /config edit form, saving to the project config
+// when there is one like /config set does
+func (m *NewModel) openConfigEditor() {
+	if err := m.configManager.Load(); err != nil {
+		m.reportError(errlog.CategoryConfig, "Failed to load configuration", err)
+		return
+	}
+	scope := ui.ScopeGlobal
+	if m.configManager.ProjectConfigExists() {
+		scope = ui.ScopeProject
+	}
+	m.configEditor = ui.NewConfigEditor(*m.configManager.Get(), scope, m.width, m.height)
+}
+
+// registerShutdownHooks sets up what the app releases on exit. Hooks run in
+// reverse order: requests are cancelled first and the session database is
+// closed last. Input history and chat messages are written as they are
+// entered, so they need no flushing.
+func (m *NewModel) registerShutdownHooks() {
+	m.shutdown = shutdown.NewManager()
+	if m.sessionManager != nil {
+		m.shutdown.Register("session database", m.sessionManager.Close)
+	}
+	if m.apiClient != nil {
+		m.shutdown.Register("API client", func() error {
+			m.apiClient.Close()
+			return nil
+		})
+	}
+	m.shutdown.Register("file watcher", m.fileContext.Close)
+	m.shutdown.Register("session", func() error {
+		// Keep the part of a response that was still streaming
+		if m.streamingManager.IsActive() && m.streamingManager.GetStreamContent() != "" {
+			m.messageManager.RecordMessage("assistant", m.streamingManager.GetStreamContent())
+		}
+		m.streamingManager.Reset()
+		return nil
+	})
+	m.shutdown.Register("API requests", func() error {
+		if m.apiCancel != nil {
+			m.apiCancel()
+			m.apiCancel = nil
+		}
+		m.taskManager.CancelAll()
+		return nil
+	})
+}
+
+// switchSession makes session the current session, so that new messages are
+// saved to it
+func (m *NewModel) switchSession(session *sessions.Session) {
+	m.currentSession = session
+	m.messageManager.SetSession(session)
+}
+
+// openFileViewer shows content read-only in place of the chat until closed
+func (m *NewModel) openFileViewer(path, content string, line int) {
+	plain := m.layoutManager.IsPlain() || m.renderer.IsAccessible()
+	m.fileViewer = ui.NewFileViewer(path, content, line, plain, m.width, m.height)
+}
+
+// openCompareViewer shows the loaded and on-disk content of a file side by side
+func (m *NewModel) openCompareViewer(path, loaded, disk string) {
+	plain := m.layoutManager.IsPlain() || m.renderer.IsAccessible()
+	m.fileViewer = ui.NewCompareViewer(path, loaded, disk, plain, m.width, m.height)
+}
+
+// openHunkPicker lets the user choose which hunks of a suggested diff to
+// apply, in place of the chat until done
+func (m *NewModel) openHunkPicker(suggestion files.PatchSuggestion) {
+	plain := m.layoutManager.IsPlain() || m.renderer.IsAccessible()
+	m.hunkSuggestion = suggestion
+	m.hunkPicker = ui.NewHunkPicker(suggestion.RelPath, files.PatchHunks(suggestion.Patch), plain, m.width, m.height)
+}
+
+// applyHunks writes the hunks accepted in the hunk picker; nil means the
+// picker was cancelled
+func (m *NewModel) applyHunks(accept []bool) {
+	suggestion := m.hunkSuggestion
+	m.hunkSuggestion = files.PatchSuggestion{}
+	if accept == nil {
+		m.addMessage("system", fmt.Sprintf("Cancelled; %s was not changed", suggestion.RelPath))
+		return
+	}
+
+	applied, err := m.fileContext.ApplySuggestion(suggestion, accept)
+	switch {
+	case err != nil && applied.Hunks == 0:
+		m.reportError(errlog.CategoryGeneral, fmt.Sprintf("Applying the diff to %s failed", suggestion.RelPath), err)
+		return
+	case err != nil:
+		m.addMessage("system", fmt.Sprintf("⚠️ Applied %d of %d hunk(s) to %s, but reloading it failed: %v. Use /reload", applied.Hunks, len(accept), suggestion.RelPath, err))
+	case applied.Hunks == 0:
+		m.addMessage("system", fmt.Sprintf("⏭️ Skipped every hunk; %s was not changed and the suggestion was dropped", suggestion.RelPath))
+		return
+	default:
+		message := fmt.Sprintf("✅ Applied %d of %d hunk(s) to %s", applied.Hunks, len(accept), suggestion.RelPath)
+		switch {
+		case applied.FormatErr != nil:
+			message = fmt.Sprintf("⚠️ Applied %d of %d hunk(s) to %s unformatted: %v", applied.Hunks, len(accept), suggestion.RelPath, applied.FormatErr)
+		case applied.Formatter != "":
+			message += ", formatted with " + applied.Formatter
+		}
+		m.addMessage("system", message)
+	}
+	if applied.Diff != "" {
+		m.addMessage("system", "```diff\n"+applied.Diff+"```")
+	}
+}
+
+// prefillEditContext puts where the user was in the editor into the input,
+// below any draft, so the next message can refer to it
+func (m *NewModel) prefillEditContext(where *editor.EditContext) {
+	snippet := where.Snippet()
+	if draft := strings.TrimRight(m.textarea.Value(), "\n"); draft != "" {
+		snippet = draft + "\n\n" + snippet
+	}
+	m.textarea.SetValue(snippet)
+	location := fmt.Sprintf("%s:%d", where.Path, where.Line)
+	if where.Start > 0 {
+		location = fmt.Sprintf("%s:%d-%d", where.Path, where.Start, where.End)
+	}
+	m.addMessage("system", i18n.T("status.editor_context", location))
+}
+
+// saveConfigEdit writes the settings saved in the /config edit form
+func (m *NewModel) saveConfigEdit(result ui.ConfigEditResult) {
+	if len(result.Changed) == 0 {
+		m.addMessage("system", "No configuration changes")
+		return
+	}
+
+	var err error
+	path := "~/.deecli/config.yaml"
+	if result.Scope == ui.ScopeProject {
+		path = "./.deecli/config.yaml"
+		err = m.configManager.SaveProject(&result.Config)
+	} else {
+		err = m.configManager.SaveGlobal(&result.Config)
+	}
+	if err != nil {
+		m.reportError(errlog.CategoryConfig, "Failed to save configuration", err)
+		return
+	}
+	if err := m.configManager.Load(); err != nil {
+		m.reportError(errlog.CategoryConfig, "Configuration saved but reload failed", err)
+		return
+	}
+
+	m.applyConfig()
+
+	m.addMessage("system", fmt.Sprintf("✅ Saved %s to %s\n   Some settings (redact-secrets, screen-reader) apply from the next session",
+		strings.Join(result.Changed, ", "), path))
+}
+
+// reviewPullRequest loads the diff fetched by /pr review and asks for a review
+func (m *NewModel) reviewPullRequest(msg commands.PullRequestFetchedMsg) tea.Cmd {
+	if msg.Err != nil {
+		m.setLoading(false, "")
+		m.reportError(errlog.Classify(msg.Err), "Failed to fetch the pull request: "+errlog.UserMessage(msg.Err), msg.Err)
+		return nil
+	}
+
+	pr := msg.PullRequest
+	if err := m.fileContext.LoadFile(msg.DiffPath); err != nil {
+		m.addMessage("system", fmt.Sprintf("⚠️ Diff saved to %s but not loaded: %v", msg.DiffPath, err))
+	} else {
+		m.addMessage("system", fmt.Sprintf("🔀 Loaded #%d %s (%s by %s) from %s", pr.Number, pr.Title, pr.State, pr.Author, msg.DiffPath))
+	}
+	if m.aiOperations == nil {
+		m.setLoading(false, "")
+		return nil
+	}
+
+	loadingCmd := m.setLoading(true, fmt.Sprintf("Reviewing #%d...", pr.Number))
+	cmd := m.aiOperations.ReviewPullRequest(pr.Title, pr.Body, pr.Diff)
+	// Store the cancel function
+	m.apiCancel = m.aiOperations.GetAPICancel()
+	return tea.Batch(loadingCmd, cmd)
+}
+
+// sessionTitleMinMessages is how many chat messages a session needs before it gets a title
+const sessionTitleMinMessages = 4
+
+// maybeGenerateSessionTitle requests a title once the session has enough messages
+func (m *NewModel) maybeGenerateSessionTitle() tea.Cmd {
+	if m.titleRequested || m.aiOperations == nil || m.apiClient == nil ||
+		m.sessionManager == nil || m.currentSession == nil || m.currentSession.Title != "" {
+		return nil
+	}
+
+	chatMessages := 0
+	for _, msg := range m.messageManager.GetAPIMessages() {
+		if msg.Role == "user" || msg.Role == "assistant" {
+			chatMessages++
+		}
+	}
+	if chatMessages < sessionTitleMinMessages {
+		return nil
+	}
+
+	m.titleRequested = true
+	return m.aiOperations.GenerateSessionTitle(m.currentSession.ID)
+}
+
+// handleSessionTitle stores a generated session title
+func (m *NewModel) handleSessionTitle(msg ai.SessionTitleMsg) {
+	if msg.Err != nil || msg.Title == "" {
+		debug.Printf("[DEBUG] Session title generation failed: %v\n", msg.Err)
+		return
+	}
+
+	if err := m.sessionManager.SetSessionTitle(msg.SessionID, msg.Title); err != nil {
+		debug.Printf("[DEBUG] Failed to save session title: %v\n", err)
+		return
+	}
+
+	if m.currentSession != nil && m.currentSession.ID == msg.SessionID {
+		m.currentSession.Title = msg.Title
+	}
+}
+
+// notifyCompletion alerts the user when a response finishes while the terminal is unfocused
+func (m *NewModel) notifyCompletion() {
+	if m.hasFocus || m.isLoading || m.configManager == nil {
+		return
+	}
+
+	mode := m.configManager.GetNotifyOnComplete()
+	if mode == notify.ModeOff {
+		return
+	}
+
+	if err := notify.Send(mode, "DeeCLI", "Response complete"); err != nil {
+		debug.Printf("[DEBUG] Completion notification failed: %v\n", err)
+	}
+}
+
+// reportSuggestionChecks warns when a reload changed a file that has pending AI diffs
+func (m *NewModel) reportSuggestionChecks(results []files.ReloadResult) {
+	for _, result := range results {
+		for _, check := range result.Suggestions {
+			m.addMessage("system", check.Summary())
+		}
+	}
+}
+
+// showRedactionNotice tells the user which secrets were masked in the last request
+func (m *NewModel) showRedactionNotice() {
+	if summary := m.redactor.TakeSummary(); summary != "" {
+		m.addMessage("system", fmt.Sprintf("🔒 Redacted before sending: %s", summary))
+	}
+}
+
+// reportError records an error in the error log and shows it in the chat
+func (m *NewModel) reportError(category errlog.Category, summary string, err error) {
+	entry := m.errorLog.Record(category, summary, err)
+	m.addMessage("system", entry.Format())
+}
+
+// reportToolFailure reports a tool call that returned an error
+func (m *NewModel) reportToolFailure(msg ToolExecutionCompleteMsg) {
+	name := msg.ToolCall.Function.Name
+	switch {
+	case msg.Error != nil:
+		m.reportError(errlog.CategoryTool, fmt.Sprintf("Tool %s failed: %v", name, msg.Error), msg.Error)
+	case msg.Result != nil && !msg.Result.Success:
+		m.reportError(errlog.CategoryTool, fmt.Sprintf("Tool %s failed: %s", name, msg.Result.Error), fmt.Errorf("%s", msg.Result.Error))
+	}
+}
+
 // handleAPIResponse handles API responses for both old and new message types
-func (m *NewModel) handleAPIResponse(response string, err error) {
+func (m *NewModel) handleAPIResponse(response string, err error) tea.Cmd {
 	m.setLoading(false, "")
 	m.apiCancel = nil
 
 	// Delegate to API response handler
-	result := m.apiResponseHandler.HandleResponse(response, err, m.toolsManager.ShouldSuppressToolCalls(), m.fileContext)
+	suppressed := m.toolsManager.ShouldSuppressToolCalls()
+	result := m.apiResponseHandler.HandleResponse(response, err, suppressed, m.fileContext)
 
 	// Clear suppress flag if it was set
-	if m.toolsManager.ShouldSuppressToolCalls() {
+	if suppressed {
 		m.toolsManager.ClearSuppressToolCalls()
 		debug.Printf("[DEBUG] Suppressing tool call parsing for this response (tool_choice=none follow-up)\n")
 	}
 
 	if !result.Success {
+		if cmd := m.retryOverflow(err, suppressed); cmd != nil {
+			return cmd
+		}
 		// Handle error result
 		if result.ErrorMessage != "" {
-			m.addMessage("system", result.ErrorMessage)
+			m.reportError(errlog.Classify(err), "", err)
 		}
-	} else if result.AssistantContent != "" {
+	} else if strings.TrimSpace(result.AssistantContent) != "" {
 		// Handle successful response
-		m.addMessage("assistant", result.AssistantContent)
+		m.addResponse(result.AssistantContent)
+		m.fileContext.TrackPatchSuggestions(result.AssistantContent)
 
 		// Handle tool calls if present
 		if len(result.ToolCalls) > 0 {
@@ -1230,10 +2092,15 @@ func (m *NewModel) handleAPIResponse(response string, err error) {
 				Response:  nil,
 			}
 			m.handleToolCallsResponse(toolMsg)
+		} else {
+			m.citeSources()
 		}
+	} else if len(result.ToolCalls) == 0 {
+		return m.reaskEmpty(suppressed)
 	}
 
 	m.viewport.GotoBottom()
+	return nil
 }
 
 // parseAndExtractToolCalls parses DeepSeek's tool call markup and extracts proper tool calls
@@ -1243,96 +2110,47 @@ func (m *NewModel) parseAndExtractToolCalls(content string) ([]api.ToolCall, str
 }
 
 // handleStreamCompleteInternal handles completion from streaming manager
-func (m *NewModel) handleStreamCompleteInternal(msg streaming.StreamCompleteInternalMsg) {
+func (m *NewModel) handleStreamCompleteInternal(msg streaming.StreamCompleteInternalMsg) tea.Cmd {
 	m.setLoading(false, "")
 	m.apiCancel = nil
-
-	// Clean up old streaming state
-	m.streamReader = nil
-	m.streamContent = ""
+	m.showRedactionNotice()
 
 	if msg.Err != nil {
+		if cmd := m.retryOverflow(msg.Err, false); cmd != nil {
+			return cmd
+		}
 		// Handle error cases
 		if apiErr, ok := msg.Err.(api.APIError); ok {
 			if apiErr.Message != "request cancelled by user" {
-				errorMsg := fmt.Sprintf("❌ %s", apiErr.UserMessage)
-				if apiErr.StatusCode > 0 {
-					errorMsg += fmt.Sprintf(" (HTTP %d)", apiErr.StatusCode)
-				}
-				m.addMessage("system", errorMsg)
+				m.reportError(errlog.Classify(msg.Err), "", msg.Err)
 			}
 		} else if msg.Err != context.Canceled {
-			m.addMessage("system", fmt.Sprintf("❌ Error: %v", msg.Err))
+			m.reportError(errlog.Classify(msg.Err), fmt.Sprintf("Error: %v", msg.Err), msg.Err)
 		}
-	} else if msg.Content != "" {
+	} else if strings.TrimSpace(msg.Content) != "" {
 		// Handle successful completion
-		// If no message was added during streaming (no meaningful content), add it now
+		// If no message was added during streaming (no meaningful content), add it now;
+		// otherwise it is already displayed and only needs recording
 		if !msg.MessageAdded && msg.FinalContent != "" {
-			m.addMessage("assistant", msg.FinalContent)
+			m.addResponse(msg.FinalContent)
+		} else if msg.MessageAdded {
+			m.messageManager.RecordResponse(msg.Content, m.lastGeneration())
+			m.messageManager.SetLastSource(msg.Content)
 		}
 
 		// Track files mentioned in response
 		if m.fileTracker != nil {
 			m.fileTracker.ExtractFilesFromResponseWithContext(msg.Content, m.fileContext.Files)
 		}
-
-		// Add to API messages for history
-		m.apiMessages = append(m.apiMessages, api.Message{
-			Role:    "assistant",
-			Content: msg.Content,
-		})
+		m.fileContext.TrackPatchSuggestions(msg.Content)
+		m.citeSources()
+	} else {
+		return m.reaskEmpty(false)
 	}
 
 	// Ensure viewport is up to date
 	m.viewport.GotoBottom()
-}
-
-// Following the official Bubbletea chat example pattern
-// handleStreamComplete handles the completion of a stream
-func (m *NewModel) handleStreamComplete(content string, err error) {
-	m.setLoading(false, "")
-	m.apiCancel = nil
-	m.streamReader = nil
-	m.streamContent = ""
-
-	if err != nil {
-		// Check if it's an enhanced APIError
-		if apiErr, ok := err.(api.APIError); ok {
-			// Show user-friendly message, but don't show cancellation as error
-			if apiErr.Message != "request cancelled by user" {
-				errorMsg := fmt.Sprintf("❌ %s", apiErr.UserMessage)
-				if apiErr.StatusCode > 0 {
-					errorMsg += fmt.Sprintf(" (HTTP %d)", apiErr.StatusCode)
-				}
-				m.addMessage("system", errorMsg)
-			}
-		} else if err != context.Canceled {
-			// Don't show error for context cancellation
-			m.addMessage("system", fmt.Sprintf("❌ Error: %v", err))
-		}
-	} else {
-		// Ensure final content is properly set (update the last message with final content)
-		if len(m.messages) > 0 {
-			lastIdx := len(m.messages) - 1
-			m.messages[lastIdx] = m.renderer.FormatMessage("assistant", content)
-			// Sync message manager before updating viewport
-			if m.messageManager != nil {
-				m.messageManager.SetMessages(m.messages)
-			}
-			m.viewport.SetContent(strings.Join(m.messages, "\n\n"))
-		}
-
-		// Track files mentioned in the AI response
-		if m.fileTracker != nil {
-			m.fileTracker.ExtractFilesFromResponseWithContext(content, m.fileContext.Files)
-		}
-		// Add to API messages for history
-		m.apiMessages = append(m.apiMessages, api.Message{
-			Role:    "assistant",
-			Content: content,
-		})
-	}
-	m.viewport.GotoBottom()
+	return nil
 }
 
 func (m *NewModel) loadPreviousSession() error {
@@ -1340,22 +2158,33 @@ func (m *NewModel) loadPreviousSession() error {
 		return fmt.Errorf("no session loader available")
 	}
 
-	messages, apiMessages, err := m.sessionLoader.LoadSession()
+	messages, annotations, apiMessages, err := m.sessionLoader.LoadSession()
 	if err != nil {
 		return err
 	}
 
-	// Use message manager to set messages
-	m.messageManager.SetMessages(messages)
+	m.messageManager.SetMessages(messages, annotations)
 	m.messageManager.SetAPIMessages(apiMessages)
 
-	// Update local references for backward compatibility
-	m.messages = messages
-	m.apiMessages = apiMessages
-
 	return nil
 }
 
+// auditContext identifies the session and the user message (by its 1-based
+// position in the conversation) that started the current tool chain
+func (m *NewModel) auditContext() (int64, int) {
+	var sessionID int64
+	if m.currentSession != nil {
+		sessionID = m.currentSession.ID
+	}
+	messages := m.messageManager.GetAPIMessages()
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Role == "user" {
+			return sessionID, i + 1
+		}
+	}
+	return sessionID, 0
+}
+
 // handleToolCallsResponse handles AI responses that request tool executions
 func (m *NewModel) handleToolCallsResponse(msg ai.ToolCallsResponseMsg) tea.Cmd {
 	// Delegate to tools manager
@@ -1374,17 +2203,76 @@ func (m *NewModel) executeApprovedTool(response tools.ApprovalResponse) tea.Cmd
 	return m.toolsManager.ExecuteApprovedTool(response)
 }
 
+// cancelToolChain discards queued tool calls and pending follow-ups and returns to input
+func (m *NewModel) cancelToolChain() {
+	discarded := m.toolsManager.CancelChain()
+	m.setLoading(false, "")
+	m.apiCancel = nil
+	m.focusMode = "input"
+	m.textarea.Focus()
+
+	status := i18n.T("status.tool_chain_cancelled")
+	if discarded > 0 {
+		status += i18n.T("status.tool_calls_discarded", discarded)
+	}
+	m.addMessage("system", status+i18n.T("status.ready"))
+	m.restoreQueuedPrompts()
+}
+
+// citeSources adds a footnote under the response just shown listing the tool
+// calls that informed it, so answers grounded in tool results stand out.
+// /sources expands the footnote with the matching audit log entries.
+func (m *NewModel) citeSources() {
+	sources := m.toolsManager.TakeSources()
+	if len(sources) == 0 {
+		return
+	}
+	m.lastSources = sources
+
+	citations := make([]string, len(sources))
+	for i, source := range sources {
+		citation := fmt.Sprintf("[%d] %s", i+1, source.Tool)
+		if target := source.Target(); target != "" {
+			citation += " " + target
+		}
+		if status := source.Status(); status != "ok" {
+			citation += " (" + status + ")"
+		}
+		citations[i] = citation
+	}
+	m.addMessage("system", "📎 Sources: "+strings.Join(citations, " · ")+" (/sources for details)")
+}
+
 // Use ToolExecutionCompleteMsg from tools manager
 type ToolExecutionCompleteMsg = toolsManager.ToolExecutionCompleteMsg
 
 // handleToolExecutionComplete handles the completion of tool execution
 func (m *NewModel) handleToolExecutionComplete(msg ToolExecutionCompleteMsg) tea.Cmd {
 	// Delegate to tools manager and handle success/failure
+	cancelled := !m.toolsManager.HasPendingChain()
 	cmd, success := m.toolsManager.HandleToolExecutionComplete(msg, m.aiOperations)
 	if !success {
 		// Tool execution failed, no further action needed
+		if !cancelled {
+			m.reportToolFailure(msg)
+		}
 		return nil
 	}
 	// Return the command from tools manager (may trigger follow-up or next tool)
 	return cmd
 }
+
+// isBinaryPaste reports whether pasted input looks like binary data rather
+// than text: it holds NUL bytes, invalid UTF-8 or many control characters
+func isBinaryPaste(runes []rune) bool {
+	control := 0
+	for _, r := range runes {
+		switch {
+		case r == 0 || r == utf8.RuneError:
+			return true
+		case r < 32 && r != '\n' && r != '\r' && r != '\t':
+			control++
+		}
+	}
+	return control > 0 && control*10 > len(runes)
+}
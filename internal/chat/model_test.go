@@ -14,8 +14,20 @@
 package chat
 
 import (
+	"errors"
+	"io"
+	"os"
+	"runtime"
+	"strings"
+	"syscall"
 	"testing"
+	"time"
 
+	"github.com/antenore/deecli/internal/api"
+	"github.com/antenore/deecli/internal/chat/commands"
+	"github.com/antenore/deecli/internal/files"
+	"github.com/antenore/deecli/internal/i18n"
+	"github.com/antenore/deecli/internal/sessions"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
@@ -179,4 +191,310 @@ func TestFocusModeArrowKeys(t *testing.T) {
 	if model.textarea.Value() == "" && len(model.inputManager.GetInputHistory()) > 0 {
 		t.Error("In input focus mode, up arrow should navigate history")
 	}
-}
\ No newline at end of file
+}
+
+func TestIsBinaryPaste(t *testing.T) {
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{"func main() {\n\tfmt.Println(\"hi\")\n}", false},
+		{"naïve café ✓", false},
+		{"\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR", true},
+		{string([]byte{0xff, 0xd8, 0xff, 0xe0}), true},
+		{"\x1b\x02\x03\x04\x05ab", true},
+	}
+	for _, tt := range tests {
+		if got := isBinaryPaste([]rune(tt.input)); got != tt.want {
+			t.Errorf("isBinaryPaste(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestCheckpointRecovery(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Chdir(t.TempDir())
+	if err := os.WriteFile("main.go", []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	crashed := newChatModel()
+	conversation := []api.Message{
+		{Role: "user", Content: "What does main.go do?"},
+		{Role: "assistant", Content: "Nothing yet."},
+	}
+	crashed.messageManager.SetAPIMessages(conversation)
+	if err := crashed.fileContext.LoadFile("main.go"); err != nil {
+		t.Fatal(err)
+	}
+	crashed.saveCheckpoint()
+
+	cp, err := sessions.LoadCheckpoint()
+	if err != nil || cp == nil {
+		t.Fatalf("expected a checkpoint, got %v (%v)", cp, err)
+	}
+
+	recovered := newChatModel()
+	recovered.recoverCheckpoint(cp)
+	if got := recovered.messageManager.GetAPIMessages(); len(got) != 2 || got[1].Content != "Nothing yet." {
+		t.Errorf("expected the conversation back, got %+v", got)
+	}
+	if paths := recovered.fileContext.GetLoadedPaths(); len(paths) != 1 || paths[0] != "main.go" {
+		t.Errorf("expected main.go to be reloaded, got %v", paths)
+	}
+
+	// An empty conversation leaves nothing to recover
+	newChatModel().saveCheckpoint()
+	if cp, _ := sessions.LoadCheckpoint(); cp != nil {
+		t.Error("expected the checkpoint to be removed")
+	}
+}
+
+func TestGuardCmdCatchesPanics(t *testing.T) {
+	boom := func() tea.Msg { panic("boom") }
+	if crash, ok := guardCmd(boom)().(crashPanic); !ok || crash.value != "boom" || len(crash.stack) == 0 {
+		t.Fatalf("expected the panic as a message, got %#v", crash)
+	}
+
+	// Commands of a batch run separately, so they are guarded too
+	batch, ok := guardCmd(tea.Batch(boom, boom))().(tea.BatchMsg)
+	if !ok || len(batch) != 2 {
+		t.Fatalf("expected the batch to pass through, got %#v", batch)
+	}
+	if _, ok := batch[0]().(crashPanic); !ok {
+		t.Error("expected the commands of a batch to be guarded")
+	}
+
+	defer func() {
+		if _, ok := recover().(crashPanic); !ok {
+			t.Error("expected the guard to re-panic in the event loop")
+		}
+	}()
+	crashGuard{}.Update(crashPanic{value: "boom"})
+}
+
+// quitModel is a minimal program that runs until it is told to quit
+type quitModel struct{}
+
+func (quitModel) Init() tea.Cmd                       { return nil }
+func (quitModel) Update(tea.Msg) (tea.Model, tea.Cmd) { return quitModel{}, nil }
+func (quitModel) View() string                        { return "" }
+
+func TestQuitOnSignals(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("SIGHUP cannot be sent on Windows")
+	}
+
+	p := tea.NewProgram(quitModel{}, tea.WithInput(nil), tea.WithOutput(io.Discard))
+	stop := quitOnSignals(p)
+	done := make(chan error, 1)
+	go func() {
+		_, err := p.Run()
+		done <- err
+	}()
+
+	// Closing the terminal must quit the same way /quit does
+	self, _ := os.FindProcess(os.Getpid())
+	if err := self.Signal(syscall.SIGHUP); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("expected a clean exit, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		p.Kill()
+		t.Fatal("program did not quit on SIGHUP")
+	}
+	if !stop() {
+		t.Error("expected stop to report the signal")
+	}
+}
+
+func TestPromptQueue(t *testing.T) {
+	model := newChatModel()
+	model.isLoading = true // A response is streaming
+
+	if !model.turnBusy() {
+		t.Fatal("expected the turn to be busy while loading")
+	}
+	model.queuePrompt("next question")
+	if !strings.Contains(model.messageManager.Render(""), "next question") {
+		t.Error("expected the queued prompt to be shown")
+	}
+
+	// Up on the empty input takes it back for editing
+	if !model.editQueuedPrompt() || len(model.promptQueue) != 0 || model.textarea.Value() != "next question" {
+		t.Fatalf("expected the prompt back in the input, got queue %q and input %q", model.promptQueue, model.textarea.Value())
+	}
+	model.textarea.Reset()
+	model.queuePrompt("edited question")
+
+	// Nothing is sent while the turn lasts
+	if model.sendQueuedPrompt() != nil || len(model.promptQueue) != 1 {
+		t.Fatal("expected the prompt to wait for the turn to end")
+	}
+	model.isLoading = false
+	model.sendQueuedPrompt()
+	if len(model.promptQueue) != 0 {
+		t.Error("expected the prompt to be sent once the turn ended")
+	}
+	if strings.Contains(model.messageManager.Render(""), i18n.T("queue.label")) {
+		t.Error("expected the sent prompt to no longer be shown as queued")
+	}
+
+	// Cancelling a turn hands the queue back to the input
+	model.queuePrompt("first")
+	model.queuePrompt("second")
+	model.restoreQueuedPrompts()
+	if len(model.promptQueue) != 0 || model.textarea.Value() != "first\n\nsecond" {
+		t.Errorf("expected the queue in the input, got %q", model.textarea.Value())
+	}
+}
+
+func TestDuplicatePromptGuard(t *testing.T) {
+	model := newChatModel()
+	model.handlePrompt("explain this")
+	sent := len(model.messageManager.GetAPIMessages())
+
+	// Sending the same prompt again takes a second Enter
+	model.textarea.SetValue("explain this")
+	model.handlePrompt("explain this")
+	if len(model.messageManager.GetAPIMessages()) != sent || model.textarea.Value() != "explain this" {
+		t.Fatal("expected the repeated prompt to wait for confirmation")
+	}
+	model.handlePrompt("explain this")
+	if len(model.messageManager.GetAPIMessages()) != sent+1 || model.textarea.Value() != "" {
+		t.Fatal("expected the confirmed prompt to be sent")
+	}
+
+	// While it is answered, the same prompt is neither sent nor queued twice
+	model.isLoading = true
+	model.handlePrompt("explain this")
+	model.handlePrompt("and that")
+	model.handlePrompt("and that")
+	if len(model.promptQueue) != 1 || model.promptQueue[0] != "and that" {
+		t.Errorf("expected only the new prompt queued once, got %q", model.promptQueue)
+	}
+}
+
+func TestWarmUpDone(t *testing.T) {
+	model := newChatModel()
+	model.layoutManager.SetPlain(true)
+
+	model.handleWarmUpDone(warmUpDoneMsg{latency: 250 * time.Millisecond})
+	if header := model.layoutManager.RenderHeader(0, "input", nil, nil, ""); !strings.Contains(header, "api:250ms") {
+		t.Errorf("expected the latency in the header, got %q", header)
+	}
+
+	shown := len(model.messageManager.GetMessages())
+	model.handleWarmUpDone(warmUpDoneMsg{err: errors.New("dial tcp: refused")})
+	if header := model.layoutManager.RenderHeader(0, "input", nil, nil, ""); !strings.Contains(header, "api:offline") {
+		t.Errorf("expected offline in the header, got %q", header)
+	}
+	if len(model.messageManager.GetMessages()) != shown {
+		t.Error("a failed warm-up should not add a chat message")
+	}
+}
+
+func TestTabs(t *testing.T) {
+	tabs := newTabSet(newChatModel(), newChatModel)
+	tabs.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	first := tabs.current()
+
+	tabs.Update(tabMsg{id: first.id, msg: commands.TabMsg{Action: "new", Name: "review"}})
+	if len(tabs.tabs) != 2 || tabs.active != 1 || tabs.current().name != "review" {
+		t.Fatalf("expected the new tab to be active, got %d tabs, active %d", len(tabs.tabs), tabs.active)
+	}
+	if bar := tabs.renderBar(); !strings.Contains(bar, "1 chat") || !strings.Contains(bar, "2 review") {
+		t.Errorf("expected both tabs in the bar, got %q", bar)
+	}
+	second := tabs.current()
+
+	// Commands of a background tab still reach it
+	tabs.Update(tea.KeyMsg{Type: tea.KeyCtrlLeft})
+	if tabs.current() != first {
+		t.Fatal("expected Ctrl+Left to switch back to the first tab")
+	}
+	tabs.Update(tabMsg{id: second.id, msg: commands.TabMsg{Action: "rename", Name: "docs"}})
+	if second.name != "docs" || first.name != "chat" {
+		t.Errorf("expected only the background tab renamed, got %q and %q", first.name, second.name)
+	}
+
+	if _, cmd := tabs.Update(tabMsg{id: first.id, msg: tea.QuitMsg{}}); cmd == nil {
+		t.Error("expected a tab's quit to quit the program")
+	}
+
+	tabs.Update(tabMsg{id: second.id, msg: commands.TabMsg{Action: "close"}})
+	if len(tabs.tabs) != 1 || tabs.current() != first {
+		t.Fatalf("expected the closed tab removed, got %d tabs", len(tabs.tabs))
+	}
+	if _, cmd := tabs.Update(tabMsg{id: second.id, msg: warmUpDoneMsg{}}); cmd != nil {
+		t.Error("expected messages of a closed tab to be dropped")
+	}
+	tabs.Update(tabMsg{id: first.id, msg: commands.TabMsg{Action: "close"}})
+	if len(tabs.tabs) != 1 {
+		t.Error("expected the last tab to stay open")
+	}
+}
+
+func TestEmptyResponseReask(t *testing.T) {
+	model := newChatModel()
+	if model.aiOperations == nil {
+		t.Skip("AI operations not available")
+	}
+	model.submitPrompt("explain this")
+	shown := len(model.messageManager.GetMessages())
+
+	// The first empty response is asked again, with a notice
+	if model.reaskEmpty(false) == nil || !model.emptyRetried {
+		t.Fatal("expected the empty response to be asked again")
+	}
+	if len(model.messageManager.GetMessages()) != shown+1 {
+		t.Error("expected a notice that the request is sent again")
+	}
+
+	// A second one is reported instead of retried
+	model.isLoading = false
+	if model.reaskEmpty(false) != nil {
+		t.Fatal("expected no second retry")
+	}
+	if len(model.messageManager.GetMessages()) != shown+2 {
+		t.Error("expected an error for the persistent empty response")
+	}
+
+	// The next prompt gets its own retry
+	model.isLoading = false
+	model.submitPrompt("and that")
+	if model.emptyRetried {
+		t.Error("expected the retry to be reset for a new prompt")
+	}
+}
+
+func TestContextWarning(t *testing.T) {
+	model := newChatModel()
+	if warning := model.contextWarning(); warning != "" {
+		t.Errorf("expected no warning without files or history, got %q", warning)
+	}
+
+	model.fileContext.Files = []files.LoadedFile{
+		{Path: "/p/small.go", RelPath: "small.go", Language: "go", Content: "package small\n", Size: 14},
+		{Path: "/p/big.go", RelPath: "big.go", Language: "go", Content: strings.Repeat("x\n", 42000), Size: 84000},
+	}
+	model.aiOperations.SetAPIMessages([]api.Message{{Role: "user", Content: "hello"}})
+
+	warning := model.contextWarning()
+	if !strings.Contains(warning, "/unload big.go") || !strings.Contains(warning, "/compact") {
+		t.Errorf("expected /unload big.go and /compact suggested, got %q", warning)
+	}
+	if strings.Index(warning, "/unload") > strings.Index(warning, "/compact") {
+		t.Errorf("the files take most of the prompt, so /unload should come first: %q", warning)
+	}
+
+	model.layoutManager.SetPlain(true)
+	model.layoutManager.SetContextWarning(warning)
+	if header := model.layoutManager.RenderHeader(2, "input", nil, nil, ""); !strings.Contains(header, "warning: ") {
+		t.Errorf("expected the warning in the header, got %q", header)
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chat
+
+import (
+	"strings"
+
+	"github.com/antenore/deecli/internal/errlog"
+	"github.com/antenore/deecli/internal/i18n"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// overflowKeepTurns is how many user turns of the history a request sent
+// again after a context length error keeps
+const overflowKeepTurns = 2
+
+// retryOverflow handles the provider refusing a request as too long for the
+// model: the first time for a prompt, the request is sent again with a
+// quarter of its file context and only the recent turns of the history, and
+// what was left out is reported. It returns nil when err is another error or
+// nothing can be cut, and the error is then reported as usual.
+func (m *NewModel) retryOverflow(err error, suppressTools bool) tea.Cmd {
+	if errlog.Classify(err) != errlog.CategoryContext || m.overflowRetried || m.aiOperations == nil {
+		return nil
+	}
+
+	var cuts []string
+	contextPrompt := m.aiOperations.LastContextPrompt()
+	if contextPrompt != "" && len(m.fileContext.Files) > 0 {
+		shorter := m.fileContext.BuildContextPromptWithLimit(len(contextPrompt) / 4)
+		if len(shorter) < len(contextPrompt) {
+			cuts = append(cuts, i18n.T("overflow.files", len(contextPrompt)/1024, len(shorter)/1024))
+			contextPrompt = shorter
+		}
+	}
+
+	cmd, dropped := m.aiOperations.RetryShorter(contextPrompt, overflowKeepTurns)
+	if cmd == nil {
+		return nil
+	}
+	if dropped > 0 {
+		cuts = append(cuts, i18n.T("overflow.history", dropped))
+	}
+
+	m.overflowRetried = true
+	m.addMessage("system", i18n.T("overflow.retrying", strings.Join(cuts, ", ")))
+	if m.toolsManager != nil {
+		m.toolsManager.SetSuppressToolCalls(suppressTools)
+	}
+	m.apiCancel = m.aiOperations.GetAPICancel()
+	if loading := m.setLoading(true, i18n.T("loading.thinking")); loading != nil {
+		cmd = tea.Batch(cmd, loading)
+	}
+	return cmd
+}
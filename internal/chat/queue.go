@@ -0,0 +1,159 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chat
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/antenore/deecli/internal/i18n"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// turnBusy reports whether a response, or a tool chain it started, is still
+// in progress, so a new prompt has to wait for it
+func (m *NewModel) turnBusy() bool {
+	if m.isLoading || m.streamingManager.IsActive() {
+		return true
+	}
+	return m.toolsManager != nil && m.toolsManager.HasPendingChain()
+}
+
+// handlePrompt sends a prompt submitted with Enter, or queues it while a
+// turn is in progress. A prompt that is already being answered or queued is
+// ignored, so a double Enter or re-send does not ask twice; sending the
+// previous prompt again once its answer is in takes a second Enter.
+func (m *NewModel) handlePrompt(input string) tea.Cmd {
+	if m.turnBusy() {
+		m.textarea.Reset()
+		if input == m.lastPrompt || slices.Contains(m.promptQueue, input) {
+			m.addMessage("system", i18n.T("duplicate.ignored"))
+			return nil
+		}
+		// A prompt typed during a turn waits for the turn to end
+		m.queuePrompt(input)
+		return nil
+	}
+
+	if input == m.lastPrompt && input != m.confirmRepeat {
+		// Keep the prompt in the input for the confirming Enter
+		m.confirmRepeat = input
+		m.addMessage("system", i18n.T("duplicate.confirm"))
+		return nil
+	}
+	m.textarea.Reset()
+	return m.submitPrompt(input)
+}
+
+// queuePrompt queues input to be sent when the current turn ends and shows
+// it dimmed below the conversation
+func (m *NewModel) queuePrompt(input string) {
+	m.promptQueue = append(m.promptQueue, input)
+	m.showQueue()
+}
+
+// editQueuedPrompt moves the last queued prompt back to the input, where it
+// can be changed and sent again or cleared to cancel it. It reports whether
+// there was one.
+func (m *NewModel) editQueuedPrompt() bool {
+	if len(m.promptQueue) == 0 {
+		return false
+	}
+	last := len(m.promptQueue) - 1
+	m.textarea.SetValue(m.promptQueue[last])
+	m.promptQueue = m.promptQueue[:last]
+	m.showQueue()
+	return true
+}
+
+// sendQueuedPrompt sends the oldest queued prompt once the turn has ended
+func (m *NewModel) sendQueuedPrompt() tea.Cmd {
+	if len(m.promptQueue) == 0 || m.turnBusy() {
+		return nil
+	}
+	input := m.promptQueue[0]
+	m.promptQueue = m.promptQueue[1:]
+	m.showQueue()
+	return m.submitPrompt(input)
+}
+
+// restoreQueuedPrompts moves all queued prompts back to the input when the
+// turn is cancelled, so they are not sent without the user seeing them again
+func (m *NewModel) restoreQueuedPrompts() {
+	if len(m.promptQueue) == 0 {
+		return
+	}
+	prompts := m.promptQueue
+	if current := m.textarea.Value(); current != "" {
+		prompts = append(prompts, current)
+	}
+	m.textarea.SetValue(strings.Join(prompts, "\n\n"))
+	m.promptQueue = nil
+	m.showQueue()
+	m.addMessage("system", i18n.T("queue.restored"))
+}
+
+// showQueue updates the queued prompts shown in the viewport
+func (m *NewModel) showQueue() {
+	m.messageManager.SetQueued(m.promptQueue)
+	m.refreshViewport()
+}
+
+// submitPrompt sends input to the model with the loaded files as context
+func (m *NewModel) submitPrompt(input string) tea.Cmd {
+	m.lastPrompt = input
+	m.confirmRepeat = ""
+	m.emptyRetried = false
+	m.overflowRetried = false
+	m.addMessage("user", input)
+
+	if m.apiClient == nil {
+		m.addMessage("system", i18n.T("status.api_key_missing"))
+		return nil
+	}
+
+	contextPrompt := m.buildContextPrompt(input)
+
+	loading := m.setLoading(true, i18n.T("loading.thinking"))
+	m.refreshViewport()
+	return tea.Batch(loading, m.callAPI(contextPrompt, input))
+}
+
+// buildContextPrompt returns the loaded files as context for input,
+// truncated to fit max_context_size
+func (m *NewModel) buildContextPrompt(input string) string {
+	contextPrompt := ""
+	if len(m.fileContext.Files) > 0 {
+		// Get config for smart context management
+		maxContextSize := 100000 // Default
+		if m.configManager != nil {
+			maxContextSize = m.configManager.GetMaxContextSize()
+		}
+
+		// Estimate if we need truncation (leave buffer for user input and API overhead)
+		inputSize := len(input)
+		bufferSize := inputSize + 10000 // Reserve 10KB for API overhead and user input
+		contextBudget := maxContextSize - bufferSize
+
+		if contextBudget > 5000 { // Only use truncation if we have reasonable budget
+			contextPrompt = m.fileContext.BuildContextPromptWithLimit(contextBudget)
+		} else {
+			// Very tight budget, use minimal context
+			contextPrompt = fmt.Sprintf("Files loaded: %d (content truncated due to size limits)\n",
+				len(m.fileContext.Files))
+		}
+	}
+	return contextPrompt
+}
@@ -0,0 +1,117 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chat
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/antenore/deecli/internal/errlog"
+	"github.com/antenore/deecli/internal/sessions"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// checkpointInterval is how often the conversation is checkpointed for
+// recovery after a crash
+const checkpointInterval = 30 * time.Second
+
+// checkpointMsg asks for the conversation to be checkpointed
+type checkpointMsg struct{}
+
+// checkpointTick schedules the next checkpoint
+func checkpointTick() tea.Cmd {
+	return tea.Tick(checkpointInterval, func(time.Time) tea.Msg {
+		return checkpointMsg{}
+	})
+}
+
+// saveCheckpoint writes the conversation and the loaded file list to the
+// checkpoint file and reports whether it did. A failure is recorded in the
+// error log once, without interrupting the chat.
+func (m *NewModel) saveCheckpoint() bool {
+	apiMessages := m.messageManager.GetAPIMessages()
+	paths := m.fileContext.GetLoadedPaths()
+	if len(apiMessages) == 0 && len(paths) == 0 {
+		sessions.RemoveCheckpoint() // Nothing worth recovering
+		return false
+	}
+
+	cp := sessions.Checkpoint{SavedAt: time.Now(), APIMessages: apiMessages, Files: paths}
+	if m.currentSession != nil {
+		cp.SessionID = m.currentSession.ID
+	}
+	err := sessions.SaveCheckpoint(cp)
+	if err != nil && !m.checkpointFailed {
+		m.errorLog.Record(errlog.CategoryGeneral, "Failed to save the recovery checkpoint", err)
+	}
+	m.checkpointFailed = err != nil
+	return err == nil
+}
+
+// offerRecovery asks whether to restore the conversation checkpointed by a
+// run that crashed or was killed, before the UI takes over the terminal. It
+// reports whether the conversation was restored.
+func offerRecovery(m *NewModel) bool {
+	cp, err := sessions.LoadCheckpoint()
+	if err != nil || cp == nil {
+		return false
+	}
+
+	fmt.Printf("Recover previous session? %d messages and %d files, interrupted after %s [Y/n] ",
+		len(cp.APIMessages), len(cp.Files), cp.SavedAt.Local().Format("2006-01-02 15:04"))
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	if answer != "" && answer != "y" && answer != "yes" {
+		sessions.RemoveCheckpoint()
+		return false
+	}
+
+	m.recoverCheckpoint(cp)
+	return true
+}
+
+// recoverCheckpoint restores the conversation and reloads the files of a checkpoint
+func (m *NewModel) recoverCheckpoint(cp *sessions.Checkpoint) {
+	if m.sessionManager != nil && cp.SessionID != 0 && (m.currentSession == nil || m.currentSession.ID != cp.SessionID) {
+		if session, err := m.sessionManager.GetSession(cp.SessionID); err == nil {
+			m.switchSession(session)
+		}
+	}
+
+	var display []string
+	for _, msg := range cp.APIMessages {
+		if (msg.Role == "user" || msg.Role == "assistant") && msg.Content != "" {
+			display = append(display, m.renderer.FormatMessage(msg.Role, msg.Content))
+		}
+	}
+	m.messageManager.SetMessages(display, nil)
+	m.messageManager.SetAPIMessages(cp.APIMessages)
+
+	var missing []string
+	for _, path := range cp.Files {
+		if err := m.fileContext.LoadFile(path); err != nil {
+			missing = append(missing, path)
+		}
+	}
+
+	notice := fmt.Sprintf("♻️ Recovered %d messages and %d files from the interrupted session",
+		len(cp.APIMessages), len(cp.Files)-len(missing))
+	if len(missing) > 0 {
+		notice += "\n   Could not reload: " + strings.Join(missing, ", ")
+	}
+	m.messageManager.AddDisplayMessage(m.renderer.FormatMessage("system", notice))
+}
@@ -0,0 +1,57 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chat
+
+import (
+	"github.com/antenore/deecli/internal/errlog"
+)
+
+// handleSidebarKey handles the keys that arrange the focused files sidebar:
+// s cycles the order, g toggles grouping by directory, [ and ] select a
+// directory and space collapses or expands it. The order and grouping are
+// saved to the config.
+func (m *NewModel) handleSidebarKey(key string) {
+	switch key {
+	case "s", "g":
+		if key == "s" {
+			m.sidebar.CycleSort()
+		} else {
+			m.sidebar.ToggleGroup()
+		}
+		if m.configManager != nil {
+			if err := m.configManager.SetSidebarView(m.sidebar.Sort, m.sidebar.Group); err != nil {
+				m.reportError(errlog.CategoryConfig, "Failed to save the sidebar order", err)
+			}
+		}
+	case "[", "]":
+		if !m.sidebar.SelectDirectory(m.fileContext, key == "]") {
+			return
+		}
+	case " ":
+		if !m.sidebar.ToggleDirectory(m.fileContext) {
+			return
+		}
+	}
+
+	m.sidebarViewport.SetContent(m.renderFilesSidebar())
+
+	// Keep the selected directory header in view
+	if line := m.sidebar.SelectedLine(); line >= 0 {
+		if line < m.sidebarViewport.YOffset {
+			m.sidebarViewport.SetYOffset(line)
+		} else if line >= m.sidebarViewport.YOffset+m.sidebarViewport.Height {
+			m.sidebarViewport.SetYOffset(line - m.sidebarViewport.Height + 1)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chat
+
+import (
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// quitSignals end the chat like /quit: SIGTERM is sent by kill and service
+// managers such as systemd, SIGHUP when the terminal window is closed.
+// Bubble Tea handles SIGTERM itself but not SIGHUP, which would otherwise
+// terminate the process before the shutdown hooks save anything.
+var quitSignals = []os.Signal{syscall.SIGTERM, syscall.SIGHUP}
+
+// quitOnSignals asks p to quit gracefully when one of quitSignals arrives.
+// The returned stop function stops listening and reports whether the
+// program was quit by a signal.
+func quitOnSignals(p *tea.Program) (stop func() bool) {
+	var signaled atomic.Bool
+	sigs := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(sigs, quitSignals...)
+
+	go func() {
+		for {
+			select {
+			case <-sigs:
+				signaled.Store(true)
+				p.Quit()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() bool {
+		signal.Stop(sigs)
+		close(done)
+		return signaled.Load()
+	}
+}
@@ -17,67 +17,87 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 	"unicode"
 
 	"github.com/antenore/deecli/internal/ai"
-	"github.com/antenore/deecli/internal/api"
 	"github.com/antenore/deecli/internal/chat/ui"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
-// Manager handles streaming operations and state
+// frameInterval is how often a growing response is redrawn. Chunks arriving
+// in between are drawn together, so fast responses do not redraw the
+// viewport for every token.
+const frameInterval = 50 * time.Millisecond
+
+// FrameMsg asks for the chunks received since the last frame to be drawn
+type FrameMsg struct {
+	stream *ai.Stream
+}
+
+// Manager turns stream events into display state for the chat view
 type Manager struct {
-	streamReader         api.StreamReader
-	streamContent        string
-	isActive             bool
-	messageAdded         bool // Track if assistant message has been added yet
+	stream         *ai.Stream // Stream currently being displayed
+	streamContent  string
+	isActive       bool
+	messageAdded   bool // Track if assistant message has been added yet
+	dirty          bool // Content changed since it was last drawn
+	frameScheduled bool // A FrameMsg for the current stream is on its way
 }
 
 // NewManager creates a new streaming manager
 func NewManager() *Manager {
-	return &Manager{
-		streamContent: "",
-		isActive:      false,
-		messageAdded:  false,
-	}
+	return &Manager{}
 }
 
-// StartStream handles the start of a new stream
-func (sm *Manager) StartStream(msg ai.StreamStartedMsg, renderer interface{}, messages *[]string) tea.Cmd {
-	sm.streamReader = msg.Stream
+// StartStream begins displaying the stream announced by a StreamStarted event
+func (sm *Manager) StartStream(msg ai.StreamEventMsg) tea.Cmd {
+	if sm.stream != nil {
+		sm.stream.Close()
+	}
+	sm.stream = msg.Stream
 	sm.streamContent = ""
 	sm.isActive = true
 	sm.messageAdded = false
+	sm.dirty = false
+	sm.frameScheduled = false
 
 	// Don't add assistant message yet - wait for meaningful content
 	// This prevents empty assistant messages from showing
+	return sm.stream.Next()
+}
 
-	// Start reading the first chunk
-	return ai.ReadNextChunk(msg.Stream, sm.streamContent)
+// IsCurrent reports whether an event should be handled: it starts a stream,
+// failed before one started, or belongs to the stream being displayed.
+// Events from a stream that was cancelled or replaced are ignored.
+func (sm *Manager) IsCurrent(msg ai.StreamEventMsg) bool {
+	return msg.Kind == ai.StreamStarted || msg.Stream == nil || msg.Stream == sm.stream
 }
 
-// HandleChunk processes a streaming chunk
-func (sm *Manager) HandleChunk(msg ai.StreamChunkMsg, spinner *ui.Spinner, isLoading *bool, setLoadingFn func(bool, string) tea.Cmd) (tea.Cmd, []tea.Cmd) {
+// HandleChunk processes a StreamDelta event and returns the commands to run:
+// reading the next event, scheduling the next frame, plus stopping the
+// spinner once text arrives
+func (sm *Manager) HandleChunk(msg ai.StreamEventMsg, spinner *ui.Spinner, isLoading *bool, setLoadingFn func(bool, string) tea.Cmd) []tea.Cmd {
 	var cmds []tea.Cmd
 
-	if msg.Err != nil {
-		return sm.completeStream(sm.streamContent, msg.Err), nil
+	// Tool call markup is handled by the tool system, never displayed, but the
+	// stream must still be read to the end
+	filteredContent, _ := sm.filterToolCallMarkers(msg.Content)
+	sm.streamContent += filteredContent
+	if filteredContent != "" {
+		sm.dirty = true
+		if !sm.frameScheduled && sm.stream != nil {
+			sm.frameScheduled = true
+			stream := sm.stream
+			cmds = append(cmds, tea.Tick(frameInterval, func(time.Time) tea.Msg {
+				return FrameMsg{stream: stream}
+			}))
+		}
 	}
-
-	// Filter out tool call markers and detect tool calls
-	filteredContent, toolCallDetected := sm.filterToolCallMarkers(msg.Content)
-	
-	// If we detected a tool call pattern, we should not display this content
-	// and instead let the tool execution system handle it
-	if toolCallDetected {
-		// Don't append the raw tool call content to display
-		// Return early to prevent showing tool call markers
-		return nil, cmds
+	if spinner != nil {
+		spinner.AddReceived(len(filteredContent))
 	}
 
-	// Append filtered chunk content
-	sm.streamContent += filteredContent
-
 	// Stop spinner only when we have accumulated meaningful content
 	// This ensures the spinner stays visible during the "thinking" phase
 	// and initial token streaming before substantial text appears
@@ -87,66 +107,81 @@ func (sm *Manager) HandleChunk(msg ai.StreamChunkMsg, spinner *ui.Spinner, isLoa
 		}
 	}
 
-	// Continue reading next chunk
-	if sm.streamReader != nil {
-		nextCmd := ai.ReadNextChunk(sm.streamReader, sm.streamContent)
-		if len(cmds) > 0 {
-			cmds = append(cmds, nextCmd)
-			return nil, cmds
-		}
-		return nextCmd, cmds
+	if sm.stream != nil {
+		cmds = append(cmds, sm.stream.Next())
 	}
-
-	return nil, cmds
+	return cmds
 }
 
-// CompleteStream handles stream completion
-func (sm *Manager) CompleteStream(msg ai.StreamCompleteMsg) tea.Cmd {
-	return sm.completeStream(msg.TotalContent, msg.Err)
-}
+// CompleteStream handles a StreamDone or StreamFailed event
+func (sm *Manager) CompleteStream(msg ai.StreamEventMsg) tea.Cmd {
+	var err error
+	if msg.Kind == ai.StreamFailed {
+		err = msg.Err
+	}
 
-// completeStream internal completion handler
-func (sm *Manager) completeStream(content string, err error) tea.Cmd {
 	sm.isActive = false
-	sm.streamReader = nil
-	// Keep streamContent for final message processing
-	finalContent := sm.streamContent
+	sm.stream = nil
+	// The displayed text has tool call markup filtered out
+	content := sm.streamContent
 	sm.streamContent = ""
+	messageAdded := sm.messageAdded
 
-	// Return completion message with final content
 	return func() tea.Msg {
 		return StreamCompleteInternalMsg{
 			Content:      content,
-			FinalContent: finalContent, // Include accumulated content for proper message sync
-			MessageAdded: sm.messageAdded, // Track if message was added during streaming
+			FinalContent: content,
+			MessageAdded: messageAdded, // Track if message was added during streaming
 			Err:          err,
 		}
 	}
 }
 
-// UpdateDisplay updates the streaming display with accumulated content
-func (sm *Manager) UpdateDisplay(content string, renderer interface{}, messages *[]string, viewport ViewportInterface) {
+// Conversation is the message store the streaming display writes to
+type Conversation interface {
+	AddDisplayMessage(formatted string)
+	ReplaceLastMessage(formatted string)
+	RemoveLastMessage()
+	Render(trailer string) string
+}
+
+// UpdateDisplay shows the accumulated content as the last assistant message
+func (sm *Manager) UpdateDisplay(content string, renderer interface{ FormatMessage(string, string) string }, conv Conversation, viewport ViewportInterface) {
 	// Add assistant message only when we have meaningful content for the first time
 	if !sm.messageAdded && sm.hasMeaningfulContent() {
-		if r, ok := renderer.(interface{ FormatMessage(string, string) string }); ok {
-			*messages = append(*messages, r.FormatMessage("assistant", content))
-			sm.messageAdded = true
-		}
-	} else if sm.messageAdded && len(*messages) > 0 {
+		conv.AddDisplayMessage(renderer.FormatMessage("assistant", content))
+		sm.messageAdded = true
+	} else if sm.messageAdded {
 		// Update the last message (which should be our streaming assistant message)
-		lastIdx := len(*messages) - 1
-		if r, ok := renderer.(interface{ FormatMessage(string, string) string }); ok {
-			(*messages)[lastIdx] = r.FormatMessage("assistant", content)
-		}
+		conv.ReplaceLastMessage(renderer.FormatMessage("assistant", content))
 	}
 
-	// Update viewport content only if we have messages
-	if len(*messages) > 0 {
-		viewport.SetContent(strings.Join(*messages, "\n\n"))
+	if sm.messageAdded {
+		viewport.SetContent(conv.Render(""))
 		_ = viewport.GotoBottom() // Ignore return value
 	}
 }
 
+// HandleFrame draws the chunks received since the last frame. Frames of a
+// stream that was cancelled or replaced are ignored.
+func (sm *Manager) HandleFrame(msg FrameMsg, renderer interface{ FormatMessage(string, string) string }, conv Conversation, viewport ViewportInterface) {
+	if msg.stream != sm.stream {
+		return
+	}
+	sm.frameScheduled = false
+	sm.Flush(renderer, conv, viewport)
+}
+
+// Flush draws content that has not been drawn yet, such as the last chunks
+// of a stream that ends before the next frame
+func (sm *Manager) Flush(renderer interface{ FormatMessage(string, string) string }, conv Conversation, viewport ViewportInterface) {
+	if !sm.dirty {
+		return
+	}
+	sm.dirty = false
+	sm.UpdateDisplay(sm.streamContent, renderer, conv, viewport)
+}
+
 // ViewportInterface defines required viewport methods
 type ViewportInterface interface {
 	SetContent(string)
@@ -158,29 +193,6 @@ func (sm *Manager) GetStreamContent() string {
 	return sm.streamContent
 }
 
-// AddContent adds content to the stream (for direct manipulation)
-func (sm *Manager) AddContent(content string) {
-	sm.streamContent += content
-}
-
-// GetStreamReader returns the current stream reader
-func (sm *Manager) GetStreamReader() interface{} {
-	return sm.streamReader
-}
-
-// GetStream returns the current stream reader as api.StreamReader
-func (sm *Manager) GetStream() api.StreamReader {
-	if reader, ok := sm.streamReader.(api.StreamReader); ok {
-		return reader
-	}
-	return nil
-}
-
-// AppendContent appends content to the stream
-func (sm *Manager) AppendContent(content string) {
-	sm.streamContent += content
-}
-
 // IsActive returns whether streaming is currently active
 func (sm *Manager) IsActive() bool {
 	return sm.isActive
@@ -191,10 +203,38 @@ func (sm *Manager) Reset() {
 	sm.streamContent = ""
 	sm.isActive = false
 	sm.messageAdded = false
-	if sm.streamReader != nil {
-		sm.streamReader.Close()
-		sm.streamReader = nil
+	sm.dirty = false
+	sm.frameScheduled = false
+	if sm.stream != nil {
+		sm.stream.Close()
+		sm.stream = nil
+	}
+}
+
+// Discard stops displaying the stream and removes the partial response it
+// showed, so the stream can be replaced by a retry
+func (sm *Manager) Discard(conv Conversation) {
+	if sm.messageAdded {
+		conv.RemoveLastMessage()
+	}
+	sm.Reset()
+}
+
+// Keep stops a stream the user cancelled and keeps its partial response on
+// screen, ending with marker. It returns that response, or "" when too
+// little had arrived to be worth keeping.
+func (sm *Manager) Keep(marker string, renderer interface{ FormatMessage(string, string) string }, conv Conversation) string {
+	content := ""
+	if sm.isActive && sm.hasMeaningfulContent() {
+		content = strings.TrimSpace(sm.streamContent) + "\n\n" + marker
+		if sm.messageAdded {
+			conv.ReplaceLastMessage(renderer.FormatMessage("assistant", content))
+		} else {
+			conv.AddDisplayMessage(renderer.FormatMessage("assistant", content))
+		}
 	}
+	sm.Reset()
+	return content
 }
 
 // hasMeaningfulContent checks if the content has substantial text (not just whitespace/tokens)
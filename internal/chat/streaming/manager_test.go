@@ -0,0 +1,191 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streaming
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/antenore/deecli/internal/ai"
+	"github.com/antenore/deecli/internal/chat/ui"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type benchViewport struct{}
+
+func (benchViewport) SetContent(string)    {}
+func (benchViewport) GotoBottom() []string { return nil }
+
+// benchConversation is a minimal Conversation backed by a transcript
+type benchConversation struct {
+	messages   []string
+	transcript ui.Transcript
+}
+
+func (c *benchConversation) AddDisplayMessage(formatted string) {
+	c.messages = append(c.messages, formatted)
+}
+
+func (c *benchConversation) ReplaceLastMessage(formatted string) {
+	c.messages[len(c.messages)-1] = formatted
+}
+
+func (c *benchConversation) RemoveLastMessage() {
+	c.messages = c.messages[:len(c.messages)-1]
+}
+
+func (c *benchConversation) Render(trailer string) string {
+	return c.transcript.Join(c.messages, trailer)
+}
+
+func TestManager_HandleChunk(t *testing.T) {
+	sm := NewManager()
+	loading := true
+	stopped := false
+	setLoading := func(on bool, _ string) tea.Cmd {
+		stopped = !on
+		return nil
+	}
+
+	sm.HandleChunk(ai.StreamEventMsg{Kind: ai.StreamDelta, Content: "Hello there, "}, nil, &loading, setLoading)
+	sm.HandleChunk(ai.StreamEventMsg{Kind: ai.StreamDelta, Content: "<｜tool▁calls▁begin｜>"}, nil, &loading, setLoading)
+	sm.HandleChunk(ai.StreamEventMsg{Kind: ai.StreamDelta, Content: "world"}, nil, &loading, setLoading)
+
+	if got := sm.GetStreamContent(); got != "Hello there, world" {
+		t.Errorf("expected tool markup to be filtered, got %q", got)
+	}
+	if !stopped {
+		t.Error("expected the spinner to stop once meaningful text arrived")
+	}
+
+	cmd := sm.CompleteStream(ai.StreamEventMsg{Kind: ai.StreamDone})
+	done, ok := cmd().(StreamCompleteInternalMsg)
+	if !ok || done.Content != "Hello there, world" || done.Err != nil {
+		t.Errorf("unexpected completion %+v", done)
+	}
+}
+
+func TestManager_CoalescesChunksIntoFrames(t *testing.T) {
+	sm := NewManager()
+	stream := &ai.Stream{}
+	sm.StartStream(ai.StreamEventMsg{Kind: ai.StreamStarted, Stream: stream})
+
+	renderer := ui.NewRenderer(nil)
+	conv := &benchConversation{}
+	loading := false
+	setLoading := func(bool, string) tea.Cmd { return nil }
+
+	// Only the first chunk of a frame schedules a redraw
+	first := sm.HandleChunk(ai.StreamEventMsg{Kind: ai.StreamDelta, Stream: stream, Content: "Hello there, "}, nil, &loading, setLoading)
+	second := sm.HandleChunk(ai.StreamEventMsg{Kind: ai.StreamDelta, Stream: stream, Content: "world"}, nil, &loading, setLoading)
+	if len(first) != 2 || len(second) != 1 {
+		t.Fatalf("expected one frame for both chunks, got %d and %d commands", len(first), len(second))
+	}
+	if len(conv.messages) != 0 {
+		t.Fatal("chunks should not be drawn before the frame")
+	}
+
+	// A frame of another stream draws nothing
+	sm.HandleFrame(FrameMsg{stream: &ai.Stream{}}, renderer, conv, benchViewport{})
+	if len(conv.messages) != 0 {
+		t.Fatal("a stale frame should be ignored")
+	}
+
+	sm.HandleFrame(FrameMsg{stream: stream}, renderer, conv, benchViewport{})
+	if len(conv.messages) != 1 || !strings.Contains(conv.messages[0], "Hello there, world") {
+		t.Fatalf("expected the frame to draw both chunks, got %q", conv.messages)
+	}
+
+	// The next chunk schedules a new frame
+	if cmds := sm.HandleChunk(ai.StreamEventMsg{Kind: ai.StreamDelta, Stream: stream, Content: "!"}, nil, &loading, setLoading); len(cmds) != 2 {
+		t.Errorf("expected a new frame after the last one was drawn, got %d commands", len(cmds))
+	}
+	sm.Flush(renderer, conv, benchViewport{})
+	if !strings.Contains(conv.messages[0], "world!") {
+		t.Errorf("expected Flush to draw pending text, got %q", conv.messages[0])
+	}
+}
+
+func TestManager_IsCurrent(t *testing.T) {
+	sm := NewManager()
+	old := &ai.Stream{}
+
+	if !sm.IsCurrent(ai.StreamEventMsg{Kind: ai.StreamStarted, Stream: old}) {
+		t.Error("a new stream should always be handled")
+	}
+	if !sm.IsCurrent(ai.StreamEventMsg{Kind: ai.StreamFailed}) {
+		t.Error("a failure before any stream started should be handled")
+	}
+	if sm.IsCurrent(ai.StreamEventMsg{Kind: ai.StreamDelta, Stream: old}) {
+		t.Error("events from a stream that is not displayed should be ignored")
+	}
+}
+
+func TestManager_Keep(t *testing.T) {
+	renderer := ui.NewRenderer(nil)
+	loading := false
+	setLoading := func(bool, string) tea.Cmd { return nil }
+
+	// A partial answer already on screen is marked and kept
+	sm := NewManager()
+	sm.isActive = true
+	conv := &benchConversation{}
+	sm.HandleChunk(ai.StreamEventMsg{Kind: ai.StreamDelta, Content: "The first step is "}, nil, &loading, setLoading)
+	sm.Flush(renderer, conv, benchViewport{})
+	sm.HandleChunk(ai.StreamEventMsg{Kind: ai.StreamDelta, Content: "to"}, nil, &loading, setLoading)
+
+	kept := sm.Keep("(truncated)", renderer, conv)
+	if kept != "The first step is to\n\n(truncated)" {
+		t.Errorf("unexpected partial answer %q", kept)
+	}
+	if len(conv.messages) != 1 || !strings.Contains(conv.messages[0], "(truncated)") {
+		t.Errorf("expected the marked answer on screen, got %q", conv.messages)
+	}
+	if sm.IsActive() || sm.GetStreamContent() != "" {
+		t.Error("expected the stream to be reset")
+	}
+
+	// Too little to keep is dropped, as is a cancelled request that was not streamed
+	sm.isActive = true
+	sm.HandleChunk(ai.StreamEventMsg{Kind: ai.StreamDelta, Content: "Th"}, nil, &loading, setLoading)
+	if kept := sm.Keep("(truncated)", renderer, conv); kept != "" || len(conv.messages) != 1 {
+		t.Errorf("expected nothing kept, got %q and %d messages", kept, len(conv.messages))
+	}
+	if kept := NewManager().Keep("(truncated)", renderer, conv); kept != "" {
+		t.Errorf("expected nothing kept without a stream, got %q", kept)
+	}
+}
+
+func BenchmarkManager_HandleChunk(b *testing.B) {
+	sm := NewManager()
+	renderer := ui.NewRenderer(nil)
+	renderer.SetViewportWidth(120, false)
+	conv := &benchConversation{messages: make([]string, 200)}
+	for i := range conv.messages {
+		conv.messages[i] = "an earlier formatted message"
+	}
+	loading := false
+	setLoading := func(bool, string) tea.Cmd { return nil }
+	chunk := ai.StreamEventMsg{Kind: ai.StreamDelta, Content: "some streamed tokens "}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if i%500 == 0 {
+			// Keep the accumulated content at a realistic size
+			sm.streamContent = ""
+		}
+		sm.HandleChunk(chunk, nil, &loading, setLoading)
+		sm.UpdateDisplay(sm.streamContent, renderer, conv, benchViewport{})
+	}
+}
@@ -0,0 +1,341 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chat
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/antenore/deecli/internal/chat/commands"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// chatTab is one chat of the tab set, with its own conversation, file
+// context and loading state
+type chatTab struct {
+	id     int
+	name   string
+	model  *NewModel
+	unread bool // Finished a request while in the background
+}
+
+// tabMsg is a message produced by a command of the tab with the given id,
+// so that it reaches that tab even when another one is active
+type tabMsg struct {
+	id  int
+	msg tea.Msg
+}
+
+// teaPkgPath is the package of Bubble Tea's own messages, such as those of
+// tea.Quit or tea.ExecProcess, which the program rather than a tab handles
+var teaPkgPath = reflect.TypeOf(tea.QuitMsg{}).PkgPath()
+
+// tabSet runs several chats in one TUI. Keys go to the active tab; the
+// results of each tab's commands go back to that tab, so a long request
+// keeps running while another tab is in use.
+type tabSet struct {
+	tabs     []*chatTab
+	active   int
+	nextID   int
+	width    int
+	height   int
+	newModel func() *NewModel // Creates the chat of a new tab; nil disables /tab new
+}
+
+// newTabSet returns a tab set holding first as its only tab
+func newTabSet(first *NewModel, newModel func() *NewModel) *tabSet {
+	t := &tabSet{newModel: newModel}
+	t.add(first, "chat")
+	return t
+}
+
+// add appends a tab showing m and returns it
+func (t *tabSet) add(m *NewModel, name string) *chatTab {
+	t.nextID++
+	if name == "" {
+		name = fmt.Sprintf("chat %d", t.nextID)
+	}
+	tab := &chatTab{id: t.nextID, name: name, model: m}
+	t.tabs = append(t.tabs, tab)
+	return tab
+}
+
+// current returns the active tab
+func (t *tabSet) current() *chatTab {
+	return t.tabs[t.active]
+}
+
+// find returns the tab with the given id, or nil if it was closed
+func (t *tabSet) find(id int) *chatTab {
+	for _, tab := range t.tabs {
+		if tab.id == id {
+			return tab
+		}
+	}
+	return nil
+}
+
+// tag makes the message of cmd, and those of the commands it batches,
+// reach the tab with the given id
+func tag(id int, cmd tea.Cmd) tea.Cmd {
+	if cmd == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		return tabMsg{id: id, msg: cmd()}
+	}
+}
+
+func (t *tabSet) Init() tea.Cmd {
+	tab := t.current()
+	return tag(tab.id, tab.model.Init())
+}
+
+func (t *tabSet) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tabMsg:
+		return t, t.route(msg)
+
+	case tea.WindowSizeMsg:
+		t.width, t.height = msg.Width, msg.Height
+		return t, t.resize()
+
+	case tea.FocusMsg, tea.BlurMsg:
+		var cmds []tea.Cmd
+		for _, tab := range t.tabs {
+			cmds = append(cmds, t.update(tab, msg))
+		}
+		return t, tea.Batch(cmds...)
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+left":
+			t.switchTo((t.active + len(t.tabs) - 1) % len(t.tabs))
+			return t, nil
+		case "ctrl+right":
+			t.switchTo((t.active + 1) % len(t.tabs))
+			return t, nil
+		}
+	}
+	return t, t.update(t.current(), msg)
+}
+
+// route delivers a message produced by a tab's command
+func (t *tabSet) route(msg tabMsg) tea.Cmd {
+	switch inner := msg.msg.(type) {
+	case nil:
+		return nil
+	case tea.BatchMsg:
+		cmds := make([]tea.Cmd, len(inner))
+		for i, cmd := range inner {
+			cmds[i] = tag(msg.id, cmd)
+		}
+		return tea.Batch(cmds...)
+	case tea.QuitMsg:
+		return tea.Quit
+	case commands.TabMsg:
+		if tab := t.find(msg.id); tab != nil {
+			return t.handleTabMsg(tab, inner)
+		}
+		return nil
+	}
+
+	if reflect.TypeOf(msg.msg).PkgPath() == teaPkgPath {
+		inner := msg.msg
+		return func() tea.Msg { return inner }
+	}
+	tab := t.find(msg.id)
+	if tab == nil {
+		return nil // The tab was closed while its command ran
+	}
+	return t.update(tab, msg.msg)
+}
+
+// update passes msg to a tab, noting when a background tab finishes its work
+func (t *tabSet) update(tab *chatTab, msg tea.Msg) tea.Cmd {
+	wasBusy := tab.model.turnBusy()
+	_, cmd := tab.model.Update(msg)
+	if tab != t.current() && wasBusy && !tab.model.turnBusy() {
+		tab.unread = true
+	}
+	return tag(tab.id, cmd)
+}
+
+// resize gives every tab the window size, less the tab bar when it is shown
+func (t *tabSet) resize() tea.Cmd {
+	if t.width == 0 && t.height == 0 {
+		return nil // The window size is not known yet
+	}
+	height := t.height
+	if len(t.tabs) > 1 {
+		height--
+	}
+	var cmds []tea.Cmd
+	for _, tab := range t.tabs {
+		cmds = append(cmds, t.update(tab, tea.WindowSizeMsg{Width: t.width, Height: height}))
+	}
+	return tea.Batch(cmds...)
+}
+
+// switchTo makes the tab at index active
+func (t *tabSet) switchTo(index int) {
+	t.active = index
+	t.current().unread = false
+}
+
+// handleTabMsg carries out a /tab command sent from tab
+func (t *tabSet) handleTabMsg(tab *chatTab, msg commands.TabMsg) tea.Cmd {
+	switch msg.Action {
+	case "new":
+		if t.newModel == nil {
+			tab.model.addMessage("system", "❌ Tabs are not available in this session")
+			return nil
+		}
+		model := t.newModel()
+		if model.redactErr != nil {
+			tab.model.addMessage("system", fmt.Sprintf("❌ Cannot open a tab: %v", model.redactErr))
+			return nil
+		}
+		opened := t.add(model, msg.Name)
+		t.switchTo(len(t.tabs) - 1)
+		opened.model.addMessage("system", fmt.Sprintf("🗂️ Opened tab %d: %s. Ctrl+Left/Right switch tabs; /tab close closes this one.", len(t.tabs), opened.name))
+		return tea.Batch(t.resize(), tag(opened.id, opened.model.tabInit()))
+
+	case "switch":
+		if msg.Index >= len(t.tabs) {
+			tab.model.addMessage("system", fmt.Sprintf("❌ No tab %d; there are %d", msg.Index+1, len(t.tabs)))
+			return nil
+		}
+		t.switchTo(msg.Index)
+		return nil
+
+	case "rename":
+		tab.name = msg.Name
+		return nil
+
+	case "close":
+		if len(t.tabs) == 1 {
+			tab.model.addMessage("system", "❌ This is the only tab; use /quit to leave")
+			return nil
+		}
+		return t.close(tab)
+
+	default:
+		tab.model.addMessage("system", t.list())
+		return nil
+	}
+}
+
+// close shuts the chat of tab down and removes it
+func (t *tabSet) close(tab *chatTab) tea.Cmd {
+	if err := tab.model.shutdown.Shutdown(); err != nil {
+		t.current().model.addMessage("system", fmt.Sprintf("⚠️ Closing tab %s: %v", tab.name, err))
+	}
+
+	activeID := t.current().id
+	for i, other := range t.tabs {
+		if other == tab {
+			t.tabs = append(t.tabs[:i], t.tabs[i+1:]...)
+			break
+		}
+	}
+	t.active = 0
+	for i, other := range t.tabs {
+		if other.id == activeID {
+			t.active = i
+		}
+	}
+	t.current().unread = false
+	return t.resize()
+}
+
+// list describes the tabs for /tab
+func (t *tabSet) list() string {
+	var b strings.Builder
+	b.WriteString("🗂️ **Tabs** (/tab <number> switches, Ctrl+Left/Right cycle, /tab new opens one)\n\n")
+	for i, tab := range t.tabs {
+		marker := " "
+		if i == t.active {
+			marker = "▶"
+		}
+		fmt.Fprintf(&b, "%s %d. %s%s\n", marker, i+1, tab.name, t.status(tab))
+	}
+	return b.String()
+}
+
+// status returns the marker shown after a tab's name: waiting for a tool
+// approval, busy, or finished in the background
+func (t *tabSet) status(tab *chatTab) string {
+	switch {
+	case tab.model.awaitingApproval():
+		return " ❓"
+	case tab.model.turnBusy():
+		return " ⏳"
+	case tab.unread:
+		return " •"
+	}
+	return ""
+}
+
+// renderBar renders the tab bar shown above the chat when there is more
+// than one tab
+func (t *tabSet) renderBar() string {
+	activeStyle := lipgloss.NewStyle().Bold(true).Reverse(true)
+	labels := make([]string, len(t.tabs))
+	for i, tab := range t.tabs {
+		label := fmt.Sprintf(" %d %s%s ", i+1, tab.name, t.status(tab))
+		if i == t.active {
+			label = activeStyle.Render(label)
+		}
+		labels[i] = label
+	}
+	return lipgloss.NewStyle().MaxWidth(t.width).Render(strings.Join(labels, "│"))
+}
+
+func (t *tabSet) View() string {
+	view := t.current().model.View()
+	if len(t.tabs) == 1 {
+		return view
+	}
+	return t.renderBar() + "\n" + view
+}
+
+// shutdown releases the resources of every open tab
+func (t *tabSet) shutdown() error {
+	var errs []error
+	for _, tab := range t.tabs {
+		if tab.model.shutdown == nil {
+			continue
+		}
+		if err := tab.model.shutdown.Shutdown(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// awaitingApproval reports whether a tool call waits for the user's decision
+func (m *NewModel) awaitingApproval() bool {
+	return m.toolsManager != nil && m.toolsManager.IsShowingApproval()
+}
+
+// tabInit starts what the chat of a tab opened with /tab new needs. Unlike
+// Init it neither warms up the connection nor saves recovery checkpoints,
+// which follow the first tab.
+func (m *NewModel) tabInit() tea.Cmd {
+	return waitForConfigChange(m.configChanges)
+}
@@ -0,0 +1,93 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/antenore/deecli/internal/chat/tasks"
+	"github.com/antenore/deecli/internal/errlog"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// startTask runs fn in the background, so that the chat stays usable while
+// it works, and announces it. The result is posted to the conversation, or
+// written to output when it is set.
+func (m *NewModel) startTask(title, output string, fn tasks.Func) tea.Cmd {
+	if output != "" {
+		title += " > " + output
+	}
+	task, cmd := m.taskManager.Start(title, fn)
+	task.Output = output
+	m.addMessage("system", fmt.Sprintf("⚙️ Started task #%d: %s. It runs in the background; /tasks cancel %d stops it.", task.ID, title, task.ID))
+	m.refreshViewport()
+	return cmd
+}
+
+// handleTaskDone posts the result of a background task to the conversation
+func (m *NewModel) handleTaskDone(msg tasks.DoneMsg) {
+	task := m.taskManager.Finish(msg.ID)
+	m.showRedactionNotice()
+
+	switch {
+	case errors.Is(msg.Err, context.Canceled):
+		m.addMessage("system", fmt.Sprintf("⏹️ Task #%d (%s) cancelled", msg.ID, msg.Title))
+	case msg.Err != nil:
+		m.reportError(errlog.Classify(msg.Err), fmt.Sprintf("Task #%d (%s) failed: %s", msg.ID, msg.Title, errlog.UserMessage(msg.Err)), msg.Err)
+	default:
+		m.addMessage("system", fmt.Sprintf("✅ Task #%d (%s) finished in %s", msg.ID, msg.Title, msg.Elapsed.Round(time.Second)))
+		if task != nil && task.Output != "" {
+			m.writeTaskOutput(task.Output, msg.Result)
+		} else {
+			m.addMessage("assistant", msg.Result)
+			m.fileContext.TrackPatchSuggestions(msg.Result)
+		}
+		m.notifyCompletion()
+	}
+	m.refreshViewport()
+}
+
+// writeTaskOutput writes the result of a task run with "> path" to path. If
+// that fails the result is posted to the conversation instead, so it is not
+// lost.
+func (m *NewModel) writeTaskOutput(path, result string) {
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	if err == nil {
+		err = os.WriteFile(path, []byte(strings.TrimRight(result, "\n")+"\n"), 0644)
+	}
+	if err != nil {
+		m.reportError(errlog.CategoryGeneral, fmt.Sprintf("Writing the result to %s failed; it is shown below instead", path), err)
+		m.addMessage("assistant", result)
+		return
+	}
+	m.addMessage("system", fmt.Sprintf("📄 Result written to %s (%d lines)", path, strings.Count(strings.TrimRight(result, "\n"), "\n")+1))
+}
+
+// taskStatuses returns the status lines of the running background tasks
+func (m *NewModel) taskStatuses() []string {
+	if m.taskManager == nil {
+		return nil
+	}
+	var statuses []string
+	for _, task := range m.taskManager.Tasks() {
+		statuses = append(statuses, task.Status())
+	}
+	return statuses
+}
@@ -0,0 +1,195 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tasks runs long chat operations, such as /analyze, in the
+// background, so the conversation can go on while they work.
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Progress reports that done of total steps are finished and names the step
+// in progress
+type Progress func(done, total int, current string)
+
+// Func is the work of a task. Its result is posted to the conversation.
+type Func func(ctx context.Context, progress Progress) (string, error)
+
+// ProgressMsg reports the progress of a running task
+type ProgressMsg struct {
+	ID      int
+	Done    int
+	Total   int
+	Current string
+}
+
+// DoneMsg reports that a task finished, failed or was cancelled
+type DoneMsg struct {
+	ID      int
+	Title   string
+	Result  string
+	Err     error
+	Elapsed time.Duration
+}
+
+// Task is an operation running in the background
+type Task struct {
+	ID        int
+	Title     string
+	Done      int
+	Total     int
+	Current   string // Step in progress, such as the file being analyzed
+	Started   time.Time
+	Cancelled bool   // Cancel was called; the task stops at its next request
+	Output    string // File the result is written to instead of the conversation, if any
+
+	cancel context.CancelFunc
+	events chan tea.Msg
+}
+
+// Manager keeps track of the running tasks. It is used from the Bubble Tea
+// loop only; the work of each task runs on its own goroutine.
+type Manager struct {
+	tasks  []*Task
+	nextID int
+}
+
+// NewManager creates a task manager with no tasks
+func NewManager() *Manager {
+	return &Manager{}
+}
+
+// Start runs fn in the background and returns the task with the command that
+// delivers its first event
+func (tm *Manager) Start(title string, fn Func) (*Task, tea.Cmd) {
+	tm.nextID++
+	ctx, cancel := context.WithCancel(context.Background())
+	task := &Task{
+		ID:      tm.nextID,
+		Title:   title,
+		Started: time.Now(),
+		cancel:  cancel,
+		// Progress is dropped rather than wait for the UI; the next report
+		// or the result supersedes it
+		events: make(chan tea.Msg, 8),
+	}
+	tm.tasks = append(tm.tasks, task)
+
+	go func() {
+		defer close(task.events)
+		defer cancel()
+		result, err := fn(ctx, func(done, total int, current string) {
+			select {
+			case task.events <- ProgressMsg{ID: task.ID, Done: done, Total: total, Current: current}:
+			default:
+			}
+		})
+		if err == nil && ctx.Err() != nil {
+			err = ctx.Err()
+		}
+		task.events <- DoneMsg{ID: task.ID, Title: title, Result: result, Err: err, Elapsed: time.Since(task.Started)}
+	}()
+	return task, next(task)
+}
+
+// next returns a command that waits for the next event of task
+func next(task *Task) tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := <-task.events
+		if !ok {
+			return nil
+		}
+		return msg
+	}
+}
+
+// Update records the progress of a task and returns the command waiting for
+// its next event
+func (tm *Manager) Update(msg ProgressMsg) tea.Cmd {
+	task := tm.Get(msg.ID)
+	if task == nil {
+		return nil
+	}
+	task.Done, task.Total, task.Current = msg.Done, msg.Total, msg.Current
+	return next(task)
+}
+
+// Finish removes a task once its DoneMsg arrived and returns it, or nil if
+// it is unknown
+func (tm *Manager) Finish(id int) *Task {
+	for i, task := range tm.tasks {
+		if task.ID == id {
+			tm.tasks = append(tm.tasks[:i], tm.tasks[i+1:]...)
+			return task
+		}
+	}
+	return nil
+}
+
+// Get returns the running task with the given id, or nil
+func (tm *Manager) Get(id int) *Task {
+	for _, task := range tm.tasks {
+		if task.ID == id {
+			return task
+		}
+	}
+	return nil
+}
+
+// Cancel stops the task with the given id; its DoneMsg still follows.
+// It reports whether the task was running.
+func (tm *Manager) Cancel(id int) bool {
+	task := tm.Get(id)
+	if task == nil {
+		return false
+	}
+	task.Cancelled = true
+	task.cancel()
+	return true
+}
+
+// CancelAll stops every running task
+func (tm *Manager) CancelAll() {
+	for _, task := range tm.tasks {
+		task.Cancelled = true
+		task.cancel()
+	}
+}
+
+// Tasks returns the running tasks, oldest first
+func (tm *Manager) Tasks() []*Task {
+	return tm.tasks
+}
+
+// Status describes the progress of a task in a line, such as
+// "#2 Analyze: 1/3 main.go (12s)"
+func (t *Task) Status() string {
+	status := fmt.Sprintf("#%d %s", t.ID, t.Title)
+	switch {
+	case t.Cancelled:
+		status += ": cancelling"
+	case t.Total > 0:
+		status += fmt.Sprintf(": %d/%d", t.Done, t.Total)
+		if t.Current != "" {
+			status += " " + t.Current
+		}
+	default:
+		status += ": starting"
+	}
+	return status + fmt.Sprintf(" (%s)", time.Since(t.Started).Round(time.Second))
+}
@@ -0,0 +1,71 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tasks
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestManager_ProgressAndResult(t *testing.T) {
+	tm := NewManager()
+	release := make(chan struct{})
+	task, cmd := tm.Start("Analyze 2 file(s)", func(ctx context.Context, progress Progress) (string, error) {
+		progress(0, 2, "a.go")
+		<-release
+		return "done", nil
+	})
+
+	msg, ok := cmd().(ProgressMsg)
+	if !ok {
+		t.Fatalf("expected a progress message first, got %T", msg)
+	}
+	next := tm.Update(msg)
+	if task.Total != 2 || task.Current != "a.go" {
+		t.Errorf("expected the progress recorded, got %d/%d %q", task.Done, task.Total, task.Current)
+	}
+	if status := task.Status(); !strings.Contains(status, "#1 Analyze 2 file(s): 0/2 a.go") {
+		t.Errorf("unexpected status %q", status)
+	}
+
+	close(release)
+	done, ok := next().(DoneMsg)
+	if !ok || done.Result != "done" || done.Err != nil {
+		t.Fatalf("expected the result, got %+v", done)
+	}
+	if tm.Finish(done.ID) != task || len(tm.Tasks()) != 0 {
+		t.Error("expected the finished task to be removed")
+	}
+}
+
+func TestManager_Cancel(t *testing.T) {
+	tm := NewManager()
+	_, cmd := tm.Start("Review main..HEAD", func(ctx context.Context, progress Progress) (string, error) {
+		<-ctx.Done()
+		return "", nil
+	})
+	if tm.Cancel(42) {
+		t.Error("expected cancelling an unknown task to fail")
+	}
+	if !tm.Cancel(1) || !tm.Get(1).Cancelled {
+		t.Fatal("expected the task to be cancelled")
+	}
+
+	done, ok := cmd().(DoneMsg)
+	if !ok || !errors.Is(done.Err, context.Canceled) {
+		t.Errorf("expected a cancelled result, got %+v", done)
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/antenore/deecli/internal/api"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// loopRepeats is how many identical calls for one message count as a loop
+const loopRepeats = 3
+
+// ToolLimitReachedMsg reports that a tool call limit stopped the chain
+type ToolLimitReachedMsg struct {
+	Reason string // Which limit was reached, e.g. "25 tool calls for this message (tool_calls_per_turn)"
+}
+
+// ToolLoopDetectedMsg reports that the model asked for the same call again
+// and again, and the chain was stopped
+type ToolLoopDetectedMsg struct {
+	Tool      string // Name of the repeated tool
+	Arguments string // Its arguments
+	Count     int    // How often it was requested for the message, including this time
+}
+
+// callLimiter counts tool calls against the per-turn and per-minute limits,
+// so a runaway model cannot read the whole project one call at a time, and
+// spots a model requesting the same call over and over
+type callLimiter struct {
+	turn      int            // Message the turn count belongs to
+	turnCalls int            // Calls made for that message
+	seen      map[string]int // Calls made for that message, by callKey
+	recent    []time.Time    // Calls made in the last minute
+}
+
+// startTurn resets the per-message counts when message is a new one
+func (l *callLimiter) startTurn(message int) {
+	if message != l.turn || l.seen == nil {
+		l.turn, l.turnCalls, l.seen = message, 0, map[string]int{}
+	}
+}
+
+// repeats returns how often the call identified by key was already made
+// for message
+func (l *callLimiter) repeats(message int, key string) int {
+	l.startTurn(message)
+	return l.seen[key]
+}
+
+// allow records a call identified by key made for message at now, or
+// returns which limit it would exceed. A limit of 0 is no limit.
+func (l *callLimiter) allow(message int, key string, perTurn, perMinute int, now time.Time) (string, bool) {
+	l.startTurn(message)
+	cutoff := now.Add(-time.Minute)
+	for len(l.recent) > 0 && !l.recent[0].After(cutoff) {
+		l.recent = l.recent[1:]
+	}
+
+	if perTurn > 0 && l.turnCalls >= perTurn {
+		return fmt.Sprintf("%d tool calls for this message (tool_calls_per_turn)", perTurn), false
+	}
+	if perMinute > 0 && len(l.recent) >= perMinute {
+		return fmt.Sprintf("%d tool calls in a minute (tool_calls_per_minute)", perMinute), false
+	}
+	l.turnCalls++
+	l.seen[key]++
+	l.recent = append(l.recent, now)
+	return "", true
+}
+
+// callKey identifies a call by its tool and arguments, whatever the order
+// and spacing of the arguments
+func callKey(toolCall api.ToolCall) string {
+	var args interface{}
+	if err := json.Unmarshal([]byte(toolCall.Function.Arguments), &args); err == nil {
+		if canonical, err := json.Marshal(args); err == nil {
+			return toolCall.Function.Name + " " + string(canonical)
+		}
+	}
+	return toolCall.Function.Name + " " + toolCall.Function.Arguments
+}
+
+// checkLimits counts toolCall against the current message, or stops the
+// chain when the model is looping on it or it would exceed a limit: the
+// queued calls are dropped and a ToolLoopDetectedMsg or ToolLimitReachedMsg
+// asks for a follow-up that tells the model why
+func (m *Manager) checkLimits(toolCall api.ToolCall) tea.Cmd {
+	message := 0
+	if m.auditContext != nil {
+		_, message = m.auditContext()
+	}
+	key := callKey(toolCall)
+	if count := m.limiter.repeats(message, key) + 1; count >= loopRepeats {
+		m.stopChain()
+		return func() tea.Msg {
+			return ToolLoopDetectedMsg{Tool: toolCall.Function.Name, Arguments: toolCall.Function.Arguments, Count: count}
+		}
+	}
+
+	perTurn, perMinute := 0, 0
+	if m.callLimits != nil {
+		perTurn, perMinute = m.callLimits()
+	}
+	reason, ok := m.limiter.allow(message, key, perTurn, perMinute, time.Now())
+	if ok {
+		return nil
+	}
+	m.stopChain()
+	return func() tea.Msg {
+		return ToolLimitReachedMsg{Reason: reason}
+	}
+}
+
+// stopChain drops the queued calls and asks for a follow-up without tools
+func (m *Manager) stopChain() {
+	m.pendingToolCalls = nil
+	m.showingApproval = false
+	m.suppressNextToolCalls = true
+	m.followupPending = true
+}
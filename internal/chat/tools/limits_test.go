@@ -0,0 +1,91 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/antenore/deecli/internal/api"
+)
+
+func TestCallLimiter(t *testing.T) {
+	var l callLimiter
+	now := time.Now()
+
+	for i := 0; i < 3; i++ {
+		if _, ok := l.allow(1, "read_file {}", 3, 5, now); !ok {
+			t.Fatalf("call %d refused within the per-turn limit", i+1)
+		}
+	}
+	if reason, ok := l.allow(1, "read_file {}", 3, 5, now); ok || !strings.Contains(reason, "tool_calls_per_turn") {
+		t.Errorf("fourth call of the message = %q, %v; want the per-turn limit", reason, ok)
+	}
+
+	// A new message starts a new turn, but the minute is shared
+	for i := 0; i < 2; i++ {
+		if _, ok := l.allow(2, "read_file {}", 3, 5, now); !ok {
+			t.Fatalf("call %d of the next message refused", i+1)
+		}
+	}
+	if reason, ok := l.allow(2, "read_file {}", 3, 5, now); ok || !strings.Contains(reason, "tool_calls_per_minute") {
+		t.Errorf("sixth call in a minute = %q, %v; want the per-minute limit", reason, ok)
+	}
+	if _, ok := l.allow(2, "read_file {}", 3, 5, now.Add(time.Minute+time.Second)); !ok {
+		t.Error("call refused after the minute passed")
+	}
+
+	// Zero disables both limits
+	var unlimited callLimiter
+	for i := 0; i < 100; i++ {
+		if _, ok := unlimited.allow(1, "read_file {}", 0, 0, now); !ok {
+			t.Fatalf("call %d refused without limits", i+1)
+		}
+	}
+}
+
+func TestManager_CheckLimitsLoop(t *testing.T) {
+	m := NewManager(Dependencies{})
+	call := func(args string) api.ToolCall {
+		var toolCall api.ToolCall
+		toolCall.Function.Name = "read_file"
+		toolCall.Function.Arguments = args
+		return toolCall
+	}
+
+	if cmd := m.checkLimits(call(`{"path": "main.go"}`)); cmd != nil {
+		t.Fatal("first call stopped")
+	}
+	if cmd := m.checkLimits(call(`{"path":"main.go"}`)); cmd != nil {
+		t.Fatal("second call stopped")
+	}
+	if cmd := m.checkLimits(call(`{"path":"go.mod"}`)); cmd != nil {
+		t.Fatal("a call with other arguments stopped")
+	}
+
+	// The third identical call, however its arguments are spaced, is a loop
+	m.pendingToolCalls = []api.ToolCall{call(`{"path":"main.go"}`), call(`{"path":"go.sum"}`)}
+	cmd := m.checkLimits(call(`{ "path" : "main.go" }`))
+	if cmd == nil {
+		t.Fatal("repeated call not detected")
+	}
+	msg, ok := cmd().(ToolLoopDetectedMsg)
+	if !ok || msg.Tool != "read_file" || msg.Count != 3 {
+		t.Errorf("checkLimits() = %+v, want a ToolLoopDetectedMsg for the third read_file", msg)
+	}
+	if len(m.pendingToolCalls) != 0 || !m.ShouldSuppressToolCalls() || !m.TakeFollowup() {
+		t.Error("the chain was not stopped for a follow-up without tools")
+	}
+}
@@ -17,9 +17,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"slices"
+	"time"
 
 	"github.com/antenore/deecli/internal/ai"
 	"github.com/antenore/deecli/internal/api"
+	"github.com/antenore/deecli/internal/audit"
 	"github.com/antenore/deecli/internal/chat/ui"
 	"github.com/antenore/deecli/internal/debug"
 	"github.com/antenore/deecli/internal/permissions"
@@ -34,12 +37,25 @@ type Manager struct {
 	permissionManager  *permissions.Manager
 	approvalHandler    *ui.ApprovalHandler
 	approvalDialog     *ui.ApprovalDialog
+	auditLog           *audit.Log
+	auditContext       func() (sessionID int64, messageID int)
 	showingApproval    bool
 	pendingToolCalls   []api.ToolCall
 	// Guard to avoid loops when DeepSeek returns tool-call markers
 	// even after we request a follow-up with tool_choice="none".
 	// When true, the next non-stream response will not trigger tool parsing.
 	suppressNextToolCalls bool
+	// Tool chain state so Esc can cancel between steps
+	executing       bool // A tool is currently running
+	followupPending bool // A follow-up API call was requested but not yet started
+	chainCancelled  bool // The user cancelled the current tool chain
+	// Audit records of the calls run for the current message, cited
+	// under the response they inform
+	sources []audit.Entry
+	// Tool call limits and the calls counted against them, which also
+	// catch a model repeating a call
+	callLimits func() (perTurn, perMinute int)
+	limiter    callLimiter
 }
 
 // Dependencies contains the dependencies needed by the tool manager
@@ -48,6 +64,9 @@ type Dependencies struct {
 	ToolsExecutor     *tools.Executor
 	PermissionManager *permissions.Manager
 	ApprovalHandler   *ui.ApprovalHandler
+	AuditLog          *audit.Log                              // Records every tool call, may be nil
+	AuditContext      func() (sessionID int64, messageID int) // Identifies the message that started a chain
+	CallLimits        func() (perTurn, perMinute int)         // Tool calls allowed per message and per minute, 0 for no limit; may be nil
 }
 
 // NewManager creates a new tool manager with the given dependencies
@@ -57,6 +76,9 @@ func NewManager(deps Dependencies) *Manager {
 		toolsExecutor:     deps.ToolsExecutor,
 		permissionManager: deps.PermissionManager,
 		approvalHandler:   deps.ApprovalHandler,
+		auditLog:          deps.AuditLog,
+		auditContext:      deps.AuditContext,
+		callLimits:        deps.CallLimits,
 	}
 }
 
@@ -65,6 +87,7 @@ type ToolExecutionCompleteMsg struct {
 	ToolCall api.ToolCall
 	Result   *tools.ExecutionResult
 	Error    error
+	Audit    audit.Entry // What was recorded in the audit log for the call
 }
 
 // HandleToolCallsResponse handles AI responses that request tool executions
@@ -77,6 +100,7 @@ func (m *Manager) HandleToolCallsResponse(msg ai.ToolCallsResponseMsg) tea.Cmd {
 
 	// Store the pending tool calls
 	m.pendingToolCalls = msg.ToolCalls
+	m.chainCancelled = false
 
 	// Show the first tool call for approval
 	if len(msg.ToolCalls) > 0 {
@@ -118,17 +142,31 @@ func (m *Manager) requestToolApproval(toolCall api.ToolCall) tea.Cmd {
 	}
 	debug.Printf("[DEBUG] ==========================================\n\n")
 
-	// Get tool description
+	if cmd := m.checkLimits(toolCall); cmd != nil {
+		return cmd
+	}
+
+	// Get tool description and, for sandboxed tools, the capabilities approval grants
 	description := fmt.Sprintf("Execute %s", toolCall.Function.Name)
+	var capabilities []tools.Capability
+	var outside []string
+	simulated := false
 	if tool, exists := m.toolsRegistry.Get(toolCall.Function.Name); exists {
 		description = tool.Description()
+		capabilities = tools.DeclaredCapabilities(tool)
+		simulated = m.toolsExecutor.Simulates(tool)
+		// A protected path is refused when the call runs, with the reason
+		outside, _ = m.toolsExecutor.OutsideWorkspace(tool, json.RawMessage(toolCall.Function.Arguments))
 	}
 
 	// Create approval request
 	approvalReq := tools.ApprovalRequest{
-		FunctionName: toolCall.Function.Name,
-		Description:  description,
-		Arguments:    args,
+		FunctionName:     toolCall.Function.Name,
+		Description:      description,
+		Arguments:        args,
+		Capabilities:     capabilities,
+		Simulated:        simulated,
+		OutsideWorkspace: outside,
 	}
 
 	// Show approval dialog - dimensions will be set by caller
@@ -146,6 +184,9 @@ func (m *Manager) requestToolApproval(toolCall api.ToolCall) tea.Cmd {
 // ExecuteApprovedTool executes a tool after user approval
 func (m *Manager) ExecuteApprovedTool(response tools.ApprovalResponse) tea.Cmd {
 	if !response.Approved || len(m.pendingToolCalls) == 0 {
+		if len(m.pendingToolCalls) > 0 {
+			m.recordAudit(m.newAuditEntry(m.pendingToolCalls[0], audit.DecisionDenied))
+		}
 		m.pendingToolCalls = nil
 		return func() tea.Msg {
 			return fmt.Errorf("tool execution cancelled")
@@ -155,6 +196,13 @@ func (m *Manager) ExecuteApprovedTool(response tools.ApprovalResponse) tea.Cmd {
 	// Get the first pending tool call
 	toolCall := m.pendingToolCalls[0]
 	m.pendingToolCalls = m.pendingToolCalls[1:] // Remove from queue
+	m.executing = true
+
+	decision := audit.DecisionOnce
+	if response.Level == tools.PermissionAlways {
+		decision = audit.DecisionAlways
+	}
+	entry := m.newAuditEntry(toolCall, decision)
 
 	// Execute the tool
 	return func() tea.Msg {
@@ -167,13 +215,34 @@ func (m *Manager) ExecuteApprovedTool(response tools.ApprovalResponse) tea.Cmd {
 			args = []byte(toolCall.Function.Arguments)
 		}
 
-		// Execute the tool
-		result, err := m.toolsExecutor.ExecuteWithoutPermission(context.Background(), toolCall.Function.Name, args)
+		// Execute the tool; approving a sandboxed tool grants the capabilities
+		// it declares, and approving any call the paths outside the workspace
+		ctx := context.Background()
+		if tool, exists := m.toolsRegistry.Get(toolCall.Function.Name); exists {
+			ctx = tools.WithCapabilities(ctx, tools.DeclaredCapabilities(tool))
+			if outside, err := m.toolsExecutor.OutsideWorkspace(tool, args); err == nil {
+				ctx = tools.WithApprovedPaths(ctx, outside)
+			}
+		}
+		started := time.Now()
+		result, err := m.toolsExecutor.ExecuteWithoutPermission(ctx, toolCall.Function.Name, args)
+		entry.DurationMs = time.Since(started).Milliseconds()
+		switch {
+		case err != nil:
+			entry.Error = err.Error()
+		case result != nil:
+			entry.Success = result.Success
+			entry.ResultSize = len(result.Output)
+			entry.Error = result.Error
+		}
+		m.recordAudit(entry)
+
 		if err != nil {
 			return ToolExecutionCompleteMsg{
 				ToolCall: toolCall,
 				Result:   nil,
 				Error:    err,
+				Audit:    entry,
 			}
 		}
 
@@ -181,12 +250,51 @@ func (m *Manager) ExecuteApprovedTool(response tools.ApprovalResponse) tea.Cmd {
 			ToolCall: toolCall,
 			Result:   result,
 			Error:    nil,
+			Audit:    entry,
 		}
 	}
 }
 
+// newAuditEntry starts the audit record of a tool call
+func (m *Manager) newAuditEntry(toolCall api.ToolCall, decision audit.Decision) audit.Entry {
+	entry := audit.Entry{
+		Time:       time.Now(),
+		Tool:       toolCall.Function.Name,
+		Arguments:  json.RawMessage(toolCall.Function.Arguments),
+		Decision:   decision,
+		ToolCallID: toolCall.ID,
+	}
+	if tool, exists := m.toolsRegistry.Get(toolCall.Function.Name); exists {
+		entry.DryRun = m.toolsExecutor.Simulates(tool)
+	}
+	if m.auditContext != nil {
+		entry.SessionID, entry.MessageID = m.auditContext()
+	}
+	return entry
+}
+
+// recordAudit writes an entry to the audit log, if one is configured
+func (m *Manager) recordAudit(entry audit.Entry) {
+	if m.auditLog == nil {
+		return
+	}
+	if err := m.auditLog.Record(entry); err != nil {
+		debug.Printf("[DEBUG] Failed to write audit log: %v\n", err)
+	}
+}
+
 // HandleToolExecutionComplete handles the completion of tool execution
 func (m *Manager) HandleToolExecutionComplete(msg ToolExecutionCompleteMsg, aiOperations *ai.Operations) (tea.Cmd, bool) {
+	m.executing = false
+
+	// Drop results that arrive after the user cancelled the chain
+	if m.chainCancelled {
+		return nil, false
+	}
+	if msg.Audit.Tool != "" {
+		m.sources = append(m.sources, msg.Audit)
+	}
+
 	if msg.Error != nil {
 		return nil, false
 	}
@@ -232,6 +340,7 @@ func (m *Manager) handleSuccessfulToolCompletion(msg ToolExecutionCompleteMsg, a
 
 	// Set the flag to suppress tool parsing in the follow-up response
 	m.suppressNextToolCalls = true
+	m.followupPending = true
 
 	// Return command to trigger follow-up API call
 	return func() tea.Msg {
@@ -245,7 +354,9 @@ type RequestToolApprovalMsg struct {
 }
 
 // TriggerFollowupMsg represents a request to trigger follow-up API call
-type TriggerFollowupMsg struct{}
+type TriggerFollowupMsg struct {
+	Note string // Told to the model with the follow-up, e.g. why the chain stopped
+}
 
 // CreateApprovalDialogMsg represents a request to create approval dialog
 type CreateApprovalDialogMsg struct {
@@ -253,6 +364,61 @@ type CreateApprovalDialogMsg struct {
 	ToolCall       api.ToolCall
 }
 
+// TakeFollowup reports whether a requested follow-up should still run and marks it started
+func (m *Manager) TakeFollowup() bool {
+	if !m.followupPending {
+		return false
+	}
+	m.followupPending = false
+	return true
+}
+
+// TakeSources returns the tool calls run for the current message, to cite
+// under the response they informed, and forgets them
+func (m *Manager) TakeSources() []audit.Entry {
+	sources := m.sources
+	m.sources = nil
+	// Calls left over from an earlier message that got no response do not count
+	if m.auditContext != nil {
+		_, messageID := m.auditContext()
+		sources = slices.DeleteFunc(sources, func(entry audit.Entry) bool {
+			return entry.MessageID != messageID
+		})
+	}
+	return sources
+}
+
+// HasPendingChain returns true while tools are queued, running, or awaiting a follow-up
+func (m *Manager) HasPendingChain() bool {
+	return m.executing || m.followupPending || m.showingApproval || len(m.pendingToolCalls) > 0
+}
+
+// CancelChain discards queued tool calls and any pending follow-up, returning how many calls were dropped
+func (m *Manager) CancelChain() int {
+	discarded := len(m.pendingToolCalls)
+	m.pendingToolCalls = nil
+	m.showingApproval = false
+	m.approvalDialog = nil
+	m.executing = false
+	m.followupPending = false
+	m.suppressNextToolCalls = false
+	m.chainCancelled = true
+	m.sources = nil
+	return discarded
+}
+
+// SetDryRun turns dry-run mode on or off for tools that write files or run commands
+func (m *Manager) SetDryRun(enabled bool) {
+	if m.toolsExecutor != nil {
+		m.toolsExecutor.SetDryRun(enabled)
+	}
+}
+
+// DryRun reports whether dry-run mode is on
+func (m *Manager) DryRun() bool {
+	return m.toolsExecutor != nil && m.toolsExecutor.DryRun()
+}
+
 // IsShowingApproval returns true if approval dialog is currently showing
 func (m *Manager) IsShowingApproval() bool {
 	return m.showingApproval
@@ -21,6 +21,7 @@ import (
 
 	"github.com/antenore/deecli/internal/ai"
 	"github.com/antenore/deecli/internal/api"
+	"github.com/antenore/deecli/internal/audit"
 	"github.com/antenore/deecli/internal/chat/ui"
 	"github.com/antenore/deecli/internal/config"
 	"github.com/antenore/deecli/internal/files"
@@ -499,4 +500,83 @@ func TestManager_IntegrationFlow(t *testing.T) {
 	if len(manager.pendingToolCalls) != 0 {
 		t.Errorf("Final state: pending tool calls = %d, want 0", len(manager.pendingToolCalls))
 	}
-}
\ No newline at end of file
+}
+
+func TestManager_CancelChain(t *testing.T) {
+	manager, _, aiOps := setupTestManager()
+
+	toolCalls := []api.ToolCall{
+		{
+			ID:   "call_1",
+			Type: "function",
+			Function: struct {
+				Name      string `json:"name"`
+				Arguments string `json:"arguments"`
+			}{
+				Name:      "test_read_file",
+				Arguments: `{"path": "cancel_test.go"}`,
+			},
+		},
+	}
+
+	cmd := manager.HandleToolCallsResponse(ai.ToolCallsResponseMsg{ToolCalls: toolCalls})
+	if cmd == nil {
+		t.Fatal("HandleToolCallsResponse() returned nil")
+	}
+	createMsg, ok := cmd().(CreateApprovalDialogMsg)
+	if !ok {
+		t.Fatal("Expected CreateApprovalDialogMsg")
+	}
+	manager.CreateApprovalDialog(createMsg.ApprovalRequest, 80, 24)
+
+	execCmd := manager.ExecuteApprovedTool(tools.ApprovalResponse{Approved: true})
+	if execCmd == nil {
+		t.Fatal("ExecuteApprovedTool() returned nil")
+	}
+	execMsg, ok := execCmd().(ToolExecutionCompleteMsg)
+	if !ok {
+		t.Fatal("Expected ToolExecutionCompleteMsg")
+	}
+
+	if !manager.HasPendingChain() {
+		t.Error("HasPendingChain() = false while a tool is executing, want true")
+	}
+
+	manager.CancelChain()
+
+	if manager.HasPendingChain() {
+		t.Error("HasPendingChain() = true after CancelChain(), want false")
+	}
+
+	// A tool finishing after cancellation must not trigger a follow-up
+	followCmd, handled := manager.HandleToolExecutionComplete(execMsg, aiOps)
+	if followCmd != nil || handled {
+		t.Error("HandleToolExecutionComplete() after CancelChain() should be ignored")
+	}
+	if manager.TakeFollowup() {
+		t.Error("TakeFollowup() = true after CancelChain(), want false")
+	}
+}
+
+func TestManager_TakeSources(t *testing.T) {
+	manager, _, aiOps := setupTestManager()
+	messageID := 3
+	manager.auditContext = func() (int64, int) { return 1, messageID }
+
+	toolCall := api.ToolCall{ID: "call_1", Type: "function"}
+	toolCall.Function.Name = "test_read_file"
+	result := &tools.ExecutionResult{Success: true, Output: "content"}
+
+	stale := audit.Entry{Tool: "test_read_file", MessageID: 2}
+	current := audit.Entry{Tool: "test_read_file", MessageID: 3, ToolCallID: "call_1"}
+	manager.HandleToolExecutionComplete(ToolExecutionCompleteMsg{ToolCall: toolCall, Result: result, Audit: stale}, aiOps)
+	manager.HandleToolExecutionComplete(ToolExecutionCompleteMsg{ToolCall: toolCall, Result: result, Audit: current}, aiOps)
+
+	sources := manager.TakeSources()
+	if len(sources) != 1 || sources[0].ToolCallID != "call_1" {
+		t.Errorf("TakeSources() = %+v, want only the call of the current message", sources)
+	}
+	if again := manager.TakeSources(); len(again) != 0 {
+		t.Errorf("TakeSources() twice = %+v, want nothing the second time", again)
+	}
+}
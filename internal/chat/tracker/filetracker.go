@@ -16,6 +16,7 @@ package tracker
 import (
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -29,12 +30,18 @@ type TrackedFile struct {
 	Description string
 	Timestamp   time.Time
 	Source      string // "ai_response", "user_mention", "edit_suggestion"
+	Line        int    // Line referenced with the file, as in main.go:42, or 0
+	Symbol      string // Function or type named with the file, or ""
+	Response    int    // Sequence number of the response the file was mentioned in
 }
 
 // FileTracker tracks files mentioned in AI responses
 type FileTracker struct {
 	mu    sync.RWMutex
 	files []TrackedFile
+	known map[string]bool // Every path tracked so far
+	fresh int             // Paths first tracked since the last MarkSeen
+	seq   int             // Responses processed so far
 }
 
 // NewFileTracker creates a new file tracker
@@ -137,8 +144,24 @@ func (ft *FileTracker) ExtractFilesFromResponseWithContext(response string, load
 		}
 	}
 
+	// Record where in each file the response points to
+	ft.seq++
+	annotateLocations(response, extracted, loadedFiles)
+	for i := range extracted {
+		extracted[i].Response = ft.seq
+	}
+
 	// Add extracted files to the tracker
 	ft.files = append(ft.files, extracted...)
+	for _, file := range extracted {
+		if ft.known == nil {
+			ft.known = make(map[string]bool)
+		}
+		if !ft.known[file.Path] {
+			ft.known[file.Path] = true
+			ft.fresh++
+		}
+	}
 
 	// Keep only the most recent 50 files
 	if len(ft.files) > 50 {
@@ -166,6 +189,63 @@ func (ft *FileTracker) GetRecentFiles(limit int) []TrackedFile {
 	return result
 }
 
+// Mentioned returns each tracked file once, most recently mentioned first
+func (ft *FileTracker) Mentioned() []TrackedFile {
+	ft.mu.RLock()
+	defer ft.mu.RUnlock()
+
+	var mentioned []TrackedFile
+	for i := len(ft.files) - 1; i >= 0; i-- {
+		if !containsFile(mentioned, ft.files[i].Path) {
+			mentioned = append(mentioned, ft.files[i])
+		}
+	}
+	return mentioned
+}
+
+// Location returns the line and symbol the latest response mentioning path
+// referenced in it. A path matches a tracked file with the same relative path
+// or when one ends with the other.
+func (ft *FileTracker) Location(path string) (int, string) {
+	ft.mu.RLock()
+	defer ft.mu.RUnlock()
+
+	path = filepath.ToSlash(filepath.Clean(path))
+	response := 0
+	line, symbol := 0, ""
+	for i := len(ft.files) - 1; i >= 0; i-- {
+		file := ft.files[i]
+		if response != 0 && file.Response != response {
+			break
+		}
+		if !samePath(file.Path, path) {
+			continue
+		}
+		response = file.Response
+		if file.Line > 0 {
+			line = file.Line
+		}
+		if file.Symbol != "" {
+			symbol = file.Symbol
+		}
+	}
+	return line, symbol
+}
+
+// NewCount returns how many files were mentioned for the first time since the last MarkSeen
+func (ft *FileTracker) NewCount() int {
+	ft.mu.RLock()
+	defer ft.mu.RUnlock()
+	return ft.fresh
+}
+
+// MarkSeen resets NewCount once the tracked files have been shown
+func (ft *FileTracker) MarkSeen() {
+	ft.mu.Lock()
+	defer ft.mu.Unlock()
+	ft.fresh = 0
+}
+
 // GetEditSuggestions returns files marked as edit suggestions
 func (ft *FileTracker) GetEditSuggestions() []TrackedFile {
 	ft.mu.RLock()
@@ -186,6 +266,8 @@ func (ft *FileTracker) Clear() {
 	ft.mu.Lock()
 	defer ft.mu.Unlock()
 	ft.files = make([]TrackedFile, 0)
+	ft.known = nil
+	ft.fresh = 0
 }
 
 // HasSuggestions returns true if there are any edit suggestions
@@ -203,6 +285,64 @@ func (ft *FileTracker) HasSuggestions() bool {
 
 // Helper functions
 
+var (
+	// fileLinePattern matches main.go:42 and main.go#L42
+	fileLinePattern = regexp.MustCompile(`([a-zA-Z0-9_\-/.]+\.[a-zA-Z0-9]+)(?::|#L)(\d+)`)
+	// linePattern matches "line 42" next to a file name
+	linePattern = regexp.MustCompile(`(?i)\blines?\s+(\d+)`)
+	// symbolPattern matches "func Name", "func (r *T) Name" and `Name()`
+	symbolPattern = regexp.MustCompile("\\bfunc\\s+(?:\\([^)]*\\)\\s*)?([A-Za-z_]\\w*)|`([A-Za-z_][\\w.]*)\\(\\)`")
+)
+
+// annotateLocations sets Line and Symbol on the extracted files from lines of
+// the response that name the file together with a line number or a function
+func annotateLocations(response string, extracted []TrackedFile, loadedFiles []files.LoadedFile) {
+	for _, text := range strings.Split(response, "\n") {
+		for _, match := range fileLinePattern.FindAllStringSubmatch(text, -1) {
+			file := findTracked(extracted, resolvePathFromLoadedFiles(cleanPath(match[1]), loadedFiles))
+			if file != nil && file.Line == 0 {
+				file.Line, _ = strconv.Atoi(match[2])
+			}
+		}
+
+		for i := range extracted {
+			file := &extracted[i]
+			if !strings.Contains(text, filepath.Base(file.Path)) {
+				continue
+			}
+			if file.Line == 0 {
+				if match := linePattern.FindStringSubmatch(text); match != nil {
+					file.Line, _ = strconv.Atoi(match[1])
+				}
+			}
+			if file.Symbol == "" {
+				if match := symbolPattern.FindStringSubmatch(text); match != nil {
+					symbol := match[1] + match[2]
+					// Keep the method name of Type.Method
+					file.Symbol = symbol[strings.LastIndex(symbol, ".")+1:]
+				}
+			}
+		}
+	}
+}
+
+// findTracked returns the extracted file with the given path
+func findTracked(extracted []TrackedFile, path string) *TrackedFile {
+	for i := range extracted {
+		if extracted[i].Path == path {
+			return &extracted[i]
+		}
+	}
+	return nil
+}
+
+// samePath reports whether two slash-separated paths name the same file,
+// allowing either to be a suffix of the other
+func samePath(a, b string) bool {
+	a = filepath.ToSlash(filepath.Clean(a))
+	return a == b || strings.HasSuffix(a, "/"+b) || strings.HasSuffix(b, "/"+a)
+}
+
 func cleanPath(path string) string {
 	// Remove leading/trailing whitespace and quotes
 	path = strings.TrimSpace(path)
@@ -297,4 +297,56 @@ func TestConcurrentAccess(t *testing.T) {
 	<-done
 
 	// If we get here without deadlock or panic, concurrent access works
-}
\ No newline at end of file
+}
+
+func TestMentioned(t *testing.T) {
+	ft := NewFileTracker()
+	ft.ExtractFilesFromResponse("Look at main.go and config.yaml")
+	if got := ft.NewCount(); got != 2 {
+		t.Errorf("NewCount() = %d, want 2", got)
+	}
+
+	ft.MarkSeen()
+	ft.ExtractFilesFromResponse("Now change main.go and add util.go")
+	if got := ft.NewCount(); got != 1 {
+		t.Errorf("NewCount() = %d, want 1 for util.go only", got)
+	}
+
+	mentioned := ft.Mentioned()
+	var paths []string
+	for _, file := range mentioned {
+		paths = append(paths, file.Path)
+	}
+	if got := strings.Join(paths, ","); got != "util.go,main.go,config.yaml" {
+		t.Errorf("Mentioned() = %s, want each file once, most recent first", got)
+	}
+
+	ft.Clear()
+	if ft.NewCount() != 0 || len(ft.Mentioned()) != 0 {
+		t.Error("expected Clear to forget mentioned files")
+	}
+}
+
+func TestLocation(t *testing.T) {
+	ft := NewFileTracker()
+	ft.ExtractFilesFromResponse("The bug is in internal/api/client.go:42 where the timeout is set.\nAlso check `Service.Close()` in service.go.")
+
+	if line, _ := ft.Location("internal/api/client.go"); line != 42 {
+		t.Errorf("Location(client.go) line = %d, want 42", line)
+	}
+	if line, _ := ft.Location("api/client.go"); line != 42 {
+		t.Errorf("expected a path suffix to match, got line %d", line)
+	}
+	if line, symbol := ft.Location("service.go"); line != 0 || symbol != "Close" {
+		t.Errorf("Location(service.go) = %d, %q, want 0, \"Close\"", line, symbol)
+	}
+
+	// Only the latest response mentioning a file counts
+	ft.ExtractFilesFromResponse("client.go looks fine now.")
+	if line, symbol := ft.Location("internal/api/client.go"); line != 0 || symbol != "" {
+		t.Errorf("expected no location from the latest response, got %d, %q", line, symbol)
+	}
+	if line, _ := ft.Location("main.go"); line != 0 {
+		t.Errorf("expected no location for an unmentioned file, got %d", line)
+	}
+}
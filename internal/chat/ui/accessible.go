@@ -0,0 +1,56 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"regexp"
+	"strings"
+)
+
+// statusLabels replaces status emoji with words a screen reader announces clearly
+var statusLabels = strings.NewReplacer(
+	"❌", "Error:",
+	"✅", "OK:",
+	"✓", "OK:",
+	"⚠️", "Warning:",
+	"⚠", "Warning:",
+	"🚫", "Cancelled:",
+	"🔒", "Security:",
+	"💡", "Tip:",
+)
+
+var (
+	// decorativeSymbols matches emoji and pictographs that carry no meaning when read aloud,
+	// along with the space that usually separates them from the text
+	decorativeSymbols = regexp.MustCompile(`[\x{1F000}-\x{1FAFF}\x{2600}-\x{27BF}\x{2B00}-\x{2BFF}\x{FE0F}\x{200D}]+ ?`)
+	// boxDrawing matches border characters
+	boxDrawing = regexp.MustCompile(`[\x{2500}-\x{257F}]+`)
+	// repeatedSpaces collapses gaps left behind by removed symbols
+	repeatedSpaces = regexp.MustCompile(`[ \t]{2,}`)
+)
+
+// AccessibleText replaces status emoji with text labels and removes decorative
+// symbols and box-drawing borders, leaving text a screen reader can present
+func AccessibleText(text string) string {
+	text = statusLabels.Replace(text)
+	text = decorativeSymbols.ReplaceAllString(text, "")
+	text = boxDrawing.ReplaceAllString(text, "-")
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+		lines[i] = line[:indent] + strings.TrimSpace(repeatedSpaces.ReplaceAllString(line[indent:], " "))
+	}
+	return strings.Join(lines, "\n")
+}
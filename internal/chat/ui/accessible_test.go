@@ -0,0 +1,60 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAccessibleText(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "❌ Failed to load file", want: "Error: Failed to load file"},
+		{input: "✅ Model set to: deepseek-chat", want: "OK: Model set to: deepseek-chat"},
+		{input: "🐉 DeeCLI - AI Code Assistant", want: "DeeCLI - AI Code Assistant"},
+		{input: "📁 Files loaded: 3", want: "Files loaded: 3"},
+		{input: "┌──────┐", want: "-"},
+		{input: "   indented 🔧 text", want: "   indented text"},
+	}
+
+	for _, tt := range tests {
+		if got := AccessibleText(tt.input); got != tt.want {
+			t.Errorf("AccessibleText(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestRenderer_AccessibleMessages(t *testing.T) {
+	r := NewRenderer(nil)
+	r.SetAccessible(true)
+	r.SetViewportWidth(80, false)
+
+	msg := r.FormatMessage("assistant", "Here you go:\n```go\nfmt.Println(\"hi\")\n```\n✅ Done")
+	for _, want := range []string{"[assistant] ", "[code start: go]", "fmt.Println(\"hi\")", "[code end]", "OK: Done"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("accessible message missing %q:\n%s", want, msg)
+		}
+	}
+	if strings.Contains(msg, "\x1b[") {
+		t.Error("accessible message should not contain ANSI escapes")
+	}
+
+	loading := r.FormatLoadingMessageWithProgress("Thinking...", "⠋", "3s")
+	if loading != "[working] Thinking... Press Escape to cancel." {
+		t.Errorf("accessible loading message = %q", loading)
+	}
+}
@@ -38,15 +38,20 @@ type approvalOption struct {
 
 // NewApprovalDialog creates a new approval dialog
 func NewApprovalDialog(request tools.ApprovalRequest, width, height int) *ApprovalDialog {
+	options := []approvalOption{
+		{"Approve Once", tools.PermissionOnce},
+		{"Always Approve (This Project)", tools.PermissionAlways},
+		{"Never (Block in This Project)", tools.PermissionNever},
+	}
+	// Paths outside the workspace are approved one call at a time
+	if len(request.OutsideWorkspace) > 0 {
+		options = append(options[:1], options[2:]...)
+	}
 	return &ApprovalDialog{
-		request: request,
-		width:   width,
-		height:  height,
-		options: []approvalOption{
-			{"Approve Once", tools.PermissionOnce},
-			{"Always Approve (This Project)", tools.PermissionAlways},
-			{"Never (Block in This Project)", tools.PermissionNever},
-		},
+		request:       request,
+		width:         width,
+		height:        height,
+		options:       options,
 		selectedIndex: 0,
 	}
 }
@@ -134,6 +139,23 @@ func (d *ApprovalDialog) View() string {
 	content.WriteString(descStyle.Render(d.request.Description))
 	content.WriteString("\n")
 
+	if d.request.Simulated {
+		content.WriteString("\nDry run: the call will only be simulated\n")
+	}
+
+	if len(d.request.OutsideWorkspace) > 0 {
+		content.WriteString("\n⚠️  Outside the workspace: " + strings.Join(d.request.OutsideWorkspace, ", ") + "\n")
+	}
+
+	// Capabilities granted to a sandboxed tool
+	if len(d.request.Capabilities) > 0 {
+		names := make([]string, len(d.request.Capabilities))
+		for i, c := range d.request.Capabilities {
+			names[i] = string(c)
+		}
+		content.WriteString("\nSandboxed, requests access to: " + strings.Join(names, ", ") + "\n")
+	}
+
 	// Parameters
 	if len(d.request.Arguments) > 0 {
 		content.WriteString("\nParameters:\n")
@@ -0,0 +1,96 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ChoicePicker shows the alternative answers to a prompt sent with
+// /send --n one at a time, so that only the chosen one is kept
+type ChoicePicker struct {
+	choices  []string
+	index    int  // Answer shown
+	plain    bool // No colors, for the plain and accessible UIs
+	viewport viewport.Model
+}
+
+// NewChoicePicker creates a picker for the alternative answers in choices
+func NewChoicePicker(choices []string, plain bool, width, height int) *ChoicePicker {
+	p := &ChoicePicker{choices: choices, plain: plain}
+	p.SetSize(width, height)
+	return p
+}
+
+// SetSize fits the picker to the terminal, leaving room for the header,
+// title and help lines
+func (p *ChoicePicker) SetSize(width, height int) {
+	p.viewport = viewport.New(max(width, 20), max(height-4, 3))
+	p.show()
+}
+
+// show puts the current answer in the viewport, wrapped to its width
+func (p *ChoicePicker) show() {
+	content := expandTabs(p.choices[p.index])
+	p.viewport.SetContent(lipgloss.NewStyle().Width(p.viewport.Width).Render(content))
+	p.viewport.GotoTop()
+}
+
+// Update handles a key. When the picker closes it returns true, with the
+// index of the chosen answer, or -1 if the user cancelled.
+func (p *ChoicePicker) Update(msg tea.KeyMsg) (bool, int) {
+	switch key := msg.String(); key {
+	case "esc":
+		return true, -1
+	case "enter":
+		return true, p.index
+	case "k", "left":
+		if p.index > 0 {
+			p.index--
+			p.show()
+		}
+	case "j", "right", "tab":
+		if p.index < len(p.choices)-1 {
+			p.index++
+			p.show()
+		}
+	default:
+		// A digit shows that answer
+		if n, err := strconv.Atoi(key); err == nil && n >= 1 && n <= len(p.choices) {
+			p.index = n - 1
+			p.show()
+			return false, -1
+		}
+		p.viewport, _ = p.viewport.Update(msg)
+	}
+	return false, -1
+}
+
+// View renders the picker
+func (p *ChoicePicker) View() string {
+	title := fmt.Sprintf("Answer %d/%d", p.index+1, len(p.choices))
+	help := "Enter: Keep this answer • j/k or 1-9: Other answers • ↑/↓: Scroll • Esc: Keep none"
+	if p.plain {
+		return fmt.Sprintf("== %s ==\n%s\n%s", title, p.viewport.View(), help)
+	}
+
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("226"))
+	helpStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	return fmt.Sprintf("%s\n%s\n%s", titleStyle.Render("🎲 "+title), p.viewport.View(), helpStyle.Render(help))
+}
@@ -0,0 +1,45 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestChoicePicker(t *testing.T) {
+	p := NewChoicePicker([]string{"first idea", "second idea", "third idea"}, true, 80, 20)
+
+	if view := p.View(); !strings.Contains(view, "Answer 1/3") || !strings.Contains(view, "first idea") {
+		t.Errorf("expected the first answer:\n%s", p.View())
+	}
+	if done, _ := p.Update(key("k")); done {
+		t.Error("expected k on the first answer to keep the picker open")
+	}
+	p.Update(key("j"))
+	if view := p.View(); !strings.Contains(view, "Answer 2/3") || !strings.Contains(view, "second idea") {
+		t.Errorf("expected j to show the second answer:\n%s", view)
+	}
+	p.Update(key("3"))
+	if !strings.Contains(p.View(), "third idea") {
+		t.Errorf("expected 3 to show the third answer:\n%s", p.View())
+	}
+	p.Update(key("j"))
+	if done, chosen := p.Update(key("enter")); !done || chosen != 2 {
+		t.Errorf("Update(enter) = %v, %d, want true, 2", done, chosen)
+	}
+	if done, chosen := p.Update(key("esc")); !done || chosen != -1 {
+		t.Errorf("Update(esc) = %v, %d, want true, -1", done, chosen)
+	}
+}
@@ -0,0 +1,247 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/antenore/deecli/internal/config"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Config scopes the editor can save to
+const (
+	ScopeGlobal  = "global"
+	ScopeProject = "project"
+)
+
+// ConfigEditResult is what the config editor saves
+type ConfigEditResult struct {
+	Config  config.Config
+	Scope   string
+	Changed []string // Keys of the fields that changed
+}
+
+// ConfigEditor is a form listing the editable settings with their current
+// values. Values are validated as they are entered and saved to the global
+// or project config together.
+type ConfigEditor struct {
+	fields   []config.Field
+	cfg      config.Config // Working copy
+	original config.Config
+	selected int
+	editing  bool
+	input    string
+	errors   map[string]string // Validation error by field key
+	scope    string
+	width    int
+	height   int
+}
+
+// NewConfigEditor creates an editor for cfg that saves to scope by default
+func NewConfigEditor(cfg config.Config, scope string, width, height int) *ConfigEditor {
+	return &ConfigEditor{
+		fields:   config.Fields(),
+		cfg:      cfg,
+		original: cfg,
+		errors:   make(map[string]string),
+		scope:    scope,
+		width:    width,
+		height:   height,
+	}
+}
+
+// Update handles a key; it returns done when the editor closes, with the
+// result to save or nil when cancelled
+func (e *ConfigEditor) Update(msg tea.KeyMsg) (bool, *ConfigEditResult) {
+	if e.editing {
+		e.updateInput(msg)
+		return false, nil
+	}
+
+	field := e.fields[e.selected]
+	switch msg.String() {
+	case "up", "k":
+		e.selected = (e.selected + len(e.fields) - 1) % len(e.fields)
+	case "down", "j":
+		e.selected = (e.selected + 1) % len(e.fields)
+	case "left", "h":
+		e.cycle(field, -1)
+	case "right", "l", " ":
+		e.cycle(field, 1)
+	case "enter":
+		switch field.Kind {
+		case config.FieldBool, config.FieldChoice:
+			e.cycle(field, 1)
+		default:
+			e.editing = true
+			e.input = field.Get(&e.cfg)
+			if field.Secret {
+				e.input = ""
+			}
+		}
+	case "tab":
+		if e.scope == ScopeGlobal {
+			e.scope = ScopeProject
+		} else {
+			e.scope = ScopeGlobal
+		}
+	case "ctrl+s":
+		return true, &ConfigEditResult{Config: e.cfg, Scope: e.scope, Changed: e.changed()}
+	case "esc", "q":
+		return true, nil
+	}
+	return false, nil
+}
+
+// updateInput edits the value of the selected field
+func (e *ConfigEditor) updateInput(msg tea.KeyMsg) {
+	field := e.fields[e.selected]
+	switch msg.Type {
+	case tea.KeyEnter:
+		if err := field.Set(&e.cfg, e.input); err != nil {
+			e.errors[field.Key] = err.Error()
+			return
+		}
+		delete(e.errors, field.Key)
+		e.editing = false
+	case tea.KeyEsc:
+		delete(e.errors, field.Key)
+		e.editing = false
+	case tea.KeyBackspace:
+		if runes := []rune(e.input); len(runes) > 0 {
+			e.input = string(runes[:len(runes)-1])
+		}
+	case tea.KeyCtrlU:
+		e.input = ""
+	case tea.KeySpace:
+		e.input += " "
+	case tea.KeyRunes:
+		e.input += string(msg.Runes)
+	}
+}
+
+// cycle moves a boolean or choice field to its next or previous value
+func (e *ConfigEditor) cycle(field config.Field, step int) {
+	var options []string
+	switch field.Kind {
+	case config.FieldBool:
+		options = []string{"false", "true"}
+	case config.FieldChoice:
+		options = field.Options
+	default:
+		return
+	}
+
+	current := 0
+	for i, option := range options {
+		if option == field.Get(&e.cfg) {
+			current = i
+		}
+	}
+	next := options[(current+step+len(options))%len(options)]
+	if err := field.Set(&e.cfg, next); err != nil {
+		e.errors[field.Key] = err.Error()
+	} else {
+		delete(e.errors, field.Key)
+	}
+}
+
+// changed lists the keys whose value differs from the starting config
+func (e *ConfigEditor) changed() []string {
+	var keys []string
+	for _, field := range e.fields {
+		if field.Get(&e.cfg) != field.Get(&e.original) {
+			keys = append(keys, field.Key)
+		}
+	}
+	return keys
+}
+
+// View renders the editor
+func (e *ConfigEditor) View() string {
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("226"))
+	keyStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("87"))
+	descStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
+	selectedStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("226")).Background(lipgloss.Color("235"))
+	errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+	helpStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	borderStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("240")).
+		Padding(1, 2).
+		MaxWidth(e.width - 4)
+
+	var content strings.Builder
+	content.WriteString(titleStyle.Render("⚙️ Configuration"))
+	content.WriteString(fmt.Sprintf("   Save to: %s config (Tab to switch)\n\n", e.scope))
+
+	keyWidth := 0
+	for _, field := range e.fields {
+		keyWidth = max(keyWidth, len(field.Key))
+	}
+
+	// Keep the selected field visible when the form is taller than the screen
+	first, last := e.visibleRange()
+	if first > 0 {
+		content.WriteString(descStyle.Render("  ↑ more") + "\n")
+	}
+	for i := first; i < last; i++ {
+		field := e.fields[i]
+		value := field.Display(&e.cfg)
+		if e.editing && i == e.selected {
+			value = e.input + "█"
+		} else if value == "" {
+			value = "(default)"
+		}
+		if field.Get(&e.cfg) != field.Get(&e.original) {
+			value += " *"
+		}
+
+		line := fmt.Sprintf("%-*s  %s", keyWidth, field.Key, value)
+		if i == e.selected {
+			content.WriteString(selectedStyle.Render("▶ "+line) + "\n")
+			content.WriteString(descStyle.Render("    "+field.Description) + "\n")
+		} else {
+			content.WriteString("  " + keyStyle.Render(fmt.Sprintf("%-*s", keyWidth, field.Key)) + "  " + value + "\n")
+		}
+		if err, ok := e.errors[field.Key]; ok {
+			content.WriteString(errorStyle.Render("    ✗ "+err) + "\n")
+		}
+	}
+	if last < len(e.fields) {
+		content.WriteString(descStyle.Render("  ↓ more") + "\n")
+	}
+
+	help := "↑/↓: Navigate • Enter: Edit • ←/→: Change option • Tab: Scope • Ctrl+S: Save • Esc: Cancel"
+	if e.editing {
+		help = "Enter: Confirm • Esc: Discard • Ctrl+U: Clear"
+	}
+	content.WriteString("\n" + helpStyle.Render(help))
+
+	return borderStyle.Render(content.String())
+}
+
+// visibleRange returns the fields that fit on screen around the selection
+func (e *ConfigEditor) visibleRange() (int, int) {
+	// Title, borders, padding, description and help take about 12 lines
+	rows := max(e.height-12, 5)
+	if rows >= len(e.fields) {
+		return 0, len(e.fields)
+	}
+	first := min(max(e.selected-rows/2, 0), len(e.fields)-rows)
+	return first, first + rows
+}
@@ -0,0 +1,111 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/antenore/deecli/internal/config"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func key(s string) tea.KeyMsg {
+	switch s {
+	case "enter":
+		return tea.KeyMsg{Type: tea.KeyEnter}
+	case "esc":
+		return tea.KeyMsg{Type: tea.KeyEsc}
+	case "tab":
+		return tea.KeyMsg{Type: tea.KeyTab}
+	case "down":
+		return tea.KeyMsg{Type: tea.KeyDown}
+	case "ctrl+u":
+		return tea.KeyMsg{Type: tea.KeyCtrlU}
+	case "ctrl+s":
+		return tea.KeyMsg{Type: tea.KeyCtrlS}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+// selectField moves the editor selection to the field with the given key
+func selectField(t *testing.T, e *ConfigEditor, name string) {
+	t.Helper()
+	for range e.fields {
+		if e.fields[e.selected].Key == name {
+			return
+		}
+		e.Update(key("down"))
+	}
+	t.Fatalf("field %s not found", name)
+}
+
+func TestConfigEditor(t *testing.T) {
+	cfg := config.Config{Model: "deepseek-chat", Temperature: 0.1, MaxTokens: 2048, AutoReloadFiles: true}
+	e := NewConfigEditor(cfg, ScopeGlobal, 100, 60)
+
+	// An invalid value is rejected inline and the field stays in edit mode
+	selectField(t, e, "temperature")
+	e.Update(key("enter"))
+	e.Update(key("ctrl+u"))
+	e.Update(key("5"))
+	e.Update(key("enter"))
+	if !e.editing || !strings.Contains(e.View(), "temperature must be between") {
+		t.Fatal("expected the invalid temperature to be reported inline")
+	}
+	e.Update(key("ctrl+u"))
+	e.Update(key("0.5"))
+	e.Update(key("enter"))
+	if e.editing || e.cfg.Temperature != 0.5 {
+		t.Fatalf("temperature = %v, editing = %v", e.cfg.Temperature, e.editing)
+	}
+
+	// Booleans and choices toggle in place
+	selectField(t, e, "auto-reload-files")
+	e.Update(key("enter"))
+	selectField(t, e, "model")
+	e.Update(key("enter"))
+
+	e.Update(key("tab"))
+	done, result := e.Update(key("ctrl+s"))
+	if !done || result == nil {
+		t.Fatal("expected ctrl+s to save")
+	}
+	if result.Scope != ScopeProject {
+		t.Errorf("Scope = %s, want project after tab", result.Scope)
+	}
+	if result.Config.AutoReloadFiles || result.Config.Model != "deepseek-reasoner" {
+		t.Errorf("Config = %+v", result.Config)
+	}
+	for _, want := range []string{"model", "temperature", "auto-reload-files"} {
+		if !slices.Contains(result.Changed, want) {
+			t.Errorf("Changed = %v, missing %s", result.Changed, want)
+		}
+	}
+	if len(result.Changed) != 3 {
+		t.Errorf("Changed = %v, want 3 keys", result.Changed)
+	}
+}
+
+func TestConfigEditor_Cancel(t *testing.T) {
+	e := NewConfigEditor(config.Config{}, ScopeGlobal, 100, 20)
+	e.Update(key("enter")) // Start editing the first field
+	if done, _ := e.Update(key("esc")); done {
+		t.Fatal("esc while editing should only discard the value")
+	}
+	if done, result := e.Update(key("esc")); !done || result != nil {
+		t.Fatal("esc should close the editor without saving")
+	}
+}
@@ -0,0 +1,248 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/antenore/deecli/internal/files"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// FileViewer is a read-only, scrollable view of a file with line numbers.
+// It shows the file without loading it into the context. In compare mode it
+// shows the loaded context and the file on disk side by side instead.
+type FileViewer struct {
+	path     string
+	lines    []string     // Highlighted lines, without numbers
+	rows     []compareRow // Side-by-side rows in compare mode
+	changes  []int        // First row of each block of changes
+	plain    bool         // No colors, for the plain and accessible UIs
+	width    int
+	viewport viewport.Model
+}
+
+// compareRow pairs a line of the loaded context with a line on disk. A line
+// number of 0 means that side has no line there.
+type compareRow struct {
+	oldNum, newNum int
+	old, new       string
+	changed        bool
+}
+
+// NewFileViewer creates a viewer for content, scrolled so that line (1-based)
+// is at the top when it is greater than zero. Plain viewers are not highlighted.
+func NewFileViewer(path, content string, line int, plain bool, width, height int) *FileViewer {
+	content = strings.TrimRight(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	content = expandTabs(content)
+	if !plain {
+		// Chroma finds the lexer from the file name when given one
+		content = HighlightCode(content, filepath.Base(path), true)
+	}
+
+	v := &FileViewer{
+		path:  path,
+		lines: strings.Split(content, "\n"),
+		plain: plain,
+	}
+	v.SetSize(width, height)
+	if line > 1 {
+		v.viewport.SetYOffset(line - 1)
+	}
+	return v
+}
+
+// NewCompareViewer creates a viewer showing the loaded content of a file next
+// to its content on disk, scrolled to the first difference
+func NewCompareViewer(path, loaded, disk string, plain bool, width, height int) *FileViewer {
+	v := &FileViewer{path: path, plain: plain}
+	diff := files.DiffLines(expandTabs(loaded), expandTabs(disk))
+	for i := 0; i < len(diff); {
+		if diff[i].Op == files.LineEqual {
+			v.rows = append(v.rows, compareRow{oldNum: diff[i].OldNum, newNum: diff[i].NewNum, old: diff[i].Text, new: diff[i].Text})
+			i++
+			continue
+		}
+
+		// Pair a run of deleted lines with the inserted lines that follow it
+		var deleted, inserted []files.DiffLine
+		for ; i < len(diff) && diff[i].Op == files.LineDeleted; i++ {
+			deleted = append(deleted, diff[i])
+		}
+		for ; i < len(diff) && diff[i].Op == files.LineInserted; i++ {
+			inserted = append(inserted, diff[i])
+		}
+		v.changes = append(v.changes, len(v.rows))
+		for j := 0; j < max(len(deleted), len(inserted)); j++ {
+			row := compareRow{changed: true}
+			if j < len(deleted) {
+				row.oldNum, row.old = deleted[j].OldNum, deleted[j].Text
+			}
+			if j < len(inserted) {
+				row.newNum, row.new = inserted[j].NewNum, inserted[j].Text
+			}
+			v.rows = append(v.rows, row)
+		}
+	}
+
+	v.SetSize(width, height)
+	if len(v.changes) > 0 {
+		v.viewport.SetYOffset(v.changes[0])
+	}
+	return v
+}
+
+// SetSize fits the viewer to the terminal, leaving room for the header,
+// title and help lines
+func (v *FileViewer) SetSize(width, height int) {
+	offset := v.viewport.YOffset
+	v.width = max(width, 20)
+	v.viewport = viewport.New(v.width, max(height-4, 3))
+	v.viewport.SetContent(v.content())
+	v.viewport.SetYOffset(offset)
+}
+
+// Update handles a key; it returns true when the viewer closes
+func (v *FileViewer) Update(msg tea.KeyMsg) bool {
+	switch msg.String() {
+	case "esc", "q":
+		return true
+	case "g", "home":
+		v.viewport.GotoTop()
+	case "G", "end":
+		v.viewport.GotoBottom()
+	case "n":
+		for _, row := range v.changes {
+			if row > v.viewport.YOffset {
+				v.viewport.SetYOffset(row)
+				break
+			}
+		}
+	case "N":
+		for i := len(v.changes) - 1; i >= 0; i-- {
+			if v.changes[i] < v.viewport.YOffset {
+				v.viewport.SetYOffset(v.changes[i])
+				break
+			}
+		}
+	default:
+		v.viewport, _ = v.viewport.Update(msg)
+	}
+	return false
+}
+
+// View renders the viewer
+func (v *FileViewer) View() string {
+	total := len(v.lines)
+	title := fmt.Sprintf("%s (%d lines)", v.path, total)
+	help := "↑/↓/PgUp/PgDn: Scroll • g/G: Top/Bottom • Esc/q: Close"
+	if v.rows != nil {
+		total = len(v.rows)
+		title = fmt.Sprintf("%s: loaded context ← → disk (%d changes)", v.path, len(v.changes))
+		help = "↑/↓/PgUp/PgDn: Scroll • n/N: Next/Previous change • Esc/q: Close"
+	}
+	position := fmt.Sprintf("%d-%d/%d", v.viewport.YOffset+1, min(v.viewport.YOffset+v.viewport.Height, total), total)
+	if v.plain {
+		return fmt.Sprintf("== %s ==\n%s\n%s | %s", title, v.viewport.View(), position, help)
+	}
+
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("226"))
+	helpStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	return fmt.Sprintf("%s\n%s\n%s", titleStyle.Render("📄 "+title), v.viewport.View(), helpStyle.Render(position+" • "+help))
+}
+
+// content renders the lines shown in the viewport
+func (v *FileViewer) content() string {
+	if v.rows != nil {
+		return v.compareContent()
+	}
+	return v.numberedContent()
+}
+
+// numberedContent prefixes each line with its right-aligned line number
+func (v *FileViewer) numberedContent() string {
+	digits := len(fmt.Sprint(len(v.lines)))
+	numberStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+
+	var content strings.Builder
+	for i, line := range v.lines {
+		number := fmt.Sprintf("%*d │ ", digits, i+1)
+		if v.plain {
+			number = fmt.Sprintf("%*d | ", digits, i+1)
+		} else {
+			number = numberStyle.Render(number)
+		}
+		content.WriteString(number + line)
+		if i < len(v.lines)-1 {
+			content.WriteString("\n")
+		}
+	}
+	return content.String()
+}
+
+// compareContent renders the rows as two columns, marking changed lines
+// with - on the context side and + on the disk side
+func (v *FileViewer) compareContent() string {
+	digits := 1
+	for _, row := range v.rows {
+		digits = max(digits, len(fmt.Sprint(max(row.oldNum, row.newNum))))
+	}
+	// Each column holds the number, a marker and the text; " │ " separates them
+	textWidth := max((v.width-3)/2-digits-3, 1)
+
+	separator := " │ "
+	if v.plain {
+		separator = " | "
+	}
+	oldStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("203"))
+	newStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("114"))
+
+	var content strings.Builder
+	for i, row := range v.rows {
+		left := compareCell(row.oldNum, row.old, "-", row.changed, digits, textWidth)
+		right := compareCell(row.newNum, row.new, "+", row.changed, digits, textWidth)
+		if row.changed && !v.plain {
+			left, right = oldStyle.Render(left), newStyle.Render(right)
+		}
+		content.WriteString(left + separator + right)
+		if i < len(v.rows)-1 {
+			content.WriteString("\n")
+		}
+	}
+	return content.String()
+}
+
+// compareCell formats one side of a compare row, padded to a fixed width
+func compareCell(num int, text, marker string, changed bool, digits, width int) string {
+	if num == 0 {
+		return strings.Repeat(" ", digits+3+width)
+	}
+	if !changed {
+		marker = " "
+	}
+	runes := []rune(text)
+	if len(runes) > width {
+		runes = append(runes[:width-1], '…')
+	}
+	return fmt.Sprintf("%*d %s %-*s", digits, num, marker, width, string(runes))
+}
+
+// expandTabs replaces tabs so that lines keep their width in the viewport
+func expandTabs(content string) string {
+	return strings.ReplaceAll(content, "\t", "    ")
+}
@@ -0,0 +1,124 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestFileViewer(t *testing.T) {
+	var content strings.Builder
+	for i := 1; i <= 50; i++ {
+		content.WriteString(fmt.Sprintf("line %d\n", i))
+	}
+	v := NewFileViewer("notes.txt", content.String(), 20, true, 80, 14)
+
+	view := v.View()
+	if !strings.Contains(view, "notes.txt (50 lines)") {
+		t.Errorf("expected a title with the line count:\n%s", view)
+	}
+	if !strings.Contains(view, "20 | line 20") || strings.Contains(view, "19 | line 19") {
+		t.Errorf("expected the view to start at line 20:\n%s", view)
+	}
+
+	v.Update(key("G"))
+	if !strings.Contains(v.View(), "50 | line 50") {
+		t.Error("expected G to scroll to the last line")
+	}
+	v.Update(key("g"))
+	if view := v.View(); !strings.Contains(view, " 1 | line 1") || strings.Contains(view, "line 20") {
+		t.Error("expected g to scroll to the first line")
+	}
+
+	if v.Update(key("j")) {
+		t.Error("expected other keys to keep the viewer open")
+	}
+	if !v.Update(key("esc")) {
+		t.Error("expected esc to close the viewer")
+	}
+}
+
+func TestCompareViewer(t *testing.T) {
+	loaded := "package main\n\nfunc old() {}\n\nfunc main() {}\n"
+	disk := "package main\n\nfunc renamed() {}\nfunc extra() {}\n\nfunc main() {}\n"
+	v := NewCompareViewer("main.go", loaded, disk, true, 100, 20)
+
+	view := v.View()
+	if !strings.Contains(view, "main.go: loaded context ← → disk (1 changes)") {
+		t.Errorf("unexpected title:\n%s", view)
+	}
+	if !strings.Contains(view, "3 - func old() {}") || !strings.Contains(view, "3 + func renamed() {}") || !strings.Contains(view, "4 + func extra() {}") {
+		t.Errorf("expected the changed lines side by side:\n%s", view)
+	}
+	if !strings.Contains(view, "1   package main") {
+		t.Errorf("expected unchanged lines without markers:\n%s", view)
+	}
+
+	// The viewer opens at the first change; n and N move between changes
+	var before, after strings.Builder
+	for i := 1; i <= 40; i++ {
+		before.WriteString(fmt.Sprintf("line %d\n", i))
+		switch i {
+		case 10, 30:
+			after.WriteString(fmt.Sprintf("changed %d\n", i))
+		default:
+			after.WriteString(fmt.Sprintf("line %d\n", i))
+		}
+	}
+	v = NewCompareViewer("notes.txt", before.String(), after.String(), true, 100, 14)
+	if v.viewport.YOffset != 9 {
+		t.Errorf("expected to start at the first change, got offset %d", v.viewport.YOffset)
+	}
+	v.Update(key("n"))
+	if v.viewport.YOffset != 29 {
+		t.Errorf("expected n to move to the next change, got offset %d", v.viewport.YOffset)
+	}
+	v.Update(key("N"))
+	if v.viewport.YOffset != 9 {
+		t.Errorf("expected N to move back to the previous change, got offset %d", v.viewport.YOffset)
+	}
+}
+
+func TestHunkPicker(t *testing.T) {
+	hunks := []string{"@@ -1 +1 @@\n-a\n+A", "@@ -3 +3 @@\n-c\n+C", "@@ -5 +5 @@\n-e\n+E"}
+	p := NewHunkPicker("letters.txt", hunks, true, 80, 20)
+	if view := p.View(); !strings.Contains(view, "letters.txt: hunk 1/3 (undecided)") || !strings.Contains(view, "+A") {
+		t.Errorf("expected the first hunk:\n%s", view)
+	}
+
+	if done, _ := p.Update(key("y")); done {
+		t.Fatal("expected the picker to move to the next hunk")
+	}
+	p.Update(key("n"))
+	p.Update(key("k"))
+	if !strings.Contains(p.View(), "hunk 2/3 (skip)") {
+		t.Errorf("expected k to go back to the skipped hunk:\n%s", p.View())
+	}
+	p.Update(key("j"))
+	done, accept := p.Update(key("y"))
+	if !done || len(accept) != 3 || !accept[0] || accept[1] || !accept[2] {
+		t.Errorf("expected hunks 1 and 3 accepted, got %v (done %v)", accept, done)
+	}
+
+	p = NewHunkPicker("letters.txt", hunks, true, 80, 20)
+	p.Update(key("y"))
+	if done, accept := p.Update(key("d")); !done || !accept[0] || accept[1] || accept[2] {
+		t.Errorf("expected d to skip the rest, got %v", accept)
+	}
+	if done, accept := NewHunkPicker("letters.txt", hunks, true, 80, 20).Update(key("esc")); !done || accept != nil {
+		t.Error("expected esc to cancel")
+	}
+}
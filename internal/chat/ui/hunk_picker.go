@@ -0,0 +1,148 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// HunkPicker steps through the hunks of a suggested diff one at a time, like
+// git add -p, so that only the accepted ones are written to the file
+type HunkPicker struct {
+	path     string
+	hunks    []string
+	accept   []bool
+	decided  []bool
+	index    int  // Hunk shown
+	plain    bool // No colors, for the plain and accessible UIs
+	viewport viewport.Model
+}
+
+// NewHunkPicker creates a picker for the hunks of a diff of path, as
+// returned by files.PatchHunks
+func NewHunkPicker(path string, hunks []string, plain bool, width, height int) *HunkPicker {
+	p := &HunkPicker{
+		path:    path,
+		hunks:   hunks,
+		accept:  make([]bool, len(hunks)),
+		decided: make([]bool, len(hunks)),
+		plain:   plain,
+	}
+	p.SetSize(width, height)
+	return p
+}
+
+// SetSize fits the picker to the terminal, leaving room for the header,
+// title and help lines
+func (p *HunkPicker) SetSize(width, height int) {
+	p.viewport = viewport.New(max(width, 20), max(height-4, 3))
+	p.show()
+}
+
+// show puts the current hunk in the viewport
+func (p *HunkPicker) show() {
+	added := lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
+	removed := lipgloss.NewStyle().Foreground(lipgloss.Color("203"))
+	header := lipgloss.NewStyle().Foreground(lipgloss.Color("39"))
+
+	lines := strings.Split(expandTabs(p.hunks[p.index]), "\n")
+	if !p.plain {
+		for i, line := range lines {
+			switch {
+			case strings.HasPrefix(line, "@@"):
+				lines[i] = header.Render(line)
+			case strings.HasPrefix(line, "+"):
+				lines[i] = added.Render(line)
+			case strings.HasPrefix(line, "-"):
+				lines[i] = removed.Render(line)
+			}
+		}
+	}
+	p.viewport.SetContent(strings.Join(lines, "\n"))
+	p.viewport.GotoTop()
+}
+
+// Update handles a key. When the picker closes it returns true, with the
+// hunks to apply, or with nil if the user cancelled.
+func (p *HunkPicker) Update(msg tea.KeyMsg) (bool, []bool) {
+	switch msg.String() {
+	case "esc":
+		return true, nil
+	case "q":
+		// Apply what was accepted so far and skip the rest
+		return true, p.accept
+	case "y":
+		return p.decide(true)
+	case "n":
+		return p.decide(false)
+	case "a", "d":
+		for i := p.index; i < len(p.hunks); i++ {
+			p.accept[i] = msg.String() == "a"
+		}
+		return true, p.accept
+	case "k", "left":
+		if p.index > 0 {
+			p.index--
+			p.show()
+		}
+	case "j", "right":
+		if p.index < len(p.hunks)-1 {
+			p.index++
+			p.show()
+		}
+	default:
+		p.viewport, _ = p.viewport.Update(msg)
+	}
+	return false, nil
+}
+
+// decide records the choice for the current hunk and moves to the next
+// undecided one, closing the picker once every hunk has a choice
+func (p *HunkPicker) decide(accept bool) (bool, []bool) {
+	p.accept[p.index], p.decided[p.index] = accept, true
+	for i := range p.hunks {
+		next := (p.index + 1 + i) % len(p.hunks)
+		if !p.decided[next] {
+			p.index = next
+			p.show()
+			return false, nil
+		}
+	}
+	return true, p.accept
+}
+
+// View renders the picker
+func (p *HunkPicker) View() string {
+	status := "undecided"
+	if p.decided[p.index] {
+		status = "skip"
+		if p.accept[p.index] {
+			status = "apply"
+		}
+	}
+	title := fmt.Sprintf("%s: hunk %d/%d (%s)", p.path, p.index+1, len(p.hunks), status)
+	help := "y: Apply • n: Skip • a/d: Apply/Skip this and the rest • j/k: Next/Previous • q: Done • Esc: Cancel"
+	if p.plain {
+		return fmt.Sprintf("== %s ==\n%s\n%s", title, p.viewport.View(), help)
+	}
+
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("226"))
+	helpStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	return fmt.Sprintf("%s\n%s\n%s", titleStyle.Render("🧩 "+title), p.viewport.View(), helpStyle.Render(help))
+}
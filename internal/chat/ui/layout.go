@@ -24,7 +24,11 @@ import (
 
 // Layout handles terminal layout calculations and header rendering
 type Layout struct {
-	configManager *config.Manager
+	configManager  *config.Manager
+	plain          bool   // Lightweight UI: no sidebar, borders or colors
+	newMentions    int    // Files newly mentioned by the AI, shown in the header
+	connection     string // API connection status shown in the header, e.g. "312ms"
+	contextWarning string // Warning shown in the header while the prompt nears max_context_size
 }
 
 // NewLayout creates a new layout manager
@@ -32,6 +36,32 @@ func NewLayout(configManager *config.Manager) *Layout {
 	return &Layout{configManager: configManager}
 }
 
+// SetPlain switches the lightweight plain UI on or off
+func (l *Layout) SetPlain(plain bool) {
+	l.plain = plain
+}
+
+// SetNewMentions sets how many newly mentioned files the header points out
+func (l *Layout) SetNewMentions(count int) {
+	l.newMentions = count
+}
+
+// SetConnection sets the API connection status shown in the header; empty hides it
+func (l *Layout) SetConnection(status string) {
+	l.connection = status
+}
+
+// SetContextWarning sets the warning the header shows while the prompt nears
+// its size limit; empty hides it
+func (l *Layout) SetContextWarning(warning string) {
+	l.contextWarning = warning
+}
+
+// IsPlain returns whether the plain UI is active
+func (l *Layout) IsPlain() bool {
+	return l.plain
+}
+
 // CalculateViewportDimensions calculates viewport height and positioning
 func (l *Layout) CalculateViewportDimensions(terminalHeight int, showCompletions bool) (height, yPosition int) {
 	// Calculate available space
@@ -56,7 +86,7 @@ func (l *Layout) CalculateViewportDimensions(terminalHeight int, showCompletions
 // CalculateTextareaWidth calculates textarea width based on layout
 func (l *Layout) CalculateTextareaWidth(terminalWidth int, sidebarVisible bool) int {
 	textareaWidth := terminalWidth - 4
-	if sidebarVisible {
+	if sidebarVisible && !l.plain {
 		textareaWidth = terminalWidth - 30 // Account for sidebar
 	}
 	if textareaWidth < 20 {
@@ -66,7 +96,11 @@ func (l *Layout) CalculateTextareaWidth(terminalWidth int, sidebarVisible bool)
 }
 
 // RenderHeader creates the application header with context information
-func (l *Layout) RenderHeader(filesCount int, focusMode string, fileContext *files.FileContext, renderer *Renderer) string {
+func (l *Layout) RenderHeader(filesCount int, focusMode string, fileContext *files.FileContext, renderer *Renderer, progress string) string {
+	if l.plain {
+		return l.renderPlainHeader(filesCount, focusMode, fileContext, progress)
+	}
+
 	headerStyle := lipgloss.NewStyle().
 		Background(lipgloss.Color("62")).
 		Foreground(lipgloss.Color("230")).
@@ -99,10 +133,7 @@ func (l *Layout) RenderHeader(filesCount int, focusMode string, fileContext *fil
 		// Get config for limits
 		maxContextSize := 100000 // Default
 		if l.configManager != nil {
-			cfg := l.configManager.Get()
-			if cfg != nil && cfg.MaxContextSize > 0 {
-				maxContextSize = cfg.MaxContextSize
-			}
+			maxContextSize = l.configManager.GetMaxContextSize()
 		}
 
 		usagePercent := fileContext.GetContextUsagePercent(maxContextSize)
@@ -134,15 +165,67 @@ func (l *Layout) RenderHeader(filesCount int, focusMode string, fileContext *fil
 		rawModeIndicator = " RAW"
 	}
 
-	header := headerStyle.Render(fmt.Sprintf("DeeCLI | F: %d%s | NL: %s | F1 | F2 | F3%s | Tab%s",
-		filesCount, contextInfo, newlineKeyDisplay, rawModeIndicator, focusIndicator))
+	// Files mentioned by the AI that /mentioned has not listed yet
+	mentionsInfo := ""
+	if l.newMentions > 0 {
+		mentionsInfo = fmt.Sprintf(" | 📎 %d", l.newMentions)
+	}
+
+	connectionInfo := ""
+	if l.connection != "" {
+		connectionInfo = " | 🔌 " + l.connection
+	}
+
+	// Add streaming progress (elapsed time and throughput)
+	progressInfo := ""
+	if progress != "" {
+		progressInfo = " | ⚡ " + progress
+	}
+
+	// Context warning first, so a narrow terminal does not cut it off
+	warningInfo := ""
+	if l.contextWarning != "" {
+		warningInfo = " | ⚠️ " + l.contextWarning
+	}
+
+	header := headerStyle.Render(fmt.Sprintf("DeeCLI%s | F: %d%s | NL: %s | F1 | F2 | F3%s | Tab%s%s%s%s",
+		warningInfo, filesCount, contextInfo, newlineKeyDisplay, rawModeIndicator, focusIndicator, mentionsInfo, connectionInfo, progressInfo))
 
 	return header
 }
 
+// renderPlainHeader creates a single uncolored header line for the plain UI
+func (l *Layout) renderPlainHeader(filesCount int, focusMode string, fileContext *files.FileContext, progress string) string {
+	parts := []string{"DeeCLI"}
+	if l.contextWarning != "" {
+		parts = append(parts, "warning: "+l.contextWarning)
+	}
+	parts = append(parts, fmt.Sprintf("files:%d", filesCount))
+	if fileContext != nil && filesCount > 0 {
+		maxContextSize := 100000
+		if l.configManager != nil {
+			maxContextSize = l.configManager.GetMaxContextSize()
+		}
+		parts = append(parts, fmt.Sprintf("ctx:%.0f%%", fileContext.GetContextUsagePercent(maxContextSize)))
+	}
+	if focusMode == "viewport" {
+		parts = append(parts, "scroll")
+	}
+	if l.newMentions > 0 {
+		parts = append(parts, fmt.Sprintf("mentioned:%d", l.newMentions))
+	}
+	if l.connection != "" {
+		parts = append(parts, "api:"+l.connection)
+	}
+	if progress != "" {
+		parts = append(parts, progress)
+	}
+	return strings.Join(parts, " | ")
+}
+
 // RenderMainContent creates the main content area with optional sidebar
 func (l *Layout) RenderMainContent(chatContent, sidebarContent string, terminalWidth int, sidebarVisible bool, focusMode string) string {
-	if !sidebarVisible {
+	if !sidebarVisible || l.plain {
 		// Single column: just the viewport
 		return chatContent
 	}
@@ -195,12 +278,23 @@ func (l *Layout) RenderFooter(inputContent string, completions []string, complet
 	var footerContent strings.Builder
 
 	// Separator
-	separator := strings.Repeat("─", terminalWidth)
+	separatorChar := "─"
+	if l.plain {
+		separatorChar = "-"
+	}
+	separator := strings.Repeat(separatorChar, terminalWidth)
 	footerContent.WriteString(separator + "\n")
 
 	// Input area
 	footerContent.WriteString(inputContent)
 
+	if l.plain {
+		if len(completions) > 0 {
+			footerContent.WriteString("\n" + l.renderPlainCompletions(completions, completionIndex))
+		}
+		return footerContent.String()
+	}
+
 	// Add completions if visible
 	if len(completions) > 0 {
 		completionStyle := lipgloss.NewStyle().
@@ -235,6 +329,24 @@ func (l *Layout) RenderFooter(inputContent string, completions []string, complet
 	return footerContent.String()
 }
 
+// renderPlainCompletions lists completions without styling, marking the selected one
+func (l *Layout) renderPlainCompletions(completions []string, completionIndex int) string {
+	var list strings.Builder
+	list.WriteString(fmt.Sprintf("(%d/%d)", completionIndex+1, len(completions)))
+	for i, comp := range completions {
+		if i >= 10 {
+			list.WriteString(fmt.Sprintf(" +%d", len(completions)-10))
+			break
+		}
+		if i == completionIndex {
+			list.WriteString(" [" + comp + "]")
+		} else {
+			list.WriteString(" " + comp)
+		}
+	}
+	return list.String()
+}
+
 // FormatKeyForDisplay formats a key string for user-friendly display
 func (l *Layout) FormatKeyForDisplay(key string) string {
 	if key == "" {
@@ -0,0 +1,64 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLayout_PlainMode(t *testing.T) {
+	layout := NewLayout(nil)
+	layout.SetPlain(true)
+
+	header := layout.RenderHeader(2, "input", nil, nil, "")
+	if header != "DeeCLI | files:2" {
+		t.Errorf("plain header = %q", header)
+	}
+	if strings.Contains(header, "\x1b[") {
+		t.Error("plain header should not contain ANSI escapes")
+	}
+
+	main := layout.RenderMainContent("chat", "sidebar", 50, true, "input")
+	if main != "chat" {
+		t.Errorf("plain main content should drop the sidebar, got %q", main)
+	}
+
+	if width := layout.CalculateTextareaWidth(50, true); width != 46 {
+		t.Errorf("plain textarea width = %d, want 46 (sidebar ignored)", width)
+	}
+
+	footer := layout.RenderFooter("> hi", []string{"/load", "/list"}, 1, 10)
+	if !strings.HasPrefix(footer, "----------\n") || !strings.Contains(footer, "[/list]") {
+		t.Errorf("plain footer = %q", footer)
+	}
+}
+
+func TestLayout_Connection(t *testing.T) {
+	layout := NewLayout(nil)
+	layout.SetConnection("312ms")
+	if header := layout.RenderHeader(0, "input", nil, nil, ""); !strings.Contains(header, "🔌 312ms") {
+		t.Errorf("header should show the connection status, got %q", header)
+	}
+
+	layout.SetPlain(true)
+	if header := layout.RenderHeader(0, "input", nil, nil, ""); header != "DeeCLI | files:0 | api:312ms" {
+		t.Errorf("plain header = %q", header)
+	}
+
+	layout.SetConnection("")
+	if header := layout.RenderHeader(0, "input", nil, nil, ""); strings.Contains(header, "api:") {
+		t.Errorf("empty status should be hidden, got %q", header)
+	}
+}
@@ -0,0 +1,188 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	markdownHeading = regexp.MustCompile(`^#{1,6}\s+\S`)
+	markdownBullet  = regexp.MustCompile(`^(\s*)[-*+]\s+(.*)$`)
+	// tableDelimiter is the row under a table header, e.g. |:---|---:|
+	tableDelimiter = regexp.MustCompile(`^\|?\s*:?-+:?\s*(\|\s*:?-+:?\s*)*\|?$`)
+)
+
+// minColumnWidth is how narrow a table column gets before the table is
+// left as written
+const minColumnWidth = 3
+
+// formatMarkdown lays out the Markdown of response text outside code blocks
+// for the terminal: tables get aligned columns that fit width, list items
+// get bullets and headings are highlighted. Heading text is kept as written,
+// markers included, so /sections finds it. Screen readers get the text
+// unchanged.
+func (r *Renderer) formatMarkdown(text string, width int) string {
+	if r.accessible {
+		return text
+	}
+
+	lines := strings.Split(text, "\n")
+	out := make([]string, 0, len(lines))
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		if isTableRow(line) && i+1 < len(lines) && tableDelimiter.MatchString(strings.TrimSpace(lines[i+1])) {
+			end := i + 2
+			for end < len(lines) && isTableRow(lines[end]) {
+				end++
+			}
+			if table, ok := r.formatTable(lines[i], lines[i+1], lines[i+2:end], width); ok {
+				out = append(out, table...)
+				i = end - 1
+				continue
+			}
+		}
+
+		switch {
+		case markdownHeading.MatchString(line):
+			if !r.plain {
+				line = lipgloss.NewStyle().Foreground(lipgloss.Color("75")).Bold(true).Render(line)
+			}
+		case markdownBullet.MatchString(line):
+			line = markdownBullet.ReplaceAllString(line, "$1• $2")
+		}
+		out = append(out, line)
+	}
+	return strings.Join(out, "\n")
+}
+
+// isTableRow reports whether line looks like a row of a pipe table
+func isTableRow(line string) bool {
+	line = strings.TrimSpace(line)
+	return strings.HasPrefix(line, "|") && strings.Count(line, "|") >= 2
+}
+
+// tableCells splits a table row into its trimmed cells
+func tableCells(row string) []string {
+	row = strings.TrimSpace(row)
+	row = strings.TrimPrefix(row, "|")
+	row = strings.TrimSuffix(row, "|")
+	cells := strings.Split(row, "|")
+	for i, cell := range cells {
+		cells[i] = strings.TrimSpace(cell)
+	}
+	return cells
+}
+
+// formatTable aligns a pipe table into columns that fit width, shrinking the
+// widest columns and cutting their cells short if needed. It reports false
+// when the columns cannot be made to fit.
+func (r *Renderer) formatTable(header, delimiter string, rows []string, width int) ([]string, bool) {
+	headerCells := tableCells(header)
+	columns := len(headerCells)
+
+	aligns := make([]lipgloss.Position, columns)
+	for i, spec := range tableCells(delimiter) {
+		if i >= columns {
+			break
+		}
+		switch {
+		case strings.HasPrefix(spec, ":") && strings.HasSuffix(spec, ":"):
+			aligns[i] = lipgloss.Center
+		case strings.HasSuffix(spec, ":"):
+			aligns[i] = lipgloss.Right
+		}
+	}
+
+	body := make([][]string, len(rows))
+	widths := make([]int, columns)
+	for i, cell := range headerCells {
+		widths[i] = lipgloss.Width(cell)
+	}
+	for i, row := range rows {
+		cells := tableCells(row)
+		// Rows with missing cells are padded, extra cells dropped
+		body[i] = make([]string, columns)
+		copy(body[i], cells)
+		for j, cell := range body[i] {
+			widths[j] = max(widths[j], lipgloss.Width(cell))
+		}
+	}
+
+	separator, rule, cross := " │ ", "─", "─┼─"
+	if r.plain {
+		separator, rule, cross = " | ", "-", "-+-"
+	}
+	available := width - lipgloss.Width(separator)*(columns-1)
+	for total(widths) > available {
+		widest := 0
+		for i, w := range widths {
+			if w > widths[widest] {
+				widest = i
+			}
+		}
+		if widths[widest] <= minColumnWidth {
+			return nil, false
+		}
+		widths[widest]--
+	}
+
+	formatRow := func(cells []string, style lipgloss.Style) string {
+		parts := make([]string, columns)
+		for i := range parts {
+			cell := truncateCell(cells[i], widths[i])
+			parts[i] = style.Width(widths[i]).Align(aligns[i]).Render(cell)
+		}
+		return strings.TrimRight(strings.Join(parts, separator), " ")
+	}
+
+	headerStyle := lipgloss.NewStyle()
+	if !r.plain {
+		headerStyle = headerStyle.Bold(true)
+	}
+	rules := make([]string, columns)
+	for i, w := range widths {
+		rules[i] = strings.Repeat(rule, w)
+	}
+
+	lines := []string{formatRow(headerCells, headerStyle), strings.Join(rules, cross)}
+	for _, cells := range body {
+		lines = append(lines, formatRow(cells, lipgloss.NewStyle()))
+	}
+	return lines, true
+}
+
+// truncateCell cuts cell to width, marking the cut with an ellipsis
+func truncateCell(cell string, width int) string {
+	if lipgloss.Width(cell) <= width {
+		return cell
+	}
+	runes := []rune(cell)
+	for len(runes) > 0 && lipgloss.Width(string(runes))+1 > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "…"
+}
+
+// total sums column widths
+func total(widths []int) int {
+	sum := 0
+	for _, w := range widths {
+		sum += w
+	}
+	return sum
+}
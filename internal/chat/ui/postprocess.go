@@ -0,0 +1,116 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/antenore/deecli/internal/config"
+)
+
+// postProcessors implements the response post-processors listed, in order,
+// by config.PostProcessors
+var postProcessors = map[string]func(r *Renderer, content string) string{
+	"strip-thinking":       func(r *Renderer, content string) string { return stripThinking(content) },
+	"collapse-blank-lines": func(r *Renderer, content string) string { return collapseBlankLines(content) },
+	"link-files": func(r *Renderer, content string) string {
+		// Screen readers and the plain UI get no escape sequences
+		if r.plain || r.accessible {
+			return content
+		}
+		return linkFileMentions(content)
+	},
+}
+
+// postProcess runs the enabled post-processors over an assistant response
+func (r *Renderer) postProcess(content string) string {
+	for _, processor := range config.PostProcessors {
+		enabled := processor.Default
+		if r.configManager != nil {
+			enabled = r.configManager.PostProcessorEnabled(processor.Name)
+		}
+		if process := postProcessors[processor.Name]; enabled && process != nil {
+			content = process(r, content)
+		}
+	}
+	return content
+}
+
+// thinkingBlock matches reasoning that some models inline in their answer
+var thinkingBlock = regexp.MustCompile(`(?is)<think(?:ing)?>.*?</think(?:ing)?>\s*`)
+
+// stripThinking removes closed <think> blocks; an open one is left alone so
+// that a response still streaming its reasoning stays visible
+func stripThinking(content string) string {
+	return thinkingBlock.ReplaceAllString(content, "")
+}
+
+// collapseBlankLines keeps at most one blank line in a row outside code blocks
+func collapseBlankLines(content string) string {
+	lines := strings.Split(content, "\n")
+	kept := lines[:0]
+	inCode := false
+	blank := false
+	for _, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inCode = !inCode
+		}
+		if !inCode && strings.TrimSpace(line) == "" {
+			if blank {
+				continue
+			}
+			blank = true
+			line = ""
+		} else {
+			blank = false
+		}
+		kept = append(kept, line)
+	}
+	return strings.Join(kept, "\n")
+}
+
+// fileMention matches relative paths with an extension and an optional line
+var fileMention = regexp.MustCompile(`(?:[\w.-]+/)*[\w-][\w.-]*\.[A-Za-z0-9]+(?::\d+)?`)
+
+// linkFileMentions wraps mentions of existing files outside code blocks in
+// OSC 8 hyperlinks, which many terminals open on click
+func linkFileMentions(content string) string {
+	lines := strings.Split(content, "\n")
+	inCode := false
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			continue
+		}
+		lines[i] = fileMention.ReplaceAllStringFunc(line, func(mention string) string {
+			path, _, _ := strings.Cut(mention, ":")
+			info, err := os.Stat(path)
+			if err != nil || !info.Mode().IsRegular() {
+				return mention
+			}
+			absPath, err := filepath.Abs(path)
+			if err != nil {
+				return mention
+			}
+			return "\x1b]8;;file://" + filepath.ToSlash(absPath) + "\x1b\\" + mention + "\x1b]8;;\x1b\\"
+		})
+	}
+	return strings.Join(lines, "\n")
+}
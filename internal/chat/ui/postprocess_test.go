@@ -0,0 +1,63 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestStripThinking(t *testing.T) {
+	got := stripThinking("<think>\nThe user wants X.\n</think>\n\nHere is X.")
+	if got != "Here is X." {
+		t.Errorf("stripThinking() = %q", got)
+	}
+
+	open := "<think>still reasoning"
+	if got := stripThinking(open); got != open {
+		t.Errorf("stripThinking() removed an open block: %q", got)
+	}
+}
+
+func TestCollapseBlankLines(t *testing.T) {
+	content := "One\n\n\n\nTwo\n```go\na := 1\n\n\nb := 2\n```\n  \n\nThree"
+	want := "One\n\nTwo\n```go\na := 1\n\n\nb := 2\n```\n\nThree"
+	if got := collapseBlankLines(content); got != want {
+		t.Errorf("collapseBlankLines() = %q, want %q", got, want)
+	}
+}
+
+func TestLinkFileMentions(t *testing.T) {
+	t.Chdir(t.TempDir())
+	if err := os.WriteFile("main.go", []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got := linkFileMentions("See main.go:3 and missing.go\n```\nmain.go\n```")
+	if !strings.Contains(got, "\x1b]8;;file://") || !strings.Contains(got, "\x1b\\main.go:3\x1b]8;;\x1b\\") {
+		t.Errorf("expected main.go:3 to be linked: %q", got)
+	}
+	if strings.Count(got, "\x1b]8;;file://") != 1 {
+		t.Errorf("expected only the existing file outside code blocks to be linked: %q", got)
+	}
+}
+
+func TestRendererPostProcessDefaults(t *testing.T) {
+	r := NewRenderer(nil)
+	got := r.postProcess("<think>hmm</think>Answer\n\n\n\nMore")
+	if got != "Answer\n\nMore" {
+		t.Errorf("postProcess() = %q", got)
+	}
+}
@@ -19,8 +19,11 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+	"time"
 
+	"github.com/antenore/deecli/internal/api"
 	"github.com/antenore/deecli/internal/config"
+	"github.com/antenore/deecli/internal/i18n"
 	"github.com/charmbracelet/lipgloss"
 )
 
@@ -31,20 +34,33 @@ type Renderer struct {
 	sidebarVisible bool
 	syntaxHighlightEnabled bool
 	rawCodeMode bool // Toggle for raw code display (no borders/formatting)
+	plain bool // Lightweight UI: no colors or code borders
+	accessible bool // Screen-reader mode: text labels instead of colors, emoji and borders
+	timestamps string // Message timestamps: off, relative or absolute
+	timestampOrigin time.Time // Relative timestamps count from the first message formatted
 }
 
 // NewRenderer creates a new renderer
 func NewRenderer(configManager *config.Manager) *Renderer {
 	// Default to disabled syntax highlighting for better copying
 	syntaxHighlight := false
+	accessible := false
 	if configManager != nil {
 		syntaxHighlight = configManager.GetSyntaxHighlightEnabled()
+		accessible = configManager.GetScreenReader()
+	}
+
+	timestamps := "off"
+	if configManager != nil {
+		timestamps = configManager.GetMessageTimestamps()
 	}
 
 	return &Renderer{
 		configManager: configManager,
 		syntaxHighlightEnabled: syntaxHighlight,
 		rawCodeMode: true, // Start in raw mode for easy copying
+		accessible: accessible,
+		timestamps: timestamps,
 	}
 }
 
@@ -65,6 +81,34 @@ func (r *Renderer) ToggleRawCodeMode() bool {
 	return r.rawCodeMode
 }
 
+// SetPlain switches uncolored, border-free output on or off
+func (r *Renderer) SetPlain(plain bool) {
+	r.plain = plain
+}
+
+// SetAccessible switches screen-reader friendly output on or off
+func (r *Renderer) SetAccessible(accessible bool) {
+	r.accessible = accessible
+}
+
+// SetTimestamps sets how message timestamps are shown: off, relative or absolute
+func (r *Renderer) SetTimestamps(mode string) {
+	r.timestamps = mode
+}
+
+// IsAccessible returns whether screen-reader friendly output is active
+func (r *Renderer) IsAccessible() bool {
+	return r.accessible
+}
+
+// FormatText prepares informational text such as the welcome screen and help for display
+func (r *Renderer) FormatText(text string) string {
+	if r.accessible {
+		return AccessibleText(text)
+	}
+	return text
+}
+
 // GetRawCodeMode returns the current raw code mode state
 func (r *Renderer) GetRawCodeMode() bool {
 	return r.rawCodeMode
@@ -72,6 +116,12 @@ func (r *Renderer) GetRawCodeMode() bool {
 
 // FormatMessage formats a message with proper styling and wrapping
 func (r *Renderer) FormatMessage(role, content string) string {
+	return r.FormatMessageAt(role, content, time.Now())
+}
+
+// FormatMessageAt formats a message sent at the given time, such as one
+// loaded from a session; the time is shown when timestamps are on
+func (r *Renderer) FormatMessageAt(role, content string, at time.Time) string {
 	var style lipgloss.Style
 	var prefix string
 
@@ -86,14 +136,36 @@ func (r *Renderer) FormatMessage(role, content string) string {
 	case "assistant":
 		style = lipgloss.NewStyle().Foreground(lipgloss.Color("86")).Bold(true)
 		prefix = "DeeCLI: "
+		content = r.postProcess(content)
 	case "system":
 		style = lipgloss.NewStyle().Foreground(lipgloss.Color("244"))
 		prefix = "System: "
 	}
 
+	if r.plain {
+		style = lipgloss.NewStyle()
+	}
+	if r.accessible {
+		// Announce the speaker as a label instead of relying on color
+		style = lipgloss.NewStyle()
+		switch role {
+		case "user":
+			prefix = "[you] "
+		case "assistant":
+			prefix = "[assistant] "
+		default:
+			prefix = "[" + role + "] "
+		}
+	}
+
+	stamp := r.formatTimestamp(at)
+	if stamp != "" {
+		stamp += " "
+	}
+
 	// Calculate available width for content
-	availableWidth := r.viewportWidth - len(prefix) - 2 // Account for prefix and some padding
-	if r.sidebarVisible {
+	availableWidth := r.viewportWidth - len(prefix) - len(stamp) - 2 // Account for prefix and some padding
+	if r.sidebarVisible && !r.plain {
 		// Adjust for sidebar taking up space
 		availableWidth = r.viewportWidth - 30 // Account for sidebar width
 	}
@@ -102,9 +174,76 @@ func (r *Renderer) FormatMessage(role, content string) string {
 	}
 
 	// Format content with code block handling
-	formattedContent := r.formatContentWithCodeBlocks(content, availableWidth)
+	formattedContent := r.formatContentWithCodeBlocks(content, availableWidth, role == "assistant")
+
+	if stamp != "" && !r.plain && !r.accessible {
+		stamp = lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render(stamp)
+	}
+	return stamp + style.Render(prefix) + formattedContent
+}
+
+// formatTimestamp returns the time a message was sent as configured: the
+// clock time (with the date if it is not today) or the time since the first
+// message, e.g. +1h05m. It is empty when timestamps are off.
+func (r *Renderer) formatTimestamp(at time.Time) string {
+	if at.IsZero() {
+		return ""
+	}
+	switch r.timestamps {
+	case "absolute":
+		at = at.Local()
+		if now := time.Now(); at.YearDay() != now.YearDay() || at.Year() != now.Year() {
+			return at.Format("2006-01-02 15:04")
+		}
+		return at.Format("15:04")
+	case "relative":
+		if r.timestampOrigin.IsZero() {
+			r.timestampOrigin = at
+		}
+		elapsed := max(int(at.Sub(r.timestampOrigin).Minutes()), 0)
+		if elapsed >= 60 {
+			return fmt.Sprintf("+%dh%02dm", elapsed/60, elapsed%60)
+		}
+		return fmt.Sprintf("+%dm", elapsed)
+	}
+	return ""
+}
 
-	return style.Render(prefix) + formattedContent
+// FormatAnnotation formats the line shown under a response, on demand, with
+// the model, temperature and tokens that produced it
+func (r *Renderer) FormatAnnotation(gen api.Generation) string {
+	if r.accessible {
+		return "[generation] " + gen.String()
+	}
+	if r.plain {
+		return "  " + gen.String()
+	}
+	return lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Italic(true).Render("  ↳ " + gen.String())
+}
+
+// FormatQueuedMessage formats a prompt waiting for the current response to
+// finish, dimmed so it does not read as sent
+func (r *Renderer) FormatQueuedMessage(content string) string {
+	label := i18n.T("queue.label")
+	if r.accessible {
+		return "[" + label + "] " + content
+	}
+	if r.plain {
+		return label + ": " + content
+	}
+	return lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Faint(true).Render(label + ": " + content)
+}
+
+// FormatTaskStatus formats the status line of a background task
+func (r *Renderer) FormatTaskStatus(status string) string {
+	label := i18n.T("tasks.label")
+	if r.accessible {
+		return "[" + label + "] " + status
+	}
+	if r.plain {
+		return label + ": " + status
+	}
+	return lipgloss.NewStyle().Foreground(lipgloss.Color("39")).Render("⚙️ " + label + ": " + status)
 }
 
 // FormatInitialContent creates the welcome message
@@ -126,16 +265,7 @@ func (r *Renderer) FormatInitialContent() string {
 	}
 
 	// Compact welcome screen
-	welcomeContent := fmt.Sprintf(`🐉 DeeCLI - AI Code Assistant | %s
-
-Essential Commands: /load <file> /unload <pattern> /list /clear /analyze /config /history /help
-Quick Keys: Tab=complete/focus %s=newline ↑/↓ or %s/%s=history F1=help F2=files F3=format
-
-💡 Start by loading files: /load *.go or /load main.go
-   Code is raw by default (copy-friendly). Press F3 for formatted view`,
-		filepath.Base(cwd), newlineKey, historyBackKey, historyForwardKey)
-
-	return welcomeContent
+	return i18n.T("welcome.body", filepath.Base(cwd), newlineKey, historyBackKey, historyForwardKey)
 }
 
 // FormatHelpContent creates the detailed help content
@@ -151,84 +281,7 @@ func (r *Renderer) FormatHelpContent() string {
 		historyForwardKey = r.formatKeyForDisplay(r.configManager.GetHistoryForwardKey())
 	}
 
-	return fmt.Sprintf(`🐉 DeeCLI Help
-
-=== Multi-line Input ===
-• Enter: Send message
-• %s: New line in message
-• Type naturally across multiple lines
-
-=== History Navigation ===
-• %s: Previous command/message
-• %s: Next command/message
-
-=== Chat Commands ===
-/load <file>    Load files (additive - adds to existing)
-/load --all <file> Load files ignoring .gitignore
-/unload <pattern> Remove files matching pattern
-/add <file>     Same as /load (deprecated)
-/list           List all loaded files
-/clear          Clear all loaded files
-/analyze        Analyze loaded files
-/improve        Get improvement suggestions
-/explain        Explain loaded code
-/edit           AI suggests which files to edit based on conversation
-/edit <file>    Open specific file in editor
-/edit <file:line> Jump to specific line in file
-/config         View/manage configuration settings
-/keysetup       Configure key bindings
-/history        View/manage command history
-/help           Show this help
-/quit           Exit the application
-
-=== Keyboard Shortcuts ===
-Tab             Smart: show/accept completions OR switch focus
-Enter           Send message
-%s         New line in message
-↑ or %s    Previous history (single-line input only)
-↓ or %s    Next history (single-line input only)
-F1              Toggle this help
-F2              Toggle files sidebar
-F3              Toggle code format (raw/bordered) for new messages
-Esc             Cancel ongoing AI response
-Ctrl+C          Exit application
-Ctrl+W          Delete word backward
-Ctrl+U/K        Delete to line start/end
-Alt+Backspace   Delete word backward (alternative)
-
-=== Focus Modes ===
-✏️ INPUT        Type messages and commands
-📜 CHAT         Scroll through chat history
-📁 FILES        Browse loaded files (when F2 open)
-
-Tab cycles focus: Input → Chat → Files (if open) → Input
-
-=== Navigation ===
-↑/↓             Scroll in viewport/sidebar OR history in input (single-line)
-PgUp/PgDn       Page up/down
-Ctrl+U/Ctrl+D   Half page up/down
-Home/End        Jump to top/bottom
-Esc/Enter       Return to input mode
-
-Tip: Yellow border shows which pane has focus!
-
-=== File Patterns ===
-You can use glob patterns to load multiple files:
-  /load *.go           Load all .go files
-  /load src/**/*.go    Load all .go files in src
-  /load {*.go,*.md}    Load all .go and .md files
-
-=== Tips ===
-• Multi-line messages: Use %s to add new lines
-• Quick submit: Just press Enter to send your message
-• Press Tab (when no completions) to switch between panes
-• Standard text editing shortcuts work (Ctrl+W, Ctrl+U, Ctrl+K, etc.)
-• Yellow border shows which pane has focus
-• Tab shows completions, use ↑↓ arrows to cycle, Tab/Enter to accept, Esc to cancel
-• Arrow keys scroll in focused panes
-• Press Esc to quickly return to input mode
-
-Press F1 to close this help`, newlineKey, historyBackKey, historyForwardKey,
+	return i18n.T("help.body", newlineKey, historyBackKey, historyForwardKey,
 		newlineKey, historyBackKey, historyForwardKey, newlineKey)
 }
 
@@ -240,13 +293,30 @@ func (r *Renderer) FormatLoadingMessage(loadingMsg string) string {
 
 	// Add hint about cancellation
 	hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("244"))
-	hintText := hintStyle.Render("Press Esc to cancel")
+	hintText := hintStyle.Render(i18n.T("loading.cancel_hint"))
 
 	return loadingText + "\n" + hintText
 }
 
 // FormatLoadingMessageWithSpinner creates a loading message with animated spinner
 func (r *Renderer) FormatLoadingMessageWithSpinner(loadingMsg string, spinnerFrame string) string {
+	return r.FormatLoadingMessageWithProgress(loadingMsg, spinnerFrame, "")
+}
+
+// FormatLoadingMessageWithProgress creates a loading message with spinner and progress details
+func (r *Renderer) FormatLoadingMessageWithProgress(loadingMsg string, spinnerFrame string, progress string) string {
+	if r.accessible {
+		// No spinner or ticking counters: they would be re-announced on every frame
+		return "[working] " + r.FormatText(loadingMsg) + " " + i18n.T("loading.cancel_hint_aloud")
+	}
+	if r.plain {
+		text := loadingMsg
+		if progress != "" {
+			text += " (" + progress + ")"
+		}
+		return text + " - " + i18n.T("loading.cancel_hint_short")
+	}
+
 	// Add loading indicator with animated spinner
 	loadingStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("220")).Bold(true)
 	spinnerText := spinnerFrame
@@ -255,15 +325,22 @@ func (r *Renderer) FormatLoadingMessageWithSpinner(loadingMsg string, spinnerFra
 	}
 	loadingText := loadingStyle.Render(spinnerText + " " + loadingMsg)
 
+	// Add elapsed time and throughput
+	if progress != "" {
+		progressStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("244"))
+		loadingText += " " + progressStyle.Render("("+progress+")")
+	}
+
 	// Add hint about cancellation
 	hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("244"))
-	hintText := hintStyle.Render("Press Esc to cancel")
+	hintText := hintStyle.Render(i18n.T("loading.cancel_hint"))
 
 	return loadingText + "\n" + hintText
 }
 
-// formatContentWithCodeBlocks processes content to format code blocks with clear boundaries
-func (r *Renderer) formatContentWithCodeBlocks(content string, width int) string {
+// formatContentWithCodeBlocks processes content to format code blocks with clear boundaries;
+// with markdown set, the text around them is laid out by formatMarkdown
+func (r *Renderer) formatContentWithCodeBlocks(content string, width int, markdown bool) string {
 	// Regular expression to match code blocks with optional language
 	codeBlockRegex := regexp.MustCompile("(?s)```([a-zA-Z0-9_+-]*)\n(.*?)```")
 
@@ -277,6 +354,10 @@ func (r *Renderer) formatContentWithCodeBlocks(content string, width int) string
 		// Add text before code block
 		if match[0] > lastEnd {
 			textBefore := content[lastEnd:match[0]]
+			if markdown {
+				textBefore = r.formatMarkdown(textBefore, width)
+			}
+			textBefore = r.FormatText(textBefore)
 			// Wrap non-code text
 			wrapper := lipgloss.NewStyle().Width(width)
 			result.WriteString(wrapper.Render(strings.TrimSpace(textBefore)))
@@ -301,6 +382,10 @@ func (r *Renderer) formatContentWithCodeBlocks(content string, width int) string
 	// Add remaining text after last code block
 	if lastEnd < len(content) {
 		remainingText := content[lastEnd:]
+		if markdown {
+			remainingText = r.formatMarkdown(remainingText, width)
+		}
+		remainingText = r.FormatText(remainingText)
 		if strings.TrimSpace(remainingText) != "" {
 			wrapper := lipgloss.NewStyle().Width(width)
 			result.WriteString("\n")
@@ -313,8 +398,17 @@ func (r *Renderer) formatContentWithCodeBlocks(content string, width int) string
 
 // formatCodeBlock formats a single code block with clear boundaries
 func (r *Renderer) formatCodeBlock(code, language string, width int) string {
+	// Screen readers get explicit boundaries instead of borders
+	if r.accessible {
+		label := "[code start]"
+		if language != "" {
+			label = "[code start: " + language + "]"
+		}
+		return "\n" + label + "\n" + strings.TrimRight(code, "\n") + "\n[code end]\n"
+	}
+
 	// If raw mode is enabled, return code as-is with minimal formatting
-	if r.rawCodeMode {
+	if r.rawCodeMode || r.plain {
 		var block strings.Builder
 		block.WriteString("\n")
 		// Just the raw code, nothing else - perfect for copying
@@ -0,0 +1,108 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func BenchmarkRenderer_FormatMessage(b *testing.B) {
+	r := NewRenderer(nil)
+	r.SetViewportWidth(120, false)
+	content := strings.Repeat("A paragraph of **markdown** with `inline code` and some words to wrap. ", 20) +
+		"\n\n```go\n" + strings.Repeat("fmt.Println(\"hello\")\n", 30) + "```\n\n- item one\n- item two\n"
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		r.FormatMessage("assistant", content)
+	}
+}
+
+func BenchmarkTranscript_Join(b *testing.B) {
+	messages := make([]string, 500)
+	for i := range messages {
+		messages[i] = strings.Repeat("formatted message line\n", 10)
+	}
+
+	b.ReportAllocs()
+	var tr Transcript
+	for i := 0; i < b.N; i++ {
+		// Streaming rewrites the last message on every chunk
+		messages[len(messages)-1] = strings.Repeat("x", i%100)
+		tr.Join(messages, "")
+	}
+}
+
+func TestRenderer_FormatMarkdown(t *testing.T) {
+	r := NewRenderer(nil)
+	r.SetPlain(true)
+	text := "## Results\n\n| Name | Count |\n|:-----|------:|\n| apples | 3 |\n| kiwi | 12 |\n\n- first\n  * nested\n"
+
+	got := r.formatMarkdown(text, 80)
+	want := "## Results\n\n" +
+		"Name   | Count\n" +
+		"-------+------\n" +
+		"apples |     3\n" +
+		"kiwi   |    12\n" +
+		"\n• first\n  • nested\n"
+	if got != want {
+		t.Errorf("formatMarkdown() =\n%s\nwant\n%s", got, want)
+	}
+
+	// Wide tables shrink their widest columns to fit
+	wide := "| Key | Description |\n|---|---|\n| a | " + strings.Repeat("long text ", 10) + "|\n"
+	for _, line := range strings.Split(r.formatMarkdown(wide, 30), "\n") {
+		if w := len([]rune(line)); w > 30 {
+			t.Errorf("line %q is %d wide, want at most 30", line, w)
+		}
+	}
+	if !strings.Contains(r.formatMarkdown(wide, 30), "…") {
+		t.Error("expected the cut cell to end with an ellipsis")
+	}
+
+	// Screen readers get the Markdown as written
+	r.SetAccessible(true)
+	if got := r.formatMarkdown(text, 80); got != text {
+		t.Errorf("expected the text unchanged for screen readers, got %q", got)
+	}
+}
+
+func TestRenderer_Timestamps(t *testing.T) {
+	r := NewRenderer(nil)
+	r.SetPlain(true)
+	r.SetViewportWidth(80, false)
+	start := time.Now()
+
+	if got := r.FormatMessageAt("user", "hi", start); !strings.HasPrefix(got, "You: ") {
+		t.Errorf("expected no timestamp by default, got %q", got)
+	}
+
+	r.SetTimestamps("absolute")
+	if got := r.FormatMessageAt("user", "hi", start); !strings.HasPrefix(got, start.Format("15:04")+" You: ") {
+		t.Errorf("expected the clock time, got %q", got)
+	}
+	yesterday := start.AddDate(0, 0, -1)
+	if got := r.FormatMessageAt("user", "hi", yesterday); !strings.HasPrefix(got, yesterday.Format("2006-01-02 15:04")+" ") {
+		t.Errorf("expected the date for an older message, got %q", got)
+	}
+
+	// Relative times count from the first message formatted
+	r.SetTimestamps("relative")
+	r.FormatMessageAt("user", "first", start)
+	if got := r.FormatMessageAt("assistant", "later", start.Add(75*time.Minute)); !strings.HasPrefix(got, "+1h15m DeeCLI: ") {
+		t.Errorf("expected the time since the first message, got %q", got)
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Section is an anchor in a response: a Markdown heading or the start of a
+// code block
+type Section struct {
+	Title string
+	Line  int // Line of the rendered message the section starts on
+}
+
+var (
+	// escapeSequence matches color codes and OSC 8 hyperlinks
+	escapeSequence = regexp.MustCompile(`\x1b\[[0-9;]*[a-zA-Z]|\x1b]8;[^\x1b]*\x1b\\`)
+	sectionHeading = regexp.MustCompile(`^(#{1,6})\s+(.+)$`)
+)
+
+// sectionNeedleLength is how much of a heading or first code line is looked
+// for in the rendered message; longer text may be wrapped onto more lines
+const sectionNeedleLength = 20
+
+// FindSections returns the headings and code blocks of the Markdown source
+// of a response, with the lines they start on in rendered, the response as
+// displayed. Rendering hides the fences and, in raw code mode, any sign of
+// where code starts, so the sections are parsed from the source and located
+// in order by their text. Sections that are not displayed (e.g. in stripped
+// reasoning) are left out. Headings are indented by their level.
+func FindSections(source, rendered string) []Section {
+	type anchor struct {
+		title, needle string
+		code          bool // The needle is the first line of code
+	}
+	var anchors []anchor
+	inCode := false
+	lines := strings.Split(source, "\n")
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			inCode = !inCode
+			if inCode {
+				anchors = append(anchors, anchor{codeTitle(strings.TrimPrefix(trimmed, "```")), firstCodeLine(lines[i+1:]), true})
+			}
+			continue
+		}
+		if match := sectionHeading.FindStringSubmatch(trimmed); !inCode && match != nil {
+			anchors = append(anchors, anchor{strings.Repeat("  ", len(match[1])-1) + match[2], trimmed, false})
+		}
+	}
+
+	var sections []Section
+	renderedLines := strings.Split(escapeSequence.ReplaceAllString(rendered, ""), "\n")
+	next := 0
+	for _, a := range anchors {
+		if a.needle == "" {
+			continue
+		}
+		needle := a.needle
+		if runes := []rune(needle); len(runes) > sectionNeedleLength {
+			needle = string(runes[:sectionNeedleLength])
+		}
+		for j := next; j < len(renderedLines); j++ {
+			if strings.Contains(renderedLines[j], needle) {
+				line := j
+				if a.code && j > next {
+					line-- // Include the border or label drawn above the code
+				}
+				sections = append(sections, Section{Title: a.title, Line: line})
+				next = j + 1
+				break
+			}
+		}
+	}
+	return sections
+}
+
+// firstCodeLine returns the first non-blank line of a code block
+func firstCodeLine(lines []string) string {
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			break
+		}
+		if trimmed != "" {
+			return trimmed
+		}
+	}
+	return ""
+}
+
+// codeTitle names a code block section after its language, if known
+func codeTitle(language string) string {
+	if language = strings.TrimSpace(language); language == "" {
+		return "‹code›"
+	}
+	return "‹code: " + language + "›"
+}
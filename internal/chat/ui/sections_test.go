@@ -0,0 +1,59 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFindSections(t *testing.T) {
+	response := "## Setup\nInstall it first.\n\n```sh\n# not a heading\nmake install\n```\n\n### Usage\nRun it.\n\n```\nplain code\n```\n"
+	want := []string{"  Setup", "‹code: sh›", "    Usage", "‹code›"}
+
+	for _, tc := range []struct {
+		name      string
+		configure func(r *Renderer)
+	}{
+		{"raw", func(r *Renderer) {}},
+		{"bordered", func(r *Renderer) { r.ToggleRawCodeMode() }},
+		{"accessible", func(r *Renderer) { r.SetAccessible(true) }},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := NewRenderer(nil)
+			r.SetViewportWidth(100, false)
+			tc.configure(r)
+			rendered := r.FormatMessage("assistant", response)
+			lines := strings.Split(escapeSequence.ReplaceAllString(rendered, ""), "\n")
+
+			var titles []string
+			for _, section := range FindSections(response, rendered) {
+				titles = append(titles, section.Title)
+				if section.Line >= len(lines) {
+					t.Fatalf("section %q is past the end", section.Title)
+				}
+				// The anchor is the heading itself or right above the code
+				if strings.HasSuffix(section.Title, "Usage") && !strings.Contains(lines[section.Line], "### Usage") {
+					t.Errorf("expected the Usage section on its heading, got line %q", lines[section.Line])
+				}
+				if section.Title == "‹code: sh›" && !strings.Contains(lines[section.Line+1], "# not a heading") {
+					t.Errorf("expected the sh section right above the code, got line %q", lines[section.Line+1])
+				}
+			}
+			if strings.Join(titles, "|") != strings.Join(want, "|") {
+				t.Errorf("FindSections() = %q, want %q", titles, want)
+			}
+		})
+	}
+}
@@ -14,7 +14,11 @@
 package ui
 
 import (
+	"cmp"
 	"fmt"
+	"math"
+	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/antenore/deecli/internal/config"
@@ -23,22 +27,138 @@ import (
 )
 
 // Sidebar handles the files sidebar rendering
-type Sidebar struct{}
+type Sidebar struct {
+	Sort  string // Order of the files: loaded, name, size or changed
+	Group bool   // Group the files by directory
+
+	collapsed    map[string]bool // Directories whose files are hidden
+	selected     string          // Directory header selected with [ and ]
+	selectedLine int             // Line of the selected header in the last render, -1 if none
+}
 
 // NewSidebar creates a new sidebar
 func NewSidebar() *Sidebar {
-	return &Sidebar{}
+	return &Sidebar{
+		Sort:         "loaded",
+		collapsed:    make(map[string]bool),
+		selectedLine: -1,
+	}
+}
+
+// CycleSort switches to the next order of config.ValidSidebarSorts and
+// returns it
+func (s *Sidebar) CycleSort() string {
+	i := slices.Index(config.ValidSidebarSorts, s.Sort)
+	s.Sort = config.ValidSidebarSorts[(i+1)%len(config.ValidSidebarSorts)]
+	return s.Sort
+}
+
+// ToggleGroup turns grouping by directory on or off and returns whether it
+// is on
+func (s *Sidebar) ToggleGroup() bool {
+	s.Group = !s.Group
+	return s.Group
+}
+
+// SelectDirectory moves the header selection to the next or previous
+// directory, wrapping around. It returns false when the files are not
+// grouped.
+func (s *Sidebar) SelectDirectory(fileContext *files.FileContext, forward bool) bool {
+	if !s.Group {
+		return false
+	}
+	dirs := s.directories(fileContext)
+	if len(dirs) == 0 {
+		return false
+	}
+	i := slices.Index(dirs, s.selected)
+	switch {
+	case i < 0 && forward:
+		i = 0
+	case i < 0:
+		i = len(dirs) - 1
+	case forward:
+		i = (i + 1) % len(dirs)
+	default:
+		i = (i + len(dirs) - 1) % len(dirs)
+	}
+	s.selected = dirs[i]
+	return true
+}
+
+// ToggleDirectory collapses or expands the selected directory. It returns
+// false when no directory is selected.
+func (s *Sidebar) ToggleDirectory(fileContext *files.FileContext) bool {
+	if !s.Group || !slices.Contains(s.directories(fileContext), s.selected) {
+		return false
+	}
+	s.collapsed[s.selected] = !s.collapsed[s.selected]
+	return true
+}
+
+// SelectedLine returns the line of the selected directory header in the last
+// rendered sidebar, or -1 if none is shown
+func (s *Sidebar) SelectedLine() int {
+	return s.selectedLine
+}
+
+// order returns the indexes of the loaded files in the sidebar order
+func (s *Sidebar) order(loaded []files.LoadedFile) []int {
+	order := make([]int, len(loaded))
+	for i := range order {
+		order[i] = len(loaded) - 1 - i // Most recently loaded first
+	}
+	byPath := func(a, b int) int { return strings.Compare(loaded[a].RelPath, loaded[b].RelPath) }
+	switch s.Sort {
+	case "name":
+		slices.SortStableFunc(order, byPath)
+	case "size":
+		slices.SortStableFunc(order, func(a, b int) int {
+			if loaded[a].Size != loaded[b].Size {
+				return cmp.Compare(loaded[b].Size, loaded[a].Size)
+			}
+			return byPath(a, b)
+		})
+	case "changed":
+		slices.SortStableFunc(order, func(a, b int) int {
+			if c := loaded[b].ModTime.Compare(loaded[a].ModTime); c != 0 {
+				return c
+			}
+			return byPath(a, b)
+		})
+	}
+	return order
+}
+
+// directories returns the directories of the loaded files, sorted
+func (s *Sidebar) directories(fileContext *files.FileContext) []string {
+	var dirs []string
+	for _, file := range fileContext.Files {
+		if dir := filepath.Dir(file.RelPath); !slices.Contains(dirs, dir) {
+			dirs = append(dirs, dir)
+		}
+	}
+	slices.Sort(dirs)
+	return dirs
 }
 
 // RenderFilesSidebar creates the files sidebar content
 func (s *Sidebar) RenderFilesSidebar(fileContext *files.FileContext, configManager *config.Manager) string {
 	var sb strings.Builder
+	s.selectedLine = -1
 
 	// Sidebar title
 	titleStyle := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("212")).
 		Bold(true)
 	sb.WriteString(titleStyle.Render("Files") + "\n")
+
+	// Current order and grouping, changed with s and g
+	view := "↕ " + s.Sort
+	if s.Group {
+		view += " • dirs"
+	}
+	sb.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render(view) + "\n")
 	sb.WriteString(strings.Repeat("─", 22) + "\n")
 
 	if len(fileContext.Files) == 0 {
@@ -52,38 +172,68 @@ func (s *Sidebar) RenderFilesSidebar(fileContext *files.FileContext, configManag
 	} else {
 		// List ALL files with icons and sizes (no limit for scrolling)
 		totalSize := int64(0)
-		for i, file := range fileContext.Files {
-			// Get file type icon
-			icon := s.GetFileTypeIcon(file.Language)
+		for _, file := range fileContext.Files {
+			totalSize += file.Size
+		}
 
-			// Format file size
-			sizeStr := s.FormatFileSize(file.Size)
+		// Estimated tokens of each file, for its share of the context
+		tokens := fileContext.GetFileTokens()
+		totalTokens := 0
+		for _, n := range tokens {
+			totalTokens += n
+		}
 
-			// File name (truncate if too long for sidebar width)
-			fileName := file.RelPath
-			if len(fileName) > 18 {
-				fileName = fileName[:15] + "..."
+		order := s.order(fileContext.Files)
+		if !s.Group {
+			for n, i := range order {
+				s.writeFile(&sb, i, fileContext.Files[i], fileContext.Files[i].RelPath, tokens[i], totalTokens)
+				if n < len(order)-1 {
+					sb.WriteString("\n")
+				}
 			}
+		} else {
+			headerStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("208")).Bold(true)
+			selectedStyle := headerStyle.Reverse(true)
+			for d, dir := range s.directories(fileContext) {
+				var inDir []int
+				for _, i := range order {
+					if filepath.Dir(fileContext.Files[i].RelPath) == dir {
+						inDir = append(inDir, i)
+					}
+				}
 
-			// File number for future selection
-			numberStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
-			fileStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("252"))
-
-			// File entry with number
-			sb.WriteString(fmt.Sprintf("%s %s %s\n",
-				numberStyle.Render(fmt.Sprintf("%2d.", i+1)),
-				icon,
-				fileStyle.Render(fileName)))
-
-			// Size and language (indented)
-			detailStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("244"))
-			sb.WriteString(detailStyle.Render(fmt.Sprintf("     %s • %s", file.Language, sizeStr)) + "\n")
+				if d > 0 {
+					sb.WriteString("\n")
+				}
+				arrow := "▾"
+				if s.collapsed[dir] {
+					arrow = "▸"
+				}
+				name := dir + "/"
+				if dir == "." {
+					name = "./"
+				}
+				if len(name) > 14 {
+					name = "..." + name[len(name)-11:]
+				}
+				header := fmt.Sprintf("%s %s (%d)", arrow, name, len(inDir))
+				if dir == s.selected {
+					s.selectedLine = strings.Count(sb.String(), "\n")
+					sb.WriteString(selectedStyle.Render(header) + "\n")
+				} else {
+					sb.WriteString(headerStyle.Render(header) + "\n")
+				}
+				if s.collapsed[dir] {
+					continue
+				}
 
-			if i < len(fileContext.Files)-1 {
-				sb.WriteString("\n")
+				for n, i := range inDir {
+					s.writeFile(&sb, i, fileContext.Files[i], filepath.Base(fileContext.Files[i].RelPath), tokens[i], totalTokens)
+					if n < len(inDir)-1 {
+						sb.WriteString("\n")
+					}
+				}
 			}
-
-			totalSize += file.Size
 		}
 
 		// Context usage information with warnings
@@ -98,10 +248,7 @@ func (s *Sidebar) RenderFilesSidebar(fileContext *files.FileContext, configManag
 		// Get config for limits
 		maxContextSize := 100000 // Default
 		if configManager != nil {
-			cfg := configManager.Get()
-			if cfg != nil && cfg.MaxContextSize > 0 {
-				maxContextSize = cfg.MaxContextSize
-			}
+			maxContextSize = configManager.GetMaxContextSize()
 		}
 
 		usagePercent := fileContext.GetContextUsagePercent(maxContextSize)
@@ -134,6 +281,11 @@ func (s *Sidebar) RenderFilesSidebar(fileContext *files.FileContext, configManag
 			Bold(true).
 			Render(fmt.Sprintf("Context: %s/%s%s", formattedSizeStr, maxSizeStr, warningText)) + "\n")
 
+		// Budget bar: how much of max_context_size the files take
+		sb.WriteString(lipgloss.NewStyle().
+			Foreground(lipgloss.Color(contextColor)).
+			Render(fmt.Sprintf("%s %3.0f%%", s.RenderBar(usagePercent, 16), usagePercent)) + "\n")
+
 		// Show estimated tokens
 		sb.WriteString(lipgloss.NewStyle().
 			Foreground(lipgloss.Color("244")).
@@ -143,6 +295,71 @@ func (s *Sidebar) RenderFilesSidebar(fileContext *files.FileContext, configManag
 	return sb.String()
 }
 
+// writeFile writes the entry of the loaded file with index i, shown as name,
+// with its estimated tokens and their share of totalTokens
+func (s *Sidebar) writeFile(sb *strings.Builder, i int, file files.LoadedFile, name string, tokens, totalTokens int) {
+	// Get file type icon
+	icon := s.GetFileTypeIcon(file.Language)
+
+	// Format file size
+	sizeStr := s.FormatFileSize(file.Size)
+
+	// File name (truncate if too long for sidebar width)
+	fileName := name
+	if len(fileName) > 18 {
+		fileName = fileName[:15] + "..."
+	}
+
+	// File number for future selection, in load order whatever the sidebar order
+	numberStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	fileStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("252"))
+
+	// File entry with number
+	sb.WriteString(fmt.Sprintf("%s %s %s\n",
+		numberStyle.Render(fmt.Sprintf("%2d.", i+1)),
+		icon,
+		fileStyle.Render(fileName)))
+
+	// Size and language (indented)
+	detailStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("244"))
+
+	// Where a symbolic link points
+	if target := file.Target; target != "" {
+		if len(target) > 16 {
+			target = "..." + target[len(target)-13:]
+		}
+		sb.WriteString(detailStyle.Render(fmt.Sprintf("   → %s", target)) + "\n")
+	}
+	sb.WriteString(detailStyle.Render(fmt.Sprintf("     %s • %s", file.Language, sizeStr)) + "\n")
+
+	// Share of the context prompt, in orange for a file taking most of it
+	share := 0.0
+	if totalTokens > 0 {
+		share = float64(tokens) / float64(totalTokens) * 100
+	}
+	barColor := "39"
+	if share >= 50 && tokens < totalTokens {
+		barColor = "208"
+	}
+	sb.WriteString("     " + lipgloss.NewStyle().Foreground(lipgloss.Color(barColor)).
+		Render(fmt.Sprintf("%s %3.0f%% %s", s.RenderBar(share, 6), share, s.FormatTokens(tokens))) + "\n")
+}
+
+// RenderBar draws percent (0-100, clamped) as a bar of width cells
+func (s *Sidebar) RenderBar(percent float64, width int) string {
+	percent = math.Max(0, math.Min(percent, 100))
+	filled := int(math.Round(percent / 100 * float64(width)))
+	return strings.Repeat("█", filled) + strings.Repeat("░", width-filled)
+}
+
+// FormatTokens formats a token count compactly, as 850 or 12.3k
+func (s *Sidebar) FormatTokens(tokens int) string {
+	if tokens < 1000 {
+		return fmt.Sprintf("%d", tokens)
+	}
+	return fmt.Sprintf("%.1fk", float64(tokens)/1000)
+}
+
 // GetFileTypeIcon returns an icon for the given file language
 func (s *Sidebar) GetFileTypeIcon(language string) string {
 	iconMap := map[string]string{
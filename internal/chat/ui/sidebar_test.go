@@ -0,0 +1,124 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/antenore/deecli/internal/files"
+)
+
+func sidebarFiles() *files.FileContext {
+	now := time.Now()
+	fc := files.NewFileContext()
+	fc.Files = []files.LoadedFile{
+		{RelPath: "src/b.go", Language: "go", Size: 300, ModTime: now.Add(-time.Hour)},
+		{RelPath: "README.md", Language: "markdown", Size: 100, ModTime: now},
+		{RelPath: "src/a.go", Language: "go", Size: 200, ModTime: now.Add(-2 * time.Hour)},
+	}
+	return fc
+}
+
+func TestSidebar_Order(t *testing.T) {
+	fc := sidebarFiles()
+	sidebar := NewSidebar()
+
+	tests := []struct {
+		sort string
+		want []int
+	}{
+		{"loaded", []int{2, 1, 0}},
+		{"name", []int{1, 2, 0}},
+		{"size", []int{0, 2, 1}},
+		{"changed", []int{1, 0, 2}},
+	}
+	for _, tt := range tests {
+		sidebar.Sort = tt.sort
+		if got := sidebar.order(fc.Files); !slices.Equal(got, tt.want) {
+			t.Errorf("order by %s = %v, want %v", tt.sort, got, tt.want)
+		}
+	}
+
+	// Cycling returns to the first order
+	sidebar.Sort = "loaded"
+	for range len(tests) {
+		sidebar.CycleSort()
+	}
+	if sidebar.Sort != "loaded" {
+		t.Errorf("sort after a full cycle = %s, want loaded", sidebar.Sort)
+	}
+}
+
+func TestSidebar_GroupAndCollapse(t *testing.T) {
+	fc := sidebarFiles()
+	sidebar := NewSidebar()
+	sidebar.Sort = "name"
+
+	if sidebar.SelectDirectory(fc, true) {
+		t.Error("directories should not be selectable without grouping")
+	}
+
+	sidebar.ToggleGroup()
+	out := sidebar.RenderFilesSidebar(fc, nil)
+	if !strings.Contains(out, "▾ ./ (1)") || !strings.Contains(out, "▾ src/ (2)") {
+		t.Errorf("grouped sidebar should show directory headers:\n%s", out)
+	}
+	if strings.Index(out, "a.go") > strings.Index(out, "b.go") {
+		t.Errorf("files should be sorted by name within a directory:\n%s", out)
+	}
+	// Numbers stay those of the load order
+	if !strings.Contains(out, " 3. ") {
+		t.Errorf("src/a.go should keep number 3:\n%s", out)
+	}
+
+	// ] selects ./ then src/
+	sidebar.SelectDirectory(fc, true)
+	sidebar.SelectDirectory(fc, true)
+	if !sidebar.ToggleDirectory(fc) {
+		t.Fatal("the selected directory should toggle")
+	}
+	out = sidebar.RenderFilesSidebar(fc, nil)
+	if !strings.Contains(out, "▸ src/ (2)") || strings.Contains(out, "a.go") {
+		t.Errorf("src/ should be collapsed:\n%s", out)
+	}
+	if sidebar.SelectedLine() < 0 {
+		t.Error("the selected header line should be known after rendering")
+	}
+}
+
+func TestSidebar_TokenBars(t *testing.T) {
+	sidebar := NewSidebar()
+	if bar := sidebar.RenderBar(50, 6); bar != "███░░░" {
+		t.Errorf("RenderBar(50, 6) = %q", bar)
+	}
+	if bar := sidebar.RenderBar(140, 4); bar != "████" {
+		t.Errorf("RenderBar should clamp to 100%%, got %q", bar)
+	}
+	if got := sidebar.FormatTokens(12345); got != "12.3k" {
+		t.Errorf("FormatTokens(12345) = %q", got)
+	}
+
+	fc := sidebarFiles()
+	fc.Files[0].Content = strings.Repeat("x", 4000)
+	out := sidebar.RenderFilesSidebar(fc, nil)
+	if !strings.Contains(out, "1.0k") {
+		t.Errorf("sidebar should show the tokens of src/b.go:\n%s", out)
+	}
+	if !strings.Contains(out, "%") || !strings.Contains(out, "░") {
+		t.Errorf("sidebar should show share and budget bars:\n%s", out)
+	}
+}
@@ -14,6 +14,7 @@
 package ui
 
 import (
+	"fmt"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -36,6 +37,10 @@ type Spinner struct {
 	style      SpinnerStyle
 	active     bool
 	interval   time.Duration
+
+	// Progress tracking for the current request
+	startTime     time.Time
+	receivedChars int
 }
 
 // NewSpinner creates a new spinner with the specified style
@@ -72,6 +77,11 @@ func (s *Spinner) setFrames() {
 
 // Start begins the spinner animation
 func (s *Spinner) Start() tea.Cmd {
+	// Only reset progress when starting fresh, so restarts keep the running total
+	if !s.active {
+		s.startTime = time.Now()
+		s.receivedChars = 0
+	}
 	s.active = true
 	s.frameIndex = 0
 	return s.tick()
@@ -87,6 +97,39 @@ func (s *Spinner) IsActive() bool {
 	return s.active
 }
 
+// AddReceived records streamed characters for throughput reporting
+func (s *Spinner) AddReceived(chars int) {
+	s.receivedChars += chars
+}
+
+// Elapsed returns the time since the spinner was started
+func (s *Spinner) Elapsed() time.Duration {
+	if s.startTime.IsZero() {
+		return 0
+	}
+	return time.Since(s.startTime)
+}
+
+// Tokens returns the approximate number of tokens received (1 token ≈ 4 characters)
+func (s *Spinner) Tokens() int {
+	return s.receivedChars / 4
+}
+
+// Progress returns elapsed time and, once tokens arrive, the approximate throughput
+func (s *Spinner) Progress() string {
+	elapsed := s.Elapsed()
+	if elapsed == 0 {
+		return ""
+	}
+
+	progress := fmt.Sprintf("%ds", int(elapsed.Seconds()))
+	if tokens := s.Tokens(); tokens > 0 {
+		rate := float64(tokens) / elapsed.Seconds()
+		progress += fmt.Sprintf(" · ~%d tokens · %.1f tok/s", tokens, rate)
+	}
+	return progress
+}
+
 // Frame returns the current animation frame
 func (s *Spinner) Frame() string {
 	if !s.active || len(s.frames) == 0 {
@@ -297,4 +297,39 @@ func TestSpinnerFrameIndexBounds(t *testing.T) {
 	if spinner.frameIndex >= len(spinner.frames) {
 		t.Error("Frame index should be within bounds after update")
 	}
-}
\ No newline at end of file
+}
+
+func TestSpinnerProgress(t *testing.T) {
+	spinner := NewDefaultSpinner()
+
+	if spinner.Progress() != "" {
+		t.Error("Progress should be empty before the spinner starts")
+	}
+
+	spinner.Start()
+	spinner.startTime = time.Now().Add(-10 * time.Second)
+
+	if got := spinner.Progress(); got != "10s" {
+		t.Errorf("Expected elapsed-only progress '10s', got %q", got)
+	}
+
+	spinner.AddReceived(400) // ~100 tokens
+	if spinner.Tokens() != 100 {
+		t.Errorf("Expected 100 tokens, got %d", spinner.Tokens())
+	}
+
+	got := spinner.Progress()
+	if got != "10s · ~100 tokens · 10.0 tok/s" {
+		t.Errorf("Unexpected progress: %q", got)
+	}
+
+	// Stopping keeps the totals; starting again resets them
+	spinner.Stop()
+	if spinner.Tokens() != 100 {
+		t.Error("Stop should not reset received tokens")
+	}
+	spinner.Start()
+	if spinner.Tokens() != 0 {
+		t.Error("Start should reset received tokens")
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import "strings"
+
+// MessageSeparator separates formatted messages in the chat viewport
+const MessageSeparator = "\n\n"
+
+// Transcript joins formatted chat messages for the viewport incrementally.
+// Every message but the last is cached as a frozen prefix, so appending a
+// message or rewriting the last one (as streaming does) only costs the new
+// text instead of re-joining the whole conversation on every update.
+type Transcript struct {
+	segments []string        // Messages already joined into frozen
+	frozen   strings.Builder // segments joined with MessageSeparator
+}
+
+// Join returns messages joined with MessageSeparator, followed by trailer
+// (such as a loading indicator) when it is not empty
+func (t *Transcript) Join(messages []string, trailer string) string {
+	if len(messages) == 0 {
+		return trailer
+	}
+
+	stable := messages[:len(messages)-1]
+	if !t.covers(stable) {
+		t.Reset()
+	}
+	for _, msg := range stable[len(t.segments):] {
+		if len(t.segments) > 0 {
+			t.frozen.WriteString(MessageSeparator)
+		}
+		t.frozen.WriteString(msg)
+		t.segments = append(t.segments, msg)
+	}
+
+	last := messages[len(messages)-1]
+	var b strings.Builder
+	b.Grow(t.frozen.Len() + len(last) + len(trailer) + 2*len(MessageSeparator))
+	b.WriteString(t.frozen.String())
+	if len(stable) > 0 {
+		b.WriteString(MessageSeparator)
+	}
+	b.WriteString(last)
+	if trailer != "" {
+		b.WriteString(MessageSeparator)
+		b.WriteString(trailer)
+	}
+	return b.String()
+}
+
+// Reset drops the cached prefix, e.g. after the message list was replaced
+func (t *Transcript) Reset() {
+	t.segments = nil
+	t.frozen.Reset()
+}
+
+// covers reports whether the cached segments are a prefix of messages. The
+// strings usually share their backing data, so the comparisons are cheap.
+func (t *Transcript) covers(messages []string) bool {
+	if len(t.segments) > len(messages) {
+		return false
+	}
+	for i, seg := range t.segments {
+		if messages[i] != seg {
+			return false
+		}
+	}
+	return true
+}
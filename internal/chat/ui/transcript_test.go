@@ -0,0 +1,58 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTranscript_Join(t *testing.T) {
+	var tr Transcript
+	messages := []string{"one"}
+
+	check := func(trailer string) {
+		t.Helper()
+		want := strings.Join(messages, MessageSeparator)
+		if trailer != "" {
+			want += MessageSeparator + trailer
+		}
+		if got := tr.Join(messages, trailer); got != want {
+			t.Errorf("Join() = %q, want %q", got, want)
+		}
+	}
+
+	if got := tr.Join(nil, "loading"); got != "loading" {
+		t.Errorf("expected only the trailer for no messages, got %q", got)
+	}
+	check("")
+
+	// Appending grows the frozen prefix without rebuilding it
+	messages = append(messages, "two", "three")
+	check("loading")
+	if len(tr.segments) != 2 {
+		t.Errorf("expected 2 cached segments, got %d", len(tr.segments))
+	}
+
+	// Rewriting the last message, as streaming does
+	messages[2] = "three, streamed"
+	check("")
+
+	// Replacing the list (e.g. loading a session) rebuilds the prefix
+	messages = []string{"other", "list"}
+	check("")
+	if len(tr.segments) != 1 || tr.segments[0] != "other" {
+		t.Errorf("expected the cache to be rebuilt, got %q", tr.segments)
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chat
+
+import (
+	"context"
+	"time"
+
+	"github.com/antenore/deecli/internal/api"
+	"github.com/antenore/deecli/internal/errlog"
+	"github.com/antenore/deecli/internal/i18n"
+	"github.com/antenore/deecli/internal/update"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// updateCheckTimeout bounds the startup update check
+const updateCheckTimeout = 5 * time.Second
+
+// updateCheckDoneMsg reports the outcome of the startup update check
+type updateCheckDoneMsg struct {
+	tag string // Newer release, or "" if this one is the latest
+	err error
+}
+
+// checkForUpdates looks for a newer release in the background when
+// check_updates is set; GitHub is asked at most once a day
+func (m *NewModel) checkForUpdates() tea.Cmd {
+	if m.configManager == nil || !m.configManager.GetCheckUpdates() {
+		return nil
+	}
+	client, err := api.NetworkOptionsFromConfig(m.configManager).HTTPClient(updateCheckTimeout)
+	if err != nil {
+		return nil // The chat already reported the invalid network settings
+	}
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), updateCheckTimeout)
+		defer cancel()
+		tag, err := update.Available(ctx, client, update.Version, update.DefaultCachePath())
+		return updateCheckDoneMsg{tag: tag, err: err}
+	}
+}
+
+// handleUpdateCheckDone announces a newer release. A failure is only
+// recorded for /errors, as the check is not worth interrupting the chat.
+func (m *NewModel) handleUpdateCheckDone(msg updateCheckDoneMsg) {
+	if msg.err != nil {
+		m.errorLog.Record(errlog.Classify(msg.err), "Update check failed", msg.err)
+		return
+	}
+	if msg.tag != "" {
+		m.addMessage("system", i18n.T("update.available", msg.tag, update.Version))
+	}
+}
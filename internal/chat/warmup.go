@@ -0,0 +1,59 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chat
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/antenore/deecli/internal/api"
+	"github.com/antenore/deecli/internal/errlog"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// warmUpDoneMsg reports the outcome of the startup connection warm-up
+type warmUpDoneMsg struct {
+	latency time.Duration
+	err     error
+}
+
+// warmUpConnection opens the API connection in the background when
+// warm_up_on_start is set, so the first request skips the TLS handshake
+func (m *NewModel) warmUpConnection() tea.Cmd {
+	if m.apiClient == nil || m.configManager == nil || !m.configManager.GetWarmUpOnStart() {
+		return nil
+	}
+	m.layoutManager.SetConnection("connecting…")
+	return warmUp(m.apiClient)
+}
+
+// warmUp runs the warm-up request and reports how long it took
+func warmUp(client *api.Service) tea.Cmd {
+	return func() tea.Msg {
+		start := time.Now()
+		err := client.WarmUp()
+		return warmUpDoneMsg{latency: time.Since(start), err: err}
+	}
+}
+
+// handleWarmUpDone shows the warm-up result in the header. A failure is only
+// recorded for /errors, since the first real request retries anyway.
+func (m *NewModel) handleWarmUpDone(msg warmUpDoneMsg) {
+	if msg.err != nil {
+		m.layoutManager.SetConnection("offline")
+		m.errorLog.Record(errlog.Classify(msg.err), "Connection warm-up failed", msg.err)
+		return
+	}
+	m.layoutManager.SetConnection(fmt.Sprintf("%dms", msg.latency.Milliseconds()))
+}
@@ -0,0 +1,102 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commitlint
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// DefaultTypes are the commit types of the Conventional Commits convention
+var DefaultTypes = []string{"feat", "fix", "docs", "style", "refactor", "perf", "test", "build", "ci", "chore", "revert"}
+
+// DefaultMaxHeaderLength is the longest header allowed when no limit is configured
+const DefaultMaxHeaderLength = 72
+
+// header matches "type(scope)!: description"
+var header = regexp.MustCompile(`^([a-zA-Z]+)(?:\(([^()]*)\))?(!)?:\s*(.*)$`)
+
+// Rules are the conventional-commit rules a message must follow
+type Rules struct {
+	Types           []string // Allowed types; empty uses DefaultTypes
+	Scopes          []string // Allowed scopes; empty allows any
+	RequireScope    bool
+	MaxHeaderLength int // 0 uses DefaultMaxHeaderLength
+}
+
+func (r Rules) types() []string {
+	if len(r.Types) == 0 {
+		return DefaultTypes
+	}
+	return r.Types
+}
+
+func (r Rules) maxHeaderLength() int {
+	if r.MaxHeaderLength <= 0 {
+		return DefaultMaxHeaderLength
+	}
+	return r.MaxHeaderLength
+}
+
+// Lint returns the rules the message breaks, or nil when it complies
+func Lint(message string, rules Rules) []string {
+	lines := strings.Split(strings.TrimSpace(message), "\n")
+	first := strings.TrimSpace(lines[0])
+
+	var problems []string
+	if n := len([]rune(first)); n > rules.maxHeaderLength() {
+		problems = append(problems, fmt.Sprintf("header is %d characters, the limit is %d", n, rules.maxHeaderLength()))
+	}
+	if len(lines) > 1 && strings.TrimSpace(lines[1]) != "" {
+		problems = append(problems, "the header must be followed by a blank line")
+	}
+
+	m := header.FindStringSubmatch(first)
+	if m == nil {
+		return append(problems, `header must look like "type(scope): description"`)
+	}
+	commitType, scope, description := m[1], m[2], m[4]
+	if !slices.Contains(rules.types(), commitType) {
+		problems = append(problems, fmt.Sprintf("type %q is not one of %s", commitType, strings.Join(rules.types(), ", ")))
+	}
+	switch {
+	case scope == "" && rules.RequireScope:
+		problems = append(problems, "a scope is required")
+	case scope != "" && len(rules.Scopes) > 0 && !slices.Contains(rules.Scopes, scope):
+		problems = append(problems, fmt.Sprintf("scope %q is not one of %s", scope, strings.Join(rules.Scopes, ", ")))
+	}
+	if strings.TrimSpace(description) == "" {
+		problems = append(problems, "the description is empty")
+	}
+	return problems
+}
+
+// Describe explains the rules to the model generating the message
+func (r Rules) Describe() string {
+	var b strings.Builder
+	b.WriteString("Follow the Conventional Commits format: the subject line is \"type(scope): description\".\n")
+	b.WriteString(fmt.Sprintf("Allowed types: %s.\n", strings.Join(r.types(), ", ")))
+	switch {
+	case len(r.Scopes) > 0 && r.RequireScope:
+		b.WriteString(fmt.Sprintf("A scope is required, one of: %s.\n", strings.Join(r.Scopes, ", ")))
+	case len(r.Scopes) > 0:
+		b.WriteString(fmt.Sprintf("The scope is optional; if present, one of: %s.\n", strings.Join(r.Scopes, ", ")))
+	case r.RequireScope:
+		b.WriteString("A scope is required.\n")
+	}
+	b.WriteString(fmt.Sprintf("The subject line is at most %d characters.", r.maxHeaderLength()))
+	return b.String()
+}
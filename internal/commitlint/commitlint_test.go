@@ -0,0 +1,62 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commitlint
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		rules   Rules
+		want    []string // Substrings of the expected problems, in order
+	}{
+		{"valid", "feat(chat): add /git commit\n\nStage the journal files.", Rules{}, nil},
+		{"breaking change", "refactor!: drop the legacy viewer", Rules{}, nil},
+		{"not conventional", "Add /git commit", Rules{}, []string{"must look like"}},
+		{"unknown type", "feature: add x", Rules{}, []string{`type "feature"`}},
+		{"custom types", "wip: add x", Rules{Types: []string{"wip"}}, nil},
+		{"missing scope", "fix: crash", Rules{RequireScope: true}, []string{"scope is required"}},
+		{"unknown scope", "fix(api): crash", Rules{Scopes: []string{"chat", "tools"}}, []string{`scope "api"`}},
+		{"too long", "fix: " + strings.Repeat("x", 70), Rules{}, []string{"75 characters, the limit is 72"}},
+		{"custom length", "fix: a short one", Rules{MaxHeaderLength: 10}, []string{"limit is 10"}},
+		{"no blank line", "fix: crash\nbody", Rules{}, []string{"blank line"}},
+		{"empty description", "fix: ", Rules{}, []string{"description is empty"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Lint(tt.message, tt.rules)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Lint() = %q, want %d problem(s)", got, len(tt.want))
+			}
+			for i, want := range tt.want {
+				if !strings.Contains(got[i], want) {
+					t.Errorf("Lint()[%d] = %q, want it to contain %q", i, got[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestRulesDescribe(t *testing.T) {
+	got := Rules{Scopes: []string{"chat"}, RequireScope: true, MaxHeaderLength: 50}.Describe()
+	for _, want := range []string{"feat, fix", "A scope is required, one of: chat", "at most 50 characters"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Describe() = %q, want it to contain %q", got, want)
+		}
+	}
+}
@@ -15,11 +15,16 @@ package config
 
 import (
 	"fmt"
+	"maps"
+	"net/url"
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
+	"time"
 
+	"github.com/antenore/deecli/internal/i18n"
 	"gopkg.in/yaml.v3"
 )
 
@@ -37,16 +42,96 @@ type Config struct {
 	AutoReloadFiles  bool                      `yaml:"auto_reload_files,omitempty"`     // Enable file auto-reload
 	AutoReloadDebounce int                     `yaml:"auto_reload_debounce,omitempty"`  // Debounce time in ms
 	ShowReloadNotices  bool                    `yaml:"show_reload_notices,omitempty"`   // Show reload notifications
-	MaxContextSize   int                       `yaml:"max_context_size,omitempty"`      // Max formatted context size in bytes
+	MaxContextSize   int                       `yaml:"max_context_size,omitempty"`      // Max formatted context size in bytes (0 = derived from the model)
 	SyntaxHighlight  bool                      `yaml:"syntax_highlight,omitempty"`      // Enable syntax highlighting in code blocks
 	CodeBlockStyle   string                    `yaml:"code_block_style,omitempty"`      // Style for code blocks: "bordered" or "simple"
 	ToolPermissions  map[string]ToolPermission `yaml:"tool_permissions,omitempty"`      // AI tool function permissions
+	Ignore           []string                  `yaml:"ignore,omitempty"`                // Extra ignore patterns (gitignore syntax)
+	RedactSecrets    bool                      `yaml:"redact_secrets,omitempty"`        // Mask secrets before sending to the API
+	RedactPatterns   []string                  `yaml:"redact_patterns,omitempty"`       // Extra secret patterns (regular expressions)
+	NotifyOnComplete string                    `yaml:"notify_on_complete,omitempty"`    // Notify when a response finishes unfocused: off, bell, desktop, both
+	PlainMode        bool                      `yaml:"plain_mode,omitempty"`            // Always use the lightweight plain UI
+	PlainModeWidth   int                       `yaml:"plain_mode_width,omitempty"`      // Use the plain UI below this terminal width (negative disables)
+	ScreenReader     bool                      `yaml:"screen_reader,omitempty"`         // Text labels instead of spinners, emoji, borders and colors
+	Language         string                    `yaml:"language,omitempty"`              // UI language code (en, it) or "auto" to follow LANG
+	LazyLoadThreshold int                      `yaml:"lazy_load_threshold,omitempty"`   // Keep file content on disk once more than this many files are loaded (negative disables)
+	LargeFileThreshold int                     `yaml:"large_file_threshold,omitempty"`  // Load only a preview of files larger than this many KB (negative disables)
+	LargeFilePreview  int                      `yaml:"large_file_preview,omitempty"`    // Size in KB of the preview kept for large files
+	MaxFileSize      int                       `yaml:"max_file_size,omitempty"`         // Size in KB of the largest file that can be loaded
+	MaxFilesPerLoad  int                       `yaml:"max_files_per_load,omitempty"`    // Files one /load may match (at most max_loaded_files)
+	MaxLoadedFiles   int                       `yaml:"max_loaded_files,omitempty"`      // Files that can be loaded at once
+	ExternalTools    []ExternalTool            `yaml:"external_tools,omitempty"`        // Tools provided by external executables
+	Plugins          []string                  `yaml:"plugins,omitempty"`               // Manifest files of sandboxed WASM tool plugins
+	WasmRuntime      string                    `yaml:"wasm_runtime,omitempty"`          // WASI runtime command used to run plugins
+	GitHubToken      string                    `yaml:"github_token,omitempty"`          // Token for GitHub API features such as /share gist
+	GitLabToken      string                    `yaml:"gitlab_token,omitempty"`          // Token for the GitLab API used by the issue and merge request tools
+	CommitLint       *CommitLint               `yaml:"commit_lint,omitempty"`           // Conventional-commit rules for generated commit messages
+	Mode             string                    `yaml:"mode,omitempty"`                  // Temperature preset: coding, general, creative or off
+	ModePrompt       bool                      `yaml:"mode_prompt,omitempty"`           // Add the mode's system prompt variant
+	HistoryMaxEntries int                      `yaml:"history_max_entries,omitempty"`   // Commands kept in the project's input history file
+	ExtractText      bool                      `yaml:"extract_text,omitempty"`          // Load the text of PDFs and images with external tools
+	PostProcessors   map[string]bool           `yaml:"post_processors,omitempty"`       // Response post-processors turned on or off by name
+	StreamStallTimeout int                     `yaml:"stream_stall_timeout,omitempty"`  // Seconds without response data before a stream counts as stalled (negative disables)
+	StreamAutoReconnect bool                   `yaml:"stream_auto_reconnect,omitempty"` // Replay a stalled request once before asking
+	ScrollbackLimit  int                       `yaml:"scrollback_limit,omitempty"`      // Chat messages kept in memory before older ones move to the session store (negative disables)
+	ToolCallsPerTurn   int                     `yaml:"tool_calls_per_turn,omitempty"`   // Tool calls one message may trigger before the chain stops (negative disables)
+	ToolCallsPerMinute int                     `yaml:"tool_calls_per_minute,omitempty"` // Tool calls allowed in any minute (negative disables)
+	MessageTimestamps string                   `yaml:"message_timestamps,omitempty"`    // Show when each message was sent: off, relative or absolute
+	SidebarSort      string                    `yaml:"sidebar_sort,omitempty"`          // Order of the files sidebar: loaded (default), name, size or changed
+	SidebarGroup     bool                      `yaml:"sidebar_group,omitempty"`         // Group the files sidebar by directory
+	WarmUpOnStart    bool                      `yaml:"warm_up_on_start,omitempty"`      // Open the API connection in the background when the chat starts
+	CheckUpdates     bool                      `yaml:"check_updates,omitempty"`         // Look for a newer release once a day when the chat starts
+	Telemetry        string                    `yaml:"telemetry,omitempty"`             // Usage statistics kept for /stats: local (default) or off; never sent
+	TrustedDirectories []string                `yaml:"trusted_directories,omitempty"`   // Directories whose project config and tools are trusted; read from the global config only
+	Proxy            string                    `yaml:"proxy,omitempty"`                 // Proxy URL for API requests; empty uses HTTP(S)_PROXY
+	CABundle         string                    `yaml:"ca_bundle,omitempty"`             // PEM file with extra certificate authorities to trust
+	InsecureSkipVerify bool                    `yaml:"insecure_skip_verify,omitempty"`  // Skip TLS certificate verification (unsafe)
+	RequestMetrics   bool                      `yaml:"request_metrics,omitempty"`       // Append per-request size, latency and retry metrics to .deecli/requests.jsonl
+	Formatters       map[string][]string       `yaml:"formatters,omitempty"`            // Formatter command by language, run on files written by /apply (empty disables)
+	FileTemplates    []FileTemplate            `yaml:"file_templates,omitempty"`        // Starting content of new files by path pattern
+	LicenseHeader    string                    `yaml:"license_header,omitempty"`        // File with the license header put at the top of new files
+	EditorContext    bool                      `yaml:"editor_context,omitempty"`        // Pre-fill the input with where the cursor was when the editor closes
+	InstructionFileSize int                    `yaml:"instruction_file_size,omitempty"` // Size in KB of the instruction file /edit opens next to a file (negative disables)
+	EditorCmd        string                    `yaml:"editor_cmd,omitempty"`            // Command template opening a file with its instruction file, e.g. "nvim -O {target} {instructions}"
+	EditorGotoCmd    string                    `yaml:"editor_goto_cmd,omitempty"`       // Command template opening a file at a line, e.g. "nvim +{line} {file}"
+}
+
+// FileTemplate is the starting content of new files whose path matches
+// Pattern, as a Go text/template
+type FileTemplate struct {
+	Pattern  string `yaml:"pattern"`            // Glob matched against the path if it has a slash, else the base name; "**/" matches any directories
+	Template string `yaml:"template,omitempty"` // Inline template
+	File     string `yaml:"file,omitempty"`     // File holding the template, relative to the config file
+}
+
+// CommitLint holds the conventional-commit rules generated commit messages
+// are checked against. A project setting replaces the global one as a whole.
+type CommitLint struct {
+	Enabled         bool     `yaml:"enabled"`
+	Types           []string `yaml:"types,omitempty"`             // Allowed types; empty allows the standard ones
+	Scopes          []string `yaml:"scopes,omitempty"`            // Allowed scopes; empty allows any
+	RequireScope    bool     `yaml:"require_scope,omitempty"`     // Reject messages without a scope
+	MaxHeaderLength int      `yaml:"max_header_length,omitempty"` // Longest subject line allowed; 0 uses 72
+}
+
+// ExternalTool describes a tool function implemented by an external executable.
+// The definition is given inline or read from a JSON manifest file with the
+// same fields; inline fields take priority over the manifest.
+type ExternalTool struct {
+	Name        string                 `yaml:"name,omitempty" json:"name"`
+	Description string                 `yaml:"description,omitempty" json:"description"`
+	Parameters  map[string]interface{} `yaml:"parameters,omitempty" json:"parameters"` // JSON schema for the arguments
+	Command     []string               `yaml:"command,omitempty" json:"command"`       // Executable and fixed arguments
+	Manifest    string                 `yaml:"manifest,omitempty" json:"-"`            // Path to a JSON manifest file
+	Timeout     int                    `yaml:"timeout,omitempty" json:"timeout"`       // Seconds before the tool is stopped
+	ReadOnly    bool                   `yaml:"read_only,omitempty" json:"read_only"`   // The tool changes nothing, so dry-run mode still runs it
 }
 
 // ToolPermission represents permission settings for AI tool functions
 type ToolPermission struct {
-	Level     string `yaml:"level"`      // "once", "always", or "never"
-	UpdatedAt int64  `yaml:"updated_at"` // Unix timestamp
+	Level        string   `yaml:"level"`                  // "once", "always", or "never"
+	UpdatedAt    int64    `yaml:"updated_at"`             // Unix timestamp
+	Capabilities []string `yaml:"capabilities,omitempty"` // Capabilities granted to a sandboxed tool
 }
 
 type Profile struct {
@@ -56,6 +141,52 @@ type Profile struct {
 	MaxTokens   int     `yaml:"max_tokens,omitempty"`
 }
 
+// DefaultPlainModeWidth is the terminal width below which the plain UI is used
+const DefaultPlainModeWidth = 60
+
+// DefaultLazyLoadThreshold is the number of loaded files above which new files are loaded lazily
+const DefaultLazyLoadThreshold = 25
+
+// DefaultLargeFileThreshold is the file size in KB above which only a preview is loaded
+const DefaultLargeFileThreshold = 256
+
+// DefaultLargeFilePreview is the size in KB of the preview kept for large files
+const DefaultLargeFilePreview = 32
+
+// DefaultMaxFileSize is the size in KB of the largest file that can be loaded
+const DefaultMaxFileSize = 10 * 1024
+
+// DefaultMaxLoadedFiles is how many files can be loaded at once; one /load may
+// match as many unless max_files_per_load is lower
+const DefaultMaxLoadedFiles = 50
+
+// DefaultInstructionFileSize is the size in KB of the suggestions and code kept in instruction files
+const DefaultInstructionFileSize = 16
+
+// DefaultHistoryMaxEntries is the number of commands kept in the input history file
+const DefaultHistoryMaxEntries = 1000
+
+// DefaultScrollbackLimit is the number of chat messages kept in memory
+const DefaultScrollbackLimit = 500
+
+// DefaultToolCallsPerTurn is how many tool calls one message may trigger
+const DefaultToolCallsPerTurn = 25
+
+// DefaultToolCallsPerMinute is how many tool calls are allowed in any minute
+const DefaultToolCallsPerMinute = 60
+
+// DefaultStreamStallTimeout is how many seconds a stream may go without data before it counts as stalled
+const DefaultStreamStallTimeout = 30
+
+// DefaultWasmRuntime is the WASI runtime used to run tool plugins
+const DefaultWasmRuntime = "wasmtime"
+
+// DefaultFormatters are the formatters run on applied changes for languages
+// the formatters setting does not mention
+var DefaultFormatters = map[string][]string{
+	"go": {"gofmt"},
+}
+
 var (
 	defaultConfig = Config{
 		Model:            "deepseek-chat",
@@ -66,38 +197,52 @@ var (
 		AutoReloadFiles:  true,
 		AutoReloadDebounce: 100,
 		ShowReloadNotices: true,
-		MaxContextSize:   100000, // 100KB formatted context limit
 		SyntaxHighlight:  false,  // Disable syntax highlighting by default for better copying
 		CodeBlockStyle:   "simple", // Use simple style by default for easy copying
 		ToolPermissions:  make(map[string]ToolPermission),
+		RedactSecrets:    true,
+		NotifyOnComplete: "off",
+		PlainModeWidth:   DefaultPlainModeWidth,
+		LazyLoadThreshold: DefaultLazyLoadThreshold,
+		LargeFileThreshold: DefaultLargeFileThreshold,
+		LargeFilePreview:  DefaultLargeFilePreview,
 	}
 )
 
 type Manager struct {
 	globalConfig  *Config
 	projectConfig *Config
+	projectKeys   map[string]bool // Top-level keys the project config file sets
 	mergedConfig  *Config
 	globalPath    string
 	projectPath   string
+	projectDir    string // Directory whose trust decides if the project config is read, "" to always read it
+	untrusted     bool   // projectDir is not trusted, so the project config was skipped
+	profile       string // Profile applied instead of active_profile, see UseProfile
 }
 
 func NewManager() *Manager {
 	home, _ := os.UserHomeDir()
 	globalPath := filepath.Join(home, ".deecli", "config.yaml")
 	projectPath := filepath.Join(".deecli", "config.yaml")
+	projectDir, _ := filepath.Abs(".")
 
 	return &Manager{
 		globalPath:  globalPath,
 		projectPath: projectPath,
+		projectDir:  projectDir,
 	}
 }
 
 func (m *Manager) Load() error {
 	m.globalConfig = &Config{}
-	m.projectConfig = &Config{}
+	m.projectConfig = nil
+	m.projectKeys = nil
 
-	// Load global config
-	if err := m.loadConfigFile(m.globalPath, m.globalConfig); err != nil && !os.IsNotExist(err) {
+	// Load global config; without one the defaults apply
+	if err := m.loadConfigFile(m.globalPath, m.globalConfig); os.IsNotExist(err) {
+		m.globalConfig = nil
+	} else if err != nil {
 		return fmt.Errorf("failed to load global config: %w", err)
 	}
 
@@ -108,9 +253,18 @@ func (m *Manager) Load() error {
 		}
 	}
 
-	// Load project config
-	if err := m.loadConfigFile(m.projectPath, m.projectConfig); err != nil && !os.IsNotExist(err) {
-		return fmt.Errorf("failed to load project config: %w", err)
+	// Load project config, only from a trusted directory as it can enable
+	// tools and change where requests go; an untrusted one has none to merge
+	m.untrusted = !m.isTrusted(m.projectDir)
+	if !m.untrusted {
+		m.projectConfig = &Config{}
+		if err := m.loadConfigFile(m.projectPath, m.projectConfig); os.IsNotExist(err) {
+			m.projectConfig = nil
+		} else if err != nil {
+			return fmt.Errorf("failed to load project config: %w", err)
+		} else {
+			m.projectKeys = configKeys(m.projectPath)
+		}
 	}
 
 	// Validate project config
@@ -156,6 +310,25 @@ func (m *Manager) loadConfigFile(path string, cfg *Config) error {
 	return nil
 }
 
+// configKeys returns the top-level keys the config file at path sets. A
+// boolean the project config leaves out keeps its global value, although
+// the file is read over the defaults.
+func configKeys(path string) map[string]bool {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var raw map[string]yaml.Node
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil
+	}
+	keys := make(map[string]bool, len(raw))
+	for key := range raw {
+		keys[key] = true
+	}
+	return keys
+}
+
 func (m *Manager) mergeConfigs() *Config {
 	merged := defaultConfig
 
@@ -173,12 +346,30 @@ func (m *Manager) mergeConfigs() *Config {
 		if m.globalConfig.MaxTokens != 0 {
 			merged.MaxTokens = m.globalConfig.MaxTokens
 		}
+		if m.globalConfig.MaxContextSize != 0 {
+			merged.MaxContextSize = m.globalConfig.MaxContextSize
+		}
 		if m.globalConfig.UserName != "" {
 			merged.UserName = m.globalConfig.UserName
 		}
+		if m.globalConfig.NewlineKey != "" {
+			merged.NewlineKey = m.globalConfig.NewlineKey
+		}
+		if m.globalConfig.HistoryBackKey != "" {
+			merged.HistoryBackKey = m.globalConfig.HistoryBackKey
+		}
+		if m.globalConfig.HistoryForwardKey != "" {
+			merged.HistoryForwardKey = m.globalConfig.HistoryForwardKey
+		}
 		if len(m.globalConfig.Profiles) > 0 {
 			merged.Profiles = m.globalConfig.Profiles
 		}
+		if len(m.globalConfig.PostProcessors) > 0 {
+			merged.PostProcessors = maps.Clone(m.globalConfig.PostProcessors)
+		}
+		if len(m.globalConfig.Formatters) > 0 {
+			merged.Formatters = maps.Clone(m.globalConfig.Formatters)
+		}
 		if m.globalConfig.ActiveProfile != "" {
 			merged.ActiveProfile = m.globalConfig.ActiveProfile
 		}
@@ -193,6 +384,110 @@ func (m *Manager) mergeConfigs() *Config {
 		if m.globalConfig.CodeBlockStyle != "" {
 			merged.CodeBlockStyle = m.globalConfig.CodeBlockStyle
 		}
+		merged.Ignore = append(merged.Ignore, m.globalConfig.Ignore...)
+		// Redaction settings
+		merged.RedactSecrets = m.globalConfig.RedactSecrets
+		merged.RedactPatterns = append(merged.RedactPatterns, m.globalConfig.RedactPatterns...)
+		if m.globalConfig.NotifyOnComplete != "" {
+			merged.NotifyOnComplete = m.globalConfig.NotifyOnComplete
+		}
+		if m.globalConfig.MessageTimestamps != "" {
+			merged.MessageTimestamps = m.globalConfig.MessageTimestamps
+		}
+		if m.globalConfig.SidebarSort != "" {
+			merged.SidebarSort = m.globalConfig.SidebarSort
+		}
+		merged.SidebarGroup = m.globalConfig.SidebarGroup
+		// Plain UI settings
+		merged.PlainMode = m.globalConfig.PlainMode
+		if m.globalConfig.PlainModeWidth != 0 {
+			merged.PlainModeWidth = m.globalConfig.PlainModeWidth
+		}
+		merged.ScreenReader = m.globalConfig.ScreenReader
+		merged.ExtractText = m.globalConfig.ExtractText
+		if m.globalConfig.StreamStallTimeout != 0 {
+			merged.StreamStallTimeout = m.globalConfig.StreamStallTimeout
+		}
+		merged.StreamAutoReconnect = m.globalConfig.StreamAutoReconnect
+		merged.WarmUpOnStart = m.globalConfig.WarmUpOnStart
+		merged.CheckUpdates = m.globalConfig.CheckUpdates
+		if m.globalConfig.Telemetry != "" {
+			merged.Telemetry = m.globalConfig.Telemetry
+		}
+		merged.TrustedDirectories = m.globalConfig.TrustedDirectories
+		if m.globalConfig.ScrollbackLimit != 0 {
+			merged.ScrollbackLimit = m.globalConfig.ScrollbackLimit
+		}
+		if m.globalConfig.ToolCallsPerTurn != 0 {
+			merged.ToolCallsPerTurn = m.globalConfig.ToolCallsPerTurn
+		}
+		if m.globalConfig.ToolCallsPerMinute != 0 {
+			merged.ToolCallsPerMinute = m.globalConfig.ToolCallsPerMinute
+		}
+		if m.globalConfig.Mode != "" {
+			merged.Mode = m.globalConfig.Mode
+			merged.ModePrompt = m.globalConfig.ModePrompt
+		}
+		if m.globalConfig.Language != "" {
+			merged.Language = m.globalConfig.Language
+		}
+		if m.globalConfig.LazyLoadThreshold != 0 {
+			merged.LazyLoadThreshold = m.globalConfig.LazyLoadThreshold
+		}
+		if m.globalConfig.HistoryMaxEntries != 0 {
+			merged.HistoryMaxEntries = m.globalConfig.HistoryMaxEntries
+		}
+		if m.globalConfig.LargeFileThreshold != 0 {
+			merged.LargeFileThreshold = m.globalConfig.LargeFileThreshold
+		}
+		if m.globalConfig.LargeFilePreview != 0 {
+			merged.LargeFilePreview = m.globalConfig.LargeFilePreview
+		}
+		if m.globalConfig.MaxFileSize != 0 {
+			merged.MaxFileSize = m.globalConfig.MaxFileSize
+		}
+		if m.globalConfig.MaxFilesPerLoad != 0 {
+			merged.MaxFilesPerLoad = m.globalConfig.MaxFilesPerLoad
+		}
+		if m.globalConfig.MaxLoadedFiles != 0 {
+			merged.MaxLoadedFiles = m.globalConfig.MaxLoadedFiles
+		}
+		if m.globalConfig.InstructionFileSize != 0 {
+			merged.InstructionFileSize = m.globalConfig.InstructionFileSize
+		}
+		if m.globalConfig.EditorCmd != "" {
+			merged.EditorCmd = m.globalConfig.EditorCmd
+		}
+		if m.globalConfig.EditorGotoCmd != "" {
+			merged.EditorGotoCmd = m.globalConfig.EditorGotoCmd
+		}
+		merged.ExternalTools = mergeExternalTools(merged.ExternalTools, m.globalConfig.ExternalTools, filepath.Dir(m.globalPath))
+		merged.Plugins = mergePluginPaths(merged.Plugins, m.globalConfig.Plugins, filepath.Dir(m.globalPath))
+		merged.FileTemplates = mergeFileTemplates(merged.FileTemplates, m.globalConfig.FileTemplates, filepath.Dir(m.globalPath))
+		if m.globalConfig.LicenseHeader != "" {
+			merged.LicenseHeader = resolveConfigPath(m.globalConfig.LicenseHeader, filepath.Dir(m.globalPath))
+		}
+		if m.globalConfig.WasmRuntime != "" {
+			merged.WasmRuntime = m.globalConfig.WasmRuntime
+		}
+		if m.globalConfig.GitHubToken != "" {
+			merged.GitHubToken = m.globalConfig.GitHubToken
+		}
+		if m.globalConfig.Proxy != "" {
+			merged.Proxy = m.globalConfig.Proxy
+		}
+		if m.globalConfig.CABundle != "" {
+			merged.CABundle = resolveConfigPath(m.globalConfig.CABundle, filepath.Dir(m.globalPath))
+		}
+		merged.InsecureSkipVerify = m.globalConfig.InsecureSkipVerify
+		merged.RequestMetrics = m.globalConfig.RequestMetrics
+		merged.EditorContext = m.globalConfig.EditorContext
+		if m.globalConfig.GitLabToken != "" {
+			merged.GitLabToken = m.globalConfig.GitLabToken
+		}
+		if m.globalConfig.CommitLint != nil {
+			merged.CommitLint = m.globalConfig.CommitLint
+		}
 	}
 
 	// Apply project config (higher priority)
@@ -209,9 +504,21 @@ func (m *Manager) mergeConfigs() *Config {
 		if m.projectConfig.MaxTokens != 0 {
 			merged.MaxTokens = m.projectConfig.MaxTokens
 		}
+		if m.projectConfig.MaxContextSize != 0 {
+			merged.MaxContextSize = m.projectConfig.MaxContextSize
+		}
 		if m.projectConfig.UserName != "" {
 			merged.UserName = m.projectConfig.UserName
 		}
+		if m.projectConfig.NewlineKey != "" {
+			merged.NewlineKey = m.projectConfig.NewlineKey
+		}
+		if m.projectConfig.HistoryBackKey != "" {
+			merged.HistoryBackKey = m.projectConfig.HistoryBackKey
+		}
+		if m.projectConfig.HistoryForwardKey != "" {
+			merged.HistoryForwardKey = m.projectConfig.HistoryForwardKey
+		}
 		if m.projectConfig.ActiveProfile != "" {
 			merged.ActiveProfile = m.projectConfig.ActiveProfile
 		}
@@ -226,6 +533,133 @@ func (m *Manager) mergeConfigs() *Config {
 		if m.projectConfig.CodeBlockStyle != "" {
 			merged.CodeBlockStyle = m.projectConfig.CodeBlockStyle
 		}
+		// Ignore patterns are additive: project patterns extend global ones
+		merged.Ignore = append(merged.Ignore, m.projectConfig.Ignore...)
+		// Redaction settings from project config
+		if m.projectKeys["redact_secrets"] {
+			merged.RedactSecrets = m.projectConfig.RedactSecrets
+		}
+		merged.RedactPatterns = append(merged.RedactPatterns, m.projectConfig.RedactPatterns...)
+		if m.projectConfig.NotifyOnComplete != "" {
+			merged.NotifyOnComplete = m.projectConfig.NotifyOnComplete
+		}
+		if m.projectConfig.MessageTimestamps != "" {
+			merged.MessageTimestamps = m.projectConfig.MessageTimestamps
+		}
+		if m.projectConfig.SidebarSort != "" {
+			merged.SidebarSort = m.projectConfig.SidebarSort
+		}
+		if m.projectKeys["sidebar_group"] {
+			merged.SidebarGroup = m.projectConfig.SidebarGroup
+		}
+		// Plain UI settings from project config
+		if m.projectKeys["plain_mode"] {
+			merged.PlainMode = m.projectConfig.PlainMode
+		}
+		if m.projectConfig.PlainModeWidth != 0 {
+			merged.PlainModeWidth = m.projectConfig.PlainModeWidth
+		}
+		if m.projectKeys["screen_reader"] {
+			merged.ScreenReader = m.projectConfig.ScreenReader
+		}
+		if m.projectKeys["extract_text"] {
+			merged.ExtractText = m.projectConfig.ExtractText
+		}
+		if m.projectConfig.StreamStallTimeout != 0 {
+			merged.StreamStallTimeout = m.projectConfig.StreamStallTimeout
+		}
+		if m.projectKeys["stream_auto_reconnect"] {
+			merged.StreamAutoReconnect = m.projectConfig.StreamAutoReconnect
+		}
+		if m.projectKeys["warm_up_on_start"] {
+			merged.WarmUpOnStart = m.projectConfig.WarmUpOnStart
+		}
+		if m.projectKeys["check_updates"] {
+			merged.CheckUpdates = m.projectConfig.CheckUpdates
+		}
+		if m.projectConfig.Telemetry != "" {
+			merged.Telemetry = m.projectConfig.Telemetry
+		}
+		if m.projectConfig.ScrollbackLimit != 0 {
+			merged.ScrollbackLimit = m.projectConfig.ScrollbackLimit
+		}
+		if m.projectConfig.ToolCallsPerTurn != 0 {
+			merged.ToolCallsPerTurn = m.projectConfig.ToolCallsPerTurn
+		}
+		if m.projectConfig.ToolCallsPerMinute != 0 {
+			merged.ToolCallsPerMinute = m.projectConfig.ToolCallsPerMinute
+		}
+		if m.projectConfig.Mode != "" {
+			merged.Mode = m.projectConfig.Mode
+			merged.ModePrompt = m.projectConfig.ModePrompt
+		}
+		if m.projectConfig.Language != "" {
+			merged.Language = m.projectConfig.Language
+		}
+		if m.projectConfig.LazyLoadThreshold != 0 {
+			merged.LazyLoadThreshold = m.projectConfig.LazyLoadThreshold
+		}
+		if m.projectConfig.HistoryMaxEntries != 0 {
+			merged.HistoryMaxEntries = m.projectConfig.HistoryMaxEntries
+		}
+		if m.projectConfig.LargeFileThreshold != 0 {
+			merged.LargeFileThreshold = m.projectConfig.LargeFileThreshold
+		}
+		if m.projectConfig.LargeFilePreview != 0 {
+			merged.LargeFilePreview = m.projectConfig.LargeFilePreview
+		}
+		if m.projectConfig.MaxFileSize != 0 {
+			merged.MaxFileSize = m.projectConfig.MaxFileSize
+		}
+		if m.projectConfig.MaxFilesPerLoad != 0 {
+			merged.MaxFilesPerLoad = m.projectConfig.MaxFilesPerLoad
+		}
+		if m.projectConfig.MaxLoadedFiles != 0 {
+			merged.MaxLoadedFiles = m.projectConfig.MaxLoadedFiles
+		}
+		if m.projectConfig.InstructionFileSize != 0 {
+			merged.InstructionFileSize = m.projectConfig.InstructionFileSize
+		}
+		if m.projectConfig.EditorCmd != "" {
+			merged.EditorCmd = m.projectConfig.EditorCmd
+		}
+		if m.projectConfig.EditorGotoCmd != "" {
+			merged.EditorGotoCmd = m.projectConfig.EditorGotoCmd
+		}
+		// External tools from project config replace global ones with the same name
+		merged.ExternalTools = mergeExternalTools(merged.ExternalTools, m.projectConfig.ExternalTools, filepath.Dir(m.projectPath))
+		merged.Plugins = mergePluginPaths(merged.Plugins, m.projectConfig.Plugins, filepath.Dir(m.projectPath))
+		merged.FileTemplates = mergeFileTemplates(merged.FileTemplates, m.projectConfig.FileTemplates, filepath.Dir(m.projectPath))
+		if m.projectConfig.LicenseHeader != "" {
+			merged.LicenseHeader = resolveConfigPath(m.projectConfig.LicenseHeader, filepath.Dir(m.projectPath))
+		}
+		if m.projectConfig.WasmRuntime != "" {
+			merged.WasmRuntime = m.projectConfig.WasmRuntime
+		}
+		if m.projectConfig.GitHubToken != "" {
+			merged.GitHubToken = m.projectConfig.GitHubToken
+		}
+		if m.projectConfig.Proxy != "" {
+			merged.Proxy = m.projectConfig.Proxy
+		}
+		if m.projectConfig.CABundle != "" {
+			merged.CABundle = resolveConfigPath(m.projectConfig.CABundle, filepath.Dir(m.projectPath))
+		}
+		if m.projectKeys["insecure_skip_verify"] {
+			merged.InsecureSkipVerify = m.projectConfig.InsecureSkipVerify
+		}
+		if m.projectKeys["request_metrics"] {
+			merged.RequestMetrics = m.projectConfig.RequestMetrics
+		}
+		if m.projectKeys["editor_context"] {
+			merged.EditorContext = m.projectConfig.EditorContext
+		}
+		if m.projectConfig.GitLabToken != "" {
+			merged.GitLabToken = m.projectConfig.GitLabToken
+		}
+		if m.projectConfig.CommitLint != nil {
+			merged.CommitLint = m.projectConfig.CommitLint
+		}
 		// Merge profiles
 		for name, profile := range m.projectConfig.Profiles {
 			merged.Profiles[name] = profile
@@ -234,11 +668,29 @@ func (m *Manager) mergeConfigs() *Config {
 		for name, permission := range m.projectConfig.ToolPermissions {
 			merged.ToolPermissions[name] = permission
 		}
+		// Merge post-processor toggles (project config takes priority)
+		for name, enabled := range m.projectConfig.PostProcessors {
+			if merged.PostProcessors == nil {
+				merged.PostProcessors = make(map[string]bool)
+			}
+			merged.PostProcessors[name] = enabled
+		}
+		// Merge formatters (project config takes priority per language)
+		for language, command := range m.projectConfig.Formatters {
+			if merged.Formatters == nil {
+				merged.Formatters = make(map[string][]string)
+			}
+			merged.Formatters[language] = command
+		}
 	}
 
-	// Apply active profile if set
-	if merged.ActiveProfile != "" {
-		if profile, exists := merged.Profiles[merged.ActiveProfile]; exists {
+	// Apply active profile if set; UseProfile overrides it without saving
+	active := merged.ActiveProfile
+	if m.profile != "" {
+		active = m.profile
+	}
+	if active != "" {
+		if profile, exists := merged.Profiles[active]; exists {
 			if profile.APIKey != "" {
 				merged.APIKey = profile.APIKey
 			}
@@ -257,12 +709,99 @@ func (m *Manager) mergeConfigs() *Config {
 	return &merged
 }
 
+// mergeExternalTools returns base extended with extra, where an entry in extra
+// replaces an entry of base with the same name or manifest. Relative manifest
+// paths in extra are resolved against dir, the directory of their config file.
+func mergeExternalTools(base, extra []ExternalTool, dir string) []ExternalTool {
+	merged := append([]ExternalTool(nil), base...)
+	for _, tool := range extra {
+		if tool.Manifest != "" && !filepath.IsAbs(tool.Manifest) {
+			tool.Manifest = filepath.Join(dir, tool.Manifest)
+		}
+		replaced := false
+		for i, existing := range merged {
+			if (tool.Name != "" && existing.Name == tool.Name) ||
+				(tool.Name == "" && tool.Manifest != "" && existing.Manifest == tool.Manifest) {
+				merged[i] = tool
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			merged = append(merged, tool)
+		}
+	}
+	return merged
+}
+
+// mergePluginPaths returns base extended with the plugin manifests in extra
+// that it does not list yet, resolving relative paths against dir
+func mergePluginPaths(base, extra []string, dir string) []string {
+	merged := append([]string(nil), base...)
+	for _, path := range extra {
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		if !slices.Contains(merged, path) {
+			merged = append(merged, path)
+		}
+	}
+	return merged
+}
+
+// mergeFileTemplates returns the templates of extra, which take priority, in
+// front of base, resolving their relative template files against dir
+func mergeFileTemplates(base, extra []FileTemplate, dir string) []FileTemplate {
+	var merged []FileTemplate
+	for _, template := range extra {
+		if template.File != "" {
+			template.File = resolveConfigPath(template.File, dir)
+		}
+		merged = append(merged, template)
+	}
+	return append(merged, base...)
+}
+
+// resolveConfigPath resolves a path from a config file: ~ is the home
+// directory and relative paths are relative to the config file's directory
+func resolveConfigPath(path, dir string) string {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, rest)
+		}
+	}
+	if !filepath.IsAbs(path) {
+		return filepath.Join(dir, path)
+	}
+	return path
+}
+
 func (m *Manager) applyEnvironmentOverrides() {
 	if apiKey := os.Getenv("DEEPSEEK_API_KEY"); apiKey != "" {
 		m.mergedConfig.APIKey = apiKey
 	}
 }
 
+// UseProfile applies the named profile instead of active_profile for this
+// run, without changing the configuration files
+func (m *Manager) UseProfile(name string) error {
+	if _, exists := m.Get().Profiles[name]; !exists {
+		names := m.ProfileNames()
+		if len(names) == 0 {
+			return fmt.Errorf("unknown profile '%s': no profiles are configured", name)
+		}
+		return fmt.Errorf("unknown profile '%s'. Valid profiles are: %s", name, strings.Join(names, ", "))
+	}
+	m.profile = name
+	m.mergedConfig = m.mergeConfigs()
+	return nil
+}
+
+// ProfileNames returns the names of the configured profiles, sorted
+func (m *Manager) ProfileNames() []string {
+	return slices.Sorted(maps.Keys(m.Get().Profiles))
+}
+
 func (m *Manager) Get() *Config {
 	if m.mergedConfig == nil {
 		return &defaultConfig
@@ -278,12 +817,29 @@ func (m *Manager) GetModel() string {
 	return m.Get().Model
 }
 
+// GetTemperature returns the temperature of the active mode, or the configured one
 func (m *Manager) GetTemperature() float64 {
-	return m.Get().Temperature
+	cfg := m.Get()
+	if preset, ok := LookupMode(cfg.Mode); ok {
+		return preset.Temperature
+	}
+	return cfg.Temperature
 }
 
+// GetMaxTokens returns the configured max tokens, limited to what the model accepts
 func (m *Manager) GetMaxTokens() int {
-	return m.Get().MaxTokens
+	cfg := m.Get()
+	return ClampMaxTokens(cfg.Model, cfg.MaxTokens)
+}
+
+// GetMaxContextSize returns the file context limit in bytes, derived from the
+// model's context window unless max_context_size is set
+func (m *Manager) GetMaxContextSize() int {
+	cfg := m.Get()
+	if cfg.MaxContextSize > 0 {
+		return cfg.MaxContextSize
+	}
+	return ContextBudget(cfg.Model, cfg.MaxTokens)
 }
 
 func (m *Manager) HasAPIKey() bool {
@@ -312,6 +868,14 @@ func (m *Manager) SaveGlobal(cfg *Config) error {
 }
 
 func (m *Manager) SaveProject(cfg *Config) error {
+	if m.untrusted {
+		return fmt.Errorf("%s is not trusted; run 'deecli trust' to use a project config there", m.projectDir)
+	}
+	// Trust is granted in the global config only
+	project := *cfg
+	project.TrustedDirectories = nil
+	cfg = &project
+
 	// Ensure directory exists
 	dir := filepath.Dir(m.projectPath)
 	if err := os.MkdirAll(dir, 0755); err != nil {
@@ -343,6 +907,7 @@ func (m *Manager) InitGlobalConfig(apiKey string) error {
 		AutoReloadFiles:  defaultConfig.AutoReloadFiles,
 		AutoReloadDebounce: defaultConfig.AutoReloadDebounce,
 		ShowReloadNotices: defaultConfig.ShowReloadNotices,
+		RedactSecrets:    defaultConfig.RedactSecrets,
 	}
 
 	return m.SaveGlobal(cfg)
@@ -455,6 +1020,407 @@ func (m *Manager) SetKeyBinding(keyType, key string) error {
 	return m.SaveGlobal(cfg)
 }
 
+// GetIgnorePatterns returns the extra file ignore patterns from global and project config
+func (m *Manager) GetIgnorePatterns() []string {
+	cfg := m.Get()
+	return cfg.Ignore
+}
+
+// GetRedactSecrets returns whether secrets are masked before sending to the API
+func (m *Manager) GetRedactSecrets() bool {
+	cfg := m.Get()
+	return cfg.RedactSecrets
+}
+
+// GetRedactPatterns returns the extra secret patterns from global and project config
+func (m *Manager) GetRedactPatterns() []string {
+	cfg := m.Get()
+	return cfg.RedactPatterns
+}
+
+// GetNotifyOnComplete returns the completion notification mode
+func (m *Manager) GetNotifyOnComplete() string {
+	cfg := m.Get()
+	if cfg.NotifyOnComplete == "" {
+		return "off"
+	}
+	return cfg.NotifyOnComplete
+}
+
+// GetMessageTimestamps returns how message timestamps are shown: off,
+// relative (to the start of the session) or absolute
+func (m *Manager) GetMessageTimestamps() string {
+	cfg := m.Get()
+	if cfg.MessageTimestamps == "" {
+		return "off"
+	}
+	return cfg.MessageTimestamps
+}
+
+// GetSidebarSort returns the order of the files sidebar: loaded (most
+// recently loaded first), name, size or changed (most recently modified first)
+func (m *Manager) GetSidebarSort() string {
+	cfg := m.Get()
+	if cfg.SidebarSort == "" {
+		return "loaded"
+	}
+	return cfg.SidebarSort
+}
+
+// GetSidebarGroup returns whether the files sidebar is grouped by directory
+func (m *Manager) GetSidebarGroup() bool {
+	cfg := m.Get()
+	return cfg.SidebarGroup
+}
+
+// SetSidebarView saves the order and grouping chosen in the files sidebar to
+// the file /config set writes by default: the project config when there is
+// one, else the global config
+func (m *Manager) SetSidebarView(sort string, group bool) error {
+	if err := ValidateSidebarSort(sort); err != nil {
+		return err
+	}
+	set := func(cfg *Config) *Config {
+		saved := Config{}
+		if cfg != nil {
+			saved = *cfg
+		}
+		saved.SidebarSort = sort
+		saved.SidebarGroup = group
+		return &saved
+	}
+
+	if m.ProjectConfigExists() && !m.untrusted {
+		project := set(m.projectConfig)
+		if err := m.SaveProject(project); err != nil {
+			return err
+		}
+		m.projectConfig = project
+	} else {
+		global := set(m.globalConfig)
+		if err := m.SaveGlobal(global); err != nil {
+			return err
+		}
+		m.globalConfig = global
+	}
+
+	if m.mergedConfig != nil {
+		m.mergedConfig.SidebarSort = sort
+		m.mergedConfig.SidebarGroup = group
+	}
+	return nil
+}
+
+// GetPlainMode returns whether the plain UI is always used
+func (m *Manager) GetPlainMode() bool {
+	cfg := m.Get()
+	return cfg.PlainMode
+}
+
+// GetPlainModeWidth returns the terminal width below which the plain UI is used, or 0 if disabled
+func (m *Manager) GetPlainModeWidth() int {
+	cfg := m.Get()
+	if cfg.PlainModeWidth == 0 {
+		return DefaultPlainModeWidth
+	}
+	if cfg.PlainModeWidth < 0 {
+		return 0
+	}
+	return cfg.PlainModeWidth
+}
+
+// GetScreenReader returns whether screen-reader friendly output is enabled
+func (m *Manager) GetScreenReader() bool {
+	cfg := m.Get()
+	return cfg.ScreenReader
+}
+
+// GetExtractText returns whether PDFs and images are loaded as extracted text
+func (m *Manager) GetExtractText() bool {
+	cfg := m.Get()
+	return cfg.ExtractText
+}
+
+// GetLazyLoadThreshold returns how many loaded files trigger lazy loading, or 0 if disabled
+func (m *Manager) GetLazyLoadThreshold() int {
+	cfg := m.Get()
+	if cfg.LazyLoadThreshold == 0 {
+		return DefaultLazyLoadThreshold
+	}
+	if cfg.LazyLoadThreshold < 0 {
+		return 0
+	}
+	return cfg.LazyLoadThreshold
+}
+
+// GetHistoryMaxEntries returns how many commands the input history file keeps
+func (m *Manager) GetHistoryMaxEntries() int {
+	cfg := m.Get()
+	if cfg.HistoryMaxEntries <= 0 {
+		return DefaultHistoryMaxEntries
+	}
+	return cfg.HistoryMaxEntries
+}
+
+// GetStreamStallTimeout returns how long a stream may go without data before
+// it counts as stalled, or 0 if stall detection is disabled
+func (m *Manager) GetStreamStallTimeout() time.Duration {
+	cfg := m.Get()
+	if cfg.StreamStallTimeout == 0 {
+		return DefaultStreamStallTimeout * time.Second
+	}
+	if cfg.StreamStallTimeout < 0 {
+		return 0
+	}
+	return time.Duration(cfg.StreamStallTimeout) * time.Second
+}
+
+// GetStreamAutoReconnect returns whether a stalled stream is replayed once automatically
+func (m *Manager) GetStreamAutoReconnect() bool {
+	cfg := m.Get()
+	return cfg.StreamAutoReconnect
+}
+
+// GetWarmUpOnStart returns whether the API connection is warmed up when the chat starts
+func (m *Manager) GetWarmUpOnStart() bool {
+	cfg := m.Get()
+	return cfg.WarmUpOnStart
+}
+
+// GetCheckUpdates returns whether the chat looks for a newer release when it starts
+func (m *Manager) GetCheckUpdates() bool {
+	cfg := m.Get()
+	return cfg.CheckUpdates
+}
+
+// GetTelemetry returns whether usage statistics are kept on this machine,
+// "local", or not at all, "off"
+func (m *Manager) GetTelemetry() string {
+	cfg := m.Get()
+	if cfg.Telemetry == "" {
+		return "local"
+	}
+	return cfg.Telemetry
+}
+
+// GetProxy returns the proxy URL for API requests, or "" to use the
+// HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables
+func (m *Manager) GetProxy() string {
+	cfg := m.Get()
+	return cfg.Proxy
+}
+
+// GetCABundle returns the path of the extra certificate authorities to trust
+func (m *Manager) GetCABundle() string {
+	cfg := m.Get()
+	return cfg.CABundle
+}
+
+// GetInsecureSkipVerify returns whether TLS certificate verification is skipped
+func (m *Manager) GetInsecureSkipVerify() bool {
+	cfg := m.Get()
+	return cfg.InsecureSkipVerify
+}
+
+// GetRequestMetrics returns whether per-request metrics are written to the metrics log
+func (m *Manager) GetRequestMetrics() bool {
+	cfg := m.Get()
+	return cfg.RequestMetrics
+}
+
+// GetEditorContext returns whether the cursor position in the editor is
+// offered as context for the next message
+func (m *Manager) GetEditorContext() bool {
+	cfg := m.Get()
+	return cfg.EditorContext
+}
+
+// GetScrollbackLimit returns how many chat messages are kept in memory, or 0 if all are
+func (m *Manager) GetScrollbackLimit() int {
+	cfg := m.Get()
+	if cfg.ScrollbackLimit == 0 {
+		return DefaultScrollbackLimit
+	}
+	if cfg.ScrollbackLimit < 0 {
+		return 0
+	}
+	return cfg.ScrollbackLimit
+}
+
+// GetToolCallsPerTurn returns how many tool calls one message may trigger, or 0 if unlimited
+func (m *Manager) GetToolCallsPerTurn() int {
+	cfg := m.Get()
+	if cfg.ToolCallsPerTurn == 0 {
+		return DefaultToolCallsPerTurn
+	}
+	if cfg.ToolCallsPerTurn < 0 {
+		return 0
+	}
+	return cfg.ToolCallsPerTurn
+}
+
+// GetToolCallsPerMinute returns how many tool calls are allowed in any minute, or 0 if unlimited
+func (m *Manager) GetToolCallsPerMinute() int {
+	cfg := m.Get()
+	if cfg.ToolCallsPerMinute == 0 {
+		return DefaultToolCallsPerMinute
+	}
+	if cfg.ToolCallsPerMinute < 0 {
+		return 0
+	}
+	return cfg.ToolCallsPerMinute
+}
+
+// GetMaxFileSize returns the size in bytes of the largest file that can be loaded
+func (m *Manager) GetMaxFileSize() int64 {
+	cfg := m.Get()
+	if cfg.MaxFileSize <= 0 {
+		return DefaultMaxFileSize * 1024
+	}
+	return int64(cfg.MaxFileSize) * 1024
+}
+
+// GetMaxLoadedFiles returns how many files can be loaded at once
+func (m *Manager) GetMaxLoadedFiles() int {
+	cfg := m.Get()
+	if cfg.MaxLoadedFiles <= 0 {
+		return DefaultMaxLoadedFiles
+	}
+	return cfg.MaxLoadedFiles
+}
+
+// GetMaxFilesPerLoad returns how many files one /load may match, by default
+// as many as can be loaded at once
+func (m *Manager) GetMaxFilesPerLoad() int {
+	cfg := m.Get()
+	if cfg.MaxFilesPerLoad <= 0 {
+		return m.GetMaxLoadedFiles()
+	}
+	return cfg.MaxFilesPerLoad
+}
+
+// GetLargeFileThreshold returns the size in bytes above which files are previewed, or 0 if disabled
+func (m *Manager) GetLargeFileThreshold() int64 {
+	cfg := m.Get()
+	if cfg.LargeFileThreshold == 0 {
+		return DefaultLargeFileThreshold * 1024
+	}
+	if cfg.LargeFileThreshold < 0 {
+		return 0
+	}
+	return int64(cfg.LargeFileThreshold) * 1024
+}
+
+// GetLargeFilePreview returns the size in bytes of the preview kept for large files
+func (m *Manager) GetLargeFilePreview() int64 {
+	cfg := m.Get()
+	if cfg.LargeFilePreview <= 0 {
+		return DefaultLargeFilePreview * 1024
+	}
+	return int64(cfg.LargeFilePreview) * 1024
+}
+
+// GetInstructionFileSize returns how many bytes of suggestions and code the
+// instruction file of /edit keeps, or 0 if instruction files are off
+func (m *Manager) GetInstructionFileSize() int {
+	cfg := m.Get()
+	if cfg.InstructionFileSize == 0 {
+		return DefaultInstructionFileSize * 1024
+	}
+	if cfg.InstructionFileSize < 0 {
+		return 0
+	}
+	return cfg.InstructionFileSize * 1024
+}
+
+// GetEditorCommands returns the command templates opening a file with its
+// instruction file and at a line; "" leaves the choice to the editor found
+func (m *Manager) GetEditorCommands() (string, string) {
+	cfg := m.Get()
+	return cfg.EditorCmd, cfg.EditorGotoCmd
+}
+
+// GetExternalTools returns the configured external tool definitions
+func (m *Manager) GetExternalTools() []ExternalTool {
+	return m.Get().ExternalTools
+}
+
+// GetFormatters returns the formatter command for each language, the
+// defaults included; a language configured with an empty command is left out
+func (m *Manager) GetFormatters() map[string][]string {
+	formatters := maps.Clone(DefaultFormatters)
+	for language, command := range m.Get().Formatters {
+		if len(command) == 0 {
+			delete(formatters, language)
+		} else {
+			formatters[language] = command
+		}
+	}
+	return formatters
+}
+
+// GetFileTemplates returns the templates for new files, project ones first
+func (m *Manager) GetFileTemplates() []FileTemplate {
+	return m.Get().FileTemplates
+}
+
+// GetLicenseHeader returns the file with the license header of new files,
+// or "" if none is configured
+func (m *Manager) GetLicenseHeader() string {
+	return m.Get().LicenseHeader
+}
+
+// GetPlugins returns the manifest paths of the configured tool plugins
+func (m *Manager) GetPlugins() []string {
+	return m.Get().Plugins
+}
+
+// GetWasmRuntime returns the WASI runtime command used to run tool plugins
+func (m *Manager) GetWasmRuntime() string {
+	if runtime := m.Get().WasmRuntime; runtime != "" {
+		return runtime
+	}
+	return DefaultWasmRuntime
+}
+
+// GetGitHubToken returns the GitHub token from the config, or from
+// GITHUB_TOKEN or GH_TOKEN when none is configured
+func (m *Manager) GetGitHubToken() string {
+	if token := m.Get().GitHubToken; token != "" {
+		return token
+	}
+	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
+		return token
+	}
+	return os.Getenv("GH_TOKEN")
+}
+
+// GetGitLabToken returns the GitLab token from the config, or from
+// GITLAB_TOKEN when none is configured
+func (m *Manager) GetGitLabToken() string {
+	if token := m.Get().GitLabToken; token != "" {
+		return token
+	}
+	return os.Getenv("GITLAB_TOKEN")
+}
+
+// GetCommitLint returns the commit message rules, or nil when linting is off
+func (m *Manager) GetCommitLint() *CommitLint {
+	if lint := m.Get().CommitLint; lint != nil && lint.Enabled {
+		return lint
+	}
+	return nil
+}
+
+// GetLanguage returns the configured UI language, or "auto" if unset
+func (m *Manager) GetLanguage() string {
+	cfg := m.Get()
+	if cfg.Language == "" {
+		return i18n.Auto
+	}
+	return cfg.Language
+}
+
 // GetSyntaxHighlightEnabled returns whether syntax highlighting is enabled
 func (m *Manager) GetSyntaxHighlightEnabled() bool {
 	cfg := m.Get()
@@ -493,6 +1459,18 @@ var (
 	// ValidModels contains the list of supported DeepSeek models
 	ValidModels = []string{"deepseek-chat", "deepseek-reasoner"}
 
+	// ValidNotifyModes contains the accepted notify_on_complete values
+	ValidNotifyModes = []string{"off", "bell", "desktop", "both"}
+
+	// ValidTimestampModes contains the accepted message_timestamps values
+	ValidTimestampModes = []string{"off", "relative", "absolute"}
+
+	// ValidSidebarSorts contains the accepted sidebar_sort values
+	ValidSidebarSorts = []string{"loaded", "name", "size", "changed"}
+
+	// ValidTelemetryModes contains the accepted telemetry values
+	ValidTelemetryModes = []string{"local", "off"}
+
 	// KeyBindingPattern matches valid key binding formats like ctrl+j, alt+enter, shift+tab
 	KeyBindingPattern = regexp.MustCompile(`^(ctrl|alt|shift|cmd|meta)(\+(ctrl|alt|shift|cmd|meta))*\+([a-z0-9]|enter|tab|space|escape|esc|up|down|left|right|home|end|pageup|pagedown|f[1-9]|f1[0-2])$|^(enter|tab|space|escape|esc|up|down|left|right|home|end|pageup|pagedown|f[1-9]|f1[0-2])$`)
 )
@@ -513,6 +1491,151 @@ func ValidateModel(model string) error {
 		model, strings.Join(ValidModels, ", "))
 }
 
+// ValidateNotifyOnComplete checks if the notification mode is valid
+func ValidateNotifyOnComplete(mode string) error {
+	if mode == "" {
+		return nil // Empty is ok, will use default
+	}
+
+	for _, valid := range ValidNotifyModes {
+		if mode == valid {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("invalid notify_on_complete '%s'. Valid values are: %s",
+		mode, strings.Join(ValidNotifyModes, ", "))
+}
+
+// ValidateTelemetry checks if the telemetry mode is valid
+func ValidateTelemetry(mode string) error {
+	if mode == "" {
+		return nil // Empty is ok, will use default
+	}
+
+	for _, valid := range ValidTelemetryModes {
+		if mode == valid {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("invalid telemetry '%s'. Valid values are: %s",
+		mode, strings.Join(ValidTelemetryModes, ", "))
+}
+
+// ValidateMessageTimestamps checks if the timestamp mode is valid
+func ValidateMessageTimestamps(mode string) error {
+	if mode == "" {
+		return nil // Empty is ok, will use default
+	}
+
+	for _, valid := range ValidTimestampModes {
+		if mode == valid {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("invalid message_timestamps '%s'. Valid values are: %s",
+		mode, strings.Join(ValidTimestampModes, ", "))
+}
+
+// ValidateSidebarSort checks if the files sidebar order is valid
+func ValidateSidebarSort(sort string) error {
+	if sort == "" {
+		return nil // Empty is ok, will use default
+	}
+
+	for _, valid := range ValidSidebarSorts {
+		if sort == valid {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("invalid sidebar_sort '%s'. Valid values are: %s",
+		sort, strings.Join(ValidSidebarSorts, ", "))
+}
+
+// ValidateProxy checks that the proxy is an absolute http, https or socks5 URL
+func ValidateProxy(proxy string) error {
+	if proxy == "" {
+		return nil // Empty is ok, the environment is used
+	}
+
+	u, err := url.Parse(proxy)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("invalid proxy '%s': expected a URL such as http://proxy.example.com:8080", proxy)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5":
+		return nil
+	}
+	return fmt.Errorf("invalid proxy scheme '%s'. Valid schemes are: http, https, socks5", u.Scheme)
+}
+
+// EditorPlaceholders are the placeholders editor command templates can use
+var EditorPlaceholders = []string{"{editor}", "{target}", "{file}", "{line}", "{instructions}"}
+
+// editorPlaceholder finds the placeholders of an editor command template
+var editorPlaceholder = regexp.MustCompile(`\{[a-z]+\}`)
+
+// ValidateEditorCommand checks that an editor command template names a
+// program, opens the file and uses only known placeholders
+func ValidateEditorCommand(template string) error {
+	if template == "" {
+		return nil // Empty is ok, the editor found is used
+	}
+	args, err := SplitCommand(template)
+	if err != nil {
+		return fmt.Errorf("invalid editor command '%s': %w", template, err)
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("invalid editor command '%s': no program", template)
+	}
+	for _, p := range editorPlaceholder.FindAllString(template, -1) {
+		if !slices.Contains(EditorPlaceholders, p) {
+			return fmt.Errorf("unknown placeholder %s in editor command. Valid placeholders are: %s", p, strings.Join(EditorPlaceholders, ", "))
+		}
+	}
+	if !strings.Contains(template, "{target}") && !strings.Contains(template, "{file}") {
+		return fmt.Errorf("editor command '%s' must open {target} or {file}", template)
+	}
+	return nil
+}
+
+// SplitCommand splits a command line into arguments at spaces, keeping
+// text in single or double quotes together
+func SplitCommand(command string) ([]string, error) {
+	var args []string
+	var arg strings.Builder
+	inArg, quote := false, rune(0)
+	for _, r := range command {
+		switch {
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote != 0:
+			arg.WriteRune(r)
+		case r == '\'' || r == '"':
+			quote, inArg = r, true
+		case r == ' ' || r == '\t':
+			if inArg {
+				args = append(args, arg.String())
+				arg.Reset()
+				inArg = false
+			}
+		default:
+			arg.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if inArg {
+		args = append(args, arg.String())
+	}
+	return args, nil
+}
+
 // ValidateAPIKey performs basic validation on the API key
 func ValidateAPIKey(apiKey string) error {
 	if apiKey == "" {
@@ -558,12 +1681,32 @@ func ValidateMaxTokens(tokens int) error {
 	if tokens <= 0 {
 		return fmt.Errorf("max_tokens must be positive, got: %d", tokens)
 	}
-	// deepseek-reasoner supports up to 64K tokens output
-	// deepseek-chat supports up to 8K tokens output
-	// We'll allow up to 65536 to support both models
-	if tokens > 65536 {
-		return fmt.Errorf("max_tokens exceeds maximum (65536), got: %d", tokens)
+	// Per-model limits are checked by ValidateMaxTokensForModel once the model is known
+	if limit := MaxOutputTokens(); tokens > limit {
+		return fmt.Errorf("max_tokens exceeds maximum (%d), got: %d", limit, tokens)
+	}
+	return nil
+}
+
+// ValidatePlainModeWidth checks if the plain UI width threshold is valid
+func ValidatePlainModeWidth(width int) error {
+	if width > 500 {
+		return fmt.Errorf("plain_mode_width too large: %d (maximum 500, negative disables)", width)
+	}
+	return nil
+}
+
+// ValidateLanguage checks if the UI language has a message catalog
+func ValidateLanguage(lang string) error {
+	if lang == "" || lang == i18n.Auto {
+		return nil // Empty is ok, will follow the environment
 	}
+
+	if !i18n.IsSupported(lang) {
+		return fmt.Errorf("invalid language '%s'. Valid values are: %s, %s",
+			lang, i18n.Auto, strings.Join(i18n.Supported(), ", "))
+	}
+
 	return nil
 }
 
@@ -578,6 +1721,52 @@ func ValidateAutoReloadDebounce(debounce int) error {
 	return nil
 }
 
+// ValidateRedactPatterns checks that all redaction patterns are valid regular expressions
+func ValidateRedactPatterns(patterns []string) error {
+	for _, pattern := range patterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("invalid redact_patterns entry %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// ValidateFileLimits checks the file loading limits: the maximum file size
+// in KB, the files one /load may match and the files loaded at once. Zero
+// keeps the default of each.
+func ValidateFileLimits(maxFileSize, maxFilesPerLoad, maxLoadedFiles int) error {
+	if maxFileSize < 0 {
+		return fmt.Errorf("max_file_size cannot be negative, got: %d", maxFileSize)
+	}
+	if maxFileSize > 1024*1024 {
+		return fmt.Errorf("max_file_size too large: %d KB (maximum 1048576, 1 GB)", maxFileSize)
+	}
+	if maxFilesPerLoad < 0 {
+		return fmt.Errorf("max_files_per_load cannot be negative, got: %d", maxFilesPerLoad)
+	}
+	if maxLoadedFiles < 0 {
+		return fmt.Errorf("max_loaded_files cannot be negative, got: %d", maxLoadedFiles)
+	}
+	if maxLoadedFiles == 0 {
+		maxLoadedFiles = DefaultMaxLoadedFiles
+	}
+	if maxFilesPerLoad > maxLoadedFiles {
+		return fmt.Errorf("max_files_per_load (%d) cannot exceed max_loaded_files (%d)", maxFilesPerLoad, maxLoadedFiles)
+	}
+	return nil
+}
+
+// ValidateHistoryMaxEntries checks the size limit of the input history file
+func ValidateHistoryMaxEntries(entries int) error {
+	if entries < 0 {
+		return fmt.Errorf("history_max_entries cannot be negative, got: %d", entries)
+	}
+	if entries > 100000 {
+		return fmt.Errorf("history_max_entries too large: %d (maximum 100000)", entries)
+	}
+	return nil
+}
+
 // ValidateUserName checks if user name is valid
 func ValidateUserName(name string) error {
 	if name == "" {
@@ -659,6 +1848,76 @@ func (c *Config) Validate() error {
 		return err
 	}
 
+	// Validate redaction patterns
+	if err := ValidateRedactPatterns(c.RedactPatterns); err != nil {
+		return err
+	}
+
+	// Validate notification mode
+	if err := ValidateNotifyOnComplete(c.NotifyOnComplete); err != nil {
+		return err
+	}
+
+	// Validate timestamp mode
+	if err := ValidateMessageTimestamps(c.MessageTimestamps); err != nil {
+		return err
+	}
+
+	// Validate sidebar order
+	if err := ValidateSidebarSort(c.SidebarSort); err != nil {
+		return err
+	}
+
+	// Validate telemetry mode
+	if err := ValidateTelemetry(c.Telemetry); err != nil {
+		return err
+	}
+
+	// Validate proxy URL
+	if err := ValidateProxy(c.Proxy); err != nil {
+		return err
+	}
+
+	// Validate editor command templates
+	if err := ValidateEditorCommand(c.EditorCmd); err != nil {
+		return err
+	}
+	if err := ValidateEditorCommand(c.EditorGotoCmd); err != nil {
+		return err
+	}
+
+	// Validate plain UI threshold
+	if err := ValidatePlainModeWidth(c.PlainModeWidth); err != nil {
+		return err
+	}
+
+	// Validate UI language
+	if err := ValidateLanguage(c.Language); err != nil {
+		return err
+	}
+
+	// Validate temperature preset
+	if err := ValidateMode(c.Mode); err != nil {
+		return err
+	}
+
+	// Validate input history limit
+	if err := ValidateHistoryMaxEntries(c.HistoryMaxEntries); err != nil {
+		return err
+	}
+
+	// Validate file loading limits
+	if err := ValidateFileLimits(c.MaxFileSize, c.MaxFilesPerLoad, c.MaxLoadedFiles); err != nil {
+		return err
+	}
+
+	// Validate post-processor toggles
+	for name := range c.PostProcessors {
+		if err := ValidatePostProcessor(name); err != nil {
+			return err
+		}
+	}
+
 	// Validate profiles
 	for name, profile := range c.Profiles {
 		if err := ValidateModel(profile.Model); err != nil {
@@ -14,6 +14,9 @@
 package config
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -70,6 +73,99 @@ func TestValidateModel(t *testing.T) {
 	}
 }
 
+func TestValidateNotifyOnComplete(t *testing.T) {
+	tests := []struct {
+		name    string
+		mode    string
+		wantErr bool
+	}{
+		{name: "Empty mode is valid", mode: "", wantErr: false},
+		{name: "Off", mode: "off", wantErr: false},
+		{name: "Bell", mode: "bell", wantErr: false},
+		{name: "Desktop", mode: "desktop", wantErr: false},
+		{name: "Both", mode: "both", wantErr: false},
+		{name: "Invalid mode", mode: "loud", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateNotifyOnComplete(tt.mode)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), "invalid notify_on_complete")
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateProxy(t *testing.T) {
+	tests := []struct {
+		name    string
+		proxy   string
+		wantErr bool
+	}{
+		{name: "Empty uses the environment", proxy: "", wantErr: false},
+		{name: "HTTP proxy", proxy: "http://proxy.example.com:8080", wantErr: false},
+		{name: "SOCKS proxy", proxy: "socks5://127.0.0.1:1080", wantErr: false},
+		{name: "Missing scheme", proxy: "proxy.example.com:8080", wantErr: true},
+		{name: "Unsupported scheme", proxy: "ftp://proxy.example.com", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateProxy(tt.proxy)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateEditorCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		wantErr  bool
+	}{
+		{name: "Empty uses the editor found", template: "", wantErr: false},
+		{name: "Split with instructions", template: "nvim -O {target} {instructions}", wantErr: false},
+		{name: "Goto", template: "nvim +{line} {file}", wantErr: false},
+		{name: "Quoted program", template: "\"/opt/My Editor/edit\" {file}", wantErr: false},
+		{name: "No file", template: "nvim {instructions}", wantErr: true},
+		{name: "Unknown placeholder", template: "nvim {target} {column}", wantErr: true},
+		{name: "Unterminated quote", template: "nvim '{target}", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateEditorCommand(tt.template)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSplitCommand(t *testing.T) {
+	args, err := SplitCommand(`"/opt/My Editor/edit"  --title 'a "b"' {file}`)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/opt/My Editor/edit", "--title", `a "b"`, "{file}"}, args)
+}
+
+func TestResolveConfigPath(t *testing.T) {
+	assert.Equal(t, "/etc/ssl/corp.pem", resolveConfigPath("/etc/ssl/corp.pem", ".deecli"))
+	assert.Equal(t, filepath.Join(".deecli", "corp.pem"), resolveConfigPath("corp.pem", ".deecli"))
+	if home, err := os.UserHomeDir(); err == nil {
+		assert.Equal(t, filepath.Join(home, "corp.pem"), resolveConfigPath("~/corp.pem", ".deecli"))
+	}
+}
+
 func TestValidateUserName(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -350,6 +446,80 @@ func TestValidateMaxTokens(t *testing.T) {
 	}
 }
 
+func TestValidateFileLimits(t *testing.T) {
+	tests := []struct {
+		name            string
+		maxFileSize     int
+		maxFilesPerLoad int
+		maxLoadedFiles  int
+		wantErr         bool
+	}{
+		{name: "Defaults", wantErr: false},
+		{name: "Valid limits", maxFileSize: 2048, maxFilesPerLoad: 20, maxLoadedFiles: 100, wantErr: false},
+		{name: "Per load within default loaded", maxFilesPerLoad: 50, wantErr: false},
+		{name: "Per load above default loaded", maxFilesPerLoad: 51, wantErr: true},
+		{name: "Per load above loaded", maxFilesPerLoad: 30, maxLoadedFiles: 20, wantErr: true},
+		{name: "Negative file size", maxFileSize: -1, wantErr: true},
+		{name: "File size too large", maxFileSize: 2 * 1024 * 1024, wantErr: true},
+		{name: "Negative loaded files", maxLoadedFiles: -5, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateFileLimits(tt.maxFileSize, tt.maxFilesPerLoad, tt.maxLoadedFiles)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestManager_GetFileLimits(t *testing.T) {
+	m := &Manager{mergedConfig: &Config{}}
+	assert.Equal(t, int64(DefaultMaxFileSize*1024), m.GetMaxFileSize())
+	assert.Equal(t, DefaultMaxLoadedFiles, m.GetMaxLoadedFiles())
+	assert.Equal(t, DefaultMaxLoadedFiles, m.GetMaxFilesPerLoad())
+
+	m.mergedConfig = &Config{MaxFileSize: 512, MaxLoadedFiles: 80}
+	assert.Equal(t, int64(512*1024), m.GetMaxFileSize())
+	assert.Equal(t, 80, m.GetMaxFilesPerLoad())
+}
+
+func TestManager_SetSidebarView(t *testing.T) {
+	t.Setenv("DEEPSEEK_API_KEY", "sk-from-the-environment-1234567890")
+	dir := t.TempDir()
+	m := &Manager{
+		globalPath:  filepath.Join(dir, "global.yaml"),
+		projectPath: filepath.Join(dir, "project.yaml"),
+	}
+	assert.NoError(t, os.WriteFile(m.globalPath, []byte("model: deepseek-chat\n"), 0600))
+	assert.NoError(t, m.Load())
+	assert.Equal(t, "loaded", m.GetSidebarSort())
+
+	// Without a project config the view goes to the global one
+	assert.NoError(t, m.SetSidebarView("size", true))
+	assert.Equal(t, "size", m.GetSidebarSort())
+	assert.True(t, m.GetSidebarGroup())
+	data, err := os.ReadFile(m.globalPath)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "sidebar_sort: size")
+	assert.NotContains(t, string(data), "sk-from-the-environment", "the API key from the environment is not saved")
+
+	// With one, the project config gets it
+	assert.NoError(t, os.WriteFile(m.projectPath, []byte("model: deepseek-chat\n"), 0600))
+	assert.NoError(t, m.Load())
+	assert.NoError(t, m.SetSidebarView("changed", false))
+	data, err = os.ReadFile(m.projectPath)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "sidebar_sort: changed")
+	assert.NoError(t, m.Load())
+	assert.Equal(t, "changed", m.GetSidebarSort())
+
+	assert.Error(t, m.SetSidebarView("random", false))
+}
+
 func TestManager_GetUserName(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -385,6 +555,99 @@ func TestManager_GetUserName(t *testing.T) {
 	}
 }
 
+func TestManager_MergeBooleans(t *testing.T) {
+	t.Setenv("DEEPSEEK_API_KEY", "")
+	tests := []struct {
+		key string
+		get func(*Manager) bool
+	}{
+		{"redact_secrets", (*Manager).GetRedactSecrets},
+		{"plain_mode", (*Manager).GetPlainMode},
+		{"screen_reader", (*Manager).GetScreenReader},
+		{"extract_text", (*Manager).GetExtractText},
+		{"stream_auto_reconnect", (*Manager).GetStreamAutoReconnect},
+		{"warm_up_on_start", (*Manager).GetWarmUpOnStart},
+		{"insecure_skip_verify", (*Manager).GetInsecureSkipVerify},
+		{"request_metrics", (*Manager).GetRequestMetrics},
+		{"editor_context", (*Manager).GetEditorContext},
+		{"check_updates", (*Manager).GetCheckUpdates},
+		{"sidebar_group", (*Manager).GetSidebarGroup},
+	}
+	for _, tt := range tests {
+		for _, global := range []bool{true, false} {
+			dir := t.TempDir()
+			m := &Manager{
+				globalPath:  filepath.Join(dir, "global.yaml"),
+				projectPath: filepath.Join(dir, "project.yaml"),
+			}
+			assert.NoError(t, os.WriteFile(m.globalPath, []byte(fmt.Sprintf("model: deepseek-chat\n%s: %t\n", tt.key, global)), 0600))
+			assert.NoError(t, m.Load())
+			assert.Equal(t, global, tt.get(m), "%s: global config only", tt.key)
+
+			// A project config only overrides the booleans it sets
+			assert.NoError(t, os.WriteFile(m.projectPath, []byte("model: deepseek-chat\n"), 0600))
+			assert.NoError(t, m.Load())
+			assert.Equal(t, global, tt.get(m), "%s: project config without it", tt.key)
+
+			assert.NoError(t, os.WriteFile(m.projectPath, []byte(fmt.Sprintf("model: deepseek-chat\n%s: %t\n", tt.key, !global)), 0600))
+			assert.NoError(t, m.Load())
+			assert.Equal(t, !global, tt.get(m), "%s: project config setting it", tt.key)
+		}
+	}
+}
+
+func TestManager_DefaultsWithoutConfig(t *testing.T) {
+	t.Setenv("DEEPSEEK_API_KEY", "")
+	dir := t.TempDir()
+	m := &Manager{
+		globalPath:  filepath.Join(dir, "global.yaml"),
+		projectPath: filepath.Join(dir, "project.yaml"),
+	}
+	assert.NoError(t, m.Load())
+	assert.True(t, m.GetRedactSecrets(), "redaction is on by default")
+}
+
+func TestManager_GetFormatters(t *testing.T) {
+	m := &Manager{mergedConfig: &Config{}}
+	assert.Equal(t, []string{"gofmt"}, m.GetFormatters()["go"])
+
+	m.mergedConfig.Formatters = map[string][]string{
+		"go":     {"goimports"},
+		"python": {"black", "-q", "-"},
+	}
+	formatters := m.GetFormatters()
+	assert.Equal(t, []string{"goimports"}, formatters["go"])
+	assert.Equal(t, []string{"black", "-q", "-"}, formatters["python"])
+	assert.Equal(t, []string{"gofmt"}, DefaultFormatters["go"], "the defaults must not change")
+
+	m.mergedConfig.Formatters = map[string][]string{"go": {}}
+	_, ok := m.GetFormatters()["go"]
+	assert.False(t, ok, "an empty command disables the language")
+}
+
+func TestManager_UseProfile(t *testing.T) {
+	m := &Manager{
+		globalConfig: &Config{
+			Model:         "deepseek-chat",
+			ActiveProfile: "home",
+			Profiles: map[string]Profile{
+				"home": {Temperature: 0.5},
+				"work": {Model: "deepseek-reasoner"},
+			},
+		},
+		projectConfig: &Config{},
+	}
+	m.mergedConfig = m.mergeConfigs()
+	assert.Equal(t, []string{"home", "work"}, m.ProfileNames())
+
+	assert.NoError(t, m.UseProfile("work"))
+	assert.Equal(t, "deepseek-reasoner", m.GetModel())
+	assert.NotEqual(t, 0.5, m.Get().Temperature, "the active profile is replaced, not combined")
+	assert.Equal(t, "home", m.Get().ActiveProfile, "the saved active profile is unchanged")
+
+	assert.Error(t, m.UseProfile("missing"))
+}
+
 func TestConfig_Validate(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -478,4 +741,39 @@ func TestConfig_Validate(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+
+func TestMergeExternalTools(t *testing.T) {
+	global := []ExternalTool{
+		{Name: "lint", Command: []string{"golint"}},
+		{Manifest: "/opt/tools/search.json"},
+	}
+	project := []ExternalTool{
+		{Name: "lint", Command: []string{"./scripts/lint"}},
+		{Manifest: "tools/deploy.json"},
+	}
+
+	merged := mergeExternalTools(mergeExternalTools(nil, global, "/home/me/.deecli"), project, ".deecli")
+
+	assert.Len(t, merged, 3)
+	assert.Equal(t, []string{"./scripts/lint"}, merged[0].Command, "project tool replaces the global one")
+	assert.Equal(t, "/opt/tools/search.json", merged[1].Manifest)
+	assert.Equal(t, filepath.Join(".deecli", "tools", "deploy.json"), merged[2].Manifest, "relative manifest resolved against its config")
+	assert.Equal(t, "golint", global[0].Command[0], "inputs are not modified")
+}
+
+func TestMergeFileTemplates(t *testing.T) {
+	global := []FileTemplate{{Pattern: "*.go", File: "/opt/templates/go.tmpl"}}
+	project := []FileTemplate{
+		{Pattern: "**/handlers/*.go", File: "templates/handler.tmpl"},
+		{Pattern: "*.md", Template: "# {{.Name}}\n"},
+	}
+
+	merged := mergeFileTemplates(mergeFileTemplates(nil, global, "/home/me/.deecli"), project, ".deecli")
+
+	assert.Len(t, merged, 3)
+	assert.Equal(t, filepath.Join(".deecli", "templates", "handler.tmpl"), merged[0].File, "project templates come first, resolved against their config")
+	assert.Equal(t, "*.md", merged[1].Pattern)
+	assert.Equal(t, "/opt/templates/go.tmpl", merged[2].File)
+	assert.Equal(t, "templates/handler.tmpl", project[0].File, "inputs are not modified")
+}
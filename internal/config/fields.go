@@ -0,0 +1,221 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/antenore/deecli/internal/i18n"
+)
+
+// FieldKind is the type of value a config field holds
+type FieldKind int
+
+const (
+	FieldString FieldKind = iota
+	FieldBool
+	FieldInt
+	FieldFloat
+	FieldChoice // One of Field.Options
+)
+
+// Field is a config setting that can be edited by key, as in /config set and
+// the /config edit form
+type Field struct {
+	Key         string
+	Description string
+	Kind        FieldKind
+	Options     []string // Accepted values of a FieldChoice
+	Secret      bool     // Mask the value when displayed
+	get         func(*Config) string
+	set         func(*Config, string) error
+}
+
+// Get returns the field value of cfg as text
+func (f Field) Get(cfg *Config) string {
+	return f.get(cfg)
+}
+
+// Set parses and validates value and stores it in cfg; cfg is unchanged on error
+func (f Field) Set(cfg *Config, value string) error {
+	return f.set(cfg, strings.TrimSpace(value))
+}
+
+// Display returns the value as shown to the user, masking secrets
+func (f Field) Display(cfg *Config) string {
+	value := f.Get(cfg)
+	if f.Secret && value != "" {
+		if len(value) > 8 {
+			return value[:4] + "..." + value[len(value)-4:]
+		}
+		return "****"
+	}
+	return value
+}
+
+// ParseBool accepts the boolean spellings /config set understands
+func ParseBool(value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "true", "1", "yes", "on":
+		return true, nil
+	case "false", "0", "no", "off":
+		return false, nil
+	}
+	return false, fmt.Errorf("invalid value %q (use true/false)", value)
+}
+
+func boolField(key, description string, ptr func(*Config) *bool) Field {
+	return Field{
+		Key: key, Description: description, Kind: FieldBool,
+		get: func(c *Config) string { return strconv.FormatBool(*ptr(c)) },
+		set: func(c *Config, value string) error {
+			b, err := ParseBool(value)
+			if err != nil {
+				return err
+			}
+			*ptr(c) = b
+			return nil
+		},
+	}
+}
+
+func intField(key, description string, ptr func(*Config) *int, validate func(int) error) Field {
+	return Field{
+		Key: key, Description: description, Kind: FieldInt,
+		get: func(c *Config) string { return strconv.Itoa(*ptr(c)) },
+		set: func(c *Config, value string) error {
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return fmt.Errorf("invalid number %q", value)
+			}
+			if validate != nil {
+				if err := validate(n); err != nil {
+					return err
+				}
+			}
+			*ptr(c) = n
+			return nil
+		},
+	}
+}
+
+func stringField(key, description string, ptr func(*Config) *string, validate func(string) error) Field {
+	return Field{
+		Key: key, Description: description, Kind: FieldString,
+		get: func(c *Config) string { return *ptr(c) },
+		set: func(c *Config, value string) error {
+			if err := validate(value); err != nil {
+				return err
+			}
+			*ptr(c) = value
+			return nil
+		},
+	}
+}
+
+func choiceField(key, description string, options []string, ptr func(*Config) *string, validate func(string) error) Field {
+	f := stringField(key, description, ptr, validate)
+	f.Kind = FieldChoice
+	f.Options = options
+	return f
+}
+
+// Fields returns the settings that can be edited by key, in display order
+func Fields() []Field {
+	apiKey := stringField("api-key", "DeepSeek API key", func(c *Config) *string { return &c.APIKey }, ValidateAPIKey)
+	apiKey.Secret = true
+
+	return []Field{
+		apiKey,
+		choiceField("model", "Model used for chat", ValidModels, func(c *Config) *string { return &c.Model }, ValidateModel),
+		{
+			Key: "temperature", Description: "Sampling temperature (0.0-2.0)", Kind: FieldFloat,
+			get: func(c *Config) string { return strconv.FormatFloat(c.Temperature, 'f', -1, 64) },
+			set: func(c *Config, value string) error {
+				t, err := strconv.ParseFloat(value, 64)
+				if err != nil {
+					return fmt.Errorf("invalid temperature %q", value)
+				}
+				if err := ValidateTemperature(t); err != nil {
+					return err
+				}
+				c.Temperature = t
+				return nil
+			},
+		},
+		choiceField("mode", "Temperature preset, overrides temperature", append(ModeNames(), ModeOff), func(c *Config) *string { return &c.Mode }, ValidateMode),
+		boolField("mode-prompt", "Add the mode's system prompt variant", func(c *Config) *bool { return &c.ModePrompt }),
+		intField("max-tokens", "Maximum tokens per response", func(c *Config) *int { return &c.MaxTokens }, ValidateMaxTokens),
+		stringField("user-name", "Your name in the chat", func(c *Config) *string { return &c.UserName }, ValidateUserName),
+		choiceField("language", "UI language", append([]string{i18n.Auto}, i18n.Supported()...), func(c *Config) *string { return &c.Language }, ValidateLanguage),
+		boolField("auto-reload-files", "Reload loaded files when they change on disk", func(c *Config) *bool { return &c.AutoReloadFiles }),
+		intField("auto-reload-debounce", "Auto-reload debounce in ms", func(c *Config) *int { return &c.AutoReloadDebounce }, ValidateAutoReloadDebounce),
+		boolField("show-reload-notices", "Show a notice when files are reloaded", func(c *Config) *bool { return &c.ShowReloadNotices }),
+		boolField("redact-secrets", "Mask secrets before sending them to the API", func(c *Config) *bool { return &c.RedactSecrets }),
+		choiceField("notify-on-complete", "Notify when a response finishes unfocused", ValidNotifyModes, func(c *Config) *string { return &c.NotifyOnComplete }, ValidateNotifyOnComplete),
+		choiceField("message-timestamps", "Timestamps on messages", ValidTimestampModes, func(c *Config) *string { return &c.MessageTimestamps }, ValidateMessageTimestamps),
+		choiceField("sidebar-sort", "Order of the files sidebar (s in the sidebar)", ValidSidebarSorts, func(c *Config) *string { return &c.SidebarSort }, ValidateSidebarSort),
+		boolField("sidebar-group", "Group the files sidebar by directory (g in the sidebar)", func(c *Config) *bool { return &c.SidebarGroup }),
+		boolField("plain-mode", "Always use the plain UI", func(c *Config) *bool { return &c.PlainMode }),
+		intField("plain-mode-width", "Use the plain UI below this width (negative disables)", func(c *Config) *int { return &c.PlainModeWidth }, ValidatePlainModeWidth),
+		boolField("screen-reader", "Text labels instead of spinners, emoji and colors", func(c *Config) *bool { return &c.ScreenReader }),
+		boolField("extract-text", "Load PDFs and images as text with pdftotext or tesseract", func(c *Config) *bool { return &c.ExtractText }),
+		boolField("syntax-highlight", "Highlight code blocks", func(c *Config) *bool { return &c.SyntaxHighlight }),
+		choiceField("code-block-style", "Code block style", []string{"simple", "bordered"}, func(c *Config) *string { return &c.CodeBlockStyle }, func(style string) error {
+			if style != "" && style != "bordered" && style != "simple" {
+				return fmt.Errorf("invalid code block style: %s (must be 'bordered' or 'simple')", style)
+			}
+			return nil
+		}),
+		intField("history-max-entries", "Commands kept in the input history file", func(c *Config) *int { return &c.HistoryMaxEntries }, ValidateHistoryMaxEntries),
+		intField("scrollback-limit", "Chat messages kept in memory; older ones load with PgUp (negative disables)", func(c *Config) *int { return &c.ScrollbackLimit }, nil),
+		intField("tool-calls-per-turn", "Tool calls one message may trigger before the chain stops (negative disables)", func(c *Config) *int { return &c.ToolCallsPerTurn }, nil),
+		intField("tool-calls-per-minute", "Tool calls allowed in any minute (negative disables)", func(c *Config) *int { return &c.ToolCallsPerMinute }, nil),
+		intField("stream-stall-timeout", "Seconds without data before a stream counts as stalled (negative disables)", func(c *Config) *int { return &c.StreamStallTimeout }, nil),
+		boolField("stream-auto-reconnect", "Replay a stalled stream once before asking", func(c *Config) *bool { return &c.StreamAutoReconnect }),
+		boolField("warm-up-on-start", "Open the API connection in the background at startup", func(c *Config) *bool { return &c.WarmUpOnStart }),
+		boolField("check-updates", "Look for a newer release once a day at startup", func(c *Config) *bool { return &c.CheckUpdates }),
+		choiceField("telemetry", "Usage statistics for /stats, kept on this machine", ValidTelemetryModes, func(c *Config) *string { return &c.Telemetry }, ValidateTelemetry),
+		boolField("request-metrics", "Log per-request size, latency and retries to .deecli/requests.jsonl", func(c *Config) *bool { return &c.RequestMetrics }),
+		boolField("editor-context", "Pre-fill the input with where you were in the editor", func(c *Config) *bool { return &c.EditorContext }),
+		intField("instruction-file-size", "Size in KB of the instruction file opened by /edit (negative disables)", func(c *Config) *int { return &c.InstructionFileSize }, nil),
+		stringField("editor-cmd", "Editor command with the instruction file, e.g. nvim -O {target} {instructions}", func(c *Config) *string { return &c.EditorCmd }, ValidateEditorCommand),
+		stringField("editor-goto-cmd", "Editor command opening a file at a line, e.g. nvim +{line} {file}", func(c *Config) *string { return &c.EditorGotoCmd }, ValidateEditorCommand),
+		stringField("proxy", "Proxy URL for API requests; empty uses HTTP(S)_PROXY", func(c *Config) *string { return &c.Proxy }, ValidateProxy),
+		stringField("ca-bundle", "PEM file with extra certificate authorities to trust", func(c *Config) *string { return &c.CABundle }, func(string) error { return nil }),
+		boolField("insecure-skip-verify", "Skip TLS certificate verification (unsafe)", func(c *Config) *bool { return &c.InsecureSkipVerify }),
+		intField("lazy-load-threshold", "Files loaded before new ones are read lazily (negative disables)", func(c *Config) *int { return &c.LazyLoadThreshold }, nil),
+		intField("large-file-threshold", "KB above which only a preview is loaded (negative disables)", func(c *Config) *int { return &c.LargeFileThreshold }, nil),
+		intField("large-file-preview", "Preview size in KB for large files", func(c *Config) *int { return &c.LargeFilePreview }, func(n int) error {
+			if n <= 0 {
+				return fmt.Errorf("large_file_preview must be positive, got: %d", n)
+			}
+			return nil
+		}),
+		intField("max-file-size", "Size in KB of the largest file that can be loaded", func(c *Config) *int { return &c.MaxFileSize }, func(n int) error {
+			return ValidateFileLimits(n, 0, 0)
+		}),
+		intField("max-files-per-load", "Files one /load may match (at most max-loaded-files)", func(c *Config) *int { return &c.MaxFilesPerLoad }, func(n int) error {
+			if n < 0 {
+				return fmt.Errorf("max_files_per_load cannot be negative, got: %d", n)
+			}
+			return nil
+		}),
+		intField("max-loaded-files", "Files that can be loaded at once", func(c *Config) *int { return &c.MaxLoadedFiles }, func(n int) error {
+			return ValidateFileLimits(0, 0, n)
+		}),
+	}
+}
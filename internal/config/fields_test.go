@@ -0,0 +1,65 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import "testing"
+
+func TestFields(t *testing.T) {
+	fields := make(map[string]Field)
+	for _, f := range Fields() {
+		if _, dup := fields[f.Key]; dup {
+			t.Fatalf("duplicate field %s", f.Key)
+		}
+		fields[f.Key] = f
+	}
+
+	cfg := defaultConfig
+	tests := []struct {
+		key     string
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{"model", "deepseek-reasoner", "deepseek-reasoner", false},
+		{"model", "gpt-4", "deepseek-reasoner", true},
+		{"temperature", "0.7", "0.7", false},
+		{"temperature", "3", "0.7", true},
+		{"temperature", "warm", "0.7", true},
+		{"max-tokens", " 4096 ", "4096", false},
+		{"max-tokens", "0", "4096", true},
+		{"auto-reload-files", "off", "false", false},
+		{"auto-reload-files", "maybe", "false", true},
+		{"notify-on-complete", "bell", "bell", false},
+		{"large-file-preview", "-1", "32", true},
+		{"lazy-load-threshold", "-1", "-1", false},
+	}
+	for _, tt := range tests {
+		f := fields[tt.key]
+		err := f.Set(&cfg, tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Set(%s, %q) error = %v, wantErr %v", tt.key, tt.value, err, tt.wantErr)
+		}
+		if got := f.Get(&cfg); got != tt.want {
+			t.Errorf("after Set(%s, %q) Get() = %q, want %q", tt.key, tt.value, got, tt.want)
+		}
+	}
+
+	apiKey := fields["api-key"]
+	if err := apiKey.Set(&cfg, "sk-abcdefghijklmnopqrstuvwxyz"); err != nil {
+		t.Fatal(err)
+	}
+	if got := apiKey.Display(&cfg); got != "sk-a...wxyz" {
+		t.Errorf("Display() = %q, want the key masked", got)
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ModelInfo describes the limits of a model, in tokens
+type ModelInfo struct {
+	ContextWindow       int  // Tokens per request, prompt and response together
+	MaxOutputTokens     int  // Largest accepted max_tokens
+	SupportsTemperature bool // Whether the API honours the temperature parameter
+	SupportsSeed        bool // Whether the API accepts the seed parameter
+}
+
+// Models lists the capabilities of the supported models
+var Models = map[string]ModelInfo{
+	"deepseek-chat":     {ContextWindow: 128000, MaxOutputTokens: 8192, SupportsTemperature: true, SupportsSeed: true},
+	"deepseek-reasoner": {ContextWindow: 128000, MaxOutputTokens: 65536, SupportsTemperature: false, SupportsSeed: false},
+}
+
+// unknownModel is assumed for models missing from Models, so that newer
+// models are not held to the limits of older ones
+var unknownModel = ModelInfo{ContextWindow: 128000, MaxOutputTokens: 65536, SupportsTemperature: true, SupportsSeed: true}
+
+// LookupModel returns the capabilities of a model, matching its name case-insensitively
+func LookupModel(model string) ModelInfo {
+	if info, ok := Models[strings.ToLower(model)]; ok {
+		return info
+	}
+	if model == "" {
+		return Models[defaultConfig.Model]
+	}
+	return unknownModel
+}
+
+// MaxOutputTokens returns the largest max_tokens accepted by any known model
+func MaxOutputTokens() int {
+	limit := unknownModel.MaxOutputTokens
+	for _, info := range Models {
+		limit = max(limit, info.MaxOutputTokens)
+	}
+	return limit
+}
+
+// ClampMaxTokens limits maxTokens to what model accepts
+func ClampMaxTokens(model string, maxTokens int) int {
+	return min(maxTokens, LookupModel(model).MaxOutputTokens)
+}
+
+// ContextBudget returns how many bytes of file context fit in a request to
+// model that reserves maxTokens for the response. Half of the remaining window
+// is kept for the system prompt, tool definitions and conversation history.
+func ContextBudget(model string, maxTokens int) int {
+	info := LookupModel(model)
+	tokens := (info.ContextWindow - ClampMaxTokens(model, maxTokens)) / 2
+	return tokens * bytesPerToken
+}
+
+// bytesPerToken is the usual approximation of one token per four characters
+const bytesPerToken = 4
+
+// ValidateMaxTokensForModel checks that max tokens is accepted by model
+func ValidateMaxTokensForModel(model string, tokens int) error {
+	if err := ValidateMaxTokens(tokens); err != nil {
+		return err
+	}
+	if limit := LookupModel(model).MaxOutputTokens; tokens > limit {
+		if model == "" {
+			model = defaultConfig.Model
+		}
+		return fmt.Errorf("max_tokens exceeds the %s maximum (%d), got: %d", model, limit, tokens)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import "testing"
+
+func TestModels(t *testing.T) {
+	for _, model := range ValidModels {
+		if _, ok := Models[model]; !ok {
+			t.Errorf("model %s has no entry in Models", model)
+		}
+	}
+
+	if LookupModel("DeepSeek-Reasoner").SupportsTemperature {
+		t.Error("expected the reasoner to ignore temperature")
+	}
+	if got := LookupModel("").MaxOutputTokens; got != Models[defaultConfig.Model].MaxOutputTokens {
+		t.Errorf("expected the default model's limits for an empty name, got %d", got)
+	}
+	if got := ClampMaxTokens("deepseek-chat", 32768); got != 8192 {
+		t.Errorf("ClampMaxTokens() = %d, want 8192", got)
+	}
+
+	if err := ValidateMaxTokensForModel("deepseek-chat", 32768); err == nil {
+		t.Error("expected 32768 tokens to be rejected for deepseek-chat")
+	}
+	if err := ValidateMaxTokensForModel("deepseek-reasoner", 32768); err != nil {
+		t.Errorf("unexpected error for deepseek-reasoner: %v", err)
+	}
+}
+
+func TestManager_GetMaxContextSize(t *testing.T) {
+	m := &Manager{mergedConfig: &Config{Model: "deepseek-chat", MaxTokens: 2048}}
+	if got, want := m.GetMaxContextSize(), ContextBudget("deepseek-chat", 2048); got != want || got <= 100000 {
+		t.Errorf("GetMaxContextSize() = %d, want %d", got, want)
+	}
+
+	m.mergedConfig.MaxContextSize = 50000
+	if got := m.GetMaxContextSize(); got != 50000 {
+		t.Errorf("GetMaxContextSize() = %d, want the configured 50000", got)
+	}
+}
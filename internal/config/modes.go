@@ -0,0 +1,98 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ModeOff clears the temperature preset, also over one set in the global config
+const ModeOff = "off"
+
+// ModePreset is a temperature for a kind of task, with a matching system prompt
+type ModePreset struct {
+	Name        string
+	Temperature float64
+	Description string
+	Prompt      string // Added to the system prompt when mode_prompt is enabled
+}
+
+// ModePresets follows DeepSeek's recommended temperature for each use case
+var ModePresets = []ModePreset{
+	{
+		Name:        "coding",
+		Temperature: 0.0,
+		Description: "Coding and math",
+		Prompt:      "Favor correct, minimal code over long explanations. State your assumptions and prefer concrete diffs.",
+	},
+	{
+		Name:        "general",
+		Temperature: 1.3,
+		Description: "General conversation and translation",
+		Prompt:      "Answer conversationally and concisely, and only show code when it helps.",
+	},
+	{
+		Name:        "creative",
+		Temperature: 1.5,
+		Description: "Creative writing and brainstorming",
+		Prompt:      "Explore several alternatives, including unconventional ones, before recommending one.",
+	},
+}
+
+// ModeNames returns the names of the temperature presets
+func ModeNames() []string {
+	names := make([]string, len(ModePresets))
+	for i, preset := range ModePresets {
+		names[i] = preset.Name
+	}
+	return names
+}
+
+// LookupMode returns the preset called name; off and empty match none
+func LookupMode(name string) (ModePreset, bool) {
+	for _, preset := range ModePresets {
+		if strings.EqualFold(preset.Name, name) {
+			return preset, true
+		}
+	}
+	return ModePreset{}, false
+}
+
+// ValidateMode checks that mode names a preset
+func ValidateMode(mode string) error {
+	if mode == "" || mode == ModeOff {
+		return nil
+	}
+	if _, ok := LookupMode(mode); !ok {
+		return fmt.Errorf("invalid mode '%s'. Valid modes are: %s, %s", mode, strings.Join(ModeNames(), ", "), ModeOff)
+	}
+	return nil
+}
+
+// GetMode returns the active temperature preset, if any
+func (m *Manager) GetMode() (ModePreset, bool) {
+	return LookupMode(m.Get().Mode)
+}
+
+// GetModePrompt returns the system prompt variant of the active mode, or ""
+// when there is no mode or mode_prompt is off
+func (m *Manager) GetModePrompt() string {
+	cfg := m.Get()
+	preset, ok := LookupMode(cfg.Mode)
+	if !ok || !cfg.ModePrompt {
+		return ""
+	}
+	return preset.Prompt
+}
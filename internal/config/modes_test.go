@@ -0,0 +1,38 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import "testing"
+
+func TestManager_GetTemperature_Mode(t *testing.T) {
+	m := &Manager{mergedConfig: &Config{Temperature: 0.7, Mode: "coding", ModePrompt: true}}
+	if got := m.GetTemperature(); got != 0 {
+		t.Errorf("GetTemperature() = %v, want the coding preset 0", got)
+	}
+	if m.GetModePrompt() == "" {
+		t.Error("expected the coding system prompt")
+	}
+
+	m.mergedConfig.Mode = ModeOff
+	if got := m.GetTemperature(); got != 0.7 {
+		t.Errorf("GetTemperature() = %v, want the configured 0.7", got)
+	}
+	if m.GetModePrompt() != "" {
+		t.Error("expected no system prompt without a mode")
+	}
+
+	if err := ValidateMode("poetry"); err == nil {
+		t.Error("expected an unknown mode to be rejected")
+	}
+}
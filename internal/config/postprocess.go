@@ -0,0 +1,66 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PostProcessor is a step of the pipeline that rewrites assistant responses
+// before they are displayed. The conversation history keeps the original text.
+type PostProcessor struct {
+	Name        string
+	Description string
+	Default     bool // Enabled unless post_processors says otherwise
+}
+
+// PostProcessors lists the response post-processors in the order they run
+var PostProcessors = []PostProcessor{
+	{Name: "strip-thinking", Description: "Remove <think> reasoning blocks", Default: true},
+	{Name: "collapse-blank-lines", Description: "Collapse runs of blank lines outside code blocks", Default: true},
+	{Name: "link-files", Description: "Make mentions of project files clickable terminal links", Default: false},
+}
+
+// PostProcessorNames returns the names of the post-processors, in order
+func PostProcessorNames() []string {
+	names := make([]string, len(PostProcessors))
+	for i, processor := range PostProcessors {
+		names[i] = processor.Name
+	}
+	return names
+}
+
+// ValidatePostProcessor checks that name is a known post-processor
+func ValidatePostProcessor(name string) error {
+	for _, processor := range PostProcessors {
+		if processor.Name == name {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown post-processor '%s'. Valid post-processors are: %s", name, strings.Join(PostProcessorNames(), ", "))
+}
+
+// PostProcessorEnabled returns whether the post-processor called name runs
+func (m *Manager) PostProcessorEnabled(name string) bool {
+	if enabled, ok := m.Get().PostProcessors[name]; ok {
+		return enabled
+	}
+	for _, processor := range PostProcessors {
+		if processor.Name == name {
+			return processor.Default
+		}
+	}
+	return false
+}
@@ -0,0 +1,32 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import "testing"
+
+func TestManager_PostProcessorEnabled(t *testing.T) {
+	m := &Manager{mergedConfig: &Config{}}
+	if !m.PostProcessorEnabled("strip-thinking") || m.PostProcessorEnabled("link-files") {
+		t.Error("expected the default post-processor states")
+	}
+
+	m.mergedConfig.PostProcessors = map[string]bool{"strip-thinking": false, "link-files": true}
+	if m.PostProcessorEnabled("strip-thinking") || !m.PostProcessorEnabled("link-files") {
+		t.Error("expected the configured post-processor states")
+	}
+
+	if err := ValidatePostProcessor("translate"); err == nil {
+		t.Error("expected an unknown post-processor to be rejected")
+	}
+}
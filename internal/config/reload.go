@@ -0,0 +1,71 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import "fmt"
+
+// Paths returns the global and project config file paths
+func (m *Manager) Paths() []string {
+	return []string{m.globalPath, m.projectPath}
+}
+
+// Reload re-reads the config files and returns a summary of each setting
+// that changed. An invalid config is reported and the current one is kept.
+func (m *Manager) Reload() ([]string, error) {
+	next := &Manager{globalPath: m.globalPath, projectPath: m.projectPath, projectDir: m.projectDir}
+	if err := next.Load(); err != nil {
+		return nil, err
+	}
+
+	var before *Config
+	if m.mergedConfig != nil {
+		before = m.mergedConfig
+	} else {
+		before = &Config{}
+	}
+	changes := Changes(before, next.mergedConfig)
+
+	m.globalConfig = next.globalConfig
+	m.projectConfig = next.projectConfig
+	m.projectKeys = next.projectKeys
+	m.mergedConfig = next.mergedConfig
+	return changes, nil
+}
+
+// keyBindingFields are the key bindings, set with /keysetup rather than by key
+var keyBindingFields = []Field{
+	stringField("newline-key", "Key that inserts a newline", func(c *Config) *string { return &c.NewlineKey }, ValidateKeyBinding),
+	stringField("history-back-key", "Key for the previous history entry", func(c *Config) *string { return &c.HistoryBackKey }, ValidateKeyBinding),
+	stringField("history-forward-key", "Key for the next history entry", func(c *Config) *string { return &c.HistoryForwardKey }, ValidateKeyBinding),
+}
+
+// Changes describes the settings that differ between two configs, as
+// "key: old → new" with secrets masked
+func Changes(before, after *Config) []string {
+	var changes []string
+	for _, f := range append(Fields(), keyBindingFields...) {
+		if f.Get(before) == f.Get(after) {
+			continue
+		}
+		old, updated := f.Display(before), f.Display(after)
+		if old == "" {
+			old = "(default)"
+		}
+		if updated == "" {
+			updated = "(default)"
+		}
+		changes = append(changes, fmt.Sprintf("%s: %s → %s", f.Key, old, updated))
+	}
+	return changes
+}
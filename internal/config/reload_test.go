@@ -0,0 +1,67 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestManager_Reload(t *testing.T) {
+	t.Setenv("DEEPSEEK_API_KEY", "")
+	dir := t.TempDir()
+	m := &Manager{
+		globalPath:  filepath.Join(dir, "global.yaml"),
+		projectPath: filepath.Join(dir, "project.yaml"),
+	}
+	write := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(m.projectPath, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("model: deepseek-chat\ntemperature: 0.1\nmax_tokens: 2048\n")
+	if err := m.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	write("model: deepseek-reasoner\ntemperature: 0.1\nmax_tokens: 4096\nnewline_key: alt+enter\n")
+	changes, err := m.Reload()
+	if err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	want := []string{"model: deepseek-chat → deepseek-reasoner", "max-tokens: 2048 → 4096", "newline-key: (default) → alt+enter"}
+	if strings.Join(changes, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Reload() changes = %q, want %q", changes, want)
+	}
+	if m.GetModel() != "deepseek-reasoner" || m.GetNewlineKey() != "alt+enter" {
+		t.Errorf("config not applied: model %s, newline key %s", m.GetModel(), m.GetNewlineKey())
+	}
+
+	// An invalid edit keeps the previous settings
+	write("model: deepseek-chat\ntemperature: 9\nmax_tokens: 2048\n")
+	if _, err := m.Reload(); err == nil {
+		t.Fatal("Reload() expected an error for an invalid temperature")
+	}
+	if m.GetModel() != "deepseek-reasoner" {
+		t.Errorf("model = %s after a failed reload, want the previous deepseek-reasoner", m.GetModel())
+	}
+
+	if changes, err := m.Reload(); err == nil || changes != nil {
+		t.Errorf("Reload() = %v, %v", changes, err)
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// ProjectDir returns the directory the project config belongs to
+func (m *Manager) ProjectDir() string {
+	return m.projectDir
+}
+
+// Trusted reports whether the project directory is trusted. The project
+// config of an untrusted directory is ignored, and tools that run commands
+// must not be enabled there.
+func (m *Manager) Trusted() bool {
+	return !m.untrusted
+}
+
+// TrustedDirectories returns the trusted directories of the global config
+func (m *Manager) TrustedDirectories() []string {
+	if m.globalConfig == nil {
+		return nil
+	}
+	return m.globalConfig.TrustedDirectories
+}
+
+// Trust adds dir to the trusted directories of the global config and
+// reloads the configuration, reading the project config if dir covers it
+func (m *Manager) Trust(dir string) error {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	return m.updateGlobal(func(global *Config) {
+		if !slices.Contains(global.TrustedDirectories, dir) {
+			global.TrustedDirectories = append(global.TrustedDirectories, dir)
+		}
+	})
+}
+
+// Untrust removes dir from the trusted directories of the global config; it
+// reports whether dir was trusted
+func (m *Manager) Untrust(dir string) (bool, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return false, err
+	}
+	found := false
+	err = m.updateGlobal(func(global *Config) {
+		global.TrustedDirectories = slices.DeleteFunc(global.TrustedDirectories, func(trusted string) bool {
+			if filepath.Clean(trusted) == dir {
+				found = true
+				return true
+			}
+			return false
+		})
+	})
+	return found, err
+}
+
+// updateGlobal applies change to the global config file alone, so that no
+// project setting is copied into it, and reloads the configuration
+func (m *Manager) updateGlobal(change func(*Config)) error {
+	global := &Config{}
+	if err := m.loadConfigFile(m.globalPath, global); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	change(global)
+	if err := m.SaveGlobal(global); err != nil {
+		return err
+	}
+	return m.Load()
+}
+
+// isTrusted reports whether dir is one of the trusted directories of the
+// global config or inside one. An empty dir is not checked.
+func (m *Manager) isTrusted(dir string) bool {
+	if dir == "" {
+		return true
+	}
+	for _, trusted := range m.TrustedDirectories() {
+		if !filepath.IsAbs(trusted) {
+			continue
+		}
+		rel, err := filepath.Rel(filepath.Clean(trusted), dir)
+		if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,105 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestManager_Trust(t *testing.T) {
+	t.Setenv("DEEPSEEK_API_KEY", "")
+	dir := t.TempDir()
+	project := filepath.Join(dir, "work", "project")
+	if err := os.MkdirAll(filepath.Join(project, ".deecli"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	m := &Manager{
+		globalPath:  filepath.Join(dir, "global.yaml"),
+		projectPath: filepath.Join(project, ".deecli", "config.yaml"),
+		projectDir:  project,
+	}
+	os.WriteFile(m.globalPath, []byte("model: deepseek-chat\n"), 0600)
+	os.WriteFile(m.projectPath, []byte("model: deepseek-reasoner\ntrusted_directories: [/]\n"), 0600)
+
+	if err := m.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if m.Trusted() || m.GetModel() != "deepseek-chat" {
+		t.Fatalf("untrusted project config applied: trusted %v, model %s", m.Trusted(), m.GetModel())
+	}
+	if err := m.SaveProject(m.Get()); err == nil {
+		t.Error("SaveProject() expected an error in an untrusted directory")
+	}
+
+	// Trusting a parent directory covers the project
+	if err := m.Trust(filepath.Join(dir, "work")); err != nil {
+		t.Fatalf("Trust() error = %v", err)
+	}
+	if !m.Trusted() || m.GetModel() != "deepseek-reasoner" {
+		t.Errorf("trusted project config not applied: trusted %v, model %s", m.Trusted(), m.GetModel())
+	}
+	if got := m.TrustedDirectories(); len(got) != 1 || got[0] != filepath.Join(dir, "work") {
+		t.Errorf("TrustedDirectories() = %v", got)
+	}
+
+	// A sibling whose name shares the prefix is not covered
+	if m.isTrusted(filepath.Join(dir, "workshop")) {
+		t.Error("a sibling directory is trusted")
+	}
+
+	found, err := m.Untrust(filepath.Join(dir, "work"))
+	if err != nil || !found {
+		t.Fatalf("Untrust() = %v, %v", found, err)
+	}
+	if m.Trusted() {
+		t.Error("project still trusted after Untrust()")
+	}
+	if found, _ := m.Untrust(filepath.Join(dir, "work")); found {
+		t.Error("Untrust() found a directory that was not trusted")
+	}
+}
+
+func TestManager_UntrustedKeepsGlobalSettings(t *testing.T) {
+	t.Setenv("DEEPSEEK_API_KEY", "")
+	dir := t.TempDir()
+	m := &Manager{
+		globalPath:  filepath.Join(dir, "global.yaml"),
+		projectPath: filepath.Join(dir, "project", ".deecli", "config.yaml"),
+		projectDir:  filepath.Join(dir, "project"),
+	}
+	if err := os.MkdirAll(filepath.Dir(m.projectPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(m.globalPath, []byte("model: deepseek-chat\nredact_secrets: true\nscreen_reader: true\nwarm_up_on_start: true\n"), 0600)
+	os.WriteFile(m.projectPath, []byte("model: deepseek-reasoner\nredact_secrets: false\nscreen_reader: false\n"), 0600)
+
+	if err := m.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if m.Trusted() {
+		t.Fatal("project directory trusted without being listed")
+	}
+	if m.projectConfig != nil {
+		t.Errorf("untrusted project config loaded: %+v", m.projectConfig)
+	}
+	if !m.GetRedactSecrets() || !m.GetScreenReader() || !m.GetWarmUpOnStart() {
+		t.Errorf("global settings lost in an untrusted directory: redact %v, screen reader %v, warm-up %v",
+			m.GetRedactSecrets(), m.GetScreenReader(), m.GetWarmUpOnStart())
+	}
+	if m.GetModel() != "deepseek-chat" {
+		t.Errorf("GetModel() = %s, want the global deepseek-chat", m.GetModel())
+	}
+}
@@ -0,0 +1,201 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package doclint checks Go packages for the doc comment conventions golint
+// enforced: a package comment starting with "Package name", and a comment
+// on every exported identifier starting with its name.
+package doclint
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Problem is a doc comment convention a package breaks
+type Problem struct {
+	File    string // Path of the file, joined to the directory given to Lint
+	Line    int
+	Message string
+}
+
+// String formats the problem as "file:line: message"
+func (p Problem) String() string {
+	return fmt.Sprintf("%s:%d: %s", p.File, p.Line, p.Message)
+}
+
+// Lint returns the doc comment problems of the Go package in dir, test files
+// excluded, in file and line order. A directory without Go files has none.
+func Lint(dir string) ([]Problem, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+	var parsed []*ast.File
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		parsed = append(parsed, file)
+	}
+	if len(parsed) == 0 {
+		return nil, nil
+	}
+
+	l := &linter{fset: fset}
+	l.packageComment(parsed)
+	for _, file := range parsed {
+		for _, decl := range file.Decls {
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				l.funcDecl(decl)
+			case *ast.GenDecl:
+				l.genDecl(decl)
+			}
+		}
+	}
+
+	sort.SliceStable(l.problems, func(i, j int) bool {
+		if l.problems[i].File != l.problems[j].File {
+			return l.problems[i].File < l.problems[j].File
+		}
+		return l.problems[i].Line < l.problems[j].Line
+	})
+	return l.problems, nil
+}
+
+// linter collects the problems of one package
+type linter struct {
+	fset     *token.FileSet
+	problems []Problem
+}
+
+func (l *linter) report(pos token.Pos, format string, args ...any) {
+	position := l.fset.Position(pos)
+	l.problems = append(l.problems, Problem{File: position.Filename, Line: position.Line, Message: fmt.Sprintf(format, args...)})
+}
+
+// packageComment checks that one file of the package has a package comment
+// of the form "Package name ...". Commands may describe themselves freely.
+func (l *linter) packageComment(parsed []*ast.File) {
+	for _, file := range parsed {
+		if file.Doc == nil {
+			continue
+		}
+		name := file.Name.Name
+		if name != "main" && !strings.HasPrefix(file.Doc.Text(), "Package "+name+" ") {
+			l.report(file.Doc.Pos(), `package comment should be of the form "Package %s ..."`, name)
+		}
+		return
+	}
+	l.report(parsed[0].Package, "package %s should have a package comment", parsed[0].Name.Name)
+}
+
+func (l *linter) funcDecl(decl *ast.FuncDecl) {
+	if !decl.Name.IsExported() {
+		return
+	}
+	kind := "function"
+	if decl.Recv != nil && len(decl.Recv.List) > 0 {
+		if !ast.IsExported(receiverType(decl.Recv.List[0].Type)) {
+			return
+		}
+		kind = "method"
+	}
+	l.comment(decl.Doc, decl.Pos(), kind, decl.Name.Name, false)
+}
+
+func (l *linter) genDecl(decl *ast.GenDecl) {
+	// A comment on a group of constants or variables documents all of them
+	grouped := decl.Lparen.IsValid()
+	if grouped && decl.Doc != nil && decl.Tok != token.TYPE {
+		return
+	}
+
+	for _, spec := range decl.Specs {
+		switch spec := spec.(type) {
+		case *ast.TypeSpec:
+			doc := spec.Doc
+			if doc == nil && !grouped {
+				doc = decl.Doc
+			}
+			if spec.Name.IsExported() {
+				l.comment(doc, spec.Pos(), "type", spec.Name.Name, true)
+			}
+		case *ast.ValueSpec:
+			doc := spec.Doc
+			if doc == nil && !grouped {
+				doc = decl.Doc
+			}
+			kind := "var"
+			if decl.Tok == token.CONST {
+				kind = "const"
+			}
+			for _, name := range spec.Names {
+				if !name.IsExported() {
+					continue
+				}
+				// Only a comment on a single name has to start with it
+				l.comment(doc, name.Pos(), kind, name.Name, false)
+				if len(spec.Names) > 1 {
+					break
+				}
+			}
+		}
+	}
+}
+
+// comment checks the doc comment of an exported identifier. Types may also
+// start it with an article, as in "A Client talks to ...".
+func (l *linter) comment(doc *ast.CommentGroup, pos token.Pos, kind, name string, articles bool) {
+	if doc == nil {
+		l.report(pos, "exported %s %s should have a comment", kind, name)
+		return
+	}
+	text := doc.Text()
+	if articles {
+		for _, article := range []string{"A ", "An ", "The "} {
+			text = strings.TrimPrefix(text, article)
+		}
+	}
+	if !strings.HasPrefix(text, name+" ") && !strings.HasPrefix(text, name+"\n") {
+		l.report(doc.Pos(), `comment on exported %s %s should be of the form "%s ..."`, kind, name, name)
+	}
+}
+
+// receiverType returns the name of the type of a method receiver
+func receiverType(expr ast.Expr) string {
+	switch expr := expr.(type) {
+	case *ast.StarExpr:
+		return receiverType(expr.X)
+	case *ast.IndexExpr:
+		return receiverType(expr.X)
+	case *ast.IndexListExpr:
+		return receiverType(expr.X)
+	case *ast.Ident:
+		return expr.Name
+	}
+	return ""
+}
@@ -0,0 +1,118 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doclint
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestLint(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"store.go": `package store
+
+// Store keeps values
+type Store struct{}
+
+// A Cache keeps recent values
+type Cache struct{}
+
+type Index struct{}
+
+// Get returns a value
+func (s *Store) Get() {}
+
+// fetches a value
+func (s *Store) Fetch() {}
+
+func (s *Store) Put() {}
+
+func (i index) Len() int { return 0 }
+
+type index struct{}
+
+// Limits of a store
+const (
+	MaxKeys = 10
+	MaxSize = 20
+)
+
+var Default = Store{}
+
+func helper() {}
+`,
+		"store_test.go": `package store
+
+func TestStore() {}
+`,
+	})
+
+	problems, err := Lint(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, problem := range problems {
+		got = append(got, filepath.Base(problem.File)+": "+problem.Message)
+	}
+	want := []string{
+		"store.go: package store should have a package comment",
+		"store.go: exported type Index should have a comment",
+		`store.go: comment on exported method Fetch should be of the form "Fetch ..."`,
+		"store.go: exported method Put should have a comment",
+		"store.go: exported var Default should have a comment",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Lint() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestLint_PackageComment(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		problem string
+	}{
+		{"documented", map[string]string{"a.go": "// Package a does things\npackage a\n", "b.go": "package a\n"}, ""},
+		{"wrong form", map[string]string{"a.go": "// Does things\npackage a\n"}, `package comment should be of the form "Package a ..."`},
+		{"command", map[string]string{"main.go": "// Deecli is a tool\npackage main\n"}, ""},
+		{"no go files", map[string]string{"README.md": "# a\n"}, ""},
+	}
+	for _, tt := range tests {
+		problems, err := Lint(writeFiles(t, tt.files))
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		got := ""
+		if len(problems) > 0 {
+			got = problems[0].Message
+		}
+		if got != tt.problem {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.problem)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package editor
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/antenore/deecli/internal/config"
+)
+
+// Command templates use these placeholders:
+//
+//	{editor}        the editor found in $EDITOR, $VISUAL or on PATH
+//	{target}/{file} the file to edit
+//	{line}          the line to open it at
+//	{instructions}  the instruction file
+//
+// An argument whose only placeholders are {line} or {instructions} is left
+// out when there is no line or no instruction file; elsewhere a missing
+// line counts as 1.
+
+// builtinCommands are the command templates of the editors DeeCLI knows,
+// by editorBaseName; vim variants are looked up as "vim"
+var builtinCommands = map[string]string{
+	"vim":       "{editor} -O +{line} {target} {instructions}", // Vertical split, target on the left
+	"code":      "{editor} --goto {target}:{line} {instructions}",
+	"emacs":     "{editor} +{line} {target} {instructions}",
+	"nano":      "{editor} +{line} {target} {instructions}",
+	"subl":      "{editor} {target}:{line} {instructions}",
+	"zed":       "{editor} {target}:{line} {instructions}",
+	"notepad":   "{editor} {target}", // One file, no line argument
+	"notepad++": "{editor} -n{line} {target} {instructions}",
+}
+
+// defaultCommand is the template of editors builtinCommands does not know;
+// most terminal editors accept +line
+const defaultCommand = "{editor} +{line} {target} {instructions}"
+
+// builtinCommand returns the template for the editor with the given base name
+func builtinCommand(editorBase string) string {
+	if strings.Contains(editorBase, "vim") {
+		return builtinCommands["vim"]
+	}
+	if template, ok := builtinCommands[editorBase]; ok {
+		return template
+	}
+	return defaultCommand
+}
+
+// commandTemplate returns the configured template for opening a file with
+// an instruction file or without one, preferring the one meant for the case
+// and falling back to the other, or "" if neither is configured
+func commandTemplate(cfg Config, withInstructions bool) string {
+	preferred, other := cfg.GotoCommand, cfg.Command
+	if withInstructions {
+		preferred, other = other, preferred
+	}
+	if preferred != "" {
+		return preferred
+	}
+	return other
+}
+
+// buildCommand returns the command opening file at line, with instructions
+// beside it unless it is "", and the editor's base name. It uses the
+// configured template, or the built-in one of the editor found; it returns
+// nil when no editor is found or the template is invalid.
+func buildCommand(cfg Config, file string, line int, instructions string) (*exec.Cmd, string) {
+	template := commandTemplate(cfg, instructions != "")
+	editor := ""
+	if template == "" || strings.Contains(template, "{editor}") {
+		if editor = findEditor(cfg.MessageLogger); editor == "" {
+			return nil, ""
+		}
+	}
+	if template == "" {
+		template = builtinCommand(editorBaseName(editor))
+	}
+
+	args, err := expandCommand(template, editor, file, line, instructions)
+	if err != nil {
+		cfg.MessageLogger("system", fmt.Sprintf("❌ Invalid editor command: %v", err))
+		return nil, ""
+	}
+	return exec.Command(args[0], args[1:]...), editorBaseName(args[0])
+}
+
+// expandCommand splits template into arguments and fills in its placeholders
+func expandCommand(template, editor, file string, line int, instructions string) ([]string, error) {
+	if err := config.ValidateEditorCommand(template); err != nil {
+		return nil, err
+	}
+	fields, _ := config.SplitCommand(template)
+
+	lineText := strconv.Itoa(max(line, 1))
+	replacer := strings.NewReplacer("{editor}", editor, "{target}", file, "{file}", file, "{line}", lineText, "{instructions}", instructions)
+	var args []string
+	for _, field := range fields {
+		if optional(field, "{line}", line > 0) || optional(field, "{instructions}", instructions != "") {
+			continue
+		}
+		args = append(args, replacer.Replace(field))
+	}
+	if len(args) == 0 || args[0] == "" {
+		return nil, fmt.Errorf("%q names no program", template)
+	}
+	return args, nil
+}
+
+// optional reports whether field should be left out because it uses
+// placeholder, which has no value, and no placeholder other than {line} or
+// {instructions}
+func optional(field, placeholder string, set bool) bool {
+	if set || !strings.Contains(field, placeholder) {
+		return false
+	}
+	rest := strings.NewReplacer("{line}", "", "{instructions}", "").Replace(field)
+	return !strings.Contains(rest, "{")
+}
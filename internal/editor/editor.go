@@ -17,31 +17,46 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"slices"
 	"strings"
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/antenore/deecli/internal/utils"
+	"github.com/antenore/deecli/internal/scaffold"
 )
 
 // EditorFinishedMsg represents an editor closing event
 type EditorFinishedMsg struct {
-	Error error
+	Error   error
+	Context *EditContext // Where the user was in the file, when Config.ReturnContext is set and the editor reported it
 }
 
 // Config holds configuration for editor operations
 type Config struct {
-	// MessageProvider provides recent chat messages for instruction files
-	MessageProvider func() []string
+	// Instructions returns what the instruction file for a file is built
+	// from; nil, or a nil result, opens the file without one
+	Instructions func(path string) *Instructions
 	// MessageLogger logs messages to the chat interface
 	MessageLogger func(role, content string)
+	// Scaffolder creates files that do not exist yet; nil uses the built-in templates
+	Scaffolder *scaffold.Scaffolder
+	// ReturnContext asks the editor where the cursor was when it closes, see MarkerEnv
+	ReturnContext bool
+	// Command is the template opening a file with its instruction file and
+	// GotoCommand the one opening a file at a line, see buildCommand; "" uses
+	// the other, or the built-in template of the editor found
+	Command     string
+	GotoCommand string
 }
 
 // OpenFileWithInstructions opens a file in the editor with AI-generated instruction file
-func OpenFileWithInstructions(filepath string, config Config) tea.Cmd {
+func OpenFileWithInstructions(path string, config Config) tea.Cmd {
 	// Parse file:line format first
-	file, line := ParseFileAndLine(filepath)
+	file, line := ParseFileAndLine(path)
 
 	// Create instruction file with context from last messages
-	instructionFile := createInstructionFile(file, config.MessageProvider)
+	instructionFile := createInstructionFile(file, config.Instructions)
 
 	// Auto-create directories if they don't exist
 	if err := ensureDirectoryExists(file, config.MessageLogger); err != nil {
@@ -51,277 +66,131 @@ func OpenFileWithInstructions(filepath string, config Config) tea.Cmd {
 
 	// Check if this is a new file creation
 	if _, err := os.Stat(file); os.IsNotExist(err) {
-		// Create the new file with basic template
-		createNewFileWithTemplate(file)
+		// Create the new file from its template
+		if err := createNewFileWithTemplate(file, config.Scaffolder); err != nil {
+			config.MessageLogger("system", fmt.Sprintf("❌ Failed to create file: %v", err))
+			os.Remove(instructionFile)
+			return nil
+		}
 	}
 	
-	// Find editor with interactive fallback
-	editor := findEditor(config.MessageLogger)
-	if editor == "" {
+	// Build the command from the configured or built-in template
+	c, editorBase := buildCommand(config, file, line, instructionFile)
+	if c == nil {
+		if instructionFile != "" {
+			os.Remove(instructionFile)
+		}
 		return nil
 	}
-	
-	// Build command - simple approach for any editor
-	var c *exec.Cmd
-	// Get editor base name for switching logic
-	editorParts := strings.Split(editor, "/")
-	editorBase := editorParts[len(editorParts)-1]
-	
-	// Handle different editors with two-file opening
+
 	switch {
-	case strings.Contains(editorBase, "vim") || editorBase == "nvim":
-		// Vim/NVim: vertical split with target file on left, suggestions on right
-		if line > 0 {
-			c = exec.Command(editor, "-O", fmt.Sprintf("+%d", line), file, instructionFile)
-		} else {
-			c = exec.Command(editor, "-O", file, instructionFile)
-		}
-	case editorBase == "code":
-		// VSCode: open target file first, then suggestions
-		if line > 0 {
-			c = exec.Command(editor, "--goto", fmt.Sprintf("%s:%d", file, line), instructionFile)
-		} else {
-			c = exec.Command(editor, file, instructionFile)
-		}
-	case editorBase == "emacs":
-		// Emacs: open target file first, then suggestions
-		if line > 0 {
-			c = exec.Command(editor, fmt.Sprintf("+%d", line), file, instructionFile)
-		} else {
-			c = exec.Command(editor, file, instructionFile)
-		}
+	case !slices.Contains(c.Args, instructionFile):
+		// Instruction files are off, or the editor opens a single file
+		config.MessageLogger("system", fmt.Sprintf("📝 Opening %s in %s", file, editorBase))
+	case line > 0:
+		config.MessageLogger("system", fmt.Sprintf("📝 Opening %s at line %d with instructions in %s", file, line, editorBase))
 	default:
-		// Other editors: target file first, suggestions second
-		if line > 0 {
-			c = exec.Command(editor, fmt.Sprintf("+%d", line), file, instructionFile)
-		} else {
-			c = exec.Command(editor, file, instructionFile)
-		}
-	}
-
-	if line > 0 {
-		config.MessageLogger("system", fmt.Sprintf("📝 Opening %s at line %d with instructions in %s", file, line, editor))
-	} else {
-		config.MessageLogger("system", fmt.Sprintf("📝 Opening %s with instructions in %s", file, editor))
+		config.MessageLogger("system", fmt.Sprintf("📝 Opening %s with instructions in %s", file, editorBase))
 	}
 	
-	return tea.ExecProcess(c, func(err error) tea.Msg {
+	logGUIWait(file, editorBase, config.MessageLogger)
+	return launch(c, file, editorBase, config.ReturnContext, func() {
 		// Clean up instruction file
 		if instructionFile != "" {
 			os.Remove(instructionFile)
 		}
-		if err != nil {
-			return EditorFinishedMsg{Error: err}
-		}
-		return EditorFinishedMsg{}
 	})
 }
 
 // CreateAndEditNewFile creates a new file with template and opens it for editing
-func CreateAndEditNewFile(filepath string, config Config) tea.Cmd {
+func CreateAndEditNewFile(path string, config Config) tea.Cmd {
 	// Always create instruction file for new files
-	instructionFile := createInstructionFile(filepath, config.MessageProvider)
+	instructionFile := createInstructionFile(path, config.Instructions)
 	
 	// Create the new file with template
-	if err := createNewFileWithTemplate(filepath); err != nil {
+	if err := createNewFileWithTemplate(path, config.Scaffolder); err != nil {
 		config.MessageLogger("system", fmt.Sprintf("❌ Failed to create file: %v", err))
 		return nil
 	}
 	
-	config.MessageLogger("system", fmt.Sprintf("✓ Creating new file: %s", filepath))
-	
-	// Find editor
-	editor := os.Getenv("EDITOR")
-	if editor == "" {
-		editor = os.Getenv("VISUAL")
-	}
-	if editor == "" {
-		// Try to find a common editor
-		for _, e := range []string{"nvim", "vim", "vi", "nano", "emacs"} {
-			if _, err := exec.LookPath(e); err == nil {
-				editor = e
-				break
-			}
-		}
-	}
-	if editor == "" {
-		config.MessageLogger("system", "❌ No editor found. Please set $EDITOR environment variable")
-		return nil
-	}
+	config.MessageLogger("system", fmt.Sprintf("✓ Creating new file: %s", path))
 	
-	// Build command based on editor
-	var c *exec.Cmd
-	if strings.Contains(editor, "vim") || editor == "nvim" {
-		// Use vertical split for vim/nvim
+	// Build the command from the configured or built-in template
+	c, editorBase := buildCommand(config, path, 0, instructionFile)
+	if c == nil {
 		if instructionFile != "" {
-			c = exec.Command(editor, "-O", instructionFile, filepath)
-		} else {
-			c = exec.Command(editor, filepath)
+			os.Remove(instructionFile)
 		}
-	} else {
-		// For other editors, just open the file
-		c = exec.Command(editor, filepath)
+		return nil
 	}
 	
-	return tea.ExecProcess(c, func(err error) tea.Msg {
+	logGUIWait(path, editorBase, config.MessageLogger)
+	return launch(c, path, editorBase, config.ReturnContext, func() {
 		// Clean up instruction file
 		if instructionFile != "" {
 			os.Remove(instructionFile)
 		}
-		if err != nil {
-			return EditorFinishedMsg{Error: err}
-		}
-		return EditorFinishedMsg{}
 	})
 }
 
 // OpenFile opens a file directly without instruction files (simple version)
-func OpenFile(filepath string, config Config) tea.Cmd {
+func OpenFile(path string, config Config) tea.Cmd {
 	// Parse file:line format
-	file, line := ParseFileAndLine(filepath)
+	file, line := ParseFileAndLine(path)
 	
-	// Find editor
-	editor := findEditor(config.MessageLogger)
-	if editor == "" {
+	// Build the command from the configured or built-in template
+	c, editorBase := buildCommand(config, file, line, "")
+	if c == nil {
 		return nil
 	}
 	
-	var c *exec.Cmd
-	editorParts := strings.Split(editor, "/")
-	editorBase := editorParts[len(editorParts)-1]
+	config.MessageLogger("system", fmt.Sprintf("📝 Opening %s in %s", file, editorBase))
 	
-	// Handle line number navigation
-	switch {
-	case strings.Contains(editorBase, "vim") || editorBase == "nvim":
-		if line > 0 {
-			c = exec.Command(editor, fmt.Sprintf("+%d", line), file)
-		} else {
-			c = exec.Command(editor, file)
-		}
-	case editorBase == "code":
-		if line > 0 {
-			c = exec.Command(editor, "--goto", fmt.Sprintf("%s:%d", file, line))
-		} else {
-			c = exec.Command(editor, file)
-		}
-	case editorBase == "emacs" || editorBase == "nano":
-		if line > 0 {
-			c = exec.Command(editor, fmt.Sprintf("+%d", line), file)
-		} else {
-			c = exec.Command(editor, file)
-		}
-	default:
-		// Generic fallback
-		c = exec.Command(editor, file)
+	logGUIWait(file, editorBase, config.MessageLogger)
+	return launch(c, file, editorBase, config.ReturnContext, func() {})
+}
+
+// logGUIWait tells the user that the chat waits for file to be saved when
+// the editor opens its own window
+func logGUIWait(file, editorBase string, messageLogger func(role, content string)) {
+	if IsGUIEditor(editorBase) {
+		messageLogger("system", fmt.Sprintf("⏳ %s opens in its own window; the chat continues when %s is saved", editorBase, file))
 	}
-	
-	config.MessageLogger("system", fmt.Sprintf("📝 Opening %s in %s", file, editor))
-	
-	return tea.ExecProcess(c, func(err error) tea.Msg {
-		if err != nil {
-			return EditorFinishedMsg{Error: err}
-		}
-		return EditorFinishedMsg{}
-	})
 }
 
-// createInstructionFile creates a temporary markdown file with AI suggestions and editing tips
-func createInstructionFile(filepath string, messageProvider func() []string) string {
-	if messageProvider == nil {
+// createInstructionFile writes the instruction file for path to a temporary
+// markdown file and returns its name, or "" if there is none
+func createInstructionFile(path string, instructions func(string) *Instructions) string {
+	if instructions == nil {
 		return ""
 	}
-	
-	// Create temp file for instructions
+	ins := instructions(path)
+	if ins == nil {
+		return ""
+	}
+
 	tmpfile, err := os.CreateTemp("", "deecli_instructions_*.md")
 	if err != nil {
 		return ""
 	}
 	defer tmpfile.Close()
-	
-	instructions := ""
-	instructions += fmt.Sprintf("# DeeCLI Edit Instructions for %s\n\n", filepath)
-	
-	// Add helpful editor shortcuts
-	instructions += "## Quick Editor Tips:\n"
-	instructions += "- **Vim/NVim**: Switch panes with `Ctrl+W W`, copy with `yip`, save+exit with `:wq`\n"
-	instructions += "- **VSCode**: Use split view, copy suggestions, then edit\n\n"
-	
-	instructions += "## AI Suggestions:\n\n"
-	
-	// Get last few relevant messages (clean of ANSI codes)
-	messages := messageProvider()
-	if len(messages) > 0 {
-		// Get last AI response (usually has the suggestions)
-		for i := len(messages) - 1; i >= 0 && i >= len(messages)-5; i-- {
-			msg := messages[i]
-			if strings.Contains(msg, "DeeCLI:") || strings.Contains(msg, "assistant:") {
-				// Strip ANSI codes and clean up the content
-				cleanMsg := utils.StripANSI(msg)
-
-				// Also remove box-drawing characters (common Unicode box chars)
-				boxChars := []string{"┌", "─", "┐", "│", "└", "┘", "├", "┤", "┬", "┴", "┼"}
-				for _, char := range boxChars {
-					cleanMsg = strings.ReplaceAll(cleanMsg, char, "")
-				}
-
-				// Clean up resulting multiple spaces and empty lines
-				lines := strings.Split(cleanMsg, "\n")
-				var cleanLines []string
-				for _, line := range lines {
-					// Trim spaces and check if line has content
-					trimmed := strings.TrimSpace(line)
-					if trimmed != "" {
-						cleanLines = append(cleanLines, trimmed)
-					}
-				}
-				cleanMsg = strings.Join(cleanLines, "\n")
-
-				instructions += "```\n"
-				instructions += cleanMsg + "\n"
-				instructions += "```\n\n"
-				break
-			}
-		}
-	}
-	
-	instructions += "## Next Steps:\n"
-	instructions += "1. Review the suggestions above\n"
-	instructions += "2. Copy relevant code/instructions\n"
-	instructions += "3. Switch to the other pane and make changes\n"
-	instructions += "4. Save and exit to return to chat\n\n"
-	instructions += "---\n"
-	instructions += "*This instruction file will be automatically deleted when you close the editor.*\n"
-	
-	tmpfile.WriteString(instructions)
+	tmpfile.WriteString(ins.Render(path))
 	return tmpfile.Name()
 }
 
-// createNewFileWithTemplate creates a new file with appropriate template based on file extension
-func createNewFileWithTemplate(filepath string) error {
-	// Determine file type and create appropriate template
-	var content string
-	
-	if strings.HasSuffix(filepath, ".go") {
-		content = "package main\n\n// TODO: Implement based on AI suggestions\n"
-	} else if strings.HasSuffix(filepath, ".py") {
-		content = "#!/usr/bin/env python3\n\n# TODO: Implement based on AI suggestions\n"
-	} else if strings.HasSuffix(filepath, ".js") {
-		content = "// TODO: Implement based on AI suggestions\n"
-	} else if strings.HasSuffix(filepath, ".sh") {
-		content = "#!/bin/bash\n\n# TODO: Implement based on AI suggestions\n"
-	} else {
-		content = "# New file created by DeeCLI\n# TODO: Implement based on AI suggestions\n"
-	}
-	
-	// Create directory if needed
-	if idx := strings.LastIndex(filepath, "/"); idx > 0 {
-		dir := filepath[:idx]
-		if err := os.MkdirAll(dir, 0755); err != nil {
-			return fmt.Errorf("failed to create directory: %w", err)
-		}
+// createNewFileWithTemplate creates a new file from the template that
+// matches its path, or from the built-in one for its extension
+func createNewFileWithTemplate(path string, scaffolder *scaffold.Scaffolder) error {
+	if scaffolder == nil {
+		scaffolder = &scaffold.Scaffolder{}
 	}
-	
-	return os.WriteFile(filepath, []byte(content), 0644)
+	return scaffolder.Create(path)
+}
+
+// Find returns the editor that would be used, from $EDITOR, $VISUAL or PATH,
+// or "" if none is found
+func Find() string {
+	return findEditor(func(role, content string) {})
 }
 
 // findEditor attempts to find an available editor with interactive fallback
@@ -342,8 +211,7 @@ func findEditor(messageLogger func(role, content string)) string {
 	}
 	
 	// Try to find a common editor
-	commonEditors := []string{"nvim", "vim", "vi", "nano", "emacs", "code"}
-	for _, e := range commonEditors {
+	for _, e := range defaultEditors() {
 		if _, err := exec.LookPath(e); err == nil {
 			messageLogger("system", fmt.Sprintf("📝 Using editor: %s", e))
 			return e
@@ -352,22 +220,49 @@ func findEditor(messageLogger func(role, content string)) string {
 	
 	// No editor found - provide helpful message
 	messageLogger("system", "❌ No editor found. Please:")
-	messageLogger("system", "   1. Install an editor: sudo apt install vim (or nvim, nano, etc.)")
-	messageLogger("system", "   2. Set EDITOR environment variable: export EDITOR=vim")
+	if runtime.GOOS == "windows" {
+		messageLogger("system", "   1. Install an editor: winget install Microsoft.VisualStudioCode (or Neovim, Notepad++, etc.)")
+		messageLogger("system", "   2. Set EDITOR environment variable: setx EDITOR code.cmd")
+	} else {
+		messageLogger("system", "   1. Install an editor: sudo apt install vim (or nvim, nano, etc.)")
+		messageLogger("system", "   2. Set EDITOR environment variable: export EDITOR=vim")
+	}
 	messageLogger("system", "   3. Or use /config editor <editor_name> (future feature)")
 	return ""
 }
 
+// defaultEditors returns the editors to look for when $EDITOR and $VISUAL are unset
+func defaultEditors() []string {
+	if runtime.GOOS == "windows" {
+		// code.cmd is the launcher VS Code puts on PATH; notepad is always present
+		return []string{"nvim", "vim", "code.cmd", "code", "notepad++", "notepad"}
+	}
+	return []string{"nvim", "vim", "vi", "nano", "emacs", "code"}
+}
+
+// editorBaseName returns the editor's program name without directory or
+// Windows executable extension, e.g. "C:\Tools\Code.CMD" becomes "code"
+func editorBaseName(editor string) string {
+	base := filepath.Base(editor)
+	switch ext := strings.ToLower(filepath.Ext(base)); ext {
+	case ".exe", ".cmd", ".bat":
+		base = strings.TrimSuffix(base, filepath.Ext(base))
+	}
+	if runtime.GOOS == "windows" {
+		base = strings.ToLower(base)
+	}
+	return base
+}
+
 // ensureDirectoryExists creates parent directories if they don't exist
-func ensureDirectoryExists(filepath string, messageLogger func(role, content string)) error {
-	lastSlashIndex := strings.LastIndex(filepath, "/")
-	if lastSlashIndex == -1 {
+func ensureDirectoryExists(path string, messageLogger func(role, content string)) error {
+	dir := filepath.Dir(path)
+	if dir == "." {
 		// No directory component, file is in current directory
 		return nil
 	}
 	
-	dir := filepath[:lastSlashIndex]
-	if dir != "" && dir != filepath {
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
 		if err := os.MkdirAll(dir, 0755); err != nil {
 			return fmt.Errorf("failed to create directory %s: %w", dir, err)
 		}
@@ -376,6 +271,28 @@ func ensureDirectoryExists(filepath string, messageLogger func(role, content str
 	return nil
 }
 
+// FindSymbol returns the line where symbol is defined in the file at path,
+// falling back to its first mention, or 0 if it does not appear
+func FindSymbol(path, symbol string) int {
+	data, err := os.ReadFile(path)
+	if err != nil || symbol == "" {
+		return 0
+	}
+	definition := regexp.MustCompile(`\b(func|def|class|function|fn|type|struct|interface|const|var|let)\s+(\([^)]*\)\s*)?` + regexp.QuoteMeta(symbol) + `\b`)
+	mention := regexp.MustCompile(`\b` + regexp.QuoteMeta(symbol) + `\b`)
+
+	firstMention := 0
+	for i, line := range strings.Split(string(data), "\n") {
+		if definition.MatchString(line) {
+			return i + 1
+		}
+		if firstMention == 0 && mention.MatchString(line) {
+			firstMention = i + 1
+		}
+	}
+	return firstMention
+}
+
 // ParseFileAndLine parses "file:line" format and returns file path and line number
 func ParseFileAndLine(input string) (string, int) {
 	lastColon := strings.LastIndex(input, ":")
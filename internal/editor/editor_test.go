@@ -15,7 +15,14 @@ package editor
 
 import (
 	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/antenore/deecli/internal/api"
 )
 
 func TestParseFileAndLine(t *testing.T) {
@@ -69,6 +76,18 @@ func TestParseFileAndLine(t *testing.T) {
 			expectedFile: "TODO.md",
 			expectedLine: 45,
 		},
+		{
+			name:         "windows drive path with line number",
+			input:        `C:\src\main.go:12`,
+			expectedFile: `C:\src\main.go`,
+			expectedLine: 12,
+		},
+		{
+			name:         "windows drive path without line number",
+			input:        `C:\src\main.go`,
+			expectedFile: `C:\src\main.go`,
+			expectedLine: 0,
+		},
 	}
 
 	for _, tt := range tests {
@@ -82,4 +101,320 @@ func TestParseFileAndLine(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+
+func TestEditorBaseName(t *testing.T) {
+	tests := []struct {
+		editor string
+		want   string
+	}{
+		{editor: "nvim", want: "nvim"},
+		{editor: filepath.Join("usr", "bin", "vim"), want: "vim"},
+		{editor: "code.cmd", want: "code"},
+		{editor: filepath.Join("Tools", "notepad.exe"), want: "notepad"},
+		{editor: "edit.bat", want: "edit"},
+		{editor: "notepad++", want: "notepad++"},
+	}
+
+	for _, tt := range tests {
+		if got := editorBaseName(tt.editor); got != tt.want {
+			t.Errorf("editorBaseName(%q) = %q, want %q", tt.editor, got, tt.want)
+		}
+	}
+}
+
+func TestEnsureDirectoryExists(t *testing.T) {
+	dir := t.TempDir()
+	var logged []string
+	logger := func(role, content string) { logged = append(logged, content) }
+
+	target := filepath.Join(dir, "a", "b", "file.go")
+	if err := ensureDirectoryExists(target, logger); err != nil {
+		t.Fatalf("ensureDirectoryExists failed: %v", err)
+	}
+	if info, err := os.Stat(filepath.Dir(target)); err != nil || !info.IsDir() {
+		t.Fatalf("expected directory %s to be created", filepath.Dir(target))
+	}
+	if len(logged) != 1 {
+		t.Errorf("expected one creation message, got %v", logged)
+	}
+
+	// Existing directories are not reported again
+	if err := ensureDirectoryExists(target, logger); err != nil {
+		t.Fatalf("ensureDirectoryExists failed: %v", err)
+	}
+	if len(logged) != 1 {
+		t.Errorf("expected no message for an existing directory, got %v", logged)
+	}
+}
+
+func TestFindSymbol(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "main.go")
+	src := "package main\n\n// Run calls helper\nfunc main() {\n\thelper()\n}\n\nfunc (s *server) helper() {}\n"
+	if err := os.WriteFile(path, []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		symbol string
+		want   int
+	}{
+		{"main", 4},    // definition
+		{"helper", 8},  // method definition wins over earlier mentions
+		{"Run", 3},     // no definition, falls back to first mention
+		{"missing", 0}, // not found
+		{"", 0},
+	}
+	for _, tt := range tests {
+		if got := FindSymbol(path, tt.symbol); got != tt.want {
+			t.Errorf("FindSymbol(%q) = %d, want %d", tt.symbol, got, tt.want)
+		}
+	}
+
+	if got := FindSymbol(filepath.Join(t.TempDir(), "nope.go"), "main"); got != 0 {
+		t.Errorf("expected 0 for a missing file, got %d", got)
+	}
+}
+
+func TestParseMarker(t *testing.T) {
+	tests := []struct {
+		content string
+		want    *EditContext
+	}{
+		{"42\n", &EditContext{Path: "main.go", Line: 42}},
+		{"42 40-48", &EditContext{Path: "main.go", Line: 42, Start: 40, End: 48}},
+		{"42 48-40", &EditContext{Path: "main.go", Line: 42}}, // Reversed region is ignored
+		{"", nil},
+		{"0", nil},
+		{"abc", nil},
+	}
+	for _, tt := range tests {
+		got := ParseMarker("main.go", tt.content)
+		if (got == nil) != (tt.want == nil) || got != nil && *got != *tt.want {
+			t.Errorf("ParseMarker(%q) = %+v, want %+v", tt.content, got, tt.want)
+		}
+	}
+}
+
+func TestEditContext_Snippet(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "main.go")
+	var lines []string
+	for i := 1; i <= 20; i++ {
+		lines = append(lines, "line "+string(rune('a'+i-1)))
+	}
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got := (&EditContext{Path: path, Line: 2}).Snippet()
+	want := "I was just editing " + path + " around line 2:\n\n```go\nline a\nline b\nline c\nline d\nline e\n```\n"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	got = (&EditContext{Path: path, Line: 19, Start: 18, End: 25}).Snippet()
+	if !strings.Contains(got, "lines 18-25:") || !strings.HasSuffix(got, "line r\nline s\nline t\n```\n") {
+		t.Errorf("expected the marked region, clipped to the file, got %q", got)
+	}
+
+	got = (&EditContext{Path: filepath.Join(t.TempDir(), "gone.go"), Line: 3}).Snippet()
+	if !strings.Contains(got, "around line 3.") {
+		t.Errorf("expected a plain note for a missing file, got %q", got)
+	}
+}
+
+func TestTrackCursor(t *testing.T) {
+	if trackCursor(exec.Command("nano", "main.go"), "main.go", "nano", false) != nil {
+		t.Error("expected no marker when tracking is off")
+	}
+
+	c := exec.Command("nvim", "-O", "main.go", "notes.md")
+	m := trackCursor(c, "main.go", "nvim", true)
+	if m == nil || m.script == "" {
+		t.Fatal("expected a marker with a vim script")
+	}
+	if !slices.Equal(c.Args[:3], []string{"nvim", "-S", m.script}) || c.Args[len(c.Args)-1] != "notes.md" {
+		t.Errorf("expected the script sourced before the files, got %v", c.Args)
+	}
+	if !slices.Contains(c.Env, MarkerEnv+"="+m.path) {
+		t.Errorf("expected %s in the editor environment", MarkerEnv)
+	}
+	script, _ := os.ReadFile(m.script)
+	if !strings.Contains(string(script), "VimLeavePre") {
+		t.Errorf("unexpected script %q", script)
+	}
+
+	// The editor writes the marker on exit
+	if err := os.WriteFile(m.path, []byte("7 5-9\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	got := m.read()
+	if got == nil || got.Path != "main.go" || got.Line != 7 || got.Start != 5 || got.End != 9 {
+		t.Errorf("read() = %+v", got)
+	}
+	if _, err := os.Stat(m.path); !os.IsNotExist(err) {
+		t.Error("expected the marker file removed")
+	}
+	if _, err := os.Stat(m.script); !os.IsNotExist(err) {
+		t.Error("expected the script removed")
+	}
+}
+
+func TestInstructions_Render(t *testing.T) {
+	messages := []api.Message{
+		{Role: "user", Content: "How do I fix the server?"},
+		{Role: "assistant", Content: "In server.go:\n\n```go\nfunc serve() {}\n```\n\nAnd a helper:\n\n```go\nfunc unrelated() {}\n```\n"},
+		{Role: "assistant", Content: "```diff\n--- a/internal/server.go\n+++ b/internal/server.go\n@@ -1 +1 @@\n-old\n+new\n```"},
+		{Role: "user", Content: "Now add logging"},
+		{Role: "assistant", ToolCalls: []api.ToolCall{{ID: "1"}}},
+		{Role: "tool", Content: "file content", ToolCallID: "1"},
+		{Role: "assistant", Content: "Add a log line at the top of `serve`."},
+	}
+
+	ins := &Instructions{Messages: messages, Line: 12, Symbol: "serve"}
+	got := ins.Render("internal/server.go")
+	for _, want := range []string{
+		"# DeeCLI Edit Instructions for internal/server.go",
+		"The conversation referenced `serve` at line 12.",
+		"## AI Suggestions:\n\nAdd a log line at the top of `serve`.",
+		"## Related Code:",
+		"func serve() {}",
+		"+++ b/internal/server.go",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "unrelated") || strings.Contains(got, "file content") {
+		t.Errorf("expected only the code about the file, got:\n%s", got)
+	}
+	if strings.Index(got, "+++ b/internal/server.go") > strings.Index(got, "func serve") {
+		t.Error("expected the newest related block first")
+	}
+
+	// A small limit shortens the answer and leaves related code out
+	ins = &Instructions{Messages: append(messages[:6:6], api.Message{Role: "assistant", Content: "```go\n" + strings.Repeat("x := 1\n", 50) + "```"}), MaxSize: 100}
+	got = ins.Render("internal/server.go")
+	if !strings.Contains(got, "shortened to keep this file small") || !strings.Contains(got, "2 more related block(s) left out") {
+		t.Errorf("expected the limit applied, got:\n%s", got)
+	}
+	if strings.Count(got, "```")%2 != 0 {
+		t.Errorf("expected the cut code block closed, got:\n%s", got)
+	}
+
+	if got := (&Instructions{}).Render("main.go"); !strings.Contains(got, "No suggestions yet.") {
+		t.Errorf("expected a note without a conversation, got:\n%s", got)
+	}
+}
+
+func TestCreateInstructionFile(t *testing.T) {
+	if name := createInstructionFile("main.go", nil); name != "" {
+		t.Errorf("expected no file without instructions, got %s", name)
+	}
+	if name := createInstructionFile("main.go", func(string) *Instructions { return nil }); name != "" {
+		t.Errorf("expected no file when turned off, got %s", name)
+	}
+	name := createInstructionFile("main.go", func(string) *Instructions { return &Instructions{} })
+	defer os.Remove(name)
+	if data, err := os.ReadFile(name); err != nil || !strings.Contains(string(data), "main.go") {
+		t.Errorf("expected the instruction file written, got %q, %v", data, err)
+	}
+}
+
+func TestIsGUIEditor(t *testing.T) {
+	for base, want := range map[string]bool{"code": true, "subl": true, "gvim": true, "vim": false, "nano": false} {
+		if got := IsGUIEditor(base); got != want {
+			t.Errorf("IsGUIEditor(%q) = %v, want %v", base, got, want)
+		}
+	}
+}
+
+func TestWaitForSave(t *testing.T) {
+	if _, err := exec.LookPath("true"); err != nil {
+		t.Skip("true not available")
+	}
+	file := filepath.Join(t.TempDir(), "main.go")
+	if err := os.WriteFile(file, []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// The launcher exits at once; the wait ends when the file is saved
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		os.WriteFile(file, []byte("package main\n\nfunc main() {}\n"), 0644)
+	}()
+	if err := waitForSave(exec.Command("true"), file, 5*time.Second); err != nil {
+		t.Errorf("expected the save to end the wait, got %v", err)
+	}
+
+	if err := waitForSave(exec.Command("true"), file, 700*time.Millisecond); err == nil {
+		t.Error("expected a timeout when the file is not saved")
+	}
+	if err := waitForSave(exec.Command("false"), file, 5*time.Second); err == nil {
+		t.Error("expected a failing launcher to end the wait with its error")
+	}
+}
+
+func TestTerminalCmd_Run(t *testing.T) {
+	if _, err := exec.LookPath("cat"); err != nil {
+		t.Skip("cat not available")
+	}
+	// Without a terminal the editor just runs with the streams it is given
+	var out strings.Builder
+	c := &terminalCmd{cmd: exec.Command("cat")}
+	c.SetStdin(strings.NewReader("hello"))
+	c.SetStdout(&out)
+	c.SetStderr(&out)
+	if err := c.Run(); err != nil || out.String() != "hello" {
+		t.Errorf("Run() = %v with output %q", err, out.String())
+	}
+}
+
+func TestExpandCommand(t *testing.T) {
+	tests := []struct {
+		template     string
+		line         int
+		instructions string
+		want         []string
+	}{
+		{builtinCommand("nvim"), 42, "notes.md", []string{"nvim", "-O", "+42", "main.go", "notes.md"}},
+		{builtinCommand("nvim"), 0, "", []string{"nvim", "-O", "main.go"}},
+		{builtinCommand("code"), 0, "notes.md", []string{"nvim", "--goto", "main.go:1", "notes.md"}},
+		{builtinCommand("notepad"), 42, "notes.md", []string{"nvim", "main.go"}},
+		{builtinCommand("kak"), 7, "", []string{"nvim", "+7", "main.go"}},
+		{"hx {file}:{line}", 3, "notes.md", []string{"hx", "main.go:3"}},
+		{"'my editor' --split {instructions} {target}", 0, "notes.md", []string{"my editor", "--split", "notes.md", "main.go"}},
+	}
+	for _, tt := range tests {
+		args, err := expandCommand(tt.template, "nvim", "main.go", tt.line, tt.instructions)
+		if err != nil {
+			t.Errorf("expandCommand(%q): %v", tt.template, err)
+			continue
+		}
+		if !slices.Equal(args, tt.want) {
+			t.Errorf("expandCommand(%q, line %d, %q) = %q, want %q", tt.template, tt.line, tt.instructions, args, tt.want)
+		}
+	}
+
+	for _, template := range []string{"nvim {target} {cursor}", "nvim -O {instructions}", "nvim '{target}"} {
+		if _, err := expandCommand(template, "nvim", "main.go", 1, "notes.md"); err == nil {
+			t.Errorf("expected %q to be rejected", template)
+		}
+	}
+}
+
+func TestCommandTemplate(t *testing.T) {
+	cfg := Config{Command: "nvim -O {target} {instructions}", GotoCommand: "nvim +{line} {file}"}
+	if got := commandTemplate(cfg, true); got != cfg.Command {
+		t.Errorf("with instructions got %q", got)
+	}
+	if got := commandTemplate(cfg, false); got != cfg.GotoCommand {
+		t.Errorf("without instructions got %q", got)
+	}
+	if got := commandTemplate(Config{GotoCommand: cfg.GotoCommand}, true); got != cfg.GotoCommand {
+		t.Errorf("expected the goto command as fallback, got %q", got)
+	}
+	if got := commandTemplate(Config{}, false); got != "" {
+		t.Errorf("expected no template, got %q", got)
+	}
+}
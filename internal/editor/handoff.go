@@ -0,0 +1,173 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package editor
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"golang.org/x/term"
+)
+
+// guiWaitTimeout is how long a GUI editor may take to save the file before
+// the chat stops waiting for it
+const guiWaitTimeout = 30 * time.Minute
+
+// guiPollInterval is how often the file is checked for a save
+const guiPollInterval = 500 * time.Millisecond
+
+// guiEditors are editors whose launcher hands the file to a window and
+// returns at once, so their exit does not mean the user is done editing
+var guiEditors = map[string]bool{
+	"code": true, "code-insiders": true, "codium": true, "cursor": true,
+	"subl": true, "sublime_text": true, "atom": true, "zed": true,
+	"gedit": true, "kate": true, "mate": true, "notepad++": true,
+	"gvim": true, "mvim": true,
+}
+
+// IsGUIEditor reports whether editorBase, as returned by editorBaseName, is
+// an editor that opens its own window rather than running in the terminal
+func IsGUIEditor(editorBase string) bool {
+	return guiEditors[editorBase]
+}
+
+// terminalReset turns off the terminal modes an editor may leave on when it
+// crashes or is killed: mouse reporting, bracketed paste, focus events,
+// application keypad, scroll regions, attributes and a hidden cursor. The
+// chat turns the ones it uses back on when it takes the terminal again.
+const terminalReset = "\x1b[0m\x1b[r\x1b[?1000l\x1b[?1002l\x1b[?1003l\x1b[?1006l" +
+	"\x1b[?2004l\x1b[?1004l\x1b[?1l\x1b>\x1b[?25h"
+
+// terminalCmd runs an editor in the terminal and puts the terminal back the
+// way it found it, whatever state the editor exits in
+type terminalCmd struct {
+	cmd *exec.Cmd
+}
+
+// Run saves the terminal state, runs the editor and restores the state. The
+// chat makes the terminal raw again from the restored state, so an editor
+// that exits with the terminal raw or without echo cannot leave the shell
+// broken once DeeCLI quits.
+func (t *terminalCmd) Run() error {
+	in, _ := t.cmd.Stdin.(*os.File)
+	var saved *term.State
+	if in != nil && term.IsTerminal(int(in.Fd())) {
+		saved, _ = term.GetState(int(in.Fd()))
+	}
+
+	err := t.cmd.Run()
+
+	if out, ok := t.cmd.Stdout.(*os.File); ok && term.IsTerminal(int(out.Fd())) {
+		out.WriteString(terminalReset)
+	}
+	if saved != nil {
+		if restoreErr := term.Restore(int(in.Fd()), saved); restoreErr != nil && err == nil {
+			err = fmt.Errorf("error restoring terminal: %w", restoreErr)
+		}
+	}
+	return err
+}
+
+// SetStdin sets the editor's standard input
+func (t *terminalCmd) SetStdin(r io.Reader) {
+	if t.cmd.Stdin == nil {
+		t.cmd.Stdin = r
+	}
+}
+
+// SetStdout sets the editor's standard output
+func (t *terminalCmd) SetStdout(w io.Writer) {
+	if t.cmd.Stdout == nil {
+		t.cmd.Stdout = w
+	}
+}
+
+// SetStderr sets the editor's standard error
+func (t *terminalCmd) SetStderr(w io.Writer) {
+	if t.cmd.Stderr == nil {
+		t.cmd.Stderr = w
+	}
+}
+
+// launch runs c to edit file and reports the result as an EditorFinishedMsg,
+// after cleanup has removed its temporary files. Terminal editors take over
+// the terminal until they exit; GUI editors run beside the chat, which waits
+// for file to be saved instead, since their launcher returns at once.
+func launch(c *exec.Cmd, file, editorBase string, returnContext bool, cleanup func()) tea.Cmd {
+	if IsGUIEditor(editorBase) {
+		return func() tea.Msg {
+			err := waitForSave(c, file, guiWaitTimeout)
+			cleanup()
+			return EditorFinishedMsg{Error: err}
+		}
+	}
+
+	marker := trackCursor(c, file, editorBase, returnContext)
+	return tea.Exec(&terminalCmd{cmd: c}, func(err error) tea.Msg {
+		cleanup()
+		where := marker.read()
+		if err != nil {
+			return EditorFinishedMsg{Error: err}
+		}
+		return EditorFinishedMsg{Context: where}
+	})
+}
+
+// waitForSave starts a GUI editor and waits until file is saved, which
+// changes its modification time or size, or until timeout passes. It
+// returns early if the editor fails to start or exits with an error.
+func waitForSave(c *exec.Cmd, file string, timeout time.Duration) error {
+	before, _ := os.Stat(file)
+	if err := c.Start(); err != nil {
+		return err
+	}
+	exited := make(chan error, 1)
+	go func() { exited <- c.Wait() }()
+
+	ticker := time.NewTicker(guiPollInterval)
+	defer ticker.Stop()
+	deadline := time.After(timeout)
+	for {
+		select {
+		case err := <-exited:
+			if err != nil {
+				return err
+			}
+			// The launcher handed the file over; keep watching it
+			exited = nil
+		case <-ticker.C:
+			if saved(before, file) {
+				return nil
+			}
+		case <-deadline:
+			return fmt.Errorf("stopped waiting for %s to be saved after %s", file, timeout)
+		}
+	}
+}
+
+// saved reports whether file changed since before was taken
+func saved(before os.FileInfo, file string) bool {
+	now, err := os.Stat(file)
+	if err != nil {
+		return false
+	}
+	if before == nil {
+		return true
+	}
+	return !now.ModTime().Equal(before.ModTime()) || now.Size() != before.Size()
+}
@@ -0,0 +1,192 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package editor
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/antenore/deecli/internal/api"
+)
+
+// maxRelatedBlocks caps the code blocks from earlier answers quoted in the
+// instruction file
+const maxRelatedBlocks = 5
+
+// fencedBlock matches a fenced code block with its info string
+var fencedBlock = regexp.MustCompile("(?s)```([^\n`]*)\n(.*?)```")
+
+// Instructions is what the instruction file opened next to a file is built
+// from: the conversation as sent to the API and what it said about the file
+type Instructions struct {
+	Messages []api.Message
+	Line     int    // Line of the file the conversation referenced, 0 if none
+	Symbol   string // Function or type it named, or ""
+	MaxSize  int    // Bytes of suggestions and code kept; 0 keeps everything
+}
+
+// Render returns the instruction file for path: the last assistant turn,
+// code blocks about the file from earlier answers, and editing tips
+func (ins *Instructions) Render(path string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# DeeCLI Edit Instructions for %s\n\n", path)
+
+	// Add helpful editor shortcuts
+	b.WriteString("## Quick Editor Tips:\n")
+	b.WriteString("- **Vim/NVim**: Switch panes with `Ctrl+W W`, copy with `yip`, save+exit with `:wq`\n")
+	b.WriteString("- **VSCode**: Use split view, copy suggestions, then edit\n\n")
+
+	switch {
+	case ins.Line > 0 && ins.Symbol != "":
+		fmt.Fprintf(&b, "## Target:\nThe conversation referenced `%s` at line %d.\n\n", ins.Symbol, ins.Line)
+	case ins.Line > 0:
+		fmt.Fprintf(&b, "## Target:\nThe conversation referenced line %d.\n\n", ins.Line)
+	case ins.Symbol != "":
+		fmt.Fprintf(&b, "## Target:\nThe conversation referenced `%s`.\n\n", ins.Symbol)
+	}
+
+	budget := ins.MaxSize
+	if budget <= 0 {
+		budget = int(^uint(0) >> 1)
+	}
+
+	b.WriteString("## AI Suggestions:\n\n")
+	turn, start := lastAssistantTurn(ins.Messages)
+	if turn == "" {
+		b.WriteString("No suggestions yet.\n\n")
+	} else {
+		clipped := clip(turn, budget)
+		budget -= len(clipped)
+		b.WriteString(clipped + "\n\n")
+	}
+
+	related, omitted := []string{}, 0
+	for _, block := range relatedCodeBlocks(ins.Messages[:start], path, turn) {
+		if len(block) > budget {
+			omitted++
+			continue
+		}
+		budget -= len(block)
+		related = append(related, block)
+	}
+	if len(related) > 0 || omitted > 0 {
+		b.WriteString("## Related Code:\n\n")
+		for _, block := range related {
+			b.WriteString(block + "\n")
+		}
+		if omitted > 0 {
+			fmt.Fprintf(&b, "*%d more related block(s) left out to keep this file short; see the chat.*\n\n", omitted)
+		}
+	}
+
+	b.WriteString("## Next Steps:\n")
+	b.WriteString("1. Review the suggestions above\n")
+	b.WriteString("2. Copy relevant code/instructions\n")
+	b.WriteString("3. Switch to the other pane and make changes\n")
+	b.WriteString("4. Save and exit to return to chat\n\n")
+	b.WriteString("---\n")
+	b.WriteString("*This instruction file will be automatically deleted when you close the editor.*\n")
+	return b.String()
+}
+
+// lastAssistantTurn returns the text the assistant wrote after the last user
+// message, and the index of the first message of that turn
+func lastAssistantTurn(messages []api.Message) (string, int) {
+	start := 0
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Role == "user" {
+			start = i + 1
+			break
+		}
+	}
+	var parts []string
+	for _, msg := range messages[start:] {
+		if msg.Role == "assistant" && strings.TrimSpace(msg.Content) != "" {
+			parts = append(parts, strings.TrimSpace(msg.Content))
+		}
+	}
+	if len(parts) == 0 {
+		// The turn is still empty, e.g. a request is running; use the one before
+		for i := start - 1; i >= 0; i-- {
+			if messages[i].Role == "assistant" && strings.TrimSpace(messages[i].Content) != "" {
+				return strings.TrimSpace(messages[i].Content), i
+			}
+		}
+		return "", start
+	}
+	return strings.Join(parts, "\n\n"), start
+}
+
+// relatedCodeBlocks returns the code blocks of earlier assistant answers that
+// are about path, newest first: diffs of the file and blocks whose info
+// string or introducing line names it. Blocks already in skip are left out.
+func relatedCodeBlocks(messages []api.Message, path, skip string) []string {
+	name := filepath.Base(path)
+	var blocks []string
+	for i := len(messages) - 1; i >= 0 && len(blocks) < maxRelatedBlocks; i-- {
+		if messages[i].Role != "assistant" {
+			continue
+		}
+		content := messages[i].Content
+		for _, m := range fencedBlock.FindAllStringSubmatchIndex(content, -1) {
+			info, body := content[m[2]:m[3]], content[m[4]:m[5]]
+			if strings.TrimSpace(body) == "" || strings.Contains(skip, body) {
+				continue
+			}
+			if !strings.Contains(info, name) && !strings.Contains(introLine(content[:m[0]]), name) && !diffTouches(body, name) {
+				continue
+			}
+			blocks = append(blocks, "```"+info+"\n"+body+"```\n")
+			if len(blocks) == maxRelatedBlocks {
+				break
+			}
+		}
+	}
+	return blocks
+}
+
+// introLine returns the last non-empty line of text, which usually says what
+// the code block after it is
+func introLine(text string) string {
+	lines := strings.Split(strings.TrimRight(text, " \t\n"), "\n")
+	return lines[len(lines)-1]
+}
+
+// diffTouches reports whether a diff has a file header naming name
+func diffTouches(body, name string) bool {
+	for _, line := range strings.Split(body, "\n") {
+		if (strings.HasPrefix(line, "+++ ") || strings.HasPrefix(line, "--- ")) && strings.HasSuffix(strings.TrimSpace(line), name) {
+			return true
+		}
+	}
+	return false
+}
+
+// clip shortens text to about limit bytes at a line break, closing a code
+// block left open by the cut
+func clip(text string, limit int) string {
+	if len(text) <= limit {
+		return text
+	}
+	cut := text[:limit]
+	if i := strings.LastIndex(cut, "\n"); i > 0 {
+		cut = cut[:i]
+	}
+	if strings.Count(cut, "```")%2 == 1 {
+		cut += "\n```"
+	}
+	return cut + "\n\n*… shortened to keep this file small; the full answer is in the chat.*"
+}
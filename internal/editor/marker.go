@@ -0,0 +1,160 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package editor
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// MarkerEnv names the environment variable holding the marker file the
+// editor may write on exit to report where the user was in the file. It
+// holds the cursor line, optionally followed by a marked region, such as
+// "42" or "42 40-48".
+const MarkerEnv = "DEECLI_EDIT_MARKER"
+
+// snippetContext is the number of lines shown around the cursor line
+const snippetContext = 3
+
+// maxSnippetLines caps the marked region quoted in the snippet
+const maxSnippetLines = 60
+
+// EditContext is where the user was in a file when the editor closed
+type EditContext struct {
+	Path  string // File as it was opened
+	Line  int    // Cursor line
+	Start int    // First line of the marked region, 0 if there is none
+	End   int    // Last line of the marked region
+}
+
+// vimMarkerScript writes the marker from vim and nvim on exit: the cursor
+// line of the window showing the file, and the last visual selection in it
+const vimMarkerScript = `function! s:DeecliMarker() abort
+  try
+    let buf = bufnr(%s)
+    if buf < 0
+      return
+    endif
+    let wins = win_findbuf(buf)
+    let out = string(empty(wins) ? getbufinfo(buf)[0].lnum : line('.', wins[0]))
+    let lines = map(filter(getmarklist(buf), {_, m -> m.mark ==# "'<" || m.mark ==# "'>"}), {_, m -> m.pos[1]})
+    if len(lines) == 2
+      let out .= ' ' . min(lines) . '-' . max(lines)
+    endif
+    call writefile([out], %s)
+  catch
+  endtry
+endfunction
+autocmd VimLeavePre * call s:DeecliMarker()
+`
+
+// cursorMarker collects where the cursor was in a file when the editor exits
+type cursorMarker struct {
+	file   string
+	path   string // Marker file
+	script string // Vim script writing the marker, for vim and nvim
+}
+
+// trackCursor makes c report the cursor position in file through a marker
+// file. It returns nil when tracking is off or the marker cannot be created.
+func trackCursor(c *exec.Cmd, file, editorBase string, enabled bool) *cursorMarker {
+	if !enabled {
+		return nil
+	}
+	marker, err := os.CreateTemp("", "deecli_marker_*")
+	if err != nil {
+		return nil
+	}
+	marker.Close()
+	m := &cursorMarker{file: file, path: marker.Name()}
+
+	c.Env = append(os.Environ(), MarkerEnv+"="+m.path)
+	if strings.Contains(editorBase, "vim") {
+		script, err := os.CreateTemp("", "deecli_marker_*.vim")
+		if err == nil {
+			abs, _ := filepath.Abs(file)
+			fmt.Fprintf(script, vimMarkerScript, vimString(abs), vimString(m.path))
+			script.Close()
+			m.script = script.Name()
+			c.Args = append([]string{c.Args[0], "-S", m.script}, c.Args[1:]...)
+		}
+	}
+	return m
+}
+
+// vimString quotes s as a Vim single-quoted string
+func vimString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// read returns what the editor wrote to the marker and removes the temporary
+// files, or nil if the marker is empty or m is nil
+func (m *cursorMarker) read() *EditContext {
+	if m == nil {
+		return nil
+	}
+	data, _ := os.ReadFile(m.path)
+	os.Remove(m.path)
+	if m.script != "" {
+		os.Remove(m.script)
+	}
+	return ParseMarker(m.file, string(data))
+}
+
+// ParseMarker parses the content of a marker file written for file, such as
+// "42" or "42 40-48", and returns nil if it holds no line
+func ParseMarker(file, content string) *EditContext {
+	fields := strings.Fields(content)
+	if len(fields) == 0 {
+		return nil
+	}
+	ctx := &EditContext{Path: file}
+	if n, err := fmt.Sscanf(fields[0], "%d", &ctx.Line); err != nil || n != 1 || ctx.Line < 1 {
+		return nil
+	}
+	if len(fields) > 1 {
+		var start, end int
+		if n, err := fmt.Sscanf(fields[1], "%d-%d", &start, &end); err == nil && n == 2 && start >= 1 && end >= start {
+			ctx.Start, ctx.End = start, end
+		}
+	}
+	return ctx
+}
+
+// Snippet describes where the user was editing, with the marked region or
+// the lines around the cursor, as a prompt to continue the chat from
+func (c *EditContext) Snippet() string {
+	data, err := os.ReadFile(c.Path)
+	if err != nil {
+		return fmt.Sprintf("I was just editing %s around line %d.\n", c.Path, c.Line)
+	}
+	lines := strings.Split(strings.TrimSuffix(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n"), "\n")
+
+	intro := fmt.Sprintf("I was just editing %s around line %d:", c.Path, c.Line)
+	from, to := c.Line-snippetContext, c.Line+snippetContext
+	if c.Start > 0 {
+		intro = fmt.Sprintf("I was just editing %s, lines %d-%d:", c.Path, c.Start, c.End)
+		from, to = c.Start, min(c.End, c.Start+maxSnippetLines-1)
+	}
+	from, to = max(from, 1), min(to, len(lines))
+	if from > to {
+		return fmt.Sprintf("I was just editing %s around line %d.\n", c.Path, c.Line)
+	}
+
+	language := strings.TrimPrefix(filepath.Ext(c.Path), ".")
+	return fmt.Sprintf("%s\n\n```%s\n%s\n```\n", intro, language, strings.Join(lines[from-1:to], "\n"))
+}
@@ -0,0 +1,197 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package errlog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/antenore/deecli/internal/api"
+)
+
+// Category groups errors by their likely cause
+type Category string
+
+const (
+	CategoryConfig  Category = "config"
+	CategoryNetwork Category = "network"
+	CategoryContext Category = "context-too-large"
+	CategoryTool    Category = "tool-failure"
+	CategoryGeneral Category = "general"
+)
+
+// DefaultLimit is the number of errors kept in the log
+const DefaultLimit = 50
+
+var categoryCodes = map[Category]string{
+	CategoryConfig:  "CFG",
+	CategoryNetwork: "NET",
+	CategoryContext: "CTX",
+	CategoryTool:    "TOOL",
+	CategoryGeneral: "ERR",
+}
+
+// Code returns the short code for the category
+func (c Category) Code() string {
+	if code, ok := categoryCodes[c]; ok {
+		return code
+	}
+	return categoryCodes[CategoryGeneral]
+}
+
+// Entry is a single recorded error
+type Entry struct {
+	Category Category
+	Summary  string
+	Details  string
+	Time     time.Time
+}
+
+// Format renders the entry the way it is shown in the chat
+func (e Entry) Format() string {
+	return fmt.Sprintf("❌ [%s] %s", e.Category.Code(), e.Summary)
+}
+
+// Log keeps the most recent errors in memory
+type Log struct {
+	mu      sync.Mutex
+	entries []Entry
+	limit   int
+}
+
+// NewLog creates a log that keeps at most limit entries
+func NewLog(limit int) *Log {
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	return &Log{limit: limit}
+}
+
+// Record adds an error to the log and returns the stored entry.
+// Summary is the short user-facing text; err, if any, provides the details.
+func (l *Log) Record(category Category, summary string, err error) Entry {
+	entry := Entry{
+		Category: category,
+		Summary:  summary,
+		Time:     time.Now(),
+	}
+	if err != nil {
+		entry.Details = err.Error()
+		if entry.Summary == "" {
+			entry.Summary = UserMessage(err)
+		}
+	}
+
+	if l == nil {
+		return entry
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = append(l.entries, entry)
+	if len(l.entries) > l.limit {
+		l.entries = l.entries[len(l.entries)-l.limit:]
+	}
+	return entry
+}
+
+// Recent returns up to n entries, newest first. n <= 0 returns all entries.
+func (l *Log) Recent(n int) []Entry {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if n <= 0 || n > len(l.entries) {
+		n = len(l.entries)
+	}
+	recent := make([]Entry, 0, n)
+	for i := len(l.entries) - 1; i >= 0 && len(recent) < n; i-- {
+		recent = append(recent, l.entries[i])
+	}
+	return recent
+}
+
+// Clear removes all recorded errors
+func (l *Log) Clear() {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = nil
+}
+
+// Classify guesses the category of an error from its type and message
+func Classify(err error) Category {
+	if err == nil {
+		return CategoryGeneral
+	}
+
+	var apiErr api.APIError
+	if errors.As(err, &apiErr) {
+		if isContextTooLarge(apiErr.Message) {
+			return CategoryContext
+		}
+		switch {
+		case apiErr.StatusCode == 401 || apiErr.StatusCode == 403:
+			return CategoryConfig
+		case apiErr.StatusCode == 429 || apiErr.StatusCode >= 500:
+			return CategoryNetwork
+		case apiErr.StatusCode == 0 && strings.HasPrefix(apiErr.UserMessage, "Network error"):
+			return CategoryNetwork
+		}
+		return CategoryGeneral
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return CategoryNetwork
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return CategoryNetwork
+	}
+	if isContextTooLarge(err.Error()) {
+		return CategoryContext
+	}
+	return CategoryGeneral
+}
+
+// UserMessage returns the friendliest available message for an error
+func UserMessage(err error) string {
+	if err == nil {
+		return ""
+	}
+	var apiErr api.APIError
+	if errors.As(err, &apiErr) && apiErr.UserMessage != "" {
+		if apiErr.StatusCode > 0 {
+			return fmt.Sprintf("%s (HTTP %d)", apiErr.UserMessage, apiErr.StatusCode)
+		}
+		return apiErr.UserMessage
+	}
+	return err.Error()
+}
+
+func isContextTooLarge(message string) bool {
+	lower := strings.ToLower(message)
+	return strings.Contains(lower, "context_length_exceeded") ||
+		strings.Contains(lower, "maximum context length") ||
+		strings.Contains(lower, "context length")
+}
@@ -0,0 +1,87 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package errlog
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/antenore/deecli/internal/api"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want Category
+	}{
+		{name: "unauthorized", err: api.APIError{StatusCode: 401, Message: "unauthorized"}, want: CategoryConfig},
+		{name: "forbidden", err: api.APIError{StatusCode: 403, Message: "forbidden"}, want: CategoryConfig},
+		{name: "rate limited", err: api.APIError{StatusCode: 429, Message: "rate limited"}, want: CategoryNetwork},
+		{name: "server error", err: api.APIError{StatusCode: 503, Message: "server error"}, want: CategoryNetwork},
+		{name: "network failure", err: api.APIError{Message: "request failed", UserMessage: "Network error. Retrying..."}, want: CategoryNetwork},
+		{name: "context too large", err: api.APIError{StatusCode: 400, Message: "bad request: This model's maximum context length is 65536 tokens"}, want: CategoryContext},
+		{name: "deadline exceeded", err: context.DeadlineExceeded, want: CategoryNetwork},
+		{name: "plain error", err: errors.New("boom"), want: CategoryGeneral},
+		{name: "nil error", err: nil, want: CategoryGeneral},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Classify(tt.err); got != tt.want {
+				t.Errorf("Classify() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLog_RecordAndRecent(t *testing.T) {
+	log := NewLog(2)
+
+	log.Record(CategoryConfig, "first", nil)
+	log.Record(CategoryNetwork, "", api.APIError{StatusCode: 502, Message: "bad gateway", UserMessage: "Server error. Retrying..."})
+	log.Record(CategoryTool, "third", errors.New("tool exploded"))
+
+	recent := log.Recent(0)
+	if len(recent) != 2 {
+		t.Fatalf("Recent() returned %d entries, want 2", len(recent))
+	}
+	if recent[0].Summary != "third" || recent[0].Details != "tool exploded" {
+		t.Errorf("newest entry = %+v, want summary 'third' with details", recent[0])
+	}
+	if recent[1].Summary != "Server error. Retrying... (HTTP 502)" {
+		t.Errorf("summary from API error = %q", recent[1].Summary)
+	}
+
+	if got := log.Recent(1); len(got) != 1 || got[0].Summary != "third" {
+		t.Errorf("Recent(1) = %+v, want only the newest entry", got)
+	}
+
+	log.Clear()
+	if len(log.Recent(0)) != 0 {
+		t.Error("Clear() should remove all entries")
+	}
+}
+
+func TestEntry_Format(t *testing.T) {
+	entry := Entry{Category: CategoryTool, Summary: "read_file failed"}
+	if got := entry.Format(); !strings.Contains(got, "[TOOL] read_file failed") {
+		t.Errorf("Format() = %q", got)
+	}
+	if got := (Entry{Category: "unknown", Summary: "x"}).Format(); !strings.Contains(got, "[ERR]") {
+		t.Errorf("unknown category should use the general code, got %q", got)
+	}
+}
@@ -0,0 +1,274 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package eval runs a suite of prompts against two system prompt or model
+// variants and compares the answers.
+package eval
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/antenore/deecli/internal/api"
+	"gopkg.in/yaml.v3"
+)
+
+// Suite is a set of prompts and the two variants they are run against
+type Suite struct {
+	Name     string    `yaml:"name"`
+	Variants []Variant `yaml:"variants"` // Exactly two
+	Cases    []Case    `yaml:"cases"`
+}
+
+// Variant is a system prompt and model combination under test
+type Variant struct {
+	Name        string   `yaml:"name"`
+	System      string   `yaml:"system,omitempty"`      // System prompt; empty uses the shipped chat prompt
+	SystemFile  string   `yaml:"system_file,omitempty"` // File with the system prompt, relative to the suite
+	Model       string   `yaml:"model,omitempty"`       // Empty uses the configured model
+	Temperature *float64 `yaml:"temperature,omitempty"` // Unset uses the configured temperature
+}
+
+// SystemPrompt returns the system prompt the variant is run with
+func (v Variant) SystemPrompt() string {
+	if v.System == "" {
+		return api.ChatSystemPrompt
+	}
+	return v.System
+}
+
+// Case is one prompt of the suite
+type Case struct {
+	Name   string   `yaml:"name"`
+	Prompt string   `yaml:"prompt"`
+	Expect []string `yaml:"expect,omitempty"` // Phrases a good answer contains, matched case-insensitively
+}
+
+// Load reads and validates the suite at path, reading system_file prompts
+func Load(path string) (*Suite, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read suite: %w", err)
+	}
+
+	var suite Suite
+	if err := yaml.Unmarshal(data, &suite); err != nil {
+		return nil, fmt.Errorf("failed to parse suite %s: %w", path, err)
+	}
+	if suite.Name == "" {
+		suite.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+
+	if len(suite.Variants) != 2 {
+		return nil, fmt.Errorf("suite must define exactly 2 variants, found %d", len(suite.Variants))
+	}
+	for i := range suite.Variants {
+		v := &suite.Variants[i]
+		if v.Name == "" {
+			v.Name = string(rune('A' + i))
+		}
+		if v.SystemFile != "" {
+			if v.System != "" {
+				return nil, fmt.Errorf("variant %s sets both system and system_file", v.Name)
+			}
+			file := v.SystemFile
+			if !filepath.IsAbs(file) {
+				file = filepath.Join(filepath.Dir(path), file)
+			}
+			prompt, err := os.ReadFile(file)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read system prompt of variant %s: %w", v.Name, err)
+			}
+			v.System = strings.TrimSpace(string(prompt))
+		}
+	}
+	if suite.Variants[0].Name == suite.Variants[1].Name {
+		return nil, fmt.Errorf("both variants are named %s", suite.Variants[0].Name)
+	}
+
+	if len(suite.Cases) == 0 {
+		return nil, fmt.Errorf("suite has no cases")
+	}
+	for i := range suite.Cases {
+		c := &suite.Cases[i]
+		if c.Name == "" {
+			c.Name = fmt.Sprintf("case %d", i+1)
+		}
+		if strings.TrimSpace(c.Prompt) == "" {
+			return nil, fmt.Errorf("%s has no prompt", c.Name)
+		}
+	}
+	return &suite, nil
+}
+
+// Completer sends a single prompt with a system prompt
+type Completer interface {
+	RunPrompt(ctx context.Context, systemPrompt, prompt string) (*api.ChatResponse, error)
+}
+
+// Result is the answer of one variant to one case
+type Result struct {
+	Response         string
+	Latency          time.Duration
+	PromptTokens     int
+	CompletionTokens int
+	Matched          []string // Expected phrases found in the response
+	Err              error
+}
+
+// CaseResult holds the answers of both variants to a case
+type CaseResult struct {
+	Case    Case
+	Results [2]Result
+}
+
+// Report is the outcome of running a suite
+type Report struct {
+	Suite *Suite
+	Cases []CaseResult
+}
+
+// Run sends every case to both variants, the first variant through
+// completers[0] and the second through completers[1]. progress, if set, is
+// called before each request. A cancelled ctx stops the run and returns the
+// cases finished so far.
+func Run(ctx context.Context, suite *Suite, completers [2]Completer, progress func(c Case, variant Variant)) *Report {
+	report := &Report{Suite: suite}
+	for _, c := range suite.Cases {
+		result := CaseResult{Case: c}
+		for i, variant := range suite.Variants {
+			if ctx.Err() != nil {
+				return report
+			}
+			if progress != nil {
+				progress(c, variant)
+			}
+			result.Results[i] = runCase(ctx, completers[i], variant, c)
+		}
+		report.Cases = append(report.Cases, result)
+	}
+	return report
+}
+
+// runCase sends one case to one variant and scores the answer
+func runCase(ctx context.Context, completer Completer, variant Variant, c Case) Result {
+	start := time.Now()
+	response, err := completer.RunPrompt(ctx, variant.SystemPrompt(), c.Prompt)
+	result := Result{Latency: time.Since(start), Err: err}
+	if err != nil {
+		return result
+	}
+	if len(response.Choices) > 0 {
+		result.Response = response.Choices[0].Message.Content
+	}
+	result.PromptTokens = response.Usage.PromptTokens
+	result.CompletionTokens = response.Usage.CompletionTokens
+
+	lower := strings.ToLower(result.Response)
+	for _, phrase := range c.Expect {
+		if strings.Contains(lower, strings.ToLower(phrase)) {
+			result.Matched = append(result.Matched, phrase)
+		}
+	}
+	return result
+}
+
+// Summary aggregates the results of one variant
+type Summary struct {
+	Matched          int // Expected phrases found, over all cases
+	Expected         int // Expected phrases, over all cases
+	Wins             int // Cases where the variant matched more phrases than the other
+	Errors           int
+	AvgLatency       time.Duration
+	PromptTokens     int
+	CompletionTokens int
+}
+
+// Summaries returns the aggregated results of both variants
+func (r *Report) Summaries() [2]Summary {
+	var summaries [2]Summary
+	var latency [2]time.Duration
+	for _, c := range r.Cases {
+		for i, result := range c.Results {
+			s := &summaries[i]
+			s.Expected += len(c.Case.Expect)
+			s.Matched += len(result.Matched)
+			s.PromptTokens += result.PromptTokens
+			s.CompletionTokens += result.CompletionTokens
+			latency[i] += result.Latency
+			if result.Err != nil {
+				s.Errors++
+			}
+		}
+		switch a, b := len(c.Results[0].Matched), len(c.Results[1].Matched); {
+		case a > b:
+			summaries[0].Wins++
+		case b > a:
+			summaries[1].Wins++
+		}
+	}
+	if n := len(r.Cases); n > 0 {
+		for i := range summaries {
+			summaries[i].AvgLatency = latency[i] / time.Duration(n)
+		}
+	}
+	return summaries
+}
+
+// Markdown renders the report: a summary table followed by both answers to each case
+func (r *Report) Markdown() string {
+	var b strings.Builder
+	a, z := r.Suite.Variants[0], r.Suite.Variants[1]
+	summaries := r.Summaries()
+
+	fmt.Fprintf(&b, "# Eval: %s\n\n", r.Suite.Name)
+	fmt.Fprintf(&b, "%d of %d cases run.\n\n", len(r.Cases), len(r.Suite.Cases))
+	fmt.Fprintf(&b, "| | %s | %s |\n|---|---|---|\n", a.Name, z.Name)
+	fmt.Fprintf(&b, "| Model | %s | %s |\n", modelName(a), modelName(z))
+	fmt.Fprintf(&b, "| Expected phrases | %d/%d | %d/%d |\n", summaries[0].Matched, summaries[0].Expected, summaries[1].Matched, summaries[1].Expected)
+	fmt.Fprintf(&b, "| Cases won | %d | %d |\n", summaries[0].Wins, summaries[1].Wins)
+	fmt.Fprintf(&b, "| Avg latency | %s | %s |\n", summaries[0].AvgLatency.Round(time.Millisecond), summaries[1].AvgLatency.Round(time.Millisecond))
+	fmt.Fprintf(&b, "| Tokens (prompt/completion) | %d/%d | %d/%d |\n", summaries[0].PromptTokens, summaries[0].CompletionTokens, summaries[1].PromptTokens, summaries[1].CompletionTokens)
+	fmt.Fprintf(&b, "| Errors | %d | %d |\n", summaries[0].Errors, summaries[1].Errors)
+
+	for _, c := range r.Cases {
+		fmt.Fprintf(&b, "\n## %s\n\n", c.Case.Name)
+		fmt.Fprintf(&b, "> %s\n", strings.ReplaceAll(strings.TrimSpace(c.Case.Prompt), "\n", "\n> "))
+		for i, variant := range r.Suite.Variants {
+			result := c.Results[i]
+			fmt.Fprintf(&b, "\n### %s (%s", variant.Name, result.Latency.Round(time.Millisecond))
+			if len(c.Case.Expect) > 0 {
+				fmt.Fprintf(&b, ", %d/%d expected", len(result.Matched), len(c.Case.Expect))
+			}
+			b.WriteString(")\n\n")
+			if result.Err != nil {
+				fmt.Fprintf(&b, "**Error:** %v\n", result.Err)
+				continue
+			}
+			b.WriteString(strings.TrimSpace(result.Response) + "\n")
+		}
+	}
+	return b.String()
+}
+
+// modelName returns the model of a variant as shown in the report
+func modelName(v Variant) string {
+	if v.Model == "" {
+		return "configured"
+	}
+	return v.Model
+}
@@ -0,0 +1,135 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eval
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/antenore/deecli/internal/api"
+)
+
+// fakeCompleter answers every prompt with a fixed response or error
+type fakeCompleter struct {
+	response string
+	err      error
+	systems  []string
+}
+
+func (f *fakeCompleter) RunPrompt(ctx context.Context, systemPrompt, prompt string) (*api.ChatResponse, error) {
+	f.systems = append(f.systems, systemPrompt)
+	if f.err != nil {
+		return nil, f.err
+	}
+	content, _ := json.Marshal(f.response)
+	var response api.ChatResponse
+	body := fmt.Sprintf(`{"choices":[{"message":{"role":"assistant","content":%s}}],"usage":{"completion_tokens":%d}}`, content, len(f.response))
+	if err := json.Unmarshal([]byte(body), &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "terse.txt"), "Be terse.\n")
+	path := filepath.Join(dir, "suite.yaml")
+	writeFile(t, path, `
+variants:
+  - name: current
+  - name: terse
+    system_file: terse.txt
+    model: deepseek-reasoner
+cases:
+  - prompt: "What is a nil map?"
+    expect: ["make"]
+`)
+
+	suite, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if suite.Name != "suite" {
+		t.Errorf("Expected the file name as suite name, got %q", suite.Name)
+	}
+	if suite.Variants[0].SystemPrompt() != api.ChatSystemPrompt {
+		t.Error("Expected a variant without system prompt to use the shipped one")
+	}
+	if suite.Variants[1].SystemPrompt() != "Be terse." {
+		t.Errorf("Expected system_file to be read, got %q", suite.Variants[1].System)
+	}
+	if suite.Cases[0].Name != "case 1" {
+		t.Errorf("Expected a default case name, got %q", suite.Cases[0].Name)
+	}
+
+	writeFile(t, path, "variants:\n  - name: only\ncases:\n  - prompt: hi\n")
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "exactly 2 variants") {
+		t.Errorf("Expected a variant count error, got %v", err)
+	}
+}
+
+func TestRunAndReport(t *testing.T) {
+	suite := &Suite{
+		Name:     "nil maps",
+		Variants: []Variant{{Name: "a"}, {Name: "b", System: "Be terse."}},
+		Cases: []Case{
+			{Name: "panic", Prompt: "Why does m[\"a\"] = 1 panic?", Expect: []string{"nil map", "make"}},
+			{Name: "zero", Prompt: "What is the zero value of a map?", Expect: []string{"nil"}},
+		},
+	}
+	a := &fakeCompleter{response: "Writing to a Nil Map panics; use make."}
+	b := &fakeCompleter{err: errors.New("rate limited")}
+
+	report := Run(context.Background(), suite, [2]Completer{a, b}, nil)
+	if len(report.Cases) != 2 {
+		t.Fatalf("Expected 2 cases, got %d", len(report.Cases))
+	}
+	if b.systems[0] != "Be terse." || a.systems[0] != api.ChatSystemPrompt {
+		t.Errorf("Unexpected system prompts: %q, %q", a.systems[0], b.systems[0])
+	}
+
+	summaries := report.Summaries()
+	if summaries[0].Matched != 3 || summaries[0].Expected != 3 || summaries[0].Wins != 2 {
+		t.Errorf("Unexpected summary for a: %+v", summaries[0])
+	}
+	if summaries[1].Errors != 2 || summaries[1].Wins != 0 {
+		t.Errorf("Unexpected summary for b: %+v", summaries[1])
+	}
+
+	markdown := report.Markdown()
+	for _, want := range []string{"# Eval: nil maps", "| Expected phrases | 3/3 | 0/3 |", "## panic", "**Error:** rate limited"} {
+		if !strings.Contains(markdown, want) {
+			t.Errorf("Report is missing %q:\n%s", want, markdown)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if report := Run(ctx, suite, [2]Completer{a, b}, nil); len(report.Cases) != 0 {
+		t.Errorf("Expected a cancelled run to stop, got %d cases", len(report.Cases))
+	}
+}
@@ -21,43 +21,57 @@ import (
 	"time"
 )
 
+// contextPromptHeader opens the prompt built from loaded files
+const contextPromptHeader = "I have the following files loaded for context:\n\n"
+
+// DefaultMaxContext is how many files may be loaded at once
+const DefaultMaxContext = 50
+
 type FileContext struct {
 	Files             []LoadedFile
 	Loader            *FileLoader
-	MaxContext        int
+	MaxContext        int // Most files loaded at once (max_loaded_files)
+	LazyThreshold     int // Load new files as lazy stubs once the context holds more than this many (0 disables)
+	Formatters        map[string][]string // Formatter command by language, run on files written by /apply
 	watcher           *FileWatcher
 	autoReloadEnabled bool
 	reloadMutex       sync.Mutex
 	lastManualReload  time.Time // Track manual reloads
 	reloadCallback    func([]ReloadResult) // Callback for auto-reload notifications
+	suggestions       map[string][]PatchSuggestion // Unapplied AI diffs by file path
+	suggestionsMu     sync.Mutex
+	promptCache       map[promptCacheKey]promptCacheEntry // Rendered file blocks reused between prompts
+	promptCacheMu     sync.Mutex
+	journal           ChangeJournal // Loaded files changed on disk during the session
+	undo              []contextSnapshot // File context before recent loads, unloads and clears
 }
 
 func NewFileContext() *FileContext {
 	return &FileContext{
 		Files:      []LoadedFile{},
 		Loader:     NewFileLoader(),
-		MaxContext: 50,
+		MaxContext: DefaultMaxContext,
 	}
 }
 
 func (fc *FileContext) LoadFile(path string) error {
-	file, err := fc.Loader.LoadFile(path)
+	file, err := fc.loadEntry(path, fc.LazyThreshold > 0 && len(fc.Files) >= fc.LazyThreshold)
 	if err != nil {
 		return err
 	}
 
-	for i, f := range fc.Files {
-		if f.Path == file.Path {
-			fc.Files[i] = file
-			return nil
-		}
+	if i := fc.indexOf(file.Path); i >= 0 {
+		fc.Files[i] = file
+		fc.resetPromptCache()
+		return nil
 	}
 
 	if len(fc.Files) >= fc.MaxContext {
-		return fmt.Errorf("context limit reached (%d files)", fc.MaxContext)
+		return fmt.Errorf("context limit reached (%d files, max_loaded_files)", fc.MaxContext)
 	}
 
 	fc.Files = append(fc.Files, file)
+	fc.resetPromptCache()
 
 	// Add to watcher if auto-reload is enabled
 	if fc.autoReloadEnabled && fc.watcher != nil {
@@ -71,43 +85,39 @@ func (fc *FileContext) LoadFile(path string) error {
 }
 
 func (fc *FileContext) LoadFiles(patterns []string) error {
-	files, err := fc.Loader.LoadFiles(patterns)
+	paths, err := fc.Loader.ExpandPatterns(patterns)
 	if err != nil {
 		return err
 	}
 
 	// First, check if we can load all files without exceeding the limit
 	newFilesCount := 0
-	for _, file := range files {
-		exists := false
-		for _, f := range fc.Files {
-			if f.Path == file.Path {
-				exists = true
-				break
-			}
-		}
-		if !exists {
+	for _, path := range paths {
+		if fc.indexOf(path) < 0 {
 			newFilesCount++
 		}
 	}
 
 	if len(fc.Files)+newFilesCount > fc.MaxContext {
-		return fmt.Errorf("cannot load %d files: would exceed context limit of %d files (currently have %d)",
+		return fmt.Errorf("cannot load %d files: would exceed context limit of %d files set by max_loaded_files (currently have %d)",
 			newFilesCount, fc.MaxContext, len(fc.Files))
 	}
 
+	// Big loads keep only metadata in memory; content is read when a prompt needs it
+	lazy := fc.LazyThreshold > 0 && len(fc.Files)+newFilesCount > fc.LazyThreshold
+	files, err := loadAll(paths, func(path string) (LoadedFile, error) {
+		return fc.loadEntry(path, lazy)
+	})
+	if err != nil {
+		return err
+	}
+
 	// Now actually load the files since we know they'll all fit
+	fc.resetPromptCache()
 	for _, file := range files {
-		exists := false
-		for i, f := range fc.Files {
-			if f.Path == file.Path {
-				fc.Files[i] = file
-				exists = true
-				break
-			}
-		}
-
-		if !exists {
+		if i := fc.indexOf(file.Path); i >= 0 {
+			fc.Files[i] = file
+		} else {
 			fc.Files = append(fc.Files, file)
 		}
 
@@ -123,12 +133,69 @@ func (fc *FileContext) LoadFiles(patterns []string) error {
 	return nil
 }
 
+// indexOf returns the index of the loaded file at path, or loaded through
+// another path to the same file, or -1 if it is not loaded
+func (fc *FileContext) indexOf(path string) int {
+	real := realPath(path)
+	for i, f := range fc.Files {
+		if f.Path == path || f.canonicalPath() == real {
+			return i
+		}
+	}
+	return -1
+}
+
+// loadEntry loads a file fully, or as a lazy stub holding only its metadata
+func (fc *FileContext) loadEntry(path string, lazy bool) (LoadedFile, error) {
+	var file LoadedFile
+	var err error
+	if lazy {
+		file, err = fc.Loader.LoadStub(path)
+	} else {
+		file, err = fc.Loader.LoadFile(path)
+	}
+	if err != nil {
+		return file, err
+	}
+	file = resolveLinks(file)
+
+	// A language given with /load --lang outlasts reloads
+	if language := fc.setLanguage(file.Path); language != "" {
+		file.Language, file.LanguageSet = language, true
+	}
+	return file, nil
+}
+
+// Content returns a loaded file's content, reading lazy stubs from disk
+func (fc *FileContext) Content(file LoadedFile) (string, error) {
+	if !file.Lazy {
+		return file.Content, nil
+	}
+	content, err := fc.Loader.ReadContent(file.Path)
+	if err != nil {
+		return "", fmt.Errorf("error reading %s: %w", file.RelPath, err)
+	}
+	return content, nil
+}
+
+// hasLazyFiles reports whether any loaded file is a lazy stub
+func (fc *FileContext) hasLazyFiles() bool {
+	for _, f := range fc.Files {
+		if f.Lazy {
+			return true
+		}
+	}
+	return false
+}
+
 func (fc *FileContext) Clear() {
 	// Unwatch all files if watcher is active
 	if fc.watcher != nil && fc.autoReloadEnabled {
 		fc.watcher.UnwatchAll()
 	}
 	fc.Files = []LoadedFile{}
+	fc.resetPromptCache()
+	fc.dropSuggestions("")
 }
 
 func (fc *FileContext) RemoveFile(path string) bool {
@@ -158,6 +225,8 @@ func (fc *FileContext) RemoveFile(path string) bool {
 		if fc.watcher != nil && fc.autoReloadEnabled {
 			fc.watcher.Unwatch(removedPath)
 		}
+		fc.resetPromptCache()
+		fc.dropSuggestions(removedPath)
 		return true
 	}
 
@@ -179,7 +248,9 @@ func (fc *FileContext) UnloadFiles(pattern string) int {
 			if fc.watcher != nil && fc.autoReloadEnabled {
 				fc.watcher.Unwatch(file.Path)
 			}
+			fc.dropSuggestions(file.Path)
 			fc.Files = append(fc.Files[:i], fc.Files[i+1:]...)
+			fc.resetPromptCache()
 			removed++
 			// Don't increment i since we removed an element
 		} else {
@@ -228,6 +299,37 @@ func (fc *FileContext) GetLoadedPaths() []string {
 	return paths
 }
 
+// Checksums returns the content hash of each loaded file keyed by absolute path,
+// a cheap snapshot for telling later which files changed. Lazy stubs have no
+// hash yet, so their size and modification time stand in for it.
+func (fc *FileContext) Checksums() map[string]string {
+	sums := make(map[string]string, len(fc.Files))
+	for _, f := range fc.Files {
+		sums[f.Path] = fingerprint(f)
+	}
+	return sums
+}
+
+// fingerprint identifies a version of a loaded file's content
+func fingerprint(f LoadedFile) string {
+	if f.Hash != "" {
+		return f.Hash
+	}
+	return fmt.Sprintf("%d-%d", f.Size, f.ModTime.UnixNano())
+}
+
+// ChangedSince returns the loaded files whose hash differs from a Checksums
+// snapshot, including files loaded after the snapshot was taken
+func (fc *FileContext) ChangedSince(snapshot map[string]string) []LoadedFile {
+	var changed []LoadedFile
+	for _, f := range fc.Files {
+		if hash, ok := snapshot[f.Path]; !ok || hash != fingerprint(f) {
+			changed = append(changed, f)
+		}
+	}
+	return changed
+}
+
 func (fc *FileContext) GetContextSize() int64 {
 	var total int64
 	for _, f := range fc.Files {
@@ -237,7 +339,45 @@ func (fc *FileContext) GetContextSize() int64 {
 }
 
 func (fc *FileContext) GetFormattedContextSize() int {
-	return len(fc.BuildContextPrompt())
+	if !fc.hasLazyFiles() {
+		return len(fc.BuildContextPrompt())
+	}
+
+	// Estimate from file sizes instead of reading every stub on each UI refresh
+	size := len(contextPromptHeader)
+	for _, file := range fc.Files {
+		size += fc.estimatedBlockSize(file)
+	}
+	return size
+}
+
+// estimatedBlockSize estimates the size of the prompt block of file from its
+// size, without reading it
+func (fc *FileContext) estimatedBlockSize(file LoadedFile) int {
+	var header strings.Builder
+	fc.appendFileContent(&header, file, false)
+	size := 0
+	shown := file.Size
+	if file.Preview && fc.Loader.PreviewSize < shown {
+		shown = fc.Loader.PreviewSize
+		size += len(previewNote(file, int(shown)))
+	}
+	return size + header.Len() + int(shown) + len("\n```\n\n")
+}
+
+// GetFileTokens estimates the tokens each loaded file adds to the context
+// prompt, in the order of Files
+func (fc *FileContext) GetFileTokens() []int {
+	lazy := fc.hasLazyFiles()
+	tokens := make([]int, len(fc.Files))
+	for i, file := range fc.Files {
+		if lazy {
+			tokens[i] = fc.estimatedBlockSize(file) / 4
+		} else {
+			tokens[i] = len(fc.cachedFileBlock(file, 0)) / 4
+		}
+	}
+	return tokens
 }
 
 // GetEstimatedTokens estimates token count from context using the common approximation
@@ -266,28 +406,19 @@ func (fc *FileContext) BuildContextPromptWithLimit(maxSize int) string {
 	}
 
 	var prompt strings.Builder
-	prompt.WriteString("I have the following files loaded for context:\n\n")
+	prompt.WriteString(contextPromptHeader)
 
-	// If no limit specified, use the original behavior
+	// If no limit specified, show every file in full
 	if maxSize == 0 {
 		for _, file := range fc.Files {
-			fc.appendFileContent(&prompt, file, false)
-
-			// Show full content
-			cleanContent := fc.cleanupContentForContext(file.Content)
-			prompt.WriteString(cleanContent)
-
-			if !strings.HasSuffix(cleanContent, "\n") {
-				prompt.WriteString("\n")
-			}
-			prompt.WriteString("```\n\n")
+			prompt.WriteString(fc.cachedFileBlock(file, 0))
 		}
 		return prompt.String()
 	}
 
 	// Smart truncation when size limit is specified
 	const headerOverhead = 200 // Approximate overhead per file header
-	remainingSize := maxSize - len("I have the following files loaded for context:\n\n")
+	remainingSize := maxSize - len(contextPromptHeader)
 
 	// Reserve space for file headers first
 	contentBudget := remainingSize - (len(fc.Files) * headerOverhead)
@@ -312,29 +443,107 @@ func (fc *FileContext) BuildContextPromptWithLimit(maxSize int) string {
 			fileContentBudget = 500
 		}
 
-		truncated := len(file.Content) > fileContentBudget
-		fc.appendFileContent(&prompt, file, truncated)
+		prompt.WriteString(fc.cachedFileBlock(file, fileContentBudget))
+	}
+
+	return prompt.String()
+}
+
+// promptCacheKey identifies a file's rendered prompt block for a content budget
+type promptCacheKey struct {
+	path        string
+	budget      int
+	previewSize int64
+}
+
+// promptCacheEntry is a rendered prompt block and the file version it was built from
+type promptCacheEntry struct {
+	fingerprint string
+	block       string
+}
+
+// cachedFileBlock returns fileBlock(file, budget), reusing the cleaned block
+// from an earlier prompt while the file's fingerprint is unchanged
+func (fc *FileContext) cachedFileBlock(file LoadedFile, budget int) string {
+	key := promptCacheKey{path: file.Path, budget: budget, previewSize: fc.Loader.PreviewSize}
+	fp := fingerprint(file)
+
+	fc.promptCacheMu.Lock()
+	entry, ok := fc.promptCache[key]
+	fc.promptCacheMu.Unlock()
+	if ok && entry.fingerprint == fp {
+		return entry.block
+	}
+
+	block, err := fc.fileBlock(file, budget)
+	if err != nil {
+		// Don't keep transient read errors around
+		return block
+	}
+
+	fc.promptCacheMu.Lock()
+	if fc.promptCache == nil {
+		fc.promptCache = make(map[promptCacheKey]promptCacheEntry)
+	}
+	fc.promptCache[key] = promptCacheEntry{fingerprint: fp, block: block}
+	fc.promptCacheMu.Unlock()
+	return block
+}
+
+// resetPromptCache drops all cached prompt blocks. Called whenever the set of
+// files or their sizes change, since that shifts every file's budget.
+func (fc *FileContext) resetPromptCache() {
+	fc.promptCacheMu.Lock()
+	fc.promptCache = nil
+	fc.promptCacheMu.Unlock()
+}
+
+// fileBlock renders one file for the context prompt, truncating its content to
+// budget characters when budget is positive. The error reports an unreadable
+// lazy file, whose block then describes the error instead of the content.
+func (fc *FileContext) fileBlock(file LoadedFile, budget int) (string, error) {
+	var block strings.Builder
+
+	content, err := fc.Content(file)
+	if err != nil {
+		content = fmt.Sprintf("[content unavailable: %v]", err)
+	}
+	truncated := budget > 0 && len(content) > budget
+	fc.appendFileContent(&block, file, truncated)
 
+	if truncated {
+		// Show truncated content
+		cleanContent := fc.cleanupContentForContext(content[:budget])
+		block.WriteString(cleanContent)
+		if !strings.HasSuffix(cleanContent, "\n") {
+			block.WriteString("\n")
+		}
+		block.WriteString(fmt.Sprintf("... [TRUNCATED - showing %d/%d chars] ...\n", budget, len(content)))
+	} else {
+		// Show full content
+		cleanContent := fc.cleanupContentForContext(content)
+		block.WriteString(cleanContent)
+		if !strings.HasSuffix(cleanContent, "\n") {
+			block.WriteString("\n")
+		}
+	}
+	block.WriteString("```\n")
+	if file.Preview || truncated {
+		shown := len(content)
 		if truncated {
-			// Show truncated content
-			cleanContent := fc.cleanupContentForContext(file.Content[:fileContentBudget])
-			prompt.WriteString(cleanContent)
-			if !strings.HasSuffix(cleanContent, "\n") {
-				prompt.WriteString("\n")
-			}
-			prompt.WriteString(fmt.Sprintf("... [TRUNCATED - showing %d/%d chars] ...\n", fileContentBudget, len(file.Content)))
-		} else {
-			// Show full content
-			cleanContent := fc.cleanupContentForContext(file.Content)
-			prompt.WriteString(cleanContent)
-			if !strings.HasSuffix(cleanContent, "\n") {
-				prompt.WriteString("\n")
-			}
+			shown = budget
 		}
-		prompt.WriteString("```\n\n")
+		block.WriteString(previewNote(file, shown))
 	}
+	block.WriteString("\n")
 
-	return prompt.String()
+	return block.String(), err
+}
+
+// previewNote tells the AI how to fetch the part of a file not shown in the prompt
+func previewNote(file LoadedFile, shown int) string {
+	return fmt.Sprintf("[PREVIEW - showing the first %d of %d bytes; call read_more with path %q and offset %d for the next chunk]\n",
+		shown, file.Size, file.RelPath, shown)
 }
 
 // appendFileContent adds file header and content setup
@@ -343,7 +552,11 @@ func (fc *FileContext) appendFileContent(prompt *strings.Builder, file LoadedFil
 	if truncated {
 		truncatedNote = " [TRUNCATED]"
 	}
-	prompt.WriteString(fmt.Sprintf("=== File: %s (%s)%s ===\n", file.RelPath, file.Language, truncatedNote))
+	language := file.Language
+	if file.ExtractedBy != "" {
+		language = "text extracted with " + file.ExtractedBy
+	}
+	prompt.WriteString(fmt.Sprintf("=== File: %s (%s)%s ===\n", file.RelPath, language, truncatedNote))
 	prompt.WriteString("```")
 	if file.Language != "text" {
 		prompt.WriteString(file.Language)
@@ -458,7 +671,7 @@ func (fc *FileContext) ReloadFiles(patterns []string) ([]ReloadResult, error) {
 		}
 		
 		// Load fresh content
-		newFile, err := fc.Loader.LoadFile(path)
+		newFile, err := fc.loadEntry(path, oldFile.Lazy)
 		if err != nil {
 			results = append(results, ReloadResult{
 				Path: oldFile.RelPath,
@@ -473,19 +686,24 @@ func (fc *FileContext) ReloadFiles(patterns []string) ([]ReloadResult, error) {
 		
 		// Track result
 		status := "unchanged"
-		if oldFile.Size != newFile.Size {
-			status = "changed"
-		} else if oldFile.Content != newFile.Content {
+		if !SameContent(*oldFile, newFile) {
 			status = "changed"
 		}
 		
-		results = append(results, ReloadResult{
+		result := ReloadResult{
 			Path: newFile.RelPath,
 			OldSize: oldFile.Size,
 			NewSize: newFile.Size,
 			Language: newFile.Language,
 			Status: status,
-		})
+		}
+		if status == "changed" {
+			fc.resetPromptCache()
+			// Don't let pending AI diffs silently target a stale baseline
+			result.Suggestions = fc.recheckSuggestions(newFile)
+			fc.recordChange(newFile, result.Suggestions)
+		}
+		results = append(results, result)
 	}
 	
 	return results, nil
@@ -499,6 +717,8 @@ type ReloadResult struct {
 	Language string
 	Status   string // "changed", "unchanged", "error"
 	Error    string
+	// Suggestions reports pending AI diffs for the file re-checked against its new content
+	Suggestions []SuggestionCheck
 }
 
 // SetWatcher sets the file watcher for auto-reload functionality
@@ -550,6 +770,15 @@ func (fc *FileContext) DisableAutoReload() {
 	}
 }
 
+// Close stops the file watcher; files are no longer reloaded automatically
+func (fc *FileContext) Close() error {
+	fc.autoReloadEnabled = false
+	if fc.watcher == nil {
+		return nil
+	}
+	return fc.watcher.Stop()
+}
+
 // autoReloadFiles performs automatic reload without duplicate prevention interference
 func (fc *FileContext) autoReloadFiles(paths []string) ([]ReloadResult, error) {
 	fc.reloadMutex.Lock()
@@ -575,7 +804,7 @@ func (fc *FileContext) autoReloadFiles(paths []string) ([]ReloadResult, error) {
 		}
 
 		// Load fresh content
-		newFile, err := fc.Loader.LoadFile(path)
+		newFile, err := fc.loadEntry(path, oldFile.Lazy)
 		if err != nil {
 			results = append(results, ReloadResult{
 				Path: oldFile.RelPath,
@@ -590,19 +819,24 @@ func (fc *FileContext) autoReloadFiles(paths []string) ([]ReloadResult, error) {
 
 		// Track result
 		status := "unchanged"
-		if oldFile.Size != newFile.Size {
-			status = "changed"
-		} else if oldFile.Content != newFile.Content {
+		if !SameContent(*oldFile, newFile) {
 			status = "changed"
 		}
 
-		results = append(results, ReloadResult{
+		result := ReloadResult{
 			Path: newFile.RelPath,
 			OldSize: oldFile.Size,
 			NewSize: newFile.Size,
 			Language: newFile.Language,
 			Status: status,
-		})
+		}
+		if status == "changed" {
+			fc.resetPromptCache()
+			// Don't let pending AI diffs silently target a stale baseline
+			result.Suggestions = fc.recheckSuggestions(newFile)
+			fc.recordChange(newFile, result.Suggestions)
+		}
+		results = append(results, result)
 	}
 
 	return results, nil
@@ -613,6 +847,14 @@ func (fc *FileContext) IsAutoReloadSupported() bool {
 	return fc.watcher != nil && fc.watcher.IsSupported()
 }
 
+// AutoReloadUnsupportedReason returns why auto-reload is unavailable, or nil if the cause is unknown
+func (fc *FileContext) AutoReloadUnsupportedReason() error {
+	if fc.watcher == nil {
+		return nil
+	}
+	return fc.watcher.UnsupportedReason()
+}
+
 // IsAutoReloadEnabled returns true if auto-reload is currently enabled
 func (fc *FileContext) IsAutoReloadEnabled() bool {
 	return fc.autoReloadEnabled && fc.IsAutoReloadSupported()
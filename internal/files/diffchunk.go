@@ -0,0 +1,108 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package files
+
+import "strings"
+
+// ChunkDiff splits a multi-file unified diff into chunks of at most budget
+// bytes, so that each can be reviewed in a separate request. Whole files are
+// kept together where they fit; larger files are split between hunks, and
+// each piece repeats the file header.
+func ChunkDiff(diff string, budget int) []string {
+	var chunks []string
+	var current strings.Builder
+	flush := func() {
+		if current.Len() > 0 {
+			chunks = append(chunks, current.String())
+			current.Reset()
+		}
+	}
+	add := func(piece string) {
+		if current.Len() > 0 && current.Len()+len(piece)+1 > budget {
+			flush()
+		}
+		if current.Len() > 0 {
+			current.WriteString("\n")
+		}
+		current.WriteString(piece)
+	}
+
+	for _, fileDiff := range splitFileDiffs(diff) {
+		if len(fileDiff) <= budget {
+			add(fileDiff)
+			continue
+		}
+		header, hunks := splitHunks(fileDiff)
+		for _, piece := range packLines(hunks, budget-len(header)-1) {
+			add(header + "\n" + piece)
+		}
+	}
+	flush()
+	return chunks
+}
+
+// splitHunks separates the header of a single-file diff from its hunks
+func splitHunks(fileDiff string) (string, []string) {
+	lines := strings.Split(fileDiff, "\n")
+	var hunks []string
+	start := -1
+	header := fileDiff
+	for i, line := range lines {
+		if !strings.HasPrefix(line, "@@") {
+			continue
+		}
+		if start < 0 {
+			header = strings.Join(lines[:i], "\n")
+		} else {
+			hunks = append(hunks, strings.Join(lines[start:i], "\n"))
+		}
+		start = i
+	}
+	if start >= 0 {
+		hunks = append(hunks, strings.Join(lines[start:], "\n"))
+	}
+	return header, hunks
+}
+
+// packLines groups hunks into pieces of at most budget bytes, cutting hunks
+// that are larger than the budget on their own at line boundaries
+func packLines(hunks []string, budget int) []string {
+	budget = max(budget, 1)
+	var pieces []string
+	var current strings.Builder
+	add := func(text string) {
+		if current.Len() > 0 && current.Len()+len(text)+1 > budget {
+			pieces = append(pieces, current.String())
+			current.Reset()
+		}
+		if current.Len() > 0 {
+			current.WriteString("\n")
+		}
+		current.WriteString(text)
+	}
+
+	for _, hunk := range hunks {
+		if len(hunk) <= budget {
+			add(hunk)
+			continue
+		}
+		for _, line := range strings.Split(hunk, "\n") {
+			add(line)
+		}
+	}
+	if current.Len() > 0 {
+		pieces = append(pieces, current.String())
+	}
+	return pieces
+}
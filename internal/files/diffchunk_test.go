@@ -0,0 +1,52 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package files
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestChunkDiff(t *testing.T) {
+	small := "diff --git a/a.go b/a.go\n--- a/a.go\n+++ b/a.go\n@@ -1 +1 @@\n-a\n+b"
+	other := strings.Replace(small, "a.go", "c.go", -1)
+	large := "diff --git a/big.go b/big.go\n--- a/big.go\n+++ b/big.go\n" +
+		"@@ -1,2 +1,2 @@\n-" + strings.Repeat("x", 60) + "\n+" + strings.Repeat("y", 60) + "\n" +
+		"@@ -10,2 +10,2 @@\n-" + strings.Repeat("z", 60) + "\n+" + strings.Repeat("w", 60)
+
+	chunks := ChunkDiff(small+"\n"+other+"\n"+large, 200)
+	if len(chunks) != 3 {
+		t.Fatalf("expected 3 chunks, got %d: %q", len(chunks), chunks)
+	}
+	if chunks[0] != small+"\n"+other {
+		t.Errorf("expected the small files to share a chunk, got %q", chunks[0])
+	}
+	for _, chunk := range chunks[1:] {
+		if !strings.HasPrefix(chunk, "diff --git a/big.go b/big.go\n--- a/big.go\n+++ b/big.go\n@@") {
+			t.Errorf("expected each piece of a split file to repeat its header, got %q", chunk)
+		}
+	}
+	for _, chunk := range chunks {
+		if len(chunk) > 200 {
+			t.Errorf("chunk of %d bytes exceeds the budget", len(chunk))
+		}
+	}
+
+	if chunks := ChunkDiff(small, 1000); len(chunks) != 1 || chunks[0] != small {
+		t.Errorf("expected a single chunk, got %q", chunks)
+	}
+	if chunks := ChunkDiff("", 1000); len(chunks) != 0 {
+		t.Errorf("expected no chunks for an empty diff, got %q", chunks)
+	}
+}
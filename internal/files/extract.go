@@ -0,0 +1,147 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package files
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// extractTimeout bounds a single text extraction
+const extractTimeout = 60 * time.Second
+
+// TextExtractor converts binary documents to text with an external command
+// that writes the text to stdout
+type TextExtractor struct {
+	Command    string
+	Extensions []string                   // Lower-case extensions handled, with the dot
+	Args       func(path string) []string // Command line arguments for the file at path
+}
+
+// Extractors are the text extraction hooks tried, in order, for binary files
+// when FileLoader.ExtractText is set
+var Extractors = []TextExtractor{
+	{
+		Command:    "pdftotext",
+		Extensions: []string{".pdf"},
+		Args:       func(path string) []string { return []string{"-layout", "-enc", "UTF-8", path, "-"} },
+	},
+	{
+		Command:    "tesseract",
+		Extensions: []string{".png", ".jpg", ".jpeg", ".tif", ".tiff", ".bmp", ".webp"},
+		Args:       func(path string) []string { return []string{path, "stdout"} },
+	},
+}
+
+// extractorFor returns the extractor for the extension of path, or nil
+func extractorFor(path string) *TextExtractor {
+	ext := strings.ToLower(filepath.Ext(path))
+	for i := range Extractors {
+		if slices.Contains(Extractors[i].Extensions, ext) {
+			return &Extractors[i]
+		}
+	}
+	return nil
+}
+
+// Available reports whether the extractor's command is installed
+func (e *TextExtractor) Available() bool {
+	_, err := exec.LookPath(e.Command)
+	return err == nil
+}
+
+// Extract returns the text of the document at path
+func (e *TextExtractor) Extract(path string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), extractTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, e.Command, e.Args(path)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%s failed: %w: %s", e.Command, err, msg)
+		}
+		return "", fmt.Errorf("%s failed: %w", e.Command, err)
+	}
+
+	text := strings.TrimSpace(strings.ToValidUTF8(string(output), "�"))
+	if text == "" {
+		return "", fmt.Errorf("%s found no text in %s", e.Command, filepath.Base(path))
+	}
+	return text + "\n", nil
+}
+
+// textExtractor returns the extractor to load absPath with, or nil when the
+// file is loaded as is
+func (fl *FileLoader) textExtractor(absPath string) *TextExtractor {
+	if !fl.ExtractText {
+		return nil
+	}
+	extractor := extractorFor(absPath)
+	if extractor == nil || !extractor.Available() || !fl.isBinaryFile(absPath) {
+		return nil
+	}
+	return extractor
+}
+
+// loadExtracted loads the text extracted from the binary document at absPath.
+// The hash covers the document itself, so changes to it are still noticed.
+func (fl *FileLoader) loadExtracted(absPath string, extractor *TextExtractor) (LoadedFile, error) {
+	info, err := fl.statFile(absPath)
+	if err != nil {
+		return LoadedFile{}, err
+	}
+
+	_, hash, err := fl.readContent(absPath, info.Size())
+	if err != nil {
+		return LoadedFile{}, err
+	}
+	text, err := extractor.Extract(absPath)
+	if err != nil {
+		return LoadedFile{}, err
+	}
+
+	return LoadedFile{
+		Path:        absPath,
+		RelPath:     displayPath(absPath),
+		Content:     text,
+		Size:        int64(len(text)),
+		Language:    "text",
+		Hash:        hash,
+		ModTime:     info.ModTime(),
+		ExtractedBy: extractor.Command,
+	}, nil
+}
+
+// binaryFileError explains why the binary file at relPath cannot be loaded,
+// pointing at text extraction when it would help
+func (fl *FileLoader) binaryFileError(relPath string) error {
+	extractor := extractorFor(relPath)
+	switch {
+	case extractor == nil:
+		return fmt.Errorf("'%s' appears to be a binary file, skipping. Use /load <text_files> instead", relPath)
+	case !fl.ExtractText:
+		return fmt.Errorf("'%s' is a binary file, skipping. Set extract-text to true to load its text with %s", relPath, extractor.Command)
+	default:
+		return fmt.Errorf("'%s' is a binary file, skipping. Install %s to load its text", relPath, extractor.Command)
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package files
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakePdftotext puts a pdftotext script printing text on the PATH
+func fakePdftotext(t *testing.T, text string) {
+	t.Helper()
+	bin := t.TempDir()
+	script := "#!/bin/sh\nprintf '%s' '" + text + "'\n"
+	if err := os.WriteFile(filepath.Join(bin, "pdftotext"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestLoadExtractedText(t *testing.T) {
+	t.Chdir(t.TempDir())
+	if err := os.WriteFile("doc.pdf", []byte("%PDF-1.4\x00\x01binary"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	loader := NewFileLoader()
+	_, err := loader.LoadFile("doc.pdf")
+	if err == nil || !strings.Contains(err.Error(), "Set extract-text to true") {
+		t.Fatalf("LoadFile() without extraction error = %v, want a hint to enable extract-text", err)
+	}
+
+	loader.ExtractText = true
+	t.Setenv("PATH", t.TempDir())
+	_, err = loader.LoadFile("doc.pdf")
+	if err == nil || !strings.Contains(err.Error(), "Install pdftotext") {
+		t.Fatalf("LoadFile() without pdftotext error = %v, want a hint to install it", err)
+	}
+
+	fakePdftotext(t, "Quarterly report")
+	file, err := loader.LoadFile("doc.pdf")
+	if err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+	if file.Content != "Quarterly report\n" || file.ExtractedBy != "pdftotext" || file.Language != "text" {
+		t.Errorf("LoadFile() = %+v, want the extracted text", file)
+	}
+
+	// Extracted text is kept in memory even when a stub is asked for
+	stub, err := loader.LoadStub("doc.pdf")
+	if err != nil || stub.Lazy || stub.Content != file.Content {
+		t.Errorf("LoadStub() = %+v, %v, want the extracted text", stub, err)
+	}
+}
+
+func TestLoadExtractedTextEmpty(t *testing.T) {
+	t.Chdir(t.TempDir())
+	if err := os.WriteFile("scan.pdf", []byte("%PDF\x00"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	fakePdftotext(t, "  ")
+
+	loader := NewFileLoader()
+	loader.ExtractText = true
+	if _, err := loader.LoadFile("scan.pdf"); err == nil || !strings.Contains(err.Error(), "found no text") {
+		t.Errorf("LoadFile() error = %v, want no text found", err)
+	}
+}
+
+func TestExtractTextIgnoresTextFiles(t *testing.T) {
+	t.Chdir(t.TempDir())
+	if err := os.WriteFile("notes.pdf", []byte("plain text\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	fakePdftotext(t, "extracted")
+
+	loader := NewFileLoader()
+	loader.ExtractText = true
+	file, err := loader.LoadFile("notes.pdf")
+	if err != nil || file.Content != "plain text\n" || file.ExtractedBy != "" {
+		t.Errorf("LoadFile() = %+v, %v, want the file as is", file, err)
+	}
+}
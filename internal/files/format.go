@@ -0,0 +1,161 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package files
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// formatTimeout bounds a single formatter run
+const formatTimeout = 30 * time.Second
+
+// Format runs command on content and returns its output. The formatter reads
+// the source on stdin and writes the formatted source to stdout, as gofmt
+// does; it runs in dir so tools such as goimports find the module.
+func Format(command []string, dir, content string) (string, error) {
+	if len(command) == 0 {
+		return content, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), formatTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	cmd.Dir = dir
+	cmd.Stdin = strings.NewReader(content)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%s failed: %w: %s", command[0], err, msg)
+		}
+		return "", fmt.Errorf("%s failed: %w", command[0], err)
+	}
+	if len(output) == 0 && content != "" {
+		return "", fmt.Errorf("%s produced no output", command[0])
+	}
+	return string(output), nil
+}
+
+// formatterFor returns the formatter configured for the language of path,
+// or nil if there is none
+func (fc *FileContext) formatterFor(path string) []string {
+	if len(fc.Formatters) == 0 || fc.Loader == nil {
+		return nil
+	}
+	if language := fc.setLanguage(path); language != "" {
+		return fc.Formatters[language]
+	}
+	return fc.Formatters[fc.Loader.detectLanguage(path)]
+}
+
+// FormatFile runs the formatter for the language of path on content. It
+// returns content unchanged, with the name of the formatter, when formatting
+// fails, so the caller can still write the file and report the error.
+func (fc *FileContext) FormatFile(path, content string) (string, string, error) {
+	command := fc.formatterFor(path)
+	if len(command) == 0 {
+		return content, "", nil
+	}
+	formatted, err := Format(command, filepath.Dir(path), content)
+	if err != nil {
+		return content, command[0], err
+	}
+	return formatted, command[0], nil
+}
+
+// UnifiedDiff returns a unified diff of old and new with three lines of
+// context, or "" if they have the same lines
+func UnifiedDiff(path, old, new string) string {
+	const context = 3
+	diff := DiffLines(old, new)
+
+	var b strings.Builder
+	for start := 0; start < len(diff); {
+		// Find the next change and extend the hunk until the changes are
+		// more than twice the context apart
+		first := start
+		for first < len(diff) && diff[first].Op == LineEqual {
+			first++
+		}
+		if first == len(diff) {
+			break
+		}
+		last := first
+		for i := first; i < len(diff) && i <= last+2*context; i++ {
+			if diff[i].Op != LineEqual {
+				last = i
+			}
+		}
+		from, to := max(first-context, 0), min(last+context+1, len(diff))
+
+		if b.Len() == 0 {
+			fmt.Fprintf(&b, "--- a/%s\n+++ b/%s\n", path, path)
+		}
+		oldStart, oldCount, newStart, newCount := 0, 0, 0, 0
+		for _, line := range diff[from:to] {
+			if line.Op != LineInserted {
+				if oldCount == 0 {
+					oldStart = line.OldNum
+				}
+				oldCount++
+			}
+			if line.Op != LineDeleted {
+				if newCount == 0 {
+					newStart = line.NewNum
+				}
+				newCount++
+			}
+		}
+		if oldCount == 0 {
+			oldStart = precedingNum(diff[:from], false)
+		}
+		if newCount == 0 {
+			newStart = precedingNum(diff[:from], true)
+		}
+		fmt.Fprintf(&b, "@@ -%d,%d +%d,%d @@\n", oldStart, oldCount, newStart, newCount)
+		for _, line := range diff[from:to] {
+			prefix := " "
+			switch line.Op {
+			case LineDeleted:
+				prefix = "-"
+			case LineInserted:
+				prefix = "+"
+			}
+			b.WriteString(prefix + line.Text + "\n")
+		}
+		start = to
+	}
+	return b.String()
+}
+
+// precedingNum returns the last old or new line number in lines, which is
+// where a hunk that has no lines on that side starts
+func precedingNum(lines []DiffLine, newSide bool) int {
+	for i := len(lines) - 1; i >= 0; i-- {
+		if newSide && lines[i].NewNum > 0 {
+			return lines[i].NewNum
+		}
+		if !newSide && lines[i].OldNum > 0 {
+			return lines[i].OldNum
+		}
+	}
+	return 0
+}
@@ -14,20 +14,32 @@
 package files
 
 import (
+	"os"
 	"path/filepath"
 
 	"github.com/sabhiram/go-gitignore"
 )
 
+// DeecliIgnoreFile is the project file listing paths DeeCLI should never load
+const DeecliIgnoreFile = ".deecliignore"
+
 // GitignoreFilter provides gitignore filtering functionality using a proven library
 type GitignoreFilter struct {
-	ignorer *ignore.GitIgnore
-	enabled bool
+	ignorer       *ignore.GitIgnore
+	enabled       bool
+	deecliIgnorer *ignore.GitIgnore // .deecliignore and config patterns, always applied
 }
 
 // NewGitignoreFilter creates a new gitignore filter
-// If respectGitignore is false, all files will be allowed
+// If respectGitignore is false, only .deecliignore patterns are applied
 func NewGitignoreFilter(respectGitignore bool) *GitignoreFilter {
+	return NewGitignoreFilterWithPatterns(respectGitignore, nil)
+}
+
+// NewGitignoreFilterWithPatterns creates a gitignore filter with extra ignore patterns
+// The extra patterns (from the `ignore:` config) and .deecliignore are applied
+// even when respectGitignore is false
+func NewGitignoreFilterWithPatterns(respectGitignore bool, patterns []string) *GitignoreFilter {
 	gf := &GitignoreFilter{
 		enabled: respectGitignore,
 	}
@@ -43,21 +55,51 @@ func NewGitignoreFilter(respectGitignore bool) *GitignoreFilter {
 		gf.ignorer = ignorer
 	}
 
+	// Load .deecliignore and append config patterns
+	if deecliIgnorer, err := ignore.CompileIgnoreFileAndLines(DeecliIgnoreFile, patterns...); err == nil {
+		gf.deecliIgnorer = deecliIgnorer
+	} else if len(patterns) > 0 {
+		gf.deecliIgnorer = ignore.CompileIgnoreLines(patterns...)
+	}
+
 	return gf
 }
 
-// ShouldIgnore returns true if the file path should be ignored according to .gitignore
+// ShouldIgnore returns true if the file path should be ignored according to .gitignore or .deecliignore
 func (gf *GitignoreFilter) ShouldIgnore(path string) bool {
+	if gf.IsExcluded(path) {
+		return true
+	}
+
 	if !gf.enabled || gf.ignorer == nil {
 		return false
 	}
 
-	// Convert to relative path from current directory
+	// Use the battle-tested gitignore library
+	return gf.ignorer.MatchesPath(relativePath(path))
+}
+
+// IsExcluded returns true if the path matches .deecliignore or the configured ignore patterns
+func (gf *GitignoreFilter) IsExcluded(path string) bool {
+	if gf == nil || gf.deecliIgnorer == nil {
+		return false
+	}
+	return gf.deecliIgnorer.MatchesPath(relativePath(path))
+}
+
+// relativePath converts a path to be relative to the current directory
+func relativePath(path string) string {
+	if filepath.IsAbs(path) {
+		if wd, err := os.Getwd(); err == nil {
+			if relPath, err := filepath.Rel(wd, path); err == nil {
+				return relPath
+			}
+		}
+		return path
+	}
 	relPath, err := filepath.Rel(".", path)
 	if err != nil {
-		relPath = path
+		return path
 	}
-
-	// Use the battle-tested gitignore library
-	return gf.ignorer.MatchesPath(relPath)
-}
\ No newline at end of file
+	return relPath
+}
@@ -14,6 +14,7 @@
 package files
 
 import (
+	"os"
 	"strings"
 	"testing"
 
@@ -129,4 +130,55 @@ func TestGitignoreLibraryIntegration(t *testing.T) {
 			t.Errorf("pattern %q with path %q: got %v, want %v", tt.pattern, tt.path, !tt.matches, tt.matches)
 		}
 	}
-}
\ No newline at end of file
+}
+
+func TestDeecliIgnorePatterns(t *testing.T) {
+	// Config patterns apply even when .gitignore is bypassed
+	filter := NewGitignoreFilterWithPatterns(false, []string{"secrets/", "*.pem", "testdata/fixtures/"})
+
+	tests := []struct {
+		path   string
+		ignore bool
+	}{
+		{"secrets/api.key", true},
+		{"certs/server.pem", true},
+		{"testdata/fixtures/big.json", true},
+		{"main.go", false},
+		{"node_modules/test.js", false},
+	}
+
+	for _, tt := range tests {
+		if got := filter.ShouldIgnore(tt.path); got != tt.ignore {
+			t.Errorf("ShouldIgnore(%q) = %v, want %v", tt.path, got, tt.ignore)
+		}
+		if got := filter.IsExcluded(tt.path); got != tt.ignore {
+			t.Errorf("IsExcluded(%q) = %v, want %v", tt.path, got, tt.ignore)
+		}
+	}
+}
+
+func TestDeecliIgnoreFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	os.Chdir(tmpDir)
+
+	if err := os.WriteFile(DeecliIgnoreFile, []byte("generated/\n*.secret\n"), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", DeecliIgnoreFile, err)
+	}
+
+	filter := NewGitignoreFilterWithPatterns(true, []string{"fixtures/"})
+
+	if !filter.ShouldIgnore("generated/api.pb.go") {
+		t.Error("should ignore paths listed in .deecliignore")
+	}
+	if !filter.ShouldIgnore("db.secret") {
+		t.Error("should ignore files matching .deecliignore patterns")
+	}
+	if !filter.ShouldIgnore("fixtures/data.json") {
+		t.Error("should ignore configured patterns alongside .deecliignore")
+	}
+	if filter.ShouldIgnore("main.go") {
+		t.Error("should not ignore regular files")
+	}
+}
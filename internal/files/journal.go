@@ -0,0 +1,91 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package files
+
+import (
+	"sync"
+	"time"
+)
+
+// ChangeEntry is a loaded file that changed on disk during the session
+type ChangeEntry struct {
+	Path    string // Absolute path
+	RelPath string
+	Time    time.Time // Last time the change was seen
+	Applied bool      // The change applied a diff the AI suggested
+}
+
+// ChangeJournal records the loaded files that changed on disk during the
+// session, in the order they first changed, so they can be committed together
+type ChangeJournal struct {
+	mu      sync.Mutex
+	entries []ChangeEntry
+}
+
+// Record notes that a file changed; applied marks a change that applied an AI diff
+func (j *ChangeJournal) Record(path, relPath string, applied bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	for i := range j.entries {
+		if j.entries[i].Path == path {
+			j.entries[i].Time = time.Now()
+			j.entries[i].Applied = j.entries[i].Applied || applied
+			return
+		}
+	}
+	j.entries = append(j.entries, ChangeEntry{Path: path, RelPath: relPath, Time: time.Now(), Applied: applied})
+}
+
+// Entries returns the recorded changes, oldest first
+func (j *ChangeJournal) Entries() []ChangeEntry {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return append([]ChangeEntry(nil), j.entries...)
+}
+
+// Forget drops the given paths from the journal, typically once they are committed
+func (j *ChangeJournal) Forget(paths []string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	drop := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		drop[path] = true
+	}
+	kept := j.entries[:0]
+	for _, entry := range j.entries {
+		if !drop[entry.Path] {
+			kept = append(kept, entry)
+		}
+	}
+	j.entries = kept
+}
+
+// Journal returns the record of loaded files changed during the session
+func (fc *FileContext) Journal() *ChangeJournal {
+	return &fc.journal
+}
+
+// recordChange adds a reloaded file to the journal
+func (fc *FileContext) recordChange(file LoadedFile, checks []SuggestionCheck) {
+	applied := false
+	for _, check := range checks {
+		if check.Status == "applied" {
+			applied = true
+		}
+	}
+	fc.journal.Record(file.Path, file.RelPath, applied)
+}
@@ -0,0 +1,62 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package files
+
+import (
+	"os"
+	"testing"
+)
+
+func TestChangeJournal(t *testing.T) {
+	fc, path := loadSuggestionFile(t, "func main() {\n\tprintln(\"hello\")\n}\n")
+	if entries := fc.Journal().Entries(); len(entries) != 0 {
+		t.Fatalf("Entries() = %v before any change", entries)
+	}
+
+	// Reloading an unchanged file records nothing
+	if _, err := fc.ReloadFiles(nil); err != nil {
+		t.Fatal(err)
+	}
+	if entries := fc.Journal().Entries(); len(entries) != 0 {
+		t.Fatalf("Entries() = %v after an unchanged reload", entries)
+	}
+
+	fc.TrackPatchSuggestions(suggestionResponse)
+	if err := os.WriteFile(path, []byte("func main() {\n\tprintln(\"hello, world\")\n}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fc.ReloadFiles(nil); err != nil {
+		t.Fatal(err)
+	}
+	entries := fc.Journal().Entries()
+	if len(entries) != 1 || entries[0].Path != path || !entries[0].Applied {
+		t.Fatalf("Entries() = %+v, want one applied change of %s", entries, path)
+	}
+
+	// A later manual edit keeps the file once and remembers the applied diff
+	if err := os.WriteFile(path, []byte("func main() {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fc.ReloadFiles(nil); err != nil {
+		t.Fatal(err)
+	}
+	if entries := fc.Journal().Entries(); len(entries) != 1 || !entries[0].Applied {
+		t.Fatalf("Entries() = %+v after a second change", entries)
+	}
+
+	fc.Journal().Forget([]string{path})
+	if entries := fc.Journal().Entries(); len(entries) != 0 {
+		t.Fatalf("Entries() = %v after Forget", entries)
+	}
+}
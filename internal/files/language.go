@@ -0,0 +1,216 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package files
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// languageProbeSize is how many bytes at each end of a file are read to
+// find a modeline or shebang
+const languageProbeSize = 1024
+
+// modelineLines is how many lines at each end of a file may hold a modeline,
+// as in Vim
+const modelineLines = 5
+
+var (
+	vimModeline   = regexp.MustCompile(`(?:^|\s)(?:vi|vim|ex):.*?\b(?:ft|filetype)=([\w+#-]+)`)
+	emacsModeline = regexp.MustCompile(`-\*-(.*?)-\*-`)
+	emacsMode     = regexp.MustCompile(`(?i)(?:^|;)\s*mode:\s*([\w+#-]+)`)
+)
+
+// languageAliases maps editor and interpreter names to the language names
+// used in the context prompt
+var languageAliases = map[string]string{
+	"sh":           "bash",
+	"dash":         "bash",
+	"ksh":          "bash",
+	"shell-script": "bash",
+	"js":           "javascript",
+	"node":         "javascript",
+	"nodejs":       "javascript",
+	"ts":           "typescript",
+	"deno":         "typescript",
+	"ts-node":      "typescript",
+	"py":           "python",
+	"rb":           "ruby",
+	"c++":          "cpp",
+	"cs":           "csharp",
+	"c#":           "csharp",
+	"pwsh":         "powershell",
+	"rscript":      "r",
+	"escript":      "erlang",
+	"runhaskell":   "haskell",
+	"make":         "makefile",
+	"yml":          "yaml",
+	"md":           "markdown",
+}
+
+// shebangLanguages are the interpreters a shebang line is recognized by
+var shebangLanguages = map[string]bool{
+	"python": true, "bash": true, "zsh": true, "fish": true, "javascript": true,
+	"typescript": true, "ruby": true, "perl": true, "php": true, "lua": true,
+	"r": true, "powershell": true, "julia": true, "elixir": true, "erlang": true,
+	"haskell": true, "swift": true, "makefile": true, "scala": true,
+}
+
+// detectLanguage returns the language of the file at path: the one named by
+// a Vim or Emacs modeline, else the one of its extension or name, else the
+// interpreter of its shebang line
+func (fl *FileLoader) detectLanguage(path string) string {
+	head, tail := readEnds(path, languageProbeSize)
+	if lang := modelineLanguage(append(head, tail...)); lang != "" {
+		return lang
+	}
+	if lang := languageByName(path); lang != "" {
+		return lang
+	}
+	if len(head) > 0 {
+		if lang := shebangLanguage(head[0]); lang != "" {
+			return lang
+		}
+	}
+	return "text"
+}
+
+// NormalizeLanguage returns the context prompt name of language, as given
+// with /load --lang or in a modeline
+func NormalizeLanguage(language string) string {
+	language = strings.ToLower(strings.TrimSpace(language))
+	if alias, ok := languageAliases[language]; ok {
+		return alias
+	}
+	return language
+}
+
+// modelineLanguage returns the language named by a Vim modeline
+// ("vim: set ft=python:") or an Emacs one ("-*- mode: python -*-") in lines
+func modelineLanguage(lines []string) string {
+	for _, line := range lines {
+		if m := vimModeline.FindStringSubmatch(line); m != nil {
+			return NormalizeLanguage(m[1])
+		}
+		if m := emacsModeline.FindStringSubmatch(line); m != nil {
+			vars := strings.TrimSpace(m[1])
+			if !strings.Contains(vars, ":") {
+				return NormalizeLanguage(vars)
+			}
+			if mode := emacsMode.FindStringSubmatch(vars); mode != nil {
+				return NormalizeLanguage(mode[1])
+			}
+		}
+	}
+	return ""
+}
+
+// shebangLanguage returns the language of the interpreter a shebang line
+// runs, such as "#!/usr/bin/env python3", or "" for an unknown one
+func shebangLanguage(line string) string {
+	if !strings.HasPrefix(line, "#!") {
+		return ""
+	}
+	fields := strings.Fields(line[2:])
+	if len(fields) == 0 {
+		return ""
+	}
+	interpreter := filepath.Base(fields[0])
+	if interpreter == "env" {
+		// Skip env's options and variable assignments
+		interpreter = ""
+		for _, field := range fields[1:] {
+			if !strings.HasPrefix(field, "-") && !strings.Contains(field, "=") {
+				interpreter = filepath.Base(field)
+				break
+			}
+		}
+	}
+	// python3.12 is python
+	interpreter = strings.TrimRight(interpreter, "0123456789.")
+	if lang := NormalizeLanguage(interpreter); shebangLanguages[lang] {
+		return lang
+	}
+	return ""
+}
+
+// readEnds returns the first and last modelineLines lines of the file at
+// path, from up to size bytes at each end. Lines cut by the window are left
+// out.
+func readEnds(path string, size int64) ([]string, []string) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return nil, nil
+	}
+
+	buf := make([]byte, min(size, info.Size()))
+	n, _ := file.ReadAt(buf, 0)
+	head := strings.Split(string(buf[:n]), "\n")
+	if int64(n) < info.Size() {
+		head = head[:len(head)-1]
+	}
+	head = head[:min(len(head), modelineLines)]
+	if info.Size() <= size {
+		lines := strings.Split(strings.TrimRight(string(buf[:n]), "\n"), "\n")
+		return head, lines[max(len(lines)-modelineLines, 0):]
+	}
+
+	n, _ = file.ReadAt(buf, info.Size()-size)
+	tail := strings.Split(strings.TrimRight(string(buf[:n]), "\n"), "\n")[1:]
+	return head, tail[max(len(tail)-modelineLines, 0):]
+}
+
+// SetLanguage sets the language of the loaded files matching patterns,
+// overriding the detected one; reloads keep it. It returns how many files
+// it was set for.
+func (fc *FileContext) SetLanguage(patterns []string, language string) (int, error) {
+	paths, err := fc.Loader.ExpandPatterns(patterns)
+	if err != nil {
+		return 0, err
+	}
+	language = NormalizeLanguage(language)
+	if language == "" {
+		return 0, fmt.Errorf("no language given")
+	}
+
+	set := 0
+	for _, path := range paths {
+		if i := fc.indexOf(path); i >= 0 {
+			fc.Files[i].Language = language
+			fc.Files[i].LanguageSet = true
+			set++
+		}
+	}
+	if set > 0 {
+		fc.resetPromptCache()
+	}
+	return set, nil
+}
+
+// setLanguage returns the language set with SetLanguage for the loaded file
+// at path, or "" if there is none
+func (fc *FileContext) setLanguage(path string) string {
+	if i := fc.indexOf(path); i >= 0 && fc.Files[i].LanguageSet {
+		return fc.Files[i].Language
+	}
+	return ""
+}
@@ -0,0 +1,62 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package files
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDetectLanguage(t *testing.T) {
+	dir := t.TempDir()
+	long := strings.Repeat("filler line\n", 200)
+
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		want    string
+	}{
+		{"extension", "main.go", "package main\n", "go"},
+		{"file name", "Makefile", "all:\n", "makefile"},
+		{"no extension", "notes", "just text\n", "text"},
+		{"shebang", "deploy", "#!/bin/bash\necho hi\n", "bash"},
+		{"env shebang with version", "tool", "#!/usr/bin/env python3.12\nprint(1)\n", "python"},
+		{"env shebang with options", "run", "#!/usr/bin/env -S node --no-warnings\n", "javascript"},
+		{"unknown interpreter", "odd", "#!/usr/bin/frobnicate\n", "text"},
+		{"shebang does not override the extension", "script.rb", "#!/usr/bin/env python\n", "ruby"},
+		{"vim modeline", "config", "# vim: set ft=yaml:\nkey: value\n", "yaml"},
+		{"vim modeline at the end", "build", "#!/bin/sh\n" + long + "# vim: filetype=python\n", "python"},
+		{"vim modeline overrides the extension", "query.txt", "-- vi: ft=sql\nselect 1;\n", "sql"},
+		{"emacs modeline", "hook", "#!/bin/sh\n# -*- mode: ruby; coding: utf-8 -*-\n", "ruby"},
+		{"emacs short modeline", "rules", "# -*- perl -*-\n", "perl"},
+		{"emacs coding only", "data.json", "// -*- coding: utf-8 -*-\n{}\n", "json"},
+		{"modeline past the first lines", "late", "a\nb\nc\nd\ne\nf\n# vim: ft=lua\n" + long, "text"},
+	}
+
+	fl := NewFileLoader()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, tt.file)
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if got := fl.detectLanguage(path); got != tt.want {
+				t.Errorf("detectLanguage(%s) = %q, want %q", tt.file, got, tt.want)
+			}
+		})
+	}
+}
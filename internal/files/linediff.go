@@ -0,0 +1,118 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package files
+
+import "strings"
+
+// Line diff operations
+const (
+	LineEqual = iota
+	LineDeleted
+	LineInserted
+)
+
+// maxDiffCells bounds the LCS table; larger changed regions are reported as
+// replaced wholesale instead of line by line
+const maxDiffCells = 4_000_000
+
+// DiffLine is one line of a line-by-line comparison. OldNum and NewNum are
+// 1-based line numbers, 0 for the side the line is missing from.
+type DiffLine struct {
+	Op     int
+	OldNum int
+	NewNum int
+	Text   string
+}
+
+// DiffLines compares old and new line by line
+func DiffLines(old, new string) []DiffLine {
+	a, b := splitLines(old), splitLines(new)
+
+	// Common prefix and suffix need no table
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var diff []DiffLine
+	for i := 0; i < prefix; i++ {
+		diff = append(diff, DiffLine{Op: LineEqual, OldNum: i + 1, NewNum: i + 1, Text: a[i]})
+	}
+	diff = append(diff, diffMiddle(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix], prefix)...)
+	for i := 0; i < suffix; i++ {
+		oldIdx, newIdx := len(a)-suffix+i, len(b)-suffix+i
+		diff = append(diff, DiffLine{Op: LineEqual, OldNum: oldIdx + 1, NewNum: newIdx + 1, Text: a[oldIdx]})
+	}
+	return diff
+}
+
+// diffMiddle diffs the changed region between the common prefix and suffix,
+// where offset is the number of lines before it
+func diffMiddle(a, b []string, offset int) []DiffLine {
+	var diff []DiffLine
+	if len(a)*len(b) > maxDiffCells {
+		for i, line := range a {
+			diff = append(diff, DiffLine{Op: LineDeleted, OldNum: offset + i + 1, Text: line})
+		}
+		for i, line := range b {
+			diff = append(diff, DiffLine{Op: LineInserted, NewNum: offset + i + 1, Text: line})
+		}
+		return diff
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			diff = append(diff, DiffLine{Op: LineEqual, OldNum: offset + i + 1, NewNum: offset + j + 1, Text: a[i]})
+			i++
+			j++
+		case j == len(b) || (i < len(a) && lcs[i+1][j] >= lcs[i][j+1]):
+			diff = append(diff, DiffLine{Op: LineDeleted, OldNum: offset + i + 1, Text: a[i]})
+			i++
+		default:
+			diff = append(diff, DiffLine{Op: LineInserted, NewNum: offset + j + 1, Text: b[j]})
+			j++
+		}
+	}
+	return diff
+}
+
+// splitLines splits content into lines, ignoring a trailing newline
+func splitLines(content string) []string {
+	content = strings.TrimSuffix(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	if content == "" {
+		return nil
+	}
+	return strings.Split(content, "\n")
+}
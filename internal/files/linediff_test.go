@@ -0,0 +1,72 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package files
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiffLines(t *testing.T) {
+	old := "a\nb\nc\nd\n"
+	new := "a\nc\nx\nd\ne\n"
+
+	want := []DiffLine{
+		{Op: LineEqual, OldNum: 1, NewNum: 1, Text: "a"},
+		{Op: LineDeleted, OldNum: 2, Text: "b"},
+		{Op: LineEqual, OldNum: 3, NewNum: 2, Text: "c"},
+		{Op: LineInserted, NewNum: 3, Text: "x"},
+		{Op: LineEqual, OldNum: 4, NewNum: 4, Text: "d"},
+		{Op: LineInserted, NewNum: 5, Text: "e"},
+	}
+	got := DiffLines(old, new)
+	if len(got) != len(want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("line %d: got %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	for _, line := range DiffLines("same\r\nlines\r\n", "same\nlines") {
+		if line.Op != LineEqual {
+			t.Errorf("expected line endings to be ignored, got %+v", line)
+		}
+	}
+	if diff := DiffLines("", "new"); len(diff) != 1 || diff[0].Op != LineInserted {
+		t.Errorf("expected a single insertion, got %+v", diff)
+	}
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	var old, new []string
+	for i := 1; i <= 20; i++ {
+		old = append(old, string(rune('a'+i-1)))
+	}
+	new = append(new, old...)
+	new[1] = "B"
+	new = append(new[:15], append([]string{"x"}, new[15:]...)...)
+
+	got := UnifiedDiff("f.txt", strings.Join(old, "\n")+"\n", strings.Join(new, "\n")+"\n")
+	want := "--- a/f.txt\n+++ b/f.txt\n" +
+		"@@ -1,5 +1,5 @@\n a\n-b\n+B\n c\n d\n e\n" +
+		"@@ -13,6 +13,7 @@\n m\n n\n o\n+x\n p\n q\n r\n"
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+	if diff := UnifiedDiff("f.txt", "same\n", "same\n"); diff != "" {
+		t.Errorf("expected no diff for equal content, got %q", diff)
+	}
+}
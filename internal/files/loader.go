@@ -14,17 +14,36 @@
 package files
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"runtime"
+	"sort"
 	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultPreviewThreshold = 256 * 1024       // Files larger than this are loaded as a preview
+	DefaultPreviewSize      = 32 * 1024        // Bytes kept in memory for a preview
+	DefaultMaxFileSize      = 10 * 1024 * 1024 // Files larger than this are not loaded
+	DefaultMaxFiles         = 50               // Files one load may match, the same as DefaultMaxContext
+	readChunkSize           = 64 * 1024
 )
 
 type FileLoader struct {
-	MaxFileSize     int64
-	MaxFiles        int
+	MaxFileSize      int64 // Largest file loaded, in bytes (max_file_size)
+	MaxFiles         int   // Most files one load may match (max_files_per_load)
+	PreviewThreshold int64 // Files larger than this keep only a preview in memory (0 disables)
+	PreviewSize      int64 // Size of the preview window for large files
+	ExtractText      bool  // Load the text of PDFs and images through Extractors
 	gitignoreFilter *GitignoreFilter
+	ignorePatterns  []string
 }
 
 func NewFileLoader() *FileLoader {
@@ -33,22 +52,152 @@ func NewFileLoader() *FileLoader {
 
 // NewFileLoaderWithOptions creates a FileLoader with gitignore options
 func NewFileLoaderWithOptions(respectGitignore bool) *FileLoader {
+	return NewFileLoaderWithPatterns(respectGitignore, nil)
+}
+
+// NewFileLoaderWithPatterns creates a FileLoader with gitignore options and extra ignore patterns
+func NewFileLoaderWithPatterns(respectGitignore bool, ignorePatterns []string) *FileLoader {
 	return &FileLoader{
-		MaxFileSize:     10 * 1024 * 1024, // 10MB default
-		MaxFiles:        100,
-		gitignoreFilter: NewGitignoreFilter(respectGitignore),
+		MaxFileSize:      DefaultMaxFileSize,
+		MaxFiles:         DefaultMaxFiles,
+		PreviewThreshold: DefaultPreviewThreshold,
+		PreviewSize:      DefaultPreviewSize,
+		gitignoreFilter:  NewGitignoreFilterWithPatterns(respectGitignore, ignorePatterns),
+		ignorePatterns:   ignorePatterns,
 	}
 }
 
+// IgnorePatterns returns the extra ignore patterns configured for this loader
+func (fl *FileLoader) IgnorePatterns() []string {
+	return fl.ignorePatterns
+}
+
+// IsExcluded returns true if the path is excluded by .deecliignore or config patterns
+func (fl *FileLoader) IsExcluded(path string) bool {
+	return fl.gitignoreFilter.IsExcluded(path)
+}
+
 type LoadedFile struct {
 	Path     string
 	RelPath  string
 	Content  string
 	Size     int64
 	Language string
+	Hash     string    // SHA-256 of the full file content, hex-encoded
+	Lazy     bool      // Content is not held in memory; read it with FileContext.Content
+	Preview  bool      // Content holds only the first chunk of a large file; read_more fetches the rest
+	ModTime  time.Time // Modification time when loaded
+	ExtractedBy string // Command that extracted Content from a binary document, if any
+	LanguageSet bool   // Language was given with /load --lang rather than detected; reloads keep it
+	RealPath    string // Path with symbolic links resolved; every path a file is loaded through has the same one
+	Target      string // What Path points to when it is a symbolic link, for display
+}
+
+// canonicalPath returns the path that identifies the file whatever path it
+// was loaded through
+func (f LoadedFile) canonicalPath() string {
+	if f.RealPath != "" {
+		return f.RealPath
+	}
+	return f.Path
+}
+
+// realPath returns path with symbolic links resolved, or path itself when
+// that fails
+func realPath(path string) string {
+	if real, err := filepath.EvalSymlinks(path); err == nil {
+		return real
+	}
+	return path
+}
+
+// resolveLinks sets RealPath of file and, when its path is a symbolic link,
+// Target
+func resolveLinks(file LoadedFile) LoadedFile {
+	file.RealPath = realPath(file.Path)
+	if info, err := os.Lstat(file.Path); err == nil && info.Mode()&os.ModeSymlink != 0 {
+		file.Target = displayPath(file.RealPath)
+	}
+	return file
+}
+
+// ContentHash returns the hex-encoded SHA-256 of content, as stored in LoadedFile.Hash
+func ContentHash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// SameContent reports whether two loads of a file have identical content,
+// comparing sizes and hashes before falling back to the full content.
+// Lazy stubs carry no content, so they are compared by modification time.
+func SameContent(a, b LoadedFile) bool {
+	if a.Size != b.Size {
+		return false
+	}
+	if a.Lazy || b.Lazy {
+		return a.ModTime.Equal(b.ModTime)
+	}
+	if a.Hash != "" && b.Hash != "" {
+		return a.Hash == b.Hash
+	}
+	return a.Content == b.Content
 }
 
 func (fl *FileLoader) LoadFiles(patterns []string) ([]LoadedFile, error) {
+	paths, err := fl.ExpandPatterns(patterns)
+	if err != nil {
+		return nil, err
+	}
+
+	return loadAll(paths, fl.loadSingleFile)
+}
+
+// loadWorkers returns how many goroutines to use for n independent file operations.
+// Loading is mostly I/O bound, so use a few more workers than CPUs.
+func loadWorkers(n int) int {
+	return min(n, max(4, runtime.NumCPU()*2))
+}
+
+// forEachParallel calls fn(i) for every i in [0, n) on a bounded worker pool
+func forEachParallel(n int, fn func(i int)) {
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < loadWorkers(n); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				fn(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+}
+
+// loadAll loads paths concurrently. Files are returned in the order of paths, and
+// when several fail the error for the first one in that order is reported, so the
+// outcome is the same as loading them one by one.
+func loadAll(paths []string, load func(string) (LoadedFile, error)) ([]LoadedFile, error) {
+	files := make([]LoadedFile, len(paths))
+	errs := make([]error, len(paths))
+	forEachParallel(len(paths), func(i int) {
+		files[i], errs[i] = load(paths[i])
+	})
+
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("error loading %s: %w", paths[i], err)
+		}
+	}
+	return files, nil
+}
+
+// ExpandPatterns returns the absolute paths matched by patterns, enforcing MaxFiles
+func (fl *FileLoader) ExpandPatterns(patterns []string) ([]string, error) {
 	// Validate patterns first
 	for _, pattern := range patterns {
 		if err := fl.validatePattern(pattern); err != nil {
@@ -56,38 +205,70 @@ func (fl *FileLoader) LoadFiles(patterns []string) ([]LoadedFile, error) {
 		}
 	}
 
-	// First, expand all patterns and collect unique paths
-	allPaths := make(map[string]bool)
-	for _, pattern := range patterns {
-		matches, err := fl.expandPattern(pattern)
-		if err != nil {
-			return nil, fmt.Errorf("error expanding pattern %s: %w", pattern, err)
+	// First, expand all patterns in parallel and collect unique paths
+	expanded := make([][]string, len(patterns))
+	errs := make([]error, len(patterns))
+	forEachParallel(len(patterns), func(i int) {
+		expanded[i], errs[i] = fl.expandPattern(patterns[i])
+	})
+
+	// Keyed by the real path, so a file and a symbolic link to it are loaded once
+	allPaths := make(map[string]string)
+	for i, pattern := range patterns {
+		if errs[i] != nil {
+			return nil, fmt.Errorf("error expanding pattern %s: %w", pattern, errs[i])
 		}
-		for _, path := range matches {
+		for _, path := range expanded[i] {
 			absPath, err := filepath.Abs(path)
 			if err != nil {
 				continue
 			}
-			allPaths[absPath] = true
+			if real := realPath(absPath); allPaths[real] == "" {
+				allPaths[real] = absPath
+			}
 		}
 	}
 
 	// Check if we would exceed the file limit
 	if len(allPaths) > fl.MaxFiles {
-		return nil, fmt.Errorf("pattern matches %d files, exceeds maximum limit of %d. Use more specific patterns like '*.go' instead of '*'", len(allPaths), fl.MaxFiles)
+		return nil, fmt.Errorf("pattern matches %d files, exceeds maximum limit of %d (max_files_per_load). Use more specific patterns like '*.go' instead of '*'", len(allPaths), fl.MaxFiles)
 	}
 
-	// Now load all the files
-	var files []LoadedFile
-	for absPath := range allPaths {
-		file, err := fl.loadSingleFile(absPath)
-		if err != nil {
-			return nil, fmt.Errorf("error loading %s: %w", absPath, err)
-		}
-		files = append(files, file)
+	paths := make([]string, 0, len(allPaths))
+	for _, absPath := range allPaths {
+		paths = append(paths, absPath)
 	}
+	sort.Strings(paths)
+	return paths, nil
+}
 
-	return files, nil
+// LoadStub returns a lazy LoadedFile for path: it is validated like LoadFile
+// but its content is left on disk until needed
+func (fl *FileLoader) LoadStub(path string) (LoadedFile, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return LoadedFile{}, fmt.Errorf("error resolving path: %w", err)
+	}
+
+	// Extracted text cannot be read back from disk, so keep it in memory
+	if extractor := fl.textExtractor(absPath); extractor != nil {
+		return fl.loadExtracted(absPath, extractor)
+	}
+
+	info, err := fl.checkFile(absPath)
+	if err != nil {
+		return LoadedFile{}, err
+	}
+
+	return LoadedFile{
+		Path:     absPath,
+		RelPath:  displayPath(absPath),
+		Size:     info.Size(),
+		Language: fl.detectLanguage(absPath),
+		Lazy:     true,
+		Preview:  fl.isPreview(info.Size()),
+		ModTime:  info.ModTime(),
+	}, nil
 }
 
 func (fl *FileLoader) LoadFile(path string) (LoadedFile, error) {
@@ -100,43 +281,135 @@ func (fl *FileLoader) LoadFile(path string) (LoadedFile, error) {
 }
 
 func (fl *FileLoader) loadSingleFile(absPath string) (LoadedFile, error) {
-	info, err := os.Stat(absPath)
-	if err != nil {
-		relPath, _ := filepath.Rel(".", absPath)
-		return LoadedFile{}, fmt.Errorf("file not found: %s. Try: /load *.go or /list to see available files", relPath)
+	if extractor := fl.textExtractor(absPath); extractor != nil {
+		return fl.loadExtracted(absPath, extractor)
 	}
 
-	if info.IsDir() {
-		relPath, _ := filepath.Rel(".", absPath)
-		return LoadedFile{}, fmt.Errorf("'%s' is a directory, not a file. Try: /load %s/* or /load %s/**/*", relPath, relPath, relPath)
+	info, err := fl.checkFile(absPath)
+	if err != nil {
+		return LoadedFile{}, err
 	}
 
-	if info.Size() > fl.MaxFileSize {
-		relPath, _ := filepath.Rel(".", absPath)
-		sizeMB := float64(info.Size()) / (1024 * 1024)
-		maxMB := float64(fl.MaxFileSize) / (1024 * 1024)
-		return LoadedFile{}, fmt.Errorf("file too large: %s (%.1fMB, max: %.0fMB). Use a text editor to view large files", relPath, sizeMB, maxMB)
+	content, hash, err := fl.readContent(absPath, info.Size())
+	if err != nil {
+		return LoadedFile{}, err
 	}
 
-	if fl.isBinaryFile(absPath) {
-		relPath, _ := filepath.Rel(".", absPath)
-		return LoadedFile{}, fmt.Errorf("'%s' appears to be a binary file, skipping. Use /load <text_files> instead", relPath)
+	return LoadedFile{
+		Path:     absPath,
+		RelPath:  displayPath(absPath),
+		Content:  content,
+		Size:     info.Size(),
+		Language: fl.detectLanguage(absPath),
+		Hash:     hash,
+		Preview:  fl.isPreview(info.Size()),
+		ModTime:  info.ModTime(),
+	}, nil
+}
+
+// ReadContent reads the content of path as it would be loaded, returning only
+// the preview window for files past PreviewThreshold
+func (fl *FileLoader) ReadContent(path string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("error reading file: %w", err)
 	}
+	content, _, err := fl.readContent(path, info.Size())
+	return content, err
+}
+
+// isPreview reports whether a file of the given size is loaded as a preview
+func (fl *FileLoader) isPreview(size int64) bool {
+	return fl.PreviewThreshold > 0 && fl.PreviewSize > 0 && size > fl.PreviewThreshold
+}
 
+// readContent reads absPath in chunks. Large files keep only the preview
+// window, cut back to the last full line, while the hash still covers the
+// whole file so reload detection sees changes past the preview.
+func (fl *FileLoader) readContent(absPath string, size int64) (string, string, error) {
 	file, err := os.Open(absPath)
 	if err != nil {
-		return LoadedFile{}, fmt.Errorf("error opening file: %w", err)
+		return "", "", fmt.Errorf("error opening file: %w", err)
 	}
 	defer file.Close()
 
-	content, err := io.ReadAll(file)
+	limit := size
+	preview := fl.isPreview(size)
+	if preview {
+		limit = fl.PreviewSize
+	}
+
+	hasher := sha256.New()
+	var kept bytes.Buffer
+	buf := make([]byte, readChunkSize)
+	for {
+		n, err := file.Read(buf)
+		if n > 0 {
+			hasher.Write(buf[:n])
+			if remaining := limit - int64(kept.Len()); remaining > 0 {
+				kept.Write(buf[:min(int64(n), remaining)])
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", "", fmt.Errorf("error reading file: %w", err)
+		}
+	}
+
+	content := kept.Bytes()
+	if preview {
+		if idx := bytes.LastIndexByte(content, '\n'); idx >= 0 {
+			content = content[:idx+1]
+		}
+	}
+	return string(content), hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// checkFile verifies that absPath is a regular text file within the size limit
+func (fl *FileLoader) checkFile(absPath string) (os.FileInfo, error) {
+	info, err := fl.statFile(absPath)
 	if err != nil {
-		return LoadedFile{}, fmt.Errorf("error reading file: %w", err)
+		return nil, err
 	}
 
+	if fl.isBinaryFile(absPath) {
+		return nil, fl.binaryFileError(displayPath(absPath))
+	}
+
+	return info, nil
+}
+
+// statFile verifies that absPath is a regular file within the size limit
+func (fl *FileLoader) statFile(absPath string) (os.FileInfo, error) {
+	info, err := os.Stat(absPath)
+	if err != nil {
+		relPath, _ := filepath.Rel(".", absPath)
+		return nil, fmt.Errorf("file not found: %s. Try: /load *.go or /list to see available files", relPath)
+	}
+
+	if info.IsDir() {
+		relPath, _ := filepath.Rel(".", absPath)
+		return nil, fmt.Errorf("'%s' is a directory, not a file. Try: /load %s/* or /load %s/**/*", relPath, relPath, relPath)
+	}
+
+	if info.Size() > fl.MaxFileSize {
+		relPath, _ := filepath.Rel(".", absPath)
+		sizeMB := float64(info.Size()) / (1024 * 1024)
+		maxMB := float64(fl.MaxFileSize) / (1024 * 1024)
+		return nil, fmt.Errorf("file too large: %s (%.1fMB, max: %.1fMB set by max_file_size). Use a text editor to view large files", relPath, sizeMB, maxMB)
+	}
+
+	return info, nil
+}
+
+// displayPath returns absPath relative to the working directory when the file is inside it
+func displayPath(absPath string) string {
 	// Calculate relative path from current working directory
 	cwd, cwdErr := os.Getwd()
 	var relPath string
+	var err error
 
 	if cwdErr == nil {
 		relPath, err = filepath.Rel(cwd, absPath)
@@ -152,14 +425,7 @@ func (fl *FileLoader) loadSingleFile(absPath string) (LoadedFile, error) {
 			relPath = absPath
 		}
 	}
-
-	return LoadedFile{
-		Path:     absPath,
-		RelPath:  relPath,
-		Content:  string(content),
-		Size:     info.Size(),
-		Language: fl.detectLanguage(absPath),
-	}, nil
+	return relPath
 }
 
 func (fl *FileLoader) expandPattern(pattern string) ([]string, error) {
@@ -266,7 +532,9 @@ func (fl *FileLoader) isBinaryFile(path string) bool {
 	return false
 }
 
-func (fl *FileLoader) detectLanguage(path string) string {
+// languageByName returns the language of path by its extension or file
+// name, or "" if neither tells
+func languageByName(path string) string {
 	ext := strings.ToLower(filepath.Ext(path))
 	
 	languageMap := map[string]string{
@@ -354,7 +622,7 @@ func (fl *FileLoader) detectLanguage(path string) string {
 		return "dockerfile"
 	}
 
-	return "text"
+	return ""
 }
 
 func (fl *FileLoader) GetFilesInfo(files []LoadedFile) string {
@@ -381,6 +649,9 @@ func (fl *FileLoader) GetFilesInfo(files []LoadedFile) string {
 		
 		// Enhanced file info with icon and better formatting
 		info.WriteString(fmt.Sprintf("  %s %s\n", icon, f.RelPath))
+		if f.Target != "" {
+			info.WriteString(fmt.Sprintf("    → %s\n", f.Target))
+		}
 		info.WriteString(fmt.Sprintf("    %s • %s\n", f.Language, sizeStr))
 		
 		if i < len(files)-1 {
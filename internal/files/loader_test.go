@@ -14,6 +14,7 @@
 package files
 
 import (
+	"fmt"
 	"os"
 	"strings"
 	"testing"
@@ -187,4 +188,325 @@ func TestPatternValidation(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+
+func TestLoadFileHash(t *testing.T) {
+	path := t.TempDir() + "/main.go"
+	if err := os.WriteFile(path, []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	loader := NewFileLoader()
+	first, err := loader.LoadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Hash != ContentHash([]byte("package main\n")) {
+		t.Errorf("unexpected hash %q", first.Hash)
+	}
+
+	// Same size, different content
+	if err := os.WriteFile(path, []byte("package util\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	second, err := loader.LoadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if SameContent(first, second) {
+		t.Error("files with different content should not be the same")
+	}
+	if !SameContent(first, first) {
+		t.Error("a file should have the same content as itself")
+	}
+}
+
+func TestFileContextChangedSince(t *testing.T) {
+	dir := t.TempDir()
+	a, b := dir+"/a.go", dir+"/b.go"
+	for _, path := range []string{a, b} {
+		if err := os.WriteFile(path, []byte("package a\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	fc := NewFileContext()
+	if err := fc.LoadFile(a); err != nil {
+		t.Fatal(err)
+	}
+	snapshot := fc.Checksums()
+	if changed := fc.ChangedSince(snapshot); len(changed) != 0 {
+		t.Errorf("expected no changes, got %d", len(changed))
+	}
+
+	if err := os.WriteFile(a, []byte("package b\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fc.ReloadFiles(nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := fc.LoadFile(b); err != nil {
+		t.Fatal(err)
+	}
+
+	changed := fc.ChangedSince(snapshot)
+	if len(changed) != 2 {
+		t.Fatalf("expected the edited and the new file, got %d", len(changed))
+	}
+}
+
+func TestFileContextLazyLoading(t *testing.T) {
+	t.Chdir(t.TempDir())
+	for _, name := range []string{"a.go", "b.go", "c.go"} {
+		if err := os.WriteFile(name, []byte("package "+strings.TrimSuffix(name, ".go")+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	fc := NewFileContext()
+	fc.LazyThreshold = 2
+	if err := fc.LoadFiles([]string{"*.go"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(fc.Files) != 3 {
+		t.Fatalf("expected 3 files, got %d", len(fc.Files))
+	}
+	for _, f := range fc.Files {
+		if !f.Lazy || f.Content != "" {
+			t.Errorf("%s should be a lazy stub", f.RelPath)
+		}
+		if f.Size == 0 || f.Language != "go" {
+			t.Errorf("%s stub should keep size and language, got %d %q", f.RelPath, f.Size, f.Language)
+		}
+	}
+
+	content, err := fc.Content(fc.Files[0])
+	if err != nil || content != "package a\n" {
+		t.Errorf("Content() = %q, %v", content, err)
+	}
+	prompt := fc.BuildContextPrompt()
+	if !strings.Contains(prompt, "package b") || !strings.Contains(prompt, "package c") {
+		t.Errorf("prompt should include stub content read from disk:\n%s", prompt)
+	}
+	if fc.GetFormattedContextSize() == 0 {
+		t.Error("formatted size should be estimated for lazy files")
+	}
+	tokens := fc.GetFileTokens()
+	sum := 0
+	for _, n := range tokens {
+		sum += n
+	}
+	if len(tokens) != 3 || tokens[0] == 0 || sum > fc.GetEstimatedTokens() {
+		t.Errorf("GetFileTokens() = %v, want a share of %d tokens per file", tokens, fc.GetEstimatedTokens())
+	}
+
+	// Reloading keeps stubs lazy and notices changes
+	if err := os.WriteFile("a.go", []byte("package aa\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	results, err := fc.ReloadFiles([]string{"a.go"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].Status != "changed" {
+		t.Errorf("expected a changed result, got %+v", results)
+	}
+	if !fc.Files[0].Lazy {
+		t.Error("reloaded stub should stay lazy")
+	}
+}
+
+func TestLoadFilePreview(t *testing.T) {
+	path := t.TempDir() + "/big.log"
+	data := []byte(strings.Repeat("0123456789abcdef\n", 100)) // 1700 bytes
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	loader := NewFileLoader()
+	loader.PreviewThreshold = 1024
+	loader.PreviewSize = 100
+
+	file, err := loader.LoadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !file.Preview {
+		t.Fatal("expected a preview for a file past the threshold")
+	}
+	// Cut back to the last full line within the window
+	if file.Content != strings.Repeat("0123456789abcdef\n", 5) {
+		t.Errorf("unexpected preview content %q", file.Content)
+	}
+	if file.Size != int64(len(data)) {
+		t.Errorf("expected size %d, got %d", len(data), file.Size)
+	}
+	if file.Hash != ContentHash(data) {
+		t.Error("hash should cover the whole file, not just the preview")
+	}
+
+	fc := NewFileContext()
+	fc.Loader = loader
+	fc.Files = []LoadedFile{file}
+	prompt := fc.BuildContextPrompt()
+	if !strings.Contains(prompt, "call read_more") || !strings.Contains(prompt, "offset 85") {
+		t.Errorf("expected a read_more note in the prompt, got:\n%s", prompt)
+	}
+
+	loader.PreviewThreshold = 0
+	full, err := loader.LoadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if full.Preview || full.Content != string(data) {
+		t.Error("expected full content with previews disabled")
+	}
+}
+
+func TestLoadFilesConcurrentOrder(t *testing.T) {
+	t.Chdir(t.TempDir())
+	var patterns []string
+	for i := 0; i < 40; i++ {
+		name := fmt.Sprintf("file%02d.go", i)
+		if err := os.WriteFile(name, []byte(fmt.Sprintf("package p%d\n", i)), 0644); err != nil {
+			t.Fatal(err)
+		}
+		patterns = append(patterns, name)
+	}
+
+	loader := NewFileLoader()
+	files, err := loader.LoadFiles([]string{"*.go"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 40 {
+		t.Fatalf("expected 40 files, got %d", len(files))
+	}
+	for i, f := range files {
+		if f.Content != fmt.Sprintf("package p%d\n", i) {
+			t.Errorf("file %d out of order: %q", i, f.Content)
+		}
+	}
+
+	// The reported error is for the first failing path, whatever finishes first
+	for _, name := range []string{"file05.go", "file30.go"} {
+		if err := os.WriteFile(name, []byte{0, 1, 2}, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_, err = loader.LoadFiles(patterns)
+	if err == nil || !strings.Contains(err.Error(), "file05.go") {
+		t.Errorf("expected error for file05.go, got %v", err)
+	}
+}
+
+func TestBuildContextPromptCache(t *testing.T) {
+	path := t.TempDir() + "/main.go"
+	if err := os.WriteFile(path, []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	fc := NewFileContext()
+	if err := fc.LoadFile(path); err != nil {
+		t.Fatal(err)
+	}
+	first := fc.BuildContextPrompt()
+	if !strings.Contains(first, "package main") {
+		t.Fatalf("expected file content in prompt, got:\n%s", first)
+	}
+
+	// Same fingerprint: the cached block is reused instead of re-cleaning Content
+	fc.Files[0].Content = "package other\n"
+	if fc.BuildContextPrompt() != first {
+		t.Error("expected the cached prompt block for an unchanged hash")
+	}
+	if len(fc.promptCache) != 1 {
+		t.Errorf("expected one cached block, got %d", len(fc.promptCache))
+	}
+
+	// A reload with new content invalidates the cache
+	if err := os.WriteFile(path, []byte("package util\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fc.ReloadFiles(nil); err != nil {
+		t.Fatal(err)
+	}
+	if prompt := fc.BuildContextPrompt(); !strings.Contains(prompt, "package util") {
+		t.Errorf("expected reloaded content in prompt, got:\n%s", prompt)
+	}
+}
+
+// benchmarkContext returns a context of n generated Go files of about size bytes each
+func benchmarkContext(n, size int) *FileContext {
+	fc := NewFileContext()
+	line := "\tfmt.Println(\"some moderately long line of generated code\")\n"
+	content := "package bench\n\n" + strings.Repeat(line, size/len(line))
+	for i := 0; i < n; i++ {
+		fc.Files = append(fc.Files, LoadedFile{
+			Path:     fmt.Sprintf("/bench/file%d.go", i),
+			RelPath:  fmt.Sprintf("file%d.go", i),
+			Content:  content,
+			Size:     int64(len(content)),
+			Language: "go",
+			Hash:     ContentHash([]byte(fmt.Sprintf("%d%s", i, content))),
+		})
+	}
+	return fc
+}
+
+func BenchmarkBuildContextPromptWithLimit(b *testing.B) {
+	fc := benchmarkContext(50, 8*1024)
+
+	b.Run("cached", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			fc.BuildContextPromptWithLimit(100000)
+		}
+	})
+
+	b.Run("uncached", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			fc.resetPromptCache()
+			fc.BuildContextPromptWithLimit(100000)
+		}
+	})
+}
+
+func TestLoadFilesSymlink(t *testing.T) {
+	t.Chdir(t.TempDir())
+	if err := os.MkdirAll("src", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile("src/main.go", []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("src/main.go", "link.go"); err != nil {
+		t.Skipf("symbolic links not supported: %v", err)
+	}
+
+	fc := NewFileContext()
+	if err := fc.LoadFiles([]string{"link.go"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(fc.Files) != 1 || fc.Files[0].Target != "src/main.go" {
+		t.Fatalf("expected link.go pointing to src/main.go, got %+v", fc.Files)
+	}
+	if !strings.Contains(fc.GetInfo(), "→ src/main.go") {
+		t.Errorf("expected /list to show the link target:\n%s", fc.GetInfo())
+	}
+
+	// The real path, and both in one load, are the same file
+	if err := fc.LoadFile("src/main.go"); err != nil {
+		t.Fatal(err)
+	}
+	if err := fc.LoadFiles([]string{"src/main.go", "*.go"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(fc.Files) != 1 {
+		t.Fatalf("expected one entry for the file, got %d", len(fc.Files))
+	}
+	if fc.Files[0].Target != "" {
+		t.Errorf("expected no target when loaded through the real path, got %q", fc.Files[0].Target)
+	}
+}
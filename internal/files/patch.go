@@ -0,0 +1,191 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package files
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var hunkHeader = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// hunk is one "@@" section of a unified diff
+type hunk struct {
+	oldStart int
+	oldLines []string // context and removed lines, in order
+	newLines []string // context and added lines, in order
+}
+
+// ApplyPatch applies a single-file unified diff to content and returns the result.
+// Hunks are matched on their context, so small line offsets are tolerated.
+func ApplyPatch(content, patch string) (string, error) {
+	hunks, err := parseHunks(patch)
+	if err != nil {
+		return "", err
+	}
+	if len(hunks) == 0 {
+		return "", fmt.Errorf("patch contains no hunks")
+	}
+	return applyHunks(content, hunks)
+}
+
+// PatchHunks returns the text of each hunk of a single-file unified diff,
+// starting with its "@@" header, for choosing which ones to apply
+func PatchHunks(patch string) []string {
+	_, hunks := splitHunks(strings.TrimSuffix(strings.ReplaceAll(patch, "\r\n", "\n"), "\n"))
+	return hunks
+}
+
+// ApplySelectedHunks applies the hunks of patch for which accept is true,
+// leaving the code of the others as it is. Hunks are numbered as in PatchHunks.
+func ApplySelectedHunks(content, patch string, accept []bool) (string, error) {
+	hunks, err := parseHunks(patch)
+	if err != nil {
+		return "", err
+	}
+	if len(accept) != len(hunks) {
+		return "", fmt.Errorf("patch has %d hunks, %d were chosen", len(hunks), len(accept))
+	}
+	var selected []hunk
+	for i, h := range hunks {
+		if accept[i] {
+			selected = append(selected, h)
+		}
+	}
+	return applyHunks(content, selected)
+}
+
+// IsPatchApplied reports whether content already contains the result of the patch,
+// i.e. the patch can be reversed against it
+func IsPatchApplied(content, patch string) bool {
+	hunks, err := parseHunks(patch)
+	if err != nil || len(hunks) == 0 {
+		return false
+	}
+	reversed := make([]hunk, len(hunks))
+	for i, h := range hunks {
+		reversed[i] = hunk{oldStart: h.oldStart, oldLines: h.newLines, newLines: h.oldLines}
+	}
+	_, err = applyHunks(content, reversed)
+	return err == nil
+}
+
+// applyHunks replaces each hunk's old lines with its new lines, in order
+func applyHunks(content string, hunks []hunk) (string, error) {
+	trailingNewline := content == "" || strings.HasSuffix(content, "\n")
+	var lines []string
+	if content != "" {
+		lines = strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+	}
+
+	var result []string
+	cursor := 0
+	for i, h := range hunks {
+		pos := findHunk(lines, h, cursor)
+		if pos < 0 {
+			return "", fmt.Errorf("hunk %d (line %d) does not match the file", i+1, h.oldStart)
+		}
+		result = append(result, lines[cursor:pos]...)
+		result = append(result, h.newLines...)
+		cursor = pos + len(h.oldLines)
+	}
+	result = append(result, lines[cursor:]...)
+
+	out := strings.Join(result, "\n")
+	if trailingNewline && len(result) > 0 {
+		out += "\n"
+	}
+	return out, nil
+}
+
+// parseHunks extracts the hunks of a unified diff, ignoring file headers
+func parseHunks(patch string) ([]hunk, error) {
+	var hunks []hunk
+	var current *hunk
+
+	patch = strings.TrimSuffix(strings.ReplaceAll(patch, "\r\n", "\n"), "\n")
+	for _, line := range strings.Split(patch, "\n") {
+		if match := hunkHeader.FindStringSubmatch(line); match != nil {
+			start, _ := strconv.Atoi(match[1])
+			hunks = append(hunks, hunk{oldStart: start})
+			current = &hunks[len(hunks)-1]
+			continue
+		}
+		if current == nil {
+			continue // diff --git, ---, +++ and other headers
+		}
+		switch {
+		case strings.HasPrefix(line, "+"):
+			current.newLines = append(current.newLines, line[1:])
+		case strings.HasPrefix(line, "-"):
+			current.oldLines = append(current.oldLines, line[1:])
+		case strings.HasPrefix(line, " "):
+			current.oldLines = append(current.oldLines, line[1:])
+			current.newLines = append(current.newLines, line[1:])
+		case line == "":
+			// Some tools strip the leading space from empty context lines
+			current.oldLines = append(current.oldLines, "")
+			current.newLines = append(current.newLines, "")
+		case strings.HasPrefix(line, `\`):
+			// "\ No newline at end of file"
+		default:
+			return nil, fmt.Errorf("unexpected line in patch: %q", line)
+		}
+	}
+
+	return hunks, nil
+}
+
+// findHunk returns where the hunk's old lines start in lines, searching outward
+// from the position given in the hunk header but never before cursor
+func findHunk(lines []string, h hunk, cursor int) int {
+	expected := h.oldStart - 1
+	if len(h.oldLines) == 0 {
+		// Pure insertion: trust the header
+		if h.oldStart == 0 {
+			expected = 0
+		} else {
+			expected = h.oldStart
+		}
+		if expected < cursor || expected > len(lines) {
+			return -1
+		}
+		return expected
+	}
+
+	last := len(lines) - len(h.oldLines)
+	for offset := 0; ; offset++ {
+		before, after := expected-offset, expected+offset
+		if before < cursor && after > last {
+			return -1
+		}
+		if after >= cursor && after <= last && matchesAt(lines, h.oldLines, after) {
+			return after
+		}
+		if offset > 0 && before >= cursor && before <= last && matchesAt(lines, h.oldLines, before) {
+			return before
+		}
+	}
+}
+
+func matchesAt(lines, want []string, pos int) bool {
+	for i, line := range want {
+		if lines[pos+i] != line {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,143 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package files
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestApplyPatch(t *testing.T) {
+	original := "package main\n\nimport \"fmt\"\n\nfunc main() {\n\tfmt.Println(\"hello\")\n}\n"
+
+	tests := []struct {
+		name    string
+		content string
+		patch   string
+		want    string
+		wantErr string
+	}{
+		{
+			name:    "replace a line",
+			content: original,
+			patch: `--- a/main.go
++++ b/main.go
+@@ -5,3 +5,3 @@
+ func main() {
+-	fmt.Println("hello")
++	fmt.Println("hello, world")
+ }
+`,
+			want: strings.Replace(original, `"hello"`, `"hello, world"`, 1),
+		},
+		{
+			name:    "tolerates a line offset",
+			content: "// header\n" + original,
+			patch: `@@ -5,3 +5,4 @@
+ func main() {
+ 	fmt.Println("hello")
++	fmt.Println("bye")
+ }
+`,
+			want: "// header\n" + strings.Replace(original, "\"hello\")\n", "\"hello\")\n\tfmt.Println(\"bye\")\n", 1),
+		},
+		{
+			name:    "multiple hunks",
+			content: "a\nb\nc\nd\ne\nf\ng\n",
+			patch: `@@ -1,2 +1,2 @@
+-a
++A
+ b
+@@ -6,2 +6,2 @@
+ f
+-g
++G
+`,
+			want: "A\nb\nc\nd\ne\nf\nG\n",
+		},
+		{
+			name:    "new file",
+			content: "",
+			patch: `--- /dev/null
++++ b/new.txt
+@@ -0,0 +1,2 @@
++first
++second
+`,
+			want: "first\nsecond\n",
+		},
+		{
+			name:    "context mismatch",
+			content: original,
+			patch: `@@ -5,3 +5,3 @@
+ func other() {
+-	fmt.Println("hello")
++	fmt.Println("bye")
+ }
+`,
+			wantErr: "does not match",
+		},
+		{
+			name:    "no hunks",
+			content: original,
+			patch:   "just some text",
+			wantErr: "no hunks",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ApplyPatch(tt.content, tt.patch)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ApplyPatch() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ApplyPatch() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ApplyPatch() =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplySelectedHunks(t *testing.T) {
+	content := "a\nb\nc\nd\ne\nf\ng\n"
+	patch := `--- a/letters.txt
++++ b/letters.txt
+@@ -1,2 +1,2 @@
+-a
++A
+ b
+@@ -6,2 +6,2 @@
+ f
+-g
++G
+`
+	hunks := PatchHunks(patch)
+	if len(hunks) != 2 || !strings.HasPrefix(hunks[1], "@@ -6,2 +6,2 @@") {
+		t.Fatalf("PatchHunks() = %q", hunks)
+	}
+
+	got, err := ApplySelectedHunks(content, patch, []bool{false, true})
+	if err != nil || got != "a\nb\nc\nd\ne\nf\nG\n" {
+		t.Errorf("ApplySelectedHunks() = %q, %v; want only the second hunk applied", got, err)
+	}
+	if _, err := ApplySelectedHunks(content, patch, []bool{true}); err == nil {
+		t.Error("expected an error when the choices do not match the hunks")
+	}
+}
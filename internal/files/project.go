@@ -0,0 +1,145 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package files
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ProjectSummaryPath is where /init stores the generated project map
+const ProjectSummaryPath = ".deecli/PROJECT.md"
+
+const (
+	maxOverviewEntries = 400      // Max paths listed in the tree
+	maxOverviewDepth   = 4        // Max directory depth walked
+	maxKeyFileSize     = 4 * 1024 // Max bytes included per key file
+)
+
+// keyFileNames are project files whose content helps describe the project
+var keyFileNames = map[string]bool{
+	"go.mod":           true,
+	"package.json":     true,
+	"Cargo.toml":       true,
+	"pyproject.toml":   true,
+	"requirements.txt": true,
+	"pom.xml":          true,
+	"build.gradle":     true,
+	"Makefile":         true,
+	"Dockerfile":       true,
+	"README.md":        true,
+	"README":           true,
+	"main.go":          true,
+}
+
+// BuildProjectOverview walks the project tree and collects key files for summarization
+func (fl *FileLoader) BuildProjectOverview(root string) (string, error) {
+	var tree []string
+	var keyFiles []string
+	truncated := false
+
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil // Skip inaccessible paths
+		}
+		if path == root {
+			return nil
+		}
+
+		relPath, relErr := filepath.Rel(root, path)
+		if relErr != nil {
+			relPath = path
+		}
+		depth := strings.Count(relPath, string(filepath.Separator))
+
+		if info.IsDir() {
+			// Skip hidden, ignored and deeply nested directories
+			if strings.HasPrefix(info.Name(), ".") || fl.gitignoreFilter.ShouldIgnore(path) || depth >= maxOverviewDepth {
+				return filepath.SkipDir
+			}
+			if len(tree) < maxOverviewEntries {
+				tree = append(tree, relPath+"/")
+			}
+			return nil
+		}
+
+		if strings.HasPrefix(info.Name(), ".") || fl.gitignoreFilter.ShouldIgnore(path) {
+			return nil
+		}
+
+		if len(tree) < maxOverviewEntries {
+			tree = append(tree, relPath)
+		} else {
+			truncated = true
+		}
+
+		if keyFileNames[info.Name()] && depth <= 2 {
+			keyFiles = append(keyFiles, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to walk project: %w", err)
+	}
+
+	var overview strings.Builder
+	overview.WriteString("=== PROJECT TREE ===\n")
+	for _, entry := range tree {
+		overview.WriteString(entry + "\n")
+	}
+	if truncated {
+		overview.WriteString("... (tree truncated)\n")
+	}
+
+	sort.Strings(keyFiles)
+	for _, path := range keyFiles {
+		content, err := os.ReadFile(path)
+		if err != nil || fl.isBinaryFile(path) {
+			continue
+		}
+		relPath, _ := filepath.Rel(root, path)
+		overview.WriteString(fmt.Sprintf("\n=== %s ===\n", relPath))
+		if len(content) > maxKeyFileSize {
+			overview.Write(content[:maxKeyFileSize])
+			overview.WriteString("\n... (truncated)\n")
+		} else {
+			overview.Write(content)
+			overview.WriteString("\n")
+		}
+	}
+
+	return overview.String(), nil
+}
+
+// WriteProjectSummary saves the generated project map to ProjectSummaryPath
+func WriteProjectSummary(content string) error {
+	if err := os.MkdirAll(filepath.Dir(ProjectSummaryPath), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(ProjectSummaryPath), err)
+	}
+	if err := os.WriteFile(ProjectSummaryPath, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", ProjectSummaryPath, err)
+	}
+	return nil
+}
+
+// LoadProjectSummary adds the project map to the context if it exists
+func (fc *FileContext) LoadProjectSummary() bool {
+	if _, err := os.Stat(ProjectSummaryPath); err != nil {
+		return false
+	}
+	return fc.LoadFile(ProjectSummaryPath) == nil
+}
@@ -0,0 +1,71 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package files
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBuildProjectOverview(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	os.Chdir(tmpDir)
+
+	os.MkdirAll(filepath.Join("cmd", "app"), 0755)
+	os.MkdirAll(".hidden", 0755)
+	os.WriteFile("go.mod", []byte("module example.com/app\n"), 0644)
+	os.WriteFile(filepath.Join("cmd", "app", "main.go"), []byte("package main\n"), 0644)
+	os.WriteFile(filepath.Join(".hidden", "secret.txt"), []byte("nope"), 0644)
+
+	overview, err := NewFileLoader().BuildProjectOverview(".")
+	if err != nil {
+		t.Fatalf("BuildProjectOverview() error = %v", err)
+	}
+
+	for _, want := range []string{"=== PROJECT TREE ===", "cmd/app/main.go", "=== go.mod ===", "module example.com/app"} {
+		if !strings.Contains(overview, want) {
+			t.Errorf("overview missing %q:\n%s", want, overview)
+		}
+	}
+	if strings.Contains(overview, "secret.txt") {
+		t.Error("overview should skip hidden directories")
+	}
+}
+
+func TestProjectSummaryRoundTrip(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	os.Chdir(tmpDir)
+
+	fc := NewFileContext()
+	if fc.LoadProjectSummary() {
+		t.Fatal("LoadProjectSummary() should return false when no summary exists")
+	}
+
+	if err := WriteProjectSummary("# Project Map\n"); err != nil {
+		t.Fatalf("WriteProjectSummary() error = %v", err)
+	}
+
+	if !fc.LoadProjectSummary() {
+		t.Fatal("LoadProjectSummary() should load an existing summary")
+	}
+	if len(fc.Files) != 1 || fc.Files[0].Content != "# Project Map\n" {
+		t.Errorf("unexpected context files: %+v", fc.Files)
+	}
+}
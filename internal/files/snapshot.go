@@ -0,0 +1,169 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package files
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultSnapshotDir is where /snapshot keeps workspace snapshots
+var DefaultSnapshotDir = filepath.Join(".deecli", "snapshots")
+
+// snapshotName is what a snapshot may be called; it is also its file name
+var snapshotName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// SnapshotFile is a loaded file as it was on disk when the snapshot was taken
+type SnapshotFile struct {
+	Path    string      `json:"path"` // Absolute path
+	RelPath string      `json:"rel_path"`
+	Hash    string      `json:"hash"`    // SHA-256 of Content, as in LoadedFile.Hash
+	Content []byte      `json:"content"` // Backup written back by Restore
+	Mode    os.FileMode `json:"mode"`
+}
+
+// Snapshot records the loaded files of the workspace, so that changes made
+// to them while experimenting can be reverted without touching anything else
+type Snapshot struct {
+	Name    string         `json:"name"`
+	Created time.Time      `json:"created"`
+	Files   []SnapshotFile `json:"files"`
+}
+
+// CreateSnapshot saves the current disk content of the loaded files as the
+// snapshot name in dir, replacing an older snapshot of that name
+func CreateSnapshot(dir, name string, loaded []LoadedFile) (*Snapshot, error) {
+	if !snapshotName.MatchString(name) {
+		return nil, fmt.Errorf("invalid snapshot name %q: use letters, digits, '.', '_' and '-'", name)
+	}
+	if len(loaded) == 0 {
+		return nil, errors.New("no files loaded to snapshot")
+	}
+
+	snapshot := &Snapshot{Name: name, Created: time.Now()}
+	for _, file := range loaded {
+		content, err := os.ReadFile(file.Path)
+		if err != nil {
+			return nil, fmt.Errorf("error reading %s: %w", file.RelPath, err)
+		}
+		mode := os.FileMode(0644)
+		if info, err := os.Stat(file.Path); err == nil {
+			mode = info.Mode().Perm()
+		}
+		snapshot.Files = append(snapshot.Files, SnapshotFile{
+			Path:    file.Path,
+			RelPath: file.RelPath,
+			Hash:    ContentHash(content),
+			Content: content,
+			Mode:    mode,
+		})
+	}
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+	if err := os.WriteFile(snapshotPath(dir, name), data, 0600); err != nil {
+		return nil, fmt.Errorf("failed to save snapshot: %w", err)
+	}
+	return snapshot, nil
+}
+
+// LoadSnapshot reads the snapshot name from dir
+func LoadSnapshot(dir, name string) (*Snapshot, error) {
+	if !snapshotName.MatchString(name) {
+		return nil, fmt.Errorf("invalid snapshot name %q", name)
+	}
+	data, err := os.ReadFile(snapshotPath(dir, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("no snapshot named %s", name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("snapshot %s is corrupt: %w", name, err)
+	}
+	return &snapshot, nil
+}
+
+// ListSnapshots returns the snapshots in dir, newest first
+func ListSnapshots(dir string) ([]*Snapshot, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshots []*Snapshot
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".json")
+		if !ok || entry.IsDir() {
+			continue
+		}
+		if snapshot, err := LoadSnapshot(dir, name); err == nil {
+			snapshots = append(snapshots, snapshot)
+		}
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Created.After(snapshots[j].Created)
+	})
+	return snapshots, nil
+}
+
+// snapshotPath returns the file of the snapshot name in dir
+func snapshotPath(dir, name string) string {
+	return filepath.Join(dir, name+".json")
+}
+
+// Changed returns the files whose content on disk no longer matches the
+// snapshot, including those that were deleted
+func (s *Snapshot) Changed() []SnapshotFile {
+	var changed []SnapshotFile
+	for _, file := range s.Files {
+		content, err := os.ReadFile(file.Path)
+		if err != nil || ContentHash(content) != file.Hash {
+			changed = append(changed, file)
+		}
+	}
+	return changed
+}
+
+// Restore writes back the files that changed since the snapshot and returns
+// them. Files that were not loaded when it was taken are left alone.
+func (s *Snapshot) Restore() ([]SnapshotFile, error) {
+	changed := s.Changed()
+	for i, file := range changed {
+		if err := os.MkdirAll(filepath.Dir(file.Path), 0755); err != nil {
+			return changed[:i], fmt.Errorf("error restoring %s: %w", file.RelPath, err)
+		}
+		if err := os.WriteFile(file.Path, file.Content, file.Mode); err != nil {
+			return changed[:i], fmt.Errorf("error restoring %s: %w", file.RelPath, err)
+		}
+	}
+	return changed, nil
+}
@@ -0,0 +1,73 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package files
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSnapshotRestore(t *testing.T) {
+	original := "func main() {\n\tprintln(\"hello\")\n}\n"
+	fc, path := loadSuggestionFile(t, original)
+	dir := filepath.Join(t.TempDir(), "snapshots")
+
+	if _, err := CreateSnapshot(dir, "../escape", fc.Files); err == nil || !strings.Contains(err.Error(), "invalid snapshot name") {
+		t.Errorf("expected a name with a path to be rejected, got %v", err)
+	}
+	if _, err := CreateSnapshot(dir, "before-refactor", fc.Files); err != nil {
+		t.Fatalf("CreateSnapshot failed: %v", err)
+	}
+
+	// An edit and an untracked file made during the experiment
+	if err := os.WriteFile(path, []byte("func main() {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	other := filepath.Join(filepath.Dir(path), "other.go")
+	if err := os.WriteFile(other, []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	snapshots, err := ListSnapshots(dir)
+	if err != nil || len(snapshots) != 1 || snapshots[0].Name != "before-refactor" {
+		t.Fatalf("ListSnapshots() = %v, %v", snapshots, err)
+	}
+	snapshot, err := LoadSnapshot(dir, "before-refactor")
+	if err != nil {
+		t.Fatalf("LoadSnapshot failed: %v", err)
+	}
+	if changed := snapshot.Changed(); len(changed) != 1 || changed[0].Path != path {
+		t.Fatalf("Changed() = %+v, want %s", changed, path)
+	}
+
+	restored, err := snapshot.Restore()
+	if err != nil || len(restored) != 1 {
+		t.Fatalf("Restore() = %+v, %v", restored, err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != original {
+		t.Errorf("expected the original content back, got %q", data)
+	}
+	if _, err := os.Stat(other); err != nil {
+		t.Error("expected files outside the snapshot to be left alone")
+	}
+	if restored, _ := snapshot.Restore(); len(restored) != 0 {
+		t.Errorf("expected nothing left to restore, got %+v", restored)
+	}
+
+	if _, err := LoadSnapshot(dir, "missing"); err == nil {
+		t.Error("expected an error for an unknown snapshot")
+	}
+}
@@ -0,0 +1,262 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package files
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/antenore/deecli/internal/i18n"
+)
+
+var (
+	// diffFence matches fenced diff or patch blocks in an AI response
+	diffFence = regexp.MustCompile("(?s)```(?:diff|patch)[^\n]*\n(.*?)```")
+	// diffTarget matches the new-file header of a unified diff
+	diffTarget = regexp.MustCompile(`^\+\+\+ (?:b/)?(\S+)`)
+)
+
+// PatchSuggestion is a unified diff the AI proposed for a loaded file that has
+// not been applied to the file on disk yet
+type PatchSuggestion struct {
+	Path     string // Absolute path of the target file
+	RelPath  string
+	Patch    string
+	BaseHash string // Hash of the content the patch was last checked against
+}
+
+// SuggestionCheck describes what a reload meant for a pending suggestion
+type SuggestionCheck struct {
+	RelPath string
+	Status  string // "applied", "rebased", "conflict"
+	Error   string
+}
+
+// Summary describes the check for the chat
+func (c SuggestionCheck) Summary() string {
+	switch c.Status {
+	case "applied":
+		return i18n.T("suggestions.applied", c.RelPath)
+	case "rebased":
+		return i18n.T("suggestions.rebased", c.RelPath)
+	default:
+		return i18n.T("suggestions.conflict", c.RelPath, c.Error)
+	}
+}
+
+// TrackPatchSuggestions records the diffs in an AI response that target loaded
+// files and apply to their current content. It returns how many were recorded.
+func (fc *FileContext) TrackPatchSuggestions(response string) int {
+	fc.suggestionsMu.Lock()
+	defer fc.suggestionsMu.Unlock()
+
+	tracked := 0
+	for _, block := range diffFence.FindAllStringSubmatch(response, -1) {
+		for _, patch := range splitFileDiffs(block[1]) {
+			file := fc.findDiffTarget(patch)
+			if file == nil {
+				continue
+			}
+			// Only suggestions that fit the current baseline are worth tracking
+			content, err := fc.Content(*file)
+			if err != nil {
+				continue
+			}
+			if _, err := ApplyPatch(content, patch); err != nil {
+				continue
+			}
+			if fc.suggestions == nil {
+				fc.suggestions = make(map[string][]PatchSuggestion)
+			}
+			fc.suggestions[file.Path] = append(fc.suggestions[file.Path], PatchSuggestion{
+				Path:     file.Path,
+				RelPath:  file.RelPath,
+				Patch:    patch,
+				BaseHash: file.Hash,
+			})
+			tracked++
+		}
+	}
+	return tracked
+}
+
+// PendingSuggestions returns the unapplied suggestions for a loaded file
+func (fc *FileContext) PendingSuggestions(path string) []PatchSuggestion {
+	fc.suggestionsMu.Lock()
+	defer fc.suggestionsMu.Unlock()
+	return append([]PatchSuggestion(nil), fc.suggestions[path]...)
+}
+
+// recheckSuggestions re-diffs pending suggestions for a file against its new
+// content. Suggestions that are now applied or no longer fit are dropped.
+func (fc *FileContext) recheckSuggestions(file LoadedFile) []SuggestionCheck {
+	fc.suggestionsMu.Lock()
+	defer fc.suggestionsMu.Unlock()
+
+	path := file.Path
+	pending := fc.suggestions[path]
+	if len(pending) == 0 {
+		return nil
+	}
+	content, err := fc.Content(file)
+	if err != nil {
+		return nil
+	}
+
+	var checks []SuggestionCheck
+	var kept []PatchSuggestion
+	for _, s := range pending {
+		if s.BaseHash != "" && s.BaseHash == file.Hash {
+			kept = append(kept, s) // Already checked against this content
+			continue
+		}
+		check := SuggestionCheck{RelPath: s.RelPath}
+		if IsPatchApplied(content, s.Patch) {
+			check.Status = "applied"
+		} else if _, err := ApplyPatch(content, s.Patch); err == nil {
+			check.Status = "rebased"
+			s.BaseHash = file.Hash
+			kept = append(kept, s)
+		} else {
+			check.Status = "conflict"
+			check.Error = err.Error()
+		}
+		checks = append(checks, check)
+	}
+
+	if len(kept) == 0 {
+		delete(fc.suggestions, path)
+	} else {
+		fc.suggestions[path] = kept
+	}
+	return checks
+}
+
+// AppliedSuggestion describes what ApplySuggestion wrote
+type AppliedSuggestion struct {
+	Hunks     int    // Hunks written
+	Formatter string // Formatter run on the result, empty if none is configured
+	FormatErr error  // Why formatting failed; the file was written unformatted
+	Diff      string // Unified diff of the file as written, formatting included
+}
+
+// ApplySuggestion writes the hunks of a pending suggestion for which accept
+// is true to its file, formats the result with the formatter of its language,
+// reloads the file and stops tracking the suggestion
+func (fc *FileContext) ApplySuggestion(s PatchSuggestion, accept []bool) (AppliedSuggestion, error) {
+	var result AppliedSuggestion
+	data, err := os.ReadFile(s.Path)
+	if err != nil {
+		return result, err
+	}
+	updated, err := ApplySelectedHunks(string(data), s.Patch, accept)
+	if err != nil {
+		return result, fmt.Errorf("the diff no longer applies to %s: %w", s.RelPath, err)
+	}
+	for _, ok := range accept {
+		if ok {
+			result.Hunks++
+		}
+	}
+
+	// Forget the suggestion first, so the reload does not report a partly
+	// applied diff as a conflict
+	fc.suggestionsMu.Lock()
+	kept := fc.suggestions[s.Path][:0]
+	for _, pending := range fc.suggestions[s.Path] {
+		if pending.Patch != s.Patch {
+			kept = append(kept, pending)
+		}
+	}
+	if len(kept) == 0 {
+		delete(fc.suggestions, s.Path)
+	} else {
+		fc.suggestions[s.Path] = kept
+	}
+	fc.suggestionsMu.Unlock()
+
+	if result.Hunks == 0 {
+		return result, nil
+	}
+	updated, result.Formatter, result.FormatErr = fc.FormatFile(s.Path, updated)
+	result.Diff = UnifiedDiff(s.RelPath, string(data), updated)
+
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(s.Path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	if err := os.WriteFile(s.Path, []byte(updated), mode); err != nil {
+		return AppliedSuggestion{}, err
+	}
+	if _, err := fc.ReloadFiles([]string{s.Path}); err != nil {
+		return result, err
+	}
+	fc.journal.Record(s.Path, s.RelPath, true)
+	return result, nil
+}
+
+// dropSuggestions forgets pending suggestions for an unloaded file, or all of them if path is empty
+func (fc *FileContext) dropSuggestions(path string) {
+	fc.suggestionsMu.Lock()
+	defer fc.suggestionsMu.Unlock()
+	if path == "" {
+		fc.suggestions = nil
+		return
+	}
+	delete(fc.suggestions, path)
+}
+
+// findDiffTarget returns the loaded file a single-file diff applies to
+func (fc *FileContext) findDiffTarget(patch string) *LoadedFile {
+	for _, line := range strings.Split(patch, "\n") {
+		match := diffTarget.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		target := filepath.ToSlash(match[1])
+		for i, f := range fc.Files {
+			if filepath.ToSlash(f.RelPath) == target || strings.HasSuffix(filepath.ToSlash(f.Path), "/"+target) {
+				return &fc.Files[i]
+			}
+		}
+		return nil
+	}
+	return nil
+}
+
+// splitFileDiffs splits a multi-file diff into one diff per file
+func splitFileDiffs(diff string) []string {
+	lines := strings.Split(diff, "\n")
+	var parts []string
+	start := -1
+	for i, line := range lines {
+		newFile := strings.HasPrefix(line, "diff --git ") ||
+			(strings.HasPrefix(line, "--- ") && i+1 < len(lines) && strings.HasPrefix(lines[i+1], "+++ ") &&
+				(i == 0 || !strings.HasPrefix(lines[i-1], "diff --git ")))
+		if !newFile {
+			continue
+		}
+		if start >= 0 {
+			parts = append(parts, strings.Join(lines[start:i], "\n"))
+		}
+		start = i
+	}
+	if start >= 0 {
+		parts = append(parts, strings.Join(lines[start:], "\n"))
+	}
+	return parts
+}
@@ -0,0 +1,179 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package files
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const suggestionResponse = "Change the greeting:\n\n```diff\n--- a/main.go\n+++ b/main.go\n@@ -1,3 +1,3 @@\n func main() {\n-\tprintln(\"hello\")\n+\tprintln(\"hello, world\")\n }\n```\n"
+
+func loadSuggestionFile(t *testing.T, content string) (*FileContext, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "main.go")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	fc := NewFileContext()
+	if err := fc.LoadFile(path); err != nil {
+		t.Fatal(err)
+	}
+	return fc, fc.Files[0].Path
+}
+
+func TestTrackPatchSuggestions(t *testing.T) {
+	fc, path := loadSuggestionFile(t, "func main() {\n\tprintln(\"hello\")\n}\n")
+
+	if got := fc.TrackPatchSuggestions("no diffs here"); got != 0 {
+		t.Errorf("expected no suggestions, got %d", got)
+	}
+	if got := fc.TrackPatchSuggestions(suggestionResponse); got != 1 {
+		t.Fatalf("expected 1 suggestion, got %d", got)
+	}
+	if pending := fc.PendingSuggestions(path); len(pending) != 1 {
+		t.Fatalf("expected 1 pending suggestion, got %d", len(pending))
+	}
+
+	// Diffs for files that are not loaded, or that don't fit, are ignored
+	other := "```diff\n--- a/other.go\n+++ b/other.go\n@@ -1 +1 @@\n-a\n+b\n```"
+	stale := "```diff\n--- a/main.go\n+++ b/main.go\n@@ -1 +1 @@\n-missing\n+line\n```"
+	if got := fc.TrackPatchSuggestions(other + "\n" + stale); got != 0 {
+		t.Errorf("expected unrelated diffs to be ignored, got %d", got)
+	}
+
+	fc.UnloadFiles("*.go")
+	if pending := fc.PendingSuggestions(path); len(pending) != 0 {
+		t.Errorf("unloading should drop suggestions, got %d", len(pending))
+	}
+}
+
+func TestReloadRechecksSuggestions(t *testing.T) {
+	tests := []struct {
+		name        string
+		newContent  string
+		wantStatus  string
+		wantPending int
+	}{
+		{
+			name:        "unrelated change keeps the suggestion",
+			newContent:  "// greeting\nfunc main() {\n\tprintln(\"hello\")\n}\n",
+			wantStatus:  "rebased",
+			wantPending: 1,
+		},
+		{
+			name:        "suggestion applied by the user",
+			newContent:  "func main() {\n\tprintln(\"hello, world\")\n}\n",
+			wantStatus:  "applied",
+			wantPending: 0,
+		},
+		{
+			name:        "conflicting edit",
+			newContent:  "func main() {\n\tprintln(\"goodbye\")\n}\n",
+			wantStatus:  "conflict",
+			wantPending: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fc, path := loadSuggestionFile(t, "func main() {\n\tprintln(\"hello\")\n}\n")
+			fc.TrackPatchSuggestions(suggestionResponse)
+
+			if err := os.WriteFile(path, []byte(tt.newContent), 0644); err != nil {
+				t.Fatal(err)
+			}
+			results, err := fc.autoReloadFiles([]string{path})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(results) != 1 || len(results[0].Suggestions) != 1 {
+				t.Fatalf("expected one suggestion check, got %+v", results)
+			}
+			if got := results[0].Suggestions[0].Status; got != tt.wantStatus {
+				t.Errorf("status = %q, want %q", got, tt.wantStatus)
+			}
+			if got := len(fc.PendingSuggestions(path)); got != tt.wantPending {
+				t.Errorf("pending = %d, want %d", got, tt.wantPending)
+			}
+		})
+	}
+}
+
+func TestSplitFileDiffs(t *testing.T) {
+	diff := "diff --git a/a.go b/a.go\n--- a/a.go\n+++ b/a.go\n@@ -1 +1 @@\n-a\n+b\n--- a/b.go\n+++ b/b.go\n@@ -1 +1 @@\n-c\n+d"
+	parts := splitFileDiffs(diff)
+	if len(parts) != 2 {
+		t.Fatalf("expected 2 file diffs, got %d: %q", len(parts), parts)
+	}
+}
+
+func TestApplySuggestion(t *testing.T) {
+	fc, path := loadSuggestionFile(t, "func main() {\n\tprintln(\"hello\")\n}\n")
+	fc.TrackPatchSuggestions(suggestionResponse)
+	pending := fc.PendingSuggestions(path)
+	if len(pending) != 1 {
+		t.Fatalf("expected 1 pending suggestion, got %d", len(pending))
+	}
+
+	applied, err := fc.ApplySuggestion(pending[0], []bool{true})
+	if err != nil || applied.Hunks != 1 || applied.Formatter != "" {
+		t.Fatalf("ApplySuggestion() = %+v, %v", applied, err)
+	}
+	if !strings.Contains(applied.Diff, "+\tprintln(\"hello, world\")") {
+		t.Errorf("expected the written change in the diff, got %q", applied.Diff)
+	}
+	if data, _ := os.ReadFile(path); !strings.Contains(string(data), "hello, world") {
+		t.Errorf("expected the hunk written to disk, got %q", data)
+	}
+	if !strings.Contains(fc.Files[0].Content, "hello, world") {
+		t.Error("expected the loaded copy to be reloaded")
+	}
+	if len(fc.PendingSuggestions(path)) != 0 {
+		t.Error("expected the applied suggestion to be dropped")
+	}
+	if entries := fc.Journal().Entries(); len(entries) != 1 || !entries[0].Applied {
+		t.Errorf("expected an applied change in the journal, got %+v", entries)
+	}
+}
+
+func TestApplySuggestion_Formats(t *testing.T) {
+	fc, path := loadSuggestionFile(t, "func main() {\n\tprintln(\"hello\")\n}\n")
+	fc.Formatters = map[string][]string{"go": {"sed", "s/world/World/"}}
+	fc.TrackPatchSuggestions(suggestionResponse)
+
+	applied, err := fc.ApplySuggestion(fc.PendingSuggestions(path)[0], []bool{true})
+	if err != nil || applied.Formatter != "sed" || applied.FormatErr != nil {
+		t.Fatalf("ApplySuggestion() = %+v, %v", applied, err)
+	}
+	if data, _ := os.ReadFile(path); !strings.Contains(string(data), "hello, World") {
+		t.Errorf("expected the formatted result written, got %q", data)
+	}
+	if !strings.Contains(applied.Diff, "+\tprintln(\"hello, World\")") {
+		t.Errorf("expected the formatting in the diff, got %q", applied.Diff)
+	}
+
+	// A failing formatter still writes the change, unformatted
+	fc.Formatters["go"] = []string{"deecli-no-such-formatter"}
+	fc.TrackPatchSuggestions(strings.ReplaceAll(strings.ReplaceAll(suggestionResponse, "hello, world", "bye"), "\"hello\"", "\"hello, World\""))
+	applied, err = fc.ApplySuggestion(fc.PendingSuggestions(path)[0], []bool{true})
+	if err != nil || applied.FormatErr == nil {
+		t.Fatalf("expected the formatter error reported, got %+v, %v", applied, err)
+	}
+	if data, _ := os.ReadFile(path); !strings.Contains(string(data), "bye") {
+		t.Errorf("expected the unformatted change written, got %q", data)
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package files
+
+import "slices"
+
+// maxUndo is how many file context changes can be undone
+const maxUndo = 10
+
+// contextSnapshot is the file context before a load, unload or clear
+type contextSnapshot struct {
+	operation   string // Command that changed the context, e.g. "/clear"
+	files       []LoadedFile
+	suggestions map[string][]PatchSuggestion
+}
+
+// SaveUndo records the loaded files and their pending suggestions before
+// operation changes them, so that Undo can restore them
+func (fc *FileContext) SaveUndo(operation string) {
+	fc.suggestionsMu.Lock()
+	suggestions := make(map[string][]PatchSuggestion, len(fc.suggestions))
+	for path, pending := range fc.suggestions {
+		suggestions[path] = slices.Clone(pending)
+	}
+	fc.suggestionsMu.Unlock()
+
+	fc.undo = append(fc.undo, contextSnapshot{
+		operation:   operation,
+		files:       slices.Clone(fc.Files),
+		suggestions: suggestions,
+	})
+	if len(fc.undo) > maxUndo {
+		fc.undo = fc.undo[len(fc.undo)-maxUndo:]
+	}
+}
+
+// DiscardUndo drops the last snapshot, for operations that changed nothing
+func (fc *FileContext) DiscardUndo() {
+	if len(fc.undo) > 0 {
+		fc.undo = fc.undo[:len(fc.undo)-1]
+	}
+}
+
+// Undo restores the file context saved before the last operation and returns
+// that operation, or false if there is nothing to undo. Files come back with
+// the content they had then; /reload refreshes them from disk.
+func (fc *FileContext) Undo() (string, bool) {
+	if len(fc.undo) == 0 {
+		return "", false
+	}
+	snapshot := fc.undo[len(fc.undo)-1]
+	fc.undo = fc.undo[:len(fc.undo)-1]
+
+	if fc.watcher != nil && fc.autoReloadEnabled {
+		fc.watcher.UnwatchAll()
+		for _, file := range snapshot.files {
+			fc.watcher.Watch(file.Path)
+		}
+	}
+	fc.Files = snapshot.files
+	fc.resetPromptCache()
+
+	fc.suggestionsMu.Lock()
+	fc.suggestions = snapshot.suggestions
+	fc.suggestionsMu.Unlock()
+	return snapshot.operation, true
+}
@@ -0,0 +1,59 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package files
+
+import (
+	"os"
+	"testing"
+)
+
+func TestUndo(t *testing.T) {
+	t.Chdir(t.TempDir())
+	for _, name := range []string{"a.go", "b.go"} {
+		if err := os.WriteFile(name, []byte("package main\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	fc := NewFileContext()
+	if _, ok := fc.Undo(); ok {
+		t.Fatal("expected nothing to undo")
+	}
+
+	fc.SaveUndo("/load")
+	if err := fc.LoadFiles([]string{"a.go", "b.go"}); err != nil {
+		t.Fatal(err)
+	}
+	fc.suggestions = map[string][]PatchSuggestion{fc.Files[0].Path: {{Path: fc.Files[0].Path, Patch: "p"}}}
+
+	fc.SaveUndo("/clear")
+	fc.Clear()
+
+	if op, ok := fc.Undo(); !ok || op != "/clear" {
+		t.Fatalf("expected to undo /clear, got %q %v", op, ok)
+	}
+	if len(fc.Files) != 2 || len(fc.PendingSuggestions(fc.Files[0].Path)) != 1 {
+		t.Errorf("expected files and suggestions restored, got %d files", len(fc.Files))
+	}
+
+	// Discarded snapshots are not undone
+	fc.SaveUndo("/unload")
+	fc.DiscardUndo()
+	if op, _ := fc.Undo(); op != "/load" {
+		t.Errorf("expected to undo /load next, got %q", op)
+	}
+	if len(fc.Files) != 0 {
+		t.Errorf("expected no files before the first load, got %d", len(fc.Files))
+	}
+}
@@ -17,6 +17,8 @@ import (
 	"context"
 	"log"
 	"path/filepath"
+	"runtime"
+	"strings"
 	"sync"
 	"time"
 
@@ -34,6 +36,7 @@ type FileWatcher struct {
 	stopChan         chan struct{}
 	mu               sync.RWMutex
 	supported        bool                 // Platform support flag
+	unsupportedErr   error                // Why file watching is unavailable, if it is
 	lastReloadTime   map[string]time.Time // Track recent reloads to prevent duplicates
 	reloadCallback   func([]string) error // Callback function for reloading files
 }
@@ -57,10 +60,10 @@ func NewWatcher(debounceDelay time.Duration) (*FileWatcher, error) {
 	// Try to create fsnotify watcher
 	watcher, err := fsnotify.NewWatcher()
 	if err != nil {
-		// Platform doesn't support file watching
+		// Platform doesn't support file watching; the chat reports this
+		// instead of logging, which would corrupt the TUI
 		fw.supported = false
-		log.Printf("⚠️ File watching not supported on this platform: %v", err)
-		log.Printf("   Manual reload with /reload command will be required")
+		fw.unsupportedErr = err
 		return fw, nil // Return degraded watcher, not error
 	}
 
@@ -73,6 +76,36 @@ func (fw *FileWatcher) IsSupported() bool {
 	return fw.supported && fw.watcher != nil
 }
 
+// UnsupportedReason returns why file watching is unavailable, or nil if it is supported
+func (fw *FileWatcher) UnsupportedReason() error {
+	return fw.unsupportedErr
+}
+
+// watchedKey maps an event path to the key it was registered under. Windows
+// file systems are case-insensitive and may report a different case than the
+// path passed to Watch, so the lookup ignores case there.
+func (fw *FileWatcher) watchedKey(path string) (string, bool) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		absPath = path
+	}
+
+	fw.mu.RLock()
+	defer fw.mu.RUnlock()
+
+	if _, ok := fw.watchedPaths[absPath]; ok {
+		return absPath, true
+	}
+	if runtime.GOOS == "windows" {
+		for key := range fw.watchedPaths {
+			if strings.EqualFold(key, absPath) {
+				return key, true
+			}
+		}
+	}
+	return absPath, false
+}
+
 // Watch adds a file to the watch list
 func (fw *FileWatcher) Watch(path string) error {
 	if !fw.IsSupported() {
@@ -228,11 +261,8 @@ func (fw *FileWatcher) processEvents(ctx context.Context) {
 
 			// Re-add the file to watcher if it was renamed (common with editor saves)
 			if event.Op&fsnotify.Rename == fsnotify.Rename {
-				absPath, _ := filepath.Abs(event.Name)
 				// Check if this file should be watched
-				fw.mu.RLock()
-				_, shouldWatch := fw.watchedPaths[absPath]
-				fw.mu.RUnlock()
+				absPath, shouldWatch := fw.watchedKey(event.Name)
 
 				if shouldWatch {
 					// Re-add the file to the watcher after a small delay
@@ -248,7 +278,7 @@ func (fw *FileWatcher) processEvents(ctx context.Context) {
 			   event.Op&fsnotify.Create == fsnotify.Create ||
 			   event.Op&fsnotify.Rename == fsnotify.Rename {
 				mu.Lock()
-				absPath, _ := filepath.Abs(event.Name)
+				absPath, _ := fw.watchedKey(event.Name)
 
 				// Check if we should reload this file
 				if fw.ShouldReload(absPath) {
@@ -18,6 +18,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"runtime"
 	"testing"
 	"time"
 
@@ -234,6 +235,7 @@ func TestFileWatcher_UnsupportedPlatform(t *testing.T) {
 
 	// Test that operations don't fail on unsupported platforms
 	assert.False(t, watcher.IsSupported())
+	assert.NoError(t, watcher.UnsupportedReason())
 
 	err := watcher.Watch("/tmp/test.txt")
 	assert.NoError(t, err) // Should not error, just silently ignore
@@ -256,6 +258,36 @@ func TestFileWatcher_UnsupportedPlatform(t *testing.T) {
 	})
 }
 
+func TestFileWatcher_WatchedKey(t *testing.T) {
+	watcher, err := NewWatcher(50 * time.Millisecond)
+	require.NoError(t, err)
+	defer watcher.Stop()
+
+	if !watcher.IsSupported() {
+		t.Skip("File watching not supported on this platform")
+	}
+
+	tmpDir := t.TempDir()
+	testFile := filepath.Join(tmpDir, "Main.go")
+	require.NoError(t, os.WriteFile(testFile, []byte("package main"), 0644))
+	require.NoError(t, watcher.Watch(testFile))
+
+	absPath, _ := filepath.Abs(testFile)
+	key, ok := watcher.watchedKey(testFile)
+	assert.True(t, ok)
+	assert.Equal(t, absPath, key)
+
+	lower := filepath.Join(tmpDir, "main.go")
+	key, ok = watcher.watchedKey(lower)
+	if runtime.GOOS == "windows" {
+		// Event paths may differ in case from the watched path
+		assert.True(t, ok)
+		assert.Equal(t, absPath, key)
+	} else {
+		assert.False(t, ok)
+	}
+}
+
 func TestFileWatcher_Integration(t *testing.T) {
 	if testing.Short() {
 		t.Skip("Skipping integration test in short mode")
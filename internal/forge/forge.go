@@ -0,0 +1,300 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Kind identifies the forge hosting a repository
+type Kind string
+
+const (
+	GitHub Kind = "github"
+	GitLab Kind = "gitlab"
+)
+
+// maxResponseSize bounds how much of an API response is read
+const maxResponseSize = 16 << 20
+
+// Remote is a repository on a forge
+type Remote struct {
+	Kind Kind
+	Host string
+	Path string // owner/repo on GitHub, group/.../project on GitLab
+}
+
+// Tokens holds the API tokens for each forge; empty tokens make anonymous requests
+type Tokens struct {
+	GitHub string
+	GitLab string
+}
+
+// Issue is an issue on a forge
+type Issue struct {
+	Number int
+	Title  string
+	State  string
+	Author string
+	Body   string
+	URL    string
+}
+
+// PullRequest is a GitHub pull request or GitLab merge request with its diff
+type PullRequest struct {
+	Issue
+	Diff string
+}
+
+// ParseRemoteURL recognizes GitHub and GitLab remotes in the SSH, scp-like
+// and HTTPS forms git accepts
+func ParseRemoteURL(raw string) (Remote, error) {
+	raw = strings.TrimSpace(raw)
+	var host, path string
+	if u, err := url.Parse(raw); err == nil && u.Scheme != "" && u.Host != "" {
+		host, path = u.Hostname(), u.Path
+	} else if at := strings.Index(raw, "@"); at >= 0 && strings.Contains(raw[at:], ":") {
+		// scp-like: git@github.com:owner/repo.git
+		rest := raw[at+1:]
+		colon := strings.Index(rest, ":")
+		host, path = rest[:colon], rest[colon+1:]
+	} else {
+		return Remote{}, fmt.Errorf("unrecognized remote URL %q", raw)
+	}
+
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	remote := Remote{Host: host, Path: path}
+	switch {
+	case strings.Contains(host, "github"):
+		remote.Kind = GitHub
+	case strings.Contains(host, "gitlab"):
+		remote.Kind = GitLab
+	default:
+		return Remote{}, fmt.Errorf("remote host %s is neither GitHub nor GitLab", host)
+	}
+	if strings.Count(path, "/") < 1 {
+		return Remote{}, fmt.Errorf("unrecognized repository path %q", path)
+	}
+	return remote, nil
+}
+
+// DetectRemote returns the forge repository of the origin remote in dir
+func DetectRemote(ctx context.Context, dir string) (Remote, error) {
+	cmd := exec.CommandContext(ctx, "git", "remote", "get-url", "origin")
+	cmd.Dir = dir
+	output, err := cmd.Output()
+	if err != nil {
+		return Remote{}, fmt.Errorf("no origin remote found: %w", err)
+	}
+	return ParseRemoteURL(string(output))
+}
+
+// Client talks to the REST API of the forge hosting a repository
+type Client struct {
+	Remote     Remote
+	Token      string
+	BaseURL    string // API root, derived from the remote host
+	HTTPClient *http.Client
+}
+
+// NewClient creates a client for remote using the matching token
+func NewClient(remote Remote, tokens Tokens) *Client {
+	client := &Client{
+		Remote:     remote,
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+	}
+	switch remote.Kind {
+	case GitHub:
+		client.Token = tokens.GitHub
+		client.BaseURL = "https://api.github.com"
+		if remote.Host != "github.com" {
+			// GitHub Enterprise Server
+			client.BaseURL = "https://" + remote.Host + "/api/v3"
+		}
+	case GitLab:
+		client.Token = tokens.GitLab
+		client.BaseURL = "https://" + remote.Host + "/api/v4"
+	}
+	return client
+}
+
+// GetIssue fetches an issue by number
+func (c *Client) GetIssue(ctx context.Context, number int) (*Issue, error) {
+	if c.Remote.Kind == GitLab {
+		var issue gitlabItem
+		if err := c.getJSON(ctx, fmt.Sprintf("%s/issues/%d", c.gitlabProject(), number), &issue); err != nil {
+			return nil, err
+		}
+		return issue.toIssue(), nil
+	}
+
+	var issue githubItem
+	if err := c.getJSON(ctx, fmt.Sprintf("/repos/%s/issues/%d", c.Remote.Path, number), &issue); err != nil {
+		return nil, err
+	}
+	return issue.toIssue(), nil
+}
+
+// GetPullRequest fetches a pull or merge request and its diff
+func (c *Client) GetPullRequest(ctx context.Context, number int) (*PullRequest, error) {
+	if c.Remote.Kind == GitLab {
+		base := fmt.Sprintf("%s/merge_requests/%d", c.gitlabProject(), number)
+		var mr gitlabItem
+		if err := c.getJSON(ctx, base, &mr); err != nil {
+			return nil, err
+		}
+		var diffs []gitlabDiff
+		if err := c.getJSON(ctx, base+"/diffs", &diffs); err != nil {
+			return nil, err
+		}
+		var diff strings.Builder
+		for _, d := range diffs {
+			diff.WriteString(d.unified())
+		}
+		return &PullRequest{Issue: *mr.toIssue(), Diff: diff.String()}, nil
+	}
+
+	path := fmt.Sprintf("/repos/%s/pulls/%d", c.Remote.Path, number)
+	var pr githubItem
+	if err := c.getJSON(ctx, path, &pr); err != nil {
+		return nil, err
+	}
+	diff, err := c.get(ctx, path, "application/vnd.github.diff")
+	if err != nil {
+		return nil, err
+	}
+	return &PullRequest{Issue: *pr.toIssue(), Diff: string(diff)}, nil
+}
+
+// gitlabProject is the API path of the project
+func (c *Client) gitlabProject() string {
+	return "/projects/" + url.PathEscape(c.Remote.Path)
+}
+
+func (c *Client) getJSON(ctx context.Context, path string, v interface{}) error {
+	accept := "application/json"
+	if c.Remote.Kind == GitHub {
+		accept = "application/vnd.github+json"
+	}
+	data, err := c.get(ctx, path, accept)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("unexpected %s response: %w", c.Remote.Kind, err)
+	}
+	return nil
+}
+
+func (c *Client) get(ctx context.Context, path, accept string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.BaseURL+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", accept)
+	if c.Token != "" {
+		if c.Remote.Kind == GitLab {
+			req.Header.Set("PRIVATE-TOKEN", c.Token)
+		} else {
+			req.Header.Set("Authorization", "Bearer "+c.Token)
+		}
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s request failed: %w", c.Remote.Kind, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return nil, fmt.Errorf("%s request failed: %w", c.Remote.Kind, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		_ = json.Unmarshal(data, &apiErr)
+		if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusUnauthorized {
+			return nil, fmt.Errorf("%s returned HTTP %d for %s (check the number and your %s token)", c.Remote.Kind, resp.StatusCode, c.Remote.Path, c.Remote.Kind)
+		}
+		return nil, fmt.Errorf("%s returned HTTP %d: %s", c.Remote.Kind, resp.StatusCode, apiErr.Message)
+	}
+	return data, nil
+}
+
+// githubItem is the part of a GitHub issue or pull request we use
+type githubItem struct {
+	Number  int    `json:"number"`
+	Title   string `json:"title"`
+	State   string `json:"state"`
+	Body    string `json:"body"`
+	HTMLURL string `json:"html_url"`
+	User    struct {
+		Login string `json:"login"`
+	} `json:"user"`
+}
+
+func (i githubItem) toIssue() *Issue {
+	return &Issue{Number: i.Number, Title: i.Title, State: i.State, Author: i.User.Login, Body: i.Body, URL: i.HTMLURL}
+}
+
+// gitlabItem is the part of a GitLab issue or merge request we use
+type gitlabItem struct {
+	IID         int    `json:"iid"`
+	Title       string `json:"title"`
+	State       string `json:"state"`
+	Description string `json:"description"`
+	WebURL      string `json:"web_url"`
+	Author      struct {
+		Username string `json:"username"`
+	} `json:"author"`
+}
+
+func (i gitlabItem) toIssue() *Issue {
+	return &Issue{Number: i.IID, Title: i.Title, State: i.State, Author: i.Author.Username, Body: i.Description, URL: i.WebURL}
+}
+
+// gitlabDiff is one file of a merge request diff
+type gitlabDiff struct {
+	OldPath     string `json:"old_path"`
+	NewPath     string `json:"new_path"`
+	Diff        string `json:"diff"`
+	NewFile     bool   `json:"new_file"`
+	DeletedFile bool   `json:"deleted_file"`
+}
+
+// unified renders the file diff with the headers GitLab leaves out
+func (d gitlabDiff) unified() string {
+	from, to := "a/"+d.OldPath, "b/"+d.NewPath
+	if d.NewFile {
+		from = "/dev/null"
+	}
+	if d.DeletedFile {
+		to = "/dev/null"
+	}
+	diff := d.Diff
+	if diff != "" && !strings.HasSuffix(diff, "\n") {
+		diff += "\n"
+	}
+	return fmt.Sprintf("diff --git a/%s b/%s\n--- %s\n+++ %s\n%s", d.OldPath, d.NewPath, from, to, diff)
+}
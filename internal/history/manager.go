@@ -15,22 +15,35 @@ package history
 
 import (
 	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 )
 
+// DefaultMaxEntries is the number of commands kept when no limit is set
+const DefaultMaxEntries = 1000
+
 // Entry represents a single history entry
 type Entry struct {
 	Command   string    `json:"command"`
 	Timestamp time.Time `json:"timestamp"`
+	Project   string    `json:"project,omitempty"` // Project directory the command was entered in
 }
 
-// Manager handles project-specific history persistence
+// Manager handles project-specific history persistence. Each project's
+// history is kept in its own file under ~/.deecli/history, named after a
+// hash of the project path, so repositories do not share /load patterns
+// and prompts.
 type Manager struct {
 	projectDir  string
+	historyDir  string
 	historyFile string
 	maxEntries  int
 }
@@ -43,34 +56,110 @@ func NewManager() (*Manager, error) {
 		return nil, fmt.Errorf("failed to get current directory: %w", err)
 	}
 
-	// Create .deecli directory if it doesn't exist
-	deecliDir := filepath.Join(projectDir, ".deecli")
-	if err := os.MkdirAll(deecliDir, 0755); err != nil {
-		return nil, fmt.Errorf("failed to create .deecli directory: %w", err)
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get home directory: %w", err)
+	}
+	historyDir := filepath.Join(home, ".deecli", "history")
+	if err := os.MkdirAll(historyDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create history directory: %w", err)
 	}
 
-	return &Manager{
+	m := &Manager{
 		projectDir:  projectDir,
-		historyFile: filepath.Join(deecliDir, "history.jsonl"),
-		maxEntries:  1000, // Keep last 1000 entries
-	}, nil
+		historyDir:  historyDir,
+		historyFile: filepath.Join(historyDir, ProjectKey(projectDir)+".jsonl"),
+		maxEntries:  DefaultMaxEntries,
+	}
+	m.importLegacy()
+	return m, nil
+}
+
+// ProjectKey returns the name of the history file of a project directory
+func ProjectKey(projectDir string) string {
+	sum := sha256.Sum256([]byte(filepath.Clean(projectDir)))
+	return hex.EncodeToString(sum[:8])
+}
+
+// Path returns the history file of the current project
+func (m *Manager) Path() string {
+	return m.historyFile
+}
+
+// importLegacy copies the history that older versions kept in the
+// project's .deecli directory, the first time the project is opened
+func (m *Manager) importLegacy() {
+	if _, err := os.Stat(m.historyFile); err == nil {
+		return
+	}
+	legacy, err := os.Open(filepath.Join(m.projectDir, ".deecli", "history.jsonl"))
+	if err != nil {
+		return
+	}
+	defer legacy.Close()
+
+	file, err := os.OpenFile(m.historyFile, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+	io.Copy(file, legacy)
+}
+
+// SetMaxEntries sets how many commands the history file keeps; values
+// below 1 restore the default. The file is trimmed on the next Add.
+func (m *Manager) SetMaxEntries(maxEntries int) {
+	if maxEntries < 1 {
+		maxEntries = DefaultMaxEntries
+	}
+	m.maxEntries = maxEntries
 }
 
 // Load reads history from the project-specific file
 func (m *Manager) Load() ([]string, error) {
+	entries, err := m.LoadEntries()
+	if err != nil {
+		return nil, err
+	}
+
+	commands := make([]string, len(entries))
+	for i, entry := range entries {
+		commands[i] = entry.Command
+	}
+	return commands, nil
+}
+
+// LoadEntries reads the history entries, oldest first. Consecutive
+// duplicates are collapsed and only the last maxEntries are returned.
+func (m *Manager) LoadEntries() ([]Entry, error) {
+	entries, _, err := readEntries(m.historyFile)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) > m.maxEntries {
+		entries = entries[len(entries)-m.maxEntries:]
+	}
+	return entries, nil
+}
+
+// readEntries reads all entries of a history file with consecutive
+// duplicates collapsed, and the number of entries before collapsing
+func readEntries(path string) ([]Entry, int, error) {
 	// Check if history file exists
-	if _, err := os.Stat(m.historyFile); os.IsNotExist(err) {
-		return []string{}, nil // No history yet
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return []Entry{}, 0, nil // No history yet
 	}
 
-	file, err := os.Open(m.historyFile)
+	file, err := os.Open(path)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open history file: %w", err)
+		return nil, 0, fmt.Errorf("failed to open history file: %w", err)
 	}
 	defer file.Close()
 
-	var commands []string
+	var entries []Entry
+	stored := 0
 	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024) // Allow long multi-line prompts
 
 	for scanner.Scan() {
 		var entry Entry
@@ -78,14 +167,63 @@ func (m *Manager) Load() ([]string, error) {
 			// Skip malformed lines
 			continue
 		}
-		commands = append(commands, entry.Command)
+		stored++
+		if len(entries) > 0 && entries[len(entries)-1].Command == entry.Command {
+			entries[len(entries)-1] = entry // Keep the latest timestamp
+			continue
+		}
+		entries = append(entries, entry)
 	}
 
 	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("failed to read history file: %w", err)
+		return nil, 0, fmt.Errorf("failed to read history file: %w", err)
 	}
 
-	return commands, nil
+	return entries, stored, nil
+}
+
+// Search returns the persisted entries containing term, ignoring case,
+// most recent first
+func (m *Manager) Search(term string) ([]Entry, error) {
+	entries, err := m.LoadEntries()
+	if err != nil {
+		return nil, err
+	}
+
+	return matching(entries, term), nil
+}
+
+// SearchAll is Search over the history of every project, most recent first
+func (m *Manager) SearchAll(term string) ([]Entry, error) {
+	paths, err := filepath.Glob(filepath.Join(m.historyDir, "*.jsonl"))
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []Entry
+	for _, path := range paths {
+		entries, _, err := readEntries(path)
+		if err != nil {
+			continue // Skip unreadable files
+		}
+		matches = append(matches, matching(entries, term)...)
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Timestamp.After(matches[j].Timestamp)
+	})
+	return matches, nil
+}
+
+// matching returns the entries containing term, ignoring case, newest first
+func matching(entries []Entry, term string) []Entry {
+	term = strings.ToLower(term)
+	var matches []Entry
+	for i := len(entries) - 1; i >= 0; i-- {
+		if strings.Contains(strings.ToLower(entries[i].Command), term) {
+			matches = append(matches, entries[i])
+		}
+	}
+	return matches
 }
 
 // Add appends a new command to history
@@ -111,6 +249,7 @@ func (m *Manager) Add(command string) error {
 	entry := Entry{
 		Command:   command,
 		Timestamp: time.Now(),
+		Project:   m.projectDir,
 	}
 
 	data, err := json.Marshal(entry)
@@ -140,19 +279,22 @@ func (m *Manager) GetLast() (string, error) {
 	return commands[len(commands)-1], nil
 }
 
-// trimHistory keeps only the last maxEntries in the file
+// trimHistory keeps only the last maxEntries in the file, dropping
+// consecutive duplicates left by older versions
 func (m *Manager) trimHistory() error {
-	commands, err := m.Load()
+	entries, stored, err := readEntries(m.historyFile)
 	if err != nil {
 		return err
 	}
 
-	if len(commands) <= m.maxEntries {
+	if stored <= m.maxEntries && stored == len(entries) {
 		return nil // No trimming needed
 	}
 
 	// Keep only the last maxEntries
-	commands = commands[len(commands)-m.maxEntries:]
+	if len(entries) > m.maxEntries {
+		entries = entries[len(entries)-m.maxEntries:]
+	}
 
 	// Rewrite the file
 	file, err := os.Create(m.historyFile)
@@ -161,11 +303,7 @@ func (m *Manager) trimHistory() error {
 	}
 	defer file.Close()
 
-	for _, cmd := range commands {
-		entry := Entry{
-			Command:   cmd,
-			Timestamp: time.Now(), // We lose original timestamps during trim
-		}
+	for _, entry := range entries {
 		data, err := json.Marshal(entry)
 		if err != nil {
 			continue
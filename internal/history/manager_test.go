@@ -0,0 +1,165 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package history
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestManager_DedupeAndLimit(t *testing.T) {
+	m, err := newTestManager(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.SetMaxEntries(3)
+
+	for _, command := range []string{"one", "one", "two", "three", "three", "four"} {
+		if err := m.Add(command); err != nil {
+			t.Fatalf("Add(%q): %v", command, err)
+		}
+	}
+	commands, err := m.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"two", "three", "four"}; !slices.Equal(commands, want) {
+		t.Errorf("got %v, want %v", commands, want)
+	}
+}
+
+func TestManager_DedupeExistingFile(t *testing.T) {
+	m, err := newTestManager(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Files written by older versions can hold consecutive duplicates
+	file, err := os.Create(m.historyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	for i, command := range []string{"a", "a", "b"} {
+		data, _ := json.Marshal(Entry{Command: command, Timestamp: first.Add(time.Duration(i) * time.Hour)})
+		file.Write(append(data, '\n'))
+	}
+	file.Close()
+
+	entries, err := m.LoadEntries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].Command != "a" || !entries[0].Timestamp.Equal(first.Add(time.Hour)) {
+		t.Errorf("expected duplicates collapsed to the latest one, got %+v", entries)
+	}
+
+	// Trimming rewrites the file without the duplicates and keeps timestamps
+	if err := m.Add("c"); err != nil {
+		t.Fatal(err)
+	}
+	_, stored, err := readEntries(m.historyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored != 3 {
+		t.Errorf("expected 3 entries in the file, got %d", stored)
+	}
+	entries, _ = m.LoadEntries()
+	if !entries[1].Timestamp.Equal(first.Add(2 * time.Hour)) {
+		t.Errorf("expected timestamps to survive trimming, got %v", entries[1].Timestamp)
+	}
+}
+
+func TestManager_Search(t *testing.T) {
+	m, err := newTestManager(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, command := range []string{"/load main.go", "explain the Parser", "/load parser.go"} {
+		m.Add(command)
+	}
+
+	matches, err := m.Search("PARSER")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 2 || matches[0].Command != "/load parser.go" || matches[1].Command != "explain the Parser" {
+		t.Errorf("expected case-insensitive matches, most recent first, got %+v", matches)
+	}
+	if matches, _ := m.Search("missing"); len(matches) != 0 {
+		t.Errorf("expected no matches, got %+v", matches)
+	}
+}
+
+func TestManager_PerProject(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	projectA, projectB := t.TempDir(), t.TempDir()
+
+	// Older versions kept the history in the project's .deecli directory
+	if err := os.MkdirAll(filepath.Join(projectA, ".deecli"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	legacy, _ := json.Marshal(Entry{Command: "/load legacy.go", Timestamp: time.Now().Add(-time.Hour)})
+	if err := os.WriteFile(filepath.Join(projectA, ".deecli", "history.jsonl"), append(legacy, '\n'), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Chdir(projectA)
+	a, err := NewManager()
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.Add("/load *.go")
+
+	t.Chdir(projectB)
+	b, err := NewManager()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.Add("/load *.py")
+
+	if a.Path() == b.Path() {
+		t.Fatal("expected separate history files per project")
+	}
+	if commands, _ := a.Load(); !slices.Equal(commands, []string{"/load legacy.go", "/load *.go"}) {
+		t.Errorf("unexpected history for project A: %v", commands)
+	}
+	if commands, _ := b.Load(); !slices.Equal(commands, []string{"/load *.py"}) {
+		t.Errorf("unexpected history for project B: %v", commands)
+	}
+
+	if matches, _ := b.Search("/load"); len(matches) != 1 {
+		t.Errorf("expected Search to cover only the current project, got %+v", matches)
+	}
+	matches, err := b.SearchAll("/load")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 3 || matches[0].Command != "/load *.py" || matches[0].Project != projectB || matches[2].Command != "/load legacy.go" {
+		t.Errorf("expected matches from every project, most recent first, got %+v", matches)
+	}
+}
+
+// newTestManager creates a manager with its own home and project directories
+func newTestManager(t *testing.T) (*Manager, error) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	t.Chdir(t.TempDir())
+	return NewManager()
+}
@@ -0,0 +1,206 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package i18n
+
+// english is the reference catalog; every other catalog must define the same keys
+var english = map[string]string{
+	"welcome.body": `🐉 DeeCLI - AI Code Assistant | %s
+
+Essential Commands: /load <file> /unload <pattern> /list /clear /analyze /config /history /help
+Quick Keys: Tab=complete/focus %s=newline ↑/↓ or %s/%s=history F1=help F2=files F3=format
+
+💡 Start by loading files: /load *.go or /load main.go
+   Code is raw by default (copy-friendly). Press F3 for formatted view`,
+
+	"help.body": `🐉 DeeCLI Help
+
+=== Multi-line Input ===
+• Enter: Send message
+• %s: New line in message
+• Type naturally across multiple lines
+
+=== History Navigation ===
+• %s: Previous command/message
+• %s: Next command/message
+
+=== Chat Commands ===
+/load <file>    Load files (additive - adds to existing)
+/load --all <file> Load files ignoring .gitignore
+/load --modified|--staged|--branch [base] Load the files changed in git
+/load <file> --lang <language> Label files with a language, e.g. extensionless scripts
+/unload <pattern> Remove files matching pattern
+/add <file>     Same as /load (deprecated)
+/list           List all loaded files
+/mentioned      List files the AI mentioned (load|edit|diff <n>)
+/apply [file]   Apply a suggested diff hunk by hunk (y/n/a/d, q done, Esc cancel)
+/view <file>    View a file read-only without loading it
+/view --compare <file> Compare a loaded file with its content on disk
+/clear          Clear all loaded files
+/undo-files     Undo the last /load, /unload or /clear
+/snapshot       List snapshots of the loaded files (create|restore <name>)
+/analyze        Analyze loaded files
+/improve        Get improvement suggestions
+/explain        Explain loaded code
+/analyze [files] > <out> Write the result to a file (also /explain, /improve, /review)
+/edit           AI suggests which files to edit based on conversation
+/edit <file>    Open specific file in editor
+/edit <file:line> Jump to specific line in file
+/init           Generate project map (.deecli/PROJECT.md)
+/pr review <n>  Load a GitHub PR or GitLab MR diff and review it
+/review <a>..<b> Review the diff between two git refs, findings by file
+/compact [n]    Summarize the conversation, keeping the last n turns (default 2)
+/suggest        Suggest edits from the conversation (/suggest open|prompt|fix <n>)
+/send --n <k> <prompt> Ask for k answers (up to 5) and keep the one you pick
+/docs <pkg>     Document Go packages as diffs for /apply (--check: report problems, go vet)
+/git commit     Commit the files changed this session (-m auto|<msg>, --push)
+/config         View/manage configuration settings
+/mode <name>    Temperature preset: coding, general, creative or off (--prompt)
+/postprocess    List response post-processors (/postprocess <name> on|off)
+/keysetup       Configure key bindings
+/history        View/manage this project's command history (show|clear|search [--all] <term>)
+/session        List recent sessions (/session title <text> to rename)
+/fork [title]   Continue in a copy of this session, keeping the original
+/tab            List chat tabs (/tab new [name]|<n>|rename <name>|close)
+/tasks          List background tasks such as /analyze (/tasks cancel [n])
+/share          Export the conversation, redacted (/share file <path>|gist)
+/errors         Show recent errors with codes (/errors all|clear)
+/audit          Show recorded tool calls (/audit all|<count>)
+/stats          Show local usage statistics (/stats reset|export [file])
+/sources        Show the tool calls the last response was based on
+/sections       List headings and code blocks of the chat (/sections <n> jumps)
+/dryrun         Only simulate tools that write files or run commands (/dryrun on|off)
+/help           Show this help
+/quit           Exit the application
+
+=== Keyboard Shortcuts ===
+Tab             Smart: show/accept completions OR switch focus
+Enter           Send message
+%s         New line in message
+↑ or %s    Previous history (single-line input only)
+↓ or %s    Next history (single-line input only)
+F1              Toggle this help
+F2              Toggle files sidebar
+F3              Toggle code format (raw/bordered) for new messages
+F4              Show/hide the model, temperature and tokens of each response
+Esc             Cancel ongoing AI response or pending tool chain
+Ctrl+C          Exit application
+Ctrl+←/→        Previous/next chat tab
+Ctrl+W          Delete word backward
+Ctrl+U/K        Delete to line start/end
+Alt+Backspace   Delete word backward (alternative)
+
+=== Focus Modes ===
+✏️ INPUT        Type messages and commands
+📜 CHAT         Scroll through chat history
+📁 FILES        Browse loaded files (when F2 open)
+
+Tab cycles focus: Input → Chat → Files (if open) → Input
+
+=== Navigation ===
+↑/↓             Scroll in viewport/sidebar OR history in input (single-line)
+PgUp/PgDn       Page up/down
+Ctrl+U/Ctrl+D   Half page up/down
+Home/End        Jump to top/bottom
+[ / ]           Previous/next heading or code block in chat
+s / g           Files: cycle the order (loaded, name, size, changed) / group by directory
+[ / ] Space     Files: select a directory / collapse or expand it
+Esc/Enter       Return to input mode
+
+Tip: Yellow border shows which pane has focus!
+
+=== File Patterns ===
+You can use glob patterns to load multiple files:
+  /load *.go           Load all .go files
+  /load src/**/*.go    Load all .go files in src
+  /load {*.go,*.md}    Load all .go and .md files
+
+=== Tips ===
+• Multi-line messages: Use %s to add new lines
+• Quick submit: Just press Enter to send your message
+• Press Tab (when no completions) to switch between panes
+• Standard text editing shortcuts work (Ctrl+W, Ctrl+U, Ctrl+K, etc.)
+• Yellow border shows which pane has focus
+• Tab shows completions, use ↑↓ arrows to cycle, Tab/Enter to accept, Esc to cancel
+• Arrow keys scroll in focused panes
+• Press Esc to quickly return to input mode
+
+Press F1 to close this help`,
+
+	// Loading indicator
+	"loading.thinking":          "Thinking...",
+	"loading.continuing":        "Continuing...",
+	"loading.cancel_hint":       "Press Esc to cancel",
+	"loading.cancel_hint_short": "Esc to cancel",
+	"loading.cancel_hint_aloud": "Press Escape to cancel.",
+	"stream.stalled":            "⚠️ Stream stalled, no data received. Press r to retry",
+	"stream.reconnecting":       "🔄 Stream stalled, sending the request again...",
+	"stream.truncated":          "_(truncated by user)_",
+	"empty.retrying":            "⚠️ The model sent an empty response, asking again...",
+	"empty.failed":              "The model sent an empty response again. Try rephrasing the prompt, or switch models with /config set model",
+	"overflow.retrying":         "✂️ Request too long for the model, sending it again with %s. /compact shortens the conversation for good",
+	"overflow.files":            "the file context cut from %d KB to %d KB",
+	"overflow.history":          "the %d oldest messages left out",
+	"context.near_limit":        "Context %d%% full, try %s",
+	"context.or":                " or ",
+	"scrollback.placeholder":    "… %d earlier messages (press PgUp to load)",
+	"queue.label":               "Queued (↑ to edit)",
+	"queue.restored":            "↩️ Queued message(s) moved back to the input",
+	"tasks.label":               "Task (/tasks cancel <n> stops it)",
+	"duplicate.ignored":         "⏭️ That prompt is already being answered or queued, so it was not sent again",
+	"duplicate.confirm":         "🔁 Same prompt as the last one. Press Enter again to send it anyway",
+
+	// Status messages
+	"status.request_cancelled":    "🚫 Request cancelled",
+	"status.partial_kept":         "🚫 Request cancelled; the partial answer is kept in the conversation",
+	"status.tool_chain_cancelled": "🚫 Tool chain cancelled",
+	"status.tool_calls_discarded": " (%d queued tool call(s) discarded)",
+	"status.ready":                ". Ready for your next message.",
+	"status.editor_closed":        "✓ Editor closed",
+	"status.editor_context":       "📍 Added where you were editing (%s) to the input",
+	"status.api_key_missing":      "Please set DEEPSEEK_API_KEY environment variable",
+	"status.plain_no_sidebar":     "Files sidebar is not available in plain mode. Use /list to see loaded files.",
+	"status.annotations_on":       "Showing the model, temperature and tokens under each response (F4 to hide)",
+	"status.annotations_off":      "Response annotations hidden",
+
+	// File auto-reload
+	"reload.auto_reloaded":      "📁 Auto-reloaded %d modified file(s)",
+	"reload.setup_failed":       "⚠️ Auto-reload setup failed: %v",
+	"reload.unsupported":        "ℹ️ File auto-reload is not available on this platform.\n   Use /reload command to manually reload modified files.",
+	"reload.unsupported_reason": "   Reason: %v",
+	"reload.failed":             "⚠️ Failed to auto-reload files: %v",
+	"reload.after_edit":         "🔄 Auto-reloaded %d file(s), %d changed",
+
+	// Proxy and TLS settings
+	"network.setup_failed": "⚠️ Proxy/TLS settings not applied: %v",
+	"network.insecure":     "⚠️ WARNING: TLS certificate verification is disabled (insecure_skip_verify).\n   Anyone on the network path can read and alter API traffic, including your API key.",
+
+	// Startup update check (check_updates)
+	"update.available": "🆕 DeeCLI %s is available (you have %s). Run 'deecli update' to see the changes and install it.",
+
+	// Restricted mode in an untrusted directory
+	"trust.restricted": "🔒 Restricted mode: %s is not trusted, so its .deecli/config.yaml is ignored and external tools and plugins are disabled. Run 'deecli trust' there to trust it.",
+
+	// Tool call limits (tool_calls_per_turn, tool_calls_per_minute)
+	"tools.limit_reached": "⛔ Tool chain stopped: the limit of %s was reached. The model was asked to answer with what it has; raise the limit with /config set if the calls were legitimate.",
+	"tools.loop_detected": "🔁 Tool chain stopped: the model requested %s with the same arguments %d times. It was asked to answer with the results it already has.",
+
+	// Pending AI diffs re-checked after a reload
+	"suggestions.applied":  "✅ %s changed on disk and now contains the suggested diff",
+	"suggestions.rebased":  "⚠️ %s changed on disk; the pending suggested diff was re-checked and still applies to the new content",
+	"suggestions.conflict": "⚠️ %s changed on disk and the pending suggested diff no longer applies (%s). Ask for an updated diff",
+	// Configuration
+	"config.language_set":     "✅ Language set to: %s",
+	"config.language_get":     "Language: %s",
+	"config.language_invalid": "Invalid language: %s",
+}
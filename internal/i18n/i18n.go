@@ -0,0 +1,121 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefaultLanguage is used when no language is configured or detected
+const DefaultLanguage = "en"
+
+// Auto selects the language from the LC_ALL, LC_MESSAGES or LANG environment variables
+const Auto = "auto"
+
+// catalogs maps a language code to its message catalog
+var catalogs = map[string]map[string]string{
+	"en": english,
+	"it": italian,
+}
+
+var (
+	mu      sync.RWMutex
+	current = DefaultLanguage
+)
+
+// Supported returns the available language codes in sorted order
+func Supported() []string {
+	langs := make([]string, 0, len(catalogs))
+	for lang := range catalogs {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// IsSupported reports whether a catalog exists for the language code
+func IsSupported(lang string) bool {
+	_, ok := catalogs[normalize(lang)]
+	return ok
+}
+
+// Resolve maps a configured language to a supported code, detecting it from the
+// environment for "" or "auto" and falling back to English
+func Resolve(lang string) string {
+	if lang == "" || lang == Auto {
+		for _, env := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+			if value := os.Getenv(env); value != "" {
+				lang = value
+				break
+			}
+		}
+	}
+	lang = normalize(lang)
+	if _, ok := catalogs[lang]; ok {
+		return lang
+	}
+	return DefaultLanguage
+}
+
+// SetLanguage switches the active catalog
+func SetLanguage(lang string) error {
+	if lang != "" && lang != Auto && !IsSupported(lang) {
+		return fmt.Errorf("unsupported language '%s'. Supported languages are: %s, %s",
+			lang, Auto, strings.Join(Supported(), ", "))
+	}
+	mu.Lock()
+	current = Resolve(lang)
+	mu.Unlock()
+	return nil
+}
+
+// Language returns the active language code
+func Language() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
+
+// T returns the message for key in the active language, formatted with args.
+// Missing translations fall back to English, and unknown keys to the key itself.
+func T(key string, args ...interface{}) string {
+	mu.RLock()
+	lang := current
+	mu.RUnlock()
+
+	msg, ok := catalogs[lang][key]
+	if !ok {
+		msg, ok = english[key]
+	}
+	if !ok {
+		msg = key
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
+
+// normalize turns locale names like "it_IT.UTF-8" into catalog codes like "it"
+func normalize(lang string) string {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if i := strings.IndexAny(lang, "_-.@"); i >= 0 {
+		lang = lang[:i]
+	}
+	return lang
+}
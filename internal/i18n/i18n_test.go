@@ -0,0 +1,92 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package i18n
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var verbPattern = regexp.MustCompile(`%[-+# 0]*[0-9]*(\.[0-9]+)?[a-zA-Z%]`)
+
+func TestCatalogsMatchEnglish(t *testing.T) {
+	for lang, catalog := range catalogs {
+		for key, msg := range english {
+			translated, ok := catalog[key]
+			if !ok {
+				t.Errorf("%s: missing key %q", lang, key)
+				continue
+			}
+			want := strings.Join(verbPattern.FindAllString(msg, -1), " ")
+			got := strings.Join(verbPattern.FindAllString(translated, -1), " ")
+			if got != want {
+				t.Errorf("%s: key %q has format verbs %q, want %q", lang, key, got, want)
+			}
+		}
+		for key := range catalog {
+			if _, ok := english[key]; !ok {
+				t.Errorf("%s: key %q is not in the English catalog", lang, key)
+			}
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_MESSAGES", "")
+	t.Setenv("LANG", "it_IT.UTF-8")
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "en", want: "en"},
+		{input: "IT", want: "it"},
+		{input: "it-CH", want: "it"},
+		{input: "", want: "it"},
+		{input: Auto, want: "it"},
+		{input: "xx", want: DefaultLanguage},
+	}
+
+	for _, tt := range tests {
+		if got := Resolve(tt.input); got != tt.want {
+			t.Errorf("Resolve(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestT(t *testing.T) {
+	defer SetLanguage(DefaultLanguage)
+
+	if err := SetLanguage("it"); err != nil {
+		t.Fatalf("SetLanguage(it) failed: %v", err)
+	}
+	if got := T("status.editor_closed"); got != "✓ Editor chiuso" {
+		t.Errorf("T(status.editor_closed) = %q", got)
+	}
+	if got := T("reload.after_edit", 2, 1); got != "🔄 Ricaricati automaticamente 2 file, 1 modificati" {
+		t.Errorf("T(reload.after_edit) = %q", got)
+	}
+	if got := T("no.such.key"); got != "no.such.key" {
+		t.Errorf("unknown key should return the key, got %q", got)
+	}
+
+	if err := SetLanguage("klingon"); err == nil {
+		t.Error("SetLanguage should reject unsupported languages")
+	}
+	if Language() != "it" {
+		t.Errorf("failed SetLanguage should keep the current language, got %q", Language())
+	}
+}
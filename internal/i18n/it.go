@@ -0,0 +1,206 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package i18n
+
+// italian translates the English catalog
+var italian = map[string]string{
+	"welcome.body": `🐉 DeeCLI - Assistente AI per il codice | %s
+
+Comandi principali: /load <file> /unload <pattern> /list /clear /analyze /config /history /help
+Tasti rapidi: Tab=completa/focus %s=a capo ↑/↓ o %s/%s=cronologia F1=aiuto F2=file F3=formato
+
+💡 Inizia caricando dei file: /load *.go oppure /load main.go
+   Il codice è grezzo per default (facile da copiare). Premi F3 per la vista formattata`,
+
+	"help.body": `🐉 Guida di DeeCLI
+
+=== Input su più righe ===
+• Invio: Invia il messaggio
+• %s: Nuova riga nel messaggio
+• Scrivi liberamente su più righe
+
+=== Navigazione cronologia ===
+• %s: Comando/messaggio precedente
+• %s: Comando/messaggio successivo
+
+=== Comandi della chat ===
+/load <file>    Carica file (si aggiungono a quelli esistenti)
+/load --all <file> Carica file ignorando .gitignore
+/load --modified|--staged|--branch [base] Carica i file modificati in git
+/load <file> --lang <linguaggio> Etichetta i file con un linguaggio, es. script senza estensione
+/unload <pattern> Rimuove i file corrispondenti al pattern
+/add <file>     Come /load (deprecato)
+/list           Elenca i file caricati
+/mentioned      Elenca i file citati dall'AI (load|edit|diff <n>)
+/apply [file]   Applica un diff suggerito blocco per blocco (y/n/a/d, q fine, Esc annulla)
+/view <file>    Mostra un file in sola lettura senza caricarlo
+/view --compare <file> Confronta un file caricato con il contenuto su disco
+/clear          Rimuove tutti i file caricati
+/undo-files     Annulla l'ultimo /load, /unload o /clear
+/snapshot       Elenca le istantanee dei file caricati (create|restore <nome>)
+/analyze        Analizza i file caricati
+/improve        Suggerisce miglioramenti
+/explain        Spiega il codice caricato
+/analyze [file] > <out> Scrive il risultato in un file (anche /explain, /improve, /review)
+/edit           L'AI suggerisce quali file modificare in base alla conversazione
+/edit <file>    Apre un file nell'editor
+/edit <file:line> Salta a una riga specifica del file
+/init           Genera la mappa del progetto (.deecli/PROJECT.md)
+/pr review <n>  Carica il diff di una PR GitHub o MR GitLab e la revisiona
+/review <a>..<b> Revisiona il diff tra due ref git, risultati per file
+/compact [n]    Riassume la conversazione, tenendo gli ultimi n turni (predefinito 2)
+/suggest        Suggerisce modifiche dalla conversazione (/suggest open|prompt|fix <n>)
+/send --n <k> <prompt> Chiede k risposte (fino a 5) e tiene quella scelta
+/docs <pkg>     Documenta pacchetti Go come diff per /apply (--check: segnala i problemi, go vet)
+/git commit     Fa il commit dei file modificati nella sessione (-m auto|<msg>, --push)
+/config         Mostra/gestisce la configurazione
+/mode <nome>    Preset di temperatura: coding, general, creative o off (--prompt)
+/postprocess    Elenca i post-processori delle risposte (/postprocess <nome> on|off)
+/keysetup       Configura i tasti
+/history        Mostra/gestisce la cronologia dei comandi del progetto (show|clear|search [--all] <term>)
+/session        Elenca le sessioni recenti (/session title <testo> per rinominare)
+/fork [titolo]  Continua in una copia di questa sessione, mantenendo l'originale
+/tab            Elenca le schede di chat (/tab new [nome]|<n>|rename <nome>|close)
+/tasks          Elenca le attività in background come /analyze (/tasks cancel [n])
+/share          Esporta la conversazione, con i segreti oscurati (/share file <percorso>|gist)
+/errors         Mostra gli errori recenti con i codici (/errors all|clear)
+/audit          Mostra le chiamate agli strumenti registrate (/audit all|<numero>)
+/stats          Mostra le statistiche d'uso locali (/stats reset|export [file])
+/sources        Mostra le chiamate agli strumenti su cui si basa l'ultima risposta
+/sections       Elenca titoli e blocchi di codice della chat (/sections <n> salta lì)
+/dryrun         Simula soltanto gli strumenti che scrivono file o eseguono comandi (/dryrun on|off)
+/help           Mostra questa guida
+/quit           Esce dall'applicazione
+
+=== Scorciatoie da tastiera ===
+Tab             Intelligente: mostra/accetta completamenti OPPURE cambia focus
+Invio           Invia il messaggio
+%s         Nuova riga nel messaggio
+↑ o %s     Cronologia precedente (solo input su una riga)
+↓ o %s     Cronologia successiva (solo input su una riga)
+F1              Mostra/nasconde questa guida
+F2              Mostra/nasconde la barra dei file
+F3              Cambia formato del codice (grezzo/bordato) per i nuovi messaggi
+F4              Mostra/nasconde modello, temperatura e token di ogni risposta
+Esc             Annulla la risposta AI o la catena di strumenti in corso
+Ctrl+C          Esce dall'applicazione
+Ctrl+←/→        Scheda di chat precedente/successiva
+Ctrl+W          Cancella la parola precedente
+Ctrl+U/K        Cancella fino a inizio/fine riga
+Alt+Backspace   Cancella la parola precedente (alternativa)
+
+=== Modalità di focus ===
+✏️ INPUT        Scrivi messaggi e comandi
+📜 CHAT         Scorri la cronologia della chat
+📁 FILES        Sfoglia i file caricati (con F2 aperto)
+
+Tab cambia focus: Input → Chat → File (se aperto) → Input
+
+=== Navigazione ===
+↑/↓             Scorre la vista/barra laterale OPPURE la cronologia nell'input (una riga)
+PgUp/PgDn       Pagina su/giù
+Ctrl+U/Ctrl+D   Mezza pagina su/giù
+Home/End        Vai all'inizio/alla fine
+[ / ]           Titolo o blocco di codice precedente/successivo nella chat
+s / g           File: cambia l'ordine (caricati, nome, dimensione, modificati) / raggruppa per cartella
+[ / ] Spazio    File: seleziona una cartella / la chiude o la apre
+Esc/Invio       Torna all'input
+
+Suggerimento: il bordo giallo indica il pannello attivo!
+
+=== Pattern dei file ===
+Puoi usare pattern glob per caricare più file:
+  /load *.go           Carica tutti i file .go
+  /load src/**/*.go    Carica tutti i file .go in src
+  /load {*.go,*.md}    Carica tutti i file .go e .md
+
+=== Suggerimenti ===
+• Messaggi su più righe: usa %s per andare a capo
+• Invio rapido: premi Invio per inviare il messaggio
+• Premi Tab (senza completamenti) per cambiare pannello
+• Le scorciatoie di modifica standard funzionano (Ctrl+W, Ctrl+U, Ctrl+K, ecc.)
+• Il bordo giallo indica il pannello attivo
+• Tab mostra i completamenti, usa ↑↓ per scorrerli, Tab/Invio per accettare, Esc per annullare
+• Le frecce scorrono il pannello attivo
+• Premi Esc per tornare subito all'input
+
+Premi F1 per chiudere questa guida`,
+
+	// Loading indicator
+	"loading.thinking":          "Sto pensando...",
+	"loading.continuing":        "Continuo...",
+	"loading.cancel_hint":       "Premi Esc per annullare",
+	"loading.cancel_hint_short": "Esc per annullare",
+	"loading.cancel_hint_aloud": "Premi Escape per annullare.",
+	"stream.stalled":            "⚠️ Stream bloccato, nessun dato ricevuto. Premi r per riprovare",
+	"stream.reconnecting":       "🔄 Stream bloccato, invio di nuovo la richiesta...",
+	"stream.truncated":          "_(interrotta dall'utente)_",
+	"empty.retrying":            "⚠️ Il modello ha inviato una risposta vuota, chiedo di nuovo...",
+	"empty.failed":              "Il modello ha inviato di nuovo una risposta vuota. Prova a riformulare il prompt, o cambia modello con /config set model",
+	"overflow.retrying":         "✂️ Richiesta troppo lunga per il modello, la invio di nuovo con %s. /compact accorcia la conversazione in modo permanente",
+	"overflow.files":            "il contesto dei file ridotto da %d KB a %d KB",
+	"overflow.history":          "i %d messaggi più vecchi esclusi",
+	"context.near_limit":        "Contesto pieno al %d%%, prova %s",
+	"context.or":                " o ",
+	"scrollback.placeholder":    "… %d messaggi precedenti (premi PagSu per caricarli)",
+	"queue.label":               "In coda (↑ per modificare)",
+	"queue.restored":            "↩️ Messaggi in coda riportati nell'input",
+	"tasks.label":               "Attività (/tasks cancel <n> la ferma)",
+	"duplicate.ignored":         "⏭️ Quel prompt ha già una risposta in corso o è in coda, quindi non è stato inviato di nuovo",
+	"duplicate.confirm":         "🔁 Stesso prompt dell'ultimo. Premi di nuovo Invio per inviarlo comunque",
+
+	// Status messages
+	"status.request_cancelled":    "🚫 Richiesta annullata",
+	"status.partial_kept":         "🚫 Richiesta annullata; la risposta parziale resta nella conversazione",
+	"status.tool_chain_cancelled": "🚫 Catena di strumenti annullata",
+	"status.tool_calls_discarded": " (%d chiamate in coda scartate)",
+	"status.ready":                ". Pronto per il prossimo messaggio.",
+	"status.editor_closed":        "✓ Editor chiuso",
+	"status.editor_context":       "📍 Aggiunto all'input il punto in cui stavi modificando (%s)",
+	"status.api_key_missing":      "Imposta la variabile d'ambiente DEEPSEEK_API_KEY",
+	"status.plain_no_sidebar":     "La barra dei file non è disponibile in modalità semplice. Usa /list per vedere i file caricati.",
+	"status.annotations_on":       "Mostro modello, temperatura e token sotto ogni risposta (F4 per nascondere)",
+	"status.annotations_off":      "Annotazioni delle risposte nascoste",
+
+	// File auto-reload
+	"reload.auto_reloaded":      "📁 Ricaricati automaticamente %d file modificati",
+	"reload.setup_failed":       "⚠️ Impossibile attivare il ricaricamento automatico: %v",
+	"reload.unsupported":        "ℹ️ Il ricaricamento automatico non è disponibile su questa piattaforma.\n   Usa il comando /reload per ricaricare manualmente i file modificati.",
+	"reload.unsupported_reason": "   Motivo: %v",
+	"reload.failed":             "⚠️ Ricaricamento automatico dei file non riuscito: %v",
+	"reload.after_edit":         "🔄 Ricaricati automaticamente %d file, %d modificati",
+
+	// Proxy and TLS settings
+	"network.setup_failed": "⚠️ Impostazioni proxy/TLS non applicate: %v",
+	"network.insecure":     "⚠️ ATTENZIONE: la verifica dei certificati TLS è disattivata (insecure_skip_verify).\n   Chiunque sul percorso di rete può leggere e alterare il traffico API, compresa la tua chiave API.",
+
+	// Startup update check (check_updates)
+	"update.available": "🆕 È disponibile DeeCLI %s (hai la %s). Esegui 'deecli update' per vedere le novità e installarla.",
+
+	// Restricted mode in an untrusted directory
+	"trust.restricted": "🔒 Modalità ristretta: %s non è attendibile, quindi il suo .deecli/config.yaml viene ignorato e gli strumenti esterni e i plugin sono disattivati. Esegui 'deecli trust' nella directory per renderla attendibile.",
+
+	// Tool call limits (tool_calls_per_turn, tool_calls_per_minute)
+	"tools.limit_reached": "⛔ Catena di strumenti interrotta: è stato raggiunto il limite di %s. Al modello è stato chiesto di rispondere con ciò che ha; alza il limite con /config set se le chiamate erano legittime.",
+	"tools.loop_detected": "🔁 Catena di strumenti interrotta: il modello ha richiesto %s con gli stessi argomenti %d volte. Gli è stato chiesto di rispondere con i risultati che ha già.",
+
+	// Pending AI diffs re-checked after a reload
+	"suggestions.applied":  "✅ %s è cambiato su disco e ora contiene la modifica suggerita",
+	"suggestions.rebased":  "⚠️ %s è cambiato su disco; la modifica suggerita in sospeso è stata ricontrollata e si applica ancora al nuovo contenuto",
+	"suggestions.conflict": "⚠️ %s è cambiato su disco e la modifica suggerita in sospeso non si applica più (%s). Chiedi una modifica aggiornata",
+	// Configuration
+	"config.language_set":     "✅ Lingua impostata a: %s",
+	"config.language_get":     "Lingua: %s",
+	"config.language_invalid": "Lingua non valida: %s",
+}
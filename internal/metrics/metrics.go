@@ -0,0 +1,264 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package metrics keeps anonymous usage statistics on this machine: how
+// often each command is used and how long API requests take. Only names
+// chosen by DeeCLI are recorded, never arguments, file names, prompts or
+// answers. Nothing in this package sends data anywhere; the statistics
+// leave the machine only if the user exports them and shares the file.
+package metrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// BucketBounds are the upper bounds, in milliseconds, of the latency
+// histogram; a last bucket counts the slower requests
+var BucketBounds = []int64{250, 500, 1000, 2000, 5000, 10000, 30000, 60000}
+
+// Stats are the statistics collected since Since
+type Stats struct {
+	Since    time.Time          `json:"since"`
+	Commands map[string]int     `json:"commands"` // Uses by command, e.g. "/load" or "deecli review"
+	Requests map[string]*Timing `json:"requests"` // API requests by workflow, e.g. "chat" or "review"
+}
+
+// Timing summarizes the latency of a kind of request
+type Timing struct {
+	Count    int     `json:"count"`
+	Failures int     `json:"failures"`
+	TotalMs  int64   `json:"total_ms"`
+	MaxMs    int64   `json:"max_ms"`
+	Buckets  []int64 `json:"buckets"` // Requests per BucketBounds bucket, the slower ones last
+}
+
+// add counts a request that took latency
+func (t *Timing) add(latency time.Duration, failed bool) {
+	ms := latency.Milliseconds()
+	if len(t.Buckets) != len(BucketBounds)+1 {
+		t.Buckets = make([]int64, len(BucketBounds)+1)
+	}
+	t.Count++
+	if failed {
+		t.Failures++
+	}
+	t.TotalMs += ms
+	t.MaxMs = max(t.MaxMs, ms)
+	t.Buckets[bucket(ms)]++
+}
+
+// Average returns the mean latency
+func (t *Timing) Average() time.Duration {
+	if t.Count == 0 {
+		return 0
+	}
+	return time.Duration(t.TotalMs/int64(t.Count)) * time.Millisecond
+}
+
+// Percentile returns the upper bound of the histogram bucket holding the
+// p-th percentile, or the slowest latency seen for the last bucket
+func (t *Timing) Percentile(p float64) time.Duration {
+	if t.Count == 0 {
+		return 0
+	}
+	rank := int64(p/100*float64(t.Count) + 0.5)
+	rank = max(rank, 1)
+	var seen int64
+	for i, n := range t.Buckets {
+		seen += n
+		if seen >= rank {
+			if i < len(BucketBounds) {
+				return time.Duration(min(BucketBounds[i], t.MaxMs)) * time.Millisecond
+			}
+			break
+		}
+	}
+	return time.Duration(t.MaxMs) * time.Millisecond
+}
+
+// bucket returns the histogram bucket of a latency in milliseconds
+func bucket(ms int64) int {
+	for i, bound := range BucketBounds {
+		if ms <= bound {
+			return i
+		}
+	}
+	return len(BucketBounds)
+}
+
+// CommandNames returns the recorded commands, most used first
+func (s *Stats) CommandNames() []string {
+	names := make([]string, 0, len(s.Commands))
+	for name := range s.Commands {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if s.Commands[names[i]] != s.Commands[names[j]] {
+			return s.Commands[names[i]] > s.Commands[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+// Workflows returns the workflows with recorded requests, by name
+func (s *Stats) Workflows() []string {
+	names := make([]string, 0, len(s.Requests))
+	for name := range s.Requests {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DefaultPath returns the file statistics are kept in
+func DefaultPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".deecli", "stats.json")
+}
+
+// Recorder adds events to the statistics file. A nil Recorder records
+// nothing, so callers need not check whether statistics are enabled.
+type Recorder struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewRecorder returns a recorder writing to path, or nil if path is ""
+func NewRecorder(path string) *Recorder {
+	if path == "" {
+		return nil
+	}
+	return &Recorder{path: path}
+}
+
+// Path returns the statistics file
+func (r *Recorder) Path() string {
+	if r == nil {
+		return ""
+	}
+	return r.path
+}
+
+// Command counts a use of a command; name must be chosen by DeeCLI, such
+// as "/load", never typed by the user
+func (r *Recorder) Command(name string) {
+	r.update(func(s *Stats) {
+		s.Commands[name]++
+	})
+}
+
+// Request counts an API request of workflow that took latency
+func (r *Recorder) Request(workflow string, latency time.Duration, failed bool) {
+	r.update(func(s *Stats) {
+		timing := s.Requests[workflow]
+		if timing == nil {
+			timing = &Timing{}
+			s.Requests[workflow] = timing
+		}
+		timing.add(latency, failed)
+	})
+}
+
+// update applies change to the statistics file. Each event is written at
+// once, so statistics from several running chats add up and survive a crash.
+func (r *Recorder) update(change func(*Stats)) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stats, err := Load(r.path)
+	if err != nil {
+		return // A damaged file is replaced by /stats reset, not overwritten here
+	}
+	change(stats)
+	save(r.path, stats)
+}
+
+// Load reads the statistics at path; a missing file gives empty statistics
+func Load(path string) (*Stats, error) {
+	stats := &Stats{Since: time.Now(), Commands: map[string]int{}, Requests: map[string]*Timing{}}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return stats, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, stats); err != nil {
+		return nil, fmt.Errorf("invalid statistics file %s: %w", path, err)
+	}
+	if stats.Commands == nil {
+		stats.Commands = map[string]int{}
+	}
+	if stats.Requests == nil {
+		stats.Requests = map[string]*Timing{}
+	}
+	return stats, nil
+}
+
+// Reset removes the statistics at path
+func Reset(path string) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// Export writes the statistics at path to target as indented JSON, to be
+// shared by hand; it returns the statistics written
+func Export(path, target string) (*Stats, error) {
+	stats, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.MarshalIndent(stats, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return stats, os.WriteFile(target, append(data, '\n'), 0644)
+}
+
+// save writes stats to path, replacing the file in one step
+func save(path string, stats *Stats) error {
+	data, err := json.Marshal(stats)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".stats-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
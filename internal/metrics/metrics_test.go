@@ -0,0 +1,118 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRecorder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stats.json")
+	r := NewRecorder(path)
+	r.Command("/load")
+	r.Command("/load")
+	r.Command("/help")
+	r.Request("chat", 300*time.Millisecond, false)
+	r.Request("chat", 1500*time.Millisecond, true)
+
+	// A second recorder, as in another running chat, adds to the same file
+	NewRecorder(path).Command("/help")
+
+	stats, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if stats.Commands["/load"] != 2 || stats.Commands["/help"] != 2 {
+		t.Errorf("unexpected commands: %v", stats.Commands)
+	}
+	if names := stats.CommandNames(); len(names) != 2 || names[0] != "/help" {
+		t.Errorf("CommandNames() = %v, want /help first", names)
+	}
+	chat := stats.Requests["chat"]
+	if chat == nil || chat.Count != 2 || chat.Failures != 1 || chat.MaxMs != 1500 {
+		t.Fatalf("unexpected chat timing: %+v", chat)
+	}
+	if got := chat.Average(); got != 900*time.Millisecond {
+		t.Errorf("Average() = %v, want 900ms", got)
+	}
+
+	if err := Reset(path); err != nil {
+		t.Fatalf("Reset() error = %v", err)
+	}
+	if stats, _ := Load(path); len(stats.Commands) != 0 {
+		t.Errorf("expected no commands after a reset, got %v", stats.Commands)
+	}
+}
+
+func TestNilRecorder(t *testing.T) {
+	var r *Recorder
+	r.Command("/load")
+	r.Request("chat", time.Second, false)
+	if NewRecorder("") != nil {
+		t.Error("expected no recorder without a path")
+	}
+}
+
+func TestTiming_Percentile(t *testing.T) {
+	var timing Timing
+	for _, ms := range []int64{100, 200, 300, 400, 600, 700, 800, 900, 4000, 90000} {
+		timing.add(time.Duration(ms)*time.Millisecond, false)
+	}
+	tests := []struct {
+		p    float64
+		want time.Duration
+	}{
+		{50, time.Second},
+		{20, 250 * time.Millisecond},
+		{90, 5 * time.Second},
+		{100, 90 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := timing.Percentile(tt.p); got != tt.want {
+			t.Errorf("Percentile(%v) = %v, want %v", tt.p, got, tt.want)
+		}
+	}
+}
+
+func TestLoad_Damaged(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stats.json")
+	os.WriteFile(path, []byte("{not json"), 0644)
+	if _, err := Load(path); err == nil {
+		t.Error("expected an error for a damaged file")
+	}
+
+	// Events are dropped rather than overwriting the damaged file
+	NewRecorder(path).Command("/load")
+	if data, _ := os.ReadFile(path); string(data) != "{not json" {
+		t.Errorf("damaged file overwritten: %s", data)
+	}
+}
+
+func TestExport(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "stats.json")
+	NewRecorder(path).Command("/review")
+
+	target := filepath.Join(dir, "export.json")
+	stats, err := Export(path, target)
+	if err != nil || stats.Commands["/review"] != 1 {
+		t.Fatalf("Export() = %+v, %v", stats, err)
+	}
+	if exported, err := Load(target); err != nil || exported.Commands["/review"] != 1 {
+		t.Errorf("exported file does not load back: %+v, %v", exported, err)
+	}
+}
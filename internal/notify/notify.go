@@ -0,0 +1,91 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notify
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// Notification modes for the notify_on_complete setting
+const (
+	ModeOff     = "off"
+	ModeBell    = "bell"
+	ModeDesktop = "desktop"
+	ModeBoth    = "both"
+)
+
+// bellWriter is where the terminal bell is written
+var bellWriter io.Writer = os.Stderr
+
+// Send delivers a completion notification according to mode
+func Send(mode, title, body string) error {
+	switch mode {
+	case ModeBell:
+		return Bell()
+	case ModeDesktop:
+		return Desktop(title, body)
+	case ModeBoth:
+		Bell()
+		return Desktop(title, body)
+	default:
+		return nil
+	}
+}
+
+// Bell rings the terminal bell
+func Bell() error {
+	_, err := fmt.Fprint(bellWriter, "\a")
+	return err
+}
+
+// Desktop shows an OS desktop notification using the platform's native tool
+func Desktop(title, body string) error {
+	var c *exec.Cmd
+
+	switch runtime.GOOS {
+	case "darwin":
+		script := fmt.Sprintf("display notification %q with title %q", body, title)
+		c = exec.Command("osascript", "-e", script)
+	case "windows":
+		script := fmt.Sprintf(`[reflection.assembly]::loadwithpartialname('System.Windows.Forms') | Out-Null;`+
+			`$n = New-Object System.Windows.Forms.NotifyIcon;`+
+			`$n.Icon = [System.Drawing.SystemIcons]::Information;`+
+			`$n.Visible = $true;`+
+			`$n.ShowBalloonTip(5000, '%s', '%s', 'Info')`,
+			escapePowerShell(title), escapePowerShell(body))
+		c = exec.Command("powershell", "-NoProfile", "-Command", script)
+	default:
+		if _, err := exec.LookPath("notify-send"); err != nil {
+			return fmt.Errorf("notify-send not found, install libnotify for desktop notifications")
+		}
+		c = exec.Command("notify-send", "--app-name=DeeCLI", title, body)
+	}
+
+	// Don't block the UI waiting for the notifier
+	if err := c.Start(); err != nil {
+		return err
+	}
+	go c.Wait()
+	return nil
+}
+
+// escapePowerShell escapes single quotes for a single-quoted PowerShell string
+func escapePowerShell(s string) string {
+	return strings.ReplaceAll(s, "'", "''")
+}
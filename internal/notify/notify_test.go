@@ -0,0 +1,55 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notify
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSend_Bell(t *testing.T) {
+	var buf bytes.Buffer
+	oldWriter := bellWriter
+	bellWriter = &buf
+	defer func() { bellWriter = oldWriter }()
+
+	if err := Send(ModeBell, "DeeCLI", "done"); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if buf.String() != "\a" {
+		t.Errorf("expected bell character, got %q", buf.String())
+	}
+}
+
+func TestSend_Off(t *testing.T) {
+	var buf bytes.Buffer
+	oldWriter := bellWriter
+	bellWriter = &buf
+	defer func() { bellWriter = oldWriter }()
+
+	for _, mode := range []string{ModeOff, ""} {
+		if err := Send(mode, "DeeCLI", "done"); err != nil {
+			t.Errorf("Send(%q) error = %v", mode, err)
+		}
+	}
+	if buf.Len() != 0 {
+		t.Errorf("expected no output when notifications are off, got %q", buf.String())
+	}
+}
+
+func TestEscapePowerShell(t *testing.T) {
+	if got := escapePowerShell("it's done"); got != "it''s done" {
+		t.Errorf("escapePowerShell() = %q", got)
+	}
+}
@@ -63,10 +63,11 @@ func (m *Manager) SetPermission(functionName, projectPath string, level tools.Pe
 		cfg.ToolPermissions = make(map[string]config.ToolPermission)
 	}
 
-	// Set the permission
+	// Set the permission, keeping any capabilities already granted
 	cfg.ToolPermissions[functionName] = config.ToolPermission{
-		Level:     string(level),
-		UpdatedAt: time.Now().Unix(),
+		Level:        string(level),
+		UpdatedAt:    time.Now().Unix(),
+		Capabilities: cfg.ToolPermissions[functionName].Capabilities,
 	}
 
 	// Save to project config (permissions are project-specific)
@@ -86,6 +87,43 @@ func (m *Manager) RequestApproval(request tools.ApprovalRequest) (tools.Approval
 	return m.approvalHandler.RequestApproval(request)
 }
 
+// MissingCapabilities returns the declared capabilities of a sandboxed tool
+// that have not been granted in the current project
+func (m *Manager) MissingCapabilities(functionName string, declared []tools.Capability) []tools.Capability {
+	var granted []tools.Capability
+	if permission, exists := m.configManager.Get().ToolPermissions[functionName]; exists {
+		for _, c := range permission.Capabilities {
+			granted = append(granted, tools.Capability(c))
+		}
+	}
+
+	var missing []tools.Capability
+	for _, c := range declared {
+		if !tools.HasCapability(granted, c) {
+			missing = append(missing, c)
+		}
+	}
+	return missing
+}
+
+// GrantCapabilities records the capabilities granted to a sandboxed tool in the current project
+func (m *Manager) GrantCapabilities(functionName string, caps []tools.Capability) error {
+	cfg := m.configManager.Get()
+	if cfg.ToolPermissions == nil {
+		cfg.ToolPermissions = make(map[string]config.ToolPermission)
+	}
+
+	permission := cfg.ToolPermissions[functionName]
+	permission.Capabilities = nil
+	for _, c := range caps {
+		permission.Capabilities = append(permission.Capabilities, string(c))
+	}
+	permission.UpdatedAt = time.Now().Unix()
+	cfg.ToolPermissions[functionName] = permission
+
+	return m.configManager.SaveProject(cfg)
+}
+
 // GetAllPermissions returns all permissions for the current project
 func (m *Manager) GetAllPermissions() []tools.ToolPermission {
 	cfg := m.configManager.Get()
@@ -0,0 +1,109 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redact
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Rule describes a named secret pattern
+type Rule struct {
+	Name    string
+	Pattern *regexp.Regexp
+}
+
+// DefaultRules are the built-in patterns for obvious secrets
+var DefaultRules = []Rule{
+	{Name: "private key", Pattern: regexp.MustCompile(`-----BEGIN [A-Z0-9 ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z0-9 ]*PRIVATE KEY-----`)},
+	{Name: "AWS access key", Pattern: regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{Name: "AWS secret key", Pattern: regexp.MustCompile(`(?i)aws_secret_access_key\s*[:=]\s*["']?[A-Za-z0-9/+=]{40}["']?`)},
+	{Name: "bearer token", Pattern: regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9\-._~+/]{16,}=*`)},
+	{Name: "GitHub token", Pattern: regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{36,}\b`)},
+	{Name: "API key", Pattern: regexp.MustCompile(`\bsk-[A-Za-z0-9_\-]{20,}\b`)},
+}
+
+// Redactor masks secrets in text and keeps a tally of what it replaced
+type Redactor struct {
+	rules  []Rule
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+// New creates a redactor with the default rules plus extra regular expressions
+func New(extraPatterns []string) (*Redactor, error) {
+	rules := make([]Rule, len(DefaultRules), len(DefaultRules)+len(extraPatterns))
+	copy(rules, DefaultRules)
+
+	for _, pattern := range extraPatterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %q: %w", pattern, err)
+		}
+		rules = append(rules, Rule{Name: "custom pattern", Pattern: re})
+	}
+
+	return &Redactor{
+		rules:  rules,
+		counts: make(map[string]int),
+	}, nil
+}
+
+// Redact replaces every secret in text with a [REDACTED:<name>] marker
+func (r *Redactor) Redact(text string) string {
+	if r == nil || text == "" {
+		return text
+	}
+
+	for _, rule := range r.rules {
+		matches := 0
+		text = rule.Pattern.ReplaceAllStringFunc(text, func(string) string {
+			matches++
+			return fmt.Sprintf("[REDACTED:%s]", rule.Name)
+		})
+		if matches > 0 {
+			r.mu.Lock()
+			r.counts[rule.Name] += matches
+			r.mu.Unlock()
+		}
+	}
+
+	return text
+}
+
+// TakeSummary returns a description of what was redacted since the last call and resets the tally
+func (r *Redactor) TakeSummary() string {
+	if r == nil {
+		return ""
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.counts) == 0 {
+		return ""
+	}
+
+	var parts []string
+	for name, count := range r.counts {
+		parts = append(parts, fmt.Sprintf("%d %s", count, name))
+	}
+	sort.Strings(parts)
+	r.counts = make(map[string]int)
+
+	return strings.Join(parts, ", ")
+}
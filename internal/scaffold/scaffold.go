@@ -0,0 +1,249 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package scaffold renders the starting content of new files from templates
+// chosen by path, with the project's license header and, for Go, the package
+// name of the directory.
+package scaffold
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/antenore/deecli/internal/config"
+)
+
+// Data is what a template can use
+type Data struct {
+	Path     string // Path of the new file, as given
+	FileName string // Base name, e.g. "server.go"
+	Name     string // Base name without extension, e.g. "server"
+	Package  string // Go package name inferred from the directory
+	Year     int
+	License  string // License header commented for the file's language and followed by a blank line; empty if none
+}
+
+// builtin are the templates used when no configured template matches, by
+// extension
+var builtin = map[string]string{
+	".go": "{{.License}}package {{.Package}}\n\n// TODO: Implement based on AI suggestions\n",
+	".py": "#!/usr/bin/env python3\n\n{{.License}}# TODO: Implement based on AI suggestions\n",
+	".js": "{{.License}}// TODO: Implement based on AI suggestions\n",
+	".ts": "{{.License}}// TODO: Implement based on AI suggestions\n",
+	".sh": "#!/bin/bash\n\n{{.License}}# TODO: Implement based on AI suggestions\n",
+}
+
+// fallback is the template of files with an extension builtin does not know
+const fallback = "{{.License}}# New file created by DeeCLI\n# TODO: Implement based on AI suggestions\n"
+
+// commentPrefixes are the line comment markers license headers get, by
+// extension; files of other types get no header
+var commentPrefixes = map[string]string{
+	".go": "//", ".js": "//", ".jsx": "//", ".ts": "//", ".tsx": "//",
+	".java": "//", ".c": "//", ".h": "//", ".cpp": "//", ".hpp": "//", ".cc": "//",
+	".cs": "//", ".rs": "//", ".swift": "//", ".kt": "//", ".scala": "//", ".php": "//",
+	".py": "#", ".sh": "#", ".bash": "#", ".rb": "#", ".pl": "#", ".r": "#",
+	".yaml": "#", ".yml": "#", ".toml": "#",
+	".sql": "--", ".lua": "--",
+}
+
+// Scaffolder creates new files from templates
+type Scaffolder struct {
+	Templates     []config.FileTemplate // Tried in order; the first whose pattern matches is used
+	LicenseHeader string                // File with the license header text; empty for none
+}
+
+// New returns a scaffolder with the templates and license header of the
+// configuration, or with the built-in templates only if cm is nil
+func New(cm *config.Manager) *Scaffolder {
+	if cm == nil {
+		return &Scaffolder{}
+	}
+	return &Scaffolder{Templates: cm.GetFileTemplates(), LicenseHeader: cm.GetLicenseHeader()}
+}
+
+// Create writes the scaffold of the file at path, creating its directory
+func (s *Scaffolder) Create(path string) error {
+	content, err := s.Render(path)
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create directory: %w", err)
+		}
+	}
+	return os.WriteFile(path, []byte(content), 0644)
+}
+
+// Render returns the starting content of a new file at path
+func (s *Scaffolder) Render(path string) (string, error) {
+	source, name, err := s.template(path)
+	if err != nil {
+		return "", err
+	}
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(source)
+	if err != nil {
+		return "", fmt.Errorf("invalid template %s: %w", name, err)
+	}
+
+	data := Data{
+		Path:     path,
+		FileName: filepath.Base(path),
+		Name:     strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)),
+		Year:     time.Now().Year(),
+	}
+	if strings.HasSuffix(path, ".go") {
+		data.Package = PackageName(path)
+	}
+	if data.License, err = s.license(path); err != nil {
+		return "", err
+	}
+
+	var out strings.Builder
+	if err := tmpl.Execute(&out, data); err != nil {
+		return "", fmt.Errorf("template %s: %w", name, err)
+	}
+	return out.String(), nil
+}
+
+// template returns the template source for path and a name to report errors with
+func (s *Scaffolder) template(filePath string) (string, string, error) {
+	for _, t := range s.Templates {
+		if !Matches(t.Pattern, filePath) {
+			continue
+		}
+		if t.Template != "" || t.File == "" {
+			return t.Template, t.Pattern, nil
+		}
+		data, err := os.ReadFile(t.File)
+		if err != nil {
+			return "", "", fmt.Errorf("error reading template for %s: %w", t.Pattern, err)
+		}
+		return string(data), t.File, nil
+	}
+	ext := strings.ToLower(filepath.Ext(filePath))
+	if source, ok := builtin[ext]; ok {
+		return source, "built-in " + ext, nil
+	}
+	return fallback, "built-in", nil
+}
+
+// license returns the license header commented for the type of path
+func (s *Scaffolder) license(filePath string) (string, error) {
+	prefix := commentPrefixes[strings.ToLower(filepath.Ext(filePath))]
+	if s.LicenseHeader == "" || prefix == "" {
+		return "", nil
+	}
+	data, err := os.ReadFile(s.LicenseHeader)
+	if err != nil {
+		return "", fmt.Errorf("error reading license header: %w", err)
+	}
+	text := strings.TrimSpace(strings.ReplaceAll(string(data), "\r\n", "\n"))
+	if text == "" {
+		return "", nil
+	}
+
+	var b strings.Builder
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimRight(line, " \t"); line == "" {
+			b.WriteString(prefix + "\n")
+		} else {
+			b.WriteString(prefix + " " + line + "\n")
+		}
+	}
+	b.WriteString("\n")
+	return b.String(), nil
+}
+
+// Matches reports whether pattern matches filePath. A pattern with a slash
+// is matched against the whole slash-separated path, others against the base
+// name; "**/" matches any number of leading directories.
+func Matches(pattern, filePath string) bool {
+	filePath = filepath.ToSlash(filepath.Clean(filePath))
+	if !strings.Contains(pattern, "/") {
+		ok, _ := path.Match(pattern, path.Base(filePath))
+		return ok
+	}
+	if rest, ok := strings.CutPrefix(pattern, "**/"); ok {
+		parts := strings.Split(filePath, "/")
+		for i := range parts {
+			if Matches(rest, strings.Join(parts[i:], "/")) {
+				return true
+			}
+		}
+		return false
+	}
+	ok, _ := path.Match(pattern, filePath)
+	return ok
+}
+
+// packageClause finds the package clause of a Go file
+var packageClause = regexp.MustCompile(`^package\s+([A-Za-z_][A-Za-z0-9_]*)`)
+
+// PackageName infers the package of a new Go file at path: the package of
+// the other Go files in its directory, otherwise main at a module root or
+// under cmd/ and the directory name elsewhere
+func PackageName(filePath string) string {
+	dir := filepath.Dir(filePath)
+	matches, _ := filepath.Glob(filepath.Join(dir, "*.go"))
+	for _, match := range matches {
+		if filepath.Clean(match) == filepath.Clean(filePath) {
+			continue
+		}
+		if name := readPackage(match); name != "" {
+			return strings.TrimSuffix(name, "_test")
+		}
+	}
+
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "main"
+	}
+	if _, err := os.Stat(filepath.Join(abs, "go.mod")); err == nil || filepath.Base(filepath.Dir(abs)) == "cmd" {
+		return "main"
+	}
+	var name strings.Builder
+	for _, r := range strings.ToLower(filepath.Base(abs)) {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '_' {
+			name.WriteRune(r)
+		}
+	}
+	if name.Len() == 0 || name.String()[0] >= '0' && name.String()[0] <= '9' {
+		return "main"
+	}
+	return name.String()
+}
+
+// readPackage returns the package named by the Go file at path, or ""
+func readPackage(path string) string {
+	file, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if m := packageClause.FindStringSubmatch(scanner.Text()); m != nil {
+			return m[1]
+		}
+	}
+	return ""
+}
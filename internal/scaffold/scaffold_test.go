@@ -0,0 +1,127 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scaffold
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/antenore/deecli/internal/config"
+)
+
+func TestMatches(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"*.go", "internal/server/rpc.go", true},
+		{"*_test.go", "internal/server/rpc.go", false},
+		{"internal/*/*.go", "internal/server/rpc.go", true},
+		{"cmd/*.go", "internal/server/rpc.go", false},
+		{"**/handlers/*.go", "internal/api/handlers/user.go", true},
+		{"**/handlers/*.go", "handlers/user.go", true},
+		{"**/handlers/*.go", "internal/api/user.go", false},
+	}
+	for _, tt := range tests {
+		if got := Matches(tt.pattern, tt.path); got != tt.want {
+			t.Errorf("Matches(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestPackageName(t *testing.T) {
+	root := t.TempDir()
+	write := func(rel, content string) {
+		t.Helper()
+		path := filepath.Join(root, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("go.mod", "module example.com/app\n")
+	write("store/db.go", "// Package storage keeps records\npackage storage\n")
+	write("api/api_test.go", "package api_test\n")
+
+	tests := map[string]string{
+		"store/cache.go":     "storage", // Existing files win over the directory name
+		"api/handler.go":     "api",     // External test packages name the package under test
+		"my-utils/helper.go": "myutils",
+		"main.go":            "main",
+		"cmd/serve/serve.go": "main",
+		"2fa/otp.go":         "main",
+	}
+	for rel, want := range tests {
+		if got := PackageName(filepath.Join(root, rel)); got != want {
+			t.Errorf("PackageName(%s) = %q, want %q", rel, got, want)
+		}
+	}
+}
+
+func TestScaffolder_Render(t *testing.T) {
+	dir := t.TempDir()
+	header := filepath.Join(dir, "HEADER")
+	if err := os.WriteFile(header, []byte("Copyright 2025 Example\n\nLicensed under MIT\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	handler := filepath.Join(dir, "handler.tmpl")
+	if err := os.WriteFile(handler, []byte("{{.License}}package {{.Package}}\n\n// {{.Name}} handles requests\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	s := &Scaffolder{
+		Templates: []config.FileTemplate{
+			{Pattern: "**/handlers/*.go", File: handler},
+			{Pattern: "*.md", Template: "# {{.Name}}\n"},
+		},
+		LicenseHeader: header,
+	}
+
+	got, err := s.Render(filepath.Join(dir, "api", "handlers", "user.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "// Copyright 2025 Example\n//\n// Licensed under MIT\n\npackage handlers\n\n// user handles requests\n"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	if got, _ := s.Render(filepath.Join(dir, "README.md")); got != "# README\n" {
+		t.Errorf("expected the inline template without a header, got %q", got)
+	}
+	if got, _ := s.Render(filepath.Join(dir, "run.py")); !strings.HasPrefix(got, "#!/usr/bin/env python3\n\n# Copyright 2025 Example\n") {
+		t.Errorf("expected the built-in template with a # header, got %q", got)
+	}
+
+	s.Templates = []config.FileTemplate{{Pattern: "*.go", Template: "{{.Missing}}"}}
+	if _, err := s.Render(filepath.Join(dir, "x.go")); err == nil {
+		t.Error("expected an unknown field to fail")
+	}
+}
+
+func TestScaffolder_Create(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "newpkg", "thing.go")
+	if err := (&Scaffolder{}).Create(path); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || !strings.HasPrefix(string(data), "package newpkg\n") {
+		t.Errorf("expected the built-in Go template, got %q, %v", data, err)
+	}
+}
@@ -0,0 +1,303 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/antenore/deecli/internal/errlog"
+	"github.com/antenore/deecli/internal/files"
+)
+
+// JSON-RPC 2.0 error codes
+const (
+	rpcParseError     = -32700
+	rpcInvalidRequest = -32600
+	rpcMethodNotFound = -32601
+	rpcInvalidParams  = -32602
+	rpcServerError    = -32000
+)
+
+// maxRPCMessage limits the size of a single JSON-RPC message
+const maxRPCMessage = 8 << 20
+
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcNotification struct {
+	JSONRPC string `json:"jsonrpc"`
+	Method  string `json:"method"`
+	Params  any    `json:"params"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    any    `json:"data,omitempty"`
+}
+
+// StreamEvent is sent as an "event" notification while a response streams
+type StreamEvent struct {
+	Type    string `json:"type"` // "chunk", "done" or "error"
+	Content string `json:"content,omitempty"`
+	Error   string `json:"error,omitempty"`
+	Code    string `json:"code,omitempty"`
+}
+
+// rpcConn holds the state of one stdio connection
+type rpcConn struct {
+	server    *Server
+	out       io.Writer
+	writeMu   sync.Mutex
+	streaming bool // Whether the client subscribed with streamEvents
+}
+
+// ServeRPC speaks newline-delimited JSON-RPC 2.0 on r and w until r is closed.
+// Requests are handled one at a time, in order.
+func (s *Server) ServeRPC(ctx context.Context, r io.Reader, w io.Writer) error {
+	conn := &rpcConn{server: s, out: w}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxRPCMessage)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		conn.handle(ctx, []byte(line))
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+	return scanner.Err()
+}
+
+func (c *rpcConn) handle(ctx context.Context, data []byte) {
+	var req rpcRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		c.reply(nil, nil, &rpcError{Code: rpcParseError, Message: "parse error: " + err.Error()})
+		return
+	}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		c.reply(req.ID, nil, &rpcError{Code: rpcInvalidRequest, Message: "invalid request"})
+		return
+	}
+
+	result, rpcErr := c.dispatch(ctx, req)
+	if len(req.ID) == 0 {
+		return // Notifications get no response
+	}
+	c.reply(req.ID, result, rpcErr)
+}
+
+func (c *rpcConn) dispatch(ctx context.Context, req rpcRequest) (any, *rpcError) {
+	s := c.server
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch req.Method {
+	case "initialize":
+		return map[string]any{
+			"name":    "deecli",
+			"methods": []string{"loadFiles", "listFiles", "clearFiles", "sendMessage", "streamEvents", "applyPatch", "resetConversation"},
+		}, nil
+
+	case "loadFiles":
+		var params struct {
+			Patterns []string `json:"patterns"`
+		}
+		if err := decodeParams(req.Params, &params); err != nil || len(params.Patterns) == 0 {
+			return nil, invalidParams("patterns is required")
+		}
+		entries, err := s.loadFiles(params.Patterns)
+		if err != nil {
+			return nil, serverError(err)
+		}
+		return map[string]any{"files": entries}, nil
+
+	case "listFiles":
+		return map[string]any{"files": s.fileEntries()}, nil
+
+	case "clearFiles":
+		s.fileContext.Clear()
+		return map[string]any{"files": []FileEntry{}}, nil
+
+	case "streamEvents":
+		var params struct {
+			Enabled *bool `json:"enabled"`
+		}
+		if err := decodeParams(req.Params, &params); err != nil {
+			return nil, invalidParams(err.Error())
+		}
+		c.streaming = params.Enabled == nil || *params.Enabled
+		return map[string]any{"enabled": c.streaming}, nil
+
+	case "sendMessage":
+		var params struct {
+			Message string `json:"message"`
+		}
+		if err := decodeParams(req.Params, &params); err != nil || strings.TrimSpace(params.Message) == "" {
+			return nil, invalidParams("message is required")
+		}
+
+		var onChunk func(string)
+		if c.streaming {
+			onChunk = func(delta string) {
+				c.notify("event", StreamEvent{Type: "chunk", Content: delta})
+			}
+		}
+		response, err := s.chat(ctx, strings.TrimSpace(params.Message), onChunk)
+		if err != nil {
+			if c.streaming {
+				c.notify("event", StreamEvent{Type: "error", Error: errlog.UserMessage(err), Code: errlog.Classify(err).Code()})
+			}
+			return nil, serverError(err)
+		}
+		if c.streaming {
+			c.notify("event", StreamEvent{Type: "done", Content: response})
+		}
+		return ChatResponse{Response: response}, nil
+
+	case "applyPatch":
+		var params struct {
+			Path  string `json:"path"`
+			Patch string `json:"patch"`
+		}
+		if err := decodeParams(req.Params, &params); err != nil || params.Path == "" || params.Patch == "" {
+			return nil, invalidParams("path and patch are required")
+		}
+		result, err := s.applyPatch(params.Path, params.Patch)
+		if err != nil {
+			return nil, serverError(err)
+		}
+		return result, nil
+
+	case "resetConversation":
+		s.resetConversation()
+		return map[string]any{"reset": true}, nil
+	}
+
+	return nil, &rpcError{Code: rpcMethodNotFound, Message: fmt.Sprintf("method not found: %s", req.Method)}
+}
+
+// applyPatch applies a unified diff to a file inside the working directory,
+// formats the result with the formatter of its language and refreshes it in
+// the context if it is loaded
+func (s *Server) applyPatch(path, patch string) (map[string]any, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	if rel, err := filepath.Rel(cwd, absPath); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return nil, fmt.Errorf("path is outside the working directory: %s", path)
+	}
+
+	original, err := os.ReadFile(absPath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	updated, err := files.ApplyPatch(string(original), patch)
+	if err != nil {
+		return nil, err
+	}
+	result := map[string]any{"path": path, "applied": true}
+	updated, formatter, formatErr := s.fileContext.FormatFile(absPath, updated)
+	if formatter != "" {
+		result["formatter"] = formatter
+	}
+	if formatErr != nil {
+		result["format_error"] = formatErr.Error()
+	}
+
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(absPath); err == nil {
+		mode = info.Mode().Perm()
+	} else if err := os.MkdirAll(filepath.Dir(absPath), 0755); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(absPath, []byte(updated), mode); err != nil {
+		return nil, err
+	}
+
+	for _, file := range s.fileContext.Files {
+		if file.Path == absPath {
+			_, err := s.fileContext.ReloadFiles([]string{file.RelPath})
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+func (c *rpcConn) reply(id json.RawMessage, result any, rpcErr *rpcError) {
+	if len(id) == 0 {
+		id = json.RawMessage("null")
+	}
+	c.write(rpcResponse{JSONRPC: "2.0", ID: id, Result: result, Error: rpcErr})
+}
+
+func (c *rpcConn) notify(method string, params any) {
+	c.write(rpcNotification{JSONRPC: "2.0", Method: method, Params: params})
+}
+
+func (c *rpcConn) write(v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	c.out.Write(append(data, '\n'))
+}
+
+func decodeParams(raw json.RawMessage, v any) error {
+	if len(raw) == 0 {
+		return nil
+	}
+	return json.Unmarshal(raw, v)
+}
+
+func invalidParams(message string) *rpcError {
+	return &rpcError{Code: rpcInvalidParams, Message: message}
+}
+
+func serverError(err error) *rpcError {
+	return &rpcError{
+		Code:    rpcServerError,
+		Message: errlog.UserMessage(err),
+		Data:    map[string]string{"code": errlog.Classify(err).Code()},
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// runRPC sends each request line and returns the decoded responses
+func runRPC(t *testing.T, s *Server, requests ...string) []map[string]any {
+	t.Helper()
+	var out bytes.Buffer
+	if err := s.ServeRPC(context.Background(), strings.NewReader(strings.Join(requests, "\n")), &out); err != nil {
+		t.Fatalf("ServeRPC() error = %v", err)
+	}
+
+	var responses []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var resp map[string]any
+		if err := json.Unmarshal([]byte(line), &resp); err != nil {
+			t.Fatalf("invalid JSON response %q: %v", line, err)
+		}
+		responses = append(responses, resp)
+	}
+	return responses
+}
+
+func errorCode(resp map[string]any) float64 {
+	if e, ok := resp["error"].(map[string]any); ok {
+		return e["code"].(float64)
+	}
+	return 0
+}
+
+func TestServeRPC_Protocol(t *testing.T) {
+	responses := runRPC(t, newTestServer(t, ""),
+		`{"jsonrpc":"2.0","id":1,"method":"initialize"}`,
+		`not json`,
+		`{"jsonrpc":"2.0","id":2,"method":"nope"}`,
+		`{"jsonrpc":"2.0","id":3,"method":"sendMessage","params":{"message":""}}`,
+		`{"jsonrpc":"2.0","method":"resetConversation"}`,
+		`{"jsonrpc":"2.0","id":4,"method":"streamEvents","params":{"enabled":true}}`,
+	)
+
+	if len(responses) != 5 {
+		t.Fatalf("got %d responses, want 5 (notifications get none)", len(responses))
+	}
+	if _, ok := responses[0]["result"]; !ok {
+		t.Errorf("initialize should return a result, got %v", responses[0])
+	}
+	if errorCode(responses[1]) != rpcParseError {
+		t.Errorf("malformed JSON should be a parse error, got %v", responses[1])
+	}
+	if errorCode(responses[2]) != rpcMethodNotFound {
+		t.Errorf("unknown method should be method-not-found, got %v", responses[2])
+	}
+	if errorCode(responses[3]) != rpcInvalidParams {
+		t.Errorf("empty message should be invalid params, got %v", responses[3])
+	}
+	if result, ok := responses[4]["result"].(map[string]any); !ok || result["enabled"] != true {
+		t.Errorf("streamEvents should enable streaming, got %v", responses[4])
+	}
+}
+
+func TestServeRPC_ApplyPatch(t *testing.T) {
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	if err := os.WriteFile("notes.txt", []byte("one\ntwo\nthree\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	patch := "@@ -1,3 +1,3 @@\n one\n-two\n+TWO\n three\n"
+	params, _ := json.Marshal(map[string]string{"path": "notes.txt", "patch": patch})
+	outside, _ := json.Marshal(map[string]string{"path": filepath.Join("..", "escape.txt"), "patch": patch})
+
+	s := newTestServer(t, "")
+	responses := runRPC(t, s,
+		`{"jsonrpc":"2.0","id":1,"method":"loadFiles","params":{"patterns":["notes.txt"]}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"applyPatch","params":`+string(params)+`}`,
+		`{"jsonrpc":"2.0","id":3,"method":"applyPatch","params":`+string(outside)+`}`,
+	)
+
+	if errorCode(responses[1]) != 0 {
+		t.Fatalf("applyPatch failed: %v", responses[1])
+	}
+	data, _ := os.ReadFile("notes.txt")
+	if string(data) != "one\nTWO\nthree\n" {
+		t.Errorf("file content = %q", data)
+	}
+	if s.fileContext.Files[0].Content != "one\nTWO\nthree\n" {
+		t.Errorf("loaded file was not refreshed: %q", s.fileContext.Files[0].Content)
+	}
+	if errorCode(responses[2]) != rpcServerError {
+		t.Errorf("patching outside the working directory should fail, got %v", responses[2])
+	}
+}
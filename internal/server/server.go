@@ -0,0 +1,457 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/antenore/deecli/internal/ai"
+	"github.com/antenore/deecli/internal/api"
+	"github.com/antenore/deecli/internal/config"
+	"github.com/antenore/deecli/internal/errlog"
+	"github.com/antenore/deecli/internal/files"
+	"github.com/antenore/deecli/internal/sessions"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// DefaultAddr is the address the server listens on when none is given
+const DefaultAddr = "127.0.0.1:8787"
+
+// maxRequestBody limits the size of JSON request bodies
+const maxRequestBody = 1 << 20
+
+// Server exposes deecli's context management and chat over HTTP.
+// Requests are serialized: the underlying operations keep a single conversation.
+type Server struct {
+	mu            sync.Mutex
+	fileContext   *files.FileContext
+	operations    *ai.Operations
+	configManager *config.Manager
+	sessions      *sessions.Manager
+	session       *sessions.Session
+	token         string
+}
+
+// Options configures a Server
+type Options struct {
+	Token    string            // Bearer token required on every request; a random one is generated when empty
+	Sessions *sessions.Manager // Optional session store; exchanges are saved to a new session
+}
+
+// New creates a server using the given API service and configuration
+func New(service *api.Service, configManager *config.Manager, opts Options) *Server {
+	fileContext := files.NewFileContext()
+	fileContext.Loader = files.NewFileLoaderWithPatterns(true, configManager.GetIgnorePatterns())
+	fileContext.LazyThreshold = configManager.GetLazyLoadThreshold()
+	fileContext.Formatters = configManager.GetFormatters()
+	fileContext.Loader.PreviewThreshold = configManager.GetLargeFileThreshold()
+	fileContext.Loader.PreviewSize = configManager.GetLargeFilePreview()
+	fileContext.Loader.MaxFileSize = configManager.GetMaxFileSize()
+	fileContext.Loader.MaxFiles = configManager.GetMaxFilesPerLoad()
+	fileContext.MaxContext = configManager.GetMaxLoadedFiles()
+	fileContext.Loader.ExtractText = configManager.GetExtractText()
+	fileContext.LoadProjectSummary()
+
+	token := opts.Token
+	if token == "" {
+		token = rand.Text()
+	}
+
+	return &Server{
+		fileContext:   fileContext,
+		operations:    ai.NewOperations(service, fileContext, configManager),
+		configManager: configManager,
+		sessions:      opts.Sessions,
+		token:         token,
+	}
+}
+
+// Token returns the bearer token requests must carry
+func (s *Server) Token() string {
+	return s.token
+}
+
+// ChatRequest is the body of POST /v1/chat
+type ChatRequest struct {
+	Message string `json:"message"`
+	Stream  bool   `json:"stream"`
+}
+
+// ChatResponse is returned by non-streaming POST /v1/chat
+type ChatResponse struct {
+	Response string `json:"response"`
+}
+
+// LoadRequest is the body of POST /v1/files
+type LoadRequest struct {
+	Patterns []string `json:"patterns"`
+}
+
+// FileEntry describes a file loaded into the context
+type FileEntry struct {
+	Path     string `json:"path"`
+	Size     int64  `json:"size"`
+	Language string `json:"language"`
+	Hash     string `json:"hash"` // SHA-256 of the loaded content
+}
+
+// ErrorResponse is returned for failed requests
+type ErrorResponse struct {
+	Error string `json:"error"`
+	Code  string `json:"code"`
+}
+
+// Handler returns the HTTP handler serving the API
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/health", s.handleHealth)
+	mux.HandleFunc("GET /v1/files", s.handleListFiles)
+	mux.HandleFunc("POST /v1/files", s.handleLoadFiles)
+	mux.HandleFunc("DELETE /v1/files", s.handleClearFiles)
+	mux.HandleFunc("POST /v1/chat", s.handleChat)
+	mux.HandleFunc("DELETE /v1/history", s.handleClearHistory)
+	return checkHost(s.authenticate(requireJSON(mux)))
+}
+
+// checkHost rejects requests addressed to a host other than the loopback
+// interface, so a web page cannot reach the server through DNS rebinding
+func checkHost(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, err := net.SplitHostPort(r.Host)
+		if err != nil {
+			host = r.Host
+		}
+		host = strings.Trim(host, "[]")
+		if host != "localhost" && host != "127.0.0.1" && host != "::1" {
+			writeError(w, http.StatusForbidden, errlog.CategoryConfig, "requests must be addressed to localhost or 127.0.0.1")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// authenticate rejects requests without the server's bearer token
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	expected := []byte("Bearer " + s.token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), expected) != 1 {
+			writeError(w, http.StatusUnauthorized, errlog.CategoryConfig, "missing or invalid bearer token")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// requireJSON rejects request bodies that are not sent as application/json,
+// which a browser cannot send cross-origin without a preflight
+func requireJSON(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+			if err != nil || mediaType != "application/json" {
+				writeError(w, http.StatusUnsupportedMediaType, errlog.CategoryGeneral, "request body must be application/json")
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+func (s *Server) handleListFiles(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeJSON(w, http.StatusOK, s.fileEntries())
+}
+
+func (s *Server) handleLoadFiles(w http.ResponseWriter, r *http.Request) {
+	var req LoadRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, errlog.CategoryGeneral, err.Error())
+		return
+	}
+	if len(req.Patterns) == 0 {
+		writeError(w, http.StatusBadRequest, errlog.CategoryGeneral, "patterns is required")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries, err := s.loadFiles(req.Patterns)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errlog.CategoryGeneral, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, entries)
+}
+
+func (s *Server) handleClearFiles(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fileContext.Clear()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleClearHistory(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.resetConversation()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleChat(w http.ResponseWriter, r *http.Request) {
+	var req ChatRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, errlog.CategoryGeneral, err.Error())
+		return
+	}
+	req.Message = strings.TrimSpace(req.Message)
+	if req.Message == "" {
+		writeError(w, http.StatusBadRequest, errlog.CategoryGeneral, "message is required")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !req.Stream {
+		response, err := s.chat(r.Context(), req.Message, nil)
+		if err != nil {
+			writeAPIError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, ChatResponse{Response: response})
+		return
+	}
+
+	// Stream as server-sent events: "chunk" events carry partial content,
+	// followed by a single "done" or "error" event
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, errlog.CategoryGeneral, "streaming not supported")
+		return
+	}
+	started := false
+	startEvents := func() {
+		if !started {
+			started = true
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.Header().Set("Cache-Control", "no-cache")
+			w.WriteHeader(http.StatusOK)
+		}
+	}
+
+	response, err := s.chat(r.Context(), req.Message, func(delta string) {
+		startEvents()
+		writeEvent(w, "chunk", ChatResponse{Response: delta})
+		flusher.Flush()
+	})
+	if err != nil && !started {
+		writeAPIError(w, err)
+		return
+	}
+	startEvents()
+	if err != nil {
+		writeEvent(w, "error", ErrorResponse{Error: errlog.UserMessage(err), Code: errlog.Classify(err).Code()})
+	} else {
+		writeEvent(w, "done", ChatResponse{Response: response})
+	}
+	flusher.Flush()
+}
+
+// chat sends a message with the loaded files as context and records the exchange.
+// When onChunk is set the response is streamed and each piece is passed to it.
+// The caller must hold s.mu.
+func (s *Server) chat(ctx context.Context, message string, onChunk func(string)) (string, error) {
+	defer s.releaseAPICall()
+	contextPrompt := s.buildContextPrompt(message)
+
+	if onChunk == nil {
+		msg := s.runWithCancel(ctx, s.operations.CallAPI(contextPrompt, message))
+		result, ok := msg.(ai.APIResponseMsg)
+		if !ok {
+			return "", fmt.Errorf("unexpected response %T", msg)
+		}
+		if result.Err != nil {
+			return "", result.Err
+		}
+		s.appendExchange(message, result.Response)
+		return result.Response, nil
+	}
+
+	msg := s.runWithCancel(ctx, s.operations.CallAPIStream(contextPrompt, message))
+	started, ok := msg.(ai.StreamEventMsg)
+	if !ok {
+		return "", fmt.Errorf("unexpected response %T", msg)
+	}
+	if started.Kind == ai.StreamFailed {
+		return "", started.Err
+	}
+	if started.Stream == nil {
+		return "", fmt.Errorf("stream could not be started")
+	}
+	stream := started.Stream
+	defer stream.Close()
+
+	stop := s.cancelOnDisconnect(ctx)
+	defer stop()
+
+	var content strings.Builder
+	for ev := range stream.Events() {
+		switch ev.Kind {
+		case ai.StreamDelta:
+			content.WriteString(ev.Content)
+			onChunk(ev.Content)
+		case ai.StreamFailed:
+			return content.String(), ev.Err
+		}
+	}
+
+	s.appendExchange(message, content.String())
+	return content.String(), nil
+}
+
+// runWithCancel runs an operation, cancelling the API call if the client goes away
+func (s *Server) runWithCancel(ctx context.Context, cmd tea.Cmd) tea.Msg {
+	stop := s.cancelOnDisconnect(ctx)
+	defer stop()
+	return cmd()
+}
+
+// cancelOnDisconnect cancels the in-flight API call when ctx is done.
+// The returned function stops watching.
+func (s *Server) cancelOnDisconnect(ctx context.Context) func() {
+	cancel := s.operations.GetAPICancel()
+	if cancel == nil {
+		return func() {}
+	}
+	stop := context.AfterFunc(ctx, cancel)
+	return func() { stop() }
+}
+
+// releaseAPICall frees the context of the last API call once it is finished
+func (s *Server) releaseAPICall() {
+	if cancel := s.operations.GetAPICancel(); cancel != nil {
+		cancel()
+		s.operations.SetAPICancel(nil)
+	}
+}
+
+// buildContextPrompt builds the file context, leaving room for the message
+func (s *Server) buildContextPrompt(message string) string {
+	if len(s.fileContext.Files) == 0 {
+		return ""
+	}
+	maxContextSize := s.configManager.GetMaxContextSize()
+	contextBudget := maxContextSize - len(message) - 10000
+	if contextBudget <= 5000 {
+		return fmt.Sprintf("Files loaded: %d (content truncated due to size limits)\n", len(s.fileContext.Files))
+	}
+	return s.fileContext.BuildContextPromptWithLimit(contextBudget)
+}
+
+// resetConversation forgets the history; the next exchange starts a new session
+func (s *Server) resetConversation() {
+	s.operations.SetAPIMessages([]api.Message{})
+	s.session = nil
+}
+
+// appendExchange records a completed turn in the conversation history
+func (s *Server) appendExchange(message, response string) {
+	history := append(s.operations.GetAPIMessages(),
+		api.Message{Role: "user", Content: message},
+		api.Message{Role: "assistant", Content: response},
+	)
+	s.operations.SetAPIMessages(history)
+	s.saveToSession(message, response)
+}
+
+// saveToSession persists an exchange so it shows up in /session and --continue
+func (s *Server) saveToSession(message, response string) {
+	if s.sessions == nil {
+		return
+	}
+	if s.session == nil {
+		session, err := s.sessions.CreateSession()
+		if err != nil {
+			return
+		}
+		s.session = session
+	}
+	s.sessions.SaveMessage(s.session.ID, "user", message)
+	s.sessions.SaveMessage(s.session.ID, "assistant", response)
+}
+
+// loadFiles adds files matching patterns to the context and returns all loaded files
+func (s *Server) loadFiles(patterns []string) ([]FileEntry, error) {
+	if err := s.fileContext.LoadFiles(patterns); err != nil {
+		return nil, err
+	}
+	return s.fileEntries(), nil
+}
+
+func (s *Server) fileEntries() []FileEntry {
+	entries := make([]FileEntry, 0, len(s.fileContext.Files))
+	for _, file := range s.fileContext.Files {
+		entries = append(entries, FileEntry{Path: file.RelPath, Size: file.Size, Language: file.Language, Hash: file.Hash})
+	}
+	return entries
+}
+
+func decodeJSON(r *http.Request, v any) error {
+	decoder := json.NewDecoder(io.LimitReader(r.Body, maxRequestBody))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("invalid request body: %w", err)
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, category errlog.Category, message string) {
+	writeJSON(w, status, ErrorResponse{Error: message, Code: category.Code()})
+}
+
+func writeAPIError(w http.ResponseWriter, err error) {
+	status := http.StatusBadGateway
+	var apiErr api.APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == 0 && !apiErr.Retryable {
+		status = http.StatusInternalServerError
+	}
+	writeError(w, status, errlog.Classify(err), errlog.UserMessage(err))
+}
+
+func writeEvent(w http.ResponseWriter, event string, v any) {
+	data, _ := json.Marshal(v)
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+}
@@ -0,0 +1,193 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/antenore/deecli/internal/api"
+	"github.com/antenore/deecli/internal/config"
+)
+
+func newTestServer(t *testing.T, token string) *Server {
+	t.Helper()
+	service := api.NewDeepSeekService("sk-test", "deepseek-chat", 0.1, 256)
+	return New(service, config.NewManager(), Options{Token: token})
+}
+
+// doRequest sends an authenticated request to localhost, with a JSON body
+// if one is given
+func doRequest(t *testing.T, s *Server, method, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Host = "127.0.0.1:8787"
+	req.Header.Set("Authorization", "Bearer "+s.Token())
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, req)
+	return rec
+}
+
+func TestServer_Health(t *testing.T) {
+	rec := doRequest(t, newTestServer(t, ""), http.MethodGet, "/v1/health", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), `"ok"`) {
+		t.Errorf("unexpected body: %s", rec.Body.String())
+	}
+}
+
+func TestServer_Token(t *testing.T) {
+	s := newTestServer(t, "secret")
+	if rec := doRequest(t, s, http.MethodGet, "/v1/health", ""); rec.Code != http.StatusOK {
+		t.Errorf("status with token = %d, want 200", rec.Code)
+	}
+
+	for _, header := range []string{"", "Bearer wrong"} {
+		req := httptest.NewRequest(http.MethodGet, "/v1/health", nil)
+		req.Host = "localhost:8787"
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		rec := httptest.NewRecorder()
+		s.Handler().ServeHTTP(rec, req)
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("status with Authorization %q = %d, want 401", header, rec.Code)
+		}
+	}
+
+	// Without --token one is generated, so the server is never open
+	generated := newTestServer(t, "")
+	if len(generated.Token()) < 16 || generated.Token() == newTestServer(t, "").Token() {
+		t.Errorf("generated token %q is not random", generated.Token())
+	}
+}
+
+func TestServer_Host(t *testing.T) {
+	s := newTestServer(t, "secret")
+	tests := []struct {
+		host string
+		want int
+	}{
+		{"127.0.0.1:8787", http.StatusOK},
+		{"localhost:8787", http.StatusOK},
+		{"[::1]:8787", http.StatusOK},
+		{"localhost", http.StatusOK},
+		{"attacker.example:8787", http.StatusForbidden},
+		{"127.0.0.1.attacker.example", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/v1/health", nil)
+		req.Host = tt.host
+		req.Header.Set("Authorization", "Bearer secret")
+		rec := httptest.NewRecorder()
+		s.Handler().ServeHTTP(rec, req)
+		if rec.Code != tt.want {
+			t.Errorf("Host %s: status = %d, want %d", tt.host, rec.Code, tt.want)
+		}
+	}
+}
+
+func TestServer_ContentType(t *testing.T) {
+	s := newTestServer(t, "secret")
+	for contentType, want := range map[string]int{
+		"":                                  http.StatusUnsupportedMediaType,
+		"text/plain":                        http.StatusUnsupportedMediaType,
+		"application/x-www-form-urlencoded": http.StatusUnsupportedMediaType,
+		"application/json; charset=utf-8":   http.StatusBadRequest, // Accepted, then refused for the empty message
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/v1/chat", strings.NewReader(`{"message": ""}`))
+		req.Host = "127.0.0.1:8787"
+		req.Header.Set("Authorization", "Bearer secret")
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		rec := httptest.NewRecorder()
+		s.Handler().ServeHTTP(rec, req)
+		if rec.Code != want {
+			t.Errorf("Content-Type %q: status = %d, want %d", contentType, rec.Code, want)
+		}
+	}
+}
+
+func TestServer_Files(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "main.go")
+	if err := os.WriteFile(path, []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	s := newTestServer(t, "")
+
+	rec := doRequest(t, s, http.MethodPost, "/v1/files", `{"patterns": ["`+filepath.ToSlash(path)+`"]}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("load status = %d, body %s", rec.Code, rec.Body.String())
+	}
+
+	rec = doRequest(t, s, http.MethodGet, "/v1/files", "")
+	var entries []FileEntry
+	if err := json.Unmarshal(rec.Body.Bytes(), &entries); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Size != int64(len("package main\n")) {
+		t.Errorf("entries = %+v, want the loaded file", entries)
+	}
+
+	rec = doRequest(t, s, http.MethodDelete, "/v1/files", "")
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("clear status = %d, want 204", rec.Code)
+	}
+	rec = doRequest(t, s, http.MethodGet, "/v1/files", "")
+	if strings.TrimSpace(rec.Body.String()) != "[]" {
+		t.Errorf("files after clear = %s, want []", rec.Body.String())
+	}
+}
+
+func TestServer_BadRequests(t *testing.T) {
+	s := newTestServer(t, "")
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+	}{
+		{name: "empty message", method: http.MethodPost, path: "/v1/chat", body: `{"message": "  "}`},
+		{name: "malformed chat body", method: http.MethodPost, path: "/v1/chat", body: `{`},
+		{name: "unknown field", method: http.MethodPost, path: "/v1/chat", body: `{"prompt": "hi"}`},
+		{name: "no patterns", method: http.MethodPost, path: "/v1/files", body: `{"patterns": []}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := doRequest(t, s, tt.method, tt.path, tt.body)
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, want 400", rec.Code)
+			}
+			var errResp ErrorResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &errResp); err != nil || errResp.Error == "" {
+				t.Errorf("expected JSON error body, got %s", rec.Body.String())
+			}
+		})
+	}
+}
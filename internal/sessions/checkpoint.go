@@ -0,0 +1,80 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sessions
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/antenore/deecli/internal/api"
+)
+
+// CheckpointPath is where the chat checkpoints the running conversation,
+// relative to the project directory. The file is removed on a clean exit, so
+// finding it on start means the previous run crashed or was killed.
+var CheckpointPath = filepath.Join(".deecli", "checkpoint.json")
+
+// Checkpoint is the state of a conversation needed to recover it
+type Checkpoint struct {
+	SessionID   int64         `json:"session_id"`
+	SavedAt     time.Time     `json:"saved_at"`
+	APIMessages []api.Message `json:"api_messages"`
+	Files       []string      `json:"files,omitempty"` // Paths of the loaded files
+}
+
+// SaveCheckpoint writes cp to CheckpointPath, replacing the previous one
+// atomically so a crash while saving never leaves a truncated file
+func SaveCheckpoint(cp Checkpoint) error {
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(CheckpointPath), 0755); err != nil {
+		return err
+	}
+	tmp := CheckpointPath + ".tmp"
+	// The conversation may quote secrets from loaded files
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, CheckpointPath)
+}
+
+// LoadCheckpoint reads the checkpoint left by a previous run, or returns nil
+// if there is none
+func LoadCheckpoint() (*Checkpoint, error) {
+	data, err := os.ReadFile(CheckpointPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var cp Checkpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return nil, err
+	}
+	return &cp, nil
+}
+
+// RemoveCheckpoint deletes the checkpoint, as a clean exit does
+func RemoveCheckpoint() error {
+	if err := os.Remove(CheckpointPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
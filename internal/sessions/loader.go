@@ -16,6 +16,7 @@ package sessions
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/antenore/deecli/internal/api"
 )
@@ -25,7 +26,8 @@ type LoaderDependencies struct {
 	SessionManager     *Manager
 	CurrentSession     *Session
 	Renderer           interface {
-		FormatMessage(role, content string) string
+		FormatMessageAt(role, content string, at time.Time) string
+		FormatAnnotation(gen api.Generation) string
 		SetViewportWidth(width int, filesVisible bool)
 	}
 	Viewport           interface {
@@ -49,31 +51,41 @@ func NewLoader(deps *LoaderDependencies) *Loader {
 	}
 }
 
-// LoadSession loads the previous session and returns messages and apiMessages
-func (l *Loader) LoadSession() ([]string, []api.Message, error) {
+// LoadSession loads the previous session and returns the display messages,
+// the annotations of the responses among them and the apiMessages
+func (l *Loader) LoadSession() ([]string, []string, []api.Message, error) {
 	if l.deps.SessionManager == nil || l.deps.CurrentSession == nil {
-		return nil, nil, fmt.Errorf("no session manager available")
+		return nil, nil, nil, fmt.Errorf("no session manager available")
 	}
 
 	messages, err := l.deps.SessionManager.GetSessionMessages(l.deps.CurrentSession.ID)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to load session messages: %w", err)
+		return nil, nil, nil, fmt.Errorf("failed to load session messages: %w", err)
 	}
 
 	var displayMessages []string
+	var annotations []string
 	var apiMessages []api.Message
 
 	// Initialize with formatted initial content
 	if l.deps.FormatInitialContent != nil {
 		displayMessages = append(displayMessages, l.deps.FormatInitialContent())
+		annotations = append(annotations, "")
 	}
 
 	for _, msg := range messages {
+		var gen *api.Generation
+		if msg.Generation.Model != "" {
+			gen = &msg.Generation
+		}
+
 		// Store in API format for context (exclude system messages)
 		if msg.Role != "system" {
 			apiMessages = append(apiMessages, api.Message{
-				Role:    msg.Role,
-				Content: msg.Content,
+				Role:       msg.Role,
+				Content:    msg.Content,
+				Time:       msg.Timestamp,
+				Generation: gen,
 			})
 		}
 
@@ -85,12 +97,18 @@ func (l *Loader) LoadSession() ([]string, []api.Message, error) {
 		// Use renderer to format the message
 		var formattedContent string
 		if l.deps.Renderer != nil {
-			formattedContent = l.deps.Renderer.FormatMessage(msg.Role, msg.Content)
+			formattedContent = l.deps.Renderer.FormatMessageAt(msg.Role, msg.Content, msg.Timestamp)
 		} else {
 			// Fallback if renderer is not available
 			formattedContent = fmt.Sprintf("%s: %s", msg.Role, msg.Content)
 		}
 		displayMessages = append(displayMessages, formattedContent)
+
+		annotation := ""
+		if gen != nil && l.deps.Renderer != nil {
+			annotation = l.deps.Renderer.FormatAnnotation(*gen)
+		}
+		annotations = append(annotations, annotation)
 	}
 
 	// Update viewport with content
@@ -100,5 +118,5 @@ func (l *Loader) LoadSession() ([]string, []api.Message, error) {
 		l.deps.Viewport.GotoBottom()
 	}
 
-	return displayMessages, apiMessages, nil
+	return displayMessages, annotations, apiMessages, nil
 }
\ No newline at end of file
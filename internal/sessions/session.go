@@ -18,8 +18,10 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"time"
 
+	"github.com/antenore/deecli/internal/api"
 	_ "github.com/mattn/go-sqlite3"
 )
 
@@ -29,17 +31,20 @@ type Manager struct {
 }
 
 type Message struct {
-	ID        int64
-	SessionID int64
-	Role      string
-	Content   string
-	Timestamp time.Time
+	ID         int64
+	SessionID  int64
+	Role       string
+	Content    string
+	Timestamp  time.Time
+	Generation api.Generation // Model, temperature and tokens of a response; empty Model for other messages
 }
 
 type Session struct {
-	ID        int64
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID           int64
+	Title        string // Short AI-generated title, empty until generated
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	MessageCount int // Populated by ListSessions
 }
 
 func NewManager() (*Manager, error) {
@@ -90,20 +95,77 @@ func (m *Manager) initSchema() error {
 	);
 
 	CREATE INDEX IF NOT EXISTS idx_messages_session ON messages(session_id);
+
+	CREATE TABLE IF NOT EXISTS scrollback (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		session_id INTEGER NOT NULL,
+		content TEXT NOT NULL,
+		FOREIGN KEY (session_id) REFERENCES sessions(id)
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_scrollback_session ON scrollback(session_id);
 	`
 
-	_, err := m.db.Exec(schema)
-	return err
+	if _, err := m.db.Exec(schema); err != nil {
+		return err
+	}
+
+	return m.migrateSchema()
+}
+
+// migrateSchema adds columns introduced after the initial schema
+func (m *Manager) migrateSchema() error {
+	added := []struct{ table, column, definition string }{
+		{"sessions", "title", "TEXT NOT NULL DEFAULT ''"},
+		{"messages", "model", "TEXT NOT NULL DEFAULT ''"},
+		{"messages", "temperature", "REAL"},
+		{"messages", "prompt_tokens", "INTEGER NOT NULL DEFAULT 0"},
+		{"messages", "completion_tokens", "INTEGER NOT NULL DEFAULT 0"},
+	}
+	for _, col := range added {
+		exists, err := m.hasColumn(col.table, col.column)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			if _, err := m.db.Exec(fmt.Sprintf(`ALTER TABLE %s ADD COLUMN %s %s`, col.table, col.column, col.definition)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// hasColumn reports whether table has the named column
+func (m *Manager) hasColumn(table, column string) (bool, error) {
+	rows, err := m.db.Query(fmt.Sprintf(`PRAGMA table_info(%s)`, table))
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var cid, notNull, pk int
+		var name, colType string
+		var defaultValue sql.NullString
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &defaultValue, &pk); err != nil {
+			return false, err
+		}
+		if name == column {
+			return true, nil
+		}
+	}
+	return false, rows.Err()
 }
 
 func (m *Manager) GetCurrentSession() (*Session, error) {
 	var session Session
 	err := m.db.QueryRow(`
-		SELECT id, created_at, updated_at 
+		SELECT id, title, created_at, updated_at 
 		FROM sessions 
 		ORDER BY updated_at DESC 
 		LIMIT 1
-	`).Scan(&session.ID, &session.CreatedAt, &session.UpdatedAt)
+	`).Scan(&session.ID, &session.Title, &session.CreatedAt, &session.UpdatedAt)
 
 	if err == sql.ErrNoRows {
 		return m.CreateSession()
@@ -115,6 +177,20 @@ func (m *Manager) GetCurrentSession() (*Session, error) {
 	return &session, nil
 }
 
+// GetSession returns the session with the given ID
+func (m *Manager) GetSession(id int64) (*Session, error) {
+	var session Session
+	err := m.db.QueryRow(`
+		SELECT id, title, created_at, updated_at
+		FROM sessions
+		WHERE id = ?
+	`, id).Scan(&session.ID, &session.Title, &session.CreatedAt, &session.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
 func (m *Manager) CreateSession() (*Session, error) {
 	result, err := m.db.Exec(`
 		INSERT INTO sessions (created_at, updated_at) 
@@ -137,10 +213,16 @@ func (m *Manager) CreateSession() (*Session, error) {
 }
 
 func (m *Manager) SaveMessage(sessionID int64, role, content string) error {
+	return m.SaveResponse(sessionID, role, content, api.Generation{})
+}
+
+// SaveResponse saves a message together with the model, temperature and
+// tokens that produced it
+func (m *Manager) SaveResponse(sessionID int64, role, content string, gen api.Generation) error {
 	_, err := m.db.Exec(`
-		INSERT INTO messages (session_id, role, content, timestamp)
-		VALUES (?, ?, ?, CURRENT_TIMESTAMP)
-	`, sessionID, role, content)
+		INSERT INTO messages (session_id, role, content, timestamp, model, temperature, prompt_tokens, completion_tokens)
+		VALUES (?, ?, ?, CURRENT_TIMESTAMP, ?, ?, ?, ?)
+	`, sessionID, role, content, gen.Model, gen.Temperature, gen.PromptTokens, gen.CompletionTokens)
 
 	if err != nil {
 		return err
@@ -157,7 +239,7 @@ func (m *Manager) SaveMessage(sessionID int64, role, content string) error {
 
 func (m *Manager) GetSessionMessages(sessionID int64) ([]Message, error) {
 	rows, err := m.db.Query(`
-		SELECT id, session_id, role, content, timestamp
+		SELECT id, session_id, role, content, timestamp, model, temperature, prompt_tokens, completion_tokens
 		FROM messages
 		WHERE session_id = ?
 		ORDER BY timestamp ASC
@@ -170,16 +252,204 @@ func (m *Manager) GetSessionMessages(sessionID int64) ([]Message, error) {
 	var messages []Message
 	for rows.Next() {
 		var msg Message
-		err := rows.Scan(&msg.ID, &msg.SessionID, &msg.Role, &msg.Content, &msg.Timestamp)
+		var temperature sql.NullFloat64
+		err := rows.Scan(&msg.ID, &msg.SessionID, &msg.Role, &msg.Content, &msg.Timestamp,
+			&msg.Generation.Model, &temperature, &msg.Generation.PromptTokens, &msg.Generation.CompletionTokens)
 		if err != nil {
 			return nil, err
 		}
+		if temperature.Valid {
+			msg.Generation.Temperature = &temperature.Float64
+		}
 		messages = append(messages, msg)
 	}
 
 	return messages, rows.Err()
 }
 
+// SetSessionTitle stores a short title for the session
+func (m *Manager) SetSessionTitle(sessionID int64, title string) error {
+	_, err := m.db.Exec(`
+		UPDATE sessions 
+		SET title = ? 
+		WHERE id = ?
+	`, title, sessionID)
+	return err
+}
+
+// ForkSession creates a session titled title holding a copy of the messages
+// of session sourceID, which is left unchanged
+func (m *Manager) ForkSession(sourceID int64, title string) (*Session, error) {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		INSERT INTO sessions (title, created_at, updated_at)
+		VALUES (?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+	`, title)
+	if err != nil {
+		return nil, err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO messages (session_id, role, content, timestamp, model, temperature, prompt_tokens, completion_tokens)
+		SELECT ?, role, content, timestamp, model, temperature, prompt_tokens, completion_tokens
+		FROM messages
+		WHERE session_id = ?
+		ORDER BY id
+	`, id, sourceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &Session{
+		ID:        id,
+		Title:     title,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}, nil
+}
+
+// ArchiveScrollback stores formatted display messages, oldest first, that
+// were moved out of memory by the scrollback limit
+func (m *Manager) ArchiveScrollback(sessionID int64, formatted []string) error {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, content := range formatted {
+		if _, err := tx.Exec(`
+			INSERT INTO scrollback (session_id, content)
+			VALUES (?, ?)
+		`, sessionID, content); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// RestoreScrollback removes the count most recently archived display
+// messages of a session and returns them, oldest first
+func (m *Manager) RestoreScrollback(sessionID int64, count int) ([]string, error) {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`
+		SELECT id, content
+		FROM scrollback
+		WHERE session_id = ?
+		ORDER BY id DESC
+		LIMIT ?
+	`, sessionID, count)
+	if err != nil {
+		return nil, err
+	}
+
+	var restored []string
+	var oldest int64
+	for rows.Next() {
+		var content string
+		if err := rows.Scan(&oldest, &content); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		restored = append(restored, content)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(restored) == 0 {
+		return nil, nil
+	}
+
+	if _, err := tx.Exec(`DELETE FROM scrollback WHERE session_id = ? AND id >= ?`, sessionID, oldest); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	slices.Reverse(restored)
+	return restored, nil
+}
+
+// ClearScrollback drops the archived display messages of a session
+func (m *Manager) ClearScrollback(sessionID int64) error {
+	_, err := m.db.Exec(`DELETE FROM scrollback WHERE session_id = ?`, sessionID)
+	return err
+}
+
+// DeleteSession removes a session with its messages and archived scrollback
+func (m *Manager) DeleteSession(sessionID int64) error {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, query := range []string{
+		`DELETE FROM messages WHERE session_id = ?`,
+		`DELETE FROM scrollback WHERE session_id = ?`,
+	} {
+		if _, err := tx.Exec(query, sessionID); err != nil {
+			return err
+		}
+	}
+	result, err := tx.Exec(`DELETE FROM sessions WHERE id = ?`, sessionID)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return tx.Commit()
+}
+
+// ListSessions returns the most recently updated sessions with their message counts
+func (m *Manager) ListSessions(limit int) ([]Session, error) {
+	rows, err := m.db.Query(`
+		SELECT s.id, s.title, s.created_at, s.updated_at, COUNT(msg.id)
+		FROM sessions s
+		LEFT JOIN messages msg ON msg.session_id = s.id
+		GROUP BY s.id
+		ORDER BY s.updated_at DESC
+		LIMIT ?
+	`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sessions []Session
+	for rows.Next() {
+		var session Session
+		err := rows.Scan(&session.ID, &session.Title, &session.CreatedAt, &session.UpdatedAt, &session.MessageCount)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+
+	return sessions, rows.Err()
+}
+
 func (m *Manager) HasPreviousSession() bool {
 	var count int
 	err := m.db.QueryRow(`
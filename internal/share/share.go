@@ -0,0 +1,150 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package share
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/antenore/deecli/internal/api"
+	"github.com/antenore/deecli/internal/redact"
+)
+
+// GistAPIURL is the GitHub endpoint gists are created at
+var GistAPIURL = "https://api.github.com/gists"
+
+// Options controls how a conversation is exported
+type Options struct {
+	Title    string           // Heading of the export
+	Redactor *redact.Redactor // Masks secrets; required
+	BaseDir  string           // Paths under this directory are made relative
+	HomeDir  string           // Other paths under this directory start with ~
+	Time     time.Time        // Export time shown in the header
+
+	Timestamps  bool // Show when each message was sent, if known
+	Annotations bool // Note the model, temperature and tokens under each response, if known
+}
+
+// Markdown renders the user and assistant messages of a conversation as
+// Markdown, with secrets redacted and local paths relativized
+func Markdown(messages []api.Message, opts Options) string {
+	var out strings.Builder
+	title := opts.Title
+	if title == "" {
+		title = "DeeCLI conversation"
+	}
+	out.WriteString("# " + title + "\n\n")
+	out.WriteString(fmt.Sprintf("_Exported from DeeCLI on %s_\n", opts.Time.Format("2006-01-02 15:04")))
+
+	for _, msg := range messages {
+		var heading string
+		switch msg.Role {
+		case "user":
+			heading = "You"
+		case "assistant":
+			heading = "Assistant"
+		default:
+			// System prompts and raw tool output are not part of the shared conversation
+			continue
+		}
+		content := strings.TrimSpace(msg.Content)
+		if content == "" {
+			continue
+		}
+		content = RelativizePaths(opts.Redactor.Redact(content), opts.BaseDir, opts.HomeDir)
+		if opts.Timestamps && !msg.Time.IsZero() {
+			heading += " · " + msg.Time.Local().Format("2006-01-02 15:04")
+		}
+		out.WriteString("\n## " + heading + "\n\n" + content + "\n")
+		if opts.Annotations && msg.Generation != nil {
+			out.WriteString("\n_" + msg.Generation.String() + "_\n")
+		}
+	}
+	return out.String()
+}
+
+// RelativizePaths rewrites absolute paths under baseDir as relative ones and
+// other paths under homeDir as ~, so exports don't reveal the local layout
+func RelativizePaths(text, baseDir, homeDir string) string {
+	for _, dir := range []struct{ path, replacement string }{
+		{baseDir, "."},
+		{homeDir, "~"},
+	} {
+		if dir.path == "" || dir.path == string(filepath.Separator) {
+			continue
+		}
+		// Only whole path components match: /home/me/project must not rewrite /home/me/project2
+		prefix := strings.TrimRight(dir.path, string(filepath.Separator))
+		re := regexp.MustCompile(regexp.QuoteMeta(prefix) + `([^\w.\-]|$)`)
+		text = re.ReplaceAllString(text, dir.replacement+"${1}")
+	}
+	return text
+}
+
+// CreateGist uploads content as a secret gist and returns its URL
+func CreateGist(ctx context.Context, token, filename, description, content string) (string, error) {
+	if token == "" {
+		return "", fmt.Errorf("no GitHub token: set github_token in the config or GITHUB_TOKEN")
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"description": description,
+		"public":      false,
+		"files": map[string]interface{}{
+			filename: map[string]string{"content": content},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", GistAPIURL, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to create gist: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if resp.StatusCode != http.StatusCreated {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		_ = json.Unmarshal(data, &apiErr)
+		return "", fmt.Errorf("failed to create gist: HTTP %d %s", resp.StatusCode, apiErr.Message)
+	}
+
+	var gist struct {
+		HTMLURL string `json:"html_url"`
+	}
+	if err := json.Unmarshal(data, &gist); err != nil || gist.HTMLURL == "" {
+		return "", fmt.Errorf("unexpected gist response")
+	}
+	return gist.HTMLURL, nil
+}
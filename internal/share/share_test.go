@@ -0,0 +1,133 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package share
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/antenore/deecli/internal/api"
+	"github.com/antenore/deecli/internal/redact"
+)
+
+func TestMarkdown(t *testing.T) {
+	redactor, err := redact.New(nil)
+	if err != nil {
+		t.Fatalf("redact.New() error = %v", err)
+	}
+	messages := []api.Message{
+		{Role: "system", Content: "You are a helpful assistant"},
+		{Role: "user", Content: "Why does /home/me/project/main.go fail with key sk-abcdefghijklmnopqrstuvwxyz?"},
+		{Role: "tool", Content: "raw tool output"},
+		{Role: "assistant", Content: "Check /home/me/.config/app.yaml and /home/me/project2/x."},
+	}
+
+	out := Markdown(messages, Options{
+		Title:    "Debugging",
+		Redactor: redactor,
+		BaseDir:  "/home/me/project",
+		HomeDir:  "/home/me",
+		Time:     time.Date(2025, 1, 2, 3, 4, 0, 0, time.UTC),
+	})
+
+	for _, want := range []string{"# Debugging", "## You", "./main.go", "[REDACTED:API key]", "## Assistant", "~/.config/app.yaml", "~/project2/x"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in export:\n%s", want, out)
+		}
+	}
+	for _, unwanted := range []string{"helpful assistant", "raw tool output", "sk-abc", "/home/me"} {
+		if strings.Contains(out, unwanted) {
+			t.Errorf("did not expect %q in export:\n%s", unwanted, out)
+		}
+	}
+}
+
+func TestCreateGist(t *testing.T) {
+	var got struct {
+		Public bool                         `json:"public"`
+		Files  map[string]map[string]string `json:"files"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"message":"Bad credentials"}`))
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"html_url":"https://gist.github.com/abc"}`))
+	}))
+	defer server.Close()
+	defer func(url string) { GistAPIURL = url }(GistAPIURL)
+	GistAPIURL = server.URL
+
+	url, err := CreateGist(context.Background(), "secret", "chat.md", "desc", "# hi")
+	if err != nil || url != "https://gist.github.com/abc" {
+		t.Fatalf("CreateGist() = %q, %v", url, err)
+	}
+	if got.Public || got.Files["chat.md"]["content"] != "# hi" {
+		t.Errorf("unexpected request %+v", got)
+	}
+
+	if _, err := CreateGist(context.Background(), "wrong", "chat.md", "desc", "# hi"); err == nil || !strings.Contains(err.Error(), "Bad credentials") {
+		t.Errorf("expected the API error message, got %v", err)
+	}
+	if _, err := CreateGist(context.Background(), "", "chat.md", "desc", "# hi"); err == nil {
+		t.Error("expected an error without a token")
+	}
+}
+
+func TestMarkdown_Timestamps(t *testing.T) {
+	redactor, err := redact.New(nil)
+	if err != nil {
+		t.Fatalf("redact.New() error = %v", err)
+	}
+	sent := time.Date(2025, 1, 2, 3, 4, 0, 0, time.Local)
+	messages := []api.Message{
+		{Role: "user", Content: "Hello", Time: sent},
+		{Role: "assistant", Content: "Hi"}, // Time unknown
+	}
+
+	out := Markdown(messages, Options{Redactor: redactor, Timestamps: true})
+	if !strings.Contains(out, "## You · 2025-01-02 03:04\n") || !strings.Contains(out, "## Assistant\n") {
+		t.Errorf("expected the known time in the headings:\n%s", out)
+	}
+	if out := Markdown(messages, Options{Redactor: redactor}); strings.Contains(out, "2025-01-02") {
+		t.Errorf("expected no timestamps unless asked for:\n%s", out)
+	}
+}
+
+func TestMarkdown_Annotations(t *testing.T) {
+	redactor, err := redact.New(nil)
+	if err != nil {
+		t.Fatalf("redact.New() error = %v", err)
+	}
+	messages := []api.Message{
+		{Role: "user", Content: "Hello"},
+		{Role: "assistant", Content: "Hi", Generation: &api.Generation{Model: "deepseek-reasoner", PromptTokens: 5, CompletionTokens: 2}},
+	}
+
+	out := Markdown(messages, Options{Redactor: redactor, Annotations: true})
+	if !strings.Contains(out, "Hi\n\n_deepseek-reasoner · 5 → 2 tokens_\n") {
+		t.Errorf("expected the annotation under the response:\n%s", out)
+	}
+	if out := Markdown(messages, Options{Redactor: redactor}); strings.Contains(out, "deepseek-reasoner") {
+		t.Errorf("expected no annotations unless asked for:\n%s", out)
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package shutdown coordinates releasing resources when the application
+// exits, however it exits.
+package shutdown
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// hook is a named shutdown step
+type hook struct {
+	name string
+	fn   func() error
+}
+
+// Manager runs the registered shutdown hooks once, in reverse order of
+// registration, so resources are released before the ones they depend on
+type Manager struct {
+	mu    sync.Mutex
+	hooks []hook
+	done  bool
+}
+
+// NewManager creates a shutdown manager with no hooks
+func NewManager() *Manager {
+	return &Manager{}
+}
+
+// Register adds a hook run by Shutdown. Hooks registered after Shutdown are
+// ignored.
+func (m *Manager) Register(name string, fn func() error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.done {
+		m.hooks = append(m.hooks, hook{name: name, fn: fn})
+	}
+}
+
+// Shutdown runs the hooks and returns their errors joined. A hook that fails
+// or panics does not keep the others from running. Later calls do nothing.
+func (m *Manager) Shutdown() error {
+	m.mu.Lock()
+	if m.done {
+		m.mu.Unlock()
+		return nil
+	}
+	m.done = true
+	hooks := m.hooks
+	m.hooks = nil
+	m.mu.Unlock()
+
+	var errs []error
+	for i := len(hooks) - 1; i >= 0; i-- {
+		if err := run(hooks[i]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// run runs a hook, turning a panic into an error
+func run(h hook) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%s: panic: %v", h.name, r)
+		}
+	}()
+	if err := h.fn(); err != nil {
+		return fmt.Errorf("%s: %w", h.name, err)
+	}
+	return nil
+}
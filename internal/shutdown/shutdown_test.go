@@ -0,0 +1,44 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shutdown
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestManager_Shutdown(t *testing.T) {
+	m := NewManager()
+	var order []string
+	m.Register("first", func() error { order = append(order, "first"); return nil })
+	m.Register("failing", func() error { order = append(order, "failing"); return errors.New("disk full") })
+	m.Register("panicking", func() error { panic("boom") })
+	m.Register("last", func() error { order = append(order, "last"); return nil })
+
+	err := m.Shutdown()
+	if !slices.Equal(order, []string{"last", "failing", "first"}) {
+		t.Errorf("expected hooks in reverse order past failures, got %v", order)
+	}
+	if err == nil || !strings.Contains(err.Error(), "failing: disk full") || !strings.Contains(err.Error(), "panicking: panic: boom") {
+		t.Errorf("expected the failures to be reported, got %v", err)
+	}
+
+	// Hooks run once, and later registrations are ignored
+	m.Register("late", func() error { order = append(order, "late"); return nil })
+	if err := m.Shutdown(); err != nil || len(order) != 3 {
+		t.Errorf("expected a second shutdown to do nothing, got %v and %v", err, order)
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"fmt"
+)
+
+// Capability is a host resource a sandboxed tool may be granted
+type Capability string
+
+const (
+	CapabilityFSRead  Capability = "fs-read"  // Read access to a copy of the project directory
+	CapabilityFSWrite Capability = "fs-write" // Read and write access to the project directory
+	CapabilityNet     Capability = "net"      // Network access
+)
+
+// ParseCapability validates a capability name from a plugin manifest
+func ParseCapability(name string) (Capability, error) {
+	switch c := Capability(name); c {
+	case CapabilityFSRead, CapabilityFSWrite, CapabilityNet:
+		return c, nil
+	}
+	return "", fmt.Errorf("unknown capability %q (valid: %s, %s, %s)", name, CapabilityFSRead, CapabilityFSWrite, CapabilityNet)
+}
+
+// DeclaredCapabilities returns the capabilities a tool needs, or nil for
+// tools that are not sandboxed
+func DeclaredCapabilities(tool ToolFunction) []Capability {
+	if sandboxed, ok := tool.(SandboxedTool); ok {
+		return sandboxed.Capabilities()
+	}
+	return nil
+}
+
+type capabilitiesKey struct{}
+
+// WithCapabilities returns a context granting caps to the tool executed with it
+func WithCapabilities(ctx context.Context, caps []Capability) context.Context {
+	return context.WithValue(ctx, capabilitiesKey{}, caps)
+}
+
+// GrantedCapabilities returns the capabilities granted by ctx. Sandboxed
+// tools must run with only these; none are granted by default.
+func GrantedCapabilities(ctx context.Context) []Capability {
+	caps, _ := ctx.Value(capabilitiesKey{}).([]Capability)
+	return caps
+}
+
+// HasCapability reports whether caps contains c
+func HasCapability(caps []Capability, c Capability) bool {
+	for _, granted := range caps {
+		if granted == c {
+			return true
+		}
+	}
+	return false
+}
@@ -18,13 +18,19 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"sync/atomic"
 	"time"
 )
 
+// DryRunPrefix marks the output of a simulated tool call
+const DryRunPrefix = "[DRY RUN - nothing was executed] "
+
 // Executor handles safe execution of tool functions
 type Executor struct {
 	registry    *Registry
 	permissions PermissionManager
+	dryRun      atomic.Bool // Simulate tools that write files or run commands
+	workspace   string      // Directory tools work in, "" for the current one
 }
 
 // PermissionManager interface for managing tool permissions
@@ -34,6 +40,13 @@ type PermissionManager interface {
 	RequestApproval(request ApprovalRequest) (ApprovalResponse, error)
 }
 
+// CapabilityManager is implemented by permission managers that remember the
+// capabilities granted to sandboxed tools
+type CapabilityManager interface {
+	MissingCapabilities(functionName string, declared []Capability) []Capability
+	GrantCapabilities(functionName string, caps []Capability) error
+}
+
 // NewExecutor creates a new tool executor
 func NewExecutor(registry *Registry, permissions PermissionManager) *Executor {
 	return &Executor{
@@ -69,6 +82,29 @@ func (e *Executor) Execute(ctx context.Context, request ExecutionRequest, projec
 		return nil, fmt.Errorf("failed to check permissions: %w", err)
 	}
 
+	// Paths outside the workspace are never auto-approved, and protected
+	// locations are refused whatever the permission
+	outside, err := e.OutsideWorkspace(tool, request.Arguments)
+	if err != nil {
+		return &ExecutionResult{
+			Success: false,
+			Error:   err.Error(),
+		}, nil
+	}
+	if permission == PermissionAlways && len(outside) > 0 {
+		permission = ""
+	}
+
+	// A sandboxed tool is only auto-approved while every capability it
+	// declares has been granted; a new capability asks the user again
+	declared := DeclaredCapabilities(tool)
+	if permission == PermissionAlways && len(declared) > 0 {
+		capManager, ok := e.permissions.(CapabilityManager)
+		if !ok || len(capManager.MissingCapabilities(request.FunctionName, declared)) > 0 {
+			permission = ""
+		}
+	}
+
 	// Handle permission levels
 	switch permission {
 	case PermissionNever:
@@ -90,9 +126,12 @@ func (e *Executor) Execute(ctx context.Context, request ExecutionRequest, projec
 		}
 
 		approvalReq := ApprovalRequest{
-			FunctionName: request.FunctionName,
-			Description:  tool.Description(),
-			Arguments:    args,
+			FunctionName:     request.FunctionName,
+			Description:      tool.Description(),
+			Arguments:        args,
+			Capabilities:     declared,
+			Simulated:        e.Simulates(tool),
+			OutsideWorkspace: outside,
 		}
 
 		approval, err := e.permissions.RequestApproval(approvalReq)
@@ -107,23 +146,29 @@ func (e *Executor) Execute(ctx context.Context, request ExecutionRequest, projec
 			}, nil
 		}
 
-		// Save permission if not "once"
-		if approval.Level != PermissionOnce {
+		// Save permission if not "once"; approving paths outside the
+		// workspace covers this call only
+		if approval.Level != PermissionOnce && !(approval.Level == PermissionAlways && len(outside) > 0) {
 			if err := e.permissions.SetPermission(request.FunctionName, projectPath, approval.Level); err != nil {
 				// Log error but continue with execution
 				fmt.Printf("Warning: failed to save permission: %v\n", err)
 			}
+			if capManager, ok := e.permissions.(CapabilityManager); ok && approval.Level == PermissionAlways && len(declared) > 0 {
+				if err := capManager.GrantCapabilities(request.FunctionName, declared); err != nil {
+					fmt.Printf("Warning: failed to save capabilities: %v\n", err)
+				}
+			}
 		}
 
 	case PermissionAlways:
 		// Approved, continue with execution
 	}
 
-	// Execute the function with timeout
-	execCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	// Execute the function with timeout and the capabilities and paths just approved
+	execCtx, cancel := context.WithTimeout(WithApprovedPaths(WithCapabilities(ctx, declared), outside), 30*time.Second)
 	defer cancel()
 
-	output, err := tool.Execute(execCtx, request.Arguments)
+	output, err := e.run(execCtx, tool, request.Arguments)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "[DEBUG] Tool execution failed: %s - %v\n", request.FunctionName, err)
 		return &ExecutionResult{
@@ -140,7 +185,9 @@ func (e *Executor) Execute(ctx context.Context, request ExecutionRequest, projec
 	}, nil
 }
 
-// ExecuteWithoutPermission runs a tool function without permission checks (for testing)
+// ExecuteWithoutPermission runs a tool function without permission checks.
+// Sandboxed tools only get the capabilities already granted by ctx, and
+// paths outside the workspace must be approved by ctx.
 func (e *Executor) ExecuteWithoutPermission(ctx context.Context, functionName string, args json.RawMessage) (*ExecutionResult, error) {
 	tool, exists := e.registry.Get(functionName)
 	if !exists {
@@ -150,7 +197,7 @@ func (e *Executor) ExecuteWithoutPermission(ctx context.Context, functionName st
 		}, nil
 	}
 
-	output, err := tool.Execute(ctx, args)
+	output, err := e.run(ctx, tool, args)
 	if err != nil {
 		return &ExecutionResult{
 			Success: false,
@@ -162,4 +209,38 @@ func (e *Executor) ExecuteWithoutPermission(ctx context.Context, functionName st
 		Success: true,
 		Output:  output,
 	}, nil
-}
\ No newline at end of file
+}
+
+// SetDryRun turns dry-run mode on or off
+func (e *Executor) SetDryRun(enabled bool) {
+	e.dryRun.Store(enabled)
+}
+
+// DryRun reports whether tools that write files or run commands are only simulated
+func (e *Executor) DryRun() bool {
+	return e.dryRun.Load()
+}
+
+// Simulates reports whether a call to tool would only be simulated
+func (e *Executor) Simulates(tool ToolFunction) bool {
+	if !e.DryRun() {
+		return false
+	}
+	_, ok := tool.(Simulator)
+	return ok
+}
+
+// run executes tool, or simulates it in dry-run mode
+func (e *Executor) run(ctx context.Context, tool ToolFunction, args json.RawMessage) (string, error) {
+	if err := e.checkPaths(ctx, tool, args); err != nil {
+		return "", err
+	}
+	if e.Simulates(tool) {
+		output, err := tool.(Simulator).Simulate(ctx, args)
+		if err != nil {
+			return "", err
+		}
+		return DryRunPrefix + output, nil
+	}
+	return tool.Execute(ctx, args)
+}
@@ -240,4 +240,138 @@ func TestExecutor_ToolFailure(t *testing.T) {
 	if result != nil {
 		t.Errorf("Execute() result = %v, want nil when tool fails", result)
 	}
-}
\ No newline at end of file
+}
+// sandboxedMockTool is a mockTool that declares capabilities
+type sandboxedMockTool struct {
+	mockTool
+	capabilities []Capability
+}
+
+func (m *sandboxedMockTool) Capabilities() []Capability { return m.capabilities }
+
+// capabilityPermissionManager auto-approves tools and tracks capability grants
+type capabilityPermissionManager struct {
+	granted   []Capability
+	requested []ApprovalRequest
+}
+
+func (m *capabilityPermissionManager) CheckPermission(functionName, projectPath string) (PermissionLevel, error) {
+	return PermissionAlways, nil
+}
+
+func (m *capabilityPermissionManager) SetPermission(functionName, projectPath string, level PermissionLevel) error {
+	return nil
+}
+
+func (m *capabilityPermissionManager) RequestApproval(request ApprovalRequest) (ApprovalResponse, error) {
+	m.requested = append(m.requested, request)
+	return ApprovalResponse{Approved: true, Level: PermissionAlways}, nil
+}
+
+func (m *capabilityPermissionManager) MissingCapabilities(functionName string, declared []Capability) []Capability {
+	var missing []Capability
+	for _, c := range declared {
+		if !HasCapability(m.granted, c) {
+			missing = append(missing, c)
+		}
+	}
+	return missing
+}
+
+func (m *capabilityPermissionManager) GrantCapabilities(functionName string, caps []Capability) error {
+	m.granted = caps
+	return nil
+}
+
+func TestExecutor_ExecuteSandboxed(t *testing.T) {
+	registry := NewRegistry()
+	var seen []Capability
+	tool := &sandboxedMockTool{
+		mockTool: mockTool{
+			name: "plugin",
+			executeFunc: func(ctx context.Context, args json.RawMessage) (string, error) {
+				seen = GrantedCapabilities(ctx)
+				return "ok", nil
+			},
+		},
+		capabilities: []Capability{CapabilityFSRead},
+	}
+	registry.Register(tool)
+	permissions := &capabilityPermissionManager{}
+	executor := NewExecutor(registry, permissions)
+	request := ExecutionRequest{FunctionName: "plugin", Arguments: json.RawMessage("{}")}
+
+	// "always" does not cover capabilities that were never granted
+	if _, err := executor.Execute(context.Background(), request, "/test"); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if len(permissions.requested) != 1 || !HasCapability(permissions.requested[0].Capabilities, CapabilityFSRead) {
+		t.Fatalf("expected an approval request listing fs-read, got %+v", permissions.requested)
+	}
+	if !HasCapability(seen, CapabilityFSRead) {
+		t.Errorf("expected the approved capability to reach the tool, got %v", seen)
+	}
+
+	// Once granted, the tool runs without asking again
+	if _, err := executor.Execute(context.Background(), request, "/test"); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if len(permissions.requested) != 1 {
+		t.Errorf("expected no new approval request, got %d", len(permissions.requested))
+	}
+
+	// A newly declared capability asks again
+	tool.capabilities = append(tool.capabilities, CapabilityNet)
+	executor.Execute(context.Background(), request, "/test")
+	if len(permissions.requested) != 2 {
+		t.Errorf("expected a new approval request for net, got %d", len(permissions.requested))
+	}
+
+	// Without permission checks no capability is granted
+	seen = nil
+	executor.ExecuteWithoutPermission(context.Background(), "plugin", nil)
+	if len(seen) != 0 {
+		t.Errorf("expected no capabilities without approval, got %v", seen)
+	}
+}
+
+// simulatorMockTool is a mockTool with side effects that can be simulated
+type simulatorMockTool struct {
+	mockTool
+	executed bool
+}
+
+func (m *simulatorMockTool) Execute(ctx context.Context, args json.RawMessage) (string, error) {
+	m.executed = true
+	return "written", nil
+}
+
+func (m *simulatorMockTool) Simulate(ctx context.Context, args json.RawMessage) (string, error) {
+	return "would write", nil
+}
+
+func TestExecutor_DryRun(t *testing.T) {
+	registry := NewRegistry()
+	writer := &simulatorMockTool{mockTool: mockTool{name: "writer"}}
+	reader := &mockTool{name: "reader"}
+	registry.Register(writer)
+	registry.Register(reader)
+	executor := NewExecutor(registry, &mockPermissionManager{allowAll: true})
+	executor.SetDryRun(true)
+
+	result, err := executor.Execute(context.Background(), ExecutionRequest{FunctionName: "writer"}, "/test")
+	if err != nil || !result.Success || result.Output != DryRunPrefix+"would write" || writer.executed {
+		t.Errorf("expected a simulated call, got %+v (%v), executed=%v", result, err, writer.executed)
+	}
+
+	result, _ = executor.ExecuteWithoutPermission(context.Background(), "reader", nil)
+	if result.Output != "mock output" {
+		t.Errorf("expected read-only tools to run in dry-run mode, got %q", result.Output)
+	}
+
+	executor.SetDryRun(false)
+	result, _ = executor.ExecuteWithoutPermission(context.Background(), "writer", nil)
+	if result.Output != "written" || !writer.executed {
+		t.Errorf("expected a real call with dry run off, got %q", result.Output)
+	}
+}
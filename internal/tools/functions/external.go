@@ -0,0 +1,204 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functions
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/antenore/deecli/internal/config"
+	"github.com/antenore/deecli/internal/tools"
+)
+
+// DefaultExternalTimeout bounds an external tool run when no timeout is configured
+const DefaultExternalTimeout = 30 * time.Second
+
+// externalNamePattern matches the function names accepted by the API
+var externalNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`)
+
+// External implements a tool function backed by an external executable.
+// The arguments are written to its stdin as JSON and the result is read from
+// its stdout, either as {"output": "..."} / {"error": "..."} or as plain text.
+type External struct {
+	name        string
+	description string
+	parameters  map[string]interface{}
+	command     []string
+	timeout     time.Duration
+	readOnly    bool
+}
+
+// NewExternal builds an external tool from its configuration, reading the
+// manifest file first when one is given. Tools not declared read-only are
+// only simulated in dry-run mode.
+func NewExternal(spec config.ExternalTool) (tools.ToolFunction, error) {
+	tool, err := newExternal(spec)
+	if err != nil {
+		return nil, err
+	}
+	if tool.readOnly {
+		return tool, nil
+	}
+	return &simulatedExternal{tool}, nil
+}
+
+// newExternal builds the external tool described by spec
+func newExternal(spec config.ExternalTool) (*External, error) {
+	if spec.Manifest != "" {
+		data, err := os.ReadFile(spec.Manifest)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read tool manifest: %w", err)
+		}
+		var manifest config.ExternalTool
+		if err := json.Unmarshal(data, &manifest); err != nil {
+			return nil, fmt.Errorf("invalid tool manifest %s: %w", spec.Manifest, err)
+		}
+		// Commands given as relative paths are relative to the manifest
+		if len(manifest.Command) > 0 && strings.ContainsRune(manifest.Command[0], filepath.Separator) && !filepath.IsAbs(manifest.Command[0]) {
+			manifest.Command[0] = filepath.Join(filepath.Dir(spec.Manifest), manifest.Command[0])
+		}
+		if spec.Name == "" {
+			spec.Name = manifest.Name
+		}
+		if spec.Description == "" {
+			spec.Description = manifest.Description
+		}
+		if spec.Parameters == nil {
+			spec.Parameters = manifest.Parameters
+		}
+		if len(spec.Command) == 0 {
+			spec.Command = manifest.Command
+		}
+		if spec.Timeout == 0 {
+			spec.Timeout = manifest.Timeout
+		}
+		spec.ReadOnly = spec.ReadOnly || manifest.ReadOnly
+	}
+
+	if !externalNamePattern.MatchString(spec.Name) {
+		return nil, fmt.Errorf("invalid tool name %q: use letters, digits, '_' or '-' (max 64)", spec.Name)
+	}
+	if len(spec.Command) == 0 || spec.Command[0] == "" {
+		return nil, fmt.Errorf("tool %s has no command", spec.Name)
+	}
+
+	tool := &External{
+		name:        spec.Name,
+		description: spec.Description,
+		parameters:  spec.Parameters,
+		command:     spec.Command,
+		timeout:     DefaultExternalTimeout,
+		readOnly:    spec.ReadOnly,
+	}
+	if tool.description == "" {
+		tool.description = fmt.Sprintf("Run the external tool %s", spec.Name)
+	}
+	if tool.parameters == nil {
+		tool.parameters = map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{},
+		}
+	}
+	if spec.Timeout > 0 {
+		tool.timeout = time.Duration(spec.Timeout) * time.Second
+	}
+	return tool, nil
+}
+
+// Name returns the function name
+func (e *External) Name() string {
+	return e.name
+}
+
+// Description returns what this function does
+func (e *External) Description() string {
+	return e.description
+}
+
+// Parameters returns the JSON schema for parameters
+func (e *External) Parameters() map[string]interface{} {
+	return e.parameters
+}
+
+// Execute runs the executable with the arguments on stdin
+func (e *External) Execute(ctx context.Context, args json.RawMessage) (string, error) {
+	return runToolCommand(ctx, e.name, e.command, e.timeout, args)
+}
+
+// simulatedExternal is an external tool not declared read-only
+type simulatedExternal struct {
+	*External
+}
+
+// Simulate describes the command that would run
+func (e *simulatedExternal) Simulate(ctx context.Context, args json.RawMessage) (string, error) {
+	return describeToolCommand(e.command, args), nil
+}
+
+// describeToolCommand echoes a command and the arguments it would receive
+func describeToolCommand(command []string, args json.RawMessage) string {
+	if len(bytes.TrimSpace(args)) == 0 || string(args) == "null" {
+		args = json.RawMessage("{}")
+	}
+	return fmt.Sprintf("would run %s with stdin %s", strings.Join(command, " "), args)
+}
+
+// runToolCommand runs command with the JSON arguments on stdin and returns
+// the result it prints on stdout, either as {"output": "..."} / {"error": "..."}
+// or as plain text
+func runToolCommand(ctx context.Context, name string, command []string, timeout time.Duration, args json.RawMessage) (string, error) {
+	if len(bytes.TrimSpace(args)) == 0 || string(args) == "null" {
+		args = json.RawMessage("{}")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	cmd.Stdin = bytes.NewReader(args)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.Env = append(os.Environ(), "DEECLI_TOOL="+name)
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return "", fmt.Errorf("%s timed out after %s", name, timeout)
+		}
+		return "", fmt.Errorf("%s failed: %w\n%s", name, err, strings.TrimSpace(stderr.String()))
+	}
+
+	var result struct {
+		Output *string `json:"output"`
+		Error  string  `json:"error"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &result); err == nil {
+		if result.Error != "" {
+			return "", errors.New(result.Error)
+		}
+		if result.Output != nil {
+			return *result.Output, nil
+		}
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
@@ -0,0 +1,113 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functions
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/antenore/deecli/internal/config"
+	"github.com/antenore/deecli/internal/tools"
+)
+
+func TestExternalTool_Execute(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+
+	echo, err := NewExternal(config.ExternalTool{Name: "echo_args", Command: []string{"sh", "-c", "cat"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result, err := echo.Execute(context.Background(), json.RawMessage(`{"query":"x"}`))
+	if err != nil || result != `{"query":"x"}` {
+		t.Errorf("expected arguments on stdin, got %q (%v)", result, err)
+	}
+
+	structured, _ := NewExternal(config.ExternalTool{Name: "structured", Command: []string{"sh", "-c", `echo '{"output":"done"}'`}})
+	if result, err := structured.Execute(context.Background(), nil); err != nil || result != "done" {
+		t.Errorf("expected the output field, got %q (%v)", result, err)
+	}
+
+	failing, _ := NewExternal(config.ExternalTool{Name: "failing", Command: []string{"sh", "-c", `echo '{"error":"boom"}'`}})
+	if _, err := failing.Execute(context.Background(), nil); err == nil || err.Error() != "boom" {
+		t.Errorf("expected the error field as error, got %v", err)
+	}
+
+	crashing, _ := NewExternal(config.ExternalTool{Name: "crashing", Command: []string{"sh", "-c", "echo oops >&2; exit 3"}})
+	if _, err := crashing.Execute(context.Background(), nil); err == nil || !strings.Contains(err.Error(), "oops") {
+		t.Errorf("expected stderr in the error, got %v", err)
+	}
+}
+
+func TestNewExternal_Manifest(t *testing.T) {
+	dir := t.TempDir()
+	manifest := filepath.Join(dir, "lint.json")
+	data := `{
+		"name": "lint",
+		"description": "Run the linter",
+		"parameters": {"type": "object", "properties": {"path": {"type": "string"}}},
+		"command": ["bin/lint", "--json"],
+		"timeout": 5
+	}`
+	if err := os.WriteFile(manifest, []byte(data), 0644); err != nil {
+		t.Fatalf("Failed to write manifest: %v", err)
+	}
+
+	tool, err := newExternal(config.ExternalTool{Manifest: manifest, Description: "Lint a file"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tool.Name() != "lint" || tool.Description() != "Lint a file" {
+		t.Errorf("expected manifest name and inline description, got %q, %q", tool.Name(), tool.Description())
+	}
+	if _, ok := tool.Parameters()["properties"]; !ok {
+		t.Errorf("expected the manifest parameters, got %v", tool.Parameters())
+	}
+	if want := filepath.Join(dir, "bin", "lint"); tool.command[0] != want {
+		t.Errorf("expected command relative to the manifest %q, got %q", want, tool.command[0])
+	}
+
+	if _, err := NewExternal(config.ExternalTool{Name: "bad name", Command: []string{"true"}}); err == nil {
+		t.Error("expected an error for an invalid name")
+	}
+	if _, err := NewExternal(config.ExternalTool{Name: "nocommand"}); err == nil {
+		t.Error("expected an error for a missing command")
+	}
+}
+
+func TestNewExternal_DryRun(t *testing.T) {
+	tool, err := NewExternal(config.ExternalTool{Name: "deploy", Command: []string{"./deploy.sh", "--prod"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	simulator, ok := tool.(tools.Simulator)
+	if !ok {
+		t.Fatal("expected tools with side effects to be simulated in dry-run mode")
+	}
+	result, _ := simulator.Simulate(context.Background(), json.RawMessage(`{"env":"prod"}`))
+	if result != `would run ./deploy.sh --prod with stdin {"env":"prod"}` {
+		t.Errorf("unexpected simulation %q", result)
+	}
+
+	readOnly, _ := NewExternal(config.ExternalTool{Name: "search", Command: []string{"search"}, ReadOnly: true})
+	if _, ok := readOnly.(tools.Simulator); ok {
+		t.Error("expected read-only tools to run in dry-run mode")
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/antenore/deecli/internal/forge"
+)
+
+// maxPRDiffSize caps the diff get_pr_diff returns to the model
+const maxPRDiffSize = 200 * 1024
+
+// GetIssue implements the get_issue tool function for the origin forge
+type GetIssue struct {
+	Tokens forge.Tokens
+}
+
+// Name returns the function name
+func (g *GetIssue) Name() string {
+	return "get_issue"
+}
+
+// Description returns what this function does
+func (g *GetIssue) Description() string {
+	return "Get the title, state, author and description of an issue from the project's GitHub or GitLab repository"
+}
+
+// Parameters returns the JSON schema for parameters
+func (g *GetIssue) Parameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"number": map[string]interface{}{
+				"type":        "integer",
+				"description": "Issue number",
+			},
+		},
+		"required": []string{"number"},
+	}
+}
+
+// Execute fetches the issue from the forge of the origin remote
+func (g *GetIssue) Execute(ctx context.Context, args json.RawMessage) (string, error) {
+	number, err := parseForgeNumber(args)
+	if err != nil {
+		return "", err
+	}
+	client, err := forgeClient(ctx, g.Tokens)
+	if err != nil {
+		return "", err
+	}
+	issue, err := client.GetIssue(ctx, number)
+	if err != nil {
+		return "", err
+	}
+	return formatForgeItem("Issue", issue), nil
+}
+
+// GetPRDiff implements the get_pr_diff tool function for the origin forge
+type GetPRDiff struct {
+	Tokens forge.Tokens
+}
+
+// Name returns the function name
+func (g *GetPRDiff) Name() string {
+	return "get_pr_diff"
+}
+
+// Description returns what this function does
+func (g *GetPRDiff) Description() string {
+	return "Get the description and unified diff of a pull request (GitHub) or merge request (GitLab) of the project's repository"
+}
+
+// Parameters returns the JSON schema for parameters
+func (g *GetPRDiff) Parameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"number": map[string]interface{}{
+				"type":        "integer",
+				"description": "Pull request or merge request number",
+			},
+		},
+		"required": []string{"number"},
+	}
+}
+
+// Execute fetches the pull request and its diff from the forge of the origin remote
+func (g *GetPRDiff) Execute(ctx context.Context, args json.RawMessage) (string, error) {
+	number, err := parseForgeNumber(args)
+	if err != nil {
+		return "", err
+	}
+	client, err := forgeClient(ctx, g.Tokens)
+	if err != nil {
+		return "", err
+	}
+	pr, err := client.GetPullRequest(ctx, number)
+	if err != nil {
+		return "", err
+	}
+
+	diff := pr.Diff
+	if len(diff) > maxPRDiffSize {
+		diff = diff[:maxPRDiffSize] + fmt.Sprintf("\n[diff truncated: showing %d of %d bytes]", maxPRDiffSize, len(pr.Diff))
+	}
+	return formatForgeItem("Pull request", &pr.Issue) + "\n\n```diff\n" + diff + "\n```", nil
+}
+
+// parseForgeNumber reads the required issue or pull request number
+func parseForgeNumber(args json.RawMessage) (int, error) {
+	var params struct {
+		Number int `json:"number"`
+	}
+	if err := json.Unmarshal(args, &params); err != nil {
+		return 0, fmt.Errorf("failed to parse arguments: %w", err)
+	}
+	if params.Number <= 0 {
+		return 0, fmt.Errorf("number must be a positive issue or pull request number")
+	}
+	return params.Number, nil
+}
+
+// forgeClient returns a client for the origin remote of the working directory
+func forgeClient(ctx context.Context, tokens forge.Tokens) (*forge.Client, error) {
+	remote, err := forge.DetectRemote(ctx, ".")
+	if err != nil {
+		return nil, err
+	}
+	return forge.NewClient(remote, tokens), nil
+}
+
+// formatForgeItem renders an issue or pull request header and description
+func formatForgeItem(kind string, issue *forge.Issue) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("%s #%d: %s\n", kind, issue.Number, issue.Title))
+	b.WriteString(fmt.Sprintf("State: %s\nAuthor: %s\nURL: %s\n", issue.State, issue.Author, issue.URL))
+	if body := strings.TrimSpace(issue.Body); body != "" {
+		b.WriteString("\n" + body)
+	} else {
+		b.WriteString("\n(no description)")
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
@@ -34,6 +34,11 @@ func (g *GitDiff) Description() string {
 	return "Show changes between commits, commit and working tree, etc"
 }
 
+// PathArguments returns the argument the executor checks against the workspace
+func (g *GitDiff) PathArguments() []string {
+	return []string{"file"}
+}
+
 // Parameters returns the JSON schema for parameters
 func (g *GitDiff) Parameters() map[string]interface{} {
 	return map[string]interface{}{
@@ -14,16 +14,23 @@
 package functions
 
 import (
+	"errors"
+	"fmt"
+
+	"github.com/antenore/deecli/internal/config"
+	"github.com/antenore/deecli/internal/forge"
 	"github.com/antenore/deecli/internal/tools"
 )
 
-// RegisterAll registers all built-in tool functions
-func RegisterAll() error {
+// RegisterAll registers all built-in tool functions. The file tools leave
+// out the paths .deecliignore and the ignore config patterns exclude.
+func RegisterAll(ignorePatterns []string) error {
 	functions := []tools.ToolFunction{
 		&GitStatus{},
 		&GitDiff{},
-		&ListFiles{},
-		&ReadFile{},
+		&ListFiles{IgnorePatterns: ignorePatterns},
+		&ReadFile{IgnorePatterns: ignorePatterns},
+		&ReadMore{},
 	}
 
 	for _, fn := range functions {
@@ -33,4 +40,58 @@ func RegisterAll() error {
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+// RegisterExternal registers the tools provided by external executables.
+// Invalid definitions are skipped and reported together in the returned error.
+func RegisterExternal(specs []config.ExternalTool) error {
+	var errs []error
+	for _, spec := range specs {
+		tool, err := NewExternal(spec)
+		if err == nil {
+			err = tools.Register(tool)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("external tool %s: %w", externalLabel(spec), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// RegisterPlugins registers the sandboxed WASM tool plugins described by the
+// given manifests, run with the given WASI runtime. The project copy an
+// fs-read plugin gets leaves out the paths the ignore patterns exclude.
+// Invalid manifests are skipped and reported together in the returned error.
+func RegisterPlugins(manifests []string, runtime string, ignorePatterns []string) error {
+	var errs []error
+	for _, manifest := range manifests {
+		plugin, err := NewWASMPlugin(manifest, runtime)
+		if err == nil {
+			plugin.ignorePatterns = ignorePatterns
+			err = tools.Register(plugin)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("plugin %s: %w", manifest, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// RegisterForge registers the GitHub/GitLab issue and pull request tools,
+// authenticating with the given tokens
+func RegisterForge(tokens forge.Tokens) error {
+	for _, fn := range []tools.ToolFunction{&GetIssue{Tokens: tokens}, &GetPRDiff{Tokens: tokens}} {
+		if err := tools.Register(fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// externalLabel names a tool definition in error messages
+func externalLabel(spec config.ExternalTool) string {
+	if spec.Name != "" {
+		return spec.Name
+	}
+	return spec.Manifest
+}
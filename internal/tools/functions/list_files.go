@@ -21,10 +21,14 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+
+	deecliFiles "github.com/antenore/deecli/internal/files"
 )
 
 // ListFiles implements file listing tool function
-type ListFiles struct{}
+type ListFiles struct {
+	IgnorePatterns []string // Ignore config patterns, applied with .deecliignore
+}
 
 // Name returns the function name
 func (l *ListFiles) Name() string {
@@ -36,6 +40,11 @@ func (l *ListFiles) Description() string {
 	return "List files in a directory. Examples: {} lists current dir, {\"recursive\":true} lists all files recursively, {\"path\":\"internal\",\"recursive\":true} lists internal/ recursively"
 }
 
+// PathArguments returns the argument the executor checks against the workspace
+func (l *ListFiles) PathArguments() []string {
+	return []string{"path"}
+}
+
 // Parameters returns the JSON schema for parameters
 func (l *ListFiles) Parameters() map[string]interface{} {
 	return map[string]interface{}{
@@ -99,6 +108,9 @@ func (l *ListFiles) Execute(ctx context.Context, args json.RawMessage) (string,
 
 	var files []string
 
+	// Respect .deecliignore and the ignore config so excluded paths are never offered to the AI
+	ignoreFilter := deecliFiles.NewGitignoreFilterWithPatterns(false, l.IgnorePatterns)
+
 	if params.Recursive {
 		// Walk directory tree
 		err = filepath.Walk(params.Path, func(path string, info os.FileInfo, err error) error {
@@ -111,6 +123,14 @@ func (l *ListFiles) Execute(ctx context.Context, args json.RawMessage) (string,
 				return filepath.SkipDir
 			}
 
+			// Skip paths excluded by .deecliignore
+			if path != params.Path && ignoreFilter.IsExcluded(path) {
+				if info.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+
 			// Skip directories unless they match pattern
 			if info.IsDir() {
 				return nil
@@ -152,6 +172,9 @@ func (l *ListFiles) Execute(ctx context.Context, args json.RawMessage) (string,
 			}
 
 			path := filepath.Join(params.Path, entry.Name())
+			if ignoreFilter.IsExcluded(path) {
+				continue
+			}
 			if entry.IsDir() {
 				path += "/"
 			}
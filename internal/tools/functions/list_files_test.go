@@ -326,4 +326,35 @@ func TestListFilesTool_HiddenFiles(t *testing.T) {
 	// Behavior with hidden files may vary by implementation
 	// This test just ensures it doesn't crash
 	t.Logf("Output with hidden files: %s", output)
-}
\ No newline at end of file
+}
+
+func TestListFilesTool_IgnorePatterns(t *testing.T) {
+	t.Chdir(t.TempDir())
+	for _, path := range []string{"main.go", "notes.txt", "server.pem", filepath.Join("secrets", "api.key")} {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("test"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(".deecliignore", []byte("notes.txt\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tool := &ListFiles{IgnorePatterns: []string{"*.pem", "secrets/"}}
+	for _, args := range []string{`{}`, `{"recursive":true}`} {
+		result, err := tool.Execute(context.Background(), json.RawMessage(args))
+		if err != nil {
+			t.Fatalf("Execute(%s) error = %v", args, err)
+		}
+		if !strings.Contains(result, "main.go") {
+			t.Errorf("Execute(%s) = %q, want main.go listed", args, result)
+		}
+		for _, excluded := range []string{"notes.txt", "server.pem", "api.key"} {
+			if strings.Contains(result, excluded) {
+				t.Errorf("Execute(%s) = %q, want %s left out", args, result, excluded)
+			}
+		}
+	}
+}
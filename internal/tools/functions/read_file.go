@@ -20,10 +20,14 @@ import (
 	"fmt"
 	"os"
 	"strings"
+
+	deecliFiles "github.com/antenore/deecli/internal/files"
 )
 
 // ReadFile implements file reading tool function
-type ReadFile struct{}
+type ReadFile struct {
+	IgnorePatterns []string // Ignore config patterns, applied with .deecliignore
+}
 
 // Name returns the function name
 func (r *ReadFile) Name() string {
@@ -35,6 +39,11 @@ func (r *ReadFile) Description() string {
 	return "Read a file. Examples: {\"path\":\"TODO.md\"}, {\"path\":\"main.go\"}, {\"path\":\"internal/api/client.go\"}"
 }
 
+// PathArguments returns the argument the executor checks against the workspace
+func (r *ReadFile) PathArguments() []string {
+	return []string{"path"}
+}
+
 // Parameters returns the JSON schema for parameters
 func (r *ReadFile) Parameters() map[string]interface{} {
 	return map[string]interface{}{
@@ -92,6 +101,11 @@ func (r *ReadFile) Execute(ctx context.Context, args json.RawMessage) (string, e
 		return "", fmt.Errorf("path is required. Use: {\"path\":\"filename\"} e.g., {\"path\":\"TODO.md\"}")
 	}
 
+	// Excluded files stay hidden even when the AI names them
+	if deecliFiles.NewGitignoreFilterWithPatterns(false, r.IgnorePatterns).IsExcluded(params.Path) {
+		return "", fmt.Errorf("%s is excluded by .deecliignore or the ignore config", params.Path)
+	}
+
 	// Open the file
 	file, err := os.Open(params.Path)
 	if err != nil {
@@ -18,6 +18,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -275,4 +276,35 @@ func toLower(s string) string {
 		}
 	}
 	return string(result)
-}
\ No newline at end of file
+}
+
+func TestReadFileTool_IgnorePatterns(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	for _, name := range []string{"main.go", "notes.txt", "server.pem"} {
+		if err := os.WriteFile(name, []byte("test"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(".deecliignore", []byte("notes.txt\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tool := &ReadFile{IgnorePatterns: []string{"*.pem"}}
+	tests := []struct {
+		path     string
+		excluded bool
+	}{
+		{"main.go", false},
+		{"notes.txt", true},
+		{"server.pem", true},
+		{filepath.Join(dir, "server.pem"), true},
+	}
+	for _, tt := range tests {
+		args, _ := json.Marshal(map[string]string{"path": tt.path})
+		_, err := tool.Execute(context.Background(), json.RawMessage(args))
+		if excluded := err != nil && strings.Contains(err.Error(), "excluded"); excluded != tt.excluded {
+			t.Errorf("Execute(%s) error = %v, want excluded %v", tt.path, err, tt.excluded)
+		}
+	}
+}
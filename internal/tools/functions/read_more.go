@@ -0,0 +1,126 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+const (
+	readMoreDefaultLength = 32 * 1024
+	readMoreMaxLength     = 128 * 1024
+)
+
+// ReadMore implements chunked reading of large files that were only previewed in context
+type ReadMore struct{}
+
+// Name returns the function name
+func (r *ReadMore) Name() string {
+	return "read_more"
+}
+
+// Description returns what this function does
+func (r *ReadMore) Description() string {
+	return "Read the next chunk of a large file shown as a preview. Example: {\"path\":\"data/big.log\",\"offset\":32768}"
+}
+
+// PathArguments returns the argument the executor checks against the workspace
+func (r *ReadMore) PathArguments() []string {
+	return []string{"path"}
+}
+
+// Parameters returns the JSON schema for parameters
+func (r *ReadMore) Parameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"path": map[string]interface{}{
+				"type":        "string",
+				"description": "File path to read (required)",
+			},
+			"offset": map[string]interface{}{
+				"type":        "integer",
+				"description": "Byte offset to start reading from, as given in the preview note (required)",
+				"minimum":     0,
+			},
+			"length": map[string]interface{}{
+				"type":        "integer",
+				"description": fmt.Sprintf("Number of bytes to read (optional, default %d, max %d)", readMoreDefaultLength, readMoreMaxLength),
+				"minimum":     1,
+			},
+		},
+		"required":             []string{"path", "offset"},
+		"additionalProperties": false,
+	}
+}
+
+// Execute reads one chunk of the specified file
+func (r *ReadMore) Execute(ctx context.Context, args json.RawMessage) (string, error) {
+	var params struct {
+		Path   string `json:"path"`
+		Offset int64  `json:"offset"`
+		Length int64  `json:"length"`
+	}
+
+	if err := json.Unmarshal(args, &params); err != nil {
+		return "", fmt.Errorf("invalid JSON format. Use: {\"path\":\"filename\",\"offset\":0}")
+	}
+	if params.Path == "" {
+		return "", fmt.Errorf("path is required. Use: {\"path\":\"filename\",\"offset\":0}")
+	}
+	if params.Offset < 0 {
+		return "", fmt.Errorf("offset must not be negative")
+	}
+	if params.Length <= 0 {
+		params.Length = readMoreDefaultLength
+	}
+	if params.Length > readMoreMaxLength {
+		params.Length = readMoreMaxLength
+	}
+
+	file, err := os.Open(params.Path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("file not found: %s. Use list_files to see available files", params.Path)
+		}
+		return "", fmt.Errorf("cannot open %s: %w", params.Path, err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return "", fmt.Errorf("cannot stat file: %w", err)
+	}
+	size := info.Size()
+	if params.Offset >= size {
+		return fmt.Sprintf("[end of file: %s is %d bytes]", params.Path, size), nil
+	}
+
+	buf := make([]byte, params.Length)
+	n, err := file.ReadAt(buf, params.Offset)
+	if err != nil && err != io.EOF {
+		return "", fmt.Errorf("error reading file: %w", err)
+	}
+
+	end := params.Offset + int64(n)
+	footer := fmt.Sprintf("[bytes %d-%d of %d; next offset: %d]", params.Offset, end, size, end)
+	if end >= size {
+		footer = fmt.Sprintf("[bytes %d-%d of %d; end of file]", params.Offset, end, size)
+	}
+	return string(buf[:n]) + "\n" + footer, nil
+}
@@ -0,0 +1,64 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functions
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadMoreTool_Execute(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "big.txt")
+	if err := os.WriteFile(path, []byte("0123456789"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	tool := &ReadMore{}
+	run := func(args map[string]interface{}) (string, error) {
+		raw, _ := json.Marshal(args)
+		return tool.Execute(context.Background(), raw)
+	}
+
+	result, err := run(map[string]interface{}{"path": path, "offset": 2, "length": 4})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(result, "2345\n") || !strings.Contains(result, "next offset: 6") {
+		t.Errorf("unexpected chunk: %q", result)
+	}
+
+	result, err = run(map[string]interface{}{"path": path, "offset": 6})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(result, "6789\n") || !strings.Contains(result, "end of file") {
+		t.Errorf("unexpected final chunk: %q", result)
+	}
+
+	result, err = run(map[string]interface{}{"path": path, "offset": 10})
+	if err != nil || !strings.Contains(result, "end of file") {
+		t.Errorf("expected end of file notice, got %q (%v)", result, err)
+	}
+
+	if _, err := run(map[string]interface{}{"path": path, "offset": -1}); err == nil {
+		t.Error("expected error for negative offset")
+	}
+	if _, err := run(map[string]interface{}{"offset": 0}); err == nil {
+		t.Error("expected error for missing path")
+	}
+}
@@ -0,0 +1,224 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/antenore/deecli/internal/files"
+	"github.com/antenore/deecli/internal/tools"
+)
+
+// pluginManifest is the JSON manifest distributed with a WASM tool plugin
+type pluginManifest struct {
+	Name         string                 `json:"name"`
+	Description  string                 `json:"description"`
+	Parameters   map[string]interface{} `json:"parameters"`
+	Module       string                 `json:"module"`       // WASI module, relative to the manifest
+	Capabilities []string               `json:"capabilities"` // Host resources the plugin needs
+	Timeout      int                    `json:"timeout"`      // Seconds before the plugin is stopped
+}
+
+// WASMPlugin implements a sandboxed tool function distributed as a WASI
+// module. It runs in a WASI runtime with no access to the host except the
+// capabilities it declares and the user grants for the call.
+type WASMPlugin struct {
+	name         string
+	description  string
+	parameters   map[string]interface{}
+	module       string
+	capabilities []tools.Capability
+	runtime      string
+	timeout      time.Duration
+
+	// Ignore config patterns, left out of the project copy fs-read grants
+	ignorePatterns []string
+}
+
+// NewWASMPlugin loads a plugin from its manifest; runtime is the
+// wasmtime-compatible command used to run the module
+func NewWASMPlugin(manifestPath, runtime string) (*WASMPlugin, error) {
+	data, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plugin manifest: %w", err)
+	}
+	var manifest pluginManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("invalid plugin manifest %s: %w", manifestPath, err)
+	}
+
+	if !externalNamePattern.MatchString(manifest.Name) {
+		return nil, fmt.Errorf("invalid plugin name %q: use letters, digits, '_' or '-' (max 64)", manifest.Name)
+	}
+	if manifest.Module == "" {
+		return nil, fmt.Errorf("plugin %s has no module", manifest.Name)
+	}
+
+	plugin := &WASMPlugin{
+		name:        manifest.Name,
+		description: manifest.Description,
+		parameters:  manifest.Parameters,
+		module:      manifest.Module,
+		runtime:     runtime,
+		timeout:     DefaultExternalTimeout,
+	}
+	if !filepath.IsAbs(plugin.module) {
+		plugin.module = filepath.Join(filepath.Dir(manifestPath), plugin.module)
+	}
+	for _, name := range manifest.Capabilities {
+		c, err := tools.ParseCapability(name)
+		if err != nil {
+			return nil, fmt.Errorf("plugin %s: %w", manifest.Name, err)
+		}
+		if !tools.HasCapability(plugin.capabilities, c) {
+			plugin.capabilities = append(plugin.capabilities, c)
+		}
+	}
+	if plugin.description == "" {
+		plugin.description = fmt.Sprintf("Run the plugin %s", manifest.Name)
+	}
+	if plugin.parameters == nil {
+		plugin.parameters = map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{},
+		}
+	}
+	if manifest.Timeout > 0 {
+		plugin.timeout = time.Duration(manifest.Timeout) * time.Second
+	}
+	return plugin, nil
+}
+
+// Name returns the function name
+func (p *WASMPlugin) Name() string {
+	return p.name
+}
+
+// Description returns what this function does
+func (p *WASMPlugin) Description() string {
+	return p.description
+}
+
+// Parameters returns the JSON schema for parameters
+func (p *WASMPlugin) Parameters() map[string]interface{} {
+	return p.parameters
+}
+
+// Capabilities returns the host resources the plugin declares
+func (p *WASMPlugin) Capabilities() []tools.Capability {
+	return p.capabilities
+}
+
+// Execute runs the module with the arguments on stdin, enabling only the
+// declared capabilities that ctx grants
+func (p *WASMPlugin) Execute(ctx context.Context, args json.RawMessage) (string, error) {
+	if _, err := exec.LookPath(p.runtime); err != nil {
+		return "", fmt.Errorf("WASM runtime %q not found: install wasmtime or set wasm_runtime", p.runtime)
+	}
+
+	granted := tools.GrantedCapabilities(ctx)
+	var snapshot string
+	if p.enabled(granted, tools.CapabilityFSRead) && !p.enabled(granted, tools.CapabilityFSWrite) {
+		dir, err := snapshotProject(p.ignorePatterns)
+		if err != nil {
+			return "", fmt.Errorf("failed to copy the project for plugin %s: %w", p.name, err)
+		}
+		defer os.RemoveAll(dir)
+		snapshot = dir
+	}
+	return runToolCommand(ctx, p.name, p.command(granted, snapshot), p.timeout, args)
+}
+
+// Simulate describes the runtime invocation the granted capabilities would
+// produce, without copying the project for fs-read
+func (p *WASMPlugin) Simulate(ctx context.Context, args json.RawMessage) (string, error) {
+	return describeToolCommand(p.command(tools.GrantedCapabilities(ctx), "<copy of the project>"), args), nil
+}
+
+// enabled reports whether the plugin declares c and granted includes it
+func (p *WASMPlugin) enabled(granted []tools.Capability, c tools.Capability) bool {
+	return tools.HasCapability(p.capabilities, c) && tools.HasCapability(granted, c)
+}
+
+// command builds the runtime invocation for the granted capabilities;
+// snapshot is the project copy preopened for fs-read
+func (p *WASMPlugin) command(granted []tools.Capability, snapshot string) []string {
+	command := []string{p.runtime, "run"}
+	for _, c := range p.capabilities {
+		if !tools.HasCapability(granted, c) {
+			continue
+		}
+		switch c {
+		case tools.CapabilityFSRead:
+			// The runtime preopens directories read-write, so the plugin
+			// reads a copy of the project and its changes are thrown away.
+			// fs-write already gives it the project itself.
+			if !p.enabled(granted, tools.CapabilityFSWrite) {
+				command = append(command, "--dir", snapshot+"::.")
+			}
+		case tools.CapabilityFSWrite:
+			// Preopen the project directory read-write as the guest's "."
+			command = append(command, "--dir", ".")
+		case tools.CapabilityNet:
+			command = append(command, "-S", "inherit-network", "-S", "allow-ip-name-lookup")
+		}
+	}
+	return append(command, p.module)
+}
+
+// snapshotProject copies the project directory to a new temporary directory,
+// leaving out .git, symbolic links, which could lead outside the project,
+// and the paths .gitignore, .deecliignore and ignorePatterns exclude
+func snapshotProject(ignorePatterns []string) (string, error) {
+	dir, err := os.MkdirTemp("", "deecli-plugin-")
+	if err != nil {
+		return "", err
+	}
+	filter := files.NewGitignoreFilterWithPatterns(true, ignorePatterns)
+	err = filepath.WalkDir(".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || path == "." {
+			return err
+		}
+		if d.Name() == ".git" || filter.ShouldIgnore(path) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		target := filepath.Join(dir, path)
+		switch {
+		case d.IsDir():
+			return os.Mkdir(target, 0755)
+		case d.Type().IsRegular():
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			return os.WriteFile(target, data, 0644)
+		}
+		return nil
+	})
+	if err != nil {
+		os.RemoveAll(dir)
+		return "", err
+	}
+	return dir, nil
+}
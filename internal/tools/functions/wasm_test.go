@@ -0,0 +1,119 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functions
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/antenore/deecli/internal/tools"
+)
+
+func writePluginManifest(t *testing.T, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "plugin.json")
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatalf("Failed to write manifest: %v", err)
+	}
+	return path
+}
+
+func TestNewWASMPlugin(t *testing.T) {
+	manifest := writePluginManifest(t, `{
+		"name": "word_count",
+		"description": "Count words in project files",
+		"module": "word_count.wasm",
+		"capabilities": ["fs-read", "net", "fs-read"]
+	}`)
+
+	plugin, err := NewWASMPlugin(manifest, "wasmtime")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := filepath.Join(filepath.Dir(manifest), "word_count.wasm"); plugin.module != want {
+		t.Errorf("expected module relative to the manifest %q, got %q", want, plugin.module)
+	}
+	if want := []tools.Capability{tools.CapabilityFSRead, tools.CapabilityNet}; !reflect.DeepEqual(plugin.Capabilities(), want) {
+		t.Errorf("expected capabilities %v, got %v", want, plugin.Capabilities())
+	}
+
+	if _, err := NewWASMPlugin(writePluginManifest(t, `{"name": "p", "module": "p.wasm", "capabilities": ["fs-exec"]}`), "wasmtime"); err == nil {
+		t.Error("expected an error for an unknown capability")
+	}
+	if _, err := NewWASMPlugin(writePluginManifest(t, `{"name": "p"}`), "wasmtime"); err == nil {
+		t.Error("expected an error for a missing module")
+	}
+}
+
+func TestWASMPlugin_Command(t *testing.T) {
+	plugin := &WASMPlugin{
+		module:       "p.wasm",
+		runtime:      "wasmtime",
+		capabilities: []tools.Capability{tools.CapabilityFSRead, tools.CapabilityNet},
+	}
+
+	if got, want := plugin.command(nil, ""), []string{"wasmtime", "run", "p.wasm"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("without grants expected %v, got %v", want, got)
+	}
+	if got, want := plugin.command([]tools.Capability{tools.CapabilityFSRead}, "/tmp/copy"), []string{"wasmtime", "run", "--dir", "/tmp/copy::.", "p.wasm"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("with fs-read expected %v, got %v", want, got)
+	}
+
+	// fs-write preopens the project itself, which fs-read then needs no copy of
+	plugin.capabilities = []tools.Capability{tools.CapabilityFSRead, tools.CapabilityFSWrite}
+	all := []tools.Capability{tools.CapabilityFSRead, tools.CapabilityFSWrite}
+	if got, want := plugin.command(all, ""), []string{"wasmtime", "run", "--dir", ".", "p.wasm"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("with fs-write expected %v, got %v", want, got)
+	}
+
+	// Capabilities granted but not declared are not enabled
+	plugin.capabilities = nil
+	if got, want := plugin.command([]tools.Capability{tools.CapabilityNet}, ""), []string{"wasmtime", "run", "p.wasm"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("undeclared capability expected %v, got %v", want, got)
+	}
+}
+
+func TestSnapshotProject(t *testing.T) {
+	t.Chdir(t.TempDir())
+	os.MkdirAll(filepath.Join("src", "vendor"), 0755)
+	os.MkdirAll(".git", 0755)
+	os.WriteFile(filepath.Join("src", "main.go"), []byte("package main\n"), 0644)
+	os.WriteFile(filepath.Join("src", "vendor", "lib.go"), []byte("package lib\n"), 0644)
+	os.WriteFile(filepath.Join(".git", "config"), []byte("[core]\n"), 0644)
+	os.WriteFile(".env", []byte("TOKEN=secret\n"), 0644)
+	os.WriteFile(".deecliignore", []byte(".env\n"), 0644)
+
+	dir, err := snapshotProject([]string{"src/vendor/"})
+	if err != nil {
+		t.Fatalf("snapshotProject failed: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	if data, err := os.ReadFile(filepath.Join(dir, "src", "main.go")); err != nil || string(data) != "package main\n" {
+		t.Errorf("expected src/main.go to be copied, got %q, %v", data, err)
+	}
+	for _, excluded := range []string{".git", ".env", filepath.Join("src", "vendor", "lib.go")} {
+		if _, err := os.Stat(filepath.Join(dir, excluded)); err == nil {
+			t.Errorf("expected %s to be left out of the copy", excluded)
+		}
+	}
+
+	// Changes to the copy do not reach the project
+	os.WriteFile(filepath.Join(dir, "src", "main.go"), []byte("changed"), 0644)
+	if data, _ := os.ReadFile(filepath.Join("src", "main.go")); string(data) != "package main\n" {
+		t.Errorf("expected the project file unchanged, got %q", data)
+	}
+}
@@ -33,6 +33,21 @@ type ToolFunction interface {
 	Execute(ctx context.Context, args json.RawMessage) (string, error)
 }
 
+// SandboxedTool is a tool function that runs isolated from the host and can
+// only use the capabilities it declares and is granted
+type SandboxedTool interface {
+	ToolFunction
+
+	// Capabilities returns the host resources the tool needs
+	Capabilities() []Capability
+}
+
+// Simulator is implemented by tools that write files or run commands. In
+// dry-run mode Simulate describes what Execute would do instead of doing it.
+type Simulator interface {
+	Simulate(ctx context.Context, args json.RawMessage) (string, error)
+}
+
 // PermissionLevel represents the permission level for a tool
 type PermissionLevel string
 
@@ -66,9 +81,12 @@ type ExecutionResult struct {
 
 // ApprovalRequest represents a request for user approval
 type ApprovalRequest struct {
-	FunctionName string                 `json:"function_name"`
-	Description  string                 `json:"description"`
-	Arguments    map[string]interface{} `json:"arguments"`
+	FunctionName     string                 `json:"function_name"`
+	Description      string                 `json:"description"`
+	Arguments        map[string]interface{} `json:"arguments"`
+	Capabilities     []Capability           `json:"capabilities,omitempty"`      // Granted to a sandboxed tool on approval
+	Simulated        bool                   `json:"simulated,omitempty"`         // Dry-run mode: the call is only simulated
+	OutsideWorkspace []string               `json:"outside_workspace,omitempty"` // Paths outside the workspace, approved for this call only
 }
 
 // ApprovalResponse represents user's approval decision
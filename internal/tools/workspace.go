@@ -0,0 +1,159 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+)
+
+// PathTool is implemented by tools that take file system paths. The
+// executor checks the paths against the workspace before the tool runs, so
+// the tools themselves need not.
+type PathTool interface {
+	// PathArguments returns the names of the arguments holding paths
+	PathArguments() []string
+}
+
+// SetWorkspace sets the directory tools work in; paths outside it need the
+// user's approval for each call. The default is the current directory.
+func (e *Executor) SetWorkspace(root string) {
+	e.workspace = root
+}
+
+// Workspace returns the directory tools work in, with symbolic links resolved
+func (e *Executor) Workspace() string {
+	root := e.workspace
+	if root == "" {
+		root, _ = os.Getwd()
+	}
+	root, _ = filepath.Abs(root)
+	return resolve(root)
+}
+
+// OutsideWorkspace returns the paths a call of tool would access outside
+// the workspace, resolved to absolute paths. It fails for a path in a
+// protected location, which no approval can allow.
+func (e *Executor) OutsideWorkspace(tool ToolFunction, args json.RawMessage) ([]string, error) {
+	pathTool, ok := tool.(PathTool)
+	if !ok {
+		return nil, nil
+	}
+	var values map[string]interface{}
+	if err := json.Unmarshal(args, &values); err != nil {
+		return nil, nil // The tool reports its invalid arguments itself
+	}
+
+	root := e.Workspace()
+	var outside []string
+	for _, name := range pathTool.PathArguments() {
+		path, ok := values[name].(string)
+		if !ok || path == "" {
+			continue
+		}
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(root, path)
+		}
+		path = resolve(path)
+
+		// A protected location is only reachable when the workspace is in it
+		for _, dir := range protectedDirs() {
+			if within(dir, path) && !within(dir, root) {
+				return nil, fmt.Errorf("access to %s is not allowed: %s is a protected location", values[name], dir)
+			}
+		}
+		if !within(root, path) {
+			outside = append(outside, path)
+		}
+	}
+	return outside, nil
+}
+
+// checkPaths refuses a call of tool that accesses a protected location, or
+// a path outside the workspace that ctx does not approve
+func (e *Executor) checkPaths(ctx context.Context, tool ToolFunction, args json.RawMessage) error {
+	outside, err := e.OutsideWorkspace(tool, args)
+	if err != nil {
+		return err
+	}
+	approved := ApprovedPaths(ctx)
+	for _, path := range outside {
+		if !slices.Contains(approved, path) {
+			return fmt.Errorf("%s is outside the workspace %s and was not approved", path, e.Workspace())
+		}
+	}
+	return nil
+}
+
+type approvedPathsKey struct{}
+
+// WithApprovedPaths returns a context allowing the tool executed with it to
+// access paths outside the workspace, as returned by OutsideWorkspace. The
+// user must have approved that call.
+func WithApprovedPaths(ctx context.Context, paths []string) context.Context {
+	return context.WithValue(ctx, approvedPathsKey{}, paths)
+}
+
+// ApprovedPaths returns the paths outside the workspace approved by ctx
+func ApprovedPaths(ctx context.Context) []string {
+	paths, _ := ctx.Value(approvedPathsKey{}).([]string)
+	return paths
+}
+
+// protectedDirs returns the locations tools may never access: system
+// directories and the places credentials are kept
+func protectedDirs() []string {
+	var dirs []string
+	if runtime.GOOS == "windows" {
+		if root := os.Getenv("SystemRoot"); root != "" {
+			dirs = append(dirs, root)
+		}
+	} else {
+		dirs = append(dirs, "/etc", "/proc", "/sys", "/dev", "/boot")
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		for _, name := range []string{".ssh", ".gnupg", ".aws", ".kube", ".docker", ".deecli"} {
+			dirs = append(dirs, filepath.Join(home, name))
+		}
+	}
+	for i, dir := range dirs {
+		dirs[i] = resolve(dir)
+	}
+	return dirs
+}
+
+// resolve returns the absolute path with its symbolic links evaluated, as
+// far as it exists
+func resolve(path string) string {
+	path = filepath.Clean(path)
+	if real, err := filepath.EvalSymlinks(path); err == nil {
+		return real
+	}
+	if parent := filepath.Dir(path); parent != path {
+		return filepath.Join(resolve(parent), filepath.Base(path))
+	}
+	return path
+}
+
+// within reports whether path is dir or inside it
+func within(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,137 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// pathTool is a mock tool taking a path argument
+type pathTool struct {
+	mockTool
+}
+
+func (p *pathTool) PathArguments() []string { return []string{"path"} }
+
+func TestExecutor_OutsideWorkspace(t *testing.T) {
+	workspace := t.TempDir()
+	outsideDir := t.TempDir()
+	os.WriteFile(filepath.Join(outsideDir, "notes.txt"), []byte("notes"), 0644)
+	os.Symlink(outsideDir, filepath.Join(workspace, "link"))
+
+	executor := NewExecutor(NewRegistry(), &mockPermissionManager{allowAll: true})
+	executor.SetWorkspace(workspace)
+	tool := &pathTool{mockTool{name: "read_file"}}
+	real := resolve(outsideDir)
+
+	tests := []struct {
+		name    string
+		path    string
+		outside []string
+		wantErr bool
+	}{
+		{"relative path", "main.go", nil, false},
+		{"dot dot inside", "internal/../main.go", nil, false},
+		{"dot dot escaping", "../" + filepath.Base(outsideDir) + "/notes.txt", []string{filepath.Join(real, "notes.txt")}, false},
+		{"absolute path elsewhere", filepath.Join(outsideDir, "notes.txt"), []string{filepath.Join(real, "notes.txt")}, false},
+		{"symlink out of the workspace", "link/notes.txt", []string{filepath.Join(real, "notes.txt")}, false},
+	}
+	if runtime.GOOS != "windows" {
+		tests = append(tests, struct {
+			name    string
+			path    string
+			outside []string
+			wantErr bool
+		}{"system location", "/etc/passwd", nil, true})
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args, _ := json.Marshal(map[string]string{"path": tt.path})
+			outside, err := executor.OutsideWorkspace(tool, args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("OutsideWorkspace() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if strings.Join(outside, ",") != strings.Join(tt.outside, ",") {
+				t.Errorf("OutsideWorkspace() = %v, want %v", outside, tt.outside)
+			}
+		})
+	}
+}
+
+func TestExecutor_PathApproval(t *testing.T) {
+	outsideDir := t.TempDir()
+	registry := NewRegistry()
+	executed := false
+	tool := &pathTool{mockTool{name: "read_file", executeFunc: func(ctx context.Context, args json.RawMessage) (string, error) {
+		executed = true
+		return "content", nil
+	}}}
+	registry.Register(tool)
+
+	// Always approved in the project, but not for a path outside it
+	permissions := &recordingPermissionManager{}
+	executor := NewExecutor(registry, permissions)
+	executor.SetWorkspace(t.TempDir())
+	args, _ := json.Marshal(map[string]string{"path": filepath.Join(outsideDir, "notes.txt")})
+
+	result, err := executor.Execute(context.Background(), ExecutionRequest{FunctionName: "read_file", Arguments: args}, "")
+	if err != nil || !result.Success || !executed {
+		t.Fatalf("Execute() = %+v, %v", result, err)
+	}
+	if len(permissions.requests) != 1 || len(permissions.requests[0].OutsideWorkspace) != 1 {
+		t.Fatalf("expected one approval request naming the outside path, got %+v", permissions.requests)
+	}
+	if permissions.saved {
+		t.Error("an approval of a path outside the workspace was saved as always")
+	}
+
+	// Without an approval in the context the call is refused
+	executed = false
+	result, _ = executor.ExecuteWithoutPermission(context.Background(), "read_file", args)
+	if result.Success || executed || !strings.Contains(result.Error, "outside the workspace") {
+		t.Errorf("ExecuteWithoutPermission() = %+v, executed %v", result, executed)
+	}
+	outside, _ := executor.OutsideWorkspace(tool, args)
+	result, _ = executor.ExecuteWithoutPermission(WithApprovedPaths(context.Background(), outside), "read_file", args)
+	if !result.Success {
+		t.Errorf("approved ExecuteWithoutPermission() = %+v", result)
+	}
+}
+
+// recordingPermissionManager always approves the tool, records the approval
+// requests and answers them with "always"
+type recordingPermissionManager struct {
+	requests []ApprovalRequest
+	saved    bool
+}
+
+func (r *recordingPermissionManager) CheckPermission(functionName, projectPath string) (PermissionLevel, error) {
+	return PermissionAlways, nil
+}
+
+func (r *recordingPermissionManager) SetPermission(functionName, projectPath string, level PermissionLevel) error {
+	r.saved = true
+	return nil
+}
+
+func (r *recordingPermissionManager) RequestApproval(request ApprovalRequest) (ApprovalResponse, error) {
+	r.requests = append(r.requests, request)
+	return ApprovalResponse{Approved: true, Level: PermissionAlways}, nil
+}
@@ -0,0 +1,158 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package update
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// ChecksumsName is the release asset listing the SHA-256 of the others, in
+// the format of sha256sum
+const ChecksumsName = "checksums.txt"
+
+// maxBinarySize bounds the download of a binary
+const maxBinarySize = 256 << 20
+
+// AssetName returns the name of the release binary for a platform, e.g.
+// deecli_linux_amd64 or deecli_windows_amd64.exe
+func AssetName(goos, goarch string) string {
+	name := "deecli_" + goos + "_" + goarch
+	if goos == "windows" {
+		name += ".exe"
+	}
+	return name
+}
+
+// Install downloads the binary of release for this platform, checks it
+// against the release checksums and replaces the executable at exe with it.
+// exe is left untouched if anything fails before the replacement.
+func Install(ctx context.Context, client *http.Client, release *Release, exe string) error {
+	name := AssetName(runtime.GOOS, runtime.GOARCH)
+	asset := release.Asset(name)
+	if asset == nil {
+		return fmt.Errorf("release %s has no binary for %s/%s (%s)", release.TagName, runtime.GOOS, runtime.GOARCH, name)
+	}
+	sums := release.Asset(ChecksumsName)
+	if sums == nil {
+		return fmt.Errorf("release %s has no %s; refusing to install an unverified binary", release.TagName, ChecksumsName)
+	}
+
+	var list bytes.Buffer
+	if err := download(ctx, client, sums.URL, &list, 1<<20); err != nil {
+		return fmt.Errorf("failed to download %s: %w", ChecksumsName, err)
+	}
+	want, ok := ParseChecksums(list.String())[name]
+	if !ok {
+		return fmt.Errorf("%s has no checksum for %s", ChecksumsName, name)
+	}
+
+	info, err := os.Stat(exe)
+	if err != nil {
+		return err
+	}
+	// The new binary is written next to the old one so the rename that
+	// replaces it stays on one file system
+	tmp, err := os.CreateTemp(filepath.Dir(exe), ".deecli-update-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	hash := sha256.New()
+	err = download(ctx, client, asset.URL, io.MultiWriter(tmp, hash), maxBinarySize)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to download %s: %w", name, err)
+	}
+	if got := hex.EncodeToString(hash.Sum(nil)); got != want {
+		return fmt.Errorf("checksum mismatch for %s: got %s, want %s", name, got, want)
+	}
+	if err := os.Chmod(tmp.Name(), info.Mode().Perm()|0111); err != nil {
+		return err
+	}
+	return replaceExecutable(exe, tmp.Name())
+}
+
+// download writes the body at url to w, failing beyond limit bytes
+func download(ctx context.Context, client *http.Client, url string, w io.Writer, limit int64) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", "deecli/"+Version)
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("server returned %s", resp.Status)
+	}
+	n, err := io.Copy(w, io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return err
+	}
+	if n > limit {
+		return fmt.Errorf("larger than %d bytes", limit)
+	}
+	return nil
+}
+
+// ParseChecksums reads sha256sum output into a map from file name to
+// lowercase hex digest
+func ParseChecksums(list string) map[string]string {
+	sums := make(map[string]string)
+	scanner := bufio.NewScanner(strings.NewReader(list))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		// sha256sum marks binary mode with a * before the name
+		sums[strings.TrimPrefix(fields[1], "*")] = strings.ToLower(fields[0])
+	}
+	return sums
+}
+
+// replaceExecutable moves next over exe. A running executable cannot be
+// overwritten on Windows but can be renamed, so the old one is moved aside
+// to exe.old first, to be removed by the next update.
+func replaceExecutable(exe, next string) error {
+	if runtime.GOOS != "windows" {
+		return os.Rename(next, exe)
+	}
+	old := exe + ".old"
+	os.Remove(old)
+	if err := os.Rename(exe, old); err != nil {
+		return err
+	}
+	if err := os.Rename(next, exe); err != nil {
+		os.Rename(old, exe)
+		return err
+	}
+	return nil
+}
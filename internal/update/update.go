@@ -0,0 +1,198 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package update finds newer DeeCLI releases on GitHub and replaces the
+// running binary with one, after checking it against the release checksums.
+package update
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Version is the version of this build; release builds set it with
+// -ldflags "-X github.com/antenore/deecli/internal/update.Version=v1.2.3"
+var Version = "0.1.0"
+
+// ReleasesURL is the GitHub endpoint of the latest release
+var ReleasesURL = "https://api.github.com/repos/antenore/deecli-go/releases/latest"
+
+// CheckInterval is how long the result of a startup check is reused before
+// GitHub is asked again
+const CheckInterval = 24 * time.Hour
+
+// Release is a published DeeCLI release
+type Release struct {
+	TagName string  `json:"tag_name"`
+	Name    string  `json:"name"`
+	Body    string  `json:"body"` // Changelog, in Markdown
+	HTMLURL string  `json:"html_url"`
+	Assets  []Asset `json:"assets"`
+}
+
+// Asset is a file attached to a release
+type Asset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+	Size int64  `json:"size"`
+}
+
+// Asset returns the asset called name, or nil
+func (r *Release) Asset(name string) *Asset {
+	for i := range r.Assets {
+		if r.Assets[i].Name == name {
+			return &r.Assets[i]
+		}
+	}
+	return nil
+}
+
+// Latest returns the latest release
+func Latest(ctx context.Context, client *http.Client) (*Release, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ReleasesURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("User-Agent", "deecli/"+Version)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach GitHub: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("GitHub returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var release Release
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return nil, fmt.Errorf("invalid release data: %w", err)
+	}
+	if release.TagName == "" {
+		return nil, fmt.Errorf("invalid release data: no tag")
+	}
+	return &release, nil
+}
+
+// Newer reports whether version latest is newer than current. Versions are
+// compared as vMAJOR.MINOR.PATCH with an optional -prerelease, which sorts
+// before the release; a version that does not parse is never newer.
+func Newer(latest, current string) bool {
+	l, lPre, ok := parseVersion(latest)
+	if !ok {
+		return false
+	}
+	c, cPre, ok := parseVersion(current)
+	if !ok {
+		return false
+	}
+	for i := range l {
+		if l[i] != c[i] {
+			return l[i] > c[i]
+		}
+	}
+	switch {
+	case lPre == cPre:
+		return false
+	case lPre == "":
+		return true
+	case cPre == "":
+		return false
+	}
+	return lPre > cPre
+}
+
+// parseVersion splits v1.2.3-rc1 into its numbers and prerelease
+func parseVersion(version string) ([3]int, string, bool) {
+	var numbers [3]int
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	version, _, _ = strings.Cut(version, "+") // Build metadata does not count
+	version, pre, _ := strings.Cut(version, "-")
+	parts := strings.Split(version, ".")
+	if len(parts) == 0 || len(parts) > 3 {
+		return numbers, "", false
+	}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return numbers, "", false
+		}
+		numbers[i] = n
+	}
+	return numbers, pre, true
+}
+
+// checkCache is the result of the last startup check
+type checkCache struct {
+	CheckedAt time.Time `json:"checked_at"`
+	Latest    string    `json:"latest"`
+}
+
+// DefaultCachePath returns where the result of the last check is kept
+func DefaultCachePath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".deecli", "update-check.json")
+}
+
+// Available returns the tag of the latest release if it is newer than
+// current, or "". GitHub is asked at most once per CheckInterval; in
+// between, the answer stored at cachePath is used.
+func Available(ctx context.Context, client *http.Client, current, cachePath string) (string, error) {
+	var cache checkCache
+	if data, err := os.ReadFile(cachePath); err == nil && json.Unmarshal(data, &cache) == nil &&
+		time.Since(cache.CheckedAt) < CheckInterval {
+		return newerTag(cache.Latest, current), nil
+	}
+
+	release, err := Latest(ctx, client)
+	if err != nil {
+		return "", err
+	}
+	RecordCheck(cachePath, release.TagName)
+	return newerTag(release.TagName, current), nil
+}
+
+// RecordCheck stores latest as the result of a check made now, so startup
+// checks skip GitHub for CheckInterval
+func RecordCheck(cachePath, latest string) {
+	if cachePath == "" {
+		return
+	}
+	data, err := json.Marshal(checkCache{CheckedAt: time.Now(), Latest: latest})
+	if err != nil {
+		return
+	}
+	os.MkdirAll(filepath.Dir(cachePath), 0755)
+	os.WriteFile(cachePath, data, 0644)
+}
+
+// newerTag returns latest if it is newer than current, or ""
+func newerTag(latest, current string) string {
+	if Newer(latest, current) {
+		return latest
+	}
+	return ""
+}
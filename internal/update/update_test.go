@@ -0,0 +1,159 @@
+// Copyright 2025 Antenore Gatta
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package update
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestNewer(t *testing.T) {
+	tests := []struct {
+		latest, current string
+		want            bool
+	}{
+		{"v0.2.0", "0.1.0", true},
+		{"v0.1.1", "v0.1.0", true},
+		{"v1.0.0", "v0.9.9", true},
+		{"v0.1.0", "0.1.0", false},
+		{"v0.1.0", "v0.2.0", false},
+		{"v0.2", "v0.1.9", true},
+		{"v0.2.0", "v0.2.0-rc1", true},
+		{"v0.2.0-rc1", "v0.2.0", false},
+		{"v0.2.0-rc2", "v0.2.0-rc1", true},
+		{"nightly", "v0.1.0", false},
+		{"v0.2.0", "dev", false},
+	}
+	for _, tt := range tests {
+		if got := Newer(tt.latest, tt.current); got != tt.want {
+			t.Errorf("Newer(%q, %q) = %v, want %v", tt.latest, tt.current, got, tt.want)
+		}
+	}
+}
+
+func TestParseChecksums(t *testing.T) {
+	sums := ParseChecksums("ABC123  deecli_linux_amd64\ndef456 *deecli_windows_amd64.exe\n\nbroken line here\n")
+	if sums["deecli_linux_amd64"] != "abc123" || sums["deecli_windows_amd64.exe"] != "def456" || len(sums) != 2 {
+		t.Errorf("unexpected checksums: %v", sums)
+	}
+}
+
+// releaseServer serves a release of binary with the given checksum list; a
+// checksums of "" leaves the list out of the release
+func releaseServer(t *testing.T, tag string, binary []byte, checksums string) *httptest.Server {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := AssetName(runtime.GOOS, runtime.GOARCH)
+		switch r.URL.Path {
+		case "/latest":
+			assets := fmt.Sprintf(`{"name":%q,"browser_download_url":"%s/bin"}`, name, server.URL)
+			if checksums != "" {
+				assets += fmt.Sprintf(`,{"name":"checksums.txt","browser_download_url":"%s/sums"}`, server.URL)
+			}
+			fmt.Fprintf(w, `{"tag_name":%q,"body":"- Faster startup","assets":[%s]}`, tag, assets)
+		case "/bin":
+			w.Write(binary)
+		case "/sums":
+			w.Write([]byte(checksums))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	original := ReleasesURL
+	t.Cleanup(func() { ReleasesURL = original })
+	ReleasesURL = server.URL + "/latest"
+	return server
+}
+
+func TestAvailable(t *testing.T) {
+	releaseServer(t, "v0.2.0", nil, "")
+	cache := filepath.Join(t.TempDir(), "update-check.json")
+
+	tag, err := Available(context.Background(), http.DefaultClient, "0.1.0", cache)
+	if err != nil || tag != "v0.2.0" {
+		t.Fatalf("Available() = %q, %v; want v0.2.0", tag, err)
+	}
+
+	// The cached answer is used while it is fresh, even if GitHub is gone
+	ReleasesURL = "http://127.0.0.1:1/unreachable"
+	if tag, err := Available(context.Background(), http.DefaultClient, "0.1.0", cache); err != nil || tag != "v0.2.0" {
+		t.Errorf("cached Available() = %q, %v; want v0.2.0", tag, err)
+	}
+	if tag, _ := Available(context.Background(), http.DefaultClient, "0.2.0", cache); tag != "" {
+		t.Errorf("expected no update for the current version, got %q", tag)
+	}
+}
+
+func TestInstall(t *testing.T) {
+	binary := []byte("#!/bin/sh\necho new\n")
+	sum := sha256.Sum256(binary)
+	name := AssetName(runtime.GOOS, runtime.GOARCH)
+
+	tests := []struct {
+		name      string
+		checksums string
+		wantErr   string
+	}{
+		{"verified", hex.EncodeToString(sum[:]) + "  " + name + "\n", ""},
+		{"mismatch", strings.Repeat("0", 64) + "  " + name + "\n", "checksum mismatch"},
+		{"no checksum for the binary", hex.EncodeToString(sum[:]) + "  other\n", "no checksum"},
+		{"no checksum list", "", "unverified"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			releaseServer(t, "v0.2.0", binary, tt.checksums)
+			exe := filepath.Join(t.TempDir(), "deecli")
+			if err := os.WriteFile(exe, []byte("old"), 0755); err != nil {
+				t.Fatal(err)
+			}
+
+			release, err := Latest(context.Background(), http.DefaultClient)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = Install(context.Background(), http.DefaultClient, release, exe)
+
+			got, _ := os.ReadFile(exe)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Install() error = %v", err)
+				}
+				if string(got) != string(binary) {
+					t.Errorf("executable not replaced: %q", got)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Install() error = %v, want %q", err, tt.wantErr)
+			}
+			if string(got) != "old" {
+				t.Errorf("executable changed after a failed install: %q", got)
+			}
+			entries, _ := os.ReadDir(filepath.Dir(exe))
+			if len(entries) != 1 {
+				t.Errorf("temporary files left behind: %v", entries)
+			}
+		})
+	}
+}
@@ -19,22 +19,14 @@ import (
 	"os/exec"
 	"strconv"
 	"strings"
-	"syscall"
-	"unsafe"
-)
 
-// winsize struct for IOCTL terminal size detection
-type winsize struct {
-	Row    uint16
-	Col    uint16
-	Xpixel uint16
-	Ypixel uint16
-}
+	"golang.org/x/term"
+)
 
 // GetTerminalSize returns terminal dimensions using multiple detection methods
 func GetTerminalSize() (int, int) {
-	// Use this approach for reliable terminal size detection
-	if width, height, err := getTerminalSizeIoctl(); err == nil {
+	// Query the console directly (ioctl on Unix, console API on conhost/Windows Terminal)
+	if width, height, err := getTerminalSizeConsole(); err == nil {
 		return width, height
 	}
 	// Fallback to tput
@@ -49,18 +41,15 @@ func GetTerminalSize() (int, int) {
 	return 80, 24 // Safe defaults
 }
 
-// getTerminalSizeIoctl tries IOCTL system call (most accurate)
-func getTerminalSizeIoctl() (int, int, error) {
-	ws := &winsize{}
-	retCode, _, _ := syscall.Syscall(syscall.SYS_IOCTL,
-		uintptr(syscall.Stdin),
-		uintptr(syscall.TIOCGWINSZ),
-		uintptr(unsafe.Pointer(ws)))
-	if int(retCode) != -1 && ws.Col > 0 && ws.Row > 0 {
-		return int(ws.Col), int(ws.Row), nil
+// getTerminalSizeConsole asks the terminal attached to stdout, then stdin (most accurate)
+func getTerminalSizeConsole() (int, int, error) {
+	for _, f := range []*os.File{os.Stdout, os.Stdin} {
+		if width, height, err := term.GetSize(int(f.Fd())); err == nil && width > 0 && height > 0 {
+			return width, height, nil
+		}
 	}
-	
-	return 0, 0, fmt.Errorf("ioctl failed")
+
+	return 0, 0, fmt.Errorf("no console attached")
 }
 
 // getTerminalSizeTput tries tput command
@@ -21,4 +21,12 @@ import (
 func StripANSI(s string) string {
 	ansiRegex := regexp.MustCompile(`\x1b\[[0-9;]*[a-zA-Z]`)
 	return ansiRegex.ReplaceAllString(s, "")
-}
\ No newline at end of file
+}
+
+// Truncate returns the first n runes of s, never splitting a multi-byte character
+func Truncate(s string, n int) string {
+	if runes := []rune(s); len(runes) > n {
+		return string(runes[:n])
+	}
+	return s
+}